@@ -3,3 +3,11 @@ module github.com/careerup-Inc/careerup-monorepo
 go 1.23.0
 
 toolchain go1.24.3
+
+require google.golang.org/grpc v1.72.1
+
+require (
+	golang.org/x/sys v0.30.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
+	google.golang.org/protobuf v1.36.5 // indirect
+)
@@ -0,0 +1,223 @@
+// Package client is a typed HTTP client for avatar-service.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultMaxRetries   = 2
+	defaultRetryBackoff = 200 * time.Millisecond
+)
+
+// ErrAvatarNotFound is returned when avatar-service has no avatar with the
+// requested ID
+var ErrAvatarNotFound = errors.New("avatar not found")
+
+// errSend marks transport errors, after which the request may or may not
+// have reached avatar-service
+var errSend = errors.New("failed to send request")
+
+// Avatar is an avatar as returned by avatar-service
+type Avatar struct {
+	ID        string            `json:"id"`
+	Style     string            `json:"style"`
+	Features  map[string]string `json:"features"`
+	ImageURL  string            `json:"image_url"`
+	Status    string            `json:"status"` // pending, generating, ready, error
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	// Errors explains a failed generation that VRoid rejected as invalid
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is a problem with one field of a generation request
+type FieldError struct {
+	// Field is "style" or "features.<name>"
+	Field string `json:"field"`
+	// Code is "unsupported_feature" or "incompatible_style"
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// GenerateAvatarRequest asks avatar-service to generate a new avatar
+type GenerateAvatarRequest struct {
+	Style string `json:"style"`
+	// Features are "name" or "name=value" entries; the avatar returned maps
+	// each name to its value
+	Features []string `json:"features"`
+}
+
+// StatusError is returned for responses with an unexpected status code
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("unexpected status code: %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+type AvatarClientInterface interface {
+	GenerateAvatar(ctx context.Context, req *GenerateAvatarRequest) (*Avatar, error)
+	GetAvatar(ctx context.Context, id string) (*Avatar, error)
+}
+
+// Config configures an AvatarClient. Zero values fall back to defaults.
+type Config struct {
+	BaseURL string // e.g. http://avatar-service:8082
+	// Timeout bounds each attempt
+	Timeout time.Duration
+	// MaxRetries is the number of extra attempts after a retryable failure;
+	// negative disables retries
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry; it doubles each time
+	RetryBackoff time.Duration
+}
+
+type AvatarClient struct {
+	baseURL      string
+	maxRetries   int
+	retryBackoff time.Duration
+	httpClient   *http.Client
+}
+
+func NewAvatarClient(cfg Config) *AvatarClient {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	return &AvatarClient{
+		baseURL:      strings.TrimRight(cfg.BaseURL, "/"),
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: cfg.RetryBackoff,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+	}
+}
+
+// GenerateAvatar starts generating an avatar. Generation is not idempotent,
+// so it is only retried when avatar-service says it did not take the
+// request (429 or 503).
+func (c *AvatarClient) GenerateAvatar(ctx context.Context, req *GenerateAvatarRequest) (*Avatar, error) {
+	jsonBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	var avatar Avatar
+	err = c.do(ctx, http.MethodPost, "/v1/avatar/generate", jsonBody, false, &avatar)
+	if err != nil {
+		return nil, err
+	}
+	return &avatar, nil
+}
+
+// GetAvatar retrieves an avatar by ID. It returns ErrAvatarNotFound when the
+// avatar does not exist.
+func (c *AvatarClient) GetAvatar(ctx context.Context, id string) (*Avatar, error) {
+	if id == "" {
+		return nil, fmt.Errorf("avatar ID is required")
+	}
+
+	var avatar Avatar
+	err := c.do(ctx, http.MethodGet, "/v1/avatar/"+url.PathEscape(id), nil, true, &avatar)
+	if err != nil {
+		return nil, err
+	}
+	return &avatar, nil
+}
+
+// do sends a request, retrying with exponential backoff, and decodes a 2xx
+// JSON response into out. Idempotent requests are also retried after
+// transport errors and any 5xx.
+func (c *AvatarClient) do(ctx context.Context, method, path string, body []byte, idempotent bool, out interface{}) error {
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := c.attempt(ctx, method, path, body, out)
+		if err == nil || attempt == c.maxRetries || !retryable(err, idempotent) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *AvatarClient) attempt(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	// Create the HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	// Send the request
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errSend, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrAvatarNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// avatar-service reports errors as {"error": "..."}
+		var errBody struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&errBody)
+		return &StatusError{StatusCode: resp.StatusCode, Message: errBody.Error}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func retryable(err error, idempotent bool) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		}
+		return idempotent && statusErr.StatusCode >= 500
+	}
+	return idempotent && errors.Is(err, errSend)
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *AvatarClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewAvatarClient(Config{BaseURL: srv.URL + "/", RetryBackoff: time.Millisecond})
+}
+
+func TestGenerateAvatar(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/avatar/generate" {
+			t.Errorf("got %s %s, want POST /v1/avatar/generate", r.Method, r.URL.Path)
+		}
+		var req GenerateAvatarRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		if !reflect.DeepEqual(req.Features, []string{"hair=short"}) {
+			t.Errorf("features = %v, want [hair=short]", req.Features)
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "av-1",
+			"style":      req.Style,
+			"features":   map[string]string{"hair": "short"},
+			"image_url":  "https://cdn.example.com/av-1.png",
+			"status":     "generating",
+			"created_at": "2025-05-01T10:00:00Z",
+			"updated_at": "2025-05-01T10:00:00Z",
+		})
+	})
+
+	avatar, err := c.GenerateAvatar(context.Background(), &GenerateAvatarRequest{
+		Style:    "anime",
+		Features: []string{"hair=short"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateAvatar() error = %v", err)
+	}
+	created := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	want := &Avatar{
+		ID:        "av-1",
+		Style:     "anime",
+		Features:  map[string]string{"hair": "short"},
+		ImageURL:  "https://cdn.example.com/av-1.png",
+		Status:    "generating",
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+	if !reflect.DeepEqual(avatar, want) {
+		t.Errorf("GenerateAvatar() = %+v, want %+v", avatar, want)
+	}
+}
+
+func TestGetAvatar(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/avatar/av-1":
+			json.NewEncoder(w).Encode(map[string]string{"id": "av-1", "status": "ready"})
+		case "/v1/avatar/bad-json":
+			w.Write([]byte("{"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "avatar not found"})
+		}
+	})
+
+	avatar, err := c.GetAvatar(context.Background(), "av-1")
+	if err != nil {
+		t.Fatalf("GetAvatar() error = %v", err)
+	}
+	if avatar.ID != "av-1" || avatar.Status != "ready" {
+		t.Errorf("GetAvatar() = %+v", avatar)
+	}
+
+	if _, err := c.GetAvatar(context.Background(), "missing"); !errors.Is(err, ErrAvatarNotFound) {
+		t.Errorf("GetAvatar(missing) error = %v, want ErrAvatarNotFound", err)
+	}
+	if _, err := c.GetAvatar(context.Background(), "bad-json"); err == nil {
+		t.Error("GetAvatar(bad-json) succeeded, want a decode error")
+	}
+	if _, err := c.GetAvatar(context.Background(), ""); err == nil {
+		t.Error("GetAvatar(\"\") succeeded, want an error")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		call         func(*AvatarClient) error
+		wantAttempts int32
+	}{
+		{
+			name:         "get retries server errors",
+			status:       http.StatusInternalServerError,
+			call:         func(c *AvatarClient) error { _, err := c.GetAvatar(context.Background(), "av-1"); return err },
+			wantAttempts: 3,
+		},
+		{
+			name:   "generate is not retried after a server error",
+			status: http.StatusInternalServerError,
+			call: func(c *AvatarClient) error {
+				_, err := c.GenerateAvatar(context.Background(), &GenerateAvatarRequest{Style: "anime"})
+				return err
+			},
+			wantAttempts: 1,
+		},
+		{
+			name:   "generate retries when rate limited",
+			status: http.StatusTooManyRequests,
+			call: func(c *AvatarClient) error {
+				_, err := c.GenerateAvatar(context.Background(), &GenerateAvatarRequest{Style: "anime"})
+				return err
+			},
+			wantAttempts: 3,
+		},
+		{
+			name:         "client errors are not retried",
+			status:       http.StatusBadRequest,
+			call:         func(c *AvatarClient) error { _, err := c.GetAvatar(context.Background(), "av-1"); return err },
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(map[string]string{"error": "nope"})
+			})
+
+			err := tt.call(c)
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status || statusErr.Message != "nope" {
+				t.Errorf("error = %v, want StatusError %d: nope", err, tt.status)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetrySucceedsAfterTransientFailure(t *testing.T) {
+	var attempts int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": "av-1"})
+	})
+
+	if _, err := c.GetAvatar(context.Background(), "av-1"); err != nil {
+		t.Fatalf("GetAvatar() error = %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+	c := NewAvatarClient(Config{BaseURL: srv.URL, Timeout: 20 * time.Millisecond, MaxRetries: -1})
+
+	if _, err := c.GetAvatar(context.Background(), "av-1"); !errors.Is(err, errSend) {
+		t.Errorf("GetAvatar() error = %v, want a send error", err)
+	}
+}
+
+func TestMockAvatarClient(t *testing.T) {
+	var c AvatarClientInterface = NewMockAvatarClient()
+	avatar, err := c.GenerateAvatar(context.Background(), &GenerateAvatarRequest{Style: "anime"})
+	if err != nil {
+		t.Fatalf("GenerateAvatar() error = %v", err)
+	}
+	got, err := c.GetAvatar(context.Background(), avatar.ID)
+	if err != nil || got != avatar {
+		t.Errorf("GetAvatar() = %v, %v; want the generated avatar", got, err)
+	}
+	if _, err := c.GetAvatar(context.Background(), "missing"); !errors.Is(err, ErrAvatarNotFound) {
+		t.Errorf("GetAvatar(missing) error = %v, want ErrAvatarNotFound", err)
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MockAvatarClient keeps avatars in memory, for tests of services that call
+// avatar-service
+type MockAvatarClient struct {
+	mu      sync.Mutex
+	avatars map[string]*Avatar
+	nextID  int
+}
+
+func NewMockAvatarClient() *MockAvatarClient {
+	return &MockAvatarClient{
+		avatars: make(map[string]*Avatar),
+	}
+}
+
+func (c *MockAvatarClient) GenerateAvatar(ctx context.Context, req *GenerateAvatarRequest) (*Avatar, error) {
+	if req.Style == "" {
+		return nil, fmt.Errorf("style is required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	now := time.Now()
+	avatar := &Avatar{
+		ID:        fmt.Sprintf("mock-%d", c.nextID),
+		Style:     req.Style,
+		Features:  mockFeatures(req.Features),
+		ImageURL:  "https://example.com/mock-avatar.png",
+		Status:    "ready",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	c.avatars[avatar.ID] = avatar
+	return avatar, nil
+}
+
+func (c *MockAvatarClient) GetAvatar(ctx context.Context, id string) (*Avatar, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	avatar, exists := c.avatars[id]
+	if !exists {
+		return nil, ErrAvatarNotFound
+	}
+	return avatar, nil
+}
+
+// mockFeatures maps each "name" or "name=value" feature to its value, as
+// avatar-service does
+func mockFeatures(entries []string) map[string]string {
+	features := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name, value, _ := strings.Cut(entry, "=")
+		features[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return features
+}
@@ -0,0 +1,42 @@
+// Package chaterr holds the error codes chat clients receive with "error"
+// messages, shared by chat-gateway, which sets most of them on stream
+// responses, and api-gateway, which forwards them over the WebSocket and
+// adds its own.
+package chaterr
+
+// Code tells chat clients why a request failed, so they can react without
+// parsing the error text.
+type Code string
+
+const (
+	// Unauthorized: the token is missing or invalid; log in again
+	Unauthorized Code = "unauthorized"
+	// RateLimited: too many requests; retry later
+	RateLimited Code = "rate_limited"
+	// InvalidMessage: the client message was malformed
+	InvalidMessage Code = "invalid_message"
+	// NothingToRegenerate: "regenerate" sent before any message
+	NothingToRegenerate Code = "nothing_to_regenerate"
+	// NothingToContinue: "continue" sent when the last answer was not cut off
+	NothingToContinue Code = "nothing_to_continue"
+	// ContinueLimitReached: the answer was continued as often as allowed
+	ContinueLimitReached Code = "continue_limit_reached"
+	// ChatUnavailable: chat-gateway cannot be reached; reconnect
+	ChatUnavailable Code = "chat_unavailable"
+	// ChatError: chat-gateway failed the conversation stream
+	ChatError Code = "chat_error"
+	// LLMUnavailable: llm-gateway could not be reached, so the answer was not
+	// started; retry the message
+	LLMUnavailable Code = "llm_unavailable"
+	// LLMError: the answer stream failed partway through
+	LLMError Code = "llm_error"
+	// LLMBusy: every model is rate limited; retry shortly
+	LLMBusy Code = "llm_busy"
+	// ConversationBusy: another message in the conversation, e.g. sent from
+	// another tab, was still being answered after chat-gateway's
+	// chat.conversation_lock_wait; resend once it is
+	ConversationBusy Code = "conversation_busy"
+	// MessageRateLimited: more messages were sent than chat-gateway's
+	// chat.message_rate allows; resend after retry_after_seconds
+	MessageRateLimited Code = "message_rate_limited"
+)
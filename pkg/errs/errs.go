@@ -0,0 +1,247 @@
+// Package errs is the error taxonomy shared by the services. A domain error
+// carries a Kind that says what went wrong in terms every service agrees
+// on, and converts to the matching gRPC and HTTP status, so a NotFound from
+// chat-gateway reaches a browser as a 404 without each hop keeping its own
+// switch.
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Kind classifies an error.
+type Kind int
+
+const (
+	// Unknown is the kind of errors outside the taxonomy, e.g. bugs or
+	// failures no caller can act on
+	Unknown Kind = iota
+	// NotFound means the thing asked for does not exist
+	NotFound
+	// PermissionDenied means the caller may not do this
+	PermissionDenied
+	// Invalid means the request is malformed and retrying it as is will not help
+	Invalid
+	// Unavailable means a dependency is down or too slow; retrying later may help
+	Unavailable
+	// Exhausted means the caller ran out of quota or hit a rate limit
+	Exhausted
+	// Conflict means the request raced a concurrent change, e.g. an edit
+	// based on a stale version; retrying after reading again may help
+	Conflict
+)
+
+var kindNames = map[Kind]string{
+	Unknown:          "unknown",
+	NotFound:         "not found",
+	PermissionDenied: "permission denied",
+	Invalid:          "invalid",
+	Unavailable:      "unavailable",
+	Exhausted:        "exhausted",
+	Conflict:         "conflict",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("kind(%d)", int(k))
+}
+
+// Error is a domain error of a Kind. It implements GRPCStatus, so returning
+// one from a gRPC handler answers with the kind's code.
+type Error struct {
+	Kind    Kind
+	Message string
+	// Err is the cause, if any
+	Err error
+}
+
+// New returns an error of kind with message.
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Newf returns an error of kind with a formatted message.
+func Newf(kind Kind, format string, args ...any) *Error {
+	return New(kind, fmt.Sprintf(format, args...))
+}
+
+// Wrap returns an error of kind with message whose cause is err.
+func Wrap(kind Kind, err error, message string) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// GRPCStatus returns the status a gRPC server answers e with.
+func (e *Error) GRPCStatus() *status.Status {
+	return status.New(GRPCCode(e.Kind), e.Error())
+}
+
+// KindOf returns the kind of err: that of the first Error in its chain,
+// else the one its gRPC status code maps to. An exceeded context deadline
+// counts as Unavailable. It returns Unknown for nil.
+func KindOf(err error) Kind {
+	if err == nil {
+		return Unknown
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Unavailable
+	}
+	if st, ok := status.FromError(err); ok {
+		return KindFromGRPC(st.Code())
+	}
+	return Unknown
+}
+
+// Is reports whether err is of kind.
+func Is(err error, kind Kind) bool {
+	return err != nil && KindOf(err) == kind
+}
+
+// GRPCCode returns the gRPC code errors of kind are answered with.
+func GRPCCode(kind Kind) codes.Code {
+	switch kind {
+	case NotFound:
+		return codes.NotFound
+	case PermissionDenied:
+		return codes.PermissionDenied
+	case Invalid:
+		return codes.InvalidArgument
+	case Unavailable:
+		return codes.Unavailable
+	case Exhausted:
+		return codes.ResourceExhausted
+	case Conflict:
+		return codes.Aborted
+	default:
+		return codes.Unknown
+	}
+}
+
+// KindFromGRPC returns the kind of an error answered with code. Codes a
+// kind is not answered with map to the nearest kind, e.g. DeadlineExceeded
+// to Unavailable; Unauthenticated and AlreadyExists, which callers tell
+// apart from the taxonomy's kinds, map to Unknown.
+func KindFromGRPC(code codes.Code) Kind {
+	switch code {
+	case codes.NotFound:
+		return NotFound
+	case codes.PermissionDenied:
+		return PermissionDenied
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return Invalid
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return Unavailable
+	case codes.ResourceExhausted:
+		return Exhausted
+	case codes.Aborted:
+		return Conflict
+	default:
+		return Unknown
+	}
+}
+
+// HTTPStatus returns the HTTP status errors of kind are answered with.
+func HTTPStatus(kind Kind) int {
+	switch kind {
+	case NotFound:
+		return http.StatusNotFound
+	case PermissionDenied:
+		return http.StatusForbidden
+	case Invalid:
+		return http.StatusBadRequest
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case Exhausted:
+		return http.StatusTooManyRequests
+	case Conflict:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// KindFromHTTP returns the kind of an error answered with the HTTP status
+// code, mapping statuses a kind is not answered with to the nearest kind
+// as KindFromGRPC does. Statuses below 400 and 401 map to Unknown.
+func KindFromHTTP(code int) Kind {
+	switch code {
+	case http.StatusNotFound, http.StatusGone:
+		return NotFound
+	case http.StatusForbidden:
+		return PermissionDenied
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return Invalid
+	case http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return Unavailable
+	case http.StatusTooManyRequests:
+		return Exhausted
+	case http.StatusConflict:
+		return Conflict
+	default:
+		return Unknown
+	}
+}
+
+// ToGRPC returns err as a gRPC status error with the code of its kind,
+// keeping the code of errors that already carry a status. It returns nil
+// for nil.
+func ToGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return status.Error(GRPCCode(e.Kind), err.Error())
+	}
+	if st, ok := status.FromError(err); ok {
+		return st.Err()
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// FromGRPC returns the gRPC status error err, e.g. from a client call, as
+// an Error of the kind its code maps to, with the status message as its
+// message. Errors of no kind are returned unchanged.
+func FromGRPC(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+	kind := KindFromGRPC(st.Code())
+	if kind == Unknown {
+		return err
+	}
+	return New(kind, st.Message())
+}
+
+// FromHTTP returns an Error for an HTTP response with status code and
+// message, or nil when code does not mark a failure. Failures of no kind
+// are Unknown.
+func FromHTTP(code int, message string) error {
+	if code < http.StatusBadRequest {
+		return nil
+	}
+	return New(KindFromHTTP(code), message)
+}
@@ -0,0 +1,179 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var kinds = []Kind{NotFound, PermissionDenied, Invalid, Unavailable, Exhausted, Conflict}
+
+func TestGRPCRoundTrip(t *testing.T) {
+	for _, kind := range kinds {
+		t.Run(kind.String(), func(t *testing.T) {
+			err := ToGRPC(New(kind, "boom"))
+			if got, want := status.Code(err), GRPCCode(kind); got != want {
+				t.Errorf("ToGRPC() code = %v, want %v", got, want)
+			}
+			back := FromGRPC(err)
+			if got := KindOf(back); got != kind {
+				t.Errorf("FromGRPC() kind = %v, want %v", got, kind)
+			}
+			if back.Error() != "boom" {
+				t.Errorf("FromGRPC() message = %q, want %q", back.Error(), "boom")
+			}
+		})
+	}
+}
+
+func TestHTTPRoundTrip(t *testing.T) {
+	for _, kind := range kinds {
+		t.Run(kind.String(), func(t *testing.T) {
+			code := HTTPStatus(kind)
+			if got := KindOf(FromHTTP(code, "boom")); got != kind {
+				t.Errorf("KindOf(FromHTTP(%d)) = %v, want %v", code, got, kind)
+			}
+		})
+	}
+}
+
+func TestKindFromGRPC(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want Kind
+	}{
+		{codes.NotFound, NotFound},
+		{codes.PermissionDenied, PermissionDenied},
+		{codes.InvalidArgument, Invalid},
+		{codes.FailedPrecondition, Invalid},
+		{codes.OutOfRange, Invalid},
+		{codes.Unavailable, Unavailable},
+		{codes.DeadlineExceeded, Unavailable},
+		{codes.ResourceExhausted, Exhausted},
+		{codes.Aborted, Conflict},
+		{codes.Unauthenticated, Unknown},
+		{codes.AlreadyExists, Unknown},
+		{codes.Internal, Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			if got := KindFromGRPC(tt.code); got != tt.want {
+				t.Errorf("KindFromGRPC(%v) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKindFromHTTP(t *testing.T) {
+	tests := []struct {
+		code int
+		want Kind
+	}{
+		{http.StatusNotFound, NotFound},
+		{http.StatusGone, NotFound},
+		{http.StatusForbidden, PermissionDenied},
+		{http.StatusBadRequest, Invalid},
+		{http.StatusUnprocessableEntity, Invalid},
+		{http.StatusServiceUnavailable, Unavailable},
+		{http.StatusBadGateway, Unavailable},
+		{http.StatusGatewayTimeout, Unavailable},
+		{http.StatusTooManyRequests, Exhausted},
+		{http.StatusUnauthorized, Unknown},
+		{http.StatusConflict, Conflict},
+		{http.StatusInternalServerError, Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.code), func(t *testing.T) {
+			if got := KindFromHTTP(tt.code); got != tt.want {
+				t.Errorf("KindFromHTTP(%d) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPStatus_Unknown(t *testing.T) {
+	if got := HTTPStatus(Unknown); got != http.StatusInternalServerError {
+		t.Errorf("HTTPStatus(Unknown) = %d, want 500", got)
+	}
+	if got := GRPCCode(Unknown); got != codes.Unknown {
+		t.Errorf("GRPCCode(Unknown) = %v, want Unknown", got)
+	}
+}
+
+func TestFromHTTP_Success(t *testing.T) {
+	if err := FromHTTP(http.StatusOK, ""); err != nil {
+		t.Errorf("FromHTTP(200) = %v, want nil", err)
+	}
+}
+
+func TestError_ServedOverGRPC(t *testing.T) {
+	// gRPC servers answer with the status of errors that implement GRPCStatus
+	err := fmt.Errorf("loading conversation: %w", New(NotFound, "conversation not found"))
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("status.Code() = %v, want NotFound", got)
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	cause := errors.New("connection refused")
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{"nil", nil, Unknown},
+		{"plain error", cause, Unknown},
+		{"domain error", New(Exhausted, "quota used up"), Exhausted},
+		{"wrapped domain error", fmt.Errorf("calling ilo: %w", Wrap(Unavailable, cause, "ilo down")), Unavailable},
+		{"status error", status.Error(codes.PermissionDenied, "not yours"), PermissionDenied},
+		{"deadline", context.DeadlineExceeded, Unavailable},
+		{"canceled", context.Canceled, Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.want {
+				t.Errorf("KindOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := Wrap(Unavailable, cause, "ilo down")
+	if !errors.Is(err, cause) {
+		t.Error("Wrap() does not unwrap to its cause")
+	}
+	if got, want := err.Error(), "ilo down: connection refused"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestToGRPC_KeepsStatus(t *testing.T) {
+	err := ToGRPC(status.Error(codes.Unauthenticated, "who are you"))
+	if got := status.Code(err); got != codes.Unauthenticated {
+		t.Errorf("ToGRPC() code = %v, want Unauthenticated", got)
+	}
+	if got := status.Code(ToGRPC(errors.New("boom"))); got != codes.Unknown {
+		t.Errorf("ToGRPC(plain) code = %v, want Unknown", got)
+	}
+	if ToGRPC(nil) != nil {
+		t.Error("ToGRPC(nil) != nil")
+	}
+}
+
+func TestFromGRPC_KeepsOtherErrors(t *testing.T) {
+	unauthenticated := status.Error(codes.Unauthenticated, "who are you")
+	if got := FromGRPC(unauthenticated); got != unauthenticated {
+		t.Errorf("FromGRPC() = %v, want the status error unchanged", got)
+	}
+	plain := errors.New("boom")
+	if got := FromGRPC(plain); got != plain {
+		t.Errorf("FromGRPC() = %v, want the error unchanged", got)
+	}
+}
@@ -0,0 +1,100 @@
+// Package grpcclient dials the gRPC backends of a service. Backends are
+// found through a resolver and calls spread over their replicas by a load
+// balancing policy, and a backend that is down is retried in the background
+// until it comes up.
+package grpcclient
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// DefaultResolverScheme resolves backend addresses through DNS, so a
+	// headless service name yields every replica
+	DefaultResolverScheme = "dns"
+	// DefaultLoadBalancingPolicy spreads calls over the resolved replicas
+	DefaultLoadBalancingPolicy = "round_robin"
+	// DefaultMaxRecvMsgSize is the largest response a client accepts, in
+	// bytes. It matches the servers' default message size limit; gRPC's own
+	// client default is 4 MB
+	DefaultMaxRecvMsgSize = 16 << 20
+
+	// maxReconnectDelay caps the backoff between attempts to reach a backend
+	// that is down, so one that comes up late is picked up within seconds
+	// rather than gRPC's default two minutes
+	maxReconnectDelay = 5 * time.Second
+)
+
+// Balancing is how a client finds and picks the replicas of a backend.
+// Zero fields mean DefaultResolverScheme and DefaultLoadBalancingPolicy.
+type Balancing struct {
+	// Scheme names the gRPC resolver, e.g. "dns" or "passthrough"
+	Scheme string
+	// Policy names the load balancing policy, e.g. "round_robin" or
+	// "pick_first"
+	Policy string
+}
+
+// Target returns addr under b's resolver scheme. An addr that already names
+// a scheme ("dns:///host:port") is kept as is.
+func (b Balancing) Target(addr string) string {
+	if strings.Contains(addr, "://") {
+		return addr
+	}
+	scheme := b.Scheme
+	if scheme == "" {
+		scheme = DefaultResolverScheme
+	}
+	return scheme + ":///" + addr
+}
+
+// ServiceConfig returns the default service config selecting b's policy.
+func (b Balancing) ServiceConfig() string {
+	policy := b.Policy
+	if policy == "" {
+		policy = DefaultLoadBalancingPolicy
+	}
+	return fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]}`, policy)
+}
+
+// DialOptions returns the options applying b's policy to a connection.
+func (b Balancing) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{grpc.WithDefaultServiceConfig(b.ServiceConfig())}
+}
+
+// MaxRecvMsgSize returns the option letting a connection's calls receive
+// responses of up to n bytes; n <= 0 means DefaultMaxRecvMsgSize.
+func MaxRecvMsgSize(n int) grpc.DialOption {
+	if n <= 0 {
+		n = DefaultMaxRecvMsgSize
+	}
+	return grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(n))
+}
+
+// Dial opens an insecure connection to the replicas of the backend at addr;
+// opts are added to the connection's options. Responses of up to
+// DefaultMaxRecvMsgSize are accepted unless opts set another MaxRecvMsgSize. It does not wait for the
+// backend: the connection is made in the background and retried while the
+// backend is down, so Dial only fails for an addr that is not a valid
+// target. The connection's state tells whether the backend has been reached.
+func (b Balancing) Dial(addr string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	connectParams := grpc.ConnectParams{Backoff: backoff.DefaultConfig, MinConnectTimeout: 20 * time.Second}
+	connectParams.Backoff.MaxDelay = maxReconnectDelay
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(connectParams),
+		MaxRecvMsgSize(DefaultMaxRecvMsgSize),
+	}, b.DialOptions()...)
+	conn, err := grpc.NewClient(b.Target(addr), append(dialOpts, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	conn.Connect()
+	return conn, nil
+}
@@ -0,0 +1,165 @@
+package grpcclient
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestBalancing_Target(t *testing.T) {
+	for _, tt := range []struct {
+		balancing Balancing
+		addr      string
+		want      string
+	}{
+		{Balancing{}, "chat-gateway:8082", "dns:///chat-gateway:8082"},
+		{Balancing{Scheme: "passthrough"}, "chat-gateway:8082", "passthrough:///chat-gateway:8082"},
+		// Explicit schemes are kept
+		{Balancing{}, "dns://10.0.0.2/chat-gateway:8082", "dns://10.0.0.2/chat-gateway:8082"},
+	} {
+		if got := tt.balancing.Target(tt.addr); got != tt.want {
+			t.Errorf("%+v.Target(%q) = %q, want %q", tt.balancing, tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestBalancing_ServiceConfig(t *testing.T) {
+	if got, want := (Balancing{}).ServiceConfig(), `{"loadBalancingConfig":[{"round_robin":{}}]}`; got != want {
+		t.Errorf("default ServiceConfig() = %s, want %s", got, want)
+	}
+	if got, want := (Balancing{Policy: "pick_first"}).ServiceConfig(), `{"loadBalancingConfig":[{"pick_first":{}}]}`; got != want {
+		t.Errorf("pick_first ServiceConfig() = %s, want %s", got, want)
+	}
+}
+
+// replica serves the health service on a local port, counting the calls it
+// receives.
+func replica(t *testing.T) (string, *atomic.Int32) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls atomic.Int32
+	srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		calls.Add(1)
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String(), &calls
+}
+
+func TestBalancing_Dial(t *testing.T) {
+	for _, tt := range []struct {
+		policy   string
+		replicas int
+	}{
+		{policy: "", replicas: 2},
+		{policy: "pick_first", replicas: 1},
+	} {
+		t.Run("policy "+tt.policy, func(t *testing.T) {
+			addr1, calls1 := replica(t)
+			addr2, calls2 := replica(t)
+			// Stands in for DNS returning both replicas of the service
+			r := manual.NewBuilderWithScheme("test")
+			r.InitialState(resolver.State{Addresses: []resolver.Address{{Addr: addr1}, {Addr: addr2}}})
+
+			conn, err := Balancing{Scheme: "test", Policy: tt.policy}.Dial("backend:9090", grpc.WithResolvers(r))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			if got, want := conn.Target(), "test:///backend:9090"; got != want {
+				t.Errorf("Target() = %q, want %q", got, want)
+			}
+
+			// round_robin only picks replicas it has connected to, so the
+			// first calls may all go to one of them
+			client := healthpb.NewHealthClient(conn)
+			for i := 0; i < 200 && (calls1.Load() == 0 || calls2.Load() == 0); i++ {
+				if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			served := 0
+			for _, calls := range []*atomic.Int32{calls1, calls2} {
+				if calls.Load() > 0 {
+					served++
+				}
+			}
+			if served != tt.replicas {
+				t.Errorf("%d replicas served calls, want %d", served, tt.replicas)
+			}
+		})
+	}
+}
+
+func TestBalancing_DialDoesNotWaitForBackend(t *testing.T) {
+	var attempts atomic.Int32
+	down := grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		attempts.Add(1)
+		return nil, &net.OpError{Op: "dial", Err: net.ErrClosed}
+	})
+
+	conn, err := Balancing{Scheme: "passthrough"}.Dial("chat-gateway:8082", down)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The connection is attempted without any call being made on it
+	deadline := time.Now().Add(5 * time.Second)
+	for attempts.Load() == 0 || conn.GetState() != connectivity.TransientFailure {
+		if time.Now().After(deadline) {
+			t.Fatalf("no connection attempt; state %v after %d attempts", conn.GetState(), attempts.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBalancing_DialAcceptsLargeResponses(t *testing.T) {
+	// Over gRPC's 4 MB client default, within DefaultMaxRecvMsgSize
+	const size = 6 << 20
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+		if err := stream.RecvMsg(&emptypb.Empty{}); err != nil {
+			return err
+		}
+		return stream.SendMsg(&wrapperspb.BytesValue{Value: make([]byte, size)})
+	}))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	call := func(opts ...grpc.DialOption) error {
+		conn, err := Balancing{Scheme: "passthrough"}.Dial(lis.Addr().String(), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		return conn.Invoke(context.Background(), "/test.Large/Get", &emptypb.Empty{}, &wrapperspb.BytesValue{})
+	}
+	if err := call(); err != nil {
+		t.Errorf("default limit: %v", err)
+	}
+	if err := call(MaxRecvMsgSize(1 << 20)); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("1 MB limit: got %v, want ResourceExhausted", err)
+	}
+}
@@ -0,0 +1,173 @@
+// Package httpclient is the HTTP client for outbound calls to third-party
+// APIs. It bounds each attempt with a timeout, pools connections per host,
+// retries idempotent requests after transient failures, and reports every
+// attempt to an optional observer.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultTimeout             = 30 * time.Second
+	defaultMaxRetries          = 2
+	defaultRetryBackoff        = 200 * time.Millisecond
+	defaultMaxIdleConnsPerHost = 10
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// Attempt describes one try at sending a request
+type Attempt struct {
+	Method string
+	URL    string
+	// Number is 1 for the first try
+	Number int
+	// StatusCode is 0 when no response was received
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+	// Retrying is set when another attempt follows
+	Retrying bool
+}
+
+// Config configures a Client. Zero values fall back to defaults.
+type Config struct {
+	// Timeout bounds each attempt, including reading the response body
+	Timeout time.Duration
+	// MaxRetries is the number of extra attempts for idempotent requests;
+	// negative disables retries
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry; it doubles each time
+	RetryBackoff time.Duration
+	// MaxIdleConnsPerHost is how many idle connections are kept per host
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept
+	IdleConnTimeout time.Duration
+	// Observe, if set, is called after every attempt
+	Observe func(Attempt)
+}
+
+// Client sends requests with the retry policy of its Config. It is safe for
+// concurrent use.
+type Client struct {
+	httpClient   *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+	observe      func(Attempt)
+}
+
+// New returns a Client for cfg.
+func New(cfg Config) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+	if cfg.MaxIdleConnsPerHost <= 0 {
+		cfg.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeout <= 0 {
+		cfg.IdleConnTimeout = defaultIdleConnTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = cfg.IdleConnTimeout
+	return &Client{
+		httpClient: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+		},
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: cfg.RetryBackoff,
+		observe:      cfg.Observe,
+	}
+}
+
+// Do sends req. Idempotent requests (GET, HEAD, OPTIONS, PUT, DELETE) are
+// retried with exponential backoff after transport errors, 429 and 5xx
+// responses, as long as their body can be replayed; other requests are sent
+// once. The last response or error is returned as http.Client.Do would.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	retries := 0
+	if idempotent(req) {
+		retries = c.maxRetries
+	}
+
+	backoff := c.retryBackoff
+	for n := 1; ; n++ {
+		if n > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		start := time.Now()
+		resp, err := c.httpClient.Do(req)
+		retrying := n <= retries && retryable(req.Context(), resp, err)
+		c.report(req, n, resp, err, time.Since(start), retrying)
+		if !retrying {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) report(req *http.Request, n int, resp *http.Response, err error, d time.Duration, retrying bool) {
+	if c.observe == nil {
+		return
+	}
+	attempt := Attempt{
+		Method:   req.Method,
+		URL:      req.URL.Redacted(),
+		Number:   n,
+		Duration: d,
+		Err:      err,
+		Retrying: retrying,
+	}
+	if resp != nil {
+		attempt.StatusCode = resp.StatusCode
+	}
+	c.observe(attempt)
+}
+
+// idempotent reports whether req may be sent more than once.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// The caller gave up; another attempt would fail the same way
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
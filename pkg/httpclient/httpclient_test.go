@@ -0,0 +1,181 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// unavailableFor serves 503 to the first n requests and 200 "ok" after,
+// echoing request bodies into bodies
+func unavailableFor(t *testing.T, n int32, attempts *int32, bodies *[]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*bodies = append(*bodies, string(body))
+		if atomic.AddInt32(attempts, 1) <= n {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(observe func(Attempt)) *Client {
+	return New(Config{MaxRetries: 2, RetryBackoff: time.Millisecond, Observe: observe})
+}
+
+func TestGetIsRetriedOn503(t *testing.T) {
+	var attempts int32
+	var bodies []string
+	srv := unavailableFor(t, 2, &attempts, &bodies)
+	var observed []Attempt
+	c := newTestClient(func(a Attempt) { observed = append(observed, a) })
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/search?q=careers", nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Errorf("response = %d %q, want 200 ok", resp.StatusCode, body)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+
+	if len(observed) != 3 {
+		t.Fatalf("observed %d attempts, want 3", len(observed))
+	}
+	for i, a := range observed {
+		wantStatus, wantRetrying := http.StatusServiceUnavailable, true
+		if i == 2 {
+			wantStatus, wantRetrying = http.StatusOK, false
+		}
+		if a.Number != i+1 || a.Method != http.MethodGet || a.StatusCode != wantStatus || a.Retrying != wantRetrying {
+			t.Errorf("attempt %d = %+v", i+1, a)
+		}
+	}
+}
+
+func TestPutBodyIsReplayedOnRetry(t *testing.T) {
+	var attempts int32
+	var bodies []string
+	srv := unavailableFor(t, 1, &attempts, &bodies)
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader(`{"style":"anime"}`))
+	resp, err := newTestClient(nil).Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if want := []string{`{"style":"anime"}`, `{"style":"anime"}`}; strings.Join(bodies, "|") != strings.Join(want, "|") {
+		t.Errorf("bodies = %q, want %q", bodies, want)
+	}
+}
+
+func TestPostIsNotRetried(t *testing.T) {
+	var attempts int32
+	var bodies []string
+	srv := unavailableFor(t, 1, &attempts, &bodies)
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"style":"anime"}`))
+	resp, err := newTestClient(nil).Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestRetriesAreBounded(t *testing.T) {
+	var attempts int32
+	var bodies []string
+	srv := unavailableFor(t, 10, &attempts, &bodies)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := newTestClient(nil).Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || attempts != 3 {
+		t.Errorf("status = %d after %d attempts, want 503 after 3", resp.StatusCode, attempts)
+	}
+}
+
+func TestClientErrorsAreNotRetried(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := newTestClient(nil).Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestTimeoutIsRetried(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(srv.Close)
+	c := New(Config{Timeout: 50 * time.Millisecond, MaxRetries: 1, RetryBackoff: time.Millisecond})
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestCancelledRequestIsNotRetried(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := New(Config{MaxRetries: 5, RetryBackoff: time.Hour, Observe: func(Attempt) { cancel() }})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if _, err := c.Do(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("Do() error = %v, want context.Canceled", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
@@ -0,0 +1,98 @@
+// Package ilo holds the ILO (Interest, Learning, Orientation) score
+// formatting shared by the gateways, so prompts and context strings render
+// scores the same way everywhere.
+package ilo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DomainScore is a scored ILO domain, independent of the proto and client
+// types each service uses.
+type DomainScore struct {
+	DomainCode string
+	Percent    float32
+	Level      string
+	Rank       int32
+}
+
+// FormatScore renders a score as "R: 72.5% (High)". The level is omitted
+// when unknown.
+func FormatScore(s DomainScore) string {
+	if s.Level == "" {
+		return fmt.Sprintf("%s: %.1f%%", s.DomainCode, s.Percent)
+	}
+	return fmt.Sprintf("%s: %.1f%% (%s)", s.DomainCode, s.Percent, s.Level)
+}
+
+// FormatScoreLines renders one "- R: 72.5% (High)" bullet per score, in the
+// order given.
+func FormatScoreLines(scores []DomainScore) []string {
+	lines := make([]string, 0, len(scores))
+	for _, s := range scores {
+		lines = append(lines, "- "+FormatScore(s))
+	}
+	return lines
+}
+
+// TopDomains returns the codes of the n highest-scoring domains. Ties are
+// broken by the service-assigned rank (lower first, 0 meaning unranked), then
+// by domain code, so the result is deterministic.
+func TopDomains(scores []DomainScore, n int) []string {
+	if n <= 0 || len(scores) == 0 {
+		return nil
+	}
+
+	sorted := make([]DomainScore, len(scores))
+	copy(sorted, scores)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Percent != b.Percent {
+			return a.Percent > b.Percent
+		}
+		if a.Rank != b.Rank {
+			if a.Rank == 0 || b.Rank == 0 {
+				return b.Rank == 0
+			}
+			return a.Rank < b.Rank
+		}
+		return a.DomainCode < b.DomainCode
+	})
+
+	if n > len(sorted) {
+		n = len(sorted)
+	}
+	codes := make([]string, n)
+	for i := 0; i < n; i++ {
+		codes[i] = sorted[i].DomainCode
+	}
+	return codes
+}
+
+// Profile renders a compact one-line summary of an ILO result for use as
+// chat context, e.g. "User ILO profile: Top domains: R, I. Domain scores:
+// R:73%, I:60%. ". Empty sections are skipped; an empty result yields "".
+func Profile(topDomains, suggestedCareers []string, scores []DomainScore) string {
+	if len(topDomains) == 0 && len(suggestedCareers) == 0 && len(scores) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("User ILO profile: ")
+	if len(topDomains) > 0 {
+		sb.WriteString("Top domains: " + strings.Join(topDomains, ", ") + ". ")
+	}
+	if len(suggestedCareers) > 0 {
+		sb.WriteString("Suggested careers: " + strings.Join(suggestedCareers, ", ") + ". ")
+	}
+	if len(scores) > 0 {
+		parts := make([]string, 0, len(scores))
+		for _, s := range scores {
+			parts = append(parts, fmt.Sprintf("%s:%.0f%%", s.DomainCode, s.Percent))
+		}
+		sb.WriteString("Domain scores: " + strings.Join(parts, ", ") + ". ")
+	}
+	return sb.String()
+}
@@ -0,0 +1,118 @@
+package ilo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		score DomainScore
+		want  string
+	}{
+		{"with level", DomainScore{DomainCode: "R", Percent: 72.5, Level: "High"}, "R: 72.5% (High)"},
+		{"without level", DomainScore{DomainCode: "I", Percent: 40}, "I: 40.0%"},
+		{"rounds to one decimal", DomainScore{DomainCode: "A", Percent: 33.333, Level: "Low"}, "A: 33.3% (Low)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatScore(tt.score); got != tt.want {
+				t.Errorf("FormatScore() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatScoreLines(t *testing.T) {
+	got := FormatScoreLines([]DomainScore{
+		{DomainCode: "R", Percent: 80, Level: "High"},
+		{DomainCode: "S", Percent: 20, Level: "Low"},
+	})
+	want := []string{"- R: 80.0% (High)", "- S: 20.0% (Low)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FormatScoreLines() = %v, want %v", got, want)
+	}
+}
+
+func TestTopDomains(t *testing.T) {
+	scores := []DomainScore{
+		{DomainCode: "R", Percent: 50},
+		{DomainCode: "I", Percent: 90},
+		{DomainCode: "A", Percent: 70},
+		{DomainCode: "S", Percent: 70},
+		{DomainCode: "E", Percent: 10},
+	}
+
+	tests := []struct {
+		name   string
+		scores []DomainScore
+		n      int
+		want   []string
+	}{
+		{"top one", scores, 1, []string{"I"}},
+		{"ties broken by code", scores, 3, []string{"I", "A", "S"}},
+		{"n larger than scores", scores[:2], 5, []string{"I", "R"}},
+		{"zero n", scores, 0, nil},
+		{"no scores", nil, 3, nil},
+		{
+			"ties broken by rank before code",
+			[]DomainScore{
+				{DomainCode: "A", Percent: 60, Rank: 2},
+				{DomainCode: "B", Percent: 60, Rank: 1},
+				{DomainCode: "C", Percent: 60},
+			},
+			3,
+			[]string{"B", "A", "C"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TopDomains(tt.scores, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopDomains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopDomains_DoesNotReorderInput(t *testing.T) {
+	scores := []DomainScore{{DomainCode: "R", Percent: 10}, {DomainCode: "I", Percent: 90}}
+	TopDomains(scores, 1)
+	if scores[0].DomainCode != "R" {
+		t.Errorf("TopDomains reordered its input: %v", scores)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		top     []string
+		careers []string
+		scores  []DomainScore
+		want    string
+	}{
+		{
+			name:    "all sections",
+			top:     []string{"R", "I"},
+			careers: []string{"Engineer"},
+			scores:  []DomainScore{{DomainCode: "R", Percent: 72.6}, {DomainCode: "I", Percent: 60}},
+			want:    "User ILO profile: Top domains: R, I. Suggested careers: Engineer. Domain scores: R:73%, I:60%. ",
+		},
+		{
+			name:   "scores only",
+			scores: []DomainScore{{DomainCode: "S", Percent: 45}},
+			want:   "User ILO profile: Domain scores: S:45%. ",
+		},
+		{
+			name: "empty result",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Profile(tt.top, tt.careers, tt.scores); got != tt.want {
+				t.Errorf("Profile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -19,7 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	ConversationService_Stream_FullMethodName = "/careerup.v1.ConversationService/Stream"
+	ConversationService_Stream_FullMethodName                  = "/careerup.v1.ConversationService/Stream"
+	ConversationService_GetConversation_FullMethodName         = "/careerup.v1.ConversationService/GetConversation"
+	ConversationService_GetConversationSummary_FullMethodName  = "/careerup.v1.ConversationService/GetConversationSummary"
+	ConversationService_ListConversations_FullMethodName       = "/careerup.v1.ConversationService/ListConversations"
+	ConversationService_SetConversationArchived_FullMethodName = "/careerup.v1.ConversationService/SetConversationArchived"
+	ConversationService_ResetConversation_FullMethodName       = "/careerup.v1.ConversationService/ResetConversation"
 )
 
 // ConversationServiceClient is the client API for ConversationService service.
@@ -28,6 +33,20 @@ const (
 type ConversationServiceClient interface {
 	// Stream establishes a bidirectional stream for chat messages.
 	Stream(ctx context.Context, opts ...grpc.CallOption) (ConversationService_StreamClient, error)
+	// GetConversation returns the history of one of the caller's conversations.
+	GetConversation(ctx context.Context, in *GetConversationRequest, opts ...grpc.CallOption) (*GetConversationResponse, error)
+	// GetConversationSummary returns the running summary of one of the
+	// caller's conversations, for prompts that can't fit the whole history.
+	GetConversationSummary(ctx context.Context, in *GetConversationSummaryRequest, opts ...grpc.CallOption) (*GetConversationSummaryResponse, error)
+	// ListConversations lists the caller's conversations, leaving out archived
+	// ones unless asked for.
+	ListConversations(ctx context.Context, in *ListConversationsRequest, opts ...grpc.CallOption) (*ListConversationsResponse, error)
+	// SetConversationArchived archives or unarchives one of the caller's
+	// conversations. Archiving only hides it from ListConversations.
+	SetConversationArchived(ctx context.Context, in *SetConversationArchivedRequest, opts ...grpc.CallOption) (*SetConversationArchivedResponse, error)
+	// ResetConversation starts one of the caller's conversations afresh
+	// without giving up its ID and title.
+	ResetConversation(ctx context.Context, in *ResetConversationRequest, opts ...grpc.CallOption) (*ResetConversationResponse, error)
 }
 
 type conversationServiceClient struct {
@@ -69,12 +88,71 @@ func (x *conversationServiceStreamClient) Recv() (*StreamResponse, error) {
 	return m, nil
 }
 
+func (c *conversationServiceClient) GetConversation(ctx context.Context, in *GetConversationRequest, opts ...grpc.CallOption) (*GetConversationResponse, error) {
+	out := new(GetConversationResponse)
+	err := c.cc.Invoke(ctx, ConversationService_GetConversation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *conversationServiceClient) GetConversationSummary(ctx context.Context, in *GetConversationSummaryRequest, opts ...grpc.CallOption) (*GetConversationSummaryResponse, error) {
+	out := new(GetConversationSummaryResponse)
+	err := c.cc.Invoke(ctx, ConversationService_GetConversationSummary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *conversationServiceClient) ListConversations(ctx context.Context, in *ListConversationsRequest, opts ...grpc.CallOption) (*ListConversationsResponse, error) {
+	out := new(ListConversationsResponse)
+	err := c.cc.Invoke(ctx, ConversationService_ListConversations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *conversationServiceClient) SetConversationArchived(ctx context.Context, in *SetConversationArchivedRequest, opts ...grpc.CallOption) (*SetConversationArchivedResponse, error) {
+	out := new(SetConversationArchivedResponse)
+	err := c.cc.Invoke(ctx, ConversationService_SetConversationArchived_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *conversationServiceClient) ResetConversation(ctx context.Context, in *ResetConversationRequest, opts ...grpc.CallOption) (*ResetConversationResponse, error) {
+	out := new(ResetConversationResponse)
+	err := c.cc.Invoke(ctx, ConversationService_ResetConversation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ConversationServiceServer is the server API for ConversationService service.
 // All implementations must embed UnimplementedConversationServiceServer
 // for forward compatibility
 type ConversationServiceServer interface {
 	// Stream establishes a bidirectional stream for chat messages.
 	Stream(ConversationService_StreamServer) error
+	// GetConversation returns the history of one of the caller's conversations.
+	GetConversation(context.Context, *GetConversationRequest) (*GetConversationResponse, error)
+	// GetConversationSummary returns the running summary of one of the
+	// caller's conversations, for prompts that can't fit the whole history.
+	GetConversationSummary(context.Context, *GetConversationSummaryRequest) (*GetConversationSummaryResponse, error)
+	// ListConversations lists the caller's conversations, leaving out archived
+	// ones unless asked for.
+	ListConversations(context.Context, *ListConversationsRequest) (*ListConversationsResponse, error)
+	// SetConversationArchived archives or unarchives one of the caller's
+	// conversations. Archiving only hides it from ListConversations.
+	SetConversationArchived(context.Context, *SetConversationArchivedRequest) (*SetConversationArchivedResponse, error)
+	// ResetConversation starts one of the caller's conversations afresh
+	// without giving up its ID and title.
+	ResetConversation(context.Context, *ResetConversationRequest) (*ResetConversationResponse, error)
 	mustEmbedUnimplementedConversationServiceServer()
 }
 
@@ -85,6 +163,21 @@ type UnimplementedConversationServiceServer struct {
 func (UnimplementedConversationServiceServer) Stream(ConversationService_StreamServer) error {
 	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
 }
+func (UnimplementedConversationServiceServer) GetConversation(context.Context, *GetConversationRequest) (*GetConversationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConversation not implemented")
+}
+func (UnimplementedConversationServiceServer) GetConversationSummary(context.Context, *GetConversationSummaryRequest) (*GetConversationSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConversationSummary not implemented")
+}
+func (UnimplementedConversationServiceServer) ListConversations(context.Context, *ListConversationsRequest) (*ListConversationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConversations not implemented")
+}
+func (UnimplementedConversationServiceServer) SetConversationArchived(context.Context, *SetConversationArchivedRequest) (*SetConversationArchivedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetConversationArchived not implemented")
+}
+func (UnimplementedConversationServiceServer) ResetConversation(context.Context, *ResetConversationRequest) (*ResetConversationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetConversation not implemented")
+}
 func (UnimplementedConversationServiceServer) mustEmbedUnimplementedConversationServiceServer() {}
 
 // UnsafeConversationServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -124,13 +217,124 @@ func (x *conversationServiceStreamServer) Recv() (*StreamRequest, error) {
 	return m, nil
 }
 
+func _ConversationService_GetConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConversationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConversationServiceServer).GetConversation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConversationService_GetConversation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConversationServiceServer).GetConversation(ctx, req.(*GetConversationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConversationService_GetConversationSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConversationSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConversationServiceServer).GetConversationSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConversationService_GetConversationSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConversationServiceServer).GetConversationSummary(ctx, req.(*GetConversationSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConversationService_ListConversations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConversationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConversationServiceServer).ListConversations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConversationService_ListConversations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConversationServiceServer).ListConversations(ctx, req.(*ListConversationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConversationService_SetConversationArchived_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetConversationArchivedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConversationServiceServer).SetConversationArchived(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConversationService_SetConversationArchived_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConversationServiceServer).SetConversationArchived(ctx, req.(*SetConversationArchivedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ConversationService_ResetConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetConversationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConversationServiceServer).ResetConversation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ConversationService_ResetConversation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConversationServiceServer).ResetConversation(ctx, req.(*ResetConversationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ConversationService_ServiceDesc is the grpc.ServiceDesc for ConversationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var ConversationService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "careerup.v1.ConversationService",
 	HandlerType: (*ConversationServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetConversation",
+			Handler:    _ConversationService_GetConversation_Handler,
+		},
+		{
+			MethodName: "GetConversationSummary",
+			Handler:    _ConversationService_GetConversationSummary_Handler,
+		},
+		{
+			MethodName: "ListConversations",
+			Handler:    _ConversationService_ListConversations_Handler,
+		},
+		{
+			MethodName: "SetConversationArchived",
+			Handler:    _ConversationService_SetConversationArchived_Handler,
+		},
+		{
+			MethodName: "ResetConversation",
+			Handler:    _ConversationService_ResetConversation_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Stream",
@@ -162,8 +162,11 @@ type IloCareerSuggestion struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DomainCode  string `protobuf:"bytes,1,opt,name=domain_code,json=domainCode,proto3" json:"domain_code,omitempty"`    // Associated domain code
-	CareerField string `protobuf:"bytes,2,opt,name=career_field,json=careerField,proto3" json:"career_field,omitempty"` // Career field name
+	DomainCode      string   `protobuf:"bytes,1,opt,name=domain_code,json=domainCode,proto3" json:"domain_code,omitempty"`                // Associated domain code
+	CareerField     string   `protobuf:"bytes,2,opt,name=career_field,json=careerField,proto3" json:"career_field,omitempty"`             // Career field name
+	MatchPercent    float32  `protobuf:"fixed32,3,opt,name=match_percent,json=matchPercent,proto3" json:"match_percent,omitempty"`        // How well the field matches the scores (0-100)
+	Rationale       string   `protobuf:"bytes,4,opt,name=rationale,proto3" json:"rationale,omitempty"`                                    // Why the field is suggested
+	RequiredDomains []string `protobuf:"bytes,5,rep,name=required_domains,json=requiredDomains,proto3" json:"required_domains,omitempty"` // Domain codes the field draws on
 }
 
 func (x *IloCareerSuggestion) Reset() {
@@ -212,6 +215,27 @@ func (x *IloCareerSuggestion) GetCareerField() string {
 	return ""
 }
 
+func (x *IloCareerSuggestion) GetMatchPercent() float32 {
+	if x != nil {
+		return x.MatchPercent
+	}
+	return 0
+}
+
+func (x *IloCareerSuggestion) GetRationale() string {
+	if x != nil {
+		return x.Rationale
+	}
+	return ""
+}
+
+func (x *IloCareerSuggestion) GetRequiredDomains() []string {
+	if x != nil {
+		return x.RequiredDomains
+	}
+	return nil
+}
+
 // IloDomainScore represents a scored domain for a user
 type IloDomainScore struct {
 	state         protoimpl.MessageState
@@ -305,6 +329,9 @@ type IloTestResult struct {
 	Scores           []*IloDomainScore `protobuf:"bytes,5,rep,name=scores,proto3" json:"scores,omitempty"`                                             // Structured scores by domain
 	TopDomains       []string          `protobuf:"bytes,6,rep,name=top_domains,json=topDomains,proto3" json:"top_domains,omitempty"`                   // Top domain codes
 	SuggestedCareers []string          `protobuf:"bytes,7,rep,name=suggested_careers,json=suggestedCareers,proto3" json:"suggested_careers,omitempty"` // List of suggested career fields
+	// The LLM analysis given when the result was submitted, so a retried
+	// submission gets it back; empty until it is saved
+	Analysis string `protobuf:"bytes,8,opt,name=analysis,proto3" json:"analysis,omitempty"`
 }
 
 func (x *IloTestResult) Reset() {
@@ -388,6 +415,13 @@ func (x *IloTestResult) GetSuggestedCareers() []string {
 	return nil
 }
 
+func (x *IloTestResult) GetAnalysis() string {
+	if x != nil {
+		return x.Analysis
+	}
+	return ""
+}
+
 // IloAnswer represents a single answer to an ILO test question
 type IloAnswer struct {
 	state         protoimpl.MessageState
@@ -458,9 +492,17 @@ type SubmitIloTestResultRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	UserId        string       `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Answers       []*IloAnswer `protobuf:"bytes,2,rep,name=answers,proto3" json:"answers,omitempty"`
-	RawResultData string       `protobuf:"bytes,3,opt,name=raw_result_data,json=rawResultData,proto3" json:"raw_result_data,omitempty"` // Optional raw data for backward compatibility
+	UserId  string       `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Answers []*IloAnswer `protobuf:"bytes,2,rep,name=answers,proto3" json:"answers,omitempty"`
+	// The answers and per-domain totals as JSON, computed by the api-gateway
+	// from the validated answers
+	RawResultData string `protobuf:"bytes,3,opt,name=raw_result_data,json=rawResultData,proto3" json:"raw_result_data,omitempty"`
+	// Identifies the submission so a retried one is not saved twice; the
+	// user's result already submitted with the same key is returned instead.
+	// Without one, the key is derived from the user, the answers and the
+	// time, so identical answers within minutes of each other are one
+	// submission
+	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
 }
 
 func (x *SubmitIloTestResultRequest) Reset() {
@@ -516,6 +558,13 @@ func (x *SubmitIloTestResultRequest) GetRawResultData() string {
 	return ""
 }
 
+func (x *SubmitIloTestResultRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 // Response after submitting an ILO test result
 type SubmitIloTestResultResponse struct {
 	state         protoimpl.MessageState
@@ -523,6 +572,8 @@ type SubmitIloTestResultResponse struct {
 	unknownFields protoimpl.UnknownFields
 
 	Result *IloTestResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// The submission repeated an earlier one, whose result this is
+	Duplicate bool `protobuf:"varint,2,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
 }
 
 func (x *SubmitIloTestResultResponse) Reset() {
@@ -564,6 +615,13 @@ func (x *SubmitIloTestResultResponse) GetResult() *IloTestResult {
 	return nil
 }
 
+func (x *SubmitIloTestResultResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
 // Request to get all ILO test results for a user
 type GetIloTestResultsRequest struct {
 	state         protoimpl.MessageState
@@ -756,6 +814,109 @@ func (x *GetIloTestResultResponse) GetResult() *IloTestResult {
 	return nil
 }
 
+// Request to save the analysis generated for one of the user's results
+type SaveIloTestResultAnalysisRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ResultId string `protobuf:"bytes,1,opt,name=result_id,json=resultId,proto3" json:"result_id,omitempty"`
+	UserId   string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Analysis string `protobuf:"bytes,3,opt,name=analysis,proto3" json:"analysis,omitempty"`
+}
+
+func (x *SaveIloTestResultAnalysisRequest) Reset() {
+	*x = SaveIloTestResultAnalysisRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_careerup_v1_ilo_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SaveIloTestResultAnalysisRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveIloTestResultAnalysisRequest) ProtoMessage() {}
+
+func (x *SaveIloTestResultAnalysisRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_careerup_v1_ilo_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveIloTestResultAnalysisRequest.ProtoReflect.Descriptor instead.
+func (*SaveIloTestResultAnalysisRequest) Descriptor() ([]byte, []int) {
+	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SaveIloTestResultAnalysisRequest) GetResultId() string {
+	if x != nil {
+		return x.ResultId
+	}
+	return ""
+}
+
+func (x *SaveIloTestResultAnalysisRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SaveIloTestResultAnalysisRequest) GetAnalysis() string {
+	if x != nil {
+		return x.Analysis
+	}
+	return ""
+}
+
+// Response after saving an analysis
+type SaveIloTestResultAnalysisResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SaveIloTestResultAnalysisResponse) Reset() {
+	*x = SaveIloTestResultAnalysisResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_careerup_v1_ilo_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SaveIloTestResultAnalysisResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveIloTestResultAnalysisResponse) ProtoMessage() {}
+
+func (x *SaveIloTestResultAnalysisResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_careerup_v1_ilo_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveIloTestResultAnalysisResponse.ProtoReflect.Descriptor instead.
+func (*SaveIloTestResultAnalysisResponse) Descriptor() ([]byte, []int) {
+	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{13}
+}
+
 // Request to get the ILO test (questions/structure)
 type GetIloTestRequest struct {
 	state         protoimpl.MessageState
@@ -766,7 +927,7 @@ type GetIloTestRequest struct {
 func (x *GetIloTestRequest) Reset() {
 	*x = GetIloTestRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_careerup_v1_ilo_proto_msgTypes[12]
+		mi := &file_careerup_v1_ilo_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -779,7 +940,7 @@ func (x *GetIloTestRequest) String() string {
 func (*GetIloTestRequest) ProtoMessage() {}
 
 func (x *GetIloTestRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_careerup_v1_ilo_proto_msgTypes[12]
+	mi := &file_careerup_v1_ilo_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -792,7 +953,7 @@ func (x *GetIloTestRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetIloTestRequest.ProtoReflect.Descriptor instead.
 func (*GetIloTestRequest) Descriptor() ([]byte, []int) {
-	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{12}
+	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{14}
 }
 
 // ILO test question structure
@@ -811,7 +972,7 @@ type IloTestQuestion struct {
 func (x *IloTestQuestion) Reset() {
 	*x = IloTestQuestion{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_careerup_v1_ilo_proto_msgTypes[13]
+		mi := &file_careerup_v1_ilo_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -824,7 +985,7 @@ func (x *IloTestQuestion) String() string {
 func (*IloTestQuestion) ProtoMessage() {}
 
 func (x *IloTestQuestion) ProtoReflect() protoreflect.Message {
-	mi := &file_careerup_v1_ilo_proto_msgTypes[13]
+	mi := &file_careerup_v1_ilo_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -837,7 +998,7 @@ func (x *IloTestQuestion) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IloTestQuestion.ProtoReflect.Descriptor instead.
 func (*IloTestQuestion) Descriptor() ([]byte, []int) {
-	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{13}
+	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *IloTestQuestion) GetId() string {
@@ -889,7 +1050,7 @@ type GetIloTestResponse struct {
 func (x *GetIloTestResponse) Reset() {
 	*x = GetIloTestResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_careerup_v1_ilo_proto_msgTypes[14]
+		mi := &file_careerup_v1_ilo_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -902,7 +1063,7 @@ func (x *GetIloTestResponse) String() string {
 func (*GetIloTestResponse) ProtoMessage() {}
 
 func (x *GetIloTestResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_careerup_v1_ilo_proto_msgTypes[14]
+	mi := &file_careerup_v1_ilo_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -915,7 +1076,7 @@ func (x *GetIloTestResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetIloTestResponse.ProtoReflect.Descriptor instead.
 func (*GetIloTestResponse) Descriptor() ([]byte, []int) {
-	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{14}
+	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetIloTestResponse) GetQuestions() []*IloTestQuestion {
@@ -947,12 +1108,15 @@ type GetIloCareerSuggestionsRequest struct {
 
 	DomainCodes []string `protobuf:"bytes,1,rep,name=domain_codes,json=domainCodes,proto3" json:"domain_codes,omitempty"` // Domain codes to get suggestions for
 	Limit       int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                               // Maximum number of suggestions
+	// Optional scores for the domains; match_percent is computed from them, and
+	// every domain counts as 100% without them
+	Scores []*IloDomainScore `protobuf:"bytes,3,rep,name=scores,proto3" json:"scores,omitempty"`
 }
 
 func (x *GetIloCareerSuggestionsRequest) Reset() {
 	*x = GetIloCareerSuggestionsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_careerup_v1_ilo_proto_msgTypes[15]
+		mi := &file_careerup_v1_ilo_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -965,7 +1129,7 @@ func (x *GetIloCareerSuggestionsRequest) String() string {
 func (*GetIloCareerSuggestionsRequest) ProtoMessage() {}
 
 func (x *GetIloCareerSuggestionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_careerup_v1_ilo_proto_msgTypes[15]
+	mi := &file_careerup_v1_ilo_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -978,7 +1142,7 @@ func (x *GetIloCareerSuggestionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetIloCareerSuggestionsRequest.ProtoReflect.Descriptor instead.
 func (*GetIloCareerSuggestionsRequest) Descriptor() ([]byte, []int) {
-	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{15}
+	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetIloCareerSuggestionsRequest) GetDomainCodes() []string {
@@ -995,6 +1159,13 @@ func (x *GetIloCareerSuggestionsRequest) GetLimit() int32 {
 	return 0
 }
 
+func (x *GetIloCareerSuggestionsRequest) GetScores() []*IloDomainScore {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
 // Response with career suggestions
 type GetIloCareerSuggestionsResponse struct {
 	state         protoimpl.MessageState
@@ -1007,7 +1178,7 @@ type GetIloCareerSuggestionsResponse struct {
 func (x *GetIloCareerSuggestionsResponse) Reset() {
 	*x = GetIloCareerSuggestionsResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_careerup_v1_ilo_proto_msgTypes[16]
+		mi := &file_careerup_v1_ilo_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1020,7 +1191,7 @@ func (x *GetIloCareerSuggestionsResponse) String() string {
 func (*GetIloCareerSuggestionsResponse) ProtoMessage() {}
 
 func (x *GetIloCareerSuggestionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_careerup_v1_ilo_proto_msgTypes[16]
+	mi := &file_careerup_v1_ilo_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1033,7 +1204,7 @@ func (x *GetIloCareerSuggestionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetIloCareerSuggestionsResponse.ProtoReflect.Descriptor instead.
 func (*GetIloCareerSuggestionsResponse) Descriptor() ([]byte, []int) {
-	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{16}
+	return file_careerup_v1_ilo_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *GetIloCareerSuggestionsResponse) GetSuggestions() []*IloCareerSuggestion {
@@ -1062,156 +1233,189 @@ var file_careerup_v1_ilo_proto_rawDesc = []byte{
 	0x76, 0x65, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
 	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x75, 0x67,
 	0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73,
-	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x59, 0x0a, 0x13, 0x49, 0x6c, 0x6f,
-	0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x64,
-	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x5f, 0x66, 0x69, 0x65, 0x6c,
-	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x46,
-	0x69, 0x65, 0x6c, 0x64, 0x22, 0x92, 0x01, 0x0a, 0x0e, 0x49, 0x6c, 0x6f, 0x44, 0x6f, 0x6d, 0x61,
-	0x69, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69,
-	0x6e, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f,
-	0x6d, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x61, 0x77, 0x5f,
-	0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x72, 0x61, 0x77,
-	0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12,
-	0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x22, 0xfb, 0x01, 0x0a, 0x0d, 0x49, 0x6c,
-	0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75,
-	0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73,
-	0x65, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x5f, 0x64,
-	0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
-	0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x64, 0x41, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x05,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e,
-	0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x53, 0x63, 0x6f, 0x72,
-	0x65, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x70,
-	0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a,
-	0x74, 0x6f, 0x70, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x73, 0x75,
-	0x67, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x73, 0x18,
-	0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64,
-	0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x73, 0x22, 0x7e, 0x0a, 0x09, 0x49, 0x6c, 0x6f, 0x41, 0x6e,
-	0x73, 0x77, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
-	0x6e, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x27,
-	0x0a, 0x0f, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65,
-	0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x8f, 0x01, 0x0a, 0x1a, 0x53, 0x75, 0x62, 0x6d,
-	0x69, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12,
-	0x30, 0x0a, 0x07, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x16, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49,
-	0x6c, 0x6f, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x52, 0x07, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72,
-	0x73, 0x12, 0x26, 0x0a, 0x0f, 0x72, 0x61, 0x77, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x5f,
-	0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x61, 0x77, 0x52,
-	0x65, 0x73, 0x75, 0x6c, 0x74, 0x44, 0x61, 0x74, 0x61, 0x22, 0x51, 0x0a, 0x1b, 0x53, 0x75, 0x62,
-	0x6d, 0x69, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75,
-	0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65,
-	0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x33, 0x0a, 0x18,
-	0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
-	0x64, 0x22, 0x51, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52,
-	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34,
-	0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x1a, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c,
-	0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x73, 0x22, 0x36, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65,
-	0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x1b, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x49, 0x64, 0x22, 0x4e, 0x0a, 0x18,
-	0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75,
-	0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65,
-	0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x13, 0x0a, 0x11,
-	0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x22, 0x99, 0x01, 0x0a, 0x0f, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65,
-	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f,
-	0x6e, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12,
-	0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65,
-	0x78, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x63, 0x6f, 0x64,
-	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x43,
-	0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xb1, 0x01,
-	0x0a, 0x12, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x09, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72,
-	0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65,
-	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x12, 0x30, 0x0a, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x16, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e,
-	0x49, 0x6c, 0x6f, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69,
-	0x6e, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31,
-	0x2e, 0x49, 0x6c, 0x6f, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c,
-	0x73, 0x22, 0x59, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x43, 0x61, 0x72, 0x65, 0x65,
-	0x72, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x63, 0x6f,
-	0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69,
-	0x6e, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x65, 0x0a, 0x1f,
-	0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x53, 0x75, 0x67, 0x67,
-	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x42, 0x0a, 0x0b, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e,
-	0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x53, 0x75, 0x67, 0x67,
-	0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69,
-	0x6f, 0x6e, 0x73, 0x32, 0x80, 0x04, 0x0a, 0x0a, 0x49, 0x6c, 0x6f, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x12, 0x68, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x49, 0x6c, 0x6f, 0x54,
-	0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x27, 0x2e, 0x63, 0x61, 0x72, 0x65,
-	0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x49, 0x6c,
-	0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x28, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31,
-	0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x11,
-	0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x73, 0x12, 0x25, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e,
-	0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65,
-	0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xc7, 0x01, 0x0a, 0x13, 0x49, 0x6c,
+	0x6f, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x5f, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0c, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x71, 0x75,
+	0x69, 0x72, 0x65, 0x64, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x44, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x73, 0x22, 0x92, 0x01, 0x0a, 0x0e, 0x49, 0x6c, 0x6f, 0x44, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x61, 0x77, 0x5f, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x72, 0x61, 0x77, 0x53,
+	0x63, 0x6f, 0x72, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c,
+	0x65, 0x76, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x22, 0x97, 0x02, 0x0a, 0x0d, 0x49, 0x6c, 0x6f,
+	0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x5f, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76,
+	0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x52, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x70, 0x5f,
+	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x74,
+	0x6f, 0x70, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x73, 0x75, 0x67,
+	0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x73, 0x18, 0x07,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x43,
+	0x61, 0x72, 0x65, 0x65, 0x72, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73,
+	0x69, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73,
+	0x69, 0x73, 0x22, 0x7e, 0x0a, 0x09, 0x49, 0x6c, 0x6f, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x12,
+	0x1f, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x12, 0x27, 0x0a, 0x0f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x69, 0x6f, 0x6e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0e, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0xb8, 0x01, 0x0a, 0x1a, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x49, 0x6c, 0x6f,
+	0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x07, 0x61, 0x6e,
+	0x73, 0x77, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x61,
+	0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x41, 0x6e, 0x73,
+	0x77, 0x65, 0x72, 0x52, 0x07, 0x61, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x73, 0x12, 0x26, 0x0a, 0x0f,
+	0x72, 0x61, 0x77, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x61, 0x77, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69,
+	0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x22, 0x6f, 0x0a,
+	0x1b, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63,
+	0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x54, 0x65,
+	0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x22, 0x33,
+	0x0a, 0x18, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x22, 0x51, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73,
 	0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x5f, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x12, 0x24, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e,
+	0x12, 0x34, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e,
+	0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x36, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f,
+	0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x49, 0x64, 0x22, 0x4e,
+	0x0a, 0x18, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x61, 0x72,
+	0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x74,
+	0x0a, 0x20, 0x53, 0x61, 0x76, 0x65, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x49, 0x64, 0x12,
+	0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x6e, 0x61, 0x6c,
+	0x79, 0x73, 0x69, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x6e, 0x61, 0x6c,
+	0x79, 0x73, 0x69, 0x73, 0x22, 0x23, 0x0a, 0x21, 0x53, 0x61, 0x76, 0x65, 0x49, 0x6c, 0x6f, 0x54,
+	0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x13, 0x0a, 0x11, 0x47, 0x65, 0x74,
+	0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x99,
+	0x01, 0x0a, 0x0f, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12,
+	0x1f, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xb1, 0x01, 0x0a, 0x12, 0x47,
+	0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x3a, 0x0a, 0x09, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e,
+	0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x73, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x09, 0x71, 0x75, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x30, 0x0a,
+	0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f,
+	0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x07, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12,
+	0x2d, 0x0a, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c,
+	0x6f, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x22, 0x8e,
+	0x01, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x63, 0x6f, 0x64, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x43,
+	0x6f, 0x64, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x63,
+	0x6f, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x61, 0x72,
+	0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x44, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x22,
+	0x65, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x42, 0x0a, 0x0b, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72,
+	0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6c, 0x6f, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x73, 0x75, 0x67, 0x67, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x32, 0xfc, 0x04, 0x0a, 0x0a, 0x49, 0x6c, 0x6f, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x68, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x49,
+	0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x27, 0x2e, 0x63,
+	0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73,
+	0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x62, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e,
 	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x63, 0x61, 0x72,
-	0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54,
-	0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x4d, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x12,
-	0x1e, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
-	0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1f, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
-	0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x74, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72,
-	0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2b, 0x2e, 0x63, 0x61,
+	0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x63, 0x61,
 	0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f,
-	0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65,
-	0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x43, 0x61, 0x72,
-	0x65, 0x65, 0x72, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0xb0, 0x01, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x2e, 0x63,
-	0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x42, 0x08, 0x49, 0x6c, 0x6f, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2d, 0x49, 0x6e, 0x63, 0x2f,
-	0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2d, 0x6d, 0x6f, 0x6e, 0x6f, 0x72, 0x65, 0x70,
-	0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70,
-	0x2f, 0x76, 0x31, 0x3b, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x76, 0x31, 0xa2, 0x02,
-	0x03, 0x43, 0x58, 0x58, 0xaa, 0x02, 0x0b, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e,
-	0x56, 0x31, 0xca, 0x02, 0x0b, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x5c, 0x56, 0x31,
-	0xe2, 0x02, 0x17, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x5c, 0x56, 0x31, 0x5c, 0x47,
-	0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x0c, 0x43, 0x61, 0x72,
-	0x65, 0x65, 0x72, 0x75, 0x70, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73,
+	0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x24, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72,
+	0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e,
+	0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49,
+	0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x7a, 0x0a, 0x19, 0x53, 0x61, 0x76, 0x65, 0x49, 0x6c, 0x6f, 0x54,
+	0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69,
+	0x73, 0x12, 0x2d, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x61, 0x76, 0x65, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x2e, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x61, 0x76, 0x65, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x4d, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x12, 0x1e,
+	0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x49, 0x6c, 0x6f, 0x54, 0x65, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x74, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2b, 0x2e, 0x63, 0x61, 0x72,
+	0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x43,
+	0x61, 0x72, 0x65, 0x65, 0x72, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72,
+	0x75, 0x70, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6c, 0x6f, 0x43, 0x61, 0x72, 0x65,
+	0x65, 0x72, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0xb0, 0x01, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x2e, 0x63, 0x61,
+	0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x76, 0x31, 0x42, 0x08, 0x49, 0x6c, 0x6f, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2d, 0x49, 0x6e, 0x63, 0x2f, 0x63,
+	0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2d, 0x6d, 0x6f, 0x6e, 0x6f, 0x72, 0x65, 0x70, 0x6f,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2f,
+	0x76, 0x31, 0x3b, 0x63, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x76, 0x31, 0xa2, 0x02, 0x03,
+	0x43, 0x58, 0x58, 0xaa, 0x02, 0x0b, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x2e, 0x56,
+	0x31, 0xca, 0x02, 0x0b, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x5c, 0x56, 0x31, 0xe2,
+	0x02, 0x17, 0x43, 0x61, 0x72, 0x65, 0x65, 0x72, 0x75, 0x70, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50,
+	0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x0c, 0x43, 0x61, 0x72, 0x65,
+	0x65, 0x72, 0x75, 0x70, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1226,25 +1430,27 @@ func file_careerup_v1_ilo_proto_rawDescGZIP() []byte {
 	return file_careerup_v1_ilo_proto_rawDescData
 }
 
-var file_careerup_v1_ilo_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_careerup_v1_ilo_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_careerup_v1_ilo_proto_goTypes = []interface{}{
-	(*IloDomain)(nil),                       // 0: careerup.v1.IloDomain
-	(*IloLevel)(nil),                        // 1: careerup.v1.IloLevel
-	(*IloCareerSuggestion)(nil),             // 2: careerup.v1.IloCareerSuggestion
-	(*IloDomainScore)(nil),                  // 3: careerup.v1.IloDomainScore
-	(*IloTestResult)(nil),                   // 4: careerup.v1.IloTestResult
-	(*IloAnswer)(nil),                       // 5: careerup.v1.IloAnswer
-	(*SubmitIloTestResultRequest)(nil),      // 6: careerup.v1.SubmitIloTestResultRequest
-	(*SubmitIloTestResultResponse)(nil),     // 7: careerup.v1.SubmitIloTestResultResponse
-	(*GetIloTestResultsRequest)(nil),        // 8: careerup.v1.GetIloTestResultsRequest
-	(*GetIloTestResultsResponse)(nil),       // 9: careerup.v1.GetIloTestResultsResponse
-	(*GetIloTestResultRequest)(nil),         // 10: careerup.v1.GetIloTestResultRequest
-	(*GetIloTestResultResponse)(nil),        // 11: careerup.v1.GetIloTestResultResponse
-	(*GetIloTestRequest)(nil),               // 12: careerup.v1.GetIloTestRequest
-	(*IloTestQuestion)(nil),                 // 13: careerup.v1.IloTestQuestion
-	(*GetIloTestResponse)(nil),              // 14: careerup.v1.GetIloTestResponse
-	(*GetIloCareerSuggestionsRequest)(nil),  // 15: careerup.v1.GetIloCareerSuggestionsRequest
-	(*GetIloCareerSuggestionsResponse)(nil), // 16: careerup.v1.GetIloCareerSuggestionsResponse
+	(*IloDomain)(nil),                         // 0: careerup.v1.IloDomain
+	(*IloLevel)(nil),                          // 1: careerup.v1.IloLevel
+	(*IloCareerSuggestion)(nil),               // 2: careerup.v1.IloCareerSuggestion
+	(*IloDomainScore)(nil),                    // 3: careerup.v1.IloDomainScore
+	(*IloTestResult)(nil),                     // 4: careerup.v1.IloTestResult
+	(*IloAnswer)(nil),                         // 5: careerup.v1.IloAnswer
+	(*SubmitIloTestResultRequest)(nil),        // 6: careerup.v1.SubmitIloTestResultRequest
+	(*SubmitIloTestResultResponse)(nil),       // 7: careerup.v1.SubmitIloTestResultResponse
+	(*GetIloTestResultsRequest)(nil),          // 8: careerup.v1.GetIloTestResultsRequest
+	(*GetIloTestResultsResponse)(nil),         // 9: careerup.v1.GetIloTestResultsResponse
+	(*GetIloTestResultRequest)(nil),           // 10: careerup.v1.GetIloTestResultRequest
+	(*GetIloTestResultResponse)(nil),          // 11: careerup.v1.GetIloTestResultResponse
+	(*SaveIloTestResultAnalysisRequest)(nil),  // 12: careerup.v1.SaveIloTestResultAnalysisRequest
+	(*SaveIloTestResultAnalysisResponse)(nil), // 13: careerup.v1.SaveIloTestResultAnalysisResponse
+	(*GetIloTestRequest)(nil),                 // 14: careerup.v1.GetIloTestRequest
+	(*IloTestQuestion)(nil),                   // 15: careerup.v1.IloTestQuestion
+	(*GetIloTestResponse)(nil),                // 16: careerup.v1.GetIloTestResponse
+	(*GetIloCareerSuggestionsRequest)(nil),    // 17: careerup.v1.GetIloCareerSuggestionsRequest
+	(*GetIloCareerSuggestionsResponse)(nil),   // 18: careerup.v1.GetIloCareerSuggestionsResponse
 }
 var file_careerup_v1_ilo_proto_depIdxs = []int32{
 	3,  // 0: careerup.v1.IloTestResult.scores:type_name -> careerup.v1.IloDomainScore
@@ -1252,25 +1458,28 @@ var file_careerup_v1_ilo_proto_depIdxs = []int32{
 	4,  // 2: careerup.v1.SubmitIloTestResultResponse.result:type_name -> careerup.v1.IloTestResult
 	4,  // 3: careerup.v1.GetIloTestResultsResponse.results:type_name -> careerup.v1.IloTestResult
 	4,  // 4: careerup.v1.GetIloTestResultResponse.result:type_name -> careerup.v1.IloTestResult
-	13, // 5: careerup.v1.GetIloTestResponse.questions:type_name -> careerup.v1.IloTestQuestion
+	15, // 5: careerup.v1.GetIloTestResponse.questions:type_name -> careerup.v1.IloTestQuestion
 	0,  // 6: careerup.v1.GetIloTestResponse.domains:type_name -> careerup.v1.IloDomain
 	1,  // 7: careerup.v1.GetIloTestResponse.levels:type_name -> careerup.v1.IloLevel
-	2,  // 8: careerup.v1.GetIloCareerSuggestionsResponse.suggestions:type_name -> careerup.v1.IloCareerSuggestion
-	6,  // 9: careerup.v1.IloService.SubmitIloTestResult:input_type -> careerup.v1.SubmitIloTestResultRequest
-	8,  // 10: careerup.v1.IloService.GetIloTestResults:input_type -> careerup.v1.GetIloTestResultsRequest
-	10, // 11: careerup.v1.IloService.GetIloTestResult:input_type -> careerup.v1.GetIloTestResultRequest
-	12, // 12: careerup.v1.IloService.GetIloTest:input_type -> careerup.v1.GetIloTestRequest
-	15, // 13: careerup.v1.IloService.GetIloCareerSuggestions:input_type -> careerup.v1.GetIloCareerSuggestionsRequest
-	7,  // 14: careerup.v1.IloService.SubmitIloTestResult:output_type -> careerup.v1.SubmitIloTestResultResponse
-	9,  // 15: careerup.v1.IloService.GetIloTestResults:output_type -> careerup.v1.GetIloTestResultsResponse
-	11, // 16: careerup.v1.IloService.GetIloTestResult:output_type -> careerup.v1.GetIloTestResultResponse
-	14, // 17: careerup.v1.IloService.GetIloTest:output_type -> careerup.v1.GetIloTestResponse
-	16, // 18: careerup.v1.IloService.GetIloCareerSuggestions:output_type -> careerup.v1.GetIloCareerSuggestionsResponse
-	14, // [14:19] is the sub-list for method output_type
-	9,  // [9:14] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	3,  // 8: careerup.v1.GetIloCareerSuggestionsRequest.scores:type_name -> careerup.v1.IloDomainScore
+	2,  // 9: careerup.v1.GetIloCareerSuggestionsResponse.suggestions:type_name -> careerup.v1.IloCareerSuggestion
+	6,  // 10: careerup.v1.IloService.SubmitIloTestResult:input_type -> careerup.v1.SubmitIloTestResultRequest
+	8,  // 11: careerup.v1.IloService.GetIloTestResults:input_type -> careerup.v1.GetIloTestResultsRequest
+	10, // 12: careerup.v1.IloService.GetIloTestResult:input_type -> careerup.v1.GetIloTestResultRequest
+	12, // 13: careerup.v1.IloService.SaveIloTestResultAnalysis:input_type -> careerup.v1.SaveIloTestResultAnalysisRequest
+	14, // 14: careerup.v1.IloService.GetIloTest:input_type -> careerup.v1.GetIloTestRequest
+	17, // 15: careerup.v1.IloService.GetIloCareerSuggestions:input_type -> careerup.v1.GetIloCareerSuggestionsRequest
+	7,  // 16: careerup.v1.IloService.SubmitIloTestResult:output_type -> careerup.v1.SubmitIloTestResultResponse
+	9,  // 17: careerup.v1.IloService.GetIloTestResults:output_type -> careerup.v1.GetIloTestResultsResponse
+	11, // 18: careerup.v1.IloService.GetIloTestResult:output_type -> careerup.v1.GetIloTestResultResponse
+	13, // 19: careerup.v1.IloService.SaveIloTestResultAnalysis:output_type -> careerup.v1.SaveIloTestResultAnalysisResponse
+	16, // 20: careerup.v1.IloService.GetIloTest:output_type -> careerup.v1.GetIloTestResponse
+	18, // 21: careerup.v1.IloService.GetIloCareerSuggestions:output_type -> careerup.v1.GetIloCareerSuggestionsResponse
+	16, // [16:22] is the sub-list for method output_type
+	10, // [10:16] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_careerup_v1_ilo_proto_init() }
@@ -1424,7 +1633,7 @@ func file_careerup_v1_ilo_proto_init() {
 			}
 		}
 		file_careerup_v1_ilo_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetIloTestRequest); i {
+			switch v := v.(*SaveIloTestResultAnalysisRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1436,7 +1645,7 @@ func file_careerup_v1_ilo_proto_init() {
 			}
 		}
 		file_careerup_v1_ilo_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IloTestQuestion); i {
+			switch v := v.(*SaveIloTestResultAnalysisResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1448,7 +1657,7 @@ func file_careerup_v1_ilo_proto_init() {
 			}
 		}
 		file_careerup_v1_ilo_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetIloTestResponse); i {
+			switch v := v.(*GetIloTestRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1460,7 +1669,7 @@ func file_careerup_v1_ilo_proto_init() {
 			}
 		}
 		file_careerup_v1_ilo_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetIloCareerSuggestionsRequest); i {
+			switch v := v.(*IloTestQuestion); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1472,6 +1681,30 @@ func file_careerup_v1_ilo_proto_init() {
 			}
 		}
 		file_careerup_v1_ilo_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetIloTestResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_careerup_v1_ilo_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetIloCareerSuggestionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_careerup_v1_ilo_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetIloCareerSuggestionsResponse); i {
 			case 0:
 				return &v.state
@@ -1490,7 +1723,7 @@ func file_careerup_v1_ilo_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_careerup_v1_ilo_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,11 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	IloService_SubmitIloTestResult_FullMethodName     = "/careerup.v1.IloService/SubmitIloTestResult"
-	IloService_GetIloTestResults_FullMethodName       = "/careerup.v1.IloService/GetIloTestResults"
-	IloService_GetIloTestResult_FullMethodName        = "/careerup.v1.IloService/GetIloTestResult"
-	IloService_GetIloTest_FullMethodName              = "/careerup.v1.IloService/GetIloTest"
-	IloService_GetIloCareerSuggestions_FullMethodName = "/careerup.v1.IloService/GetIloCareerSuggestions"
+	IloService_SubmitIloTestResult_FullMethodName       = "/careerup.v1.IloService/SubmitIloTestResult"
+	IloService_GetIloTestResults_FullMethodName         = "/careerup.v1.IloService/GetIloTestResults"
+	IloService_GetIloTestResult_FullMethodName          = "/careerup.v1.IloService/GetIloTestResult"
+	IloService_SaveIloTestResultAnalysis_FullMethodName = "/careerup.v1.IloService/SaveIloTestResultAnalysis"
+	IloService_GetIloTest_FullMethodName                = "/careerup.v1.IloService/GetIloTest"
+	IloService_GetIloCareerSuggestions_FullMethodName   = "/careerup.v1.IloService/GetIloCareerSuggestions"
 )
 
 // IloServiceClient is the client API for IloService service.
@@ -36,6 +37,8 @@ type IloServiceClient interface {
 	GetIloTestResults(ctx context.Context, in *GetIloTestResultsRequest, opts ...grpc.CallOption) (*GetIloTestResultsResponse, error)
 	// Get a specific ILO test result by ID
 	GetIloTestResult(ctx context.Context, in *GetIloTestResultRequest, opts ...grpc.CallOption) (*GetIloTestResultResponse, error)
+	// Save the analysis generated for a submitted result
+	SaveIloTestResultAnalysis(ctx context.Context, in *SaveIloTestResultAnalysisRequest, opts ...grpc.CallOption) (*SaveIloTestResultAnalysisResponse, error)
 	// Get ILO test questions and structure
 	GetIloTest(ctx context.Context, in *GetIloTestRequest, opts ...grpc.CallOption) (*GetIloTestResponse, error)
 	// Get career suggestions based on domain scores
@@ -77,6 +80,15 @@ func (c *iloServiceClient) GetIloTestResult(ctx context.Context, in *GetIloTestR
 	return out, nil
 }
 
+func (c *iloServiceClient) SaveIloTestResultAnalysis(ctx context.Context, in *SaveIloTestResultAnalysisRequest, opts ...grpc.CallOption) (*SaveIloTestResultAnalysisResponse, error) {
+	out := new(SaveIloTestResultAnalysisResponse)
+	err := c.cc.Invoke(ctx, IloService_SaveIloTestResultAnalysis_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *iloServiceClient) GetIloTest(ctx context.Context, in *GetIloTestRequest, opts ...grpc.CallOption) (*GetIloTestResponse, error) {
 	out := new(GetIloTestResponse)
 	err := c.cc.Invoke(ctx, IloService_GetIloTest_FullMethodName, in, out, opts...)
@@ -105,6 +117,8 @@ type IloServiceServer interface {
 	GetIloTestResults(context.Context, *GetIloTestResultsRequest) (*GetIloTestResultsResponse, error)
 	// Get a specific ILO test result by ID
 	GetIloTestResult(context.Context, *GetIloTestResultRequest) (*GetIloTestResultResponse, error)
+	// Save the analysis generated for a submitted result
+	SaveIloTestResultAnalysis(context.Context, *SaveIloTestResultAnalysisRequest) (*SaveIloTestResultAnalysisResponse, error)
 	// Get ILO test questions and structure
 	GetIloTest(context.Context, *GetIloTestRequest) (*GetIloTestResponse, error)
 	// Get career suggestions based on domain scores
@@ -125,6 +139,9 @@ func (UnimplementedIloServiceServer) GetIloTestResults(context.Context, *GetIloT
 func (UnimplementedIloServiceServer) GetIloTestResult(context.Context, *GetIloTestResultRequest) (*GetIloTestResultResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetIloTestResult not implemented")
 }
+func (UnimplementedIloServiceServer) SaveIloTestResultAnalysis(context.Context, *SaveIloTestResultAnalysisRequest) (*SaveIloTestResultAnalysisResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveIloTestResultAnalysis not implemented")
+}
 func (UnimplementedIloServiceServer) GetIloTest(context.Context, *GetIloTestRequest) (*GetIloTestResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetIloTest not implemented")
 }
@@ -198,6 +215,24 @@ func _IloService_GetIloTestResult_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IloService_SaveIloTestResultAnalysis_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveIloTestResultAnalysisRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IloServiceServer).SaveIloTestResultAnalysis(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IloService_SaveIloTestResultAnalysis_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IloServiceServer).SaveIloTestResultAnalysis(ctx, req.(*SaveIloTestResultAnalysisRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _IloService_GetIloTest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetIloTestRequest)
 	if err := dec(in); err != nil {
@@ -253,6 +288,10 @@ var IloService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetIloTestResult",
 			Handler:    _IloService_GetIloTestResult_Handler,
 		},
+		{
+			MethodName: "SaveIloTestResultAnalysis",
+			Handler:    _IloService_SaveIloTestResultAnalysis_Handler,
+		},
 		{
 			MethodName: "GetIloTest",
 			Handler:    _IloService_GetIloTest_Handler,
@@ -19,27 +19,47 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	LLMService_GenerateStream_FullMethodName   = "/llm.v1.LLMService/GenerateStream"
-	LLMService_GenerateWithRAG_FullMethodName  = "/llm.v1.LLMService/GenerateWithRAG"
-	LLMService_IngestDocument_FullMethodName   = "/llm.v1.LLMService/IngestDocument"
-	LLMService_CreateCollection_FullMethodName = "/llm.v1.LLMService/CreateCollection"
-	LLMService_ListCollections_FullMethodName  = "/llm.v1.LLMService/ListCollections"
-	LLMService_DeleteCollection_FullMethodName = "/llm.v1.LLMService/DeleteCollection"
+	LLMService_GenerateStream_FullMethodName         = "/llm.v1.LLMService/GenerateStream"
+	LLMService_GenerateWithRAG_FullMethodName        = "/llm.v1.LLMService/GenerateWithRAG"
+	LLMService_GetUsage_FullMethodName               = "/llm.v1.LLMService/GetUsage"
+	LLMService_GenerateStructured_FullMethodName     = "/llm.v1.LLMService/GenerateStructured"
+	LLMService_IngestDocument_FullMethodName         = "/llm.v1.LLMService/IngestDocument"
+	LLMService_CreateCollection_FullMethodName       = "/llm.v1.LLMService/CreateCollection"
+	LLMService_ListCollections_FullMethodName        = "/llm.v1.LLMService/ListCollections"
+	LLMService_DeleteCollection_FullMethodName       = "/llm.v1.LLMService/DeleteCollection"
+	LLMService_GetKnowledgeBaseStatus_FullMethodName = "/llm.v1.LLMService/GetKnowledgeBaseStatus"
 )
 
 // LLMServiceClient is the client API for LLMService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type LLMServiceClient interface {
-	// GenerateStream streams responses from the LLM.
+	// GenerateStream streams responses from the LLM. With use_rag set it
+	// retrieves documents first and answers from them, as GenerateWithRAG does.
 	GenerateStream(ctx context.Context, in *GenerateStreamRequest, opts ...grpc.CallOption) (LLMService_GenerateStreamClient, error)
 	// GenerateWithRAG streams RAG-augmented responses from the LLM.
+	//
+	// Deprecated: call GenerateStream with use_rag set, which runs the same
+	// pipeline. Kept for existing clients.
 	GenerateWithRAG(ctx context.Context, in *GenerateWithRAGRequest, opts ...grpc.CallOption) (LLMService_GenerateWithRAGClient, error)
+	// GetUsage returns a user's token usage this month. Generation calls for a
+	// user over their monthly quota fail with RESOURCE_EXHAUSTED. They also fail
+	// with RESOURCE_EXHAUSTED when every model stays rate limited, and then
+	// carry a retry-after trailer with the seconds to wait.
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
+	// GenerateStructured returns output matching one of the gateway's schemas,
+	// such as career suggestions, validated before it is returned. Output the
+	// model could not get into shape fails with INTERNAL.
+	GenerateStructured(ctx context.Context, in *GenerateStructuredRequest, opts ...grpc.CallOption) (*GenerateStructuredResponse, error)
 	// Admin endpoints for dynamic document management
 	IngestDocument(ctx context.Context, in *IngestDocumentRequest, opts ...grpc.CallOption) (*IngestDocumentResponse, error)
 	CreateCollection(ctx context.Context, in *CreateCollectionRequest, opts ...grpc.CallOption) (*CreateCollectionResponse, error)
 	ListCollections(ctx context.Context, in *ListCollectionsRequest, opts ...grpc.CallOption) (*ListCollectionsResponse, error)
 	DeleteCollection(ctx context.Context, in *DeleteCollectionRequest, opts ...grpc.CallOption) (*DeleteCollectionResponse, error)
+	// GetKnowledgeBaseStatus reports what the vector database holds, so
+	// readiness checks can tell an empty knowledge base apart from a working
+	// one. Fails with UNAVAILABLE when the collections cannot be listed.
+	GetKnowledgeBaseStatus(ctx context.Context, in *GetKnowledgeBaseStatusRequest, opts ...grpc.CallOption) (*GetKnowledgeBaseStatusResponse, error)
 }
 
 type lLMServiceClient struct {
@@ -114,6 +134,24 @@ func (x *lLMServiceGenerateWithRAGClient) Recv() (*GenerateWithRAGResponse, erro
 	return m, nil
 }
 
+func (c *lLMServiceClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, LLMService_GetUsage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lLMServiceClient) GenerateStructured(ctx context.Context, in *GenerateStructuredRequest, opts ...grpc.CallOption) (*GenerateStructuredResponse, error) {
+	out := new(GenerateStructuredResponse)
+	err := c.cc.Invoke(ctx, LLMService_GenerateStructured_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lLMServiceClient) IngestDocument(ctx context.Context, in *IngestDocumentRequest, opts ...grpc.CallOption) (*IngestDocumentResponse, error) {
 	out := new(IngestDocumentResponse)
 	err := c.cc.Invoke(ctx, LLMService_IngestDocument_FullMethodName, in, out, opts...)
@@ -150,19 +188,45 @@ func (c *lLMServiceClient) DeleteCollection(ctx context.Context, in *DeleteColle
 	return out, nil
 }
 
+func (c *lLMServiceClient) GetKnowledgeBaseStatus(ctx context.Context, in *GetKnowledgeBaseStatusRequest, opts ...grpc.CallOption) (*GetKnowledgeBaseStatusResponse, error) {
+	out := new(GetKnowledgeBaseStatusResponse)
+	err := c.cc.Invoke(ctx, LLMService_GetKnowledgeBaseStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LLMServiceServer is the server API for LLMService service.
 // All implementations must embed UnimplementedLLMServiceServer
 // for forward compatibility
 type LLMServiceServer interface {
-	// GenerateStream streams responses from the LLM.
+	// GenerateStream streams responses from the LLM. With use_rag set it
+	// retrieves documents first and answers from them, as GenerateWithRAG does.
 	GenerateStream(*GenerateStreamRequest, LLMService_GenerateStreamServer) error
 	// GenerateWithRAG streams RAG-augmented responses from the LLM.
+	//
+	// Deprecated: call GenerateStream with use_rag set, which runs the same
+	// pipeline. Kept for existing clients.
 	GenerateWithRAG(*GenerateWithRAGRequest, LLMService_GenerateWithRAGServer) error
+	// GetUsage returns a user's token usage this month. Generation calls for a
+	// user over their monthly quota fail with RESOURCE_EXHAUSTED. They also fail
+	// with RESOURCE_EXHAUSTED when every model stays rate limited, and then
+	// carry a retry-after trailer with the seconds to wait.
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
+	// GenerateStructured returns output matching one of the gateway's schemas,
+	// such as career suggestions, validated before it is returned. Output the
+	// model could not get into shape fails with INTERNAL.
+	GenerateStructured(context.Context, *GenerateStructuredRequest) (*GenerateStructuredResponse, error)
 	// Admin endpoints for dynamic document management
 	IngestDocument(context.Context, *IngestDocumentRequest) (*IngestDocumentResponse, error)
 	CreateCollection(context.Context, *CreateCollectionRequest) (*CreateCollectionResponse, error)
 	ListCollections(context.Context, *ListCollectionsRequest) (*ListCollectionsResponse, error)
 	DeleteCollection(context.Context, *DeleteCollectionRequest) (*DeleteCollectionResponse, error)
+	// GetKnowledgeBaseStatus reports what the vector database holds, so
+	// readiness checks can tell an empty knowledge base apart from a working
+	// one. Fails with UNAVAILABLE when the collections cannot be listed.
+	GetKnowledgeBaseStatus(context.Context, *GetKnowledgeBaseStatusRequest) (*GetKnowledgeBaseStatusResponse, error)
 	mustEmbedUnimplementedLLMServiceServer()
 }
 
@@ -176,6 +240,12 @@ func (UnimplementedLLMServiceServer) GenerateStream(*GenerateStreamRequest, LLMS
 func (UnimplementedLLMServiceServer) GenerateWithRAG(*GenerateWithRAGRequest, LLMService_GenerateWithRAGServer) error {
 	return status.Errorf(codes.Unimplemented, "method GenerateWithRAG not implemented")
 }
+func (UnimplementedLLMServiceServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedLLMServiceServer) GenerateStructured(context.Context, *GenerateStructuredRequest) (*GenerateStructuredResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateStructured not implemented")
+}
 func (UnimplementedLLMServiceServer) IngestDocument(context.Context, *IngestDocumentRequest) (*IngestDocumentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IngestDocument not implemented")
 }
@@ -188,6 +258,9 @@ func (UnimplementedLLMServiceServer) ListCollections(context.Context, *ListColle
 func (UnimplementedLLMServiceServer) DeleteCollection(context.Context, *DeleteCollectionRequest) (*DeleteCollectionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteCollection not implemented")
 }
+func (UnimplementedLLMServiceServer) GetKnowledgeBaseStatus(context.Context, *GetKnowledgeBaseStatusRequest) (*GetKnowledgeBaseStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKnowledgeBaseStatus not implemented")
+}
 func (UnimplementedLLMServiceServer) mustEmbedUnimplementedLLMServiceServer() {}
 
 // UnsafeLLMServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -243,6 +316,42 @@ func (x *lLMServiceGenerateWithRAGServer) Send(m *GenerateWithRAGResponse) error
 	return x.ServerStream.SendMsg(m)
 }
 
+func _LLMService_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LLMService_GenerateStructured_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateStructuredRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).GenerateStructured(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_GenerateStructured_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).GenerateStructured(ctx, req.(*GenerateStructuredRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LLMService_IngestDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(IngestDocumentRequest)
 	if err := dec(in); err != nil {
@@ -315,6 +424,24 @@ func _LLMService_DeleteCollection_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LLMService_GetKnowledgeBaseStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKnowledgeBaseStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LLMServiceServer).GetKnowledgeBaseStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LLMService_GetKnowledgeBaseStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LLMServiceServer).GetKnowledgeBaseStatus(ctx, req.(*GetKnowledgeBaseStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LLMService_ServiceDesc is the grpc.ServiceDesc for LLMService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -322,6 +449,14 @@ var LLMService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "llm.v1.LLMService",
 	HandlerType: (*LLMServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUsage",
+			Handler:    _LLMService_GetUsage_Handler,
+		},
+		{
+			MethodName: "GenerateStructured",
+			Handler:    _LLMService_GenerateStructured_Handler,
+		},
 		{
 			MethodName: "IngestDocument",
 			Handler:    _LLMService_IngestDocument_Handler,
@@ -338,6 +473,10 @@ var LLMService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteCollection",
 			Handler:    _LLMService_DeleteCollection_Handler,
 		},
+		{
+			MethodName: "GetKnowledgeBaseStatus",
+			Handler:    _LLMService_GetKnowledgeBaseStatus_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 	"strconv"
+	"time"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/grpcclient"
 	_ "github.com/careerup-Inc/careerup-monorepo/services/api-gateway/docs"
 	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
 	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/config"
 	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/i18n"
 	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/middleware"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/share"
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -16,7 +22,6 @@ import (
 	"github.com/gofiber/swagger"
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
-	"google.golang.org/grpc"
 )
 
 // @title CareerUP API
@@ -40,6 +45,13 @@ import (
 // @scheme bearer
 // @bearerFormat JWT
 
+const (
+	// How long a readiness result is reused before the backends are checked again
+	readinessCacheTTL = 2 * time.Second
+	// How long each dependency gets to answer a readiness check
+	readinessCheckTimeout = time.Second
+)
+
 func main() {
 	log.Println("Starting API Gateway...")
 
@@ -52,6 +64,9 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	for _, warning := range cfg.Warnings() {
+		log.Printf("Warning: %s", warning)
+	}
 
 	app := fiber.New(fiber.Config{
 		ReadTimeout:  cfg.Server.ReadTimeout,
@@ -59,65 +74,163 @@ func main() {
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	})
 
+	// Keep tokens and user content out of the access log and of everything
+	// logged through the standard logger
+	redactor := middleware.NewRedactor(middleware.RedactionOptions{
+		Headers:       cfg.Logging.RedactHeaders,
+		QueryParams:   cfg.Logging.RedactQueryParams,
+		MaxBodyLength: cfg.Logging.MaxBodyLength,
+		HashBodies:    cfg.Logging.HashBodies,
+	})
+	log.SetOutput(redactor.Writer(os.Stderr))
+
 	// Middleware
 	app.Use(cors.New())
-	app.Use(logger.New())
+	// Platform and app version, for the access log and the backends
+	app.Use(middleware.ClientContext())
+	app.Use(middleware.RequestLogger(redactor, logger.Config{Format: cfg.Logging.Format}))
 
-	// Initialize Redis for rate limiting
+	// Initialize Redis for rate limiting and the shared caches
 	redisClient := redis.NewClient(&redis.Options{
 		Addr: cfg.RateLimit.RedisAddr,
 	})
 	defer redisClient.Close()
 
+	// Probes are registered ahead of rate limiting so load balancers are never
+	// throttled; dependencies are added once their clients exist
+	health := handler.NewHealthChecker(readinessCacheTTL, readinessCheckTimeout)
+	app.Get("/livez", health.HandleLivez)
+	app.Get("/readyz", health.HandleReadyz)
+
+	internalTraffic, err := middleware.NewInternalTraffic(cfg.RateLimit.Allowlist, cfg.RateLimit.InternalSecret)
+	if err != nil {
+		log.Fatalf("Invalid rate limit allowlist: %v", err)
+	}
+	failureMode, err := middleware.ParseFailureMode(cfg.RateLimit.FailureMode)
+	if err != nil {
+		log.Fatalf("Invalid rate limit config: %v", err)
+	}
+
+	// Redis is only required when rate limiting fails closed without it
+	pingRedis := func(ctx context.Context) error { return redisClient.Ping(ctx).Err() }
+	if cfg.RateLimit.Enabled && failureMode == middleware.FailClosed {
+		health.Add("redis", pingRedis)
+	} else {
+		health.AddOptional("redis", pingRedis)
+	}
+
 	// Add rate limiting if enabled
 	if cfg.RateLimit.Enabled {
-		app.Use(middleware.RateLimitMiddleware(redisClient, cfg.RateLimit.RequestsPerMinute))
+		limiter := middleware.NewRateLimiter(redisClient, cfg.RateLimit.RequestsPerMinute, internalTraffic, failureMode)
+		health.AddOptional("rate_limit", func(context.Context) error { return limiter.Degraded() })
+		app.Use(limiter.Middleware())
 	}
 
+	// Maintenance switch, flipped through the internal-only admin routes
+	readOnly := middleware.NewReadOnlyMode(cfg.Server.ReadOnly)
+	rejectWrites := readOnly.Middleware()
+	admin := app.Group("/admin", middleware.InternalOnly(internalTraffic))
+	admin.Get("/read-only", readOnly.HandleGet)
+	admin.Post("/read-only", readOnly.HandleSet)
+
+	// API handlers only decode JSON bodies; reject anything else up front.
+	// Multipart upload routes, should any be added, go in the exemptions
+	app.Use("/api/v1", middleware.RequireJSON())
+
 	// Swagger
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
 	// Serve Swagger JSON
 	app.Static("/swagger/doc.json", "./docs/swagger.json")
 
-	// Initialize clients
-	authClient, err := client.NewAuthClient(cfg.Auth.ServiceAddr)
+	// Initialize clients. Backends are connected in the background, so a
+	// backend that is not up yet keeps the gateway not ready instead of
+	// stopping it from starting; only an invalid address is fatal
+	balancing := grpcclient.Balancing{Scheme: cfg.GRPCClient.ResolverScheme, Policy: cfg.GRPCClient.LoadBalancingPolicy}
+	maxRecvMsgSize := grpcclient.MaxRecvMsgSize(cfg.GRPCClient.MaxRecvMsgSize)
+	authClient, err := client.NewAuthClient(cfg.Auth.ServiceAddr, balancing, maxRecvMsgSize)
 	if err != nil {
 		log.Fatalf("Failed to create auth client: %v", err)
 	}
 	defer authClient.Close()
 
-	chatClient, err := client.NewChatClient(cfg.Chat.ServiceAddr)
+	chatClient, err := client.NewChatClient(cfg.Chat.ServiceAddr, balancing, maxRecvMsgSize)
 	if err != nil {
 		log.Fatalf("Failed to create chat client: %v", err)
 	}
 	defer chatClient.Close()
 
+	health.Add("auth", func(ctx context.Context) error { return client.CheckConn(ctx, authClient.Conn()) })
+	health.Add("chat", func(ctx context.Context) error { return client.CheckConn(ctx, chatClient.Conn()) })
+
 	// Initialize ILO and LLM gRPC connections
-	iloConn, err := grpc.NewClient(cfg.Ilo.ServiceAddr, grpc.WithInsecure())
+	iloConn, err := balancing.Dial(cfg.Ilo.ServiceAddr, maxRecvMsgSize)
 	if err != nil {
-		log.Fatalf("Failed to connect to ILO service: %v", err)
+		log.Fatalf("Failed to create ILO client: %v", err)
 	}
 	defer iloConn.Close()
-	llmConn, err := grpc.NewClient(cfg.LLM.ServiceAddr, grpc.WithInsecure())
+	llmConn, err := balancing.Dial(cfg.LLM.ServiceAddr, maxRecvMsgSize)
 	if err != nil {
-		log.Fatalf("Failed to connect to LLM service: %v", err)
+		log.Fatalf("Failed to create LLM client: %v", err)
 	}
 	defer llmConn.Close()
 
+	// Only the ILO and LLM routes need these, so they degrade readiness
+	// rather than failing it
+	health.AddOptional("ilo", func(ctx context.Context) error { return client.CheckConn(ctx, iloConn) })
+	health.AddOptional("llm", func(ctx context.Context) error { return client.CheckConn(ctx, llmConn) })
+
 	// Initialize ILO and LLM clients
 	iloClient := client.NewIloClient(iloConn)
 	llmClient := client.NewLLMClient(llmConn)
+	// Chat still answers from general knowledge without documents, but ops
+	// should know setup is incomplete
+	health.AddOptional("knowledge_base", llmClient.CheckKnowledgeBase)
+	if cfg.LLM.Cache.Enabled {
+		llmClient.EnableResponseCache(client.NewRedisResponseCache(redisClient), cfg.LLM.Cache.TTL, cfg.LLM.Model)
+	}
 
 	// Initialize middlewares with auth client
-	authMiddleware := middleware.AuthMiddleware(authClient)
+	tokenCache, err := middleware.NewTokenCache(cfg.Auth.TokenCache, redisClient, cfg.Auth.TokenCacheTTL)
+	if err != nil {
+		log.Fatalf("Invalid auth config: %v", err)
+	}
+	authMiddleware := middleware.AuthMiddleware(authClient, tokenCache)
 
 	// Initialize handlers with auth-core service address for direct REST calls
 	mainHandler := handler.NewHandler(authClient, chatClient, iloClient, llmClient, cfg.Auth.ServiceAddr)
+	mainHandler.SetTokenCache(tokenCache)
+	if cfg.Ilo.ChatContext.Enabled {
+		mainHandler.EnableIloChatContext(cfg.Ilo.ChatContext.MaxChars)
+	}
+	if cfg.Chat.TokenBatchWindow > 0 {
+		mainHandler.EnableTokenBatching(cfg.Chat.TokenBatchWindow)
+	}
+	mainHandler.SetOutboundBuffer(cfg.Chat.OutboundBuffer, handler.OverflowPolicy(cfg.Chat.OverflowPolicy))
+	if cfg.Share.Enabled {
+		signer, err := share.NewSigner(cfg.Share.Secret)
+		if err != nil {
+			log.Fatalf("Invalid share config: %v", err)
+		}
+		revocations, err := share.NewStore(cfg.Share.Store, redisClient)
+		if err != nil {
+			log.Fatalf("Invalid share config: %v", err)
+		}
+		mainHandler.EnableSharing(signer, revocations, cfg.Share.DefaultTTL, cfg.Share.MaxTTL)
+	}
+	catalog, err := i18n.New(cfg.Server.DefaultLanguage)
+	if err != nil {
+		log.Fatalf("Invalid server config: %v", err)
+	}
+	mainHandler.SetCatalog(catalog)
 
 	// Protected routes (Apply middleware before defining groups/routes)
-	protectedUser := app.Group("/api/v1/user", authMiddleware)       // Apply middleware to group
-	protectedProfile := app.Group("/api/v1/profile", authMiddleware) // Apply middleware to group
+	routeTimeouts := cfg.Server.RouteTimeouts
+	// Timeout comes first so the route's deadline also bounds token validation
+	protectedUser := app.Group("/api/v1/user", middleware.Timeout(routeTimeouts.User), authMiddleware)          // Apply middleware to group
+	protectedProfile := app.Group("/api/v1/profile", middleware.Timeout(routeTimeouts.Profile), authMiddleware) // Apply middleware to group
+	protectedConversations := app.Group("/api/v1/conversations", middleware.Timeout(routeTimeouts.User), authMiddleware)
+	protectedUsage := app.Group("/api/v1/usage", middleware.Timeout(routeTimeouts.User), authMiddleware)
 
 	// Routes
 	api := app.Group("/api/v1")
@@ -130,11 +243,12 @@ func main() {
 		})
 
 		// Auth routes
-		auth := api.Group("/auth")
+		auth := api.Group("/auth", middleware.Timeout(routeTimeouts.Auth))
 		{
-			auth.Post("/register", mainHandler.HandleRegister)
+			auth.Post("/register", rejectWrites, mainHandler.HandleRegister)
 			auth.Post("/login", mainHandler.HandleLogin)
 			auth.Post("/refresh", mainHandler.HandleRefreshToken)
+			auth.Post("/logout", mainHandler.HandleLogout)
 			auth.Get("/validate", mainHandler.HandleValidateToken)
 		}
 
@@ -144,19 +258,40 @@ func main() {
 
 		// Profile routes (Protected via group middleware)
 		// These routes are already prefixed with /api/v1/profile by the group
-		protectedProfile.Put("", mainHandler.HandleUpdateProfile) // Use PUT on the group base path
+		protectedProfile.Put("", rejectWrites, mainHandler.HandleUpdateProfile)  // Use PUT on the group base path
+		protectedProfile.Patch("", rejectWrites, mainHandler.HandlePatchProfile) // Only the fields sent; null or empty clears
+
+		// Conversation routes (Protected via group middleware)
+		protectedConversations.Get("", mainHandler.HandleListConversations)                              // ?include_archived=true lists archived ones too
+		protectedConversations.Get("/:id/export", mainHandler.HandleExportConversation)                  // Download a transcript
+		protectedConversations.Post("/:id/archive", rejectWrites, mainHandler.HandleArchiveConversation) // Hide from the default list
+		protectedConversations.Post("/:id/unarchive", rejectWrites, mainHandler.HandleUnarchiveConversation)
+		protectedConversations.Post("/:id/reset", rejectWrites, mainHandler.HandleResetConversation) // Clear the history, keeping the ID and title
+		if cfg.Share.Enabled {
+			protectedConversations.Post("/:id/shares", rejectWrites, mainHandler.HandleShareConversation) // Read-only link for someone without an account
+			// Revoking stays possible in maintenance mode
+			protectedConversations.Delete("/:id/shares/:token", mainHandler.HandleRevokeShare)
+			// Public: the token is the credential
+			api.Get("/shared/:token", middleware.Timeout(routeTimeouts.User), mainHandler.HandleGetSharedConversation)
+		}
+
+		// LLM token usage this month (Protected via group middleware)
+		protectedUsage.Get("", mainHandler.HandleGetUsage)
 
-		// Chat routes with WebSocket support (Unprotected initial upgrade, auth done inside handler)
+		// Chat routes with WebSocket support (Unprotected initial upgrade, auth done inside handler).
+		// Not timed: the connection lives for the whole chat session
 		api.Get("/ws", mainHandler.HandleWebSocket)
 		api.Get("/ws", websocket.New(mainHandler.WebSocketProxy))
 
 		// ILO routes
-		ilo := api.Group("/ilo")
+		ilo := api.Group("/ilo", middleware.Timeout(routeTimeouts.Ilo))
 		{
-			ilo.Get("/test", mainHandler.HandleGetIloTest)             // Get ILO test questions
-			ilo.Post("/result", mainHandler.HandleIloTestResult)       // Submit ILO test result
-			ilo.Get("/results", mainHandler.HandleGetIloResults)       // Get all ILO test results for user
-			ilo.Get("/result/:id", mainHandler.HandleGetIloResultById) // Get a specific ILO test result
+			ilo.Get("/test", mainHandler.HandleGetIloTest)                        // Get ILO test questions
+			ilo.Post("/result", rejectWrites, mainHandler.HandleIloTestResult)    // Submit ILO test result
+			ilo.Get("/results", mainHandler.HandleGetIloResults)                  // Get all ILO test results for user
+			ilo.Get("/result/:id", mainHandler.HandleGetIloResultById)            // Get a specific ILO test result
+			ilo.Post("/result/:id/analyze", mainHandler.HandleReanalyzeIloResult) // Re-run the analysis of a result
+			ilo.Post("/careers", mainHandler.HandleGetIloCareerSuggestions)       // Suggest careers for domains
 		}
 	}
 
@@ -3,7 +3,9 @@ module github.com/careerup-Inc/careerup-monorepo/services/api-gateway
 go 1.24.2
 
 require (
+	github.com/careerup-Inc/careerup-monorepo v0.0.0-00010101000000-000000000000
 	github.com/careerup-Inc/careerup-monorepo/proto v0.0.0-20250521211351-28bd5cbb5f7b
+	github.com/fasthttp/websocket v1.5.12
 	github.com/gofiber/contrib/websocket v1.3.4
 	github.com/gofiber/fiber/v2 v2.52.6
 	github.com/gofiber/swagger v1.1.1
@@ -12,6 +14,7 @@ require (
 	github.com/spf13/viper v1.20.1
 	github.com/stretchr/testify v1.10.0
 	github.com/swaggo/swag v1.16.4
+	go.uber.org/goleak v1.3.0
 	golang.org/x/time v0.11.0
 	google.golang.org/grpc v1.72.1
 )
@@ -28,7 +31,6 @@ require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
-	github.com/fasthttp/websocket v1.5.12 // indirect
 	github.com/fsnotify/fsnotify v1.9.0 // indirect
 	github.com/go-openapi/jsonpointer v0.21.1 // indirect
 	github.com/go-openapi/jsonreference v0.21.0 // indirect
@@ -63,3 +65,10 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250519155744-55703ea1f237 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// pkg/ and the generated protos are built from this checkout, so images
+// build from the repository root (see Dockerfile)
+replace (
+	github.com/careerup-Inc/careerup-monorepo => ../..
+	github.com/careerup-Inc/careerup-monorepo/proto => ../../proto
+)
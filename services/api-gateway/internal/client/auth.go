@@ -6,9 +6,9 @@ import (
 	"log"
 	"time"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/grpcclient"
 	pb "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type AuthClientInterface interface {
@@ -25,13 +25,16 @@ type AuthClient struct {
 	client pb.AuthServiceClient
 }
 
-func NewAuthClient(addr string) (*AuthClient, error) {
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewAuthClient connects to the auth service replicas at addr, spreading
+// calls over them as balancing says. It does not wait for the service to be
+// up; see grpcclient.Balancing.Dial. opts are added to the connection's
+// options.
+func NewAuthClient(addr string, balancing grpcclient.Balancing, opts ...grpc.DialOption) (*AuthClient, error) {
+	conn, err := balancing.Dial(addr, opts...)
 	if err != nil {
-		log.Fatalf("Failed to connect to auth service: %s %v", addr, err)
-	} else {
-		log.Printf("Connected to auth service at %s", addr)
+		return nil, fmt.Errorf("failed to connect to auth service at %s: %w", addr, err)
 	}
+	log.Printf("Connecting to auth service at %s", addr)
 
 	return &AuthClient{
 		conn:   conn,
@@ -71,12 +74,14 @@ type User struct {
 	Interests []string `json:"interests"`
 }
 
+// UpdateUserRequest changes the fields that are non-nil; the rest are left
+// as they are. Set a field to an empty value to clear it.
 type UpdateUserRequest struct {
-	Token     string   `json:"-"`
-	FirstName string   `json:"firstName"`
-	LastName  string   `json:"lastName"`
-	Hometown  string   `json:"hometown"`
-	Interests []string `json:"interests"`
+	Token     string    `json:"-"`
+	FirstName *string   `json:"firstName"`
+	LastName  *string   `json:"lastName"`
+	Hometown  *string   `json:"hometown"`
+	Interests *[]string `json:"interests"`
 }
 
 func (c *AuthClient) Register(ctx context.Context, req *RegisterRequest) (*User, error) {
@@ -152,17 +157,22 @@ func (c *AuthClient) ValidateToken(ctx context.Context, token string) (*User, er
 }
 
 func (c *AuthClient) UpdateUser(ctx context.Context, req *UpdateUserRequest) (*User, error) {
-	if req.FirstName == "" && req.LastName == "" && req.Hometown == "" && len(req.Interests) == 0 {
+	if req.FirstName == nil && req.LastName == nil && req.Hometown == nil && req.Interests == nil {
 		return nil, fmt.Errorf("at least one field is required to update")
 	}
 
-	resp, err := c.client.UpdateUser(ctx, &pb.UpdateUserRequest{
+	pbReq := &pb.UpdateUserRequest{
 		Token:     req.Token,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Hometown:  req.Hometown,
-		Interests: req.Interests,
-	})
+	}
+	if req.Interests != nil {
+		pbReq.Interests = *req.Interests
+		pbReq.ReplaceInterests = true
+	}
+
+	resp, err := c.client.UpdateUser(ctx, pbReq)
 	if err != nil {
 		return nil, err
 	}
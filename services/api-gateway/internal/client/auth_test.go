@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeAuthServiceClient records the update request
+type fakeAuthServiceClient struct {
+	pb.AuthServiceClient
+	req *pb.UpdateUserRequest
+}
+
+func (f *fakeAuthServiceClient) UpdateUser(ctx context.Context, in *pb.UpdateUserRequest, opts ...grpc.CallOption) (*pb.UpdateUserResponse, error) {
+	f.req = in
+	return &pb.UpdateUserResponse{User: &pb.User{}}, nil
+}
+
+func TestUpdateUser_FieldPresence(t *testing.T) {
+	empty := ""
+
+	t.Run("omitted fields are not sent", func(t *testing.T) {
+		fake := &fakeAuthServiceClient{}
+		c := &AuthClient{client: fake}
+		hometown := "Hue"
+		_, err := c.UpdateUser(context.Background(), &UpdateUserRequest{Token: "t", Hometown: &hometown})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Hue", fake.req.GetHometown())
+		assert.Nil(t, fake.req.FirstName)
+		assert.Nil(t, fake.req.LastName)
+		assert.False(t, fake.req.GetReplaceInterests())
+	})
+
+	t.Run("empty fields are sent to clear them", func(t *testing.T) {
+		fake := &fakeAuthServiceClient{}
+		c := &AuthClient{client: fake}
+		_, err := c.UpdateUser(context.Background(), &UpdateUserRequest{Token: "t", Hometown: &empty, Interests: &[]string{}})
+		require.NoError(t, err)
+
+		require.NotNil(t, fake.req.Hometown)
+		assert.Empty(t, *fake.req.Hometown)
+		assert.True(t, fake.req.GetReplaceInterests())
+		assert.Empty(t, fake.req.GetInterests())
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		c := &AuthClient{client: &fakeAuthServiceClient{}}
+		_, err := c.UpdateUser(context.Background(), &UpdateUserRequest{Token: "t"})
+		assert.Error(t, err)
+	})
+}
@@ -1,16 +1,30 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"log"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/grpcclient"
 	chatpb "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type ChatClientInterface interface {
 	// GetChatServiceClient returns the raw gRPC client for the ConversationService.
 	GetChatServiceClient() chatpb.ConversationServiceClient
+	// GetConversation loads a conversation's history; chat-gateway checks
+	// that it belongs to the "user-id" in the outgoing metadata.
+	GetConversation(ctx context.Context, conversationID string) (*chatpb.GetConversationResponse, error)
+	// ListConversations lists the conversations of the "user-id" in the
+	// outgoing metadata, archived ones only with includeArchived.
+	ListConversations(ctx context.Context, includeArchived bool) ([]*chatpb.ConversationInfo, error)
+	// SetConversationArchived archives or unarchives a conversation;
+	// chat-gateway checks ownership as for GetConversation.
+	SetConversationArchived(ctx context.Context, conversationID string, archived bool) (*chatpb.ConversationInfo, error)
+	// ResetConversation clears a conversation's history, keeping its ID and
+	// title; chat-gateway checks ownership as for GetConversation.
+	ResetConversation(ctx context.Context, conversationID string) (*chatpb.ConversationInfo, error)
 	Close() error
 }
 
@@ -20,14 +34,16 @@ type ChatClient struct {
 	client chatpb.ConversationServiceClient
 }
 
-// NewChatClient needs to initialize the ConversationServiceClient
-func NewChatClient(addr string) (*ChatClient, error) {
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewChatClient connects to the chat service replicas at addr, spreading
+// calls over them as balancing says. It does not wait for the service to be
+// up; see grpcclient.Balancing.Dial. opts are added to the connection's
+// options.
+func NewChatClient(addr string, balancing grpcclient.Balancing, opts ...grpc.DialOption) (*ChatClient, error) {
+	conn, err := balancing.Dial(addr, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to chat service at %s: %w", addr, err)
-	} else {
-		fmt.Printf("Connected to chat service at %s\n", addr)
 	}
+	log.Printf("Connecting to chat service at %s", addr)
 	client := chatpb.NewConversationServiceClient(conn)
 	return &ChatClient{
 		conn:   conn,
@@ -40,6 +56,41 @@ func (c *ChatClient) GetChatServiceClient() chatpb.ConversationServiceClient {
 	return c.client
 }
 
+// GetConversation implements the ChatClientInterface.
+func (c *ChatClient) GetConversation(ctx context.Context, conversationID string) (*chatpb.GetConversationResponse, error) {
+	return c.client.GetConversation(ctx, &chatpb.GetConversationRequest{ConversationId: conversationID})
+}
+
+// ListConversations implements the ChatClientInterface.
+func (c *ChatClient) ListConversations(ctx context.Context, includeArchived bool) ([]*chatpb.ConversationInfo, error) {
+	res, err := c.client.ListConversations(ctx, &chatpb.ListConversationsRequest{IncludeArchived: includeArchived})
+	if err != nil {
+		return nil, err
+	}
+	return res.GetConversations(), nil
+}
+
+// SetConversationArchived implements the ChatClientInterface.
+func (c *ChatClient) SetConversationArchived(ctx context.Context, conversationID string, archived bool) (*chatpb.ConversationInfo, error) {
+	res, err := c.client.SetConversationArchived(ctx, &chatpb.SetConversationArchivedRequest{
+		ConversationId: conversationID,
+		Archived:       archived,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.GetConversation(), nil
+}
+
+// ResetConversation implements the ChatClientInterface.
+func (c *ChatClient) ResetConversation(ctx context.Context, conversationID string) (*chatpb.ConversationInfo, error) {
+	res, err := c.client.ResetConversation(ctx, &chatpb.ResetConversationRequest{ConversationId: conversationID})
+	if err != nil {
+		return nil, err
+	}
+	return res.GetConversation(), nil
+}
+
 func (c *ChatClient) Close() error {
 	if c.conn != nil {
 		return c.conn.Close()
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	llmpb "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// CheckConn waits until conn is ready, connecting it if idle. It fails if
+// the connection does not become ready before ctx is done.
+func CheckConn(ctx context.Context, conn *grpc.ClientConn) error {
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Idle:
+			conn.Connect()
+		case connectivity.Shutdown:
+			return errors.New("connection closed")
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection not ready (%s): %w", state, ctx.Err())
+		}
+	}
+}
+
+// Conn returns the connection to auth-core, for health checks.
+func (c *AuthClient) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Conn returns the connection to chat-gateway, for health checks.
+func (c *ChatClient) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// ErrKnowledgeBaseEmpty means no collection holds documents, so RAG answers
+// come from general knowledge until documents are ingested.
+var ErrKnowledgeBaseEmpty = errors.New("knowledge base is empty: no collection holds documents")
+
+// CheckKnowledgeBase fails if llm-gateway's knowledge base is empty or its
+// status cannot be fetched.
+func (c *LLMClient) CheckKnowledgeBase(ctx context.Context) error {
+	status, err := c.client.GetKnowledgeBaseStatus(ctx, &llmpb.GetKnowledgeBaseStatusRequest{})
+	if err != nil {
+		return fmt.Errorf("knowledge base status: %w", err)
+	}
+	if status.GetEmpty() {
+		return ErrKnowledgeBaseEmpty
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	llmpb "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestCheckConn(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	dial := func(t *testing.T, dialer func(context.Context, string) (net.Conn, error)) *grpc.ClientConn {
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(dialer),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+
+	t.Run("reachable backend is ready", func(t *testing.T) {
+		conn := dial(t, func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) })
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		assert.NoError(t, CheckConn(ctx, conn))
+	})
+
+	t.Run("unreachable backend times out", func(t *testing.T) {
+		conn := dial(t, func(ctx context.Context, _ string) (net.Conn, error) {
+			return nil, &net.OpError{Op: "dial", Err: assert.AnError}
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, CheckConn(ctx, conn), context.DeadlineExceeded)
+	})
+
+	t.Run("closed connection", func(t *testing.T) {
+		conn := dial(t, func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) })
+		conn.Close()
+		assert.EqualError(t, CheckConn(context.Background(), conn), "connection closed")
+	})
+}
+
+// knowledgeBaseClient reports a fixed knowledge base status
+type knowledgeBaseClient struct {
+	llmpb.LLMServiceClient
+	status *llmpb.GetKnowledgeBaseStatusResponse
+	err    error
+}
+
+func (f *knowledgeBaseClient) GetKnowledgeBaseStatus(context.Context, *llmpb.GetKnowledgeBaseStatusRequest, ...grpc.CallOption) (*llmpb.GetKnowledgeBaseStatusResponse, error) {
+	return f.status, f.err
+}
+
+func TestCheckKnowledgeBase(t *testing.T) {
+	check := func(fake *knowledgeBaseClient) error {
+		return (&LLMClient{client: fake}).CheckKnowledgeBase(context.Background())
+	}
+
+	assert.NoError(t, check(&knowledgeBaseClient{status: &llmpb.GetKnowledgeBaseStatusResponse{Collections: 2, Documents: 40}}))
+	assert.ErrorIs(t, check(&knowledgeBaseClient{status: &llmpb.GetKnowledgeBaseStatusResponse{Empty: true, Collections: 1}}), ErrKnowledgeBaseEmpty)
+
+	err := check(&knowledgeBaseClient{err: status.Error(codes.Unavailable, "Vector store not available")})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Contains(t, err.Error(), "knowledge base status")
+}
@@ -41,6 +41,15 @@ type IloDomainScore struct {
 	Rank       int32   `json:"rank"`
 }
 
+// IloCareerSuggestion is a career field suggested for a set of domain scores
+type IloCareerSuggestion struct {
+	CareerField     string   `json:"career_field"`
+	DomainCode      string   `json:"domain_code"`
+	MatchPercent    float32  `json:"match_percent"`
+	Rationale       string   `json:"rationale"`
+	RequiredDomains []string `json:"required_domains"`
+}
+
 // IloAnswer represents a single answer to an ILO test question
 type IloAnswer struct {
 	QuestionID     string `json:"question_id"`
@@ -52,6 +61,9 @@ type SubmitILOTestResultRequest struct {
 	UserID        string
 	Answers       []IloAnswer
 	RawResultData string
+	// IdempotencyKey identifies the submission across retries. When empty
+	// the ILO service derives one from the user and answers.
+	IdempotencyKey string
 }
 
 type SubmitILOTestResultResponse struct {
@@ -62,6 +74,11 @@ type SubmitILOTestResultResponse struct {
 	Scores           []IloDomainScore
 	TopDomains       []string
 	SuggestedCareers []string
+	// Duplicate is set when the submission repeated an earlier one and
+	// the result is the one saved then
+	Duplicate bool
+	// Analysis is the LLM analysis saved with the result, if any
+	Analysis string
 }
 
 // IloTestQuestion represents a question in the ILO test
@@ -92,9 +109,10 @@ func (c *IloClient) SubmitILOTestResult(ctx context.Context, req *SubmitILOTestR
 	}
 
 	resp, err := c.client.SubmitIloTestResult(ctx, &careerupv1.SubmitIloTestResultRequest{
-		UserId:        req.UserID,
-		Answers:       protoAnswers,
-		RawResultData: req.RawResultData,
+		UserId:         req.UserID,
+		Answers:        protoAnswers,
+		RawResultData:  req.RawResultData,
+		IdempotencyKey: req.IdempotencyKey,
 	})
 
 	if err != nil {
@@ -123,9 +141,22 @@ func (c *IloClient) SubmitILOTestResult(ctx context.Context, req *SubmitILOTestR
 		Scores:           scores,
 		TopDomains:       result.GetTopDomains(),
 		SuggestedCareers: result.GetSuggestedCareers(),
+		Duplicate:        resp.GetDuplicate(),
+		Analysis:         result.GetAnalysis(),
 	}, nil
 }
 
+// SaveIloTestResultAnalysis saves the analysis generated for one of the
+// user's results, so a retried submission gets it back.
+func (c *IloClient) SaveIloTestResultAnalysis(ctx context.Context, resultID, userID, analysis string) error {
+	_, err := c.client.SaveIloTestResultAnalysis(ctx, &careerupv1.SaveIloTestResultAnalysisRequest{
+		ResultId: resultID,
+		UserId:   userID,
+		Analysis: analysis,
+	})
+	return err
+}
+
 // GetIloTest retrieves the ILO test questions from the backend service
 func (c *IloClient) GetIloTest(ctx context.Context) (*GetIloTestResponse, error) {
 	resp, err := c.client.GetIloTest(ctx, &careerupv1.GetIloTestRequest{})
@@ -170,23 +201,60 @@ func (c *IloClient) GetIloTest(ctx context.Context) (*GetIloTestResponse, error)
 	}, nil
 }
 
-// GetIloCareerSuggestions retrieves career suggestions based on domain scores
-func (c *IloClient) GetIloCareerSuggestions(ctx context.Context, domainCodes []string, limit int32) ([]string, error) {
-	resp, err := c.client.GetIloCareerSuggestions(ctx, &careerupv1.GetIloCareerSuggestionsRequest{
+// GetIloCareerSuggestions retrieves career suggestions based on domain scores.
+// scores is optional; without it every requested domain counts as 100%.
+// Suggestions without a career field are dropped and match percents are
+// clamped to 0-100.
+func (c *IloClient) GetIloCareerSuggestions(ctx context.Context, domainCodes []string, scores []IloDomainScore, limit int32) ([]IloCareerSuggestion, error) {
+	req := &careerupv1.GetIloCareerSuggestionsRequest{
 		DomainCodes: domainCodes,
 		Limit:       limit,
-	})
+	}
+	for _, s := range scores {
+		req.Scores = append(req.Scores, &careerupv1.IloDomainScore{
+			DomainCode: s.DomainCode,
+			RawScore:   s.RawScore,
+			Percent:    s.Percent,
+			Level:      s.Level,
+			Rank:       s.Rank,
+		})
+	}
 
+	resp, err := c.client.GetIloCareerSuggestions(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	careers := make([]string, 0, len(resp.GetSuggestions()))
-	for _, suggestion := range resp.GetSuggestions() {
-		careers = append(careers, suggestion.GetCareerField())
+	suggestions := make([]IloCareerSuggestion, 0, len(resp.GetSuggestions()))
+	for _, s := range resp.GetSuggestions() {
+		if s.GetCareerField() == "" {
+			continue
+		}
+		required := s.GetRequiredDomains()
+		if len(required) == 0 && s.GetDomainCode() != "" {
+			// Older ILO services only report the primary domain
+			required = []string{s.GetDomainCode()}
+		}
+		suggestions = append(suggestions, IloCareerSuggestion{
+			CareerField:     s.GetCareerField(),
+			DomainCode:      s.GetDomainCode(),
+			MatchPercent:    min(max(s.GetMatchPercent(), 0), 100),
+			Rationale:       s.GetRationale(),
+			RequiredDomains: required,
+		})
 	}
 
-	return careers, nil
+	return suggestions, nil
+}
+
+// CareerFields lists the career field names of suggestions, the shape
+// GetIloCareerSuggestions returned before suggestions carried details.
+func CareerFields(suggestions []IloCareerSuggestion) []string {
+	careers := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		careers = append(careers, s.CareerField)
+	}
+	return careers
 }
 
 // GetIloTestResults retrieves all ILO test results for a user
@@ -221,6 +289,7 @@ func (c *IloClient) GetIloTestResults(ctx context.Context, userID string) ([]*Su
 			Scores:           scores,
 			TopDomains:       protoResult.GetTopDomains(),
 			SuggestedCareers: protoResult.GetSuggestedCareers(),
+			Analysis:         protoResult.GetAnalysis(),
 		})
 	}
 
@@ -260,5 +329,6 @@ func (c *IloClient) GetIloTestResultById(ctx context.Context, resultID string) (
         Scores:           scores,
         TopDomains:       result.GetTopDomains(),
         SuggestedCareers: result.GetSuggestedCareers(),
+        Analysis:         result.GetAnalysis(),
     }, nil
 }
\ No newline at end of file
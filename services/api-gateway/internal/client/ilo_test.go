@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	careerupv1 "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeIloServiceClient records the suggestions request and returns a canned
+// response
+type fakeIloServiceClient struct {
+	careerupv1.IloServiceClient
+	resp *careerupv1.GetIloCareerSuggestionsResponse
+	req  *careerupv1.GetIloCareerSuggestionsRequest
+}
+
+func (f *fakeIloServiceClient) GetIloCareerSuggestions(ctx context.Context, in *careerupv1.GetIloCareerSuggestionsRequest, opts ...grpc.CallOption) (*careerupv1.GetIloCareerSuggestionsResponse, error) {
+	f.req = in
+	return f.resp, nil
+}
+
+func TestGetIloCareerSuggestions(t *testing.T) {
+	fake := &fakeIloServiceClient{resp: &careerupv1.GetIloCareerSuggestionsResponse{
+		Suggestions: []*careerupv1.IloCareerSuggestion{
+			{
+				DomainCode:      "LOGIC",
+				CareerField:     "Data Science",
+				MatchPercent:    82.5,
+				Rationale:       "Strong logical reasoning.",
+				RequiredDomains: []string{"LOGIC", "LANG"},
+			},
+			// From an ILO service that predates the detailed fields
+			{DomainCode: "LANG", CareerField: "Journalism"},
+			// Out-of-range percent and a missing career field
+			{DomainCode: "ART", CareerField: "Design", MatchPercent: 140},
+			{DomainCode: "ART"},
+		},
+	}}
+	c := &IloClient{client: fake}
+
+	suggestions, err := c.GetIloCareerSuggestions(context.Background(), []string{"LOGIC", "LANG"},
+		[]IloDomainScore{{DomainCode: "LOGIC", Percent: 90}}, 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"LOGIC", "LANG"}, fake.req.GetDomainCodes())
+	assert.Equal(t, int32(5), fake.req.GetLimit())
+	require.Len(t, fake.req.GetScores(), 1)
+	assert.Equal(t, float32(90), fake.req.GetScores()[0].GetPercent())
+
+	assert.Equal(t, []IloCareerSuggestion{
+		{
+			CareerField:     "Data Science",
+			DomainCode:      "LOGIC",
+			MatchPercent:    82.5,
+			Rationale:       "Strong logical reasoning.",
+			RequiredDomains: []string{"LOGIC", "LANG"},
+		},
+		{CareerField: "Journalism", DomainCode: "LANG", RequiredDomains: []string{"LANG"}},
+		{CareerField: "Design", DomainCode: "ART", MatchPercent: 100, RequiredDomains: []string{"ART"}},
+	}, suggestions)
+	assert.Equal(t, []string{"Data Science", "Journalism", "Design"}, CareerFields(suggestions))
+}
@@ -2,13 +2,27 @@ package client
 
 import (
 	context "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	llmpb "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type LLMClient struct {
 	client llmpb.LLMServiceClient
+	// Optional response cache, nil when caching is disabled
+	cache    ResponseCache
+	cacheTTL time.Duration
+	model    string
 }
 
 func NewLLMClient(conn *grpc.ClientConn) *LLMClient {
@@ -17,30 +31,234 @@ func NewLLMClient(conn *grpc.ClientConn) *LLMClient {
 	}
 }
 
+// EnableResponseCache turns on response caching for identical requests. model
+// is part of the cache key so switching models never serves stale answers.
+func (c *LLMClient) EnableResponseCache(cache ResponseCache, ttl time.Duration, model string) {
+	c.cache = cache
+	c.cacheTTL = ttl
+	c.model = model
+}
+
 type LLMAnalysisRequest struct {
 	Prompt string
 	UserID string
+	// BypassCache forces a fresh generation and refreshes the cached entry
+	BypassCache bool
 }
 
-type LLMAnalysisResponse struct {
-	Completion string
+// iloAnalysisSchema names llm-gateway's output schema for ILO analyses
+const iloAnalysisSchema = "ilo_analysis"
+
+// IloAnalysis is the analysis of an ILO test result, in the shape of
+// llm-gateway's ilo_analysis schema.
+type IloAnalysis struct {
+	// Overview of the candidate's dominant interest profile
+	Overview         string   `json:"overview"`
+	Strengths        []string `json:"strengths"`
+	DevelopmentAreas []string `json:"development_areas"`
+	// Three to five career fields that fit the profile
+	Careers []CareerSuggestion `json:"careers"`
+	// Actionable steps for the next 3 to 6 months
+	NextSteps []string `json:"next_steps"`
 }
 
-func (c *LLMClient) AnalyzeILOResult(ctx context.Context, req *LLMAnalysisRequest) (string, error) {
+// CareerSuggestion is a career field suggested for a candidate
+type CareerSuggestion struct {
+	CareerField string `json:"career_field"`
+	// How well the field fits, from 0 to 100
+	MatchPercent int    `json:"match_percent"`
+	Rationale    string `json:"rationale"`
+	// ILO domains the field draws on
+	DomainCodes []string `json:"domain_codes,omitempty"`
+}
+
+// ParseIloAnalysis decodes an analysis from its JSON, as llm-gateway
+// returns it and as it is saved with the result.
+func ParseIloAnalysis(data string) (*IloAnalysis, error) {
+	var analysis IloAnalysis
+	if err := json.Unmarshal([]byte(data), &analysis); err != nil {
+		return nil, fmt.Errorf("decode ILO analysis: %w", err)
+	}
+	return &analysis, nil
+}
+
+// BusyError is returned when llm-gateway gave up on its rate limited models;
+// the call may be retried after RetryAfter.
+type BusyError struct {
+	RetryAfter time.Duration
+	Message    string
+}
+
+func (e *BusyError) Error() string { return e.Message }
+
+// busyError turns llm-gateway giving up on rate limited models into a
+// *BusyError and returns other errors unchanged. The user's quota running out
+// is ResourceExhausted as well, but has no retry-after trailer.
+func busyError(err error, trailer metadata.MD) error {
+	if status.Code(err) != codes.ResourceExhausted {
+		return err
+	}
+	values := trailer.Get("retry-after")
+	if len(values) == 0 {
+		return err
+	}
+	seconds, convErr := strconv.Atoi(values[0])
+	if convErr != nil || seconds < 1 {
+		seconds = 1
+	}
+	return &BusyError{RetryAfter: time.Duration(seconds) * time.Second, Message: status.Convert(err).Message()}
+}
+
+// CompletionRequest asks llm-gateway to complete Prompt as is, without
+// retrieving documents.
+type CompletionRequest struct {
+	Prompt string
+	UserID string
+	// Optional sampling overrides; nil uses the server defaults
+	Params *llmpb.GenerationParams
+	// BypassCache forces a fresh generation and refreshes the cached entry
+	BypassCache bool
+}
+
+// GenerateCompletion returns the whole completion, from the response cache
+// when an identical request was answered before.
+func (c *LLMClient) GenerateCompletion(ctx context.Context, req *CompletionRequest) (string, error) {
+	var sb strings.Builder
+	err := c.StreamCompletion(ctx, req, func(token string) error {
+		sb.WriteString(token)
+		return nil
+	})
+	return sb.String(), err
+}
+
+// StreamCompletion streams the completion to onToken. Cache hits are replayed
+// in small chunks so callers see the same shape of output either way.
+func (c *LLMClient) StreamCompletion(ctx context.Context, req *CompletionRequest, onToken func(string) error) error {
+	var key string
+	if c.cache != nil {
+		key = ResponseCacheKey{Model: c.model, Prompt: req.Prompt, Params: cacheParams(req.Params)}.String()
+		if !req.BypassCache {
+			cached, found, err := c.cache.Get(ctx, key)
+			if err != nil {
+				log.Printf("LLM response cache lookup failed: %v", err)
+			} else if found {
+				for _, chunk := range chunkCachedResponse(cached, 16) {
+					if err := onToken(chunk); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+		}
+	}
+
 	stream, err := c.client.GenerateStream(ctx, &llmpb.GenerateStreamRequest{
 		Prompt: req.Prompt,
 		UserId: req.UserID,
+		Params: req.Params,
 	})
 	if err != nil {
-		return "", err
+		return err
 	}
-	var result string
+	var result strings.Builder
+	complete := false
 	for {
 		resp, err := stream.Recv()
 		if err != nil {
+			complete = err == io.EOF
+			// Calls refused up front, e.g. over the user's quota or with
+			// every model rate limited, fail here
+			if !complete && result.Len() == 0 {
+				return busyError(err, stream.Trailer())
+			}
 			break
 		}
-		result += resp.GetToken()
+		result.WriteString(resp.GetToken())
+		if err := onToken(resp.GetToken()); err != nil {
+			return err
+		}
+	}
+
+	// Only cache responses that streamed to completion
+	if c.cache != nil && complete && result.Len() > 0 {
+		if err := c.cache.Set(ctx, key, result.String(), c.cacheTTL); err != nil {
+			log.Printf("LLM response cache store failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// AnalyzeILOResult generates the analysis through llm-gateway's ilo_analysis
+// schema, which llm-gateway validates the model's output against. Its JSON is
+// cached like a completion's text.
+func (c *LLMClient) AnalyzeILOResult(ctx context.Context, req *LLMAnalysisRequest) (*IloAnalysis, error) {
+	var key string
+	if c.cache != nil {
+		key = ResponseCacheKey{Model: c.model, Prompt: req.Prompt, Params: map[string]string{"schema": iloAnalysisSchema}}.String()
+		if !req.BypassCache {
+			cached, found, err := c.cache.Get(ctx, key)
+			if err != nil {
+				log.Printf("LLM response cache lookup failed: %v", err)
+			} else if found {
+				analysis, err := ParseIloAnalysis(cached)
+				if err == nil {
+					return analysis, nil
+				}
+				log.Printf("Ignoring cached ILO analysis: %v", err)
+			}
+		}
+	}
+
+	var trailer metadata.MD
+	resp, err := c.client.GenerateStructured(ctx, &llmpb.GenerateStructuredRequest{
+		Prompt: req.Prompt,
+		UserId: req.UserID,
+		Schema: iloAnalysisSchema,
+	}, grpc.Trailer(&trailer))
+	if err != nil {
+		return nil, busyError(err, trailer)
+	}
+	analysis, err := ParseIloAnalysis(resp.GetJson())
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		if err := c.cache.Set(ctx, key, resp.GetJson(), c.cacheTTL); err != nil {
+			log.Printf("LLM response cache store failed: %v", err)
+		}
+	}
+	return analysis, nil
+}
+
+// LLMUsage is a user's token usage in the current month
+type LLMUsage struct {
+	UserID           string `json:"user_id"`
+	Period           string `json:"period"`
+	PromptTokens     int64  `json:"prompt_tokens"`
+	CompletionTokens int64  `json:"completion_tokens"`
+	TotalTokens      int64  `json:"total_tokens"`
+	Requests         int64  `json:"requests"`
+	// Quota is the monthly token quota; 0 is unlimited
+	Quota int64 `json:"quota"`
+	// Remaining is 0 when the quota is unlimited or used up
+	Remaining int64 `json:"remaining"`
+}
+
+// GetUsage returns the user's token usage this month.
+func (c *LLMClient) GetUsage(ctx context.Context, userID string) (*LLMUsage, error) {
+	resp, err := c.client.GetUsage(ctx, &llmpb.GetUsageRequest{UserId: userID})
+	if err != nil {
+		return nil, err
 	}
-	return result, nil
+	return &LLMUsage{
+		UserID:           resp.GetUserId(),
+		Period:           resp.GetPeriod(),
+		PromptTokens:     resp.GetPromptTokens(),
+		CompletionTokens: resp.GetCompletionTokens(),
+		TotalTokens:      resp.GetTotalTokens(),
+		Requests:         resp.GetRequests(),
+		Quota:            resp.GetQuota(),
+		Remaining:        resp.GetRemaining(),
+	}, nil
 }
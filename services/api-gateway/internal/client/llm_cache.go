@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	llmpb "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/redis/go-redis/v9"
+)
+
+// ResponseCache stores completed LLM responses so identical requests can be
+// answered without re-running generation.
+type ResponseCache interface {
+	Get(ctx context.Context, key string) (string, bool, error)
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+}
+
+// RedisResponseCache is a Redis-backed ResponseCache
+type RedisResponseCache struct {
+	client *redis.Client
+}
+
+func NewRedisResponseCache(client *redis.Client) *RedisResponseCache {
+	return &RedisResponseCache{client: client}
+}
+
+func (c *RedisResponseCache) Get(ctx context.Context, key string) (string, bool, error) {
+	value, err := c.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (c *RedisResponseCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// ResponseCacheKey identifies a cacheable LLM request. Only plain completions
+// are cached: a RAG answer depends on the documents retrieved inside
+// llm-gateway, which the key cannot capture.
+type ResponseCacheKey struct {
+	Model  string
+	Prompt string
+	Params map[string]string
+}
+
+// String returns the Redis key for the request, a SHA-256 over all fields
+func (k ResponseCacheKey) String() string {
+	h := sha256.New()
+	h.Write([]byte(k.Model))
+	h.Write([]byte{0})
+	h.Write([]byte(k.Prompt))
+
+	// Sort params so map iteration order doesn't change the key
+	names := make([]string, 0, len(k.Params))
+	for name := range k.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		h.Write([]byte{0})
+		h.Write([]byte(name + "=" + k.Params[name]))
+	}
+
+	return "llm_cache:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheParams returns the sampling overrides that change the response, keyed
+// by name, for ResponseCacheKey.Params.
+func cacheParams(p *llmpb.GenerationParams) map[string]string {
+	if p == nil {
+		return nil
+	}
+	params := make(map[string]string)
+	float := func(name string, value *float32) {
+		if value != nil {
+			params[name] = strconv.FormatFloat(float64(*value), 'g', -1, 32)
+		}
+	}
+	float("temperature", p.Temperature)
+	float("top_p", p.TopP)
+	float("presence_penalty", p.PresencePenalty)
+	float("frequency_penalty", p.FrequencyPenalty)
+	if len(p.GetStop()) > 0 {
+		params["stop"] = strings.Join(p.GetStop(), "\x00")
+	}
+	return params
+}
+
+// chunkCachedResponse splits a cached response into token-sized pieces so a
+// cache hit can be replayed through the same streaming callback as a live
+// generation.
+func chunkCachedResponse(text string, size int) []string {
+	if size <= 0 {
+		size = 16
+	}
+	runes := []rune(text)
+	chunks := make([]string, 0, len(runes)/size+1)
+	for start := 0; start < len(runes); start += size {
+		end := start + size
+		if end > len(runes) {
+			end = len(runes)
+		}
+		chunks = append(chunks, string(runes[start:end]))
+	}
+	return chunks
+}
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	llmpb "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// memoryResponseCache is an in-memory ResponseCache for tests
+type memoryResponseCache struct {
+	entries map[string]string
+}
+
+func newMemoryResponseCache() *memoryResponseCache {
+	return &memoryResponseCache{entries: make(map[string]string)}
+}
+
+func (c *memoryResponseCache) Get(ctx context.Context, key string) (string, bool, error) {
+	value, found := c.entries[key]
+	return value, found, nil
+}
+
+func (c *memoryResponseCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	c.entries[key] = value
+	return nil
+}
+
+// fakeLLMServiceClient counts GenerateStream calls and streams fixed tokens
+type fakeLLMServiceClient struct {
+	llmpb.LLMServiceClient
+	tokens []string
+	calls  int
+	last   *llmpb.GenerateStreamRequest
+}
+
+func (f *fakeLLMServiceClient) GenerateStream(ctx context.Context, in *llmpb.GenerateStreamRequest, opts ...grpc.CallOption) (llmpb.LLMService_GenerateStreamClient, error) {
+	f.calls++
+	f.last = in
+	return &fakeGenerateStream{tokens: f.tokens}, nil
+}
+
+type fakeGenerateStream struct {
+	grpc.ClientStream
+	tokens []string
+}
+
+func (s *fakeGenerateStream) Recv() (*llmpb.GenerateStreamResponse, error) {
+	if len(s.tokens) == 0 {
+		return nil, io.EOF
+	}
+	token := s.tokens[0]
+	s.tokens = s.tokens[1:]
+	return &llmpb.GenerateStreamResponse{Token: token}, nil
+}
+
+func TestGenerateCompletion_ResponseCache(t *testing.T) {
+	newClient := func() (*LLMClient, *fakeLLMServiceClient) {
+		fake := &fakeLLMServiceClient{tokens: []string{"Hello", ", ", "world"}}
+		c := &LLMClient{client: fake}
+		c.EnableResponseCache(newMemoryResponseCache(), time.Hour, "gpt-4o")
+		return c, fake
+	}
+	temperature := func(v float32) *llmpb.GenerationParams {
+		return &llmpb.GenerationParams{Temperature: &v}
+	}
+
+	t.Run("miss then hit", func(t *testing.T) {
+		c, fake := newClient()
+		req := &CompletionRequest{Prompt: "q", UserID: "u1", Params: temperature(0.2)}
+
+		first, err := c.GenerateCompletion(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello, world", first)
+		assert.Equal(t, float32(0.2), fake.last.GetParams().GetTemperature(), "params reach llm-gateway")
+
+		second, err := c.GenerateCompletion(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello, world", second)
+		assert.Equal(t, 1, fake.calls)
+	})
+
+	t.Run("different prompt misses", func(t *testing.T) {
+		c, fake := newClient()
+		_, _ = c.GenerateCompletion(context.Background(), &CompletionRequest{Prompt: "a"})
+		fake.tokens = []string{"other"}
+		result, err := c.GenerateCompletion(context.Background(), &CompletionRequest{Prompt: "b"})
+		assert.NoError(t, err)
+		assert.Equal(t, "other", result)
+		assert.Equal(t, 2, fake.calls)
+	})
+
+	t.Run("different params miss", func(t *testing.T) {
+		c, fake := newClient()
+		_, _ = c.GenerateCompletion(context.Background(), &CompletionRequest{Prompt: "q", Params: temperature(0.2)})
+		fake.tokens = []string{"other"}
+		result, err := c.GenerateCompletion(context.Background(), &CompletionRequest{Prompt: "q", Params: temperature(0.9)})
+		assert.NoError(t, err)
+		assert.Equal(t, "other", result)
+		assert.Equal(t, 2, fake.calls)
+	})
+
+	t.Run("bypass flag regenerates", func(t *testing.T) {
+		c, fake := newClient()
+		req := &CompletionRequest{Prompt: "q"}
+		_, _ = c.GenerateCompletion(context.Background(), req)
+
+		fake.tokens = []string{"fresh"}
+		req.BypassCache = true
+		result, err := c.GenerateCompletion(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "fresh", result)
+		assert.Equal(t, 2, fake.calls)
+
+		// The bypassed generation refreshes the cached entry
+		req.BypassCache = false
+		result, err = c.GenerateCompletion(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "fresh", result)
+		assert.Equal(t, 2, fake.calls)
+	})
+
+	t.Run("cache hit is replayed in chunks", func(t *testing.T) {
+		c, _ := newClient()
+		req := &CompletionRequest{Prompt: "q"}
+		_, _ = c.GenerateCompletion(context.Background(), req)
+
+		var chunks []string
+		err := c.StreamCompletion(context.Background(), req, func(token string) error {
+			chunks = append(chunks, token)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Hello, world"}, chunks)
+	})
+
+	t.Run("long cache hit is replayed in several chunks", func(t *testing.T) {
+		c, fake := newClient()
+		// 41 runes, many of them multi-byte
+		fake.tokens = []string{"Bạn hợp với ngành Công nghệ thông tin nhé"}
+		req := &CompletionRequest{Prompt: "q"}
+		_, _ = c.GenerateCompletion(context.Background(), req)
+
+		var chunks []string
+		err := c.StreamCompletion(context.Background(), req, func(token string) error {
+			chunks = append(chunks, token)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Bạn hợp với ngàn", "h Công nghệ thôn", "g tin nhé"}, chunks)
+		assert.Equal(t, 1, fake.calls)
+	})
+}
+
+// fakeStructuredClient counts GenerateStructured calls and answers with a
+// fixed JSON document
+type fakeStructuredClient struct {
+	llmpb.LLMServiceClient
+	json  string
+	calls int
+	last  *llmpb.GenerateStructuredRequest
+}
+
+func (f *fakeStructuredClient) GenerateStructured(ctx context.Context, in *llmpb.GenerateStructuredRequest, opts ...grpc.CallOption) (*llmpb.GenerateStructuredResponse, error) {
+	f.calls++
+	f.last = in
+	return &llmpb.GenerateStructuredResponse{Json: f.json, Method: "tool_call"}, nil
+}
+
+func TestAnalyzeILOResult_ResponseCache(t *testing.T) {
+	const analysisJSON = `{"overview":"Thiên về logic","strengths":["Giải toán"],"development_areas":["Thuyết trình"],` +
+		`"careers":[{"career_field":"Khoa học dữ liệu","match_percent":85,"rationale":"Hợp với tư duy logic","domain_codes":["LOGIC"]}],` +
+		`"next_steps":["Học Python"]}`
+	newClient := func() (*LLMClient, *fakeStructuredClient) {
+		fake := &fakeStructuredClient{json: analysisJSON}
+		c := &LLMClient{client: fake}
+		c.EnableResponseCache(newMemoryResponseCache(), time.Hour, "gpt-4o")
+		return c, fake
+	}
+
+	t.Run("miss then hit", func(t *testing.T) {
+		c, fake := newClient()
+		req := &LLMAnalysisRequest{Prompt: "analyse", UserID: "u1"}
+
+		first, err := c.AnalyzeILOResult(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "ilo_analysis", fake.last.GetSchema())
+		assert.Equal(t, "u1", fake.last.GetUserId())
+		assert.Equal(t, "Thiên về logic", first.Overview)
+		assert.Equal(t, []CareerSuggestion{{CareerField: "Khoa học dữ liệu", MatchPercent: 85, Rationale: "Hợp với tư duy logic", DomainCodes: []string{"LOGIC"}}}, first.Careers)
+
+		second, err := c.AnalyzeILOResult(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, fake.calls)
+	})
+
+	t.Run("bypass flag regenerates", func(t *testing.T) {
+		c, fake := newClient()
+		req := &LLMAnalysisRequest{Prompt: "analyse"}
+		_, _ = c.AnalyzeILOResult(context.Background(), req)
+
+		fake.json = `{"overview":"Mới"}`
+		req.BypassCache = true
+		analysis, err := c.AnalyzeILOResult(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "Mới", analysis.Overview)
+		assert.Equal(t, 2, fake.calls)
+	})
+
+	t.Run("a completion of the same prompt is cached apart", func(t *testing.T) {
+		c, fake := newClient()
+		_, _ = c.AnalyzeILOResult(context.Background(), &LLMAnalysisRequest{Prompt: "analyse"})
+		cache := c.cache.(*memoryResponseCache)
+		assert.Len(t, cache.entries, 1)
+		_, found, _ := cache.Get(context.Background(), ResponseCacheKey{Model: "gpt-4o", Prompt: "analyse"}.String())
+		assert.False(t, found)
+		assert.Equal(t, 1, fake.calls)
+	})
+}
+
+func TestResponseCacheKey(t *testing.T) {
+	base := ResponseCacheKey{Model: "gpt-4o", Prompt: "q", Params: map[string]string{"temperature": "0.7", "top_p": "1"}}
+	same := ResponseCacheKey{Model: "gpt-4o", Prompt: "q", Params: map[string]string{"top_p": "1", "temperature": "0.7"}}
+	assert.Equal(t, base.String(), same.String())
+
+	otherParams := base
+	otherParams.Params = map[string]string{"temperature": "0.2", "top_p": "1"}
+	assert.NotEqual(t, base.String(), otherParams.String())
+
+	otherModel := base
+	otherModel.Model = "gpt-4o-mini"
+	assert.NotEqual(t, base.String(), otherModel.String())
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -13,7 +16,11 @@ type Config struct {
 	Ilo       IloConfig       `mapstructure:"ilo"`
 	LLM       LLMConfig       `mapstructure:"llm"`
 	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Share     ShareConfig     `mapstructure:"share"`
 	Tracing   TracingConfig   `mapstructure:"tracing"`
+	Logging   LoggingConfig   `mapstructure:"logging"`
+
+	GRPCClient GRPCClientConfig `mapstructure:"grpc_client"`
 }
 
 type ServerConfig struct {
@@ -21,6 +28,22 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	// RouteTimeouts bound how long a request may spend in its handlers, per
+	// route group; zero leaves the group untimed
+	RouteTimeouts RouteTimeoutsConfig `mapstructure:"route_timeouts"`
+	// ReadOnly starts the gateway in maintenance mode, rejecting writes; it
+	// can be flipped at runtime through /admin/read-only
+	ReadOnly bool `mapstructure:"read_only"`
+	// DefaultLanguage ("vi" or "en") is used for user-facing strings when a
+	// request's Accept-Language names no supported language; empty means "vi"
+	DefaultLanguage string `mapstructure:"default_language"`
+}
+
+type RouteTimeoutsConfig struct {
+	Auth    time.Duration `mapstructure:"auth"`
+	User    time.Duration `mapstructure:"user"`
+	Profile time.Duration `mapstructure:"profile"`
+	Ilo     time.Duration `mapstructure:"ilo"`
 }
 
 type AuthConfig struct {
@@ -28,24 +51,97 @@ type AuthConfig struct {
 	JWTSecret       string        `mapstructure:"jwt_secret"`
 	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
 	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+	// TokenCache is where validated tokens are cached: "memory" (the
+	// default), per gateway instance, or "redis", shared between instances
+	TokenCache string `mapstructure:"token_cache"`
+	// TokenCacheTTL is how long a validated token is trusted without asking
+	// auth-core again; zero means 5m
+	TokenCacheTTL time.Duration `mapstructure:"token_cache_ttl"`
 }
 
 type ChatConfig struct {
 	ServiceAddr string `mapstructure:"service_addr"`
+	// TokenBatchWindow coalesces the assistant tokens arriving within it
+	// into one WebSocket message; zero sends every token on its own
+	TokenBatchWindow time.Duration `mapstructure:"token_batch_window"`
+	// OutboundBuffer is how many messages may wait for a WebSocket client
+	// that reads slowly; zero means 256
+	OutboundBuffer int `mapstructure:"outbound_buffer"`
+	// OverflowPolicy is what happens to a message for a client whose buffer
+	// is full: "close" (the default) disconnects the client, "drop" discards
+	// the message
+	OverflowPolicy string `mapstructure:"overflow_policy"`
 }
 
 type IloConfig struct {
 	ServiceAddr string `mapstructure:"service_addr"`
+	// ChatContext lets users share a conversation with their ILO analysis
+	ChatContext IloChatContextConfig `mapstructure:"chat_context"`
+}
+
+type IloChatContextConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxChars bounds the conversation summary added to the analysis prompt
+	MaxChars int `mapstructure:"max_chars"`
 }
 
 type LLMConfig struct {
-	ServiceAddr string `mapstructure:"service_addr"`
+	ServiceAddr string         `mapstructure:"service_addr"`
+	Model       string         `mapstructure:"model"`
+	Cache       LLMCacheConfig `mapstructure:"cache"`
+}
+
+type LLMCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	TTL     time.Duration `mapstructure:"ttl"`
 }
 
 type RateLimitConfig struct {
 	Enabled           bool   `mapstructure:"enabled"`
 	RequestsPerMinute int    `mapstructure:"requests_per_minute"`
 	RedisAddr         string `mapstructure:"redis_addr"`
+	// Allowlist holds CIDRs (or bare IPs) that are never rate limited
+	Allowlist []string `mapstructure:"allowlist"`
+	// InternalSecret, when set, lets callers bypass the limiter by sending it
+	// in the X-Internal-Secret header
+	InternalSecret string `mapstructure:"internal_secret"`
+	// FailureMode is what happens to requests while Redis is failing: "open"
+	// (the default) lets them through, "closed" rejects them with 503, and
+	// "local" limits them in memory per gateway instance
+	FailureMode string `mapstructure:"failure_mode"`
+}
+
+// ShareConfig controls the read-only links that let users share a
+// conversation with someone who has no account.
+type ShareConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// DefaultTTL is how long a share lasts when the user asks for no
+	// particular expiry; zero means 7 days
+	DefaultTTL time.Duration `mapstructure:"default_ttl"`
+	// MaxTTL caps the expiry a user may ask for; zero means 30 days
+	MaxTTL time.Duration `mapstructure:"max_ttl"`
+	// Secret signs the share tokens; every gateway instance needs the same
+	// one, and changing it ends every link. Required when sharing is enabled
+	Secret string `mapstructure:"secret"`
+	// Store is where revoked links are kept: "memory" (the default), per
+	// gateway instance, so a revoked link keeps working on the others, or
+	// "redis", shared between instances
+	Store string `mapstructure:"store"`
+}
+
+// GRPCClientConfig is how the backend clients find and pick replicas.
+type GRPCClientConfig struct {
+	// ResolverScheme resolves the service addresses: "dns" (the default)
+	// finds every replica behind a headless service, "passthrough" hands
+	// the address to the dialer as is
+	ResolverScheme string `mapstructure:"resolver_scheme"`
+	// LoadBalancingPolicy picks a replica per call: "round_robin" (the
+	// default) spreads calls, "pick_first" sticks to one
+	LoadBalancingPolicy string `mapstructure:"load_balancing_policy"`
+	// MaxRecvMsgSize is the largest response accepted from a backend, in
+	// bytes; 0 means grpcclient.DefaultMaxRecvMsgSize, the backends' own
+	// default limit
+	MaxRecvMsgSize int `mapstructure:"max_recv_msg_size"`
 }
 
 type TracingConfig struct {
@@ -55,6 +151,20 @@ type TracingConfig struct {
 	Insecure    bool   `mapstructure:"insecure"`
 }
 
+type LoggingConfig struct {
+	// Format is the access log format (Fiber logger tags, plus ${clientType}
+	// and ${appVersion}); empty uses middleware.DefaultLogFormat
+	Format string `mapstructure:"format"`
+	// RedactHeaders and RedactQueryParams are logged as [REDACTED], on top of
+	// Authorization, Cookie and the token parameters which always are
+	RedactHeaders     []string `mapstructure:"redact_headers"`
+	RedactQueryParams []string `mapstructure:"redact_query_params"`
+	// MaxBodyLength truncates logged bodies and prompts; zero logs them whole
+	MaxBodyLength int `mapstructure:"max_body_length"`
+	// HashBodies logs a digest instead of bodies and prompts
+	HashBodies bool `mapstructure:"hash_bodies"`
+}
+
 func LoadConfig(path string) (*Config, error) {
 	viper.SetConfigFile(path)
 	viper.AutomaticEnv()
@@ -68,5 +178,121 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return &config, nil
 }
+
+// Validate reports every missing or invalid field at once so a bad
+// deployment fails on startup with the full list.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	switch c.Server.DefaultLanguage {
+	case "", "vi", "en":
+	default:
+		errs = append(errs, fmt.Errorf("server.default_language must be \"vi\" or \"en\", got %q", c.Server.DefaultLanguage))
+	}
+	for _, addr := range []struct{ key, value string }{
+		{"auth.service_addr", c.Auth.ServiceAddr},
+		{"chat.service_addr", c.Chat.ServiceAddr},
+		{"ilo.service_addr", c.Ilo.ServiceAddr},
+		{"llm.service_addr", c.LLM.ServiceAddr},
+	} {
+		if addr.value == "" {
+			errs = append(errs, fmt.Errorf("%s is required", addr.key))
+		}
+	}
+	switch c.Auth.TokenCache {
+	case "", "memory", "redis":
+	default:
+		errs = append(errs, fmt.Errorf("auth.token_cache must be \"memory\" or \"redis\", got %q", c.Auth.TokenCache))
+	}
+	if c.Chat.OutboundBuffer < 0 {
+		errs = append(errs, errors.New("chat.outbound_buffer must not be negative"))
+	}
+	switch c.Chat.OverflowPolicy {
+	case "", "close", "drop":
+	default:
+		errs = append(errs, fmt.Errorf("chat.overflow_policy must be \"close\" or \"drop\", got %q", c.Chat.OverflowPolicy))
+	}
+	if c.Auth.TokenCacheTTL < 0 {
+		errs = append(errs, errors.New("auth.token_cache_ttl must not be negative"))
+	}
+	if c.Ilo.ChatContext.Enabled && c.Ilo.ChatContext.MaxChars <= 0 {
+		errs = append(errs, errors.New("ilo.chat_context.max_chars must be positive when chat context is enabled"))
+	}
+	if c.LLM.Cache.Enabled && c.LLM.Cache.TTL <= 0 {
+		errs = append(errs, errors.New("llm.cache.ttl must be positive when the cache is enabled"))
+	}
+	if c.RateLimit.Enabled && c.RateLimit.RequestsPerMinute <= 0 {
+		errs = append(errs, errors.New("rate_limit.requests_per_minute must be positive when rate limiting is enabled"))
+	}
+	switch c.RateLimit.FailureMode {
+	case "", "open", "closed", "local":
+	default:
+		errs = append(errs, fmt.Errorf("rate_limit.failure_mode must be \"open\", \"closed\" or \"local\", got %q", c.RateLimit.FailureMode))
+	}
+	if c.Share.DefaultTTL < 0 || c.Share.MaxTTL < 0 {
+		errs = append(errs, errors.New("share.default_ttl and share.max_ttl must not be negative"))
+	} else if c.Share.DefaultTTL > 0 && c.Share.MaxTTL > 0 && c.Share.DefaultTTL > c.Share.MaxTTL {
+		errs = append(errs, fmt.Errorf("share.default_ttl (%s) must not exceed share.max_ttl (%s)", c.Share.DefaultTTL, c.Share.MaxTTL))
+	}
+	if c.Share.Enabled && c.Share.Secret == "" {
+		errs = append(errs, errors.New("share.secret is required when sharing is enabled"))
+	}
+	switch c.Share.Store {
+	case "", "memory", "redis":
+	default:
+		errs = append(errs, fmt.Errorf("share.store must be \"memory\" or \"redis\", got %q", c.Share.Store))
+	}
+	switch c.GRPCClient.ResolverScheme {
+	case "", "dns", "passthrough":
+	default:
+		errs = append(errs, fmt.Errorf("grpc_client.resolver_scheme must be \"dns\" or \"passthrough\", got %q", c.GRPCClient.ResolverScheme))
+	}
+	switch c.GRPCClient.LoadBalancingPolicy {
+	case "", "round_robin", "pick_first":
+	default:
+		errs = append(errs, fmt.Errorf("grpc_client.load_balancing_policy must be \"round_robin\" or \"pick_first\", got %q", c.GRPCClient.LoadBalancingPolicy))
+	}
+	if c.GRPCClient.MaxRecvMsgSize < 0 {
+		errs = append(errs, errors.New("grpc_client.max_recv_msg_size must not be negative"))
+	}
+	if c.RateLimit.RedisAddr == "" {
+		var users []string
+		if c.RateLimit.Enabled {
+			users = append(users, "rate limiting")
+		}
+		if c.LLM.Cache.Enabled {
+			users = append(users, "the LLM cache")
+		}
+		if c.Auth.TokenCache == "redis" {
+			users = append(users, "the redis token cache")
+		}
+		if c.Share.Enabled && c.Share.Store == "redis" {
+			users = append(users, "the redis share store")
+		}
+		if len(users) > 0 {
+			errs = append(errs, fmt.Errorf("rate_limit.redis_addr is required by %s", strings.Join(users, ", ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Warnings describes optional settings that are missing, and what the
+// gateway does without them.
+func (c *Config) Warnings() []string {
+	var warnings []string
+	if c.LLM.Cache.Enabled && c.LLM.Model == "" {
+		warnings = append(warnings, "llm.model is not set: cached LLM responses are not tied to a model, so changing it serves stale answers")
+	}
+	if !c.RateLimit.Enabled {
+		warnings = append(warnings, "rate_limit.enabled is false: requests are not rate limited")
+	}
+	return warnings
+}
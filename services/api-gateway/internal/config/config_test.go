@@ -0,0 +1,162 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validConfig has every required setting.
+const validConfig = `
+server:
+  port: 8080
+auth:
+  service_addr: "auth-core:9091"
+chat:
+  service_addr: "chat-gateway:8082"
+ilo:
+  service_addr: "auth-core:9091"
+llm:
+  service_addr: "llm-gateway-py:50054"
+  model: "gpt-4o"
+  cache:
+    enabled: true
+    ttl: 24h
+rate_limit:
+  enabled: true
+  requests_per_minute: 100
+  redis_addr: "redis:6379"
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig_Valid(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, validConfig))
+	require.NoError(t, err)
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Empty(t, cfg.Warnings())
+}
+
+func TestLoadConfig_ReportsEveryProblem(t *testing.T) {
+	_, err := LoadConfig(writeConfig(t, `
+server:
+  port: 0
+auth:
+  token_cache: "redis"
+llm:
+  cache:
+    enabled: true
+rate_limit:
+  enabled: true
+  failure_mode: "sometimes"
+`))
+	require.Error(t, err)
+	assert.Equal(t, `invalid config: server.port must be between 1 and 65535, got 0
+auth.service_addr is required
+chat.service_addr is required
+ilo.service_addr is required
+llm.service_addr is required
+llm.cache.ttl must be positive when the cache is enabled
+rate_limit.requests_per_minute must be positive when rate limiting is enabled
+rate_limit.failure_mode must be "open", "closed" or "local", got "sometimes"
+rate_limit.redis_addr is required by rate limiting, the LLM cache, the redis token cache`, err.Error())
+}
+
+func TestValidate(t *testing.T) {
+	base := func() *Config {
+		cfg, err := LoadConfig(writeConfig(t, validConfig))
+		require.NoError(t, err)
+		return cfg
+	}
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{
+			name:    "unsupported language",
+			modify:  func(c *Config) { c.Server.DefaultLanguage = "fr" },
+			wantErr: "server.default_language",
+		},
+		{
+			name:    "unknown token cache",
+			modify:  func(c *Config) { c.Auth.TokenCache = "memcached" },
+			wantErr: "auth.token_cache",
+		},
+		{
+			name: "share default longer than the maximum",
+			modify: func(c *Config) {
+				c.Share.DefaultTTL = 48 * time.Hour
+				c.Share.MaxTTL = 24 * time.Hour
+			},
+			wantErr: "share.default_ttl (48h0m0s) must not exceed share.max_ttl (24h0m0s)",
+		},
+		{
+			name:    "sharing without a secret",
+			modify:  func(c *Config) { c.Share.Enabled = true },
+			wantErr: "share.secret is required",
+		},
+		{
+			name:    "unknown share store",
+			modify:  func(c *Config) { c.Share.Store = "disk" },
+			wantErr: "share.store",
+		},
+		{
+			name:    "negative outbound buffer",
+			modify:  func(c *Config) { c.Chat.OutboundBuffer = -1 },
+			wantErr: "chat.outbound_buffer",
+		},
+		{
+			name:    "unknown overflow policy",
+			modify:  func(c *Config) { c.Chat.OverflowPolicy = "block" },
+			wantErr: "chat.overflow_policy",
+		},
+		{
+			name:    "unknown resolver scheme",
+			modify:  func(c *Config) { c.GRPCClient.ResolverScheme = "xds" },
+			wantErr: "grpc_client.resolver_scheme",
+		},
+		{
+			name:    "unknown load balancing policy",
+			modify:  func(c *Config) { c.GRPCClient.LoadBalancingPolicy = "least_request" },
+			wantErr: "grpc_client.load_balancing_policy",
+		},
+		{
+			name:    "negative max receive size",
+			modify:  func(c *Config) { c.GRPCClient.MaxRecvMsgSize = -1 },
+			wantErr: "grpc_client.max_recv_msg_size",
+		},
+		{
+			name: "chat context without a size",
+			modify: func(c *Config) {
+				c.Ilo.ChatContext.Enabled = true
+				c.Ilo.ChatContext.MaxChars = 0
+			},
+			wantErr: "ilo.chat_context.max_chars",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base()
+			tt.modify(cfg)
+			assert.ErrorContains(t, cfg.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	cfg := &Config{LLM: LLMConfig{Cache: LLMCacheConfig{Enabled: true}}}
+	assert.Equal(t, []string{
+		"llm.model is not set: cached LLM responses are not tied to a model, so changing it serves stale answers",
+		"rate_limit.enabled is false: requests are not rate limited",
+	}, cfg.Warnings())
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errConnectionClosed is returned by sends after the connection is closed.
+var errConnectionClosed = errors.New("websocket connection closed")
+
+// tokenBatcher writes server messages to a WebSocket client, coalescing the
+// assistant tokens that arrive within window of the first pending one into
+// a single message. Other messages flush the pending tokens first, so order
+// is kept. Writes are serialized, so it may be shared by goroutines.
+type tokenBatcher struct {
+	mu      sync.Mutex
+	write   func(ServerMessage) error
+	window  time.Duration
+	pending strings.Builder
+	timer   *time.Timer
+	// err is the first failed write; later sends return it
+	err error
+}
+
+// newTokenBatcher batches tokens written through write; a window of zero
+// or less sends every token as it comes.
+func newTokenBatcher(write func(ServerMessage) error, window time.Duration) *tokenBatcher {
+	return &tokenBatcher{write: write, window: window}
+}
+
+// Send writes msg, holding assistant tokens back for up to the window.
+func (b *tokenBatcher) Send(msg ServerMessage) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return b.err
+	}
+	if msg.Type == "assistant_token" && b.window > 0 {
+		b.pending.WriteString(msg.Token)
+		if b.timer == nil {
+			b.timer = time.AfterFunc(b.window, b.timedFlush)
+		}
+		return nil
+	}
+	if err := b.flushLocked(); err != nil {
+		return err
+	}
+	if err := b.write(msg); err != nil {
+		b.err = err
+	}
+	return b.err
+}
+
+// Flush writes the pending tokens, if any.
+func (b *tokenBatcher) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return b.err
+	}
+	return b.flushLocked()
+}
+
+// Close drops the pending tokens and fails later sends, so nothing is
+// written once the connection is gone.
+func (b *tokenBatcher) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.pending.Reset()
+	b.err = errConnectionClosed
+}
+
+func (b *tokenBatcher) timedFlush() {
+	// A failure is kept in b.err for the next Send to report
+	_ = b.Flush()
+}
+
+func (b *tokenBatcher) flushLocked() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if b.pending.Len() == 0 {
+		return nil
+	}
+	token := b.pending.String()
+	b.pending.Reset()
+	if err := b.write(ServerMessage{Type: "assistant_token", Token: token}); err != nil {
+		b.err = err
+	}
+	return b.err
+}
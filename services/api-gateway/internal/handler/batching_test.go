@@ -0,0 +1,113 @@
+package handler_test
+
+import (
+	"testing"
+	"time"
+
+	chatpb "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedReply is sent after waiting delay
+type scriptedReply struct {
+	delay time.Duration
+	reply *chatpb.StreamResponse
+}
+
+// scriptedChatServer answers the first stream request with its script, then
+// either ends the stream or keeps it open
+type scriptedChatServer struct {
+	chatpb.UnimplementedConversationServiceServer
+	script   []scriptedReply
+	keepOpen bool
+}
+
+func (s *scriptedChatServer) Stream(stream chatpb.ConversationService_StreamServer) error {
+	if _, err := stream.Recv(); err != nil {
+		return err
+	}
+	for _, r := range s.script {
+		time.Sleep(r.delay)
+		if err := stream.Send(r.reply); err != nil {
+			return err
+		}
+	}
+	if s.keepOpen {
+		<-stream.Context().Done()
+	}
+	return nil
+}
+
+func token(delay time.Duration, text string) scriptedReply {
+	return scriptedReply{delay, &chatpb.StreamResponse{
+		Type:    "assistant_token",
+		Content: &chatpb.StreamResponse_Token{Token: text},
+	}}
+}
+
+func batchTokens(window time.Duration) func(*handler.Handler) {
+	return func(h *handler.Handler) { h.EnableTokenBatching(window) }
+}
+
+func TestWebSocketProxy_TokenBatching(t *testing.T) {
+	userMsg := handler.ClientMessage{Type: "user_msg", ConversationID: "conv-1", Text: "Hello"}
+
+	t.Run("rapid tokens are sent as one message", func(t *testing.T) {
+		ws := dialChat(t, &scriptedChatServer{
+			script:   []scriptedReply{token(0, "Xin "), token(0, "chào "), token(0, "bạn")},
+			keepOpen: true,
+		}, batchTokens(200*time.Millisecond))
+		require.NoError(t, ws.WriteJSON(userMsg))
+
+		msg := readServerMessage(t, ws)
+		assert.Equal(t, handler.ServerMessage{Type: "assistant_token", Token: "Xin chào bạn"}, msg)
+	})
+
+	t.Run("a slow trickle is sent token by token", func(t *testing.T) {
+		ws := dialChat(t, &scriptedChatServer{
+			script:   []scriptedReply{token(0, "one"), token(150*time.Millisecond, "two"), token(150*time.Millisecond, "three")},
+			keepOpen: true,
+		}, batchTokens(20*time.Millisecond))
+		require.NoError(t, ws.WriteJSON(userMsg))
+
+		for _, want := range []string{"one", "two", "three"} {
+			assert.Equal(t, want, readServerMessage(t, ws).Token)
+		}
+	})
+
+	t.Run("pending tokens are flushed before other messages", func(t *testing.T) {
+		ws := dialChat(t, &scriptedChatServer{
+			script: []scriptedReply{token(0, "a"), token(0, "b"), {0, &chatpb.StreamResponse{
+				Type:    "status",
+				Content: &chatpb.StreamResponse_Status{Status: "generating"},
+			}}},
+			keepOpen: true,
+		}, batchTokens(time.Minute))
+		require.NoError(t, ws.WriteJSON(userMsg))
+
+		assert.Equal(t, "ab", readServerMessage(t, ws).Token)
+		assert.Equal(t, "generating", readServerMessage(t, ws).Status)
+	})
+
+	t.Run("pending tokens are flushed when the stream ends", func(t *testing.T) {
+		ws := dialChat(t, &scriptedChatServer{
+			script: []scriptedReply{token(0, "last "), token(0, "words")},
+		}, batchTokens(time.Minute))
+		require.NoError(t, ws.WriteJSON(userMsg))
+
+		assert.Equal(t, "last words", readServerMessage(t, ws).Token)
+	})
+
+	t.Run("tokens are sent one by one without batching", func(t *testing.T) {
+		ws := dialChat(t, &scriptedChatServer{
+			script:   []scriptedReply{token(0, "a"), token(0, "b")},
+			keepOpen: true,
+		})
+		require.NoError(t, ws.WriteJSON(userMsg))
+
+		assert.Equal(t, "a", readServerMessage(t, ws).Token)
+		assert.Equal(t, "b", readServerMessage(t, ws).Token)
+	})
+}
@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/careerup-Inc/careerup-monorepo/pkg/errs"
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	utils "github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/metadata"
+)
+
+// exportFormatMarkdown is the only transcript format supported so far.
+const exportFormatMarkdown = "md"
+
+// @Summary Export a conversation
+// @Description Download the transcript of one of the authenticated user's conversations
+// @Tags conversations
+// @Produce text/markdown
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Param format query string false "Export format (md)"
+// @Success 200 {string} string "Markdown transcript"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/conversations/{id}/export [get]
+func (h *Handler) HandleExportConversation(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*client.User)
+	if !ok || user == nil {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "User not found in context (middleware issue?)")
+	}
+
+	conversationID := c.Params("id")
+	if conversationID == "" {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Missing conversation ID")
+	}
+	format := c.Query("format", exportFormatMarkdown)
+	if format != exportFormatMarkdown {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Unsupported export format: "+format)
+	}
+
+	// chat-gateway checks ownership against the propagated user ID
+	ctx := metadata.AppendToOutgoingContext(c.UserContext(), "user-id", user.ID)
+	conv, err := h.chatClient.GetConversation(ctx, conversationID)
+	if err != nil {
+		return sendConversationError(c, err, "Failed to load conversation")
+	}
+	if conv.GetUserId() != user.ID {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, "You don't have permission to access this conversation")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/markdown; charset=utf-8")
+	c.Attachment(fmt.Sprintf("conversation-%s.md", conversationID))
+	return c.Status(fiber.StatusOK).SendString(renderConversationMarkdown(conv))
+}
+
+// @Summary List conversations
+// @Description List one page of the authenticated user's conversations, most recently active first. Archived conversations are left out unless include_archived is set
+// @Tags conversations
+// @Produce json
+// @Security BearerAuth
+// @Param include_archived query bool false "Include archived conversations"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Conversations to skip"
+// @Param page_token query string false "pagination.next of the previous page; overrides offset"
+// @Success 200 {object} ConversationsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/conversations [get]
+func (h *Handler) HandleListConversations(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*client.User)
+	if !ok || user == nil {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "User not found in context (middleware issue?)")
+	}
+	limit, offset, err := utils.PageParams(c)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx := metadata.AppendToOutgoingContext(c.UserContext(), "user-id", user.ID)
+	infos, err := h.chatClient.ListConversations(ctx, c.QueryBool("include_archived", false))
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to list conversations: "+err.Error())
+	}
+	conversations := make([]ConversationInfo, 0, len(infos))
+	for _, info := range infos {
+		conversations = append(conversations, conversationInfo(info))
+	}
+	page := utils.Paginate(conversations, limit, offset)
+	return c.Status(fiber.StatusOK).JSON(ConversationsResponse{ListResponse: page, Conversations: page.Data})
+}
+
+// @Summary Archive a conversation
+// @Description Hide one of the authenticated user's conversations from the default list. It stays readable by ID
+// @Tags conversations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Success 200 {object} ConversationInfo
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/conversations/{id}/archive [post]
+func (h *Handler) HandleArchiveConversation(c *fiber.Ctx) error {
+	return h.setConversationArchived(c, true)
+}
+
+// @Summary Unarchive a conversation
+// @Description Return one of the authenticated user's archived conversations to the default list
+// @Tags conversations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Success 200 {object} ConversationInfo
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/conversations/{id}/unarchive [post]
+func (h *Handler) HandleUnarchiveConversation(c *fiber.Ctx) error {
+	return h.setConversationArchived(c, false)
+}
+
+func (h *Handler) setConversationArchived(c *fiber.Ctx, archived bool) error {
+	user, ok := c.Locals("user").(*client.User)
+	if !ok || user == nil {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "User not found in context (middleware issue?)")
+	}
+	conversationID := c.Params("id")
+	if conversationID == "" {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Missing conversation ID")
+	}
+
+	// chat-gateway checks ownership against the propagated user ID
+	ctx := metadata.AppendToOutgoingContext(c.UserContext(), "user-id", user.ID)
+	info, err := h.chatClient.SetConversationArchived(ctx, conversationID, archived)
+	if err != nil {
+		return sendConversationError(c, err, "Failed to update conversation")
+	}
+	return c.Status(fiber.StatusOK).JSON(conversationInfo(info))
+}
+
+// @Summary Reset a conversation
+// @Description Clear the messages of one of the authenticated user's conversations so that the next message is answered without any prior context. The conversation keeps its ID and title
+// @Tags conversations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Success 200 {object} ConversationInfo
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/conversations/{id}/reset [post]
+func (h *Handler) HandleResetConversation(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*client.User)
+	if !ok || user == nil {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "User not found in context (middleware issue?)")
+	}
+	conversationID := c.Params("id")
+	if conversationID == "" {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Missing conversation ID")
+	}
+
+	// chat-gateway checks ownership against the propagated user ID
+	ctx := metadata.AppendToOutgoingContext(c.UserContext(), "user-id", user.ID)
+	info, err := h.chatClient.ResetConversation(ctx, conversationID)
+	if err != nil {
+		return sendConversationError(c, err, "Failed to reset conversation")
+	}
+	return c.Status(fiber.StatusOK).JSON(conversationInfo(info))
+}
+
+// sendConversationError answers a failed chat-gateway call about one
+// conversation, mapping ownership and existence errors to 403 and 404 and
+// other errors by their kind.
+func sendConversationError(c *fiber.Ctx, err error, failure string) error {
+	kind := errs.KindOf(err)
+	switch kind {
+	case errs.PermissionDenied:
+		return utils.SendErrorResponse(c, errs.HTTPStatus(kind), "You don't have permission to access this conversation")
+	case errs.NotFound:
+		return utils.SendErrorResponse(c, errs.HTTPStatus(kind), "Conversation not found")
+	}
+	return utils.SendErrorResponse(c, errs.HTTPStatus(kind), failure+": "+err.Error())
+}
+
+func conversationInfo(info *pbChat.ConversationInfo) ConversationInfo {
+	return ConversationInfo{
+		ID:            info.GetConversationId(),
+		Title:         info.GetTitle(),
+		MessageCount:  info.GetMessageCount(),
+		LastMessageAt: info.GetLastMessageAt(),
+		Archived:      info.GetArchived(),
+	}
+}
+
+// citationPattern matches the "[Source 1 - name]" / "[Nguồn 1 - name]"
+// markers the RAG prompts ask the model to cite with.
+var citationPattern = regexp.MustCompile(`\[(?:Source|Nguồn) \d+ - ([^\]]+)\]`)
+
+// citedSources returns the distinct sources cited in text, in order.
+func citedSources(text string) []string {
+	var sources []string
+	seen := make(map[string]bool)
+	for _, m := range citationPattern.FindAllStringSubmatch(text, -1) {
+		source := strings.TrimSpace(m[1])
+		if !seen[source] {
+			seen[source] = true
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+var conversationRoleLabels = map[string]string{
+	"user":      "You",
+	"assistant": "CareerUP Assistant",
+}
+
+// renderConversationMarkdown renders a conversation as a Markdown transcript:
+// one section per message with its role and time, followed by the sources
+// an answer cited.
+func renderConversationMarkdown(conv *pbChat.GetConversationResponse) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Conversation %s\n", conv.GetConversationId())
+
+	for _, msg := range conv.GetMessages() {
+		role, ok := conversationRoleLabels[msg.GetRole()]
+		if !ok {
+			role = msg.GetRole()
+		}
+		fmt.Fprintf(&sb, "\n## %s · %s\n\n", role, formatTranscriptTime(msg.GetCreatedAt()))
+		sb.WriteString(strings.TrimSpace(msg.GetText()))
+		sb.WriteString("\n")
+
+		if sources := citedSources(msg.GetText()); len(sources) > 0 {
+			sb.WriteString("\n**Sources**\n\n")
+			for _, source := range sources {
+				fmt.Fprintf(&sb, "- %s\n", source)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// formatTranscriptTime shows an RFC 3339 timestamp in a readable UTC form,
+// or as-is if it cannot be parsed.
+func formatTranscriptTime(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ts
+	}
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// summarizeUserMessages condenses what the user said in conv into a bulleted
+// list of at most maxChars characters. The newest messages are kept when
+// they don't all fit; only the newest is ever cut short.
+func summarizeUserMessages(conv *pbChat.GetConversationResponse, maxChars int) string {
+	var lines []string
+	remaining := maxChars
+	msgs := conv.GetMessages()
+	for i := len(msgs) - 1; i >= 0 && remaining > len("- x"); i-- {
+		if msgs[i].GetRole() != "user" {
+			continue
+		}
+		text := strings.Join(strings.Fields(msgs[i].GetText()), " ")
+		if text == "" {
+			continue
+		}
+		line := "- " + text
+		if len(line) > remaining {
+			if len(lines) > 0 {
+				break
+			}
+			line = truncateRunes(line, remaining)
+		}
+		lines = append(lines, line)
+		remaining -= len(line) + 1 // newline
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncateRunes cuts s to at most maxBytes bytes without splitting a UTF-8
+// sequence, marking the cut with an ellipsis.
+func truncateRunes(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	const ellipsis = "…"
+	cut := maxBytes - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	if cut <= 0 {
+		return ""
+	}
+	return strings.TrimRight(s[:cut], " ") + ellipsis
+}
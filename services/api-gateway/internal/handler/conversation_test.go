@@ -0,0 +1,305 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chatpb "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// exportApp serves the export route as the given user, standing in for the
+// auth middleware.
+func exportApp(chatClient *handler.MockChatClient, userID string) *fiber.App {
+	h := handler.NewHandler(handler.NewMockAuthClient(), chatClient, nil, nil, "")
+	app := fiber.New()
+	app.Get("/api/v1/conversations/:id/export", func(c *fiber.Ctx) error {
+		c.Locals("user", &client.User{ID: userID})
+		return c.Next()
+	}, h.HandleExportConversation)
+	return app
+}
+
+func TestHandleExportConversation(t *testing.T) {
+	t.Run("renders markdown transcript", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("GetConversation", mock.Anything, "conv-1").Return(&chatpb.GetConversationResponse{
+			ConversationId: "conv-1",
+			UserId:         "user-1",
+			Messages: []*chatpb.ConversationMessage{
+				{Role: "user", Text: "Which major suits me?", CreatedAt: "2025-05-01T10:00:00Z"},
+				{
+					Role:      "assistant",
+					Text:      "Computer Science fits your profile [Source 1 - admissions.pdf]. See also [Source 2 - fees.pdf] and [Source 1 - admissions.pdf].",
+					CreatedAt: "2025-05-01T10:00:05Z",
+				},
+			},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/conversations/conv-1/export?format=md", nil)
+		resp, err := exportApp(chatClient, "user-1").Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/markdown; charset=utf-8", resp.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, `attachment; filename="conversation-conv-1.md"`, resp.Header.Get(fiber.HeaderContentDisposition))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `# Conversation conv-1
+
+## You · 2025-05-01 10:00 UTC
+
+Which major suits me?
+
+## CareerUP Assistant · 2025-05-01 10:00 UTC
+
+Computer Science fits your profile [Source 1 - admissions.pdf]. See also [Source 2 - fees.pdf] and [Source 1 - admissions.pdf].
+
+**Sources**
+
+- admissions.pdf
+- fees.pdf
+`, string(body))
+		chatClient.AssertExpectations(t)
+	})
+
+	t.Run("rejects non-owner", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("GetConversation", mock.Anything, "conv-1").
+			Return(nil, status.Error(codes.PermissionDenied, "conversation belongs to another user"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/conversations/conv-1/export", nil)
+		resp, err := exportApp(chatClient, "intruder").Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("rejects response owned by someone else", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("GetConversation", mock.Anything, "conv-1").
+			Return(&chatpb.GetConversationResponse{ConversationId: "conv-1", UserId: "user-1"}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/conversations/conv-1/export", nil)
+		resp, err := exportApp(chatClient, "intruder").Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("unknown conversation", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("GetConversation", mock.Anything, "missing").
+			Return(nil, status.Error(codes.NotFound, "conversation not found"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/conversations/missing/export", nil)
+		resp, err := exportApp(chatClient, "user-1").Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/conversations/conv-1/export?format=docx", nil)
+		resp, err := exportApp(chatClient, "user-1").Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		chatClient.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+	})
+}
+
+// conversationsApp serves the conversation list and archive routes as the
+// given user, standing in for the auth middleware.
+func conversationsApp(chatClient *handler.MockChatClient, userID string) *fiber.App {
+	h := handler.NewHandler(handler.NewMockAuthClient(), chatClient, nil, nil, "")
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &client.User{ID: userID})
+		return c.Next()
+	})
+	app.Get("/api/v1/conversations", h.HandleListConversations)
+	app.Post("/api/v1/conversations/:id/archive", h.HandleArchiveConversation)
+	app.Post("/api/v1/conversations/:id/unarchive", h.HandleUnarchiveConversation)
+	app.Post("/api/v1/conversations/:id/reset", h.HandleResetConversation)
+	return app
+}
+
+func TestHandleListConversations(t *testing.T) {
+	active := &chatpb.ConversationInfo{ConversationId: "conv-2", Title: "Học phí?", MessageCount: 2, LastMessageAt: "2025-05-02T09:00:00Z"}
+	archived := &chatpb.ConversationInfo{ConversationId: "conv-1", Title: "Ngành nào?", MessageCount: 4, LastMessageAt: "2025-05-01T10:00:00Z", Archived: true}
+
+	list := func(t *testing.T, chatClient *handler.MockChatClient, query string) handler.ConversationsResponse {
+		t.Helper()
+		resp, err := conversationsApp(chatClient, "user-1").Test(httptest.NewRequest(http.MethodGet, "/api/v1/conversations"+query, nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		var body handler.ConversationsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+
+	t.Run("archived conversations are left out by default", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("ListConversations", mock.Anything, false).Return([]*chatpb.ConversationInfo{active}, nil)
+
+		body := list(t, chatClient, "")
+		assert.Equal(t, []handler.ConversationInfo{
+			{ID: "conv-2", Title: "Học phí?", MessageCount: 2, LastMessageAt: "2025-05-02T09:00:00Z"},
+		}, body.Conversations)
+		chatClient.AssertExpectations(t)
+	})
+
+	t.Run("include_archived lists them too", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("ListConversations", mock.Anything, true).Return([]*chatpb.ConversationInfo{active, archived}, nil)
+
+		body := list(t, chatClient, "?include_archived=true")
+		require.Len(t, body.Conversations, 2)
+		assert.True(t, body.Conversations[1].Archived)
+		chatClient.AssertExpectations(t)
+	})
+
+	t.Run("no conversations is an empty list", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("ListConversations", mock.Anything, false).Return([]*chatpb.ConversationInfo(nil), nil)
+
+		body := list(t, chatClient, "")
+		assert.Equal(t, []handler.ConversationInfo{}, body.Data)
+		assert.Equal(t, []handler.ConversationInfo{}, body.Conversations)
+	})
+
+	t.Run("pages through the list", func(t *testing.T) {
+		var infos []*chatpb.ConversationInfo
+		for i := range 5 {
+			infos = append(infos, &chatpb.ConversationInfo{ConversationId: fmt.Sprintf("conv-%d", i)})
+		}
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("ListConversations", mock.Anything, false).Return(infos, nil)
+
+		body := list(t, chatClient, "?limit=2&offset=2")
+		require.Len(t, body.Data, 2)
+		assert.Equal(t, "conv-2", body.Data[0].ID)
+		assert.Equal(t, body.Data, body.Conversations)
+		assert.Equal(t, 5, body.Pagination.Total)
+		require.NotNil(t, body.Pagination.Next)
+
+		body = list(t, chatClient, "?limit=2&page_token="+*body.Pagination.Next)
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, "conv-4", body.Data[0].ID)
+		assert.Nil(t, body.Pagination.Next)
+	})
+
+	t.Run("invalid page is a bad request", func(t *testing.T) {
+		resp, err := conversationsApp(handler.NewMockChatClient(), "user-1").Test(httptest.NewRequest(http.MethodGet, "/api/v1/conversations?limit=0", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestHandleArchiveConversation(t *testing.T) {
+	post := func(t *testing.T, chatClient *handler.MockChatClient, userID, path string) *http.Response {
+		t.Helper()
+		resp, err := conversationsApp(chatClient, userID).Test(httptest.NewRequest(http.MethodPost, path, nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("archive and unarchive round-trip", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("SetConversationArchived", mock.Anything, "conv-1", true).
+			Return(&chatpb.ConversationInfo{ConversationId: "conv-1", Archived: true}, nil).Once()
+		chatClient.On("SetConversationArchived", mock.Anything, "conv-1", false).
+			Return(&chatpb.ConversationInfo{ConversationId: "conv-1"}, nil).Once()
+
+		for _, step := range []struct {
+			path     string
+			archived bool
+		}{
+			{"/api/v1/conversations/conv-1/archive", true},
+			{"/api/v1/conversations/conv-1/unarchive", false},
+		} {
+			resp := post(t, chatClient, "user-1", step.path)
+			require.Equal(t, fiber.StatusOK, resp.StatusCode)
+			var info handler.ConversationInfo
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+			assert.Equal(t, handler.ConversationInfo{ID: "conv-1", Archived: step.archived}, info)
+		}
+		chatClient.AssertExpectations(t)
+	})
+
+	t.Run("rejects non-owner", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("SetConversationArchived", mock.Anything, "conv-1", true).
+			Return(nil, status.Error(codes.PermissionDenied, "conversation belongs to another user"))
+
+		resp := post(t, chatClient, "intruder", "/api/v1/conversations/conv-1/archive")
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("unknown conversation", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("SetConversationArchived", mock.Anything, "missing", false).
+			Return(nil, status.Error(codes.NotFound, "conversation not found"))
+
+		resp := post(t, chatClient, "user-1", "/api/v1/conversations/missing/unarchive")
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestHandleResetConversation(t *testing.T) {
+	reset := func(t *testing.T, chatClient *handler.MockChatClient, userID, convID string) *http.Response {
+		t.Helper()
+		resp, err := conversationsApp(chatClient, userID).Test(httptest.NewRequest(http.MethodPost, "/api/v1/conversations/"+convID+"/reset", nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("keeps the ID and title", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("ResetConversation", mock.Anything, "conv-1").
+			Return(&chatpb.ConversationInfo{ConversationId: "conv-1", Title: "Ngành nào?"}, nil).Once()
+
+		resp := reset(t, chatClient, "user-1", "conv-1")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		var info handler.ConversationInfo
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+		assert.Equal(t, handler.ConversationInfo{ID: "conv-1", Title: "Ngành nào?"}, info)
+		chatClient.AssertExpectations(t)
+	})
+
+	t.Run("rejects non-owner", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("ResetConversation", mock.Anything, "conv-1").
+			Return(nil, status.Error(codes.PermissionDenied, "conversation belongs to another user"))
+
+		resp := reset(t, chatClient, "intruder", "conv-1")
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("unknown conversation", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("ResetConversation", mock.Anything, "missing").
+			Return(nil, status.Error(codes.NotFound, "conversation not found"))
+
+		resp := reset(t, chatClient, "user-1", "missing")
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+	t.Run("chat-gateway unavailable", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("ResetConversation", mock.Anything, "conv-1").
+			Return(nil, status.Error(codes.Unavailable, "connection refused"))
+
+		resp := reset(t, chatClient, "user-1", "conv-1")
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	})
+}
@@ -3,12 +3,19 @@ package handler
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
 	"log"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/errs"
+	"github.com/careerup-Inc/careerup-monorepo/pkg/ilo"
 	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/i18n"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/middleware"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/share"
 	utils "github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/utils"
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
@@ -48,18 +55,89 @@ type Handler struct {
 	LLMClient *client.LLMClient
 	// Auth core service address for REST calls
 	authCoreServiceAddr string
+	// Budget for the chat summary added to ILO analysis prompts; zero
+	// disables it
+	iloChatContextChars int
+	// User-facing strings, in the language each request asks for
+	catalog *i18n.Catalog
+	// Assistant tokens arriving within this window are sent to WebSocket
+	// clients as one message; zero sends each token on its own
+	tokenBatchWindow time.Duration
+	// Messages that may wait for a slow WebSocket client, and what happens
+	// to one that does not fit; zeros mean DefaultOutboundBuffer and
+	// OverflowClose
+	outboundBuffer int
+	overflowPolicy OverflowPolicy
+	// Conversation sharing; shareSigner is nil while it is disabled
+	shareSigner      *share.Signer
+	shareRevocations share.Store
+	shareTTL         time.Duration
+	shareMaxTTL      time.Duration
+	// Validated tokens trusted by the auth middleware; nil leaves evicting
+	// them to the cache's TTL
+	tokenCache middleware.TokenCache
 }
 
 func NewHandler(authClient client.AuthClientInterface, chatClient client.ChatClientInterface, iloClient *client.IloClient, llmClient *client.LLMClient, authCoreAddr string) *Handler {
+	catalog, _ := i18n.New(i18n.Vietnamese)
 	return &Handler{
 		authClient:          authClient,
 		chatClient:          chatClient,
 		IloClient:           iloClient,
 		LLMClient:           llmClient,
 		authCoreServiceAddr: authCoreAddr,
+		catalog:             catalog,
 	}
 }
 
+// EnableTokenBatching makes the WebSocket proxy coalesce assistant tokens
+// that arrive within window into one message, trading that much latency for
+// fewer frames.
+func (h *Handler) EnableTokenBatching(window time.Duration) {
+	h.tokenBatchWindow = window
+}
+
+// SetOutboundBuffer bounds how many messages may wait for a WebSocket client
+// that reads slower than the chat service answers; policy says what happens
+// to a message once size are waiting. Zeros mean DefaultOutboundBuffer and
+// OverflowClose.
+func (h *Handler) SetOutboundBuffer(size int, policy OverflowPolicy) {
+	h.outboundBuffer = size
+	h.overflowPolicy = policy
+}
+
+// SetCatalog replaces the Vietnamese-default message catalog, e.g. with one
+// defaulting to the configured language.
+func (h *Handler) SetCatalog(catalog *i18n.Catalog) {
+	h.catalog = catalog
+}
+
+// SetTokenCache gives the handler the cache of validated tokens that the
+// auth middleware trusts, so that refreshing and logging out evict the
+// access token they retire.
+func (h *Handler) SetTokenCache(tokens middleware.TokenCache) {
+	h.tokenCache = tokens
+}
+
+// evictAccessToken forgets the bearer token of c from the token cache, so
+// the next request with it is validated by auth-core again. Failures are
+// logged: the entry expires with the cache's TTL anyway.
+func (h *Handler) evictAccessToken(c *fiber.Ctx) {
+	authHeader := c.Get("Authorization")
+	if h.tokenCache == nil || !strings.HasPrefix(authHeader, "Bearer ") {
+		return
+	}
+	if err := h.tokenCache.Evict(c.UserContext(), strings.TrimPrefix(authHeader, "Bearer ")); err != nil {
+		log.Printf("Failed to evict access token from the token cache: %v", err)
+	}
+}
+
+// EnableIloChatContext lets ILO analyses draw on a conversation the user
+// chose to share, summarised in at most maxChars characters.
+func (h *Handler) EnableIloChatContext(maxChars int) {
+	h.iloChatContextChars = maxChars
+}
+
 // @Summary Register a new user
 // @Description Register a new user with email and password
 // @Tags auth
@@ -77,7 +155,7 @@ func (h *Handler) HandleRegister(c *fiber.Ctx) error {
 	}
 
 	// Call auth service to register user
-	user, err := h.authClient.Register(c.Context(), &client.RegisterRequest{
+	user, err := h.authClient.Register(c.UserContext(), &client.RegisterRequest{
 		Email:     req.Email,
 		Password:  req.Password,
 		FirstName: req.FirstName,
@@ -124,7 +202,7 @@ func (h *Handler) HandleLogin(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	loginResp, err := h.authClient.Login(c.Context(), &client.LoginRequest{
+	loginResp, err := h.authClient.Login(c.UserContext(), &client.LoginRequest{
 		Email:    req.Email,
 		Password: req.Password,
 	})
@@ -177,7 +255,7 @@ func (h *Handler) HandleRefreshToken(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "refresh_token is required")
 	}
 
-	tokens, err := h.authClient.RefreshToken(c.Context(), req.RefreshToken)
+	tokens, err := h.authClient.RefreshToken(c.UserContext(), req.RefreshToken)
 	if err != nil {
 		// Map gRPC errors
 		st, ok := status.FromError(err)
@@ -200,10 +278,28 @@ func (h *Handler) HandleRefreshToken(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Token refresh failed: "+err.Error()) // Default to 401
 	}
 
+	// The access token sent along is replaced by the new one
+	h.evictAccessToken(c)
+
 	// Return the new token response (contains new access_token, refresh_token, expires_in)
 	return c.Status(fiber.StatusOK).JSON(tokens)
 }
 
+// @Summary Log out
+// @Description Forget the access token in the gateway's token cache. auth-core has no revocation, so the token itself stays valid until it expires; clients must discard it and their refresh token
+// @Tags auth
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/auth/logout [post]
+func (h *Handler) HandleLogout(c *fiber.Ctx) error {
+	if !strings.HasPrefix(c.Get("Authorization"), "Bearer ") {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Authorization header is required")
+	}
+	h.evictAccessToken(c)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
 // @Summary Get current user
 // @Description Get the current authenticated user's profile
 // @Tags user
@@ -226,7 +322,7 @@ func (h *Handler) HandleGetProfile(c *fiber.Ctx) error {
 }
 
 // @Summary Update current user
-// @Description Update the current authenticated user's profile
+// @Description Update the current authenticated user's profile. Empty fields are left unchanged; use PATCH to clear a field
 // @Tags user
 // @Accept json
 // @Produce json
@@ -238,6 +334,28 @@ func (h *Handler) HandleGetProfile(c *fiber.Ctx) error {
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/profile [put]
 func (h *Handler) HandleUpdateProfile(c *fiber.Ctx) error {
+	return h.updateProfile(c, false)
+}
+
+// @Summary Partially update current user
+// @Description Update only the fields present in the body. A field sent as null or empty is cleared, e.g. "interests": [] removes all interests
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateUserRequest true "Fields to change"
+// @Success 200 {object} User
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/profile [patch]
+func (h *Handler) HandlePatchProfile(c *fiber.Ctx) error {
+	return h.updateProfile(c, true)
+}
+
+// updateProfile applies a profile update. PUT (patch false) keeps its old
+// behaviour of ignoring empty fields; PATCH clears them.
+func (h *Handler) updateProfile(c *fiber.Ctx, patch bool) error {
 	// Get user from context (set by auth middleware)
 	userLocal := c.Locals("user")
 	if userLocal == nil {
@@ -262,14 +380,24 @@ func (h *Handler) HandleUpdateProfile(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 
-	// Call auth service to update user
-	updatedUser, err := h.authClient.UpdateUser(c.Context(), &client.UpdateUserRequest{
+	update := &client.UpdateUserRequest{
 		Token:     token,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Hometown:  req.Hometown,
 		Interests: req.Interests,
-	})
+	}
+	if !patch {
+		dropEmptyFields(update)
+	} else if emptyString(update.FirstName) || emptyString(update.LastName) {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "first_name and last_name cannot be cleared")
+	}
+	if update.FirstName == nil && update.LastName == nil && update.Hometown == nil && update.Interests == nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "At least one field is required to update")
+	}
+
+	// Call auth service to update user
+	updatedUser, err := h.authClient.UpdateUser(c.UserContext(), update)
 
 	if err != nil {
 		// Map gRPC errors
@@ -297,6 +425,22 @@ func (h *Handler) HandleUpdateProfile(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(updatedUser)
 }
 
+func emptyString(s *string) bool {
+	return s != nil && *s == ""
+}
+
+// dropEmptyFields unsets empty fields, which PUT treats as "no change"
+func dropEmptyFields(req *client.UpdateUserRequest) {
+	for _, field := range []**string{&req.FirstName, &req.LastName, &req.Hometown} {
+		if emptyString(*field) {
+			*field = nil
+		}
+	}
+	if req.Interests != nil && len(*req.Interests) == 0 {
+		req.Interests = nil
+	}
+}
+
 // @Summary Validate token
 // @Description Validate an authentication token
 // @Tags auth
@@ -318,7 +462,7 @@ func (h *Handler) HandleValidateToken(c *fiber.Ctx) error {
 		token = authHeader[7:]
 	}
 
-	user, err := h.authClient.ValidateToken(c.Context(), token)
+	user, err := h.authClient.ValidateToken(c.UserContext(), token)
 	if err != nil {
 		// Map gRPC errors
 		st, ok := status.FromError(err)
@@ -355,17 +499,18 @@ func (h *Handler) HandleWebSocket(c *fiber.Ctx) error {
 	if websocket.IsWebSocketUpgrade(c) {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Authorization header is required"})
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Authorization header is required", "error_code": ErrorCodeUnauthorized})
 		}
 		token := authHeader
 		if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
 			token = authHeader[7:]
 		}
-		user, err := h.authClient.ValidateToken(c.Context(), token)
+		user, err := h.authClient.ValidateToken(c.UserContext(), token)
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token"})
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token", "error_code": ErrorCodeUnauthorized})
 		}
 		c.Locals("userID", user.ID)
+		c.Locals("userLanguage", c.Get(fiber.HeaderAcceptLanguage))
 		return c.Next()
 	}
 	return fiber.ErrUpgradeRequired
@@ -384,7 +529,12 @@ func (h *Handler) WebSocketProxy(conn *websocket.Conn) {
 
 	// --- gRPC Stream Setup ---
 	md := metadata.Pairs("user-id", userID)
+	// chat-gateway falls back to this when it cannot tell a message's language
+	if lang, _ := conn.Locals("userLanguage").(string); lang != "" {
+		md.Set("user-language", lang)
+	}
 	ctx := metadata.NewOutgoingContext(context.Background(), md)
+	ctx = middleware.ClientInfoLocal(conn.Locals).AppendToOutgoingContext(ctx)
 	// Add cancellation
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel() // Ensure cancellation happens on function exit
@@ -393,17 +543,52 @@ func (h *Handler) WebSocketProxy(conn *websocket.Conn) {
 	stream, err := h.chatClient.GetChatServiceClient().Stream(ctx)
 	if err != nil {
 		log.Printf("Failed to establish gRPC stream with chat-gateway: %v", err)
-		_ = conn.WriteJSON(ServerMessage{Type: "error", ErrorMessage: "Failed to connect to chat service"})
+		_ = conn.WriteJSON(ServerMessage{Type: "error", ErrorMessage: "Failed to connect to chat service", ErrorCode: streamErrorCode(err)})
 		return
 	}
 	log.Println("gRPC stream established with chat-gateway")
 
-	// Goroutine to read from gRPC stream and write to WebSocket
+	// Writes go through a bounded queue so a client that reads slowly
+	// cannot stall the gRPC stream; one that falls too far behind is
+	// disconnected, or misses messages, as the overflow policy says. The
+	// queue's goroutines may outlive this function, and conn with it once
+	// it goes back to its pool, so they use the underlying connection
+	ws := conn.Conn
+	queue := newOutboundQueue(func(msg ServerMessage) error { return ws.WriteJSON(msg) }, h.outboundBuffer, h.overflowPolicy, func() {
+		log.Printf("WebSocket client of user %s is too slow, closing the connection", userID)
+		_ = ws.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow"),
+			time.Now().Add(time.Second))
+		ws.Close()
+	})
+	defer func() {
+		queue.Close()
+		if dropped := queue.Dropped(); dropped > 0 {
+			log.Printf("Dropped %d messages for the slow WebSocket client of user %s", dropped, userID)
+		}
+	}()
+
+	// Both loops below write to the client through out, which serializes
+	// writes and batches tokens
+	out := newTokenBatcher(queue.Send, h.tokenBatchWindow)
+	defer out.Close()
+
+	// Goroutine to read from gRPC stream and write to WebSocket. It is
+	// waited for on return, once cancel has ended its Recv, so nothing uses
+	// the stream or the writers after the connection is gone
+	recvDone := make(chan struct{})
+	defer func() {
+		cancel()
+		<-recvDone
+	}()
 	go func() {
+		defer close(recvDone)
 		defer log.Println("Exiting gRPC read goroutine")
 		for {
 			res, err := stream.Recv()
 			if err != nil {
+				// Tokens still held back go out before the stream's end
+				_ = out.Flush()
 				// Handle different kinds of errors
 				st, ok := status.FromError(err)
 				if ok {
@@ -412,13 +597,13 @@ func (h *Handler) WebSocketProxy(conn *websocket.Conn) {
 					} else {
 						log.Printf("gRPC stream receive error: %v, code: %s", err, st.Code())
 						// Send error to WebSocket client if connection is still likely open
-						_ = conn.WriteJSON(ServerMessage{Type: "error", ErrorMessage: "Chat service connection error"})
+						_ = out.Send(ServerMessage{Type: "error", ErrorMessage: "Chat service connection error", ErrorCode: streamErrorCode(err)})
 					}
 				} else if err == io.EOF {
 					log.Println("gRPC stream closed by chat-gateway (EOF)")
 				} else {
 					log.Printf("gRPC stream receive error (non-gRPC): %v", err)
-					_ = conn.WriteJSON(ServerMessage{Type: "error", ErrorMessage: "Chat service communication error"})
+					_ = out.Send(ServerMessage{Type: "error", ErrorMessage: "Chat service communication error", ErrorCode: ErrorCodeChatError})
 				}
 				cancel() // Cancel context to potentially stop the write loop below
 				return   // Exit goroutine
@@ -443,18 +628,37 @@ func (h *Handler) WebSocketProxy(conn *websocket.Conn) {
 				}
 			case "error":
 				if errorContent := res.GetErrorMessage(); errorContent != "" {
-					msg = ServerMessage{Type: "error", ErrorMessage: errorContent}
+					code := ErrorCode(res.GetErrorCode())
+					if code == "" {
+						// chat-gateway versions before error codes only fail on the LLM
+						code = ErrorCodeLLMError
+					}
+					msg = ServerMessage{Type: "error", ErrorMessage: errorContent, ErrorCode: code, RetryAfterSeconds: res.GetRetryAfterSeconds()}
 				} else {
 					log.Println("Received error with empty content")
 					continue
 				}
+			case "status":
+				if statusContent := res.GetStatus(); statusContent != "" {
+					msg = ServerMessage{Type: "status", Status: statusContent}
+				} else {
+					log.Println("Received status with empty content")
+					continue
+				}
+			case "suggestions":
+				if suggestions := res.GetSuggestions(); len(suggestions) > 0 {
+					msg = ServerMessage{Type: "suggestions", Suggestions: suggestions}
+				} else {
+					log.Println("Received suggestions with no questions")
+					continue
+				}
 			default:
 				log.Printf("Unknown message type from gRPC: %s", res.Type)
 				continue // Skip unknown types
 			}
 
 			// Write the message to the WebSocket client
-			if err := conn.WriteJSON(msg); err != nil {
+			if err := out.Send(msg); err != nil {
 				log.Printf("WebSocket write error: %v", err)
 				// Assume client disconnected, cancel context to close gRPC stream
 				cancel()
@@ -483,14 +687,23 @@ func (h *Handler) WebSocketProxy(conn *websocket.Conn) {
 			var clientMsg ClientMessage
 			if err := json.Unmarshal(msgBytes, &clientMsg); err != nil {
 				log.Printf("Failed to unmarshal client message: %v", err)
-				_ = conn.WriteJSON(ServerMessage{Type: "error", ErrorMessage: "Invalid message format"})
+				_ = out.Send(ServerMessage{Type: "error", ErrorMessage: "Invalid message format", ErrorCode: ErrorCodeInvalidMessage})
 				continue
 			}
 
-			// Basic validation
-			if clientMsg.Type != "user_msg" || clientMsg.Text == "" {
+			// Basic validation; "regenerate" re-answers the conversation's last
+			// message and "continue" resumes its cut-off answer, so they need
+			// the conversation but no text
+			valid := (clientMsg.Type == "user_msg" && clientMsg.Text != "") ||
+				((clientMsg.Type == "regenerate" || clientMsg.Type == "continue") && clientMsg.ConversationID != "")
+			if !valid {
 				log.Printf("Invalid client message type or empty text: Type=%s", clientMsg.Type)
-				_ = conn.WriteJSON(ServerMessage{Type: "error", ErrorMessage: "Invalid message type or empty text"})
+				_ = out.Send(ServerMessage{Type: "error", ErrorMessage: "Invalid message type or empty text", ErrorCode: ErrorCodeInvalidMessage})
+				continue
+			}
+			if !verbosities[clientMsg.Verbosity] {
+				log.Printf("Invalid client message verbosity: %q", clientMsg.Verbosity)
+				_ = out.Send(ServerMessage{Type: "error", ErrorMessage: "Invalid verbosity; use short, normal or detailed", ErrorCode: ErrorCodeInvalidMessage})
 				continue
 			}
 
@@ -499,11 +712,12 @@ func (h *Handler) WebSocketProxy(conn *websocket.Conn) {
 				Type:           clientMsg.Type,
 				ConversationId: clientMsg.ConversationID,
 				Text:           clientMsg.Text,
+				Verbosity:      clientMsg.Verbosity,
 			}
 			if err := stream.Send(grpcReq); err != nil {
 				log.Printf("gRPC stream send error: %v", err)
 				// Assume gRPC stream is broken, send error and close connection
-				_ = conn.WriteJSON(ServerMessage{Type: "error", ErrorMessage: "Failed to send message to chat service"})
+				_ = out.Send(ServerMessage{Type: "error", ErrorMessage: "Failed to send message to chat service", ErrorCode: streamErrorCode(err)})
 				cancel()
 				break // Exit read loop
 			}
@@ -515,15 +729,37 @@ func (h *Handler) WebSocketProxy(conn *websocket.Conn) {
 	log.Println("Exiting WebSocket read loop")
 }
 
+// streamErrorCode classifies a failed call to chat-gateway.
+func streamErrorCode(err error) ErrorCode {
+	if status.Code(err) == codes.Unauthenticated {
+		return ErrorCodeUnauthorized
+	}
+	switch errs.KindOf(err) {
+	case errs.PermissionDenied:
+		return ErrorCodeUnauthorized
+	case errs.Exhausted:
+		return ErrorCodeRateLimited
+	case errs.Unavailable:
+		return ErrorCodeChatUnavailable
+	default:
+		return ErrorCodeChatError
+	}
+}
+
 // @Summary Submit ILO test result
-// @Description Submit ILO test result for the authenticated user and get analysis
+// @Description Submit ILO test result for the authenticated user and get analysis. Answers must cover every question of the test exactly once; result_data is deprecated and only read for its answers when answers is empty
 // @Tags ilo
 // @Accept json
 // @Produce json
 // @Param request body IloTestResultRequest true "ILO Test Result Request"
+// @Param no_cache query bool false "Skip the cached analysis and regenerate it"
+// @Param Idempotency-Key header string false "Identifies the submission across retries, at most 128 characters"
+// @Success 200 {object} IloTestResultResponse "A repeated submission: the original result and its analysis"
 // @Success 201 {object} IloTestResultResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
 // @Router /api/v1/ilo/result [post]
 func (h *Handler) HandleIloTestResult(c *fiber.Ctx) error {
 	var req IloTestResultRequest
@@ -536,91 +772,101 @@ func (h *Handler) HandleIloTestResult(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Missing or invalid Authorization header")
 	}
 
+	idempotencyKey := c.Get(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Idempotency-Key must be at most "+strconv.Itoa(maxIdempotencyKeyLength)+" characters")
+	}
+
+	lang := h.catalog.For(c)
+
 	// Save ILO result via gRPC to ILO service
-	user, err := h.authClient.ValidateToken(c.Context(), token)
+	user, err := h.authClient.ValidateToken(c.UserContext(), token)
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Invalid token: "+err.Error())
 	}
 
-	// Parse answers from request if they exist
-	var answers []client.IloAnswer
-	if len(req.Answers) > 0 {
-		answers = make([]client.IloAnswer, len(req.Answers))
-		for i, ans := range req.Answers {
-			answers[i] = client.IloAnswer{
-				QuestionID:     ans.QuestionID,
-				QuestionNumber: ans.QuestionNumber,
-				SelectedOption: ans.SelectedOption,
-			}
+	if req.ResultData != "" {
+		if err := checkIloResultData(req.ResultData); err != nil {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid ILO answers: "+err.Error())
 		}
 	}
 
-	result, err := h.IloClient.SubmitILOTestResult(c.Context(), &client.SubmitILOTestResultRequest{
-		UserID:        user.ID,
-		Answers:       answers,
-		RawResultData: req.ResultData,
-	})
-	if err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to save ILO test result: "+err.Error())
+	// Older clients send their answers inside result_data
+	submitted := req.Answers
+	if len(submitted) == 0 && req.ResultData != "" {
+		submitted, err = legacyIloAnswers(req.ResultData)
+		if err != nil {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid ILO answers: "+err.Error())
+		}
+		log.Printf("User %s submitted ILO answers in deprecated result_data", user.ID)
 	}
 
-	// Create a rich prompt for LLM analysis with structured data
-	// Build an expert‑level prompt so the LLM answers like a seasoned career‑guidance counsellor
-	promptLines := []string{
-		"You are a certified Vietnamese career counsellor who specialises in interpreting ILO tests for high-school students and parents.",
-		"You are a certified career guidance expert with deep knowledge of the Vietnamese ILO (Interest, Learning, Orientation) framework.",
-		"You are a friendly, slightly cheeky career-guidance guru who sprinkles gentle humour into professional advice.",
-		"Analyse the candidate’s ILO result and produce a report in vietnamese with the following sections:",
-		"1. Brief narrative overview of the candidate’s dominant interest profile.",
-		"2. Key strengths and potential development areas, illustrated with concrete examples.",
-		"3. Three to five career pathways that fit the profile, each followed by a one‑sentence rationale.",
-		"4. Actionable next steps for the candidate over the next 3–6 months (courses, extracurriculars, shadowing, mentorship, etc.).",
-		"ILO Domain Scores:",
+	test, err := h.IloClient.GetIloTest(c.UserContext())
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to load ILO test: "+err.Error())
 	}
-
-	for _, s := range result.Scores {
-		promptLines = append(promptLines, fmt.Sprintf("- %s: %.1f%% (%s)", s.DomainCode, s.Percent, s.Level))
+	answers, rawResult, err := validateIloAnswers(submitted, test.Questions)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid ILO answers: "+err.Error())
 	}
 
-	if len(result.TopDomains) > 0 {
-		promptLines = append(promptLines, "",
-			"Top domains: "+strings.Join(result.TopDomains, ", "))
+	result, err := h.IloClient.SubmitILOTestResult(c.UserContext(), &client.SubmitILOTestResultRequest{
+		UserID:         user.ID,
+		Answers:        answers,
+		RawResultData:  rawResult,
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to save ILO test result: "+err.Error())
 	}
-
-	// Personalise advice if basic user context is available
-	if user.FirstName != "" {
-		promptLines = append(promptLines, "",
-			"Candidate first name: "+user.FirstName)
+	if result.Duplicate && result.Analysis != "" {
+		// A retry of a submission that was already analysed gets the
+		// analysis saved then, so it is not generated and paid for again
+		saved, err := client.ParseIloAnalysis(result.Analysis)
+		if err == nil {
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"result":    iloTestResultResponse(result),
+				"analysis":  saved,
+				"duplicate": true,
+				"copyright": h.catalog.Message(lang, i18n.IloCopyright),
+			})
+		}
+		log.Printf("Analysing ILO result %s again: %v", result.ID, err)
 	}
 
-	promptLines = append(promptLines, "",
-		"Raw ILO data: "+req.ResultData)
-
-	llmPrompt := strings.Join(promptLines, "\n")
+	// A retry whose first attempt got no analysis saved, e.g. because the
+	// LLM failed, is analysed now
+	llmPrompt := h.iloAnalysisPrompt(lang, user, result, h.iloChatContext(c, user.ID, req), rawResult)
 
-	llmAnalysis, err := h.LLMClient.AnalyzeILOResult(c.Context(), &client.LLMAnalysisRequest{
-		Prompt: llmPrompt,
-		UserID: user.ID,
+	llmAnalysis, err := h.LLMClient.AnalyzeILOResult(c.UserContext(), &client.LLMAnalysisRequest{
+		Prompt:      llmPrompt,
+		UserID:      user.ID,
+		BypassCache: c.QueryBool("no_cache", false),
 	})
 	if err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to analyze ILO test result: "+err.Error())
+		return sendIloAnalysisError(c, err)
 	}
-
-	resp := IloTestResultResponse{
-		ID:               result.ID,
-		UserID:           result.UserID,
-		ResultData:       result.ResultData,
-		CreatedAt:        result.CreatedAt,
-		Scores:           result.Scores,
-		TopDomains:       result.TopDomains,
-		SuggestedCareers: result.SuggestedCareers,
+	// Saved as JSON, which the result keeps as text
+	analysisJSON, err := json.Marshal(llmAnalysis)
+	if err == nil {
+		err = h.IloClient.SaveIloTestResultAnalysis(c.UserContext(), result.ID, user.ID, string(analysisJSON))
+	}
+	if err != nil {
+		// The user still gets the analysis; only a retry would generate it again
+		log.Printf("Failed to save the analysis of ILO result %s: %v", result.ID, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"result":    resp,
+	status := fiber.StatusCreated
+	response := fiber.Map{
+		"result":    iloTestResultResponse(result),
 		"analysis":  llmAnalysis,
-		"copyright": "Thang đo ILO © ILO Vietnam 2020 – sử dụng cho mục đích hướng nghiệp, trích dẫn có ghi nguồn.",
-	})
+		"copyright": h.catalog.Message(lang, i18n.IloCopyright),
+	}
+	if result.Duplicate {
+		status = fiber.StatusOK
+		response["duplicate"] = true
+	}
+	return c.Status(status).JSON(response)
 }
 
 // @Summary Get ILO test questions
@@ -632,7 +878,7 @@ func (h *Handler) HandleIloTestResult(c *fiber.Ctx) error {
 // @Router /api/v1/ilo/test [get]
 func (h *Handler) HandleGetIloTest(c *fiber.Ctx) error {
 	// Call the client to get ILO test questions
-	test, err := h.IloClient.GetIloTest(c.Context())
+	test, err := h.IloClient.GetIloTest(c.UserContext())
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get ILO test: "+err.Error())
 	}
@@ -642,55 +888,91 @@ func (h *Handler) HandleGetIloTest(c *fiber.Ctx) error {
 		"questions": test.Questions,
 		"domains":   test.Domains,
 		"levels":    test.Levels,
-		"copyright": "Thang đo ILO © ILO Vietnam 2020 – sử dụng cho mục đích hướng nghiệp, trích dẫn có ghi nguồn.",
+		"copyright": h.catalog.Message(h.catalog.For(c), i18n.IloCopyright),
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// @Summary Get ILO career suggestions
+// @Description Suggest career fields for ILO domains, with match percent and rationale
+// @Tags ilo
+// @Accept json
+// @Produce json
+// @Param request body IloCareerSuggestionsRequest true "Domains and optional scores"
+// @Success 200 {object} IloCareerSuggestionsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/ilo/careers [post]
+func (h *Handler) HandleGetIloCareerSuggestions(c *fiber.Ctx) error {
+	var req IloCareerSuggestionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+	if len(req.DomainCodes) == 0 {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "At least one domain code is required")
+	}
+	if req.Limit < 0 {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Limit must not be negative")
+	}
+
+	suggestions, err := h.IloClient.GetIloCareerSuggestions(c.UserContext(), req.DomainCodes, req.Scores, req.Limit)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get career suggestions: "+err.Error())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(IloCareerSuggestionsResponse{
+		Suggestions: suggestions,
+		Careers:     client.CareerFields(suggestions),
+	})
+}
+
 // @Summary Get all ILO test results for a user
-// @Description Get all ILO test results for the authenticated user
+// @Description Get one page of the authenticated user's ILO test results
 // @Tags ilo
 // @Produce json
-// @Success 200 {array} IloTestResultResponse
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Results to skip"
+// @Param page_token query string false "pagination.next of the previous page; overrides offset"
+// @Success 200 {object} IloTestResultsResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/ilo/results [get]
 func (h *Handler) HandleGetIloResults(c *fiber.Ctx) error {
+	limit, offset, err := utils.PageParams(c)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	token := utils.ExtractTokenFromHeader(c)
 	if token == "" {
 		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Missing or invalid Authorization header")
 	}
 
 	// Validate token and get user ID
-	user, err := h.authClient.ValidateToken(c.Context(), token)
+	user, err := h.authClient.ValidateToken(c.UserContext(), token)
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Invalid token: "+err.Error())
 	}
 
 	// Get all results for this user
-	results, err := h.IloClient.GetIloTestResults(c.Context(), user.ID)
+	results, err := h.IloClient.GetIloTestResults(c.UserContext(), user.ID)
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get ILO test results: "+err.Error())
 	}
 
 	// Create response array
-	var respResults []IloTestResultResponse
+	respResults := make([]IloTestResultResponse, 0, len(results))
 	for _, result := range results {
-		respResults = append(respResults, IloTestResultResponse{
-			ID:               result.ID,
-			UserID:           result.UserID,
-			ResultData:       result.ResultData,
-			CreatedAt:        result.CreatedAt,
-			Scores:           result.Scores,
-			TopDomains:       result.TopDomains,
-			SuggestedCareers: result.SuggestedCareers,
-		})
+		respResults = append(respResults, iloTestResultResponse(result))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"results":   respResults,
-		"copyright": "Thang đo ILO © ILO Vietnam 2020 – sử dụng cho mục đích hướng nghiệp, trích dẫn có ghi nguồn.",
+	page := utils.Paginate(respResults, limit, offset)
+	return c.Status(fiber.StatusOK).JSON(IloTestResultsResponse{
+		ListResponse: page,
+		Results:      page.Data,
+		Copyright:    h.catalog.Message(h.catalog.For(c), i18n.IloCopyright),
 	})
 }
 
@@ -712,7 +994,7 @@ func (h *Handler) HandleGetIloResultById(c *fiber.Ctx) error {
 	}
 
 	// Validate token and get user ID
-	user, err := h.authClient.ValidateToken(c.Context(), token)
+	user, err := h.authClient.ValidateToken(c.UserContext(), token)
 	if err != nil {
 		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Invalid token: "+err.Error())
 	}
@@ -723,19 +1005,239 @@ func (h *Handler) HandleGetIloResultById(c *fiber.Ctx) error {
 		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Missing result ID")
 	}
 
-	// Use the client method directly to retrieve the result by ID
-	result, err := h.IloClient.GetIloTestResultById(c.Context(), resultID)
+	// The ILO service checks ownership against the propagated user ID
+	ctx := metadata.AppendToOutgoingContext(c.UserContext(), "user-id", user.ID)
+	result, err := h.IloClient.GetIloTestResultById(ctx, resultID)
 	if err != nil {
-		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get ILO test result: "+err.Error())
+		return sendIloResultError(c, err)
 	}
 
-	// Verify that this result belongs to the authenticated user
+	// Verify that this result belongs to the authenticated user (defense in
+	// depth; the ILO service enforces this too)
 	if result.UserID != user.ID {
 		return utils.SendErrorResponse(c, fiber.StatusForbidden, "You don't have permission to access this result")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"result":    result,
-		"copyright": "Thang đo ILO © ILO Vietnam 2020 – sử dụng cho mục đích hướng nghiệp, trích dẫn có ghi nguồn.",
+		"copyright": h.catalog.Message(h.catalog.For(c), i18n.IloCopyright),
 	})
 }
+
+// @Summary Re-run the analysis of an ILO test result
+// @Description Analyse one of the authenticated user's ILO results again from its stored scores, with the current prompt and model. The new analysis is returned but not stored. With stream=true it is sent as server-sent events: an "analysis" event carries the analysis as JSON, followed by a "done" event, or an "error" event if the analysis fails
+// @Tags ilo
+// @Produce json
+// @Produce text/event-stream
+// @Param id path string true "Result ID"
+// @Param stream query bool false "Stream the analysis as server-sent events"
+// @Success 200 {object} IloTestResultAnalysisResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/ilo/result/{id}/analyze [post]
+func (h *Handler) HandleReanalyzeIloResult(c *fiber.Ctx) error {
+	token := utils.ExtractTokenFromHeader(c)
+	if token == "" {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Missing or invalid Authorization header")
+	}
+	user, err := h.authClient.ValidateToken(c.UserContext(), token)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "Invalid token: "+err.Error())
+	}
+
+	resultID := c.Params("id")
+	if resultID == "" {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Missing result ID")
+	}
+
+	// The ILO service checks ownership against the propagated user ID
+	ctx := metadata.AppendToOutgoingContext(c.UserContext(), "user-id", user.ID)
+	result, err := h.IloClient.GetIloTestResultById(ctx, resultID)
+	if err != nil {
+		return sendIloResultError(c, err)
+	}
+	if result.UserID != user.ID {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, "You don't have permission to access this result")
+	}
+
+	// Asking again is asking for a new analysis, so a cached one won't do
+	lang := h.catalog.For(c)
+	req := &client.LLMAnalysisRequest{
+		Prompt:      h.iloAnalysisPrompt(lang, user, result, "", result.ResultData),
+		UserID:      user.ID,
+		BypassCache: true,
+	}
+
+	if c.QueryBool("stream", false) {
+		// The stream outlives the handler, whose context is cancelled when
+		// it returns; keep the route's deadline but not its cancellation
+		streamCtx := context.WithoutCancel(c.UserContext())
+		cancel := context.CancelFunc(func() {})
+		if deadline, ok := c.UserContext().Deadline(); ok {
+			streamCtx, cancel = context.WithDeadline(streamCtx, deadline)
+		}
+		return utils.StreamEvents(c, func(w *utils.SSEWriter) error {
+			defer cancel()
+			analysis, err := h.LLMClient.AnalyzeILOResult(streamCtx, req)
+			if err != nil {
+				return w.Send("error", "Failed to analyze ILO test result: "+status.Convert(err).Message())
+			}
+			// The analysis is generated whole, so it comes as one event
+			data, err := json.Marshal(analysis)
+			if err != nil {
+				return w.Send("error", "Failed to analyze ILO test result: "+err.Error())
+			}
+			if err := w.Send("analysis", string(data)); err != nil {
+				return err
+			}
+			return w.Send("done", "")
+		})
+	}
+
+	analysis, err := h.LLMClient.AnalyzeILOResult(c.UserContext(), req)
+	if err != nil {
+		return sendIloAnalysisError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"result":    iloTestResultResponse(result),
+		"analysis":  analysis,
+		"copyright": h.catalog.Message(lang, i18n.IloCopyright),
+	})
+}
+
+// iloTestResultResponse copies result into its API representation.
+func iloTestResultResponse(result *client.SubmitILOTestResultResponse) IloTestResultResponse {
+	return IloTestResultResponse{
+		ID:               result.ID,
+		UserID:           result.UserID,
+		ResultData:       result.ResultData,
+		CreatedAt:        result.CreatedAt,
+		Scores:           result.Scores,
+		TopDomains:       result.TopDomains,
+		SuggestedCareers: result.SuggestedCareers,
+	}
+}
+
+// iloAnalysisPrompt builds an expert-level prompt so the LLM answers like a
+// seasoned career-guidance counsellor. chatSummary may be empty; rawResult
+// is the result's raw JSON data.
+func (h *Handler) iloAnalysisPrompt(lang string, user *client.User, result *client.SubmitILOTestResultResponse, chatSummary, rawResult string) string {
+	promptLines := []string{
+		"You are a certified Vietnamese career counsellor who specialises in interpreting ILO tests for high-school students and parents.",
+		"You are a certified career guidance expert with deep knowledge of the Vietnamese ILO (Interest, Learning, Orientation) framework.",
+		"You are a friendly, slightly cheeky career-guidance guru who sprinkles gentle humour into professional advice.",
+		"Analyse the candidate’s ILO result, writing every part of the analysis in " + h.catalog.Message(lang, i18n.IloReportLanguage) + ":",
+		"1. overview: brief narrative overview of the candidate’s dominant interest profile.",
+		"2. strengths and development_areas: key strengths and potential development areas, illustrated with concrete examples.",
+		"3. careers: three to five career pathways that fit the profile, each with a one‑sentence rationale.",
+		"4. next_steps: actionable next steps for the candidate over the next 3–6 months (courses, extracurriculars, shadowing, mentorship, etc.).",
+		"ILO Domain Scores:",
+	}
+
+	scores := iloScores(result.Scores)
+	promptLines = append(promptLines, ilo.FormatScoreLines(scores)...)
+
+	// Fall back to deriving the top domains when the ILO service omits them
+	topDomains := result.TopDomains
+	if len(topDomains) == 0 {
+		topDomains = ilo.TopDomains(scores, iloTopDomainCount)
+	}
+	if len(topDomains) > 0 {
+		promptLines = append(promptLines, "",
+			"Top domains: "+strings.Join(topDomains, ", "))
+	}
+
+	// Personalise advice if basic user context is available
+	if user.FirstName != "" {
+		promptLines = append(promptLines, "",
+			"Candidate first name: "+user.FirstName)
+	}
+
+	if chatSummary != "" {
+		promptLines = append(promptLines, "",
+			"Recent career chat with the candidate (their own messages, oldest first); use it to personalise the advice:",
+			chatSummary)
+	}
+
+	promptLines = append(promptLines, "")
+	promptLines = append(promptLines, iloPromptData(rawResult)...)
+
+	return strings.Join(promptLines, "\n")
+}
+
+// sendIloResultError answers a failed lookup of one ILO result, mapping
+// ownership and existence errors to 403 and 404 and other errors by their
+// kind.
+func sendIloResultError(c *fiber.Ctx, err error) error {
+	kind := errs.KindOf(err)
+	switch kind {
+	case errs.PermissionDenied:
+		return utils.SendErrorResponse(c, errs.HTTPStatus(kind), "You don't have permission to access this result")
+	case errs.NotFound:
+		return utils.SendErrorResponse(c, errs.HTTPStatus(kind), "ILO test result not found")
+	}
+	return utils.SendErrorResponse(c, errs.HTTPStatus(kind), "Failed to get ILO test result: "+err.Error())
+}
+
+// sendIloAnalysisError responds to a failed ILO analysis: 503 with
+// Retry-After when every model is busy, 429 once the user's quota is used up.
+func sendIloAnalysisError(c *fiber.Ctx, err error) error {
+	var busy *client.BusyError
+	if errors.As(err, &busy) {
+		c.Set("Retry-After", strconv.Itoa(int(busy.RetryAfter/time.Second)))
+		return utils.SendErrorResponse(c, fiber.StatusServiceUnavailable, busy.Message)
+	}
+	if errs.Is(err, errs.Exhausted) {
+		return utils.SendErrorResponse(c, errs.HTTPStatus(errs.Exhausted), "Monthly AI usage quota reached: "+status.Convert(err).Message())
+	}
+	return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to analyze ILO test result: "+err.Error())
+}
+
+// iloChatContext summarises the conversation the user shared with their ILO
+// submission. It is best effort: without consent, a conversation, or a
+// reachable chat-gateway, the analysis goes ahead without it.
+func (h *Handler) iloChatContext(c *fiber.Ctx, userID string, req IloTestResultRequest) string {
+	if h.iloChatContextChars <= 0 || !req.ShareChatContext || req.ConversationID == "" {
+		return ""
+	}
+	ctx := metadata.AppendToOutgoingContext(c.UserContext(), "user-id", userID)
+	conv, err := h.chatClient.GetConversation(ctx, req.ConversationID)
+	if err != nil {
+		log.Printf("Skipping chat context for ILO analysis of user %s: %v", userID, err)
+		return ""
+	}
+	if conv.GetUserId() != userID {
+		return ""
+	}
+	return summarizeUserMessages(conv, h.iloChatContextChars)
+}
+
+// idempotencyKeyHeader carries the key that makes retried ILO submissions
+// return the result saved the first time. The ILO service stores at most
+// maxIdempotencyKeyLength characters of it.
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 128
+)
+
+// iloTopDomainCount is how many top domains are shown when they have to be
+// derived from the scores.
+const iloTopDomainCount = 3
+
+// iloScores converts client domain scores to the shared ilo representation.
+func iloScores(scores []client.IloDomainScore) []ilo.DomainScore {
+	out := make([]ilo.DomainScore, 0, len(scores))
+	for _, s := range scores {
+		out = append(out, ilo.DomainScore{
+			DomainCode: s.DomainCode,
+			Percent:    s.Percent,
+			Level:      s.Level,
+			Rank:       s.Rank,
+		})
+	}
+	return out
+}
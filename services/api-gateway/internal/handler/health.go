@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthCheck reports whether a dependency is usable.
+type HealthCheck func(ctx context.Context) error
+
+// DependencyStatus is one dependency's entry in the readiness report.
+type DependencyStatus struct {
+	Status string `json:"status"` // ok, down, or degraded for optional dependencies
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessResponse lists each dependency's status.
+type ReadinessResponse struct {
+	Status       string                      `json:"status"` // ok, degraded or unavailable
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+// HealthChecker serves liveness and readiness probes. Readiness results are
+// cached briefly so frequent probes don't hammer the backends.
+type HealthChecker struct {
+	cacheTTL time.Duration
+	timeout  time.Duration
+
+	mu        sync.Mutex
+	checks    map[string]HealthCheck
+	optional  map[string]bool
+	last      ReadinessResponse
+	checkedAt time.Time
+}
+
+// NewHealthChecker caches readiness for cacheTTL and gives each check
+// timeout to pass.
+func NewHealthChecker(cacheTTL, timeout time.Duration) *HealthChecker {
+	return &HealthChecker{
+		cacheTTL: cacheTTL,
+		timeout:  timeout,
+		checks:   make(map[string]HealthCheck),
+		optional: make(map[string]bool),
+	}
+}
+
+// Add registers a dependency that must be healthy for the gateway to be ready.
+func (h *HealthChecker) Add(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+	delete(h.optional, name)
+	h.checkedAt = time.Time{}
+}
+
+// AddOptional registers a dependency the gateway can run without. While it
+// fails it is reported as degraded, and the gateway stays ready.
+func (h *HealthChecker) AddOptional(name string, check HealthCheck) {
+	h.Add(name, check)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.optional[name] = true
+}
+
+// Ready runs the checks concurrently, or returns the cached result.
+func (h *HealthChecker) Ready(ctx context.Context) ReadinessResponse {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.checkedAt.IsZero() && time.Since(h.checkedAt) < h.cacheTTL {
+		return h.last
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		resMu   sync.Mutex
+		results = ReadinessResponse{Status: "ok", Dependencies: make(map[string]DependencyStatus, len(h.checks))}
+	)
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck, optional bool) {
+			defer wg.Done()
+			status := DependencyStatus{Status: "ok"}
+			if err := check(ctx); err != nil {
+				status = DependencyStatus{Status: "down", Error: err.Error()}
+				if optional {
+					status.Status = "degraded"
+				}
+			}
+			resMu.Lock()
+			defer resMu.Unlock()
+			results.Dependencies[name] = status
+			switch {
+			case status.Status == "down":
+				results.Status = "unavailable"
+			case status.Status == "degraded" && results.Status == "ok":
+				results.Status = "degraded"
+			}
+		}(name, check, h.optional[name])
+	}
+	wg.Wait()
+
+	h.last = results
+	h.checkedAt = time.Now()
+	return results
+}
+
+// @Summary Liveness probe
+// @Description Reports that the process is up; does not check dependencies
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /livez [get]
+func (h *HealthChecker) HandleLivez(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// @Summary Readiness probe
+// @Description Checks the auth and chat backends and Redis; 503 if any required one is down. Optional dependencies that fail, such as an empty knowledge base, are reported as degraded with a 200
+// @Tags health
+// @Produce json
+// @Success 200 {object} ReadinessResponse
+// @Failure 503 {object} ReadinessResponse
+// @Router /readyz [get]
+func (h *HealthChecker) HandleReadyz(c *fiber.Ctx) error {
+	ready := h.Ready(c.UserContext())
+	status := fiber.StatusOK
+	if ready.Status == "unavailable" {
+		status = fiber.StatusServiceUnavailable
+	}
+	return c.Status(status).JSON(ready)
+}
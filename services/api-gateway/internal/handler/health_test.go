@@ -0,0 +1,152 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/pkg/grpcclient"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func healthApp(checker *handler.HealthChecker) *fiber.App {
+	app := fiber.New()
+	app.Get("/livez", checker.HandleLivez)
+	app.Get("/readyz", checker.HandleReadyz)
+	return app
+}
+
+func getReadyz(t *testing.T, app *fiber.App) (int, handler.ReadinessResponse) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.NoError(t, err)
+	var body handler.ReadinessResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func TestHealthChecker(t *testing.T) {
+	healthy := func(ctx context.Context) error { return nil }
+
+	t.Run("ready when every dependency is healthy", func(t *testing.T) {
+		checker := handler.NewHealthChecker(0, time.Second)
+		checker.Add("auth", healthy)
+		checker.Add("chat", healthy)
+		checker.Add("redis", healthy)
+
+		status, body := getReadyz(t, healthApp(checker))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, handler.ReadinessResponse{Status: "ok", Dependencies: map[string]handler.DependencyStatus{
+			"auth":  {Status: "ok"},
+			"chat":  {Status: "ok"},
+			"redis": {Status: "ok"},
+		}}, body)
+	})
+
+	t.Run("degraded when a dependency is down", func(t *testing.T) {
+		checker := handler.NewHealthChecker(0, time.Second)
+		checker.Add("auth", healthy)
+		checker.Add("chat", func(ctx context.Context) error { return errors.New("connection closed") })
+		app := healthApp(checker)
+
+		status, body := getReadyz(t, app)
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.Equal(t, "unavailable", body.Status)
+		assert.Equal(t, handler.DependencyStatus{Status: "down", Error: "connection closed"}, body.Dependencies["chat"])
+		assert.Equal(t, handler.DependencyStatus{Status: "ok"}, body.Dependencies["auth"])
+
+		// The process itself is still alive
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/livez", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("slow dependency times out", func(t *testing.T) {
+		checker := handler.NewHealthChecker(0, 20*time.Millisecond)
+		checker.Add("redis", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		status, body := getReadyz(t, healthApp(checker))
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.Equal(t, "down", body.Dependencies["redis"].Status)
+	})
+
+	t.Run("optional dependency that is down degrades without failing readiness", func(t *testing.T) {
+		checker := handler.NewHealthChecker(0, time.Second)
+		checker.Add("auth", healthy)
+		checker.AddOptional("redis", func(ctx context.Context) error { return errors.New("connection refused") })
+
+		status, body := getReadyz(t, healthApp(checker))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "degraded", body.Status)
+		assert.Equal(t, handler.DependencyStatus{Status: "degraded", Error: "connection refused"}, body.Dependencies["redis"])
+
+		// A required dependency being down still fails readiness
+		checker.Add("chat", func(ctx context.Context) error { return errors.New("connection closed") })
+		status, body = getReadyz(t, healthApp(checker))
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+		assert.Equal(t, "unavailable", body.Status)
+	})
+
+	t.Run("results are cached", func(t *testing.T) {
+		var calls atomic.Int32
+		checker := handler.NewHealthChecker(time.Hour, time.Second)
+		checker.Add("auth", func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		})
+		app := healthApp(checker)
+
+		for i := 0; i < 3; i++ {
+			status, _ := getReadyz(t, app)
+			assert.Equal(t, http.StatusOK, status)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestReadyz_BackendStartsAfterGateway(t *testing.T) {
+	// Reserve an address nothing listens on yet
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	// The gateway's clients are created while chat-gateway is down
+	chatClient, err := client.NewChatClient(addr, grpcclient.Balancing{Scheme: "passthrough"})
+	require.NoError(t, err)
+	t.Cleanup(func() { chatClient.Close() })
+
+	checker := handler.NewHealthChecker(0, 100*time.Millisecond)
+	checker.Add("chat", func(ctx context.Context) error { return client.CheckConn(ctx, chatClient.Conn()) })
+	app := healthApp(checker)
+
+	status, body := getReadyz(t, app)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "down", body.Dependencies["chat"].Status)
+
+	// chat-gateway comes up
+	lis, err = net.Listen("tcp", addr)
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	assert.Eventually(t, func() bool {
+		status, _ := getReadyz(t, app)
+		return status == http.StatusOK
+	}, 15*time.Second, 50*time.Millisecond, "the gateway never became ready")
+}
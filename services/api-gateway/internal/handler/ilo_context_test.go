@@ -0,0 +1,170 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	careerupv1 "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	llmpb "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeIloResultServer serves a three-question test and stores submitted
+// results as-is, keeping the last request
+type fakeIloResultServer struct {
+	careerupv1.UnimplementedIloServiceServer
+	submitted *careerupv1.SubmitIloTestResultRequest
+}
+
+func (s *fakeIloResultServer) GetIloTest(ctx context.Context, req *careerupv1.GetIloTestRequest) (*careerupv1.GetIloTestResponse, error) {
+	options := []string{"Không thích", "Ít thích", "Khá thích", "Rất thích"}
+	return &careerupv1.GetIloTestResponse{Questions: []*careerupv1.IloTestQuestion{
+		{Id: "q1", QuestionNumber: 1, DomainCode: "LOGIC", Options: options},
+		{Id: "q2", QuestionNumber: 2, DomainCode: "LANG", Options: options},
+		{Id: "q3", QuestionNumber: 3, DomainCode: "LOGIC", Options: options},
+	}}, nil
+}
+
+func (s *fakeIloResultServer) SubmitIloTestResult(ctx context.Context, req *careerupv1.SubmitIloTestResultRequest) (*careerupv1.SubmitIloTestResultResponse, error) {
+	s.submitted = req
+	return &careerupv1.SubmitIloTestResultResponse{Result: &careerupv1.IloTestResult{
+		Id:         "result-1",
+		UserId:     req.GetUserId(),
+		ResultData: req.GetRawResultData(),
+		Scores:     []*careerupv1.IloDomainScore{{DomainCode: "LOGIC", Percent: 80}},
+	}}, nil
+}
+
+// fakeAnalysisJSON is the analysis fakeLLMServer answers with
+const fakeAnalysisJSON = `{"overview":"Phân tích","strengths":["Tư duy logic"],"development_areas":["Giao tiếp"],` +
+	`"careers":[{"career_field":"Kỹ sư phần mềm","match_percent":90,"rationale":"Hợp với tư duy logic"}],"next_steps":["Học lập trình"]}`
+
+// fakeLLMServer records the prompt and answers with a fixed analysis
+type fakeLLMServer struct {
+	llmpb.UnimplementedLLMServiceServer
+	prompt string
+}
+
+func (s *fakeLLMServer) GenerateStructured(ctx context.Context, req *llmpb.GenerateStructuredRequest) (*llmpb.GenerateStructuredResponse, error) {
+	if req.GetSchema() != "ilo_analysis" {
+		return nil, status.Errorf(codes.InvalidArgument, "unexpected schema %q", req.GetSchema())
+	}
+	s.prompt = req.GetPrompt()
+	return &llmpb.GenerateStructuredResponse{Json: fakeAnalysisJSON, Method: "tool_call"}, nil
+}
+
+func newLLMClient(t *testing.T, srv llmpb.LLMServiceServer) *client.LLMClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	llmpb.RegisterLLMServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return client.NewLLMClient(conn)
+}
+
+func TestHandleIloTestResult_ChatContext(t *testing.T) {
+	conversation := &careerupv1.GetConversationResponse{
+		ConversationId: "conv-1",
+		UserId:         "user-1",
+		Messages: []*careerupv1.ConversationMessage{
+			{Role: "user", Text: "I love building robots"},
+			{Role: "assistant", Text: "Robotics is a great field!"},
+			{Role: "user", Text: "Should I   study\nmechatronics?"},
+		},
+	}
+
+	// submit posts an ILO result and returns the prompt the LLM received
+	submit := func(t *testing.T, maxChars int, body string, chat *handler.MockChatClient) string {
+		t.Helper()
+		authClient := handler.NewMockAuthClient()
+		authClient.On("ValidateToken", mock.Anything, "valid_token").Return(&client.User{ID: "user-1"}, nil)
+		llm := &fakeLLMServer{}
+		h := handler.NewHandler(authClient, chat, newIloClient(t, &fakeIloResultServer{}), newLLMClient(t, llm), "")
+		h.EnableIloChatContext(maxChars)
+
+		app := fiber.New()
+		app.Post("/api/v1/ilo/result", h.HandleIloTestResult)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/ilo/result", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer valid_token")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		return llm.prompt
+	}
+
+	shared := `{"answers":` + completeIloAnswers + `,"conversation_id":"conv-1","share_chat_context":true}`
+
+	t.Run("summary of the shared conversation is included", func(t *testing.T) {
+		chat := handler.NewMockChatClient()
+		chat.On("GetConversation", mock.Anything, "conv-1").Return(conversation, nil)
+
+		prompt := submit(t, 1500, shared, chat)
+		assert.Contains(t, prompt, "Recent career chat with the candidate")
+		assert.Contains(t, prompt, "- I love building robots\n- Should I study mechatronics?")
+		assert.NotContains(t, prompt, "Robotics is a great field")
+	})
+
+	t.Run("summary keeps the newest messages within the budget", func(t *testing.T) {
+		chat := handler.NewMockChatClient()
+		chat.On("GetConversation", mock.Anything, "conv-1").Return(conversation, nil)
+
+		prompt := submit(t, 40, shared, chat)
+		assert.Contains(t, prompt, "oldest first); use it to personalise the advice:\n- Should I study mechatronics?\n\nRaw ILO data")
+
+		prompt = submit(t, 20, shared, chat)
+		assert.Contains(t, prompt, "advice:\n- Should I study…\n\nRaw ILO data")
+	})
+
+	t.Run("omitted without consent", func(t *testing.T) {
+		chat := handler.NewMockChatClient()
+		prompt := submit(t, 1500, `{"answers":`+completeIloAnswers+`,"conversation_id":"conv-1"}`, chat)
+		assert.NotContains(t, prompt, "Recent career chat")
+		chat.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("omitted when disabled", func(t *testing.T) {
+		chat := handler.NewMockChatClient()
+		prompt := submit(t, 0, shared, chat)
+		assert.NotContains(t, prompt, "Recent career chat")
+		chat.AssertNotCalled(t, "GetConversation", mock.Anything, mock.Anything)
+	})
+
+	t.Run("omitted when the conversation is unavailable", func(t *testing.T) {
+		chat := handler.NewMockChatClient()
+		chat.On("GetConversation", mock.Anything, "conv-1").Return(nil, status.Error(codes.Unavailable, "down"))
+		prompt := submit(t, 1500, shared, chat)
+		assert.NotContains(t, prompt, "Recent career chat")
+		assert.True(t, strings.HasSuffix(prompt, "<ilo_data>\n"+completeIloRawResult+"\n</ilo_data>"))
+	})
+
+	t.Run("omitted for another user's conversation", func(t *testing.T) {
+		chat := handler.NewMockChatClient()
+		other := &careerupv1.GetConversationResponse{UserId: "user-2", Messages: conversation.Messages}
+		chat.On("GetConversation", mock.Anything, "conv-1").Return(other, nil)
+		prompt := submit(t, 1500, shared, chat)
+		assert.NotContains(t, prompt, "robots")
+	})
+}
@@ -0,0 +1,184 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	careerupv1 "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	llmpb "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// dedupIloServer saves each idempotency key's first submission and answers
+// repeats with it, as the ILO service does
+type dedupIloServer struct {
+	fakeIloResultServer
+	mu    sync.Mutex
+	keys  []string
+	saved map[string]*careerupv1.IloTestResult
+}
+
+func (s *dedupIloServer) SubmitIloTestResult(ctx context.Context, req *careerupv1.SubmitIloTestResultRequest) (*careerupv1.SubmitIloTestResultResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, req.GetIdempotencyKey())
+	if result, ok := s.saved[req.GetIdempotencyKey()]; ok {
+		return &careerupv1.SubmitIloTestResultResponse{Result: result, Duplicate: true}, nil
+	}
+	resp, err := s.fakeIloResultServer.SubmitIloTestResult(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Result.Id = "result-" + strconv.Itoa(len(s.saved)+1)
+	s.saved[req.GetIdempotencyKey()] = resp.GetResult()
+	return resp, nil
+}
+
+func (s *dedupIloServer) SaveIloTestResultAnalysis(ctx context.Context, req *careerupv1.SaveIloTestResultAnalysisRequest) (*careerupv1.SaveIloTestResultAnalysisResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, result := range s.saved {
+		if result.GetId() == req.GetResultId() {
+			result.Analysis = req.GetAnalysis()
+		}
+	}
+	return &careerupv1.SaveIloTestResultAnalysisResponse{}, nil
+}
+
+// forgetAnalyses drops the saved analyses, as when saving them failed
+func (s *dedupIloServer) forgetAnalyses() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, result := range s.saved {
+		result.Analysis = ""
+	}
+}
+
+// countingLLMServer counts the analyses it is asked for
+type countingLLMServer struct {
+	fakeLLMServer
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *countingLLMServer) GenerateStructured(ctx context.Context, req *llmpb.GenerateStructuredRequest) (*llmpb.GenerateStructuredResponse, error) {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	return s.fakeLLMServer.GenerateStructured(ctx, req)
+}
+
+func TestHandleIloTestResult_Idempotency(t *testing.T) {
+	type submitResponse struct {
+		Result    handler.IloTestResultResponse `json:"result"`
+		Analysis  client.IloAnalysis            `json:"analysis"`
+		Duplicate bool                          `json:"duplicate"`
+	}
+
+	newApp := func(t *testing.T) (*fiber.App, *dedupIloServer, *countingLLMServer) {
+		t.Helper()
+		authClient := handler.NewMockAuthClient()
+		authClient.On("ValidateToken", mock.Anything, "valid_token").Return(&client.User{ID: "user-1"}, nil)
+		ilo := &dedupIloServer{saved: map[string]*careerupv1.IloTestResult{}}
+		llm := &countingLLMServer{}
+		h := handler.NewHandler(authClient, handler.NewMockChatClient(), newIloClient(t, ilo), newLLMClient(t, llm), "")
+		app := fiber.New()
+		app.Post("/api/v1/ilo/result", h.HandleIloTestResult)
+		return app, ilo, llm
+	}
+
+	submit := func(t *testing.T, app *fiber.App, key string) (int, submitResponse) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/ilo/result",
+			bytes.NewBufferString(`{"answers":`+completeIloAnswers+`}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer valid_token")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var out submitResponse
+		if resp.StatusCode < 300 {
+			require.NoError(t, json.Unmarshal(body, &out), string(body))
+		}
+		return resp.StatusCode, out
+	}
+
+	t.Run("a repeated submission returns the original result and analysis", func(t *testing.T) {
+		app, ilo, llm := newApp(t)
+
+		status, first := submit(t, app, "attempt-1")
+		require.Equal(t, fiber.StatusCreated, status)
+		assert.False(t, first.Duplicate)
+		assert.Equal(t, "Phân tích", first.Analysis.Overview)
+
+		status, retry := submit(t, app, "attempt-1")
+		require.Equal(t, fiber.StatusOK, status)
+		assert.True(t, retry.Duplicate)
+		assert.Equal(t, first.Result.ID, retry.Result.ID)
+		assert.Equal(t, first.Analysis, retry.Analysis)
+
+		assert.Equal(t, []string{"attempt-1", "attempt-1"}, ilo.keys)
+		assert.Equal(t, 1, llm.calls, "the retry must not be analysed again")
+	})
+
+	t.Run("a repeat without a saved analysis is analysed again", func(t *testing.T) {
+		app, ilo, llm := newApp(t)
+
+		status, first := submit(t, app, "attempt-1")
+		require.Equal(t, fiber.StatusCreated, status)
+		ilo.forgetAnalyses()
+
+		status, retry := submit(t, app, "attempt-1")
+		require.Equal(t, fiber.StatusOK, status)
+		assert.True(t, retry.Duplicate)
+		assert.Equal(t, first.Result.ID, retry.Result.ID)
+		assert.Equal(t, first.Analysis, retry.Analysis)
+		assert.Equal(t, 2, llm.calls)
+	})
+
+	t.Run("submissions with different keys are both saved", func(t *testing.T) {
+		app, _, llm := newApp(t)
+
+		status, first := submit(t, app, "attempt-1")
+		require.Equal(t, fiber.StatusCreated, status)
+		status, second := submit(t, app, "attempt-2")
+		require.Equal(t, fiber.StatusCreated, status)
+
+		assert.NotEqual(t, first.Result.ID, second.Result.ID)
+		assert.Equal(t, 2, llm.calls)
+	})
+
+	t.Run("without a key the ILO service derives one", func(t *testing.T) {
+		app, ilo, _ := newApp(t)
+
+		status, _ := submit(t, app, "")
+		require.Equal(t, fiber.StatusCreated, status)
+		assert.Equal(t, []string{""}, ilo.keys)
+	})
+
+	t.Run("an overlong key is rejected", func(t *testing.T) {
+		app, ilo, llm := newApp(t)
+
+		status, _ := submit(t, app, strings.Repeat("k", 129))
+		assert.Equal(t, fiber.StatusBadRequest, status)
+		assert.Empty(t, ilo.keys)
+		assert.Zero(t, llm.calls)
+	})
+}
@@ -0,0 +1,317 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	careerupv1 "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/i18n"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeIloServer answers career suggestion requests with a canned response
+type fakeIloServer struct {
+	careerupv1.UnimplementedIloServiceServer
+	suggestions []*careerupv1.IloCareerSuggestion
+}
+
+func (s *fakeIloServer) GetIloCareerSuggestions(ctx context.Context, req *careerupv1.GetIloCareerSuggestionsRequest) (*careerupv1.GetIloCareerSuggestionsResponse, error) {
+	return &careerupv1.GetIloCareerSuggestionsResponse{Suggestions: s.suggestions}, nil
+}
+
+// newIloClient serves srv over an in-memory connection
+func newIloClient(t *testing.T, srv careerupv1.IloServiceServer) *client.IloClient {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	careerupv1.RegisterIloServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return client.NewIloClient(conn)
+}
+
+func TestHandleGetIloCareerSuggestions(t *testing.T) {
+	iloClient := newIloClient(t, &fakeIloServer{suggestions: []*careerupv1.IloCareerSuggestion{
+		{
+			DomainCode:      "LOGIC",
+			CareerField:     "Data Science",
+			MatchPercent:    75,
+			Rationale:       "Draws on your strengths in Logic, Language.",
+			RequiredDomains: []string{"LOGIC", "LANG"},
+		},
+		{DomainCode: "LANG", CareerField: "Journalism", MatchPercent: 60, RequiredDomains: []string{"LANG"}},
+	}})
+	h := handler.NewHandler(handler.NewMockAuthClient(), handler.NewMockChatClient(), iloClient, nil, "")
+	app := fiber.New()
+	app.Post("/api/v1/ilo/careers", h.HandleGetIloCareerSuggestions)
+
+	post := func(body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/ilo/careers", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("returns detailed suggestions and career names", func(t *testing.T) {
+		resp := post(`{"domain_codes":["LOGIC","LANG"],"scores":[{"domain_code":"LOGIC","percent":90}],"limit":5}`)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body handler.IloCareerSuggestionsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, []string{"Data Science", "Journalism"}, body.Careers)
+		require.Len(t, body.Suggestions, 2)
+		assert.Equal(t, client.IloCareerSuggestion{
+			CareerField:     "Data Science",
+			DomainCode:      "LOGIC",
+			MatchPercent:    75,
+			Rationale:       "Draws on your strengths in Logic, Language.",
+			RequiredDomains: []string{"LOGIC", "LANG"},
+		}, body.Suggestions[0])
+	})
+
+	t.Run("requires domain codes", func(t *testing.T) {
+		resp := post(`{"limit":5}`)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+// fakeIloResultsServer lists a fixed set of results for any user
+type fakeIloResultsServer struct {
+	careerupv1.UnimplementedIloServiceServer
+	results []*careerupv1.IloTestResult
+}
+
+func (s *fakeIloResultsServer) GetIloTestResults(ctx context.Context, req *careerupv1.GetIloTestResultsRequest) (*careerupv1.GetIloTestResultsResponse, error) {
+	return &careerupv1.GetIloTestResultsResponse{Results: s.results}, nil
+}
+
+func TestHandleGetIloResults_Pagination(t *testing.T) {
+	srv := &fakeIloResultsServer{}
+	for _, id := range []string{"r1", "r2", "r3"} {
+		srv.results = append(srv.results, &careerupv1.IloTestResult{Id: id, UserId: "user-1"})
+	}
+	authClient := handler.NewMockAuthClient()
+	authClient.On("ValidateToken", mock.Anything, "valid_token").Return(&client.User{ID: "user-1"}, nil)
+	h := handler.NewHandler(authClient, handler.NewMockChatClient(), newIloClient(t, srv), nil, "")
+	app := fiber.New()
+	app.Get("/api/v1/ilo/results", h.HandleGetIloResults)
+
+	get := func(query string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/ilo/results"+query, nil)
+		req.Header.Set("Authorization", "Bearer valid_token")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+	ids := func(results []handler.IloTestResultResponse) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		resp := get("?limit=2")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		var body handler.IloTestResultsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, []string{"r1", "r2"}, ids(body.Data))
+		assert.Equal(t, 3, body.Pagination.Total)
+		assert.Equal(t, 2, body.Pagination.Limit)
+		assert.Equal(t, 0, body.Pagination.Offset)
+		require.NotNil(t, body.Pagination.Next)
+		assert.Equal(t, body.Data, body.Results)
+		assert.NotEmpty(t, body.Copyright)
+
+		resp = get("?limit=2&page_token=" + *body.Pagination.Next)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, []string{"r3"}, ids(body.Data))
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		resp := get("?limit=2&offset=2")
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Len(t, body["data"], 1)
+		pagination := body["pagination"].(map[string]any)
+		assert.Nil(t, pagination["next"])
+		assert.Contains(t, pagination, "next")
+	})
+
+	t.Run("rejects a bad limit", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusBadRequest, get("?limit=-1").StatusCode)
+	})
+}
+
+func TestIloCopyrightFollowsAcceptLanguage(t *testing.T) {
+	const (
+		vietnamese = "Thang đo ILO © ILO Vietnam 2020 – sử dụng cho mục đích hướng nghiệp, trích dẫn có ghi nguồn."
+		english    = "ILO scale © ILO Vietnam 2020 – for career guidance use; cite the source when quoting."
+	)
+	get := func(t *testing.T, h *handler.Handler, acceptLanguage string) (*http.Response, string) {
+		t.Helper()
+		app := fiber.New()
+		app.Get("/api/v1/ilo/test", h.HandleGetIloTest)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/ilo/test", nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		var body struct {
+			Copyright string `json:"copyright"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp, body.Copyright
+	}
+	newHandler := func(t *testing.T) *handler.Handler {
+		return handler.NewHandler(handler.NewMockAuthClient(), handler.NewMockChatClient(), newIloClient(t, &fakeIloResultServer{}), nil, "")
+	}
+
+	t.Run("English clients get English", func(t *testing.T) {
+		resp, copyright := get(t, newHandler(t), "en-US,en;q=0.9")
+		assert.Equal(t, english, copyright)
+		assert.Equal(t, "en", resp.Header.Get("Content-Language"))
+		assert.Contains(t, resp.Header.Get("Vary"), "Accept-Language")
+	})
+
+	t.Run("defaults to Vietnamese", func(t *testing.T) {
+		for _, header := range []string{"", "fr-FR"} {
+			resp, copyright := get(t, newHandler(t), header)
+			assert.Equal(t, vietnamese, copyright, header)
+			assert.Equal(t, "vi", resp.Header.Get("Content-Language"))
+		}
+	})
+
+	t.Run("configured default language", func(t *testing.T) {
+		h := newHandler(t)
+		catalog, err := i18n.New(i18n.English)
+		require.NoError(t, err)
+		h.SetCatalog(catalog)
+
+		_, copyright := get(t, h, "")
+		assert.Equal(t, english, copyright)
+		_, copyright = get(t, h, "vi")
+		assert.Equal(t, vietnamese, copyright)
+	})
+}
+
+// fakeStoredIloServer holds one stored result and, like the ILO service,
+// refuses to return it to anyone but its owner
+type fakeStoredIloServer struct {
+	careerupv1.UnimplementedIloServiceServer
+	result *careerupv1.IloTestResult
+}
+
+func (s *fakeStoredIloServer) GetIloTestResult(ctx context.Context, req *careerupv1.GetIloTestResultRequest) (*careerupv1.GetIloTestResultResponse, error) {
+	if req.GetResultId() != s.result.GetId() {
+		return nil, status.Error(codes.NotFound, "result not found")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if userID := md.Get("user-id"); len(userID) == 0 || userID[0] != s.result.GetUserId() {
+		return nil, status.Error(codes.PermissionDenied, "not your result")
+	}
+	return &careerupv1.GetIloTestResultResponse{Result: s.result}, nil
+}
+
+func TestHandleReanalyzeIloResult(t *testing.T) {
+	stored := &careerupv1.IloTestResult{
+		Id:         "result-1",
+		UserId:     "user-1",
+		ResultData: `{"totals":{"LOGIC":11,"LANG":4}}`,
+		Scores: []*careerupv1.IloDomainScore{
+			{DomainCode: "LOGIC", Percent: 91.5, Level: "High"},
+			{DomainCode: "LANG", Percent: 33, Level: "Low"},
+		},
+		TopDomains: []string{"LOGIC"},
+	}
+
+	// analyze posts a reanalysis request as userID and returns the response
+	// and the prompt the LLM received
+	analyze := func(t *testing.T, userID, path string) (*http.Response, string) {
+		t.Helper()
+		authClient := handler.NewMockAuthClient()
+		authClient.On("ValidateToken", mock.Anything, "valid_token").Return(&client.User{ID: userID, FirstName: "Lan"}, nil)
+		llm := &fakeLLMServer{}
+		h := handler.NewHandler(authClient, handler.NewMockChatClient(), newIloClient(t, &fakeStoredIloServer{result: stored}), newLLMClient(t, llm), "")
+
+		app := fiber.New()
+		app.Post("/api/v1/ilo/result/:id/analyze", h.HandleReanalyzeIloResult)
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer valid_token")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp, llm.prompt
+	}
+
+	t.Run("owner gets an analysis regenerated from the stored scores", func(t *testing.T) {
+		resp, prompt := analyze(t, "user-1", "/api/v1/ilo/result/result-1/analyze")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body struct {
+			Result    handler.IloTestResultResponse `json:"result"`
+			Analysis  client.IloAnalysis            `json:"analysis"`
+			Copyright string                        `json:"copyright"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "result-1", body.Result.ID)
+		assert.Equal(t, "Phân tích", body.Analysis.Overview)
+		assert.Equal(t, []client.CareerSuggestion{{CareerField: "Kỹ sư phần mềm", MatchPercent: 90, Rationale: "Hợp với tư duy logic"}}, body.Analysis.Careers)
+		assert.NotEmpty(t, body.Copyright)
+
+		assert.Contains(t, prompt, "ILO Domain Scores:\n- LOGIC: 91.5% (High)\n- LANG: 33.0% (Low)")
+		assert.Contains(t, prompt, "Top domains: LOGIC")
+		assert.Contains(t, prompt, "Candidate first name: Lan")
+		assert.Contains(t, prompt, "<ilo_data>\n"+`{"totals":{"LOGIC":11,"LANG":4}}`+"\n</ilo_data>")
+	})
+
+	t.Run("streams the analysis", func(t *testing.T) {
+		resp, prompt := analyze(t, "user-1", "/api/v1/ilo/result/result-1/analyze?stream=true")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		events, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "event: analysis\ndata: "+fakeAnalysisJSON+"\n\nevent: done\ndata: \n\n", string(events))
+		assert.Contains(t, prompt, "- LOGIC: 91.5% (High)")
+	})
+
+	t.Run("another user's result is forbidden", func(t *testing.T) {
+		resp, prompt := analyze(t, "user-2", "/api/v1/ilo/result/result-1/analyze")
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+		assert.Empty(t, prompt, "nothing is sent to the LLM")
+	})
+
+	t.Run("unknown result", func(t *testing.T) {
+		resp, prompt := analyze(t, "user-1", "/api/v1/ilo/result/result-9/analyze")
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Empty(t, prompt)
+	})
+}
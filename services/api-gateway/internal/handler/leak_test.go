@@ -0,0 +1,90 @@
+package handler_test
+
+import (
+	"testing"
+	"time"
+
+	chatpb "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	fastws "github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// verifyNoLeaks fails t if goroutines started after this call still run
+// once the test and its cleanups, which stop the servers dialChat starts,
+// are done. Call it before dialChat. fasthttp's process-wide date ticker
+// and the idle worker reaper, which sleeps out its interval after Shutdown,
+// are not the proxy's.
+func verifyNoLeaks(t *testing.T) {
+	t.Helper()
+	running := goleak.IgnoreCurrent()
+	t.Cleanup(func() {
+		goleak.VerifyNone(t, running,
+			goleak.IgnoreAnyFunction("github.com/valyala/fasthttp.updateServerDate.func1"),
+			goleak.IgnoreAnyFunction("github.com/valyala/fasthttp.(*workerPool).Start.func2"))
+	})
+}
+
+// endingChatServer answers the first stream request and ends the stream.
+type endingChatServer struct {
+	chatpb.UnimplementedConversationServiceServer
+}
+
+func (endingChatServer) Stream(stream chatpb.ConversationService_StreamServer) error {
+	if _, err := stream.Recv(); err != nil {
+		return err
+	}
+	return stream.Send(&chatpb.StreamResponse{Type: "status", Content: &chatpb.StreamResponse_Status{Status: "done"}})
+}
+
+func TestWebSocketProxy_NoGoroutineLeaks(t *testing.T) {
+	userMsg := handler.ClientMessage{Type: "user_msg", ConversationID: "conv-1", Text: "Hello"}
+	reply := &chatpb.StreamResponse{Type: "assistant_token", Content: &chatpb.StreamResponse_Token{Token: "Hi"}}
+
+	t.Run("client closes normally", func(t *testing.T) {
+		verifyNoLeaks(t)
+		ws := dialChat(t, &fakeChatServer{reply: reply})
+		require.NoError(t, ws.WriteJSON(userMsg))
+		require.Equal(t, "Hi", readServerMessage(t, ws).Token)
+
+		closing := fastws.FormatCloseMessage(fastws.CloseNormalClosure, "")
+		require.NoError(t, ws.WriteControl(fastws.CloseMessage, closing, time.Now().Add(time.Second)))
+	})
+
+	t.Run("client disconnects mid-answer", func(t *testing.T) {
+		verifyNoLeaks(t)
+		ws := dialChat(t, &fakeChatServer{reply: reply})
+		require.NoError(t, ws.WriteJSON(userMsg))
+		require.Equal(t, "Hi", readServerMessage(t, ws).Token)
+		require.NoError(t, ws.Close())
+	})
+
+	t.Run("chat stream fails", func(t *testing.T) {
+		verifyNoLeaks(t)
+		ws := dialChat(t, &fakeChatServer{err: status.Error(codes.Internal, "stream failed")})
+		require.NoError(t, ws.WriteJSON(userMsg))
+		require.Equal(t, handler.ErrorCodeChatError, readServerMessage(t, ws).ErrorCode)
+	})
+
+	t.Run("chat stream ends", func(t *testing.T) {
+		verifyNoLeaks(t)
+		ws := dialChat(t, endingChatServer{})
+		require.NoError(t, ws.WriteJSON(userMsg))
+		require.Equal(t, "done", readServerMessage(t, ws).Status)
+	})
+
+	t.Run("slow client is disconnected", func(t *testing.T) {
+		verifyNoLeaks(t)
+		srv := newFloodChatServer(500)
+		ws := dialChat(t, srv, outboundBuffer(4, handler.OverflowClose))
+		require.NoError(t, ws.WriteJSON(userMsg))
+		select {
+		case <-srv.ended:
+		case <-time.After(10 * time.Second):
+			t.Fatal("the chat stream was still held up by a client that does not read")
+		}
+	})
+}
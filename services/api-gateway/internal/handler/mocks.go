@@ -86,6 +86,42 @@ func (m *MockChatClient) GetChatServiceClient() chatpb.ConversationServiceClient
 	return args.Get(0).(chatpb.ConversationServiceClient)
 }
 
+// GetConversation implements ChatClientInterface
+func (m *MockChatClient) GetConversation(ctx context.Context, conversationID string) (*chatpb.GetConversationResponse, error) {
+	args := m.Called(ctx, conversationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chatpb.GetConversationResponse), args.Error(1)
+}
+
+// ListConversations implements ChatClientInterface
+func (m *MockChatClient) ListConversations(ctx context.Context, includeArchived bool) ([]*chatpb.ConversationInfo, error) {
+	args := m.Called(ctx, includeArchived)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*chatpb.ConversationInfo), args.Error(1)
+}
+
+// SetConversationArchived implements ChatClientInterface
+func (m *MockChatClient) SetConversationArchived(ctx context.Context, conversationID string, archived bool) (*chatpb.ConversationInfo, error) {
+	args := m.Called(ctx, conversationID, archived)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chatpb.ConversationInfo), args.Error(1)
+}
+
+// ResetConversation implements ChatClientInterface
+func (m *MockChatClient) ResetConversation(ctx context.Context, conversationID string) (*chatpb.ConversationInfo, error) {
+	args := m.Called(ctx, conversationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*chatpb.ConversationInfo), args.Error(1)
+}
+
 // Close implements ChatClientInterface
 func (m *MockChatClient) Close() error {
 	args := m.Called()
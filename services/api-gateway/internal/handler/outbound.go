@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// OverflowPolicy is what the WebSocket proxy does with a message for a
+// client whose outbound buffer is full.
+type OverflowPolicy string
+
+const (
+	// OverflowClose disconnects the client, which can reconnect and reload
+	// the conversation
+	OverflowClose OverflowPolicy = "close"
+	// OverflowDrop discards the message and keeps the connection
+	OverflowDrop OverflowPolicy = "drop"
+)
+
+// DefaultOutboundBuffer is how many messages may wait for a WebSocket client
+// unless configured otherwise.
+const DefaultOutboundBuffer = 256
+
+// outboundDrainTimeout bounds how long closing a queue keeps writing the
+// messages still buffered, e.g. the error explaining why the connection
+// ends.
+const outboundDrainTimeout = 2 * time.Second
+
+// errOutboundOverflow is returned by sends once a client's buffer overflowed
+// under OverflowClose.
+var errOutboundOverflow = errors.New("websocket client is too slow, outbound buffer full")
+
+// outboundQueue hands server messages to a goroutine that writes them to a
+// WebSocket client, so a client that reads slowly holds up its own writes
+// only, not the gRPC stream feeding them. Sends never block: a message that
+// does not fit in the buffer is handled by the overflow policy.
+type outboundQueue struct {
+	msgs chan ServerMessage
+	quit chan struct{}
+	// done is closed when the writer exits
+	done         chan struct{}
+	drainTimeout time.Duration
+	policy       OverflowPolicy
+	onOverflow   func()
+
+	mu sync.Mutex
+	// err is the first failed write or the overflow; later sends return it
+	err     error
+	dropped int
+	closed  bool
+	// drain is set by Close unless the queue had failed already
+	drain bool
+}
+
+// newOutboundQueue starts writing messages with write, buffering up to size
+// of them. Under OverflowClose, onOverflow is called once when the buffer
+// overflows, and should disconnect the client.
+func newOutboundQueue(write func(ServerMessage) error, size int, policy OverflowPolicy, onOverflow func()) *outboundQueue {
+	if size <= 0 {
+		size = DefaultOutboundBuffer
+	}
+	if policy == "" {
+		policy = OverflowClose
+	}
+	q := &outboundQueue{
+		msgs:         make(chan ServerMessage, size),
+		quit:         make(chan struct{}),
+		done:         make(chan struct{}),
+		drainTimeout: outboundDrainTimeout,
+		policy:       policy,
+		onOverflow:   onOverflow,
+	}
+	go q.run(write)
+	return q
+}
+
+func (q *outboundQueue) run(write func(ServerMessage) error) {
+	defer close(q.done)
+	for {
+		select {
+		case <-q.quit:
+			q.mu.Lock()
+			drain := q.drain
+			q.mu.Unlock()
+			if drain {
+				q.drainBuffered(write)
+			}
+			return
+		case msg := <-q.msgs:
+			if err := write(msg); err != nil {
+				q.fail(err)
+				return
+			}
+		}
+	}
+}
+
+// Send queues msg for writing. It fails once a write has failed, the queue
+// is closed, or the buffer overflowed under OverflowClose.
+func (q *outboundQueue) Send(msg ServerMessage) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.err != nil {
+		return q.err
+	}
+	select {
+	case q.msgs <- msg:
+		return nil
+	default:
+	}
+
+	if q.policy == OverflowDrop {
+		q.dropped++
+		return nil
+	}
+	q.err = errOutboundOverflow
+	if q.onOverflow != nil {
+		go q.onOverflow()
+	}
+	return q.err
+}
+
+// Dropped returns how many messages were discarded under OverflowDrop.
+func (q *outboundQueue) Dropped() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// drainBuffered writes the messages still buffered, until the buffer is
+// empty, a write fails or the drain timeout passes.
+func (q *outboundQueue) drainBuffered(write func(ServerMessage) error) {
+	deadline := time.Now().Add(q.drainTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case msg := <-q.msgs:
+			if err := write(msg); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// Close stops taking messages and waits, up to the drain timeout, for the
+// ones still buffered to be written; later sends fail. A queue whose writes
+// failed or that overflowed under OverflowClose is not drained.
+func (q *outboundQueue) Close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	if q.err == nil {
+		q.err = errConnectionClosed
+		q.drain = true
+	}
+	close(q.quit)
+	q.mu.Unlock()
+
+	timer := time.NewTimer(q.drainTimeout)
+	defer timer.Stop()
+	select {
+	case <-q.done:
+	case <-timer.C:
+		// A write to a client that stopped reading ends when the
+		// connection is closed
+	}
+}
+
+func (q *outboundQueue) fail(err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.err == nil {
+		q.err = err
+	}
+}
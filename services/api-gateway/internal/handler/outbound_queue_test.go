@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboundQueue_Close(t *testing.T) {
+	t.Run("buffered messages are written before closing", func(t *testing.T) {
+		release := make(chan struct{})
+		var mu sync.Mutex
+		var written []string
+		q := newOutboundQueue(func(msg ServerMessage) error {
+			<-release
+			mu.Lock()
+			defer mu.Unlock()
+			written = append(written, msg.Type)
+			return nil
+		}, 8, OverflowClose, nil)
+
+		require.NoError(t, q.Send(ServerMessage{Type: "assistant_token"}))
+		require.NoError(t, q.Send(ServerMessage{Type: "error"}))
+		close(release)
+		q.Close()
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{"assistant_token", "error"}, written)
+		assert.ErrorIs(t, q.Send(ServerMessage{Type: "status"}), errConnectionClosed)
+	})
+
+	t.Run("draining a client that stopped reading is bounded", func(t *testing.T) {
+		stuck := make(chan struct{})
+		defer close(stuck)
+		q := newOutboundQueue(func(ServerMessage) error {
+			<-stuck
+			return nil
+		}, 8, OverflowClose, nil)
+		q.drainTimeout = 50 * time.Millisecond
+
+		for range 3 {
+			require.NoError(t, q.Send(ServerMessage{Type: "assistant_token"}))
+		}
+		start := time.Now()
+		q.Close()
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("a failed queue is not drained", func(t *testing.T) {
+		var mu sync.Mutex
+		writes := 0
+		failed := make(chan struct{})
+		q := newOutboundQueue(func(ServerMessage) error {
+			mu.Lock()
+			defer mu.Unlock()
+			writes++
+			close(failed)
+			return errConnectionClosed
+		}, 8, OverflowClose, nil)
+
+		require.NoError(t, q.Send(ServerMessage{Type: "assistant_token"}))
+		<-failed
+		_ = q.Send(ServerMessage{Type: "assistant_token"})
+		q.Close()
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 1, writes)
+	})
+}
@@ -0,0 +1,112 @@
+package handler_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	chatpb "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	fastws "github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// floodChatServer answers the first stream request with more tokens than a
+// client that never reads can take in, then keeps the stream open. sent is
+// closed once every token went out, ended when the stream is over.
+type floodChatServer struct {
+	chatpb.UnimplementedConversationServiceServer
+	tokens int
+	sent   chan struct{}
+	ended  chan struct{}
+}
+
+func newFloodChatServer(tokens int) *floodChatServer {
+	return &floodChatServer{tokens: tokens, sent: make(chan struct{}), ended: make(chan struct{})}
+}
+
+func (s *floodChatServer) Stream(stream chatpb.ConversationService_StreamServer) error {
+	defer close(s.ended)
+	if _, err := stream.Recv(); err != nil {
+		return err
+	}
+	// Big enough that the socket buffers fill well before the last token
+	text := strings.Repeat("x", 64*1024)
+	for i := 0; i < s.tokens; i++ {
+		if err := stream.Send(&chatpb.StreamResponse{
+			Type:    "assistant_token",
+			Content: &chatpb.StreamResponse_Token{Token: text},
+		}); err != nil {
+			return err
+		}
+	}
+	close(s.sent)
+	<-stream.Context().Done()
+	return nil
+}
+
+func outboundBuffer(size int, policy handler.OverflowPolicy) func(*handler.Handler) {
+	return func(h *handler.Handler) { h.SetOutboundBuffer(size, policy) }
+}
+
+func TestWebSocketProxy_SlowClient(t *testing.T) {
+	userMsg := handler.ClientMessage{Type: "user_msg", ConversationID: "conv-1", Text: "Hello"}
+
+	t.Run("closes a client that falls behind", func(t *testing.T) {
+		srv := newFloodChatServer(500)
+		ws := dialChat(t, srv, outboundBuffer(4, handler.OverflowClose))
+		require.NoError(t, ws.WriteJSON(userMsg))
+
+		// The client reads nothing, so the buffer fills and the proxy hangs
+		// up instead of holding the chat stream back for good
+		select {
+		case <-srv.ended:
+		case <-time.After(10 * time.Second):
+			t.Fatal("the chat stream was still held up by a client that does not read")
+		}
+
+		// What made it into the socket before the close can still be read
+		require.NoError(t, ws.SetReadDeadline(time.Now().Add(10*time.Second)))
+		var err error
+		for err == nil {
+			_, _, err = ws.ReadMessage()
+		}
+		var netErr net.Error
+		if assert.Error(t, err) && assert.NotErrorAs(t, err, &netErr, "the connection should be closed, not time out") {
+			if closeErr, ok := err.(*fastws.CloseError); ok {
+				assert.Equal(t, fastws.CloseTryAgainLater, closeErr.Code)
+			}
+		}
+	})
+
+	t.Run("drops messages for a client that falls behind", func(t *testing.T) {
+		srv := newFloodChatServer(500)
+		ws := dialChat(t, srv, outboundBuffer(4, handler.OverflowDrop))
+		require.NoError(t, ws.WriteJSON(userMsg))
+
+		select {
+		case <-srv.sent:
+		case <-time.After(10 * time.Second):
+			t.Fatal("the chat stream was held up by a client that does not read")
+		}
+
+		// The connection stays up, with the messages that fit
+		var received int
+		require.NoError(t, ws.SetReadDeadline(time.Now().Add(2*time.Second)))
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				break
+			}
+			received++
+		}
+		assert.Positive(t, received)
+		assert.Less(t, received, 500)
+		select {
+		case <-srv.ended:
+			t.Fatal("the chat stream ended although the client is still connected")
+		default:
+		}
+	})
+}
@@ -44,7 +44,7 @@ func UpdateProfile(c *fiber.Ctx) error {
 		})
 	}
 
-	updatedUser, err := authClient.UpdateUser(c.Context(), &req)
+	updatedUser, err := authClient.UpdateUser(c.UserContext(), &req)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update profile: " + err.Error(),
@@ -0,0 +1,96 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// updateProfile sends body with method and returns the response status and
+// the update passed to the auth service, if any
+func updateProfile(t *testing.T, method, body string) (int, *client.UpdateUserRequest) {
+	t.Helper()
+	var sent *client.UpdateUserRequest
+	authClient := handler.NewMockAuthClient()
+	authClient.On("UpdateUser", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(*client.UpdateUserRequest) }).
+		Return(&client.User{ID: "user-1"}, nil)
+	h := handler.NewHandler(authClient, nil, nil, nil, "")
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", &client.User{ID: "user-1"})
+		return c.Next()
+	})
+	app.Put("/api/v1/profile", h.HandleUpdateProfile)
+	app.Patch("/api/v1/profile", h.HandlePatchProfile)
+
+	req := httptest.NewRequest(method, "/api/v1/profile", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer valid_token")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode, sent
+}
+
+func TestHandlePatchProfile(t *testing.T) {
+	t.Run("omitted fields are left unchanged", func(t *testing.T) {
+		code, sent := updateProfile(t, http.MethodPatch, `{"hometown":"Da Nang"}`)
+		require.Equal(t, fiber.StatusOK, code)
+		assert.Equal(t, "valid_token", sent.Token)
+		require.NotNil(t, sent.Hometown)
+		assert.Equal(t, "Da Nang", *sent.Hometown)
+		assert.Nil(t, sent.FirstName)
+		assert.Nil(t, sent.LastName)
+		assert.Nil(t, sent.Interests)
+	})
+
+	t.Run("empty values clear fields", func(t *testing.T) {
+		code, sent := updateProfile(t, http.MethodPatch, `{"hometown":"","interests":[]}`)
+		require.Equal(t, fiber.StatusOK, code)
+		require.NotNil(t, sent.Hometown)
+		assert.Empty(t, *sent.Hometown)
+		require.NotNil(t, sent.Interests)
+		assert.Empty(t, *sent.Interests)
+		assert.Nil(t, sent.FirstName)
+	})
+
+	t.Run("null clears fields", func(t *testing.T) {
+		code, sent := updateProfile(t, http.MethodPatch, `{"hometown":null,"interests":null}`)
+		require.Equal(t, fiber.StatusOK, code)
+		require.NotNil(t, sent.Hometown)
+		assert.Empty(t, *sent.Hometown)
+		require.NotNil(t, sent.Interests)
+		assert.Empty(t, *sent.Interests)
+	})
+
+	t.Run("names cannot be cleared", func(t *testing.T) {
+		code, sent := updateProfile(t, http.MethodPatch, `{"first_name":null}`)
+		assert.Equal(t, fiber.StatusBadRequest, code)
+		assert.Nil(t, sent)
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		code, sent := updateProfile(t, http.MethodPatch, `{}`)
+		assert.Equal(t, fiber.StatusBadRequest, code)
+		assert.Nil(t, sent)
+	})
+}
+
+func TestHandleUpdateProfile_IgnoresEmptyFields(t *testing.T) {
+	code, sent := updateProfile(t, http.MethodPut, `{"first_name":"An","last_name":"","hometown":"","interests":[]}`)
+	require.Equal(t, fiber.StatusOK, code)
+	require.NotNil(t, sent.FirstName)
+	assert.Equal(t, "An", *sent.FirstName)
+	assert.Nil(t, sent.LastName)
+	assert.Nil(t, sent.Hometown)
+	assert.Nil(t, sent.Interests)
+}
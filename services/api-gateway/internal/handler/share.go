@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/share"
+	utils "github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/metadata"
+)
+
+// EnableSharing lets users share their conversations through read-only
+// links whose tokens signer issues and checks, and which revocations keeps
+// revoked. A link lasts ttl unless the user asks for another expiry of at
+// most maxTTL; zeros mean share.DefaultTTL and share.DefaultMaxTTL, the
+// former cut to maxTTL.
+func (h *Handler) EnableSharing(signer *share.Signer, revocations share.Store, ttl, maxTTL time.Duration) {
+	if maxTTL <= 0 {
+		maxTTL = share.DefaultMaxTTL
+	}
+	if ttl <= 0 {
+		ttl = min(share.DefaultTTL, maxTTL)
+	}
+	h.shareSigner = signer
+	h.shareRevocations = revocations
+	h.shareTTL = ttl
+	h.shareMaxTTL = maxTTL
+}
+
+// @Summary Share a conversation
+// @Description Create a read-only link to one of the authenticated user's conversations, for someone without an account. Anyone holding the token can read the conversation until it expires or is revoked
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Param request body ShareConversationRequest false "Link expiry"
+// @Success 201 {object} ShareConversationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/conversations/{id}/shares [post]
+func (h *Handler) HandleShareConversation(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*client.User)
+	if !ok || user == nil {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "User not found in context (middleware issue?)")
+	}
+	conversationID := c.Params("id")
+	if conversationID == "" {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Missing conversation ID")
+	}
+
+	var req ShareConversationRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.SendErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+	ttl := h.shareTTL
+	if req.ExpiresInHours < 0 {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, "expires_in_hours must not be negative")
+	}
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if ttl > h.shareMaxTTL {
+		return utils.SendErrorResponse(c, fiber.StatusBadRequest, fmt.Sprintf("expires_in_hours must be at most %d", int(h.shareMaxTTL/time.Hour)))
+	}
+
+	// chat-gateway checks ownership against the propagated user ID
+	ctx := metadata.AppendToOutgoingContext(c.UserContext(), "user-id", user.ID)
+	conv, err := h.chatClient.GetConversation(ctx, conversationID)
+	if err != nil {
+		return sendConversationError(c, err, "Failed to load conversation")
+	}
+	if conv.GetUserId() != user.ID {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, "You don't have permission to access this conversation")
+	}
+
+	shared := share.Share{
+		ConversationID: conversationID,
+		UserID:         user.ID,
+		ExpiresAt:      time.Now().Add(ttl).UTC().Truncate(time.Second),
+	}
+	token, err := h.shareSigner.Sign(shared)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to share conversation: "+err.Error())
+	}
+	return c.Status(fiber.StatusCreated).JSON(ShareConversationResponse{
+		Token:     token,
+		URL:       "/api/v1/shared/" + token,
+		ExpiresAt: shared.ExpiresAt.Format(time.RFC3339),
+	})
+}
+
+// @Summary Revoke a conversation share
+// @Description Stop a share link to one of the authenticated user's conversations from working before it expires
+// @Tags conversations
+// @Security BearerAuth
+// @Param id path string true "Conversation ID"
+// @Param token path string true "Share token"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/conversations/{id}/shares/{token} [delete]
+func (h *Handler) HandleRevokeShare(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*client.User)
+	if !ok || user == nil {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "User not found in context (middleware issue?)")
+	}
+
+	token := c.Params("token")
+	shared, err := h.shareSigner.Verify(token)
+	if err != nil || shared.ConversationID != c.Params("id") {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, "Share not found")
+	}
+	if shared.UserID != user.ID {
+		return utils.SendErrorResponse(c, fiber.StatusForbidden, "You don't have permission to revoke this share")
+	}
+	// An expired link no longer works, so there is nothing to revoke
+	if !shared.Expired(time.Now()) {
+		// Fiber's strings point into buffers it reuses; the revocation
+		// outlives them
+		if err := h.shareRevocations.Revoke(c.UserContext(), strings.Clone(token), shared.ExpiresAt); err != nil {
+			return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to revoke share: "+err.Error())
+		}
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary View a shared conversation
+// @Description Read a conversation through a share link. No account is needed: the token is the credential
+// @Tags conversations
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} SharedConversationResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/shared/{token} [get]
+func (h *Handler) HandleGetSharedConversation(c *fiber.Ctx) error {
+	token := c.Params("token")
+	shared, err := h.shareSigner.Verify(token)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, "Share not found")
+	}
+	if shared.Expired(time.Now()) {
+		return utils.SendErrorResponse(c, fiber.StatusGone, "This share link has expired")
+	}
+	revoked, err := h.shareRevocations.Revoked(c.UserContext(), token)
+	if err != nil {
+		log.Printf("Share revocation lookup failed: %v", err)
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to load shared conversation")
+	}
+	if revoked {
+		return utils.SendErrorResponse(c, fiber.StatusGone, "This share link was revoked")
+	}
+
+	// Read the conversation as its owner, who granted the token
+	ctx := metadata.AppendToOutgoingContext(c.UserContext(), "user-id", shared.UserID)
+	conv, err := h.chatClient.GetConversation(ctx, shared.ConversationID)
+	if err != nil {
+		return sendConversationError(c, err, "Failed to load shared conversation")
+	}
+	if conv.GetUserId() != shared.UserID {
+		return utils.SendErrorResponse(c, fiber.StatusNotFound, "Conversation not found")
+	}
+
+	// A revoked link must stop working at once, not once caches expire
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(SharedConversationResponse{
+		Messages:  sharedMessages(conv.GetMessages()),
+		ExpiresAt: shared.ExpiresAt.Format(time.RFC3339),
+	})
+}
+
+// sharedMessages copies what a share link's reader may see of msgs.
+func sharedMessages(msgs []*pbChat.ConversationMessage) []SharedMessage {
+	shared := make([]SharedMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		m := SharedMessage{
+			Role:      msg.GetRole(),
+			Text:      msg.GetText(),
+			CreatedAt: msg.GetCreatedAt(),
+		}
+		for _, source := range msg.GetSources() {
+			m.Sources = append(m.Sources, SharedSource{
+				Type:  source.GetType(),
+				Title: source.GetTitle(),
+				URI:   source.GetUri(),
+			})
+		}
+		shared = append(shared, m)
+	}
+	return shared
+}
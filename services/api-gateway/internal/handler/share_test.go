@@ -0,0 +1,213 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	chatpb "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/share"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// sharedConversation is conv-1 of user-1, with an answer drawing on the
+// knowledge base.
+var sharedConversation = &chatpb.GetConversationResponse{
+	ConversationId: "conv-1",
+	UserId:         "user-1",
+	Messages: []*chatpb.ConversationMessage{
+		{Role: "user", Text: "Which major suits me?", CreatedAt: "2026-10-16T09:00:00Z"},
+		{
+			Role:      "assistant",
+			Text:      "Computer Science fits your profile.",
+			CreatedAt: "2026-10-16T09:00:05Z",
+			Sources: []*chatpb.MessageSource{
+				{Type: "knowledge_base", Title: "Admissions", Uri: "admissions.pdf", Collection: "university-scores"},
+			},
+		},
+	},
+}
+
+// asOwner is satisfied by a context carrying user-1's ID, as chat-gateway
+// requires to read conv-1.
+var asOwner = mock.MatchedBy(func(ctx context.Context) bool {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return len(md.Get("user-id")) == 1 && md.Get("user-id")[0] == "user-1"
+})
+
+// shareSecret signs the tokens of the test apps.
+const shareSecret = "test-share-secret"
+
+func newShareSigner(t *testing.T, secret string) *share.Signer {
+	t.Helper()
+	signer, err := share.NewSigner(secret)
+	require.NoError(t, err)
+	return signer
+}
+
+// shareApp serves the share routes, standing in for the auth middleware by
+// taking the user ID from the X-User header.
+func shareApp(t *testing.T, chatClient *handler.MockChatClient) *fiber.App {
+	t.Helper()
+	h := handler.NewHandler(handler.NewMockAuthClient(), chatClient, nil, nil, "")
+	h.EnableSharing(newShareSigner(t, shareSecret), share.NewMemoryStore(), 0, 48*time.Hour)
+	app := fiber.New()
+	asUser := func(c *fiber.Ctx) error {
+		c.Locals("user", &client.User{ID: c.Get("X-User")})
+		return c.Next()
+	}
+	app.Post("/api/v1/conversations/:id/shares", asUser, h.HandleShareConversation)
+	app.Delete("/api/v1/conversations/:id/shares/:token", asUser, h.HandleRevokeShare)
+	app.Get("/api/v1/shared/:token", h.HandleGetSharedConversation)
+	return app
+}
+
+func shareRequest(t *testing.T, app *fiber.App, method, path, userID, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if userID != "" {
+		req.Header.Set("X-User", userID)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp
+}
+
+// createShare shares conv-1 as user-1 and returns the new link.
+func createShare(t *testing.T, app *fiber.App, body string) handler.ShareConversationResponse {
+	t.Helper()
+	resp := shareRequest(t, app, http.MethodPost, "/api/v1/conversations/conv-1/shares", "user-1", body)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	var created handler.ShareConversationResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	return created
+}
+
+func TestHandleShareConversation(t *testing.T) {
+	t.Run("creates a link with the default expiry", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("GetConversation", asOwner, "conv-1").Return(sharedConversation, nil)
+		app := shareApp(t, chatClient)
+
+		created := createShare(t, app, "")
+		assert.Equal(t, "/api/v1/shared/"+created.Token, created.URL)
+		expiresAt, err := time.Parse(time.RFC3339, created.ExpiresAt)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(48*time.Hour), expiresAt, 2*time.Second, "the default is cut to the maximum")
+	})
+
+	t.Run("honours a requested expiry up to the maximum", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("GetConversation", asOwner, "conv-1").Return(sharedConversation, nil)
+		app := shareApp(t, chatClient)
+
+		created := createShare(t, app, `{"expires_in_hours":24}`)
+		expiresAt, err := time.Parse(time.RFC3339, created.ExpiresAt)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), expiresAt, 2*time.Second)
+
+		resp := shareRequest(t, app, http.MethodPost, "/api/v1/conversations/conv-1/shares", "user-1", `{"expires_in_hours":49}`)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("rejects non-owner", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("GetConversation", mock.Anything, "conv-1").
+			Return(nil, status.Error(codes.PermissionDenied, "conversation belongs to another user"))
+		app := shareApp(t, chatClient)
+
+		resp := shareRequest(t, app, http.MethodPost, "/api/v1/conversations/conv-1/shares", "user-2", "")
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestHandleGetSharedConversation(t *testing.T) {
+	t.Run("shows the transcript without internal fields", func(t *testing.T) {
+		chatClient := handler.NewMockChatClient()
+		chatClient.On("GetConversation", asOwner, "conv-1").Return(sharedConversation, nil)
+		app := shareApp(t, chatClient)
+		created := createShare(t, app, "")
+
+		resp := shareRequest(t, app, http.MethodGet, created.URL, "", "")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "no-store", resp.Header.Get(fiber.HeaderCacheControl))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		for _, internal := range []string{"user-1", "user_id", "collection", "university-scores"} {
+			assert.NotContains(t, string(body), internal)
+		}
+
+		var shared handler.SharedConversationResponse
+		require.NoError(t, json.Unmarshal(body, &shared))
+		assert.Equal(t, created.ExpiresAt, shared.ExpiresAt)
+		assert.Equal(t, []handler.SharedMessage{
+			{Role: "user", Text: "Which major suits me?", CreatedAt: "2026-10-16T09:00:00Z"},
+			{
+				Role:      "assistant",
+				Text:      "Computer Science fits your profile.",
+				CreatedAt: "2026-10-16T09:00:05Z",
+				Sources:   []handler.SharedSource{{Type: "knowledge_base", Title: "Admissions", URI: "admissions.pdf"}},
+			},
+		}, shared.Messages)
+	})
+
+	t.Run("refuses expired links", func(t *testing.T) {
+		expired := share.Share{ConversationID: "conv-1", UserID: "user-1", ExpiresAt: time.Now().Add(-time.Minute)}
+		token, err := newShareSigner(t, shareSecret).Sign(expired)
+		require.NoError(t, err)
+		app := shareApp(t, handler.NewMockChatClient())
+
+		resp := shareRequest(t, app, http.MethodGet, "/api/v1/shared/"+token, "", "")
+		assert.Equal(t, fiber.StatusGone, resp.StatusCode)
+	})
+
+	t.Run("refuses links it did not sign", func(t *testing.T) {
+		forged, err := newShareSigner(t, "another-secret").Sign(share.Share{
+			ConversationID: "conv-1", UserID: "user-1", ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		app := shareApp(t, handler.NewMockChatClient())
+
+		for _, token := range []string{forged, "not-a-token"} {
+			resp := shareRequest(t, app, http.MethodGet, "/api/v1/shared/"+token, "", "")
+			assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		}
+	})
+}
+
+func TestHandleRevokeShare(t *testing.T) {
+	chatClient := handler.NewMockChatClient()
+	chatClient.On("GetConversation", asOwner, "conv-1").Return(sharedConversation, nil)
+	app := shareApp(t, chatClient)
+	revoked, kept := createShare(t, app, ""), createShare(t, app, "")
+	revokePath := "/api/v1/conversations/conv-1/shares/" + revoked.Token
+
+	resp := shareRequest(t, app, http.MethodDelete, revokePath, "user-2", "")
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode, "only the owner may revoke")
+	resp = shareRequest(t, app, http.MethodDelete, "/api/v1/conversations/conv-2/shares/"+revoked.Token, "user-1", "")
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode, "the token must share the conversation named")
+
+	resp = shareRequest(t, app, http.MethodDelete, revokePath, "user-1", "")
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	resp = shareRequest(t, app, http.MethodGet, revoked.URL, "", "")
+	assert.Equal(t, fiber.StatusGone, resp.StatusCode)
+	resp = shareRequest(t, app, http.MethodGet, kept.URL, "", "")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode, "other links to the conversation keep working")
+}
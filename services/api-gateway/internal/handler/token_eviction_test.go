@@ -0,0 +1,87 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// cachedTokenApp serves the refresh and logout routes with old_token in the
+// token cache.
+func cachedTokenApp(t *testing.T, authClient *handler.MockAuthClient) (*fiber.App, middleware.TokenCache) {
+	t.Helper()
+	tokens := middleware.NewMemoryTokenCache(time.Minute)
+	require.NoError(t, tokens.Set(context.Background(), "old_token", &client.User{ID: "user-1"}))
+	h := handler.NewHandler(authClient, handler.NewMockChatClient(), nil, nil, "")
+	h.SetTokenCache(tokens)
+	app := fiber.New()
+	app.Post("/api/v1/auth/refresh", h.HandleRefreshToken)
+	app.Post("/api/v1/auth/logout", h.HandleLogout)
+	return app, tokens
+}
+
+func assertCached(t *testing.T, tokens middleware.TokenCache, token string, want bool) {
+	t.Helper()
+	_, found, err := tokens.Get(context.Background(), token)
+	require.NoError(t, err)
+	assert.Equal(t, want, found)
+}
+
+func TestHandleRefreshToken_EvictsTheReplacedToken(t *testing.T) {
+	t.Run("on success", func(t *testing.T) {
+		authClient := handler.NewMockAuthClient()
+		authClient.On("RefreshToken", mock.Anything, "refresh_token").
+			Return(&client.TokenResponse{AccessToken: "new_token", RefreshToken: "new_refresh_token"}, nil)
+		app, tokens := cachedTokenApp(t, authClient)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", strings.NewReader(`{"refresh_token":"refresh_token"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer old_token")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assertCached(t, tokens, "old_token", false)
+	})
+
+	t.Run("not on failure", func(t *testing.T) {
+		authClient := handler.NewMockAuthClient()
+		authClient.On("RefreshToken", mock.Anything, "stolen_token").
+			Return(nil, fiber.NewError(fiber.StatusUnauthorized, "invalid refresh token"))
+		app, tokens := cachedTokenApp(t, authClient)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", strings.NewReader(`{"refresh_token":"stolen_token"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer old_token")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+		assertCached(t, tokens, "old_token", true)
+	})
+}
+
+func TestHandleLogout(t *testing.T) {
+	app, tokens := cachedTokenApp(t, handler.NewMockAuthClient())
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	assertCached(t, tokens, "old_token", true)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer old_token")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	assertCached(t, tokens, "old_token", false)
+}
@@ -1,6 +1,13 @@
 package handler
 
-import "github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+import (
+	"encoding/json"
+
+	"github.com/careerup-Inc/careerup-monorepo/pkg/chaterr"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/utils"
+)
 
 type RegisterRequest struct {
 	Email     string `json:"email" binding:"required,email" example:"user@example.com"`
@@ -14,12 +21,41 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required" example:"password123"`
 }
 
+// UpdateUserRequest is a profile update. Omitted fields are left as they
+// are; with PATCH, a field sent as null or empty is cleared.
 type UpdateUserRequest struct {
-	Token     string   `json:"token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	FirstName string   `json:"first_name" example:"John"`
-	LastName  string   `json:"last_name" example:"Doe"`
-	Hometown  string   `json:"hometown" example:"New York"`
-	Interests []string `json:"interests" example:"['AI', 'Machine Learning']"`
+	Token     string    `json:"token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	FirstName *string   `json:"first_name" example:"John"`
+	LastName  *string   `json:"last_name" example:"Doe"`
+	Hometown  *string   `json:"hometown" example:"New York"`
+	Interests *[]string `json:"interests" example:"['AI', 'Machine Learning']"`
+}
+
+// UnmarshalJSON decodes fields sent as null to empty values, so they can be
+// told apart from omitted ones.
+func (r *UpdateUserRequest) UnmarshalJSON(data []byte) error {
+	type plain UpdateUserRequest
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	isNull := func(name string) bool {
+		v, ok := fields[name]
+		return ok && string(v) == "null"
+	}
+
+	for name, field := range map[string]**string{"first_name": &r.FirstName, "last_name": &r.LastName, "hometown": &r.Hometown} {
+		if isNull(name) {
+			*field = new(string)
+		}
+	}
+	if isNull("interests") {
+		r.Interests = &[]string{}
+	}
+	return nil
 }
 
 type ValidateTokenRequest struct {
@@ -32,17 +68,104 @@ type RefreshTokenRequest struct {
 
 // ClientMessage defines the structure for messages received from the WebSocket client
 type ClientMessage struct {
-	Type           string `json:"type"` // e.g., "user_msg"
+	Type           string `json:"type"` // "user_msg", "regenerate" for a new answer to the last message, or "continue" for the rest of a truncated one
 	ConversationID string `json:"conversation_id"`
 	Text           string `json:"text"`
+	Verbosity      string `json:"verbosity,omitempty"` // Answer length: "short", "normal" or "detailed"; empty means "normal"
 }
 
+// verbosities are the answer lengths a ClientMessage may ask for
+var verbosities = map[string]bool{"": true, "short": true, "normal": true, "detailed": true}
+
 // ServerMessage defines the structure for messages sent to the WebSocket client
 type ServerMessage struct {
-	Type         string `json:"type"`            // e.g., "assistant_token", "avatar_url", "error"
-	Token        string `json:"token,omitempty"` // For type="assistant_token"
-	URL          string `json:"url,omitempty"`   // For type="avatar_url"
-	ErrorMessage string `json:"error,omitempty"` // For type="error"
+	Type         string    `json:"type"`                 // e.g., "assistant_token", "avatar_url", "error", "status", "suggestions"
+	Token        string    `json:"token,omitempty"`      // For type="assistant_token"
+	URL          string    `json:"url,omitempty"`        // For type="avatar_url"
+	ErrorMessage string    `json:"error,omitempty"`      // For type="error"
+	ErrorCode    ErrorCode `json:"error_code,omitempty"` // For type="error"
+	Status       string    `json:"status,omitempty"`     // For type="status", e.g. "retrieving", "generating", "truncated"
+	// For type="suggestions", after the last token of a completed answer:
+	// follow-up questions the user may ask next
+	Suggestions []string `json:"suggestions,omitempty"`
+	// For type="error" with error_code "message_rate_limited": seconds
+	// until the message may be sent again
+	RetryAfterSeconds int32 `json:"retry_after_seconds,omitempty"`
+}
+
+// ErrorCode tells chat clients why a request failed; see chaterr for what
+// each code means. The handshake's 401 response carries one too.
+type ErrorCode = chaterr.Code
+
+const (
+	ErrorCodeUnauthorized         = chaterr.Unauthorized
+	ErrorCodeRateLimited          = chaterr.RateLimited
+	ErrorCodeInvalidMessage       = chaterr.InvalidMessage
+	ErrorCodeNothingToRegenerate  = chaterr.NothingToRegenerate
+	ErrorCodeNothingToContinue    = chaterr.NothingToContinue
+	ErrorCodeContinueLimitReached = chaterr.ContinueLimitReached
+	ErrorCodeChatUnavailable      = chaterr.ChatUnavailable
+	ErrorCodeChatError            = chaterr.ChatError
+	ErrorCodeLLMUnavailable       = chaterr.LLMUnavailable
+	ErrorCodeLLMError             = chaterr.LLMError
+	ErrorCodeLLMBusy              = chaterr.LLMBusy
+	ErrorCodeConversationBusy     = chaterr.ConversationBusy
+	ErrorCodeMessageRateLimited   = chaterr.MessageRateLimited
+)
+
+// ShareConversationRequest optionally sets how long a share link lasts
+type ShareConversationRequest struct {
+	// Hours until the link expires; 0 uses the default
+	ExpiresInHours int `json:"expires_in_hours,omitempty" example:"72"`
+}
+
+// ShareConversationResponse is a new read-only link to a conversation
+type ShareConversationResponse struct {
+	// Token grants read access to the conversation until ExpiresAt
+	Token string `json:"token"`
+	// URL is the public path that shows the conversation
+	URL       string `json:"url" example:"/api/v1/shared/q3Zx8kLmN2pR..."`
+	ExpiresAt string `json:"expires_at" example:"2026-10-23T09:00:00Z"`
+}
+
+// SharedConversationResponse is the transcript shown through a share link.
+// It holds only what the reader needs, never the owner's ID or internal
+// retrieval details.
+type SharedConversationResponse struct {
+	Messages  []SharedMessage `json:"messages"`
+	ExpiresAt string          `json:"expires_at" example:"2026-10-23T09:00:00Z"`
+}
+
+type SharedMessage struct {
+	Role      string `json:"role" example:"assistant"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"created_at" example:"2026-10-16T09:00:00Z"`
+	// The documents an answer drew on
+	Sources []SharedSource `json:"sources,omitempty"`
+}
+
+type SharedSource struct {
+	Type  string `json:"type" example:"web"`
+	Title string `json:"title,omitempty"`
+	URI   string `json:"uri,omitempty"`
+}
+
+// ConversationInfo describes a conversation without its messages
+type ConversationInfo struct {
+	ID string `json:"id" example:"conv-1"`
+	// The first user message, cut short
+	Title         string `json:"title" example:"Ngành nào hợp với tôi?"`
+	MessageCount  int32  `json:"message_count" example:"4"`
+	LastMessageAt string `json:"last_message_at" example:"2025-05-01T10:00:05Z"`
+	Archived      bool   `json:"archived"`
+}
+
+// ConversationsResponse is a page of the user's conversations, most
+// recently active first
+type ConversationsResponse struct {
+	utils.ListResponse[ConversationInfo]
+	// Deprecated: the same page as Data, kept until clients read data
+	Conversations []ConversationInfo `json:"conversations"`
 }
 
 // ILO Test Result submission
@@ -54,9 +177,26 @@ type IloAnswer struct {
 	SelectedOption int32  `json:"selected_option"`
 }
 
+// IloTestResultRequest submits answers to every question of the ILO test.
+// The raw result is computed from them server-side.
 type IloTestResultRequest struct {
-	ResultData string      `json:"result_data" example:"{\"score\":85,\"details\":{...}}"`
-	Answers    []IloAnswer `json:"answers,omitempty"`
+	Answers []IloAnswer `json:"answers,omitempty"`
+	// Deprecated: send Answers. Only read when Answers is empty, for the
+	// answers in a legacy {"answers": [...]} payload. When set it must be a
+	// JSON object of at most 64 KB
+	ResultData string `json:"result_data,omitempty" example:"{\"answers\":[{\"question_id\":\"1\",\"selected_option\":3}]}"`
+	// Optional: a conversation whose messages may inform the analysis.
+	// Only used when ShareChatContext is set, as the user's consent
+	ConversationID   string `json:"conversation_id,omitempty"`
+	ShareChatContext bool   `json:"share_chat_context,omitempty"`
+}
+
+// IloTestResultsResponse is a page of ILO test results
+type IloTestResultsResponse struct {
+	utils.ListResponse[IloTestResultResponse]
+	// Deprecated: the same page as Data, kept until clients read data
+	Results   []IloTestResultResponse `json:"results"`
+	Copyright string                  `json:"copyright"`
 }
 
 type IloTestResultResponse struct {
@@ -71,7 +211,7 @@ type IloTestResultResponse struct {
 
 type IloTestResultAnalysisResponse struct {
 	Result   IloTestResultResponse `json:"result"`
-	Analysis string                `json:"analysis"`
+	Analysis client.IloAnalysis    `json:"analysis"`
 }
 
 // IloDomain represents one of the 5 domains assessed in the ILO test
@@ -104,3 +244,18 @@ type GetIloTestResponse struct {
 	Domains   []IloDomain       `json:"domains,omitempty"`
 	Levels    []IloLevel        `json:"levels,omitempty"`
 }
+
+// IloCareerSuggestionsRequest asks for career suggestions for domains,
+// optionally with the user's scores for them
+type IloCareerSuggestionsRequest struct {
+	DomainCodes []string                `json:"domain_codes"`
+	Scores      []client.IloDomainScore `json:"scores,omitempty"`
+	Limit       int32                   `json:"limit,omitempty"`
+}
+
+// IloCareerSuggestionsResponse carries the detailed suggestions and, for
+// clients that predate them, just the career field names
+type IloCareerSuggestionsResponse struct {
+	Suggestions []client.IloCareerSuggestion `json:"suggestions"`
+	Careers     []string                     `json:"careers"`
+}
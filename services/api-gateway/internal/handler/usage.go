@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	utils "github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary Get LLM usage
+// @Description Token usage of the authenticated user this month, against their monthly quota
+// @Tags usage
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} client.LLMUsage
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/usage [get]
+func (h *Handler) HandleGetUsage(c *fiber.Ctx) error {
+	user, ok := c.Locals("user").(*client.User)
+	if !ok || user == nil {
+		return utils.SendErrorResponse(c, fiber.StatusUnauthorized, "User not found in context (middleware issue?)")
+	}
+
+	usage, err := h.LLMClient.GetUsage(c.UserContext(), user.ID)
+	if err != nil {
+		return utils.SendErrorResponse(c, fiber.StatusInternalServerError, "Failed to get usage: "+err.Error())
+	}
+	return c.Status(fiber.StatusOK).JSON(usage)
+}
@@ -0,0 +1,123 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	llmpb "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeUsageLLMServer reports fixed usage, and refuses generation once the
+// quota is used up or, when busy, as if every model were rate limited
+type fakeUsageLLMServer struct {
+	llmpb.UnimplementedLLMServiceServer
+	overQuota bool
+	busy      bool
+}
+
+func (s *fakeUsageLLMServer) GetUsage(ctx context.Context, req *llmpb.GetUsageRequest) (*llmpb.GetUsageResponse, error) {
+	return &llmpb.GetUsageResponse{
+		UserId:           req.GetUserId(),
+		Period:           "2026-10",
+		PromptTokens:     1200,
+		CompletionTokens: 300,
+		TotalTokens:      1500,
+		Requests:         4,
+		Quota:            10000,
+		Remaining:        8500,
+	}, nil
+}
+
+func (s *fakeUsageLLMServer) GenerateStructured(ctx context.Context, req *llmpb.GenerateStructuredRequest) (*llmpb.GenerateStructuredResponse, error) {
+	if s.busy {
+		grpc.SetTrailer(ctx, metadata.Pairs("retry-after", "3"))
+		return nil, status.Error(codes.ResourceExhausted, "The assistant is in high demand right now. Please try again shortly.")
+	}
+	if s.overQuota {
+		return nil, status.Error(codes.ResourceExhausted, "monthly token quota of 10000 used up for 2026-10 (10012 tokens used)")
+	}
+	return &llmpb.GenerateStructuredResponse{Json: fakeAnalysisJSON}, nil
+}
+
+func TestHandleGetUsage(t *testing.T) {
+	h := handler.NewHandler(handler.NewMockAuthClient(), handler.NewMockChatClient(), nil, newLLMClient(t, &fakeUsageLLMServer{}), "")
+	app := fiber.New()
+	app.Get("/api/v1/usage", func(c *fiber.Ctx) error {
+		c.Locals("user", &client.User{ID: "user-1"})
+		return c.Next()
+	}, h.HandleGetUsage)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/usage", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var usage client.LLMUsage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&usage))
+	assert.Equal(t, client.LLMUsage{
+		UserID:           "user-1",
+		Period:           "2026-10",
+		PromptTokens:     1200,
+		CompletionTokens: 300,
+		TotalTokens:      1500,
+		Requests:         4,
+		Quota:            10000,
+		Remaining:        8500,
+	}, usage)
+}
+
+func TestHandleIloTestResult_QuotaExceeded(t *testing.T) {
+	authClient := handler.NewMockAuthClient()
+	authClient.On("ValidateToken", mock.Anything, "valid_token").Return(&client.User{ID: "user-1"}, nil)
+	llm := &fakeUsageLLMServer{overQuota: true}
+	h := handler.NewHandler(authClient, handler.NewMockChatClient(), newIloClient(t, &fakeIloResultServer{}), newLLMClient(t, llm), "")
+
+	app := fiber.New()
+	app.Post("/api/v1/ilo/result", h.HandleIloTestResult)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/ilo/result", bytes.NewBufferString(`{"answers":`+completeIloAnswers+`}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer valid_token")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "Monthly AI usage quota reached: monthly token quota of 10000 used up")
+}
+
+func TestHandleIloTestResult_LLMBusy(t *testing.T) {
+	authClient := handler.NewMockAuthClient()
+	authClient.On("ValidateToken", mock.Anything, "valid_token").Return(&client.User{ID: "user-1"}, nil)
+	llm := &fakeUsageLLMServer{busy: true}
+	h := handler.NewHandler(authClient, handler.NewMockChatClient(), newIloClient(t, &fakeIloResultServer{}), newLLMClient(t, llm), "")
+
+	app := fiber.New()
+	app.Post("/api/v1/ilo/result", h.HandleIloTestResult)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/ilo/result", bytes.NewBufferString(`{"answers":`+completeIloAnswers+`}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer valid_token")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "3", resp.Header.Get("Retry-After"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "high demand")
+	assert.NotContains(t, string(body), "quota")
+}
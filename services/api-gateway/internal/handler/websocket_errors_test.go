@@ -0,0 +1,207 @@
+package handler_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	chatpb "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/handler"
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeChatServer answers the first stream request with reply, or fails the
+// stream with err
+type fakeChatServer struct {
+	chatpb.UnimplementedConversationServiceServer
+	reply *chatpb.StreamResponse
+	err   error
+}
+
+func (s *fakeChatServer) Stream(stream chatpb.ConversationService_StreamServer) error {
+	if _, err := stream.Recv(); err != nil {
+		return err
+	}
+	if s.err != nil {
+		return s.err
+	}
+	if err := stream.Send(s.reply); err != nil {
+		return err
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+// dialChat connects to a WebSocket proxy in front of srv and returns the
+// client side of the socket. configure, if any, adjusts the handler.
+func dialChat(t *testing.T, srv chatpb.ConversationServiceServer, configure ...func(*handler.Handler)) *fastws.Conn {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	chatpb.RegisterConversationServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	grpcConn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { grpcConn.Close() })
+
+	authClient := handler.NewMockAuthClient()
+	authClient.On("ValidateToken", mock.Anything, "valid_token").Return(&client.User{ID: "user-1"}, nil)
+	chatClient := handler.NewMockChatClient()
+	chatClient.On("GetChatServiceClient").Return(chatpb.NewConversationServiceClient(grpcConn))
+	h := handler.NewHandler(authClient, chatClient, nil, nil, "")
+	for _, fn := range configure {
+		fn(h)
+	}
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws", h.HandleWebSocket, websocket.New(h.WebSocketProxy))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+
+	header := http.Header{"Authorization": []string{"Bearer valid_token"}}
+	ws, _, err := fastws.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws", header)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+// readServerMessage reads the next message the proxy sends
+func readServerMessage(t *testing.T, ws *fastws.Conn) handler.ServerMessage {
+	t.Helper()
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var msg handler.ServerMessage
+	require.NoError(t, ws.ReadJSON(&msg))
+	return msg
+}
+
+func TestWebSocketProxy_ErrorCodes(t *testing.T) {
+	userMsg := handler.ClientMessage{Type: "user_msg", ConversationID: "conv-1", Text: "Hello"}
+
+	t.Run("invalid client messages", func(t *testing.T) {
+		ws := dialChat(t, &fakeChatServer{})
+
+		require.NoError(t, ws.WriteMessage(fastws.TextMessage, []byte("not json")))
+		msg := readServerMessage(t, ws)
+		assert.Equal(t, "error", msg.Type)
+		assert.Equal(t, handler.ErrorCodeInvalidMessage, msg.ErrorCode)
+
+		require.NoError(t, ws.WriteJSON(handler.ClientMessage{Type: "user_msg"}))
+		assert.Equal(t, handler.ErrorCodeInvalidMessage, readServerMessage(t, ws).ErrorCode)
+
+		require.NoError(t, ws.WriteJSON(handler.ClientMessage{Type: "continue"}))
+		assert.Equal(t, handler.ErrorCodeInvalidMessage, readServerMessage(t, ws).ErrorCode)
+
+		require.NoError(t, ws.WriteJSON(handler.ClientMessage{Type: "user_msg", Text: "Hello", Verbosity: "verbose"}))
+		assert.Equal(t, handler.ErrorCodeInvalidMessage, readServerMessage(t, ws).ErrorCode)
+	})
+
+	t.Run("error codes from chat-gateway are relayed", func(t *testing.T) {
+		ws := dialChat(t, &fakeChatServer{reply: &chatpb.StreamResponse{
+			Type:      "error",
+			Content:   &chatpb.StreamResponse_ErrorMessage{ErrorMessage: "Nothing to regenerate"},
+			ErrorCode: "nothing_to_regenerate",
+		}})
+		require.NoError(t, ws.WriteJSON(userMsg))
+		msg := readServerMessage(t, ws)
+		assert.Equal(t, "Nothing to regenerate", msg.ErrorMessage)
+		assert.Equal(t, handler.ErrorCodeNothingToRegenerate, msg.ErrorCode)
+	})
+
+	t.Run("throttled messages carry a retry hint", func(t *testing.T) {
+		ws := dialChat(t, &fakeChatServer{reply: &chatpb.StreamResponse{
+			Type:              "error",
+			Content:           &chatpb.StreamResponse_ErrorMessage{ErrorMessage: "Too many messages; try again in 3 seconds"},
+			ErrorCode:         "message_rate_limited",
+			RetryAfterSeconds: 3,
+		}})
+		require.NoError(t, ws.WriteJSON(userMsg))
+		msg := readServerMessage(t, ws)
+		assert.Equal(t, handler.ErrorCodeMessageRateLimited, msg.ErrorCode)
+		assert.Equal(t, int32(3), msg.RetryAfterSeconds)
+	})
+
+	t.Run("errors without a code are LLM errors", func(t *testing.T) {
+		ws := dialChat(t, &fakeChatServer{reply: &chatpb.StreamResponse{
+			Type:    "error",
+			Content: &chatpb.StreamResponse_ErrorMessage{ErrorMessage: "LLM stream error"},
+		}})
+		require.NoError(t, ws.WriteJSON(userMsg))
+		assert.Equal(t, handler.ErrorCodeLLMError, readServerMessage(t, ws).ErrorCode)
+	})
+
+	t.Run("failed streams are classified by status code", func(t *testing.T) {
+		cases := map[codes.Code]handler.ErrorCode{
+			codes.Unavailable:       handler.ErrorCodeChatUnavailable,
+			codes.ResourceExhausted: handler.ErrorCodeRateLimited,
+			codes.Unauthenticated:   handler.ErrorCodeUnauthorized,
+			codes.Internal:          handler.ErrorCodeChatError,
+		}
+		for code, want := range cases {
+			ws := dialChat(t, &fakeChatServer{err: status.Error(code, "stream failed")})
+			require.NoError(t, ws.WriteJSON(userMsg))
+			msg := readServerMessage(t, ws)
+			assert.Equal(t, "error", msg.Type)
+			assert.Equal(t, want, msg.ErrorCode, code.String())
+		}
+	})
+}
+
+// recordingChatServer hands the first stream request to requests and
+// answers it with a token.
+type recordingChatServer struct {
+	chatpb.UnimplementedConversationServiceServer
+	requests chan *chatpb.StreamRequest
+}
+
+func (s *recordingChatServer) Stream(stream chatpb.ConversationService_StreamServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	s.requests <- req
+	if err := stream.Send(&chatpb.StreamResponse{Type: "assistant_token", Content: &chatpb.StreamResponse_Token{Token: "Hi"}}); err != nil {
+		return err
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+func TestWebSocketProxy_ForwardsVerbosity(t *testing.T) {
+	for _, verbosity := range []string{"", "short", "normal", "detailed"} {
+		t.Run("verbosity "+verbosity, func(t *testing.T) {
+			srv := &recordingChatServer{requests: make(chan *chatpb.StreamRequest, 1)}
+			ws := dialChat(t, srv)
+			require.NoError(t, ws.WriteJSON(handler.ClientMessage{Type: "user_msg", ConversationID: "conv-1", Text: "Hello", Verbosity: verbosity}))
+			assert.Equal(t, "Hi", readServerMessage(t, ws).Token)
+			assert.Equal(t, verbosity, (<-srv.requests).GetVerbosity())
+		})
+	}
+}
+
+func TestWebSocketProxy_RelaysSuggestions(t *testing.T) {
+	questions := []string{"What are the tuition fees?", "Is there a dormitory?"}
+	ws := dialChat(t, &fakeChatServer{reply: &chatpb.StreamResponse{Type: "suggestions", Suggestions: questions}})
+	require.NoError(t, ws.WriteJSON(handler.ClientMessage{Type: "user_msg", ConversationID: "conv-1", Text: "Hello"}))
+	msg := readServerMessage(t, ws)
+	assert.Equal(t, "suggestions", msg.Type)
+	assert.Equal(t, questions, msg.Suggestions)
+}
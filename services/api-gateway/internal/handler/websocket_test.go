@@ -89,6 +89,7 @@ func TestHandleWebSocket_Upgrade(t *testing.T) {
 		err = json.NewDecoder(resp.Body).Decode(&response)
 		assert.NoError(t, err)
 		assert.Equal(t, "Authorization header is required", response["error"])
+		assert.Equal(t, "unauthorized", response["error_code"])
 	})
 
 	t.Run("reject upgrade with invalid token", func(t *testing.T) {
@@ -118,6 +119,7 @@ func TestHandleWebSocket_Upgrade(t *testing.T) {
 		err = json.NewDecoder(resp.Body).Decode(&response)
 		assert.NoError(t, err)
 		assert.Equal(t, "Invalid token", response["error"])
+		assert.Equal(t, "unauthorized", response["error_code"])
 
 		mockAuthClient.AssertExpectations(t)
 	})
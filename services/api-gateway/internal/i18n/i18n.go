@@ -0,0 +1,86 @@
+// Package i18n holds the gateway's user-facing strings in each supported
+// language and picks the language to answer a request in.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Supported languages, as BCP 47 primary tags.
+const (
+	Vietnamese = "vi"
+	English    = "en"
+)
+
+// Key identifies a message in the catalog.
+type Key string
+
+const (
+	// IloCopyright is the attribution shown with ILO questions and results
+	IloCopyright Key = "ilo.copyright"
+	// IloReportLanguage names the language ILO analyses are written in, for
+	// the analysis prompt
+	IloReportLanguage Key = "ilo.report_language"
+)
+
+var messages = map[string]map[Key]string{
+	Vietnamese: {
+		IloCopyright:      "Thang đo ILO © ILO Vietnam 2020 – sử dụng cho mục đích hướng nghiệp, trích dẫn có ghi nguồn.",
+		IloReportLanguage: "Vietnamese",
+	},
+	English: {
+		IloCopyright:      "ILO scale © ILO Vietnam 2020 – for career guidance use; cite the source when quoting.",
+		IloReportLanguage: "English",
+	},
+}
+
+// Catalog looks up messages, falling back to its default language.
+type Catalog struct {
+	defaultLanguage string
+}
+
+// New returns a catalog whose default language is defaultLanguage, or
+// Vietnamese when it is empty.
+func New(defaultLanguage string) (*Catalog, error) {
+	if defaultLanguage == "" {
+		defaultLanguage = Vietnamese
+	}
+	if _, ok := messages[defaultLanguage]; !ok {
+		return nil, fmt.Errorf("unsupported default language %q (want %q or %q)", defaultLanguage, Vietnamese, English)
+	}
+	return &Catalog{defaultLanguage: defaultLanguage}, nil
+}
+
+// Language picks the first supported language of an Accept-Language header
+// ("en-US,en;q=0.9,vi;q=0.8"), honouring its order, or the default.
+func (c *Catalog) Language(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if _, ok := messages[primary]; ok {
+			return primary
+		}
+	}
+	return c.defaultLanguage
+}
+
+// For picks the language for a request from its Accept-Language header and
+// marks the response as varying by it.
+func (c *Catalog) For(ctx *fiber.Ctx) string {
+	lang := c.Language(ctx.Get(fiber.HeaderAcceptLanguage))
+	ctx.Vary(fiber.HeaderAcceptLanguage)
+	ctx.Set(fiber.HeaderContentLanguage, lang)
+	return lang
+}
+
+// Message returns the message for key in lang, or in the default language
+// when lang is not supported.
+func (c *Catalog) Message(lang string, key Key) string {
+	if msg, ok := messages[lang][key]; ok {
+		return msg
+	}
+	return messages[c.defaultLanguage][key]
+}
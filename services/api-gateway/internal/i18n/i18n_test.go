@@ -0,0 +1,50 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguage(t *testing.T) {
+	catalog, err := New("")
+	require.NoError(t, err)
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", Vietnamese},
+		{"en", English},
+		{"en-US,en;q=0.9,vi;q=0.8", English},
+		{"vi-VN,vi;q=0.9,en;q=0.8", Vietnamese},
+		{"fr-FR, EN-GB;q=0.7", English},
+		{"fr,de", Vietnamese},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, catalog.Language(tt.header), tt.header)
+	}
+}
+
+func TestMessageFallsBackToDefault(t *testing.T) {
+	catalog, err := New(English)
+	require.NoError(t, err)
+
+	assert.Equal(t, English, catalog.Language("fr"))
+	assert.Equal(t, "Vietnamese", catalog.Message(Vietnamese, IloReportLanguage))
+	assert.Equal(t, "English", catalog.Message("fr", IloReportLanguage))
+}
+
+func TestEveryLanguageHasEveryMessage(t *testing.T) {
+	for key := range messages[Vietnamese] {
+		for lang, msgs := range messages {
+			assert.NotEmpty(t, msgs[key], "%s has no %s message", lang, key)
+		}
+	}
+}
+
+func TestNewRejectsUnsupportedDefault(t *testing.T) {
+	_, err := New("fr")
+	assert.Error(t, err)
+}
@@ -2,21 +2,22 @@ package middleware
 
 import (
 	"context"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
 	"github.com/gofiber/fiber/v2"
-	"github.com/patrickmn/go-cache"
 )
 
-// Cache for validated tokens to reduce calls to auth-core
-var tokenCache = cache.New(5*time.Minute, 10*time.Minute)
-
-func AuthMiddleware(authClient *client.AuthClient) fiber.Handler {
+// AuthMiddleware validates the bearer token against auth-core, caching
+// validated tokens in tokens to reduce calls to it. A failing cache is
+// logged and bypassed.
+func AuthMiddleware(authClient *client.AuthClient, tokens TokenCache) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Create context with timeout for the gRPC call
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		// Create context with timeout for the gRPC call, within the route's
+		// own deadline when Timeout runs first
+		ctx, cancel := context.WithTimeout(c.UserContext(), 5*time.Second)
 		defer cancel()
 
 		authHeader := c.Get("Authorization")
@@ -37,7 +38,10 @@ func AuthMiddleware(authClient *client.AuthClient) fiber.Handler {
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
 		// Check if token is in cache
-		if cachedUser, found := tokenCache.Get(tokenString); found {
+		cachedUser, found, err := tokens.Get(ctx, tokenString)
+		if err != nil {
+			log.Printf("Token cache unavailable, validating with auth-core: %v", err)
+		} else if found {
 			// Set user in context
 			c.Locals("user", cachedUser)
 			return c.Next()
@@ -52,7 +56,9 @@ func AuthMiddleware(authClient *client.AuthClient) fiber.Handler {
 		}
 
 		// Store in cache
-		tokenCache.Set(tokenString, user, cache.DefaultExpiration)
+		if err := tokens.Set(ctx, tokenString, user); err != nil {
+			log.Printf("Failed to cache validated token: %v", err)
+		}
 
 		// Add user information to the context
 		c.Locals("user", user)
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// ClientTypeHeader names the platform the caller runs on, e.g. "web"
+	ClientTypeHeader = "X-Client-Type"
+	// AppVersionHeader is the caller's app version, e.g. "2.3.1"
+	AppVersionHeader = "X-App-Version"
+
+	// ClientTypeMetadata and AppVersionMetadata carry the client's details
+	// to the backends as gRPC metadata
+	ClientTypeMetadata = "client-type"
+	AppVersionMetadata = "app-version"
+
+	// UnknownClient stands for a header that was not sent
+	UnknownClient = "unknown"
+	// OtherClient stands for a value outside the expected ones, so that
+	// logs and metrics labelled with it keep a bounded set of values
+	OtherClient = "other"
+
+	clientInfoLocal = "clientInfo"
+)
+
+// clientTypes are the platforms told apart; any other is OtherClient.
+var clientTypes = map[string]bool{"web": true, "ios": true, "android": true}
+
+// appVersionPattern matches up to major.minor.patch at the start of a
+// version; pre-release and build suffixes are dropped.
+var appVersionPattern = regexp.MustCompile(`^v?(\d{1,4}(?:\.\d{1,4}){0,2})`)
+
+// ClientInfo is what the caller says about the app it runs in, normalized
+// to a bounded set of values.
+type ClientInfo struct {
+	Type       string
+	AppVersion string
+}
+
+// ParseClientInfo normalizes the X-Client-Type and X-App-Version header
+// values.
+func ParseClientInfo(clientType, appVersion string) ClientInfo {
+	info := ClientInfo{Type: UnknownClient, AppVersion: UnknownClient}
+	if clientType = strings.ToLower(strings.TrimSpace(clientType)); clientType != "" {
+		info.Type = OtherClient
+		if clientTypes[clientType] {
+			info.Type = clientType
+		}
+	}
+	if appVersion = strings.TrimSpace(appVersion); appVersion != "" {
+		info.AppVersion = OtherClient
+		if m := appVersionPattern.FindStringSubmatch(appVersion); m != nil {
+			info.AppVersion = m[1]
+		}
+	}
+	return info
+}
+
+// AppendToOutgoingContext adds the client's details to the gRPC metadata of
+// calls made with ctx.
+func (i ClientInfo) AppendToOutgoingContext(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, ClientTypeMetadata, i.Type, AppVersionMetadata, i.AppVersion)
+}
+
+// ClientContext records the caller's ClientInfo for the access log and
+// handlers, and adds it to the user context's outgoing gRPC metadata so
+// every backend call made for the request carries it.
+func ClientContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		info := ParseClientInfo(c.Get(ClientTypeHeader), c.Get(AppVersionHeader))
+		c.Locals(clientInfoLocal, info)
+		c.SetUserContext(info.AppendToOutgoingContext(c.UserContext()))
+		return c.Next()
+	}
+}
+
+// ClientInfoFrom returns the ClientInfo recorded by ClientContext, or parses
+// the request's headers when it did not run.
+func ClientInfoFrom(c *fiber.Ctx) ClientInfo {
+	if info, ok := c.Locals(clientInfoLocal).(ClientInfo); ok {
+		return info
+	}
+	return ParseClientInfo(c.Get(ClientTypeHeader), c.Get(AppVersionHeader))
+}
+
+// ClientInfoLocal returns what ClientContext recorded under the request's
+// locals, for handlers that only have a locals lookup, such as WebSocket
+// connections.
+func ClientInfoLocal(locals func(key string, value ...interface{}) interface{}) ClientInfo {
+	if info, ok := locals(clientInfoLocal).(ClientInfo); ok {
+		return info
+	}
+	return ClientInfo{Type: UnknownClient, AppVersion: UnknownClient}
+}
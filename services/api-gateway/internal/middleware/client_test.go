@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestParseClientInfo(t *testing.T) {
+	for _, tt := range []struct {
+		clientType, appVersion string
+		want                   middleware.ClientInfo
+	}{
+		{"", "", middleware.ClientInfo{Type: "unknown", AppVersion: "unknown"}},
+		{"iOS", "2.3.1", middleware.ClientInfo{Type: "ios", AppVersion: "2.3.1"}},
+		{" web ", "v10.4.0-beta.2+build.77", middleware.ClientInfo{Type: "web", AppVersion: "10.4.0"}},
+		{"android", "3", middleware.ClientInfo{Type: "android", AppVersion: "3"}},
+		// Free-form values would make every log and metric label unique
+		{"smart-fridge", "nightly-2026-10-16", middleware.ClientInfo{Type: "other", AppVersion: "other"}},
+	} {
+		assert.Equal(t, tt.want, middleware.ParseClientInfo(tt.clientType, tt.appVersion), "%q %q", tt.clientType, tt.appVersion)
+	}
+}
+
+func TestClientContext(t *testing.T) {
+	var out bytes.Buffer
+	var md metadata.MD
+	app := fiber.New()
+	app.Use(middleware.ClientContext())
+	app.Use(middleware.RequestLogger(middleware.NewRedactor(middleware.RedactionOptions{}), logger.Config{Output: &out}))
+	app.Get("/api/v1/user/me", func(c *fiber.Ctx) error {
+		md, _ = metadata.FromOutgoingContext(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	t.Run("headers sent", func(t *testing.T) {
+		out.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/user/me", nil)
+		req.Header.Set(middleware.ClientTypeHeader, "android")
+		req.Header.Set(middleware.AppVersionHeader, "2.3.1-rc1")
+		_, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Contains(t, out.String(), "| /api/v1/user/me | android | 2.3.1 |")
+		assert.Equal(t, []string{"android"}, md.Get(middleware.ClientTypeMetadata))
+		assert.Equal(t, []string{"2.3.1"}, md.Get(middleware.AppVersionMetadata))
+	})
+
+	t.Run("headers absent", func(t *testing.T) {
+		out.Reset()
+		_, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/user/me", nil))
+		require.NoError(t, err)
+
+		assert.Contains(t, out.String(), "| /api/v1/user/me | unknown | unknown |")
+		assert.Equal(t, []string{"unknown"}, md.Get(middleware.ClientTypeMetadata))
+		assert.Equal(t, []string{"unknown"}, md.Get(middleware.AppVersionMetadata))
+	})
+}
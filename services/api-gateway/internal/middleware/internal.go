@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InternalSecretHeader carries the shared secret internal services send to
+// identify themselves.
+const InternalSecretHeader = "X-Internal-Secret"
+
+// InternalTraffic recognises requests from health checkers and internal
+// services by source network or shared secret.
+type InternalTraffic struct {
+	networks []*net.IPNet
+	secret   string
+}
+
+// NewInternalTraffic parses the allowlisted CIDRs. Bare IPs are accepted as
+// single-host networks. An empty secret disables header-based bypass.
+func NewInternalTraffic(cidrs []string, secret string) (*InternalTraffic, error) {
+	t := &InternalTraffic{secret: secret}
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist entry %q: %w", cidr, err)
+		}
+		t.networks = append(t.networks, network)
+	}
+	return t, nil
+}
+
+// IsInternal reports whether the request comes from an allowlisted network or
+// carries the internal secret. c.IP() is the socket peer address unless Fiber
+// is configured with a trusted ProxyHeader, so clients can't spoof it through
+// X-Forwarded-For.
+func (t *InternalTraffic) IsInternal(c *fiber.Ctx) bool {
+	if t == nil {
+		return false
+	}
+
+	if t.secret != "" {
+		provided := c.Get(InternalSecretHeader)
+		if provided != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(t.secret)) == 1 {
+			return true
+		}
+	}
+
+	ip := net.ParseIP(c.IP())
+	if ip == nil {
+		return false
+	}
+	for _, network := range t.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// InternalOnly rejects requests that IsInternal does not recognise, for
+// operational endpoints that must not be reachable by clients.
+func InternalOnly(t *InternalTraffic) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !t.IsInternal(c) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Forbidden",
+			})
+		}
+		return c.Next()
+	}
+}
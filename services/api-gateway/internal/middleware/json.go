@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireJSON answers requests whose body is not JSON with 415, before a
+// handler's BodyParser tries to decode it as a form or XML and fails with a
+// confusing error. Content-Type parameters such as charset are allowed.
+//
+// Requests without a body pass, which covers reads and WebSocket upgrades.
+// Requests under the exempt path prefixes, such as multipart uploads, are
+// not checked.
+func RequireJSON(exemptPrefixes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(c.Body()) == 0 {
+			return c.Next()
+		}
+		for _, prefix := range exemptPrefixes {
+			if strings.HasPrefix(c.Path(), prefix) {
+				return c.Next()
+			}
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+		if err == nil && mediaType == fiber.MIMEApplicationJSON {
+			return c.Next()
+		}
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error":     "Request body must be JSON, sent with Content-Type: application/json",
+			"status":    fiber.StatusUnsupportedMediaType,
+			"timestamp": time.Now().Unix(),
+		})
+	}
+}
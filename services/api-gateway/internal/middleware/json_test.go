@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireJSON(t *testing.T) {
+	app := fiber.New()
+	app.Use("/api/v1", middleware.RequireJSON("/api/v1/uploads"))
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Post("/api/v1/auth/login", ok)
+	app.Get("/api/v1/ilo/test", ok)
+	app.Post("/api/v1/uploads", ok)
+
+	send := func(method, path, contentType, body string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("form-encoded body is rejected", func(t *testing.T) {
+		resp := send(http.MethodPost, "/api/v1/auth/login", "application/x-www-form-urlencoded", "email=a%40b.c&password=x")
+		assert.Equal(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+
+	t.Run("body without a content type is rejected", func(t *testing.T) {
+		resp := send(http.MethodPost, "/api/v1/auth/login", "", `{"email":"a@b.c"}`)
+		assert.Equal(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+
+	t.Run("JSON passes through", func(t *testing.T) {
+		for _, contentType := range []string{"application/json", "application/json; charset=utf-8", "Application/JSON;charset=UTF-8"} {
+			resp := send(http.MethodPost, "/api/v1/auth/login", contentType, `{"email":"a@b.c"}`)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode, contentType)
+		}
+	})
+
+	t.Run("requests without a body pass", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusOK, send(http.MethodGet, "/api/v1/ilo/test", "", "").StatusCode)
+		assert.Equal(t, fiber.StatusOK, send(http.MethodPost, "/api/v1/auth/login", "text/plain", "").StatusCode)
+	})
+
+	t.Run("exempt routes are not checked", func(t *testing.T) {
+		resp := send(http.MethodPost, "/api/v1/uploads", "multipart/form-data; boundary=x", "--x--")
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
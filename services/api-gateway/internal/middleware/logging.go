@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+)
+
+// Redacted replaces sensitive values in logs.
+const Redacted = "[REDACTED]"
+
+const (
+	// TagClientType and TagAppVersion log the caller's ClientInfo
+	TagClientType = "clientType"
+	TagAppVersion = "appVersion"
+
+	// DefaultLogFormat is Fiber's default access log format with the
+	// caller's ClientInfo added
+	DefaultLogFormat = "${time} | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${clientType} | ${appVersion} | ${error}\n"
+)
+
+var (
+	// DefaultRedactedHeaders are always redacted, whatever is configured.
+	DefaultRedactedHeaders = []string{fiber.HeaderAuthorization, fiber.HeaderCookie, fiber.HeaderSetCookie, InternalSecretHeader}
+	// DefaultRedactedQueryParams are always redacted, whatever is configured.
+	DefaultRedactedQueryParams = []string{"token", "access_token", "refresh_token"}
+
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[^\s",]+`)
+	jwtPattern    = regexp.MustCompile(`eyJ[\w-]+\.[\w-]+\.[\w-]*`)
+	// Conversation share tokens travel in the path
+	sharePathPattern = regexp.MustCompile(`(/(?:shared|shares)/)[\w.-]+`)
+)
+
+// RedactionOptions configures a Redactor. Headers and QueryParams add to the
+// defaults.
+type RedactionOptions struct {
+	Headers     []string
+	QueryParams []string
+	// MaxBodyLength truncates logged bodies and prompts; zero logs them whole
+	MaxBodyLength int
+	// HashBodies logs a digest instead of bodies and prompts, so repeated
+	// requests can still be matched up
+	HashBodies bool
+}
+
+// Redactor keeps credentials and user content out of logs.
+type Redactor struct {
+	headers       map[string]bool
+	queryParams   map[string]bool
+	queryPattern  *regexp.Regexp
+	maxBodyLength int
+	hashBodies    bool
+}
+
+// NewRedactor builds a Redactor from opts.
+func NewRedactor(opts RedactionOptions) *Redactor {
+	r := &Redactor{
+		headers:       make(map[string]bool),
+		queryParams:   make(map[string]bool),
+		maxBodyLength: opts.MaxBodyLength,
+		hashBodies:    opts.HashBodies,
+	}
+	for _, h := range append(DefaultRedactedHeaders, opts.Headers...) {
+		r.headers[strings.ToLower(strings.TrimSpace(h))] = true
+	}
+	var params []string
+	for _, p := range append(DefaultRedactedQueryParams, opts.QueryParams...) {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p != "" && !r.queryParams[p] {
+			r.queryParams[p] = true
+			params = append(params, regexp.QuoteMeta(p))
+		}
+	}
+	r.queryPattern = regexp.MustCompile(`(?i)([?&](?:` + strings.Join(params, "|") + `)=)[^&\s"]*`)
+	return r
+}
+
+// Header returns value, or Redacted if the header is sensitive.
+func (r *Redactor) Header(name, value string) string {
+	if value != "" && r.headers[strings.ToLower(name)] {
+		return Redacted
+	}
+	return value
+}
+
+// Headers formats the request headers as name=value pairs, sorted by name.
+func (r *Redactor) Headers(c *fiber.Ctx) string {
+	var pairs []string
+	for name, values := range c.GetReqHeaders() {
+		pairs = append(pairs, name+"="+r.Header(name, strings.Join(values, ",")))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// Query returns the query string with sensitive parameters redacted.
+func (r *Redactor) Query(c *fiber.Ctx) string {
+	var pairs []string
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		v := string(value)
+		if r.queryParams[strings.ToLower(string(key))] {
+			v = Redacted
+		}
+		pairs = append(pairs, string(key)+"="+v)
+	})
+	return strings.Join(pairs, "&")
+}
+
+// Text shortens a body or prompt for logging: hashed if HashBodies is set,
+// otherwise cut to MaxBodyLength.
+func (r *Redactor) Text(s string) string {
+	if s == "" {
+		return s
+	}
+	if r.hashBodies {
+		sum := sha256.Sum256([]byte(s))
+		return fmt.Sprintf("[%d bytes sha256:%s]", len(s), hex.EncodeToString(sum[:6]))
+	}
+	if r.maxBodyLength <= 0 || len(s) <= r.maxBodyLength {
+		return s
+	}
+	cut := r.maxBodyLength
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s...[%d bytes]", s[:cut], len(s))
+}
+
+// Scrub removes bearer tokens, JWTs, share tokens and sensitive query
+// parameters from free-form log text.
+func (r *Redactor) Scrub(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "${1}"+Redacted)
+	s = jwtPattern.ReplaceAllString(s, Redacted)
+	s = sharePathPattern.ReplaceAllString(s, "${1}"+Redacted)
+	return r.queryPattern.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// Writer scrubs everything written through it before passing it to w. Wrap
+// the standard logger's output with it so handler and client logs are
+// covered too.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &scrubWriter{r: r, w: w}
+}
+
+type scrubWriter struct {
+	r *Redactor
+	w io.Writer
+}
+
+func (s *scrubWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(s.w, s.r.Scrub(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// RequestLogger is logger.New with every tag that can print headers, query
+// parameters or bodies going through r, and its output scrubbed. It adds the
+// TagClientType and TagAppVersion tags, and an empty format means
+// DefaultLogFormat.
+func RequestLogger(r *Redactor, cfg logger.Config) fiber.Handler {
+	if cfg.Output == nil {
+		cfg.Output = os.Stdout
+	}
+	if cfg.Format == "" {
+		cfg.Format = DefaultLogFormat
+	}
+	cfg.Output = r.Writer(cfg.Output)
+
+	header := func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, name string) (int, error) {
+		return output.WriteString(r.Header(name, c.Get(name)))
+	}
+	tags := map[string]logger.LogFunc{
+		logger.TagReqHeaders: func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, _ string) (int, error) {
+			return output.WriteString(r.Headers(c))
+		},
+		logger.TagHeader:    header,
+		logger.TagReqHeader: header,
+		logger.TagCookie: func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, name string) (int, error) {
+			return output.WriteString(r.Header(fiber.HeaderCookie, c.Cookies(name)))
+		},
+		logger.TagQueryStringParams: func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, _ string) (int, error) {
+			return output.WriteString(r.Query(c))
+		},
+		logger.TagQuery: func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, name string) (int, error) {
+			v := c.Query(name)
+			if v != "" && r.queryParams[strings.ToLower(name)] {
+				v = Redacted
+			}
+			return output.WriteString(v)
+		},
+		logger.TagBody: func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, _ string) (int, error) {
+			return output.WriteString(r.Text(string(c.Body())))
+		},
+		logger.TagResBody: func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, _ string) (int, error) {
+			return output.WriteString(r.Text(string(c.Response().Body())))
+		},
+		TagClientType: func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, _ string) (int, error) {
+			return output.WriteString(ClientInfoFrom(c).Type)
+		},
+		TagAppVersion: func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, _ string) (int, error) {
+			return output.WriteString(ClientInfoFrom(c).AppVersion)
+		},
+	}
+	for name, fn := range cfg.CustomTags {
+		tags[name] = fn
+	}
+	cfg.CustomTags = tags
+	return logger.New(cfg)
+}
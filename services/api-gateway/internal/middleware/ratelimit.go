@@ -1,56 +1,179 @@
 package middleware
 
 import (
+	"fmt"
+	"log"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/chaterr"
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
 )
 
-// RateLimitMiddleware creates a Redis-backed rate limiter for Fiber
-func RateLimitMiddleware(client *redis.Client, requestsPerMinute int) fiber.Handler {
+// FailureMode is what the rate limiter does with requests while Redis is
+// failing.
+type FailureMode string
+
+const (
+	// FailOpen lets requests through uncounted
+	FailOpen FailureMode = "open"
+	// FailClosed rejects requests with 503
+	FailClosed FailureMode = "closed"
+	// FailLocal counts requests in memory, per gateway instance
+	FailLocal FailureMode = "local"
+)
+
+// ParseFailureMode validates a configured failure mode; empty means FailOpen.
+func ParseFailureMode(s string) (FailureMode, error) {
+	switch mode := FailureMode(s); mode {
+	case "":
+		return FailOpen, nil
+	case FailOpen, FailClosed, FailLocal:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown rate limit failure mode %q (want open, closed or local)", s)
+}
+
+// RateLimiter is a Redis-backed, per-IP rate limiter. When Redis fails it
+// degrades according to its FailureMode instead of failing every request,
+// and reports the degradation through Degraded.
+type RateLimiter struct {
+	client            *redis.Client
+	requestsPerMinute int
+	internal          *InternalTraffic
+	mode              FailureMode
+	local             localCounter
+	// degradedSince is when Redis started failing, in Unix nanoseconds; zero
+	// while it works
+	degradedSince atomic.Int64
+}
+
+// NewRateLimiter limits each IP to requestsPerMinute. Requests recognised by
+// internal are passed through without being counted.
+func NewRateLimiter(client *redis.Client, requestsPerMinute int, internal *InternalTraffic, mode FailureMode) *RateLimiter {
+	return &RateLimiter{
+		client:            client,
+		requestsPerMinute: requestsPerMinute,
+		internal:          internal,
+		mode:              mode,
+	}
+}
+
+// Degraded returns an error describing the fallback in use while Redis is
+// failing, and nil otherwise. It suits a readiness check.
+func (l *RateLimiter) Degraded() error {
+	since := l.degradedSince.Load()
+	if since == 0 {
+		return nil
+	}
+	return fmt.Errorf("redis unavailable since %s; failing %s", time.Unix(0, since).UTC().Format(time.RFC3339), l.mode)
+}
+
+// Middleware counts each request against its IP's limit, answering 429 once
+// the limit is reached.
+func (l *RateLimiter) Middleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		ip := c.IP()
-		key := "rate_limit:" + ip
-
-		// Get current count
-		count, err := client.Get(c.Context(), key).Int()
-		if err != nil && err != redis.Nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Internal server error",
-			})
+		if l.internal.IsInternal(c) {
+			return c.Next()
 		}
 
+		count, err := l.count(c)
+		if err != nil {
+			l.degrade(err)
+			switch l.mode {
+			case FailClosed:
+				return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+					"error":     "Rate limiter unavailable; please try again shortly",
+					"status":    fiber.StatusServiceUnavailable,
+					"timestamp": time.Now().Unix(),
+				})
+			case FailLocal:
+				count = l.local.incr(c.IP(), time.Now())
+			default:
+				return c.Next()
+			}
+		} else {
+			l.recovered()
+		}
+
+		reset := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+		c.Set("X-RateLimit-Limit", strconv.Itoa(l.requestsPerMinute))
+		c.Set("X-RateLimit-Reset", reset)
+
 		// Check if limit exceeded
-		if count >= requestsPerMinute {
-			c.Set("X-RateLimit-Limit", strconv.Itoa(requestsPerMinute))
+		if count >= l.requestsPerMinute {
 			c.Set("X-RateLimit-Remaining", "0")
-			c.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
 			c.Set("Retry-After", "60")
 
 			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
 				"error":       "Rate limit exceeded",
+				"error_code":  chaterr.RateLimited,
 				"retry_after": 60,
 			})
 		}
 
-		// Increment counter
-		pipe := client.Pipeline()
-		pipe.Incr(c.Context(), key)
-		pipe.Expire(c.Context(), key, time.Minute)
-		_, err = pipe.Exec(c.Context())
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Internal server error",
-			})
-		}
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(l.requestsPerMinute-count-1))
+		return c.Next()
+	}
+}
 
-		// Add rate limit headers
-		c.Set("X-RateLimit-Limit", strconv.Itoa(requestsPerMinute))
-		c.Set("X-RateLimit-Remaining", strconv.Itoa(requestsPerMinute-count-1))
-		c.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+// count returns how many requests the caller's IP made this minute, before
+// this one, and counts this one unless the limit is already reached.
+func (l *RateLimiter) count(c *fiber.Ctx) (int, error) {
+	key := "rate_limit:" + c.IP()
 
-		return c.Next()
+	count, err := l.client.Get(c.Context(), key).Int()
+	if err != nil && err != redis.Nil {
+		return 0, err
+	}
+	if count >= l.requestsPerMinute {
+		return count, nil
+	}
+
+	pipe := l.client.Pipeline()
+	pipe.Incr(c.Context(), key)
+	pipe.Expire(c.Context(), key, time.Minute)
+	if _, err := pipe.Exec(c.Context()); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// degrade records that Redis failed, logging the first failure.
+func (l *RateLimiter) degrade(err error) {
+	if l.degradedSince.CompareAndSwap(0, time.Now().UnixNano()) {
+		log.Printf("Rate limiter: Redis unavailable (%v); failing %s until it recovers", err, l.mode)
+	}
+}
+
+// recovered records that Redis works again.
+func (l *RateLimiter) recovered() {
+	if l.degradedSince.Swap(0) != 0 {
+		log.Printf("Rate limiter: Redis recovered")
+	}
+}
+
+// localCounter counts requests per key in fixed one-minute windows, as a
+// stand-in for Redis. Counts are per gateway instance.
+type localCounter struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+}
+
+// incr counts a request for key and returns the count in the current window
+// before it.
+func (l *localCounter) incr(key string, now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.counts == nil || now.Sub(l.windowStart) >= time.Minute {
+		l.windowStart = now
+		l.counts = make(map[string]int)
 	}
+	count := l.counts[key]
+	l.counts[key] = count + 1
+	return count
 }
@@ -0,0 +1,173 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unreachableRedis returns a client whose every command fails, so with
+// FailClosed any request that reaches the counter surfaces as a 503.
+func unreachableRedis() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr: "redis:6379",
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return nil, errors.New("redis unavailable")
+		},
+		MaxRetries: -1,
+	})
+}
+
+func TestRateLimitMiddleware_InternalBypass(t *testing.T) {
+	newApp := func(t *testing.T, cidrs []string, secret string) *fiber.App {
+		internal, err := middleware.NewInternalTraffic(cidrs, secret)
+		require.NoError(t, err)
+
+		app := fiber.New()
+		app.Use(middleware.NewRateLimiter(unreachableRedis(), 10, internal, middleware.FailClosed).Middleware())
+		app.Get("/health", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+
+	t.Run("allowlisted IP bypasses the limiter", func(t *testing.T) {
+		// app.Test requests originate from 0.0.0.0
+		app := newApp(t, []string{"0.0.0.0/8"}, "")
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/health", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("X-RateLimit-Limit"))
+	})
+
+	t.Run("request with internal secret bypasses the limiter", func(t *testing.T) {
+		app := newApp(t, nil, "s3cret")
+
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.Header.Set(middleware.InternalSecretHeader, "s3cret")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("wrong secret is counted", func(t *testing.T) {
+		app := newApp(t, nil, "s3cret")
+
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.Header.Set(middleware.InternalSecretHeader, "guess")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("spoofed forwarded IP is counted", func(t *testing.T) {
+		app := newApp(t, []string{"10.0.0.0/8"}, "")
+
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.Header.Set("X-Forwarded-For", "10.0.0.5")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("empty secret never matches", func(t *testing.T) {
+		app := newApp(t, nil, "")
+
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.Header.Set(middleware.InternalSecretHeader, "")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+}
+
+func TestRateLimiter_RedisDown(t *testing.T) {
+	newApp := func(t *testing.T, mode middleware.FailureMode) (*fiber.App, *middleware.RateLimiter) {
+		internal, err := middleware.NewInternalTraffic(nil, "")
+		require.NoError(t, err)
+		limiter := middleware.NewRateLimiter(unreachableRedis(), 2, internal, mode)
+
+		app := fiber.New()
+		app.Use(limiter.Middleware())
+		app.Get("/api/v1/ilo/test", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app, limiter
+	}
+	get := func(t *testing.T, app *fiber.App) *http.Response {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/ilo/test", nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("fail open lets requests through uncounted", func(t *testing.T) {
+		app, limiter := newApp(t, middleware.FailOpen)
+		require.NoError(t, limiter.Degraded())
+
+		for i := 0; i < 5; i++ {
+			resp := get(t, app)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Empty(t, resp.Header.Get("X-RateLimit-Limit"))
+		}
+		err := limiter.Degraded()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failing open")
+	})
+
+	t.Run("fail closed rejects requests", func(t *testing.T) {
+		app, limiter := newApp(t, middleware.FailClosed)
+
+		resp := get(t, app)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Contains(t, limiter.Degraded().Error(), "failing closed")
+	})
+
+	t.Run("local fallback limits in memory", func(t *testing.T) {
+		app, limiter := newApp(t, middleware.FailLocal)
+
+		for i := 0; i < 2; i++ {
+			resp := get(t, app)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "2", resp.Header.Get("X-RateLimit-Limit"))
+		}
+		resp := get(t, app)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+		assert.Contains(t, limiter.Degraded().Error(), "failing local")
+	})
+}
+
+func TestParseFailureMode(t *testing.T) {
+	for in, want := range map[string]middleware.FailureMode{
+		"":       middleware.FailOpen,
+		"open":   middleware.FailOpen,
+		"closed": middleware.FailClosed,
+		"local":  middleware.FailLocal,
+	} {
+		got, err := middleware.ParseFailureMode(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := middleware.ParseFailureMode("sometimes")
+	assert.Error(t, err)
+}
+
+func TestNewInternalTraffic_InvalidCIDR(t *testing.T) {
+	_, err := middleware.NewInternalTraffic([]string{"not-an-ip"}, "")
+	assert.Error(t, err)
+
+	_, err = middleware.NewInternalTraffic([]string{"10.1.2.3", "fd00::/8"}, "")
+	assert.NoError(t, err)
+}
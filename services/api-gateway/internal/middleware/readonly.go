@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maintenanceMessage is returned to writes rejected in read-only mode.
+const maintenanceMessage = "CareerUP is under maintenance; changes are temporarily disabled. Please try again shortly."
+
+// ReadOnlyMode is a switch that rejects writes during maintenance (e.g.
+// migrations) while reads keep working. It is safe to flip while requests
+// are in flight.
+type ReadOnlyMode struct {
+	enabled atomic.Bool
+}
+
+// NewReadOnlyMode returns a switch in the given initial state.
+func NewReadOnlyMode(enabled bool) *ReadOnlyMode {
+	m := &ReadOnlyMode{}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// Enabled reports whether writes are currently rejected.
+func (m *ReadOnlyMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// Set turns read-only mode on or off.
+func (m *ReadOnlyMode) Set(enabled bool) {
+	if m.enabled.Swap(enabled) != enabled {
+		log.Printf("Read-only mode enabled=%t", enabled)
+	}
+}
+
+// Middleware answers mutating requests with 503 while read-only mode is on.
+// GET, HEAD and OPTIONS always pass, so it can guard a whole group; mount it
+// on the routes that write.
+func (m *ReadOnlyMode) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+		if !m.Enabled() {
+			return c.Next()
+		}
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error":     maintenanceMessage,
+			"status":    fiber.StatusServiceUnavailable,
+			"timestamp": time.Now().Unix(),
+		})
+	}
+}
+
+// HandleGet reports the current mode.
+func (m *ReadOnlyMode) HandleGet(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"read_only": m.Enabled()})
+}
+
+// HandleSet switches the mode from a {"read_only": bool} body.
+func (m *ReadOnlyMode) HandleSet(c *fiber.Ctx) error {
+	var req struct {
+		ReadOnly *bool `json:"read_only"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.ReadOnly == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":     `Body must be {"read_only": true|false}`,
+			"status":    fiber.StatusBadRequest,
+			"timestamp": time.Now().Unix(),
+		})
+	}
+	m.Set(*req.ReadOnly)
+	return c.JSON(fiber.Map{"read_only": m.Enabled()})
+}
@@ -0,0 +1,99 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readOnlyApp(mode *middleware.ReadOnlyMode) *fiber.App {
+	app := fiber.New()
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	rejectWrites := mode.Middleware()
+	app.Post("/register", rejectWrites, ok)
+	app.Put("/profile", rejectWrites, ok)
+	app.Get("/profile", rejectWrites, ok)
+	app.Get("/validate", ok)
+	return app
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	mode := middleware.NewReadOnlyMode(true)
+	app := readOnlyApp(mode)
+
+	send := func(method, path string) int {
+		resp, err := app.Test(httptest.NewRequest(method, path, nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("writes are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusServiceUnavailable, send(http.MethodPost, "/register"))
+		assert.Equal(t, http.StatusServiceUnavailable, send(http.MethodPut, "/profile"))
+	})
+
+	t.Run("reads still work", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/profile"))
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/validate"))
+	})
+
+	t.Run("writes resume once disabled", func(t *testing.T) {
+		mode.Set(false)
+		assert.Equal(t, http.StatusOK, send(http.MethodPost, "/register"))
+		assert.Equal(t, http.StatusOK, send(http.MethodPut, "/profile"))
+	})
+}
+
+func TestReadOnlyMode_AdminToggle(t *testing.T) {
+	mode := middleware.NewReadOnlyMode(false)
+	internal, err := middleware.NewInternalTraffic(nil, "s3cret")
+	require.NoError(t, err)
+
+	app := readOnlyApp(mode)
+	admin := app.Group("/admin", middleware.InternalOnly(internal))
+	admin.Get("/read-only", mode.HandleGet)
+	admin.Post("/read-only", mode.HandleSet)
+
+	toggle := func(body, secret string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/admin/read-only", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if secret != "" {
+			req.Header.Set(middleware.InternalSecretHeader, secret)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("clients cannot toggle", func(t *testing.T) {
+		resp := toggle(`{"read_only": true}`, "")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.False(t, mode.Enabled())
+	})
+
+	t.Run("internal caller enables read-only mode", func(t *testing.T) {
+		resp := toggle(`{"read_only": true}`, "s3cret")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var body map[string]bool
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.True(t, body["read_only"])
+		assert.True(t, mode.Enabled())
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/register", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("body without the flag is rejected", func(t *testing.T) {
+		resp := toggle(`{}`, "s3cret")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.True(t, mode.Enabled())
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Timeout bounds the handlers behind it to d. The deadline is carried by
+// c.UserContext(), which handlers pass to their backend calls; a request
+// that runs past it is answered with 504 whatever the handler wrote. A
+// non-positive d disables the timeout.
+//
+// Do not mount it on the WebSocket route: the connection outlives the
+// upgrade request by design.
+func Timeout(d time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if d <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), d)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+			return c.Status(fiber.StatusGatewayTimeout).JSON(fiber.Map{
+				"error":     "Request timed out",
+				"status":    fiber.StatusGatewayTimeout,
+				"timestamp": time.Now().Unix(),
+			})
+		}
+		return err
+	}
+}
@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout(t *testing.T) {
+	app := fiber.New()
+	timed := app.Group("/timed", middleware.Timeout(20*time.Millisecond))
+	// Stands in for a handler blocked on a slow gRPC backend
+	timed.Get("/slow", func(c *fiber.Ctx) error {
+		select {
+		case <-c.UserContext().Done():
+			return c.Status(fiber.StatusInternalServerError).SendString(c.UserContext().Err().Error())
+		case <-time.After(time.Second):
+			return c.SendStatus(fiber.StatusOK)
+		}
+	})
+	timed.Get("/fast", func(c *fiber.Ctx) error {
+		return c.SendString("done")
+	})
+	app.Get("/untimed", func(c *fiber.Ctx) error {
+		_, hasDeadline := c.UserContext().Deadline()
+		assert.False(t, hasDeadline)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	t.Run("slow handler gets 504", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/timed/slow", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	})
+
+	t.Run("fast handler passes through", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/timed/fast", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("routes outside the group have no deadline", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/untimed", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestTimeout_Disabled(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", middleware.Timeout(0), func(c *fiber.Ctx) error {
+		_, hasDeadline := c.UserContext().Deadline()
+		assert.False(t, hasDeadline)
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
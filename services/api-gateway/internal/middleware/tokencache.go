@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/patrickmn/go-cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultTokenCacheTTL is how long a validated token is trusted without
+// asking auth-core again, when no TTL is configured.
+const DefaultTokenCacheTTL = 5 * time.Minute
+
+// TokenCache holds the users of validated tokens, so that AuthMiddleware
+// needn't ask auth-core on every request.
+type TokenCache interface {
+	// Get returns the user of a cached token; found is false on a miss
+	Get(ctx context.Context, token string) (user *client.User, found bool, err error)
+	// Set caches the user of a validated token for the cache's TTL
+	Set(ctx context.Context, token string, user *client.User) error
+	// Evict forgets a token, so its next use is validated again
+	Evict(ctx context.Context, token string) error
+}
+
+// NewTokenCache returns the token cache for backend: "memory" (the default)
+// keeps tokens per gateway instance, "redis" shares them, and their
+// eviction, between instances.
+func NewTokenCache(backend string, redisClient *redis.Client, ttl time.Duration) (TokenCache, error) {
+	if ttl <= 0 {
+		ttl = DefaultTokenCacheTTL
+	}
+	switch backend {
+	case "", "memory":
+		return NewMemoryTokenCache(ttl), nil
+	case "redis":
+		return NewRedisTokenCache(redisClient, ttl), nil
+	}
+	return nil, fmt.Errorf("unknown token cache %q (want memory or redis)", backend)
+}
+
+// MemoryTokenCache is a TokenCache local to the process.
+type MemoryTokenCache struct {
+	entries *cache.Cache
+}
+
+func NewMemoryTokenCache(ttl time.Duration) *MemoryTokenCache {
+	return &MemoryTokenCache{entries: cache.New(ttl, 2*ttl)}
+}
+
+func (c *MemoryTokenCache) Get(ctx context.Context, token string) (*client.User, bool, error) {
+	user, found := c.entries.Get(token)
+	if !found {
+		return nil, false, nil
+	}
+	return user.(*client.User), true, nil
+}
+
+func (c *MemoryTokenCache) Set(ctx context.Context, token string, user *client.User) error {
+	c.entries.Set(token, user, cache.DefaultExpiration)
+	return nil
+}
+
+func (c *MemoryTokenCache) Evict(ctx context.Context, token string) error {
+	c.entries.Delete(token)
+	return nil
+}
+
+// RedisTokenCache is a TokenCache shared through Redis. Tokens are stored
+// under their SHA-256, never in the clear.
+type RedisTokenCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRedisTokenCache(client *redis.Client, ttl time.Duration) *RedisTokenCache {
+	return &RedisTokenCache{client: client, ttl: ttl}
+}
+
+func (c *RedisTokenCache) Get(ctx context.Context, token string) (*client.User, bool, error) {
+	data, err := c.client.Get(ctx, tokenCacheKey(token)).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var user client.User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, false, fmt.Errorf("corrupt cached token: %w", err)
+	}
+	return &user, true, nil
+}
+
+func (c *RedisTokenCache) Set(ctx context.Context, token string, user *client.User) error {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, tokenCacheKey(token), data, c.ttl).Err()
+}
+
+func (c *RedisTokenCache) Evict(ctx context.Context, token string) error {
+	return c.client.Del(ctx, tokenCacheKey(token)).Err()
+}
+
+func tokenCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "auth_token:" + hex.EncodeToString(sum[:])
+}
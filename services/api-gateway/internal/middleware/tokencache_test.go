@@ -0,0 +1,240 @@
+package middleware_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/middleware"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis serves the few commands the token cache uses, GET, SET (with
+// PX or EX) and DEL, from memory over in-process connections.
+type fakeRedis struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+}
+
+// newFakeRedis returns a client of a fresh fakeRedis, and the server
+func newFakeRedis(t *testing.T) (*redis.Client, *fakeRedis) {
+	t.Helper()
+	srv := &fakeRedis{values: make(map[string]string), expires: make(map[string]time.Time)}
+	rdb := redis.NewClient(&redis.Options{
+		Addr: "redis:6379",
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, peer := net.Pipe()
+			go srv.serve(peer)
+			return conn, nil
+		},
+		MaxRetries: -1,
+	})
+	t.Cleanup(func() { rdb.Close() })
+	return rdb, srv
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if _, err := conn.Write([]byte(s.exec(args))); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil { // $<length>
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func (s *fakeRedis) exec(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "GET":
+		value, ok := s.lookup(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "SET":
+		s.values[args[1]] = args[2]
+		delete(s.expires, args[1])
+		if len(args) == 5 {
+			n, _ := strconv.Atoi(args[4])
+			unit := time.Millisecond
+			if strings.EqualFold(args[3], "EX") {
+				unit = time.Second
+			}
+			s.expires[args[1]] = time.Now().Add(time.Duration(n) * unit)
+		}
+		return "+OK\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.lookup(key); ok {
+				deleted++
+			}
+			delete(s.values, key)
+			delete(s.expires, key)
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	case "CLIENT":
+		return "+OK\r\n"
+	}
+	// Including HELLO, which makes the client fall back to RESP2
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+func (s *fakeRedis) lookup(key string) (string, bool) {
+	if expiry, ok := s.expires[key]; ok && !time.Now().Before(expiry) {
+		delete(s.values, key)
+		delete(s.expires, key)
+	}
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// keys returns the keys stored, expired or not
+func (s *fakeRedis) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestTokenCaches(t *testing.T) {
+	const ttl = 50 * time.Millisecond
+	caches := map[string]func(t *testing.T) middleware.TokenCache{
+		"memory": func(t *testing.T) middleware.TokenCache {
+			return middleware.NewMemoryTokenCache(ttl)
+		},
+		"redis": func(t *testing.T) middleware.TokenCache {
+			rdb, _ := newFakeRedis(t)
+			return middleware.NewRedisTokenCache(rdb, ttl)
+		},
+	}
+	user := &client.User{ID: "user-1", Email: "an@example.com", Interests: []string{"robotics"}}
+	ctx := context.Background()
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			t.Run("set then get", func(t *testing.T) {
+				tokens := newCache(t)
+				_, found, err := tokens.Get(ctx, "token-1")
+				require.NoError(t, err)
+				assert.False(t, found)
+
+				require.NoError(t, tokens.Set(ctx, "token-1", user))
+				cached, found, err := tokens.Get(ctx, "token-1")
+				require.NoError(t, err)
+				require.True(t, found)
+				assert.Equal(t, user, cached)
+
+				_, found, err = tokens.Get(ctx, "token-2")
+				require.NoError(t, err)
+				assert.False(t, found)
+			})
+
+			t.Run("evict", func(t *testing.T) {
+				tokens := newCache(t)
+				require.NoError(t, tokens.Set(ctx, "token-1", user))
+				require.NoError(t, tokens.Evict(ctx, "token-1"))
+				_, found, err := tokens.Get(ctx, "token-1")
+				require.NoError(t, err)
+				assert.False(t, found)
+
+				// Evicting an unknown token is not an error
+				require.NoError(t, tokens.Evict(ctx, "token-2"))
+			})
+
+			t.Run("expiry", func(t *testing.T) {
+				tokens := newCache(t)
+				require.NoError(t, tokens.Set(ctx, "token-1", user))
+				time.Sleep(2 * ttl)
+				_, found, err := tokens.Get(ctx, "token-1")
+				require.NoError(t, err)
+				assert.False(t, found)
+			})
+		})
+	}
+}
+
+func TestRedisTokenCache_SharedBetweenInstances(t *testing.T) {
+	rdb, srv := newFakeRedis(t)
+	first := middleware.NewRedisTokenCache(rdb, time.Minute)
+	second := middleware.NewRedisTokenCache(rdb, time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, first.Set(ctx, "secret-token", &client.User{ID: "user-1"}))
+	cached, found, err := second.Get(ctx, "secret-token")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "user-1", cached.ID)
+
+	// Tokens are not stored in the clear
+	for _, key := range srv.keys() {
+		assert.NotContains(t, key, "secret-token")
+	}
+
+	require.NoError(t, second.Evict(ctx, "secret-token"))
+	_, found, err = first.Get(ctx, "secret-token")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestRedisTokenCache_Unavailable(t *testing.T) {
+	tokens := middleware.NewRedisTokenCache(unreachableRedis(), time.Minute)
+	_, found, err := tokens.Get(context.Background(), "token-1")
+	assert.Error(t, err)
+	assert.False(t, found)
+}
+
+func TestNewTokenCache(t *testing.T) {
+	tokens, err := middleware.NewTokenCache("", nil, 0)
+	require.NoError(t, err)
+	assert.IsType(t, &middleware.MemoryTokenCache{}, tokens)
+
+	tokens, err = middleware.NewTokenCache("redis", unreachableRedis(), 0)
+	require.NoError(t, err)
+	assert.IsType(t, &middleware.RedisTokenCache{}, tokens)
+
+	_, err = middleware.NewTokenCache("memcached", nil, 0)
+	assert.ErrorContains(t, err, `unknown token cache "memcached"`)
+}
@@ -0,0 +1,182 @@
+// Package share issues and checks the read-only links users hand out to let
+// someone else read one of their conversations without an account.
+//
+// A link's token is the share itself, signed by the gateway, so any
+// instance can check it without a lookup. Only revocations are stored, until
+// the revoked token would have expired anyway.
+package share
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultTTL is how long a share lasts when no TTL is configured
+	DefaultTTL = 7 * 24 * time.Hour
+	// DefaultMaxTTL caps the TTL users may ask for when no maximum is
+	// configured
+	DefaultMaxTTL = 30 * 24 * time.Hour
+)
+
+// ErrNotFound is returned for tokens the gateway did not issue, including
+// ones that were altered.
+var ErrNotFound = errors.New("share not found")
+
+// Share is what a token grants: read access to one conversation of one user
+// until ExpiresAt, unless it is revoked first.
+type Share struct {
+	ConversationID string    `json:"conversation_id"`
+	UserID         string    `json:"user_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// Expired reports whether s no longer grants access at now.
+func (s Share) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// Signer turns shares into tokens and back. Tokens are sealed with AES-GCM
+// under a key derived from the secret: its authentication tag is the
+// signature, so a token cannot be forged or altered, and the encryption
+// keeps the owner's ID out of the link.
+type Signer struct {
+	aead cipher.AEAD
+}
+
+// NewSigner returns a Signer for secret, which every gateway instance must
+// share for their links to work on each other.
+func NewSigner(secret string) (*Signer, error) {
+	if secret == "" {
+		return nil, errors.New("share secret is empty")
+	}
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{aead: aead}, nil
+}
+
+// Sign returns a new token for share. Every call gets its own token, so
+// links to the same conversation are revoked one by one.
+func (s *Signer) Sign(share Share) (string, error) {
+	claims, err := json.Marshal(share)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(s.aead.Seal(nonce, nonce, claims, nil)), nil
+}
+
+// Verify returns the share token was signed with, or ErrNotFound. It does
+// not check expiry or revocation.
+func (s *Signer) Verify(token string) (Share, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return Share{}, ErrNotFound
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	claims, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return Share{}, ErrNotFound
+	}
+	var share Share
+	if err := json.Unmarshal(claims, &share); err != nil {
+		return Share{}, ErrNotFound
+	}
+	return share, nil
+}
+
+// Store keeps the tokens revoked before they expired, until they expire.
+type Store interface {
+	// Revoke stops token from granting access before expiresAt
+	Revoke(ctx context.Context, token string, expiresAt time.Time) error
+	// Revoked reports whether token was revoked
+	Revoked(ctx context.Context, token string) (bool, error)
+}
+
+// NewStore returns the revocation store for backend: "memory" (the default)
+// keeps revocations per gateway instance, so a revoked link keeps working on
+// the other instances; "redis" shares them between instances.
+func NewStore(backend string, redisClient *redis.Client) (Store, error) {
+	switch backend {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "redis":
+		return NewRedisStore(redisClient), nil
+	}
+	return nil, fmt.Errorf("unknown share store %q (want memory or redis)", backend)
+}
+
+// MemoryStore is a Store local to the process.
+type MemoryStore struct {
+	entries *cache.Cache
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: cache.New(cache.NoExpiration, 10*time.Minute)}
+}
+
+func (s *MemoryStore) Revoke(ctx context.Context, token string, expiresAt time.Time) error {
+	if ttl := time.Until(expiresAt); ttl > 0 {
+		s.entries.Set(tokenKey(token), struct{}{}, ttl)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Revoked(ctx context.Context, token string) (bool, error) {
+	_, found := s.entries.Get(tokenKey(token))
+	return found, nil
+}
+
+// RedisStore is a Store shared through Redis.
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Revoke(ctx context.Context, token string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, tokenKey(token), 1, ttl).Err()
+}
+
+func (s *RedisStore) Revoked(ctx context.Context, token string) (bool, error) {
+	n, err := s.client.Exists(ctx, tokenKey(token)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// tokenKey is where the revocation of token is kept: tokens are
+// credentials, so only their hash is stored.
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "share:revoked:" + hex.EncodeToString(sum[:])
+}
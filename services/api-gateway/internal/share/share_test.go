@@ -0,0 +1,103 @@
+package share
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(t *testing.T, secret string) *Signer {
+	t.Helper()
+	signer, err := NewSigner(secret)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestSigner(t *testing.T) {
+	signer := newTestSigner(t, "test-secret")
+	shared := Share{ConversationID: "conv-1", UserID: "user-1", ExpiresAt: time.Date(2026, 10, 23, 9, 0, 0, 0, time.UTC)}
+
+	token, err := signer.Sign(shared)
+	require.NoError(t, err)
+	verified, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, shared, verified)
+
+	other, err := signer.Sign(shared)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other, "every share gets its own token")
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "user-1", "the owner's ID is not readable from the token")
+
+	t.Run("rejects tokens signed with another secret", func(t *testing.T) {
+		forged, err := newTestSigner(t, "other-secret").Sign(shared)
+		require.NoError(t, err)
+		_, err = signer.Verify(forged)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("rejects altered tokens", func(t *testing.T) {
+		altered := append([]byte(nil), raw...)
+		altered[len(altered)-1] ^= 1
+		_, err := signer.Verify(base64.RawURLEncoding.EncodeToString(altered))
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("rejects malformed tokens", func(t *testing.T) {
+		for _, token := range []string{"", "not a token", "c2hvcnQ"} {
+			_, err := signer.Verify(token)
+			assert.ErrorIs(t, err, ErrNotFound, token)
+		}
+	})
+
+	_, err = NewSigner("")
+	assert.Error(t, err, "a share secret is required")
+}
+
+func TestShare_Expired(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	share := Share{ExpiresAt: now.Add(time.Hour)}
+	assert.False(t, share.Expired(now.Add(59*time.Minute)))
+	assert.True(t, share.Expired(now.Add(time.Hour)))
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	revoked, err := store.Revoked(ctx, "token-1")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, store.Revoke(ctx, "token-1", time.Now().Add(time.Hour)))
+	revoked, err = store.Revoked(ctx, "token-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// An expired token no longer works, so its revocation is not kept
+	require.NoError(t, store.Revoke(ctx, "token-2", time.Now().Add(-time.Minute)))
+	revoked, err = store.Revoked(ctx, "token-2")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenKey(t *testing.T) {
+	key := tokenKey("token-1")
+	assert.NotContains(t, key, "token-1", "tokens are not stored in the clear")
+	assert.Equal(t, key, tokenKey("token-1"))
+}
+
+func TestNewStore(t *testing.T) {
+	store, err := NewStore("", nil)
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryStore{}, store)
+
+	_, err = NewStore("disk", nil)
+	assert.ErrorContains(t, err, "unknown share store")
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Page size limits for list endpoints
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Pagination describes where a page sits in a list.
+type Pagination struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	// Opaque page_token of the next page; null on the last page
+	Next *string `json:"next"`
+}
+
+// ListResponse is the envelope list endpoints respond with.
+type ListResponse[T any] struct {
+	Data       []T        `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// errInvalidPageToken is returned for page tokens Paginate did not issue.
+var errInvalidPageToken = errors.New("invalid page_token")
+
+// PageParams reads the limit query parameter, defaulting it and capping it
+// at MaxPageLimit, and where the page starts: the page_token of a previous
+// page's pagination.next, else the offset parameter.
+func PageParams(c *fiber.Ctx) (limit, offset int, err error) {
+	limit = c.QueryInt("limit", DefaultPageLimit)
+	offset = c.QueryInt("offset", 0)
+	if token := c.Query("page_token"); token != "" {
+		if offset, err = decodePageToken(token); err != nil {
+			return 0, 0, err
+		}
+	}
+	if limit <= 0 {
+		return 0, 0, fmt.Errorf("limit must be positive")
+	}
+	if offset < 0 {
+		return 0, 0, fmt.Errorf("offset must not be negative")
+	}
+	return min(limit, MaxPageLimit), offset, nil
+}
+
+// Paginate returns the page of items at offset. Data is never null, so
+// clients can always iterate it.
+func Paginate[T any](items []T, limit, offset int) ListResponse[T] {
+	page := ListResponse[T]{
+		Data:       []T{},
+		Pagination: Pagination{Total: len(items), Limit: limit, Offset: offset},
+	}
+	if offset >= len(items) {
+		return page
+	}
+	end := min(offset+limit, len(items))
+	page.Data = items[offset:end]
+	if end < len(items) {
+		next := encodePageToken(end)
+		page.Pagination.Next = &next
+	}
+	return page
+}
+
+// encodePageToken returns the page token of the page at offset. Clients
+// treat it as opaque, as they do llm-gateway's admin API page tokens.
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errInvalidPageToken
+	}
+	offset, err := strconv.Atoi(string(b))
+	if err != nil || offset < 0 {
+		return 0, errInvalidPageToken
+	}
+	return offset, nil
+}
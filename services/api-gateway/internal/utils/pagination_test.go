@@ -0,0 +1,73 @@
+package utils_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+
+	page := utils.Paginate(items, 2, 0)
+	assert.Equal(t, []string{"a", "b"}, page.Data)
+	assert.Equal(t, 5, page.Pagination.Total)
+	require.NotNil(t, page.Pagination.Next)
+	assert.NotEqual(t, "2", *page.Pagination.Next, "the token is opaque")
+
+	page = utils.Paginate(items, 2, 4)
+	assert.Equal(t, []string{"e"}, page.Data)
+	assert.Nil(t, page.Pagination.Next)
+
+	page = utils.Paginate(items, 2, 10)
+	assert.Equal(t, []string{}, page.Data)
+	assert.Equal(t, 5, page.Pagination.Total)
+
+	page = utils.Paginate[string](nil, 20, 0)
+	assert.NotNil(t, page.Data)
+	assert.Nil(t, page.Pagination.Next)
+}
+
+func TestPageParams(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		limit, offset, err := utils.PageParams(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+		return c.JSON(fiber.Map{"limit": limit, "offset": offset})
+	})
+
+	get := func(query string) (int, string) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/"+query, nil))
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.JSONEq(t, `{"limit":20,"offset":0}`, body)
+
+	_, body = get("?limit=500&offset=40")
+	assert.JSONEq(t, `{"limit":100,"offset":40}`, body)
+
+	page := utils.Paginate([]int{1, 2, 3, 4, 5}, 2, 2)
+	status, body = get("?limit=2&page_token=" + *page.Pagination.Next)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.JSONEq(t, `{"limit":2,"offset":4}`, body, "the token of the page after offset 2")
+
+	status, _ = get("?page_token=not-a-token")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	status, _ = get("?limit=0")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	status, _ = get("?offset=-1")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SSEWriter writes server-sent events to a streaming response
+type SSEWriter struct {
+	w *bufio.Writer
+}
+
+// Send writes one event and flushes it to the client straight away. An
+// empty event name sends a default "message" event. The returned error is
+// non-nil once the client has gone away.
+func (s *SSEWriter) Send(event, data string) error {
+	if event != "" {
+		if _, err := fmt.Fprintf(s.w, "event: %s\n", event); err != nil {
+			return err
+		}
+	}
+	for _, line := range strings.Split(data, "\n") {
+		if _, err := fmt.Fprintf(s.w, "data: %s\n", line); err != nil {
+			return err
+		}
+	}
+	if _, err := s.w.WriteString("\n"); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// StreamEvents responds with a text/event-stream fed by stream, which runs
+// after the handler returns. Buffering and compression would hold events
+// back, so the response opts out of both: proxies are told not to buffer
+// and the request's Accept-Encoding is dropped so compression middleware
+// leaves the body alone.
+func StreamEvents(c *fiber.Ctx, stream func(w *SSEWriter) error) error {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+	c.Request().Header.Del(fiber.HeaderAcceptEncoding)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := stream(&SSEWriter{w: w}); err != nil {
+			log.Printf("Event stream ended early: %v", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,73 @@
+package utils_test
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/api-gateway/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamEvents(t *testing.T) {
+	next := make(chan struct{})
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(compress.New())
+	app.Get("/stream", func(c *fiber.Ctx) error {
+		return utils.StreamEvents(c, func(w *utils.SSEWriter) error {
+			if err := w.Send("token", "Hello"); err != nil {
+				return err
+			}
+			// Hold the second event until the client has read the first
+			<-next
+			return w.Send("", "line one\nline two")
+		})
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+
+	req, err := http.NewRequest(http.MethodGet, "http://"+ln.Addr().String()+"/stream", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no", resp.Header.Get("X-Accel-Buffering"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	assert.Empty(t, resp.Header.Get("Content-Encoding"), "event streams must not be compressed")
+
+	body := bufio.NewReader(resp.Body)
+	readEvent := func() []string {
+		var lines []string
+		for {
+			line, err := body.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\n" {
+				return lines
+			}
+			lines = append(lines, line[:len(line)-1])
+		}
+	}
+
+	first := make(chan []string, 1)
+	go func() { first <- readEvent() }()
+	select {
+	case lines := <-first:
+		assert.Equal(t, []string{"event: token", "data: Hello"}, lines)
+	case <-time.After(2 * time.Second):
+		t.Fatal("first event was not flushed before the stream finished")
+	}
+
+	close(next)
+	assert.Equal(t, []string{"data: line one", "data: line two"}, readEvent())
+}
@@ -1,9 +1,11 @@
 package com.careerup.authcore;
 
+import com.careerup.authcore.security.GrpcUserIdInterceptor;
 import com.careerup.authcore.service.AuthGrpcService;
 import com.careerup.authcore.service.IloGrpcService;
 import io.grpc.Server;
 import io.grpc.ServerBuilder;
+import io.grpc.ServerInterceptors;
 import org.springframework.beans.factory.annotation.Autowired;
 import org.springframework.boot.CommandLineRunner;
 import org.springframework.stereotype.Component;
@@ -34,7 +36,7 @@ public class GrpcServerRunner implements CommandLineRunner {
             int grpcPort = 9091;
             server = ServerBuilder.forPort(grpcPort)
             .addService(authGrpcService)
-            .addService(iloGrpcService)
+            .addService(ServerInterceptors.intercept(iloGrpcService, new GrpcUserIdInterceptor()))
             .build()
             .start();
             System.out.println("gRPC server started on port " + grpcPort);
@@ -7,7 +7,8 @@ import java.util.List;
 import java.util.UUID;
 
 @Entity
-@Table(name = "ilo_test_results")
+@Table(name = "ilo_test_results", uniqueConstraints = @UniqueConstraint(
+        name = "ilo_test_result_idempotency_unique", columnNames = {"user_id", "idempotency_key"}))
 public class IloTestResult {
     @Id
     @GeneratedValue(strategy = GenerationType.IDENTITY)
@@ -31,6 +32,14 @@ public class IloTestResult {
     @Column(columnDefinition = "TEXT")
     private String suggestedCareers;
 
+    // Identifies the submission, so a retry of it gets this result back
+    @Column(length = 128)
+    private String idempotencyKey;
+
+    // The LLM analysis given with the result, returned again to a retry
+    @Column(columnDefinition = "TEXT")
+    private String analysis;
+
     // Getters and setters
     public Long getId() { return id; }
     public void setId(Long id) { this.id = id; }
@@ -77,4 +86,20 @@ public class IloTestResult {
     public void setSuggestedCareers(String suggestedCareers) {
         this.suggestedCareers = suggestedCareers;
     }
+
+    public String getIdempotencyKey() {
+        return idempotencyKey;
+    }
+
+    public void setIdempotencyKey(String idempotencyKey) {
+        this.idempotencyKey = idempotencyKey;
+    }
+
+    public String getAnalysis() {
+        return analysis;
+    }
+
+    public void setAnalysis(String analysis) {
+        this.analysis = analysis;
+    }
 }
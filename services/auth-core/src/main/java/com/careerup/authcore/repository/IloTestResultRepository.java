@@ -6,6 +6,7 @@ import org.springframework.data.jpa.repository.Query;
 import org.springframework.stereotype.Repository;
 
 import java.util.List;
+import java.util.Optional;
 import java.util.UUID;
 
 @Repository
@@ -14,6 +15,9 @@ public interface IloTestResultRepository extends JpaRepository<IloTestResult, Lo
     
     // Order by createdAt descending to get the most recent results first
     List<IloTestResult> findByUserIdOrderByCreatedAtDesc(UUID userId);
+
+    // The user's result submitted with the idempotency key, if any
+    Optional<IloTestResult> findByUserIdAndIdempotencyKey(UUID userId, String idempotencyKey);
     
     @Query("SELECT r FROM IloTestResult r LEFT JOIN FETCH r.domainScores WHERE r.id = :id")
     IloTestResult findByIdWithDomainScores(Long id);
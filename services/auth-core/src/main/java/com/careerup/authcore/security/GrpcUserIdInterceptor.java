@@ -0,0 +1,26 @@
+package com.careerup.authcore.security;
+
+import io.grpc.Context;
+import io.grpc.Contexts;
+import io.grpc.Metadata;
+import io.grpc.ServerCall;
+import io.grpc.ServerCallHandler;
+import io.grpc.ServerInterceptor;
+
+/**
+ * Exposes the caller's user ID, propagated by the gateways in the "user-id"
+ * metadata header, to gRPC service methods via {@link #USER_ID}.
+ */
+public class GrpcUserIdInterceptor implements ServerInterceptor {
+    public static final Context.Key<String> USER_ID = Context.key("user-id");
+
+    private static final Metadata.Key<String> USER_ID_HEADER =
+            Metadata.Key.of("user-id", Metadata.ASCII_STRING_MARSHALLER);
+
+    @Override
+    public <ReqT, RespT> ServerCall.Listener<ReqT> interceptCall(ServerCall<ReqT, RespT> call,
+            Metadata headers, ServerCallHandler<ReqT, RespT> next) {
+        Context context = Context.current().withValue(USER_ID, headers.get(USER_ID_HEADER));
+        return Contexts.interceptCall(context, call, headers, next);
+    }
+}
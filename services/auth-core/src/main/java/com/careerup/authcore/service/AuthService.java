@@ -25,6 +25,7 @@ import org.springframework.security.crypto.password.PasswordEncoder;
 import org.springframework.stereotype.Service;
 import org.springframework.transaction.annotation.Transactional;
 
+import java.util.ArrayList;
 import java.util.List;
 
 @Service
@@ -199,10 +200,13 @@ public class AuthService {
         String token = request.getToken();
         // find user to be update by token
         User _user = validateToken(token);
-        String firstName = request.getFirstName();
-        String lastName = request.getLastName();
-        String hometown = request.getHometown();
-        List<String> interests = request.getInterestsList();
+        // Unset fields stay null so updateUser leaves them unchanged
+        String firstName = request.hasFirstName() ? request.getFirstName() : null;
+        String lastName = request.hasLastName() ? request.getLastName() : null;
+        String hometown = request.hasHometown() ? request.getHometown() : null;
+        List<String> interests = request.getReplaceInterests() || request.getInterestsCount() > 0
+            ? new ArrayList<>(request.getInterestsList())
+            : null;
 
         User user = updateUser(_user.getEmail(), firstName, lastName, hometown, interests);
 
@@ -3,21 +3,27 @@ package com.careerup.authcore.service;
 import com.careerup.authcore.model.*;
 import com.careerup.authcore.repository.IloDomainScoreRepository;
 import com.careerup.authcore.repository.IloTestResultRepository;
+import com.careerup.authcore.security.GrpcUserIdInterceptor;
 import com.careerup.proto.v1.*;
 import io.grpc.stub.StreamObserver;
 import lombok.RequiredArgsConstructor;
+import org.springframework.dao.DataIntegrityViolationException;
 import org.springframework.stereotype.Service;
 import org.springframework.transaction.annotation.Transactional;
 
 import java.util.HashMap;
 import java.util.List;
 import java.util.Map;
+import java.util.Optional;
 import java.util.UUID;
 import java.util.stream.Collectors;
 
 @Service
 @RequiredArgsConstructor
 public class IloGrpcService extends IloServiceGrpc.IloServiceImplBase {
+    // Longest idempotency key a client may send, as stored
+    static final int MAX_IDEMPOTENCY_KEY_LENGTH = 128;
+
     private final IloTestResultService iloTestResultService;
     private final IloQuestionService iloQuestionService;
     private final IloDomainService iloDomainService;
@@ -27,11 +33,37 @@ public class IloGrpcService extends IloServiceGrpc.IloServiceImplBase {
     @Override
     public void submitIloTestResult(SubmitIloTestResultRequest request,
             StreamObserver<SubmitIloTestResultResponse> responseObserver) {
-        // Use the new method with structured answers
-        com.careerup.authcore.model.IloTestResult saved = iloTestResultService.saveResultWithAnswers(
-                UUID.fromString(request.getUserId()),
-                request.getRawResultData(),
-                request.getAnswersList());
+        if (request.getIdempotencyKey().length() > MAX_IDEMPOTENCY_KEY_LENGTH) {
+            responseObserver.onError(
+                    io.grpc.Status.INVALID_ARGUMENT
+                            .withDescription("Idempotency key is longer than " + MAX_IDEMPOTENCY_KEY_LENGTH + " characters")
+                            .asRuntimeException());
+            return;
+        }
+
+        // A retried submission gets the result saved the first time, so it
+        // is neither stored nor analysed twice
+        UUID userId = UUID.fromString(request.getUserId());
+        String key = iloTestResultService.submissionKey(userId, request.getIdempotencyKey(), request.getAnswersList());
+        Optional<com.careerup.authcore.model.IloTestResult> existing = iloTestResultService.findSubmission(userId, key);
+        boolean duplicate = existing.isPresent();
+        com.careerup.authcore.model.IloTestResult saved;
+        if (duplicate) {
+            saved = existing.get();
+        } else {
+            try {
+                // Use the new method with structured answers
+                saved = iloTestResultService.saveResultWithAnswers(
+                        userId,
+                        request.getRawResultData(),
+                        request.getAnswersList(),
+                        key);
+            } catch (DataIntegrityViolationException e) {
+                // A concurrent retry saved it first
+                saved = iloTestResultService.findSubmission(userId, key).orElseThrow(() -> e);
+                duplicate = true;
+            }
+        }
 
         // Build the response with all fields including domain scores
         com.careerup.proto.v1.IloTestResult.Builder resultBuilder = com.careerup.proto.v1.IloTestResult.newBuilder()
@@ -68,8 +100,14 @@ public class IloGrpcService extends IloServiceGrpc.IloServiceImplBase {
             resultBuilder.addAllSuggestedCareers(List.of(careers));
         }
 
+        // A retry gets the analysis saved for the first submission
+        if (saved.getAnalysis() != null) {
+            resultBuilder.setAnalysis(saved.getAnalysis());
+        }
+
         SubmitIloTestResultResponse response = SubmitIloTestResultResponse.newBuilder()
                 .setResult(resultBuilder.build())
+                .setDuplicate(duplicate)
                 .build();
 
         responseObserver.onNext(response);
@@ -81,6 +119,15 @@ public class IloGrpcService extends IloServiceGrpc.IloServiceImplBase {
     public void getIloTestResult(GetIloTestResultRequest request,
             StreamObserver<GetIloTestResultResponse> responseObserver) {
         try {
+            // Results are private to their owner, so the caller must be known
+            String callerId = GrpcUserIdInterceptor.USER_ID.get();
+            if (callerId == null || callerId.isBlank()) {
+                responseObserver.onError(
+                        io.grpc.Status.UNAUTHENTICATED.withDescription("Missing user-id metadata")
+                                .asRuntimeException());
+                return;
+            }
+
             String resultId = request.getResultId();
             // Fetch result by ID WITH domain scores
             com.careerup.authcore.model.IloTestResult result = iloTestResultRepository.findByIdWithDomainScores(Long.parseLong(resultId));
@@ -92,6 +139,14 @@ public class IloGrpcService extends IloServiceGrpc.IloServiceImplBase {
                 return;
             }
 
+            if (!result.getUserId().toString().equals(callerId)) {
+                responseObserver.onError(
+                        io.grpc.Status.PERMISSION_DENIED
+                                .withDescription("Test result " + resultId + " does not belong to the caller")
+                                .asRuntimeException());
+                return;
+            }
+
             // Explicitly fetch domain scores
             List<com.careerup.authcore.model.IloDomainScore> domainScores = result.getDomainScores();
             System.out.println("Found " + (domainScores != null ? domainScores.size() : 0) +
@@ -114,6 +169,42 @@ public class IloGrpcService extends IloServiceGrpc.IloServiceImplBase {
         }
     }
 
+    @Override
+    @Transactional
+    public void saveIloTestResultAnalysis(SaveIloTestResultAnalysisRequest request,
+            StreamObserver<SaveIloTestResultAnalysisResponse> responseObserver) {
+        long resultId;
+        try {
+            resultId = Long.parseLong(request.getResultId());
+        } catch (NumberFormatException e) {
+            responseObserver.onError(
+                    io.grpc.Status.INVALID_ARGUMENT.withDescription("Invalid result ID: " + request.getResultId())
+                            .asRuntimeException());
+            return;
+        }
+
+        Optional<com.careerup.authcore.model.IloTestResult> result = iloTestResultRepository.findById(resultId);
+        if (result.isEmpty()) {
+            responseObserver.onError(
+                    io.grpc.Status.NOT_FOUND.withDescription("Test result not found for result ID: " + resultId)
+                            .asRuntimeException());
+            return;
+        }
+        if (!result.get().getUserId().toString().equals(request.getUserId())) {
+            responseObserver.onError(
+                    io.grpc.Status.PERMISSION_DENIED
+                            .withDescription("Test result " + resultId + " does not belong to the user")
+                            .asRuntimeException());
+            return;
+        }
+
+        result.get().setAnalysis(request.getAnalysis());
+        iloTestResultRepository.save(result.get());
+
+        responseObserver.onNext(SaveIloTestResultAnalysisResponse.getDefaultInstance());
+        responseObserver.onCompleted();
+    }
+
     @Override
     @Transactional(readOnly = true)
     public void getIloTestResults(GetIloTestResultsRequest request,
@@ -298,9 +389,20 @@ public class IloGrpcService extends IloServiceGrpc.IloServiceImplBase {
         java.util.List<String> domainCodes = request.getDomainCodesList();
         int limit = request.getLimit() > 0 ? request.getLimit() : 5; // Default to 5 if not specified
 
-        // Convert domain codes to domain scores (using dummy scores for search only)
+        // Percent per requested domain: the caller's score if given, else the max
+        Map<String, Float> percents = new HashMap<>();
+        for (String code : domainCodes) {
+            percents.put(code, 100.0f);
+        }
+        for (com.careerup.proto.v1.IloDomainScore score : request.getScoresList()) {
+            if (percents.containsKey(score.getDomainCode())) {
+                percents.put(score.getDomainCode(), score.getPercent());
+            }
+        }
+
+        // Convert domain codes to domain scores for the search
         java.util.List<com.careerup.authcore.model.IloDomain> allDomains = iloDomainService.getAllDomains();
-        java.util.List<com.careerup.authcore.model.IloDomainScore> dummyScores = domainCodes.stream()
+        java.util.List<com.careerup.authcore.model.IloDomainScore> searchScores = domainCodes.stream()
                 .map(code -> {
                     com.careerup.authcore.model.IloDomain domain = allDomains.stream()
                             .filter(d -> d.getCode().equals(code))
@@ -311,33 +413,86 @@ public class IloGrpcService extends IloServiceGrpc.IloServiceImplBase {
                     }
                     com.careerup.authcore.model.IloDomainScore score = new com.careerup.authcore.model.IloDomainScore();
                     score.setDomain(domain);
-                    score.setPercentScore(100.0f); // Use max score for search
+                    score.setPercentScore(percents.get(code));
                     return score;
                 })
                 .filter(score -> score != null)
                 .collect(java.util.stream.Collectors.toList());
 
-        // Get career suggestions
+        // Get career suggestions; the limit applies to fields once grouped,
+        // so every career map entry is fetched
         java.util.List<com.careerup.authcore.model.IloCareerMap> careers = iloDomainService
-                .getCareerSuggestions(dummyScores, limit);
+                .getCareerSuggestions(searchScores, Integer.MAX_VALUE);
+        Map<String, java.util.List<com.careerup.authcore.model.IloCareerMap>> byField = groupByCareerField(careers, limit);
 
         // Build response
         com.careerup.proto.v1.GetIloCareerSuggestionsResponse.Builder respBuilder = com.careerup.proto.v1.GetIloCareerSuggestionsResponse
                 .newBuilder();
-        for (com.careerup.authcore.model.IloCareerMap career : careers) {
-            com.careerup.proto.v1.IloCareerSuggestion suggestion = com.careerup.proto.v1.IloCareerSuggestion
-                    .newBuilder()
-                    .setDomainCode(career.getDomain().getCode())
-                    .setCareerField(career.getCareerField())
-                    .build();
-
-            respBuilder.addSuggestions(suggestion);
+        for (Map.Entry<String, java.util.List<com.careerup.authcore.model.IloCareerMap>> entry : byField.entrySet()) {
+            respBuilder.addSuggestions(buildCareerSuggestion(entry.getKey(), entry.getValue(), percents));
         }
 
         responseObserver.onNext(respBuilder.build());
         responseObserver.onCompleted();
     }
 
+    /**
+     * Group career map entries by field, in order of each field's first entry,
+     * keeping the first limit fields. A field mapped to several domains becomes
+     * one suggestion drawing on all of them.
+     */
+    static Map<String, java.util.List<com.careerup.authcore.model.IloCareerMap>> groupByCareerField(
+            java.util.List<com.careerup.authcore.model.IloCareerMap> careers, int limit) {
+        Map<String, java.util.List<com.careerup.authcore.model.IloCareerMap>> byField = new java.util.LinkedHashMap<>();
+        for (com.careerup.authcore.model.IloCareerMap career : careers) {
+            java.util.List<com.careerup.authcore.model.IloCareerMap> entries = byField.get(career.getCareerField());
+            if (entries == null) {
+                if (byField.size() >= limit) {
+                    continue;
+                }
+                entries = new java.util.ArrayList<>();
+                byField.put(career.getCareerField(), entries);
+            }
+            entries.add(career);
+        }
+        return byField;
+    }
+
+    /**
+     * Build a career suggestion from the career map entries for one field. The
+     * match percent is the average score of the domains the field draws on.
+     */
+    static com.careerup.proto.v1.IloCareerSuggestion buildCareerSuggestion(String careerField,
+            java.util.List<com.careerup.authcore.model.IloCareerMap> entries, Map<String, Float> percents) {
+        java.util.List<com.careerup.authcore.model.IloDomain> domains = new java.util.ArrayList<>(entries.stream()
+                .collect(Collectors.toMap(e -> e.getDomain().getCode(),
+                        com.careerup.authcore.model.IloCareerMap::getDomain,
+                        (first, second) -> first, java.util.LinkedHashMap::new))
+                .values());
+        double match = domains.stream()
+                .mapToDouble(d -> percents.getOrDefault(d.getCode(), 0.0f))
+                .average()
+                .orElse(0.0);
+
+        String rationale = entries.stream()
+                .map(com.careerup.authcore.model.IloCareerMap::getDescription)
+                .filter(d -> d != null && !d.isBlank())
+                .findFirst()
+                .orElseGet(() -> "Draws on your strengths in " + domains.stream()
+                        .map(com.careerup.authcore.model.IloDomain::getName)
+                        .collect(Collectors.joining(", ")) + ".");
+
+        return com.careerup.proto.v1.IloCareerSuggestion.newBuilder()
+                .setDomainCode(domains.get(0).getCode())
+                .setCareerField(careerField)
+                .setMatchPercent((float) match)
+                .setRationale(rationale)
+                .addAllRequiredDomains(domains.stream()
+                        .map(com.careerup.authcore.model.IloDomain::getCode)
+                        .collect(Collectors.toList()))
+                .build();
+    }
+
     /**
      * Helper method to build a protobuf IloTestResult from a domain entity
      */
@@ -383,6 +538,10 @@ public class IloGrpcService extends IloServiceGrpc.IloServiceImplBase {
             resultBuilder.addAllSuggestedCareers(java.util.List.of(careers));
         }
 
+        if (r.getAnalysis() != null) {
+            resultBuilder.setAnalysis(r.getAnalysis());
+        }
+
         return resultBuilder;
     }
 }
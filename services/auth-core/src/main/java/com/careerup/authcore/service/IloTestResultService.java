@@ -3,9 +3,14 @@ package com.careerup.authcore.service;
 import com.careerup.authcore.model.*;
 import com.careerup.authcore.repository.*;
 import lombok.RequiredArgsConstructor;
+import org.springframework.beans.factory.annotation.Value;
 import org.springframework.stereotype.Service;
 import org.springframework.transaction.annotation.Transactional;
 
+import java.nio.charset.StandardCharsets;
+import java.security.MessageDigest;
+import java.security.NoSuchAlgorithmException;
+import java.time.Instant;
 import java.util.*;
 import java.util.stream.Collectors;
 
@@ -17,6 +22,11 @@ public class IloTestResultService {
     private final IloAnswerRepository iloAnswerRepository;
     private final IloDomainService iloDomainService;
 
+    // Submissions of the same answers without an idempotency key within
+    // this window of each other count as one
+    @Value("${ilo.submission.dedup-window.seconds:600}")
+    private long dedupWindowSeconds = 600;
+
     /**
      * Legacy method for backward compatibility
      */
@@ -72,11 +82,59 @@ public class IloTestResultService {
         return Optional.ofNullable(result);
     }
     
+    /**
+     * Get the user's result submitted with an idempotency key, if any
+     */
+    @Transactional(readOnly = true)
+    public Optional<IloTestResult> findSubmission(UUID userId, String idempotencyKey) {
+        return iloTestResultRepository.findByUserIdAndIdempotencyKey(userId, idempotencyKey)
+                .flatMap(result -> getResultById(result.getId()));
+    }
+
+    /**
+     * The idempotency key of a submission: the client's, or else one derived
+     * from the user, the answers and the current dedup window
+     */
+    public String submissionKey(UUID userId, String clientKey, List<com.careerup.proto.v1.IloAnswer> protoAnswers) {
+        if (clientKey != null && !clientKey.isBlank()) {
+            return clientKey;
+        }
+        return deriveIdempotencyKey(userId, protoAnswers, Instant.now().getEpochSecond() / dedupWindowSeconds);
+    }
+
+    /**
+     * Hash the user, the answers in question order and the time bucket into
+     * an idempotency key. The same answers in another order give the same key.
+     */
+    static String deriveIdempotencyKey(UUID userId, List<com.careerup.proto.v1.IloAnswer> protoAnswers, long bucket) {
+        String answers = protoAnswers.stream()
+                .map(a -> a.getQuestionId() + "=" + a.getSelectedOption())
+                .sorted()
+                .collect(Collectors.joining(","));
+        try {
+            MessageDigest digest = MessageDigest.getInstance("SHA-256");
+            byte[] hash = digest.digest((userId + "|" + answers + "|" + bucket).getBytes(StandardCharsets.UTF_8));
+            return "derived:" + HexFormat.of().formatHex(hash);
+        } catch (NoSuchAlgorithmException e) {
+            throw new IllegalStateException("SHA-256 is not available", e);
+        }
+    }
+
     /**
      * Save a test result with structured answers and calculate scores
      */
     @Transactional
     public IloTestResult saveResultWithAnswers(UUID userId, String resultData, List<com.careerup.proto.v1.IloAnswer> protoAnswers) {
+        return saveResultWithAnswers(userId, resultData, protoAnswers, null);
+    }
+
+    /**
+     * Save a test result with structured answers under an idempotency key,
+     * which must not have been used by the user before
+     */
+    @Transactional
+    public IloTestResult saveResultWithAnswers(UUID userId, String resultData, List<com.careerup.proto.v1.IloAnswer> protoAnswers,
+            String idempotencyKey) {
         // Invalidate cache for this user before saving new result
         iloDomainService.invalidateUserResultCache(userId.toString());
         
@@ -85,6 +143,7 @@ public class IloTestResultService {
         result.setUserId(userId);
         result.setResultData(resultData); // Keep raw data for backward compatibility
         result.setCreatedAt(java.time.LocalDateTime.now());
+        result.setIdempotencyKey(idempotencyKey);
         
         // Save the result first to get an ID
         result = iloTestResultRepository.save(result);
@@ -0,0 +1,7 @@
+-- Identifies a result's submission, so a retried submission returns the
+-- saved result instead of creating a duplicate
+ALTER TABLE ilo_test_results
+ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(128);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ilo_test_result_idempotency_unique
+    ON ilo_test_results (user_id, idempotency_key);
@@ -0,0 +1,4 @@
+-- The LLM analysis given with a result, returned again when its submission
+-- is retried
+ALTER TABLE ilo_test_results
+ADD COLUMN IF NOT EXISTS analysis TEXT;
@@ -0,0 +1,302 @@
+package com.careerup.authcore.service;
+
+import com.careerup.authcore.model.IloCareerMap;
+import com.careerup.authcore.model.IloDomain;
+import com.careerup.authcore.model.IloTestResult;
+import com.careerup.authcore.repository.IloDomainScoreRepository;
+import com.careerup.authcore.repository.IloTestResultRepository;
+import com.careerup.authcore.security.GrpcUserIdInterceptor;
+import com.careerup.proto.v1.GetIloTestResultRequest;
+import com.careerup.proto.v1.GetIloTestResultResponse;
+import com.careerup.proto.v1.IloAnswer;
+import com.careerup.proto.v1.IloCareerSuggestion;
+import com.careerup.proto.v1.SaveIloTestResultAnalysisRequest;
+import com.careerup.proto.v1.SaveIloTestResultAnalysisResponse;
+import com.careerup.proto.v1.SubmitIloTestResultRequest;
+import com.careerup.proto.v1.SubmitIloTestResultResponse;
+import io.grpc.Context;
+import io.grpc.Status;
+import io.grpc.StatusRuntimeException;
+import io.grpc.stub.StreamObserver;
+import org.junit.jupiter.api.BeforeEach;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.api.extension.ExtendWith;
+import org.mockito.ArgumentCaptor;
+import org.mockito.InjectMocks;
+import org.mockito.Mock;
+import org.mockito.junit.jupiter.MockitoExtension;
+import org.springframework.dao.DataIntegrityViolationException;
+
+import java.util.List;
+import java.util.Map;
+import java.util.Optional;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+import static org.mockito.ArgumentMatchers.any;
+import static org.mockito.ArgumentMatchers.eq;
+import static org.mockito.Mockito.mock;
+import static org.mockito.Mockito.never;
+import static org.mockito.Mockito.verify;
+import static org.mockito.Mockito.when;
+
+@ExtendWith(MockitoExtension.class)
+class IloGrpcServiceTest {
+
+    private static final UUID OWNER_ID = UUID.randomUUID();
+
+    @Mock
+    private IloTestResultService iloTestResultService;
+
+    @Mock
+    private IloQuestionService iloQuestionService;
+
+    @Mock
+    private IloDomainService iloDomainService;
+
+    @Mock
+    private IloTestResultRepository iloTestResultRepository;
+
+    @Mock
+    private IloDomainScoreRepository iloDomainScoreRepository;
+
+    @InjectMocks
+    private IloGrpcService iloGrpcService;
+
+    private StreamObserver<GetIloTestResultResponse> responseObserver;
+
+    @BeforeEach
+    @SuppressWarnings("unchecked")
+    void setUp() {
+        responseObserver = mock(StreamObserver.class);
+    }
+
+    private void stubResult() {
+        IloTestResult result = new IloTestResult();
+        result.setId(42L);
+        result.setUserId(OWNER_ID);
+        result.setResultData("{}");
+        when(iloTestResultRepository.findByIdWithDomainScores(42L)).thenReturn(result);
+    }
+
+    private void getResultAs(String callerId) {
+        GetIloTestResultRequest request = GetIloTestResultRequest.newBuilder().setResultId("42").build();
+        Context.current()
+                .withValue(GrpcUserIdInterceptor.USER_ID, callerId)
+                .run(() -> iloGrpcService.getIloTestResult(request, responseObserver));
+    }
+
+    private Status.Code errorCode() {
+        ArgumentCaptor<Throwable> error = ArgumentCaptor.forClass(Throwable.class);
+        verify(responseObserver).onError(error.capture());
+        verify(responseObserver, never()).onNext(any());
+        return ((StatusRuntimeException) error.getValue()).getStatus().getCode();
+    }
+
+    @Test
+    void ownerGetsResult() {
+        stubResult();
+
+        getResultAs(OWNER_ID.toString());
+
+        ArgumentCaptor<GetIloTestResultResponse> response = ArgumentCaptor.forClass(GetIloTestResultResponse.class);
+        verify(responseObserver).onNext(response.capture());
+        verify(responseObserver).onCompleted();
+        assertEquals("42", response.getValue().getResult().getId());
+        assertEquals(OWNER_ID.toString(), response.getValue().getResult().getUserId());
+    }
+
+    @Test
+    void nonOwnerIsDenied() {
+        stubResult();
+
+        getResultAs(UUID.randomUUID().toString());
+
+        assertEquals(Status.Code.PERMISSION_DENIED, errorCode());
+    }
+
+    @Test
+    void missingIdentityIsUnauthenticated() {
+        getResultAs(null);
+
+        assertEquals(Status.Code.UNAUTHENTICATED, errorCode());
+        verify(iloTestResultRepository, never()).findByIdWithDomainScores(any());
+    }
+
+    private static IloCareerMap careerMap(String field, String domainCode, String domainName, String description) {
+        IloDomain domain = new IloDomain();
+        domain.setCode(domainCode);
+        domain.setName(domainName);
+        IloCareerMap career = new IloCareerMap();
+        career.setDomain(domain);
+        career.setCareerField(field);
+        career.setDescription(description);
+        return career;
+    }
+
+    @Test
+    void careerFieldsAreLimitedAfterGrouping() {
+        Map<String, List<IloCareerMap>> byField = IloGrpcService.groupByCareerField(List.of(
+                careerMap("Data Science", "LOGIC", "Logic", null),
+                careerMap("Data Science", "LANG", "Language", null),
+                careerMap("Journalism", "LANG", "Language", null),
+                careerMap("Law", "LANG", "Language", null),
+                careerMap("Data Science", "MATH", "Math", null)), 2);
+
+        assertEquals(List.of("Data Science", "Journalism"), List.copyOf(byField.keySet()));
+        assertEquals(3, byField.get("Data Science").size());
+    }
+
+    @Test
+    void careerSuggestionCombinesDomains() {
+        IloCareerSuggestion suggestion = IloGrpcService.buildCareerSuggestion("Data Science", List.of(
+                careerMap("Data Science", "LOGIC", "Logic", null),
+                careerMap("Data Science", "LANG", "Language", "")),
+                Map.of("LOGIC", 90.0f, "LANG", 60.0f));
+
+        assertEquals("LOGIC", suggestion.getDomainCode());
+        assertEquals(List.of("LOGIC", "LANG"), suggestion.getRequiredDomainsList());
+        assertEquals(75.0f, suggestion.getMatchPercent());
+        assertEquals("Draws on your strengths in Logic, Language.", suggestion.getRationale());
+    }
+
+    @Test
+    void careerSuggestionUsesDescriptionAsRationale() {
+        IloCareerSuggestion suggestion = IloGrpcService.buildCareerSuggestion("Journalism", List.of(
+                careerMap("Journalism", "LANG", "Language", "Writing and reporting for the public.")),
+                Map.of("LANG", 80.0f));
+
+        assertEquals(80.0f, suggestion.getMatchPercent());
+        assertEquals("Writing and reporting for the public.", suggestion.getRationale());
+    }
+
+    private static final List<IloAnswer> ANSWERS = List.of(
+            IloAnswer.newBuilder().setQuestionId("1").setSelectedOption(2).build());
+
+    private static IloTestResult savedResult(long id) {
+        IloTestResult result = new IloTestResult();
+        result.setId(id);
+        result.setUserId(OWNER_ID);
+        result.setResultData("{}");
+        return result;
+    }
+
+    @SuppressWarnings("unchecked")
+    private SubmitIloTestResultResponse submit(String idempotencyKey) {
+        StreamObserver<SubmitIloTestResultResponse> observer = mock(StreamObserver.class);
+        iloGrpcService.submitIloTestResult(SubmitIloTestResultRequest.newBuilder()
+                .setUserId(OWNER_ID.toString())
+                .addAllAnswers(ANSWERS)
+                .setIdempotencyKey(idempotencyKey)
+                .build(), observer);
+        ArgumentCaptor<SubmitIloTestResultResponse> response = ArgumentCaptor.forClass(SubmitIloTestResultResponse.class);
+        verify(observer).onNext(response.capture());
+        verify(observer).onCompleted();
+        return response.getValue();
+    }
+
+    @Test
+    void firstSubmissionIsSaved() {
+        when(iloTestResultService.submissionKey(OWNER_ID, "key-1", ANSWERS)).thenReturn("key-1");
+        when(iloTestResultService.findSubmission(OWNER_ID, "key-1")).thenReturn(Optional.empty());
+        when(iloTestResultService.saveResultWithAnswers(eq(OWNER_ID), any(), eq(ANSWERS), eq("key-1")))
+                .thenReturn(savedResult(42L));
+
+        SubmitIloTestResultResponse response = submit("key-1");
+
+        assertEquals("42", response.getResult().getId());
+        assertFalse(response.getDuplicate());
+    }
+
+    @Test
+    void repeatedSubmissionReturnsOriginalResult() {
+        when(iloTestResultService.submissionKey(OWNER_ID, "key-1", ANSWERS)).thenReturn("key-1");
+        when(iloTestResultService.findSubmission(OWNER_ID, "key-1")).thenReturn(Optional.of(savedResult(42L)));
+
+        SubmitIloTestResultResponse response = submit("key-1");
+
+        assertEquals("42", response.getResult().getId());
+        assertTrue(response.getDuplicate());
+        verify(iloTestResultService, never()).saveResultWithAnswers(any(), any(), any(), any());
+    }
+
+    @Test
+    void repeatedSubmissionReturnsSavedAnalysis() {
+        IloTestResult saved = savedResult(42L);
+        saved.setAnalysis("You enjoy building things.");
+        when(iloTestResultService.submissionKey(OWNER_ID, "key-1", ANSWERS)).thenReturn("key-1");
+        when(iloTestResultService.findSubmission(OWNER_ID, "key-1")).thenReturn(Optional.of(saved));
+
+        SubmitIloTestResultResponse response = submit("key-1");
+
+        assertTrue(response.getDuplicate());
+        assertEquals("You enjoy building things.", response.getResult().getAnalysis());
+    }
+
+    @SuppressWarnings("unchecked")
+    private StreamObserver<SaveIloTestResultAnalysisResponse> saveAnalysis(UUID userId, String analysis) {
+        StreamObserver<SaveIloTestResultAnalysisResponse> observer = mock(StreamObserver.class);
+        iloGrpcService.saveIloTestResultAnalysis(SaveIloTestResultAnalysisRequest.newBuilder()
+                .setResultId("42")
+                .setUserId(userId.toString())
+                .setAnalysis(analysis)
+                .build(), observer);
+        return observer;
+    }
+
+    @Test
+    void ownerSavesAnalysis() {
+        IloTestResult saved = savedResult(42L);
+        when(iloTestResultRepository.findById(42L)).thenReturn(Optional.of(saved));
+
+        StreamObserver<SaveIloTestResultAnalysisResponse> observer = saveAnalysis(OWNER_ID, "You enjoy building things.");
+
+        verify(observer).onCompleted();
+        assertEquals("You enjoy building things.", saved.getAnalysis());
+        verify(iloTestResultRepository).save(saved);
+    }
+
+    @Test
+    void analysisOfAnotherUsersResultIsDenied() {
+        when(iloTestResultRepository.findById(42L)).thenReturn(Optional.of(savedResult(42L)));
+
+        StreamObserver<SaveIloTestResultAnalysisResponse> observer = saveAnalysis(UUID.randomUUID(), "hijack");
+
+        ArgumentCaptor<Throwable> error = ArgumentCaptor.forClass(Throwable.class);
+        verify(observer).onError(error.capture());
+        assertEquals(Status.Code.PERMISSION_DENIED, ((StatusRuntimeException) error.getValue()).getStatus().getCode());
+        verify(iloTestResultRepository, never()).save(any());
+    }
+
+    @Test
+    void concurrentRepeatReturnsTheResultSavedFirst() {
+        when(iloTestResultService.submissionKey(OWNER_ID, "", ANSWERS)).thenReturn("derived:abc");
+        when(iloTestResultService.findSubmission(OWNER_ID, "derived:abc"))
+                .thenReturn(Optional.empty())
+                .thenReturn(Optional.of(savedResult(42L)));
+        when(iloTestResultService.saveResultWithAnswers(eq(OWNER_ID), any(), eq(ANSWERS), eq("derived:abc")))
+                .thenThrow(new DataIntegrityViolationException("duplicate key"));
+
+        SubmitIloTestResultResponse response = submit("");
+
+        assertEquals("42", response.getResult().getId());
+        assertTrue(response.getDuplicate());
+    }
+
+    @Test
+    @SuppressWarnings("unchecked")
+    void overlongIdempotencyKeyIsRejected() {
+        StreamObserver<SubmitIloTestResultResponse> observer = mock(StreamObserver.class);
+        iloGrpcService.submitIloTestResult(SubmitIloTestResultRequest.newBuilder()
+                .setUserId(OWNER_ID.toString())
+                .setIdempotencyKey("k".repeat(IloGrpcService.MAX_IDEMPOTENCY_KEY_LENGTH + 1))
+                .build(), observer);
+
+        ArgumentCaptor<Throwable> error = ArgumentCaptor.forClass(Throwable.class);
+        verify(observer).onError(error.capture());
+        assertEquals(Status.Code.INVALID_ARGUMENT, ((StatusRuntimeException) error.getValue()).getStatus().getCode());
+        verify(iloTestResultService, never()).saveResultWithAnswers(any(), any(), any(), any());
+    }
+}
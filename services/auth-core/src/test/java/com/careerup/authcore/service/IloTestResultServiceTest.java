@@ -0,0 +1,39 @@
+package com.careerup.authcore.service;
+
+import com.careerup.proto.v1.IloAnswer;
+import org.junit.jupiter.api.Test;
+
+import java.util.List;
+import java.util.UUID;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertNotEquals;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+class IloTestResultServiceTest {
+
+    private static final UUID USER_ID = UUID.randomUUID();
+
+    private static IloAnswer answer(String questionId, int option) {
+        return IloAnswer.newBuilder().setQuestionId(questionId).setSelectedOption(option).build();
+    }
+
+    private static final List<IloAnswer> ANSWERS = List.of(answer("1", 2), answer("2", 4));
+
+    @Test
+    void derivedKeyIgnoresAnswerOrder() {
+        String key = IloTestResultService.deriveIdempotencyKey(USER_ID, ANSWERS, 7);
+
+        assertTrue(key.startsWith("derived:"));
+        assertEquals(key, IloTestResultService.deriveIdempotencyKey(USER_ID, List.of(answer("2", 4), answer("1", 2)), 7));
+    }
+
+    @Test
+    void derivedKeyDependsOnUserAnswersAndTime() {
+        String key = IloTestResultService.deriveIdempotencyKey(USER_ID, ANSWERS, 7);
+
+        assertNotEquals(key, IloTestResultService.deriveIdempotencyKey(UUID.randomUUID(), ANSWERS, 7));
+        assertNotEquals(key, IloTestResultService.deriveIdempotencyKey(USER_ID, List.of(answer("1", 2), answer("2", 3)), 7));
+        assertNotEquals(key, IloTestResultService.deriveIdempotencyKey(USER_ID, ANSWERS, 8));
+    }
+}
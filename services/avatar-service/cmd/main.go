@@ -1,15 +1,76 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/httpclient"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/client"
 	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/handler"
 	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/middleware"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/repository"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/service"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// shutdownTimeout bounds how long requests in flight are waited for on
+// shutdown.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
+	// Cancelled on SIGINT or SIGTERM, stopping the background workers
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, warnings, err := loadSettings(os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+	for _, warning := range warnings {
+		log.Printf("Warning: %s", warning)
+	}
+
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.mongoURI))
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer mongoClient.Disconnect(context.Background())
+	avatars := repository.NewAvatarRepository(mongoClient.Database(cfg.mongoDatabase))
+
+	// Deleted avatars past their restore window are removed for good
+	purgerDone := make(chan struct{})
+	go func() {
+		defer close(purgerDone)
+		avatars.RunPurger(ctx, cfg.purgeInterval, cfg.restoreWindow)
+	}()
+
+	var vroidClient client.VRoidClientInterface
+	if cfg.vroidAPIKey != "" {
+		// VROID_TIMEOUT_SECONDS bounds each attempt and VROID_MAX_RETRIES
+		// caps retries of idempotent calls; unset uses the client defaults
+		vroidClient = client.NewVRoidClient(cfg.vroidAPIKey, httpclient.Config{
+			Timeout:    cfg.vroidTimeout,
+			MaxRetries: cfg.vroidMaxRetries,
+		})
+	} else {
+		vroidClient = client.NewMockVRoidClient()
+	}
+
+	// Generate avatars in the background so requests are not held up by VRoid
+	generator := service.NewGenerationQueue(avatars, vroidClient, cfg.queueSize)
+	if err := generator.Recover(ctx); err != nil {
+		log.Printf("Failed to mark unfinished avatars as failed: %v", err)
+	}
+	generator.Start(ctx, cfg.workers)
+
 	// Initialize router
 	r := gin.Default()
 
@@ -18,16 +79,32 @@ func main() {
 	r.Use(middleware.RateLimit())
 
 	// Create handler
-	h := handler.NewHandler()
+	h := handler.NewHandler(generator, avatars, cfg.restoreWindow)
 
 	// Routes
 	r.POST("/v1/avatar/generate", h.GenerateAvatar)
 	r.GET("/v1/avatar/:id", h.GetAvatar)
 	r.PUT("/v1/avatar/:id", h.UpdateAvatar)
 	r.DELETE("/v1/avatar/:id", h.DeleteAvatar)
+	r.POST("/v1/avatar/:id/restore", h.RestoreAvatar)
 
 	// Start server
-	if err := http.ListenAndServe(":8082", r); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{Addr: ":8082", Handler: r}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Graceful shutdown
+	<-ctx.Done()
+	log.Println("Shutting down avatar service...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown: %v", err)
 	}
+	generator.Wait()
+	<-purgerDone
+	log.Println("Avatar service stopped.")
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/repository"
+)
+
+// Generation queue defaults, overridable with AVATAR_QUEUE_SIZE and
+// AVATAR_WORKERS
+const (
+	defaultQueueSize = 100
+	defaultWorkers   = 4
+)
+
+const defaultMongoURI = "mongodb://localhost:27017"
+
+// settings are the service's environment variables, read and checked
+// together on startup.
+type settings struct {
+	mongoURI      string
+	mongoDatabase string
+	// vroidAPIKey is empty when avatars are generated by the mock client
+	vroidAPIKey string
+	// vroidTimeout and vroidMaxRetries are zero for the client defaults
+	vroidTimeout    time.Duration
+	vroidMaxRetries int
+	queueSize       int
+	workers         int
+	// restoreWindow is how long deleted avatars can be restored, and
+	// purgeInterval how often those past it are purged
+	restoreWindow time.Duration
+	purgeInterval time.Duration
+}
+
+// loadSettings reads the settings through getenv. The error lists every
+// invalid variable at once; warnings name the optional ones that are
+// missing, and what the service does without them.
+func loadSettings(getenv func(string) string) (s settings, warnings []string, err error) {
+	var errs []error
+	positiveInt := func(key string, fallback int) int {
+		raw := getenv(key)
+		if raw == "" {
+			return fallback
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be a positive integer, got %q", key, raw))
+			return fallback
+		}
+		return v
+	}
+
+	s.mongoURI = getenv("MONGO_URI")
+	switch {
+	case s.mongoURI == "":
+		s.mongoURI = defaultMongoURI
+		warnings = append(warnings, "MONGO_URI is not set: using "+defaultMongoURI)
+	case !strings.HasPrefix(s.mongoURI, "mongodb://") && !strings.HasPrefix(s.mongoURI, "mongodb+srv://"):
+		errs = append(errs, errors.New("MONGO_URI must start with mongodb:// or mongodb+srv://"))
+	}
+	s.mongoDatabase = getenv("MONGO_DATABASE")
+	if s.mongoDatabase == "" {
+		s.mongoDatabase = "careerup"
+	}
+
+	s.vroidAPIKey = getenv("VROID_API_KEY")
+	if s.vroidAPIKey == "" {
+		warnings = append(warnings, "VROID_API_KEY is not set: avatars are generated by the mock VRoid client")
+	}
+	s.vroidTimeout = time.Duration(positiveInt("VROID_TIMEOUT_SECONDS", 0)) * time.Second
+	s.vroidMaxRetries = positiveInt("VROID_MAX_RETRIES", 0)
+	s.queueSize = positiveInt("AVATAR_QUEUE_SIZE", defaultQueueSize)
+	s.workers = positiveInt("AVATAR_WORKERS", defaultWorkers)
+	s.restoreWindow = time.Duration(positiveInt("AVATAR_RESTORE_WINDOW_HOURS", int(repository.DefaultRestoreWindow/time.Hour))) * time.Hour
+	s.purgeInterval = time.Duration(positiveInt("AVATAR_PURGE_INTERVAL_MINUTES", int(repository.DefaultPurgeInterval/time.Minute))) * time.Minute
+
+	return s, warnings, errors.Join(errs...)
+}
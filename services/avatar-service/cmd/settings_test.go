@@ -0,0 +1,86 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestLoadSettings_Defaults(t *testing.T) {
+	s, warnings, err := loadSettings(env(nil))
+	if err != nil {
+		t.Fatalf("loadSettings: %v", err)
+	}
+	want := settings{
+		mongoURI:      defaultMongoURI,
+		mongoDatabase: "careerup",
+		queueSize:     defaultQueueSize,
+		workers:       defaultWorkers,
+		restoreWindow: 7 * 24 * time.Hour,
+		purgeInterval: time.Hour,
+	}
+	if s != want {
+		t.Errorf("settings = %+v, want %+v", s, want)
+	}
+	wantWarnings := []string{
+		"MONGO_URI is not set: using mongodb://localhost:27017",
+		"VROID_API_KEY is not set: avatars are generated by the mock VRoid client",
+	}
+	if !reflect.DeepEqual(warnings, wantWarnings) {
+		t.Errorf("warnings = %q, want %q", warnings, wantWarnings)
+	}
+}
+
+func TestLoadSettings_FromEnv(t *testing.T) {
+	s, warnings, err := loadSettings(env(map[string]string{
+		"MONGO_URI":                     "mongodb+srv://cluster.example.net",
+		"MONGO_DATABASE":                "avatars",
+		"VROID_API_KEY":                 "key",
+		"VROID_TIMEOUT_SECONDS":         "15",
+		"VROID_MAX_RETRIES":             "2",
+		"AVATAR_QUEUE_SIZE":             "10",
+		"AVATAR_WORKERS":                "1",
+		"AVATAR_RESTORE_WINDOW_HOURS":   "48",
+		"AVATAR_PURGE_INTERVAL_MINUTES": "10",
+	}))
+	if err != nil {
+		t.Fatalf("loadSettings: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings %q", warnings)
+	}
+	want := settings{
+		mongoURI:        "mongodb+srv://cluster.example.net",
+		mongoDatabase:   "avatars",
+		vroidAPIKey:     "key",
+		vroidTimeout:    15 * time.Second,
+		vroidMaxRetries: 2,
+		queueSize:       10,
+		workers:         1,
+		restoreWindow:   48 * time.Hour,
+		purgeInterval:   10 * time.Minute,
+	}
+	if s != want {
+		t.Errorf("settings = %+v, want %+v", s, want)
+	}
+}
+
+func TestLoadSettings_ReportsEveryInvalidValue(t *testing.T) {
+	_, _, err := loadSettings(env(map[string]string{
+		"MONGO_URI":                   "localhost:27017",
+		"VROID_TIMEOUT_SECONDS":       "soon",
+		"AVATAR_WORKERS":              "0",
+		"AVATAR_RESTORE_WINDOW_HOURS": "-1",
+	}))
+	want := `MONGO_URI must start with mongodb:// or mongodb+srv://
+VROID_TIMEOUT_SECONDS must be a positive integer, got "soon"
+AVATAR_WORKERS must be a positive integer, got "0"
+AVATAR_RESTORE_WINDOW_HOURS must be a positive integer, got "-1"`
+	if err == nil || err.Error() != want {
+		t.Errorf("err = %v, want:\n%s", err, want)
+	}
+}
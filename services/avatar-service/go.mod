@@ -5,6 +5,7 @@ go 1.24.2
 toolchain go1.24.3
 
 require (
+	github.com/careerup-Inc/careerup-monorepo v0.0.0-00010101000000-000000000000
 	github.com/gin-gonic/gin v1.9.1
 	go.mongodb.org/mongo-driver v1.13.1
 	golang.org/x/time v0.5.0
@@ -43,6 +44,12 @@ require (
 	golang.org/x/sync v0.13.0 // indirect
 	golang.org/x/sys v0.32.0 // indirect
 	golang.org/x/text v0.24.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
+	google.golang.org/grpc v1.72.1 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// pkg/ is built from this checkout, so the image builds from the
+// repository root (see Dockerfile)
+replace github.com/careerup-Inc/careerup-monorepo => ../..
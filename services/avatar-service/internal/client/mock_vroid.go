@@ -3,12 +3,15 @@ package client
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
 )
 
 type MockVRoidClient struct {
+	// mu guards avatars; generation workers share one client
+	mu      sync.Mutex
 	avatars map[string]*model.Avatar
 }
 
@@ -19,6 +22,9 @@ func NewMockVRoidClient() *MockVRoidClient {
 }
 
 func (c *MockVRoidClient) GenerateAvatar(ctx context.Context, req *model.AvatarGenerationRequest) (*model.Avatar, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if req.Style == "" {
 		return nil, fmt.Errorf("style is required")
 	}
@@ -44,6 +50,9 @@ func (c *MockVRoidClient) GenerateAvatar(ctx context.Context, req *model.AvatarG
 }
 
 func (c *MockVRoidClient) GetAvatar(ctx context.Context, id string) (*model.Avatar, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	avatar, exists := c.avatars[id]
 	if !exists {
 		return nil, fmt.Errorf("avatar not found")
@@ -52,6 +61,9 @@ func (c *MockVRoidClient) GetAvatar(ctx context.Context, id string) (*model.Avat
 }
 
 func (c *MockVRoidClient) UpdateAvatar(ctx context.Context, id string, req *model.AvatarUpdateRequest) (*model.Avatar, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	avatar, exists := c.avatars[id]
 	if !exists {
 		return nil, fmt.Errorf("avatar not found")
@@ -70,6 +82,9 @@ func (c *MockVRoidClient) UpdateAvatar(ctx context.Context, id string, req *mode
 }
 
 func (c *MockVRoidClient) DeleteAvatar(ctx context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if _, exists := c.avatars[id]; !exists {
 		return fmt.Errorf("avatar not found")
 	}
@@ -5,9 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/httpclient"
 	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
 )
 
@@ -24,16 +26,38 @@ type VRoidClientInterface interface {
 
 type VRoidClient struct {
 	apiKey     string
-	httpClient *http.Client
+	baseURL    string
+	httpClient *httpclient.Client
 }
 
-func NewVRoidClient(apiKey string) *VRoidClient {
+// NewVRoidClient returns a client for the VRoid Hub API. Reads, updates and
+// deletes are retried after transient failures as cfg allows; generation is
+// sent once.
+func NewVRoidClient(apiKey string, cfg httpclient.Config) *VRoidClient {
+	if cfg.Observe == nil {
+		cfg.Observe = logVRoidAttempt
+	}
 	return &VRoidClient{
-		apiKey: apiKey,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		apiKey:     apiKey,
+		baseURL:    vroidAPIBaseURL,
+		httpClient: httpclient.New(cfg),
+	}
+}
+
+// logVRoidAttempt logs VRoid requests that failed or are retried.
+func logVRoidAttempt(a httpclient.Attempt) {
+	if a.Err == nil && a.StatusCode < 500 && a.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	outcome := fmt.Sprint(a.StatusCode)
+	if a.Err != nil {
+		outcome = a.Err.Error()
+	}
+	if a.Retrying {
+		log.Printf("VRoid %s %s attempt %d failed after %v (%s); retrying", a.Method, a.URL, a.Number, a.Duration, outcome)
+		return
 	}
+	log.Printf("VRoid %s %s attempt %d failed after %v (%s)", a.Method, a.URL, a.Number, a.Duration, outcome)
 }
 
 // GenerateAvatar starts the avatar generation process
@@ -50,7 +74,7 @@ func (c *VRoidClient) GenerateAvatar(ctx context.Context, req *model.AvatarGener
 	}
 
 	// Create the HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", vroidAPIBaseURL+"/avatars", bytes.NewReader(jsonBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/avatars", bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -67,7 +91,7 @@ func (c *VRoidClient) GenerateAvatar(ctx context.Context, req *model.AvatarGener
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, newVRoidError(resp)
 	}
 
 	// Parse the response
@@ -102,7 +126,7 @@ func (c *VRoidClient) GenerateAvatar(ctx context.Context, req *model.AvatarGener
 // GetAvatar retrieves an avatar by ID
 func (c *VRoidClient) GetAvatar(ctx context.Context, id string) (*model.Avatar, error) {
 	// Create the HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", vroidAPIBaseURL+"/avatars/"+id, nil)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/avatars/"+id, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -118,7 +142,7 @@ func (c *VRoidClient) GetAvatar(ctx context.Context, id string) (*model.Avatar,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, newVRoidError(resp)
 	}
 
 	// Parse the response
@@ -164,7 +188,7 @@ func (c *VRoidClient) UpdateAvatar(ctx context.Context, id string, req *model.Av
 	}
 
 	// Create the HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", vroidAPIBaseURL+"/avatars/"+id, bytes.NewReader(jsonBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "PUT", c.baseURL+"/avatars/"+id, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -181,7 +205,7 @@ func (c *VRoidClient) UpdateAvatar(ctx context.Context, id string, req *model.Av
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, newVRoidError(resp)
 	}
 
 	// Parse the response
@@ -216,7 +240,7 @@ func (c *VRoidClient) UpdateAvatar(ctx context.Context, id string, req *model.Av
 // DeleteAvatar deletes an avatar
 func (c *VRoidClient) DeleteAvatar(ctx context.Context, id string) error {
 	// Create the HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", vroidAPIBaseURL+"/avatars/"+id, nil)
+	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/avatars/"+id, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -232,7 +256,7 @@ func (c *VRoidClient) DeleteAvatar(ctx context.Context, id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return newVRoidError(resp)
 	}
 
 	return nil
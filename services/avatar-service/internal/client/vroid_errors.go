@@ -0,0 +1,91 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
+)
+
+// Codes of the VRoid validation failures that are passed on to clients as
+// field errors. Other codes only fail the request.
+const (
+	VRoidCodeUnsupportedFeature = "unsupported_feature"
+	VRoidCodeIncompatibleStyle  = "incompatible_style"
+)
+
+// maxVRoidErrorBody bounds how much of an error response is read.
+const maxVRoidErrorBody = 64 << 10
+
+// VRoidError is an unexpected response from VRoid. When VRoid explained the
+// failure, Code, Field and Message describe the first problem it reported
+// and Details lists all of them.
+type VRoidError struct {
+	StatusCode int
+	Code       string
+	// Field is the request field at fault, e.g. "style" or "features.hair";
+	// empty when the problem is not about one field
+	Field   string
+	Message string
+	Details []VRoidErrorDetail
+}
+
+// VRoidErrorDetail is one problem in a VRoid error response.
+type VRoidErrorDetail struct {
+	Code    string `json:"code"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *VRoidError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	}
+	if e.Field != "" {
+		return fmt.Sprintf("vroid rejected %s: %s: %s (status %d)", e.Field, e.Code, e.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("vroid error %s: %s (status %d)", e.Code, e.Message, e.StatusCode)
+}
+
+// FieldErrors returns the validation failures VRoid reported that the
+// client can fix by changing its request, or nil when there are none.
+func (e *VRoidError) FieldErrors() []model.FieldError {
+	var errs []model.FieldError
+	for _, d := range e.Details {
+		switch d.Code {
+		case VRoidCodeUnsupportedFeature, VRoidCodeIncompatibleStyle:
+			errs = append(errs, model.FieldError{Field: d.Field, Code: d.Code, Message: d.Message})
+		}
+	}
+	return errs
+}
+
+// newVRoidError reads the error response resp. VRoid describes failures as
+// {"errors": [{"code", "field", "message"}, ...]}, or a single
+// {"error": {...}}; other bodies leave only the status code.
+func newVRoidError(resp *http.Response) *VRoidError {
+	e := &VRoidError{StatusCode: resp.StatusCode}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxVRoidErrorBody))
+	if err != nil || !strings.HasPrefix(strings.TrimSpace(string(body)), "{") {
+		return e
+	}
+
+	var parsed struct {
+		Errors []VRoidErrorDetail `json:"errors"`
+		Error  *VRoidErrorDetail  `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return e
+	}
+	e.Details = parsed.Errors
+	if len(e.Details) == 0 && parsed.Error != nil {
+		e.Details = []VRoidErrorDetail{*parsed.Error}
+	}
+	if len(e.Details) > 0 {
+		e.Code, e.Field, e.Message = e.Details[0].Code, e.Details[0].Field, e.Details[0].Message
+	}
+	return e
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/careerup-Inc/careerup-monorepo/pkg/httpclient"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
+)
+
+// vroidServer answers every request with status and body
+func vroidServer(t *testing.T, status int, body string) *VRoidClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	c := NewVRoidClient("test-key", httpclient.Config{MaxRetries: -1})
+	c.baseURL = srv.URL
+	return c
+}
+
+var avatarRequest = &model.AvatarGenerationRequest{
+	Style:    "realistic",
+	Features: map[string]string{"hair": "twin_tails", "wings": "angel"},
+}
+
+func TestGenerateAvatar_ValidationErrors(t *testing.T) {
+	c := vroidServer(t, http.StatusUnprocessableEntity, `{"errors":[
+		{"code":"unsupported_feature","field":"features.wings","message":"wings are not supported"},
+		{"code":"incompatible_style","field":"features.hair","message":"twin_tails is not available for the realistic style"}
+	]}`)
+
+	_, err := c.GenerateAvatar(context.Background(), avatarRequest)
+	var vroidErr *VRoidError
+	if !errors.As(err, &vroidErr) {
+		t.Fatalf("error = %v, want a *VRoidError", err)
+	}
+	if vroidErr.StatusCode != http.StatusUnprocessableEntity || vroidErr.Code != VRoidCodeUnsupportedFeature || vroidErr.Field != "features.wings" {
+		t.Errorf("error = %+v", vroidErr)
+	}
+	if want := "vroid rejected features.wings: unsupported_feature: wings are not supported (status 422)"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	want := []model.FieldError{
+		{Field: "features.wings", Code: VRoidCodeUnsupportedFeature, Message: "wings are not supported"},
+		{Field: "features.hair", Code: VRoidCodeIncompatibleStyle, Message: "twin_tails is not available for the realistic style"},
+	}
+	if got := vroidErr.FieldErrors(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldErrors() = %+v, want %+v", got, want)
+	}
+}
+
+func TestVRoidError_Bodies(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		code        string
+		fieldErrors int
+		message     string
+	}{
+		{
+			name:        "single error object",
+			status:      http.StatusBadRequest,
+			body:        `{"error":{"code":"incompatible_style","field":"style","message":"unknown style"}}`,
+			code:        VRoidCodeIncompatibleStyle,
+			fieldErrors: 1,
+			message:     "vroid rejected style: incompatible_style: unknown style (status 400)",
+		},
+		{
+			name:    "error that is not about the request",
+			status:  http.StatusForbidden,
+			body:    `{"error":{"code":"invalid_api_key","message":"API key revoked"}}`,
+			code:    "invalid_api_key",
+			message: "vroid error invalid_api_key: API key revoked (status 403)",
+		},
+		{
+			name:    "unparseable body",
+			status:  http.StatusBadGateway,
+			body:    `<html>Bad Gateway</html>`,
+			message: "unexpected status code: 502",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vroidServer(t, tt.status, tt.body).GetAvatar(context.Background(), "avatar-1")
+			var vroidErr *VRoidError
+			if !errors.As(err, &vroidErr) {
+				t.Fatalf("error = %v, want a *VRoidError", err)
+			}
+			if vroidErr.Code != tt.code || len(vroidErr.FieldErrors()) != tt.fieldErrors || err.Error() != tt.message {
+				t.Errorf("error = %+v (%q), want code %q with %d field errors (%q)", vroidErr, err, tt.code, tt.fieldErrors, tt.message)
+			}
+		})
+	}
+}
+
+func TestDeleteAvatar_Error(t *testing.T) {
+	err := vroidServer(t, http.StatusNotFound, `{"error":{"code":"not_found","message":"no such avatar"}}`).DeleteAvatar(context.Background(), "avatar-1")
+	var vroidErr *VRoidError
+	if !errors.As(err, &vroidErr) || vroidErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("error = %v, want a 404 *VRoidError", err)
+	}
+}
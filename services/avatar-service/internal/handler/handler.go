@@ -1,21 +1,36 @@
 package handler
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"time"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/errs"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/repository"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
-	// TODO: Add service dependencies
+	generator *service.GenerationQueue
+	avatars   service.AvatarStore
+	// restoreWindow is how long a deleted avatar can be restored
+	restoreWindow time.Duration
 }
 
-func NewHandler() *Handler {
-	return &Handler{}
+func NewHandler(generator *service.GenerationQueue, avatars service.AvatarStore, restoreWindow time.Duration) *Handler {
+	return &Handler{
+		generator:     generator,
+		avatars:       avatars,
+		restoreWindow: restoreWindow,
+	}
 }
 
 type GenerateAvatarRequest struct {
-	Style    string   `json:"style" binding:"required"`
+	Style string `json:"style" binding:"required"`
+	// Features are "name" or "name=value" entries; see model.ParseFeatures
 	Features []string `json:"features" binding:"required"`
 }
 
@@ -25,9 +40,29 @@ func (h *Handler) GenerateAvatar(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	features, err := model.ParseFeatures(req.Features)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	avatar, err := h.generator.Enqueue(c.Request.Context(), &model.AvatarGenerationRequest{
+		Style:    req.Style,
+		Features: features,
+	})
+	// e.g. service.ErrQueueFull, answered with 503
+	if kind := errs.KindOf(err); kind != errs.Unknown {
+		c.JSON(errs.HTTPStatus(kind), gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to enqueue avatar generation: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start avatar generation"})
+		return
+	}
 
-	// TODO: Implement avatar generation using VRoid Studio API
-	c.JSON(http.StatusOK, gin.H{"message": "Avatar generation started"})
+	// Generation continues in the background; poll GetAvatar for its status
+	c.JSON(http.StatusAccepted, avatar)
 }
 
 func (h *Handler) GetAvatar(c *gin.Context) {
@@ -37,8 +72,25 @@ func (h *Handler) GetAvatar(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement avatar retrieval
-	c.JSON(http.StatusOK, gin.H{"id": id})
+	avatar, err := h.avatars.GetByID(c.Request.Context(), id)
+	switch {
+	case errs.Is(err, errs.NotFound), errors.Is(err, repository.ErrInvalidID):
+		c.JSON(http.StatusNotFound, gin.H{"error": repository.ErrAvatarNotFound.Error()})
+		return
+	case err != nil:
+		log.Printf("Failed to get avatar %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get avatar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, avatar)
+}
+
+type UpdateAvatarRequest struct {
+	// Version is the avatar's version when it was read
+	Version  *int64            `json:"version" binding:"required"`
+	Style    string            `json:"style"`
+	Features map[string]string `json:"features"`
 }
 
 func (h *Handler) UpdateAvatar(c *gin.Context) {
@@ -48,8 +100,35 @@ func (h *Handler) UpdateAvatar(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement avatar update
-	c.JSON(http.StatusOK, gin.H{"id": id})
+	var req UpdateAvatarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Style == "" && req.Features == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "style or features is required"})
+		return
+	}
+
+	avatar, err := h.avatars.Update(c.Request.Context(), id, *req.Version, &model.AvatarUpdateRequest{
+		Style:    req.Style,
+		Features: req.Features,
+	})
+	switch {
+	case errors.Is(err, repository.ErrVersionConflict):
+		// The client reads the avatar again and reapplies its edit
+		c.JSON(errs.HTTPStatus(errs.Conflict), gin.H{"error": err.Error()})
+		return
+	case errs.Is(err, errs.NotFound), errors.Is(err, repository.ErrInvalidID):
+		c.JSON(http.StatusNotFound, gin.H{"error": repository.ErrAvatarNotFound.Error()})
+		return
+	case err != nil:
+		log.Printf("Failed to update avatar %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update avatar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, avatar)
 }
 
 func (h *Handler) DeleteAvatar(c *gin.Context) {
@@ -59,6 +138,51 @@ func (h *Handler) DeleteAvatar(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement avatar deletion
-	c.JSON(http.StatusOK, gin.H{"message": "Avatar deleted"})
+	err := h.avatars.Delete(c.Request.Context(), id)
+	switch {
+	case errs.Is(err, errs.NotFound), errors.Is(err, repository.ErrInvalidID):
+		c.JSON(http.StatusNotFound, gin.H{"error": repository.ErrAvatarNotFound.Error()})
+		return
+	case err != nil:
+		log.Printf("Failed to delete avatar %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete avatar"})
+		return
+	}
+
+	// Restorable until the window runs out, then purged
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Avatar deleted",
+		"restore_before": time.Now().Add(h.restoreWindow),
+	})
+}
+
+func (h *Handler) RestoreAvatar(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "avatar ID is required"})
+		return
+	}
+
+	err := h.avatars.Restore(c.Request.Context(), id, h.restoreWindow)
+	switch {
+	case errors.Is(err, repository.ErrRestoreExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	case errs.Is(err, errs.NotFound), errors.Is(err, repository.ErrInvalidID):
+		c.JSON(http.StatusNotFound, gin.H{"error": repository.ErrAvatarNotFound.Error()})
+		return
+	case err != nil:
+		log.Printf("Failed to restore avatar %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore avatar"})
+		return
+	}
+
+	avatar, err := h.avatars.GetByID(c.Request.Context(), id)
+	if err != nil {
+		log.Printf("Failed to get restored avatar %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get avatar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, avatar)
 }
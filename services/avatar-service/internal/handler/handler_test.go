@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/repository"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// memoryStore keeps avatars in memory, and when deleted ones were deleted.
+type memoryStore struct {
+	mu      sync.Mutex
+	avatars map[string]model.Avatar
+	deleted map[string]time.Time
+}
+
+func (s *memoryStore) Create(ctx context.Context, avatar *model.Avatar) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	avatar.ID = fmt.Sprintf("avatar-%d", len(s.avatars)+1)
+	s.avatars[avatar.ID] = *avatar
+	return nil
+}
+
+func (s *memoryStore) GetByID(ctx context.Context, id string) (*model.Avatar, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	avatar, ok := s.avatars[id]
+	if _, deleted := s.deleted[id]; !ok || deleted {
+		return nil, repository.ErrAvatarNotFound
+	}
+	return &avatar, nil
+}
+
+func (s *memoryStore) UpdateStatus(ctx context.Context, id, status, imageURL string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	avatar := s.avatars[id]
+	avatar.Status = status
+	if imageURL != "" {
+		avatar.ImageURL = imageURL
+	}
+	s.avatars[id] = avatar
+	return nil
+}
+
+func (s *memoryStore) MarkFailed(ctx context.Context, id string, fieldErrs []model.FieldError) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	avatar := s.avatars[id]
+	avatar.Status = model.StatusError
+	avatar.Errors = fieldErrs
+	s.avatars[id] = avatar
+	return nil
+}
+
+func (s *memoryStore) FailUnfinished(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var failed int64
+	for id, avatar := range s.avatars {
+		if avatar.Status == model.StatusPending || avatar.Status == model.StatusGenerating {
+			avatar.Status = model.StatusError
+			s.avatars[id] = avatar
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+func (s *memoryStore) Update(ctx context.Context, id string, version int64, update *model.AvatarUpdateRequest) (*model.Avatar, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	avatar, ok := s.avatars[id]
+	if !ok {
+		return nil, repository.ErrAvatarNotFound
+	}
+	if avatar.Version != version {
+		return nil, repository.ErrVersionConflict
+	}
+	if update.Style != "" {
+		avatar.Style = update.Style
+	}
+	if update.Features != nil {
+		avatar.Features = update.Features
+	}
+	avatar.Version++
+	s.avatars[id] = avatar
+	return &avatar, nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, deleted := s.deleted[id]; deleted {
+		return repository.ErrAvatarNotFound
+	}
+	if _, ok := s.avatars[id]; !ok {
+		return repository.ErrAvatarNotFound
+	}
+	if s.deleted == nil {
+		s.deleted = make(map[string]time.Time)
+	}
+	s.deleted[id] = time.Now()
+	return nil
+}
+
+func (s *memoryStore) Restore(ctx context.Context, id string, window time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deletedAt, ok := s.deleted[id]
+	switch {
+	case !ok:
+		return repository.ErrAvatarNotFound
+	case time.Since(deletedAt) > window:
+		return repository.ErrRestoreExpired
+	}
+	delete(s.deleted, id)
+	return nil
+}
+
+func newTestRouter(generator *service.GenerationQueue, store service.AvatarStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(generator, store, time.Hour)
+	r := gin.New()
+	r.POST("/v1/avatar/generate", h.GenerateAvatar)
+	r.GET("/v1/avatar/:id", h.GetAvatar)
+	r.PUT("/v1/avatar/:id", h.UpdateAvatar)
+	r.DELETE("/v1/avatar/:id", h.DeleteAvatar)
+	r.POST("/v1/avatar/:id/restore", h.RestoreAvatar)
+	return r
+}
+
+func serve(r *gin.Engine, method, path, body string) (*httptest.ResponseRecorder, model.Avatar) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var avatar model.Avatar
+	json.Unmarshal(w.Body.Bytes(), &avatar)
+	return w, avatar
+}
+
+const generateBody = `{"style":"anime","features":["hair=short","glasses"]}`
+
+func TestGenerateAvatar_Queued(t *testing.T) {
+	store := &memoryStore{avatars: make(map[string]model.Avatar)}
+	generator := service.NewGenerationQueue(store, client.NewMockVRoidClient(), 1)
+	r := newTestRouter(generator, store)
+
+	w, avatar := serve(r, http.MethodPost, "/v1/avatar/generate", generateBody)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", w.Code, w.Body)
+	}
+	if avatar.ID == "" || avatar.Status != model.StatusPending {
+		t.Fatalf("unexpected avatar: %+v", avatar)
+	}
+	if want := map[string]string{"hair": "short", "glasses": ""}; !maps.Equal(avatar.Features, want) {
+		t.Fatalf("features = %v, want %v", avatar.Features, want)
+	}
+
+	w, _ = serve(r, http.MethodPost, "/v1/avatar/generate", generateBody)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 once the queue is full", w.Code)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	generator.Start(ctx, 1)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w, got := serve(r, http.MethodGet, "/v1/avatar/"+avatar.ID, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", w.Code)
+		}
+		if got.Status == model.StatusReady {
+			if got.ImageURL == "" {
+				t.Fatalf("ready avatar has no image: %+v", got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("avatar never became ready: %+v", got)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGenerateAvatar_InvalidFeatures(t *testing.T) {
+	store := &memoryStore{avatars: make(map[string]model.Avatar)}
+	generator := service.NewGenerationQueue(store, client.NewMockVRoidClient(), 1)
+	r := newTestRouter(generator, store)
+
+	for _, body := range []string{
+		`{"style":"anime","features":{"hair":"short"}}`,
+		`{"style":"anime","features":["=short"]}`,
+		`{"style":"anime","features":["hair=short","hair=long"]}`,
+	} {
+		if w, _ := serve(r, http.MethodPost, "/v1/avatar/generate", body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, w.Code)
+		}
+	}
+}
+
+func TestGetAvatar_NotFound(t *testing.T) {
+	store := &memoryStore{avatars: make(map[string]model.Avatar)}
+	r := newTestRouter(service.NewGenerationQueue(store, client.NewMockVRoidClient(), 1), store)
+
+	if w, _ := serve(r, http.MethodGet, "/v1/avatar/missing", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestUpdateAvatar(t *testing.T) {
+	store := &memoryStore{avatars: map[string]model.Avatar{
+		"avatar-1": {ID: "avatar-1", Style: "anime", Features: map[string]string{"hair": "short"}, Version: 1},
+	}}
+	r := newTestRouter(service.NewGenerationQueue(store, client.NewMockVRoidClient(), 1), store)
+
+	w, avatar := serve(r, http.MethodPut, "/v1/avatar/avatar-1", `{"version":1,"style":"realistic"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+	}
+	if avatar.Style != "realistic" || avatar.Features["hair"] != "short" || avatar.Version != 2 {
+		t.Fatalf("unexpected avatar: %+v", avatar)
+	}
+
+	// A second edit read at the same version lost the race
+	if w, _ := serve(r, http.MethodPut, "/v1/avatar/avatar-1", `{"version":1,"style":"chibi"}`); w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409 for a stale version", w.Code)
+	}
+	if w, _ := serve(r, http.MethodPut, "/v1/avatar/avatar-1", `{"style":"chibi"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400 without a version", w.Code)
+	}
+	if w, _ := serve(r, http.MethodPut, "/v1/avatar/avatar-1", `{"version":2}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400 without changes", w.Code)
+	}
+	if w, _ := serve(r, http.MethodPut, "/v1/avatar/missing", `{"version":1,"style":"chibi"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestDeleteAndRestoreAvatar(t *testing.T) {
+	store := &memoryStore{avatars: map[string]model.Avatar{
+		"avatar-1": {ID: "avatar-1", Style: "anime", Version: 1},
+		"avatar-2": {ID: "avatar-2", Style: "anime", Version: 1},
+	}}
+	r := newTestRouter(service.NewGenerationQueue(store, client.NewMockVRoidClient(), 1), store)
+
+	if w, _ := serve(r, http.MethodDelete, "/v1/avatar/avatar-1", ""); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+	}
+	if w, _ := serve(r, http.MethodGet, "/v1/avatar/avatar-1", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404 once deleted", w.Code)
+	}
+	if w, _ := serve(r, http.MethodDelete, "/v1/avatar/avatar-1", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404 deleting twice", w.Code)
+	}
+
+	w, avatar := serve(r, http.MethodPost, "/v1/avatar/avatar-1/restore", "")
+	if w.Code != http.StatusOK || avatar.ID != "avatar-1" {
+		t.Fatalf("status = %d, want 200 with the avatar: %s", w.Code, w.Body)
+	}
+	if w, _ := serve(r, http.MethodGet, "/v1/avatar/avatar-1", ""); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 once restored", w.Code)
+	}
+	if w, _ := serve(r, http.MethodPost, "/v1/avatar/avatar-1/restore", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404 restoring a live avatar", w.Code)
+	}
+
+	// Deleted before the restore window
+	store.deleted = map[string]time.Time{"avatar-2": time.Now().Add(-2 * time.Hour)}
+	if w, _ := serve(r, http.MethodPost, "/v1/avatar/avatar-2/restore", ""); w.Code != http.StatusGone {
+		t.Fatalf("status = %d, want 410 past the restore window", w.Code)
+	}
+	if w, _ := serve(r, http.MethodDelete, "/v1/avatar/missing", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+}
@@ -1,16 +1,45 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Avatar generation statuses
+const (
+	StatusPending    = "pending"
+	StatusGenerating = "generating"
+	StatusReady      = "ready"
+	StatusError      = "error"
+)
 
 // Avatar represents a VRoid Studio avatar
 type Avatar struct {
-	ID        string            `json:"id"`
+	ID        string            `json:"id" bson:"_id,omitempty"`
 	Style     string            `json:"style"`
 	Features  map[string]string `json:"features"`
-	ImageURL  string            `json:"image_url"`
+	ImageURL  string            `json:"image_url" bson:"image_url"`
 	Status    string            `json:"status"` // pending, generating, ready, error
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
+	CreatedAt time.Time         `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" bson:"updated_at"`
+	// Version counts the edits made to the style and features; an edit
+	// names the version it was read at, so concurrent edits cannot clobber
+	// each other. Avatars stored before versioning are at version 0.
+	Version int64 `json:"version" bson:"version"`
+	// DeletedAt is set while the avatar is soft-deleted and can still be restored
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	// Errors explains a failed generation that VRoid rejected as invalid
+	Errors []FieldError `json:"errors,omitempty" bson:"errors,omitempty"`
+}
+
+// FieldError is a problem with one field of an avatar request, such as a
+// feature VRoid does not support or one that does not go with the style
+type FieldError struct {
+	// Field is "style" or "features.<name>"
+	Field   string `json:"field" bson:"field"`
+	Code    string `json:"code" bson:"code"`
+	Message string `json:"message" bson:"message"`
 }
 
 // AvatarGenerationRequest represents a request to generate a new avatar
@@ -24,3 +53,22 @@ type AvatarUpdateRequest struct {
 	Style    string            `json:"style,omitempty"`
 	Features map[string]string `json:"features,omitempty"`
 }
+
+// ParseFeatures turns the features of a generate request, each "name" or
+// "name=value", into the name to value map avatars store. A bare name has
+// an empty value.
+func ParseFeatures(entries []string) (map[string]string, error) {
+	features := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name, value, _ := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("feature %q has no name", entry)
+		}
+		if _, ok := features[name]; ok {
+			return nil, fmt.Errorf("feature %q is given more than once", name)
+		}
+		features[name] = strings.TrimSpace(value)
+	}
+	return features, nil
+}
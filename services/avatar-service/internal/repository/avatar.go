@@ -2,28 +2,61 @@ package repository
 
 import (
 	"context"
-	"errors"
+	"log"
+	"maps"
 	"time"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/errs"
 	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultRestoreWindow is how long a deleted avatar can be restored before
+// it is purged.
+const DefaultRestoreWindow = 7 * 24 * time.Hour
+
+// DefaultPurgeInterval is how often avatars past their restore window are
+// purged.
+const DefaultPurgeInterval = time.Hour
+
+var (
+	// ErrAvatarNotFound is returned when no live avatar has the given ID.
+	ErrAvatarNotFound = errs.New(errs.NotFound, "avatar not found")
+	// ErrInvalidID is returned for IDs that are not ObjectID hex strings.
+	ErrInvalidID = errs.New(errs.Invalid, "invalid id format")
+	// ErrVersionConflict is returned when an avatar was edited since the
+	// version an update was read at.
+	ErrVersionConflict = errs.New(errs.Conflict, "avatar was changed since it was read")
+	// ErrRestoreExpired is returned when restoring an avatar deleted longer
+	// ago than the restore window.
+	ErrRestoreExpired = errs.New(errs.NotFound, "restore window expired")
 )
 
 type AvatarRepository struct {
 	collection *mongo.Collection
+	now        func() time.Time
 }
 
 func NewAvatarRepository(db *mongo.Database) *AvatarRepository {
 	return &AvatarRepository{
 		collection: db.Collection("avatars"),
+		now:        time.Now,
 	}
 }
 
+// notDeleted narrows filter to avatars that are not soft-deleted.
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
 func (r *AvatarRepository) Create(ctx context.Context, avatar *model.Avatar) error {
 	avatar.CreatedAt = time.Now()
 	avatar.UpdatedAt = time.Now()
+	avatar.Version = 1
 
 	result, err := r.collection.InsertOne(ctx, avatar)
 	if err != nil {
@@ -40,14 +73,14 @@ func (r *AvatarRepository) Create(ctx context.Context, avatar *model.Avatar) err
 func (r *AvatarRepository) GetByID(ctx context.Context, id string) (*model.Avatar, error) {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid id format")
+		return nil, ErrInvalidID
 	}
 
 	var avatar model.Avatar
-	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&avatar)
+	err = r.collection.FindOne(ctx, notDeleted(bson.M{"_id": oid})).Decode(&avatar)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("avatar not found")
+			return nil, ErrAvatarNotFound
 		}
 		return nil, err
 	}
@@ -55,51 +88,234 @@ func (r *AvatarRepository) GetByID(ctx context.Context, id string) (*model.Avata
 	return &avatar, nil
 }
 
-func (r *AvatarRepository) Update(ctx context.Context, id string, update *model.AvatarUpdateRequest) error {
-	oid, err := primitive.ObjectIDFromHex(id)
+// List returns all avatars that are not soft-deleted.
+func (r *AvatarRepository) List(ctx context.Context) ([]*model.Avatar, error) {
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{}))
 	if err != nil {
-		return errors.New("invalid id format")
+		return nil, err
+	}
+
+	avatars := []*model.Avatar{}
+	if err := cursor.All(ctx, &avatars); err != nil {
+		return nil, err
 	}
 
-	updateDoc := bson.M{
-		"$set": bson.M{
-			"updated_at": time.Now(),
-		},
+	return avatars, nil
+}
+
+// versionFilter matches avatars at version, counting those stored before
+// versioning as version 0.
+func versionFilter(version int64) any {
+	if version == 0 {
+		return bson.M{"$in": bson.A{nil, int64(0)}}
 	}
+	return version
+}
 
+// Update edits the style and features of an avatar read at version and
+// returns the avatar as edited, at the next version. It fails with
+// ErrVersionConflict when the avatar was edited since, unless that edit
+// was this one, so retrying an update that was applied but whose answer
+// was lost returns the avatar instead of a conflict.
+func (r *AvatarRepository) Update(ctx context.Context, id string, version int64, update *model.AvatarUpdateRequest) (*model.Avatar, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	set := bson.M{"updated_at": r.now()}
 	if update.Style != "" {
-		updateDoc["$set"].(bson.M)["style"] = update.Style
+		set["style"] = update.Style
 	}
 	if update.Features != nil {
-		updateDoc["$set"].(bson.M)["features"] = update.Features
+		set["features"] = update.Features
+	}
+
+	filter := notDeleted(bson.M{"_id": oid, "version": versionFilter(version)})
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var avatar model.Avatar
+	err = r.collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": set, "$inc": bson.M{"version": 1}}, opts).Decode(&avatar)
+	if err == nil {
+		return &avatar, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Version == version+1 && applied(current, update) {
+		return current, nil
+	}
+	return nil, ErrVersionConflict
+}
+
+// applied reports whether avatar already has the style and features update
+// sets.
+func applied(avatar *model.Avatar, update *model.AvatarUpdateRequest) bool {
+	if update.Style != "" && avatar.Style != update.Style {
+		return false
+	}
+	return update.Features == nil || maps.Equal(avatar.Features, update.Features)
+}
+
+// Delete soft-deletes an avatar: it disappears from reads but can be
+// restored until PurgeExpired removes it.
+func (r *AvatarRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, updateDoc)
+	now := r.now()
+	result, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": oid}), bson.M{
+		"$set": bson.M{"deleted_at": now, "updated_at": now},
+	})
 	if err != nil {
 		return err
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("avatar not found")
+		return ErrAvatarNotFound
 	}
 
 	return nil
 }
 
-func (r *AvatarRepository) Delete(ctx context.Context, id string) error {
+// Restore undoes a soft delete made within the last window.
+func (r *AvatarRepository) Restore(ctx context.Context, id string, window time.Duration) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid id format")
+		return ErrInvalidID
 	}
 
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	now := r.now()
+	result, err := r.collection.UpdateOne(ctx, bson.M{
+		"_id":        oid,
+		"deleted_at": bson.M{"$gte": now.Add(-window)},
+	}, bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$set":   bson.M{"updated_at": now},
+	})
 	if err != nil {
 		return err
 	}
 
-	if result.DeletedCount == 0 {
-		return errors.New("avatar not found")
+	if result.MatchedCount == 0 {
+		// Tell an avatar deleted too long ago, and not purged yet, from one
+		// that is not deleted or does not exist
+		err := r.collection.FindOne(ctx, bson.M{"_id": oid, "deleted_at": bson.M{"$exists": true}}).Err()
+		switch {
+		case err == mongo.ErrNoDocuments:
+			return ErrAvatarNotFound
+		case err != nil:
+			return err
+		}
+		return ErrRestoreExpired
 	}
 
 	return nil
 }
+
+// PurgeExpired permanently removes avatars soft-deleted more than window
+// ago and returns how many were removed.
+func (r *AvatarRepository) PurgeExpired(ctx context.Context, window time.Duration) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{
+		"deleted_at": bson.M{"$lt": r.now().Add(-window)},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// RunPurger calls PurgeExpired every interval until ctx is done.
+func (r *AvatarRepository) RunPurger(ctx context.Context, interval, window time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := r.PurgeExpired(ctx, window)
+			if err != nil {
+				log.Printf("Failed to purge deleted avatars: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Purged %d deleted avatars", purged)
+			}
+		}
+	}
+}
+
+// UpdateStatus records the generation status of an avatar, and its image
+// once one is available.
+func (r *AvatarRepository) UpdateStatus(ctx context.Context, id, status, imageURL string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	set := bson.M{"status": status, "updated_at": r.now()}
+	if imageURL != "" {
+		set["image_url"] = imageURL
+	}
+
+	result, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": oid}), bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrAvatarNotFound
+	}
+
+	return nil
+}
+
+// MarkFailed records that generating an avatar failed, with the field errors
+// that explain why when VRoid rejected the request.
+func (r *AvatarRepository) MarkFailed(ctx context.Context, id string, fieldErrs []model.FieldError) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	set := bson.M{"status": model.StatusError, "updated_at": r.now()}
+	if len(fieldErrs) > 0 {
+		set["errors"] = fieldErrs
+	}
+
+	result, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": oid}), bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrAvatarNotFound
+	}
+
+	return nil
+}
+
+// FailUnfinished marks every avatar still pending or generating as failed
+// and returns how many were. Their jobs did not survive a restart.
+func (r *AvatarRepository) FailUnfinished(ctx context.Context) (int64, error) {
+	filter := notDeleted(bson.M{
+		"status": bson.M{"$in": bson.A{model.StatusPending, model.StatusGenerating}},
+	})
+	result, err := r.collection.UpdateMany(ctx, filter, bson.M{
+		"$set": bson.M{"status": model.StatusError, "updated_at": r.now()},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
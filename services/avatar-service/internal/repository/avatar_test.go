@@ -0,0 +1,367 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+var fixedNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestRepository(mt *mtest.T) *AvatarRepository {
+	repo := NewAvatarRepository(mt.DB)
+	repo.now = func() time.Time { return fixedNow }
+	return repo
+}
+
+// startedCommand returns the last command sent to the server.
+func startedCommand(mt *mtest.T) bson.Raw {
+	mt.Helper()
+	event := mt.GetStartedEvent()
+	if event == nil {
+		mt.Fatal("no command was sent")
+	}
+	return event.Command
+}
+
+// statement returns the first statement of an update or delete command.
+func statement(cmd bson.Raw, field string) bson.Raw {
+	return cmd.Lookup(field).Array().Index(0).Value().Document()
+}
+
+func assertExcludesDeleted(mt *mtest.T, filter bson.Raw) {
+	mt.Helper()
+	exists, ok := filter.Lookup("deleted_at", "$exists").BooleanOK()
+	if !ok || exists {
+		mt.Fatalf("filter does not exclude soft-deleted avatars: %v", filter)
+	}
+}
+
+func TestSoftDeleteExclusion(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	id := primitive.NewObjectID()
+
+	mt.Run("get by id", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		ns := mt.DB.Name() + ".avatars"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}, {Key: "style", Value: "anime"}}))
+
+		avatar, err := repo.GetByID(context.Background(), id.Hex())
+		if err != nil {
+			mt.Fatalf("GetByID: %v", err)
+		}
+		if avatar.Style != "anime" {
+			mt.Fatalf("unexpected avatar: %+v", avatar)
+		}
+		assertExcludesDeleted(mt, startedCommand(mt).Lookup("filter").Document())
+	})
+
+	mt.Run("soft-deleted avatar is not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		ns := mt.DB.Name() + ".avatars"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		if _, err := repo.GetByID(context.Background(), id.Hex()); err == nil || err.Error() != "avatar not found" {
+			mt.Fatalf("expected avatar not found, got %v", err)
+		}
+	})
+
+	mt.Run("list", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		ns := mt.DB.Name() + ".avatars"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			bson.D{{Key: "style", Value: "anime"}},
+			bson.D{{Key: "style", Value: "realistic"}},
+		))
+
+		avatars, err := repo.List(context.Background())
+		if err != nil {
+			mt.Fatalf("List: %v", err)
+		}
+		if len(avatars) != 2 {
+			mt.Fatalf("expected 2 avatars, got %d", len(avatars))
+		}
+		assertExcludesDeleted(mt, startedCommand(mt).Lookup("filter").Document())
+	})
+
+	mt.Run("delete marks instead of removing", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		if err := repo.Delete(context.Background(), id.Hex()); err != nil {
+			mt.Fatalf("Delete: %v", err)
+		}
+		cmd := startedCommand(mt)
+		if name := cmd.Index(0).Key(); name != "update" {
+			mt.Fatalf("expected an update command, got %s", name)
+		}
+		update := statement(cmd, "updates")
+		assertExcludesDeleted(mt, update.Lookup("q").Document())
+		deletedAt := update.Lookup("u", "$set", "deleted_at").Time()
+		if !deletedAt.Equal(fixedNow) {
+			mt.Fatalf("deleted_at = %v, want %v", deletedAt, fixedNow)
+		}
+	})
+
+	mt.Run("deleting a deleted avatar fails", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}))
+
+		if err := repo.Delete(context.Background(), id.Hex()); err == nil || err.Error() != "avatar not found" {
+			mt.Fatalf("expected avatar not found, got %v", err)
+		}
+	})
+}
+
+func TestRestore(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	id := primitive.NewObjectID()
+	window := 24 * time.Hour
+
+	mt.Run("within window", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		if err := repo.Restore(context.Background(), id.Hex(), window); err != nil {
+			mt.Fatalf("Restore: %v", err)
+		}
+		update := statement(startedCommand(mt), "updates")
+		since := update.Lookup("q", "deleted_at", "$gte").Time()
+		if !since.Equal(fixedNow.Add(-window)) {
+			mt.Fatalf("restore window starts at %v, want %v", since, fixedNow.Add(-window))
+		}
+		if _, err := update.LookupErr("u", "$unset", "deleted_at"); err != nil {
+			mt.Fatalf("restore does not clear deleted_at: %v", update)
+		}
+	})
+
+	mt.Run("window expired", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		ns := mt.DB.Name() + ".avatars"
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "_id", Value: id}, {Key: "deleted_at", Value: fixedNow.Add(-2 * window)}}),
+		)
+
+		if err := repo.Restore(context.Background(), id.Hex(), window); !errors.Is(err, ErrRestoreExpired) {
+			mt.Fatalf("expected ErrRestoreExpired, got %v", err)
+		}
+		startedCommand(mt) // the update
+		exists, ok := startedCommand(mt).Lookup("filter", "deleted_at", "$exists").BooleanOK()
+		if !ok || !exists {
+			mt.Fatalf("expired restores are told apart by looking up the deleted avatar")
+		}
+	})
+
+	mt.Run("not deleted", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		ns := mt.DB.Name() + ".avatars"
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch),
+		)
+
+		if err := repo.Restore(context.Background(), id.Hex(), window); !errors.Is(err, ErrAvatarNotFound) {
+			mt.Fatalf("expected ErrAvatarNotFound, got %v", err)
+		}
+	})
+}
+
+func TestPurgeExpired(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	window := 24 * time.Hour
+
+	mt.Run("removes expired soft-deletes", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}))
+
+		purged, err := repo.PurgeExpired(context.Background(), window)
+		if err != nil {
+			mt.Fatalf("PurgeExpired: %v", err)
+		}
+		if purged != 3 {
+			mt.Fatalf("purged = %d, want 3", purged)
+		}
+
+		cmd := startedCommand(mt)
+		if name := cmd.Index(0).Key(); name != "delete" {
+			mt.Fatalf("expected a delete command, got %s", name)
+		}
+		before := statement(cmd, "deletes").Lookup("q", "deleted_at", "$lt").Time()
+		if !before.Equal(fixedNow.Add(-window)) {
+			mt.Fatalf("purge cutoff = %v, want %v", before, fixedNow.Add(-window))
+		}
+	})
+}
+
+func TestUpdateStatus(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	id := primitive.NewObjectID()
+
+	mt.Run("sets status and image", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		if err := repo.UpdateStatus(context.Background(), id.Hex(), "ready", "https://example.com/avatar.png"); err != nil {
+			mt.Fatalf("UpdateStatus: %v", err)
+		}
+		update := statement(startedCommand(mt), "updates")
+		assertExcludesDeleted(mt, update.Lookup("q").Document())
+		if status := update.Lookup("u", "$set", "status").StringValue(); status != "ready" {
+			mt.Fatalf("status = %q, want ready", status)
+		}
+		if url := update.Lookup("u", "$set", "image_url").StringValue(); url != "https://example.com/avatar.png" {
+			mt.Fatalf("image_url = %q", url)
+		}
+	})
+
+	mt.Run("keeps the image while generating", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		if err := repo.UpdateStatus(context.Background(), id.Hex(), "generating", ""); err != nil {
+			mt.Fatalf("UpdateStatus: %v", err)
+		}
+		update := statement(startedCommand(mt), "updates")
+		if _, err := update.LookupErr("u", "$set", "image_url"); err == nil {
+			mt.Fatalf("image_url was cleared: %v", update)
+		}
+	})
+}
+
+func TestFailUnfinished(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("fails pending and generating avatars", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}))
+
+		failed, err := repo.FailUnfinished(context.Background())
+		if err != nil {
+			mt.Fatalf("FailUnfinished: %v", err)
+		}
+		if failed != 2 {
+			mt.Fatalf("failed = %d, want 2", failed)
+		}
+
+		update := statement(startedCommand(mt), "updates")
+		if multi, _ := update.Lookup("multi").BooleanOK(); !multi {
+			mt.Fatalf("expected an update of every match: %v", update)
+		}
+		filter := update.Lookup("q").Document()
+		assertExcludesDeleted(mt, filter)
+		statuses, err := filter.Lookup("status", "$in").Array().Values()
+		if err != nil || len(statuses) != 2 ||
+			statuses[0].StringValue() != model.StatusPending || statuses[1].StringValue() != model.StatusGenerating {
+			mt.Fatalf("status filter = %v", filter)
+		}
+		if status := update.Lookup("u", "$set", "status").StringValue(); status != model.StatusError {
+			mt.Fatalf("status = %q, want %s", status, model.StatusError)
+		}
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	id := primitive.NewObjectID()
+	ns := func(mt *mtest.T) string { return mt.DB.Name() + ".avatars" }
+	edit := &model.AvatarUpdateRequest{Style: "realistic", Features: map[string]string{"hair": "long"}}
+
+	mt.Run("at the read version", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+			{Key: "_id", Value: id}, {Key: "style", Value: "realistic"}, {Key: "version", Value: int64(4)},
+		}}))
+
+		avatar, err := repo.Update(context.Background(), id.Hex(), 3, edit)
+		if err != nil {
+			mt.Fatalf("Update: %v", err)
+		}
+		if avatar.Version != 4 || avatar.Style != "realistic" {
+			mt.Fatalf("unexpected avatar: %+v", avatar)
+		}
+
+		cmd := startedCommand(mt)
+		if name := cmd.Index(0).Key(); name != "findAndModify" {
+			mt.Fatalf("expected a findAndModify command, got %s", name)
+		}
+		query := cmd.Lookup("query").Document()
+		assertExcludesDeleted(mt, query)
+		if version := query.Lookup("version").Int64(); version != 3 {
+			mt.Fatalf("update matches version %d, want 3", version)
+		}
+		if inc := cmd.Lookup("update", "$inc", "version").Int32(); inc != 1 {
+			mt.Fatalf("version is incremented by %d, want 1", inc)
+		}
+		if style := cmd.Lookup("update", "$set", "style").StringValue(); style != "realistic" {
+			mt.Fatalf("style = %q, want realistic", style)
+		}
+		if !cmd.Lookup("update", "$set", "updated_at").Time().Equal(fixedNow) {
+			mt.Fatalf("updated_at is not set: %v", cmd)
+		}
+	})
+
+	mt.Run("avatars stored before versioning are at version 0", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: id}, {Key: "version", Value: int64(1)}}}))
+
+		if _, err := repo.Update(context.Background(), id.Hex(), 0, edit); err != nil {
+			mt.Fatalf("Update: %v", err)
+		}
+		if _, err := startedCommand(mt).LookupErr("query", "version", "$in"); err != nil {
+			mt.Fatalf("version 0 does not match unversioned avatars: %v", startedCommand(mt))
+		}
+	})
+
+	mt.Run("stale version conflicts", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+			mtest.CreateCursorResponse(0, ns(mt), mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: id}, {Key: "style", Value: "chibi"}, {Key: "version", Value: int64(4)},
+			}),
+		)
+
+		_, err := repo.Update(context.Background(), id.Hex(), 3, edit)
+		if !errors.Is(err, ErrVersionConflict) {
+			mt.Fatalf("expected ErrVersionConflict, got %v", err)
+		}
+	})
+
+	mt.Run("retry of an applied update", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+			mtest.CreateCursorResponse(0, ns(mt), mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: id}, {Key: "style", Value: "realistic"},
+				{Key: "features", Value: bson.D{{Key: "hair", Value: "long"}}}, {Key: "version", Value: int64(4)},
+			}),
+		)
+
+		avatar, err := repo.Update(context.Background(), id.Hex(), 3, edit)
+		if err != nil {
+			mt.Fatalf("Update: %v", err)
+		}
+		if avatar.Version != 4 {
+			mt.Fatalf("version = %d, want 4", avatar.Version)
+		}
+	})
+
+	mt.Run("missing avatar", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+			mtest.CreateCursorResponse(0, ns(mt), mtest.FirstBatch),
+		)
+
+		if _, err := repo.Update(context.Background(), id.Hex(), 3, edit); !errors.Is(err, ErrAvatarNotFound) {
+			mt.Fatalf("expected ErrAvatarNotFound, got %v", err)
+		}
+	})
+}
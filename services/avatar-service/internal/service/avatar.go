@@ -2,8 +2,9 @@ package service
 
 import (
 	"context"
-	"errors"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/errs"
+	"github.com/careerup-Inc/careerup-monorepo/pkg/httpclient"
 	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/client"
 	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
 )
@@ -17,7 +18,7 @@ func NewAvatarService(apiKey string) *AvatarService {
 	if apiKey == "" {
 		vroidClient = client.NewMockVRoidClient()
 	} else {
-		vroidClient = client.NewVRoidClient(apiKey)
+		vroidClient = client.NewVRoidClient(apiKey, httpclient.Config{})
 	}
 
 	return &AvatarService{
@@ -27,10 +28,10 @@ func NewAvatarService(apiKey string) *AvatarService {
 
 func (s *AvatarService) GenerateAvatar(ctx context.Context, req *model.AvatarGenerationRequest) (*model.Avatar, error) {
 	if req.Style == "" {
-		return nil, errors.New("style is required")
+		return nil, errs.New(errs.Invalid, "style is required")
 	}
 	if req.Features == nil {
-		return nil, errors.New("features are required")
+		return nil, errs.New(errs.Invalid, "features are required")
 	}
 
 	return s.vroidClient.GenerateAvatar(ctx, req)
@@ -38,7 +39,7 @@ func (s *AvatarService) GenerateAvatar(ctx context.Context, req *model.AvatarGen
 
 func (s *AvatarService) GetAvatar(ctx context.Context, id string) (*model.Avatar, error) {
 	if id == "" {
-		return nil, errors.New("avatar ID is required")
+		return nil, errs.New(errs.Invalid, "avatar ID is required")
 	}
 
 	return s.vroidClient.GetAvatar(ctx, id)
@@ -46,7 +47,7 @@ func (s *AvatarService) GetAvatar(ctx context.Context, id string) (*model.Avatar
 
 func (s *AvatarService) UpdateAvatar(ctx context.Context, id string, req *model.AvatarUpdateRequest) (*model.Avatar, error) {
 	if id == "" {
-		return nil, errors.New("avatar ID is required")
+		return nil, errs.New(errs.Invalid, "avatar ID is required")
 	}
 
 	return s.vroidClient.UpdateAvatar(ctx, id, req)
@@ -54,7 +55,7 @@ func (s *AvatarService) UpdateAvatar(ctx context.Context, id string, req *model.
 
 func (s *AvatarService) DeleteAvatar(ctx context.Context, id string) error {
 	if id == "" {
-		return errors.New("avatar ID is required")
+		return errs.New(errs.Invalid, "avatar ID is required")
 	}
 
 	return s.vroidClient.DeleteAvatar(ctx, id)
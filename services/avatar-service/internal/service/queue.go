@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/pkg/errs"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
+)
+
+// DefaultGenerationTimeout bounds a single VRoid generation call.
+const DefaultGenerationTimeout = 2 * time.Minute
+
+// statusWriteTimeout bounds each status write. Writes outlive the queue's
+// context, so an avatar is not left pending or generating by shutdown.
+const statusWriteTimeout = 5 * time.Second
+
+// ErrQueueFull is returned by Enqueue when the queue has no room left.
+var ErrQueueFull = errs.New(errs.Unavailable, "avatar generation queue is full")
+
+// ErrQueueStopped is returned by Enqueue once the queue is shutting down.
+var ErrQueueStopped = errs.New(errs.Unavailable, "avatar generation queue is shutting down")
+
+// AvatarStore persists avatars and their generation status.
+type AvatarStore interface {
+	Create(ctx context.Context, avatar *model.Avatar) error
+	GetByID(ctx context.Context, id string) (*model.Avatar, error)
+	UpdateStatus(ctx context.Context, id, status, imageURL string) error
+	// MarkFailed sets the error status, with the field errors explaining it
+	MarkFailed(ctx context.Context, id string, fieldErrs []model.FieldError) error
+	// FailUnfinished marks every pending or generating avatar as failed and
+	// returns how many were
+	FailUnfinished(ctx context.Context) (int64, error)
+	// Update edits the style and features of an avatar read at version
+	Update(ctx context.Context, id string, version int64, update *model.AvatarUpdateRequest) (*model.Avatar, error)
+	// Delete soft-deletes an avatar, which Restore undoes within window
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string, window time.Duration) error
+}
+
+type generationJob struct {
+	avatarID string
+	req      model.AvatarGenerationRequest
+}
+
+// GenerationQueue runs avatar generation in the background. Enqueue stores
+// a pending avatar and returns at once; a pool of workers then generates it
+// with VRoid, moving it through generating to ready or error. Jobs still
+// waiting when the queue stops end in error.
+type GenerationQueue struct {
+	store       AvatarStore
+	vroidClient client.VRoidClientInterface
+	jobs        chan generationJob
+	// slots holds one token per job waiting in jobs, so a full queue is
+	// detected before anything is stored
+	slots   chan struct{}
+	timeout time.Duration
+	wg      sync.WaitGroup
+
+	mu sync.Mutex
+	// stopped is set once Start's ctx is done; Enqueue then refuses jobs
+	stopped bool
+}
+
+// NewGenerationQueue creates a queue that holds at most size waiting jobs.
+func NewGenerationQueue(store AvatarStore, vroidClient client.VRoidClientInterface, size int) *GenerationQueue {
+	return &GenerationQueue{
+		store:       store,
+		vroidClient: vroidClient,
+		jobs:        make(chan generationJob, size),
+		slots:       make(chan struct{}, size),
+		timeout:     DefaultGenerationTimeout,
+	}
+}
+
+// Recover marks avatars left pending or generating by a previous run as
+// failed, since their jobs were lost with it. Call it before Start.
+func (q *GenerationQueue) Recover(ctx context.Context) error {
+	failed, err := q.store.FailUnfinished(ctx)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		log.Printf("Marked %d unfinished avatars as %s", failed, model.StatusError)
+	}
+	return nil
+}
+
+// Start launches workers that process jobs until ctx is done. The jobs
+// still waiting then are marked failed. Wait blocks until all of that is
+// done.
+func (q *GenerationQueue) Start(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			q.work(ctx)
+		}()
+	}
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		<-ctx.Done()
+		q.stop(ctx)
+	}()
+}
+
+// Wait blocks until every worker has stopped and the waiting jobs have been
+// failed.
+func (q *GenerationQueue) Wait() {
+	q.wg.Wait()
+}
+
+// Enqueue stores a pending avatar for req and queues its generation. It
+// returns ErrQueueFull without storing anything when the queue is full, and
+// ErrQueueStopped once it is shutting down.
+func (q *GenerationQueue) Enqueue(ctx context.Context, req *model.AvatarGenerationRequest) (*model.Avatar, error) {
+	if req.Style == "" {
+		return nil, errors.New("style is required")
+	}
+	if req.Features == nil {
+		return nil, errors.New("features are required")
+	}
+
+	if q.isStopped() {
+		return nil, ErrQueueStopped
+	}
+
+	select {
+	case q.slots <- struct{}{}:
+	default:
+		return nil, ErrQueueFull
+	}
+
+	avatar := &model.Avatar{
+		Style:    req.Style,
+		Features: req.Features,
+		Status:   model.StatusPending,
+	}
+	if err := q.store.Create(ctx, avatar); err != nil {
+		<-q.slots
+		return nil, err
+	}
+
+	// Checked again under the lock stop takes, so no job is queued after
+	// stop has failed the waiting ones
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped {
+		<-q.slots
+		q.fail(ctx, avatar.ID, nil)
+		return nil, ErrQueueStopped
+	}
+	// Never blocks: jobs has as much room as slots
+	q.jobs <- generationJob{avatarID: avatar.ID, req: *req}
+	return avatar, nil
+}
+
+func (q *GenerationQueue) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-q.jobs:
+			<-q.slots
+			if ctx.Err() != nil {
+				q.fail(ctx, job.avatarID, nil)
+				continue
+			}
+			q.process(ctx, job)
+		}
+	}
+}
+
+func (q *GenerationQueue) isStopped() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stopped
+}
+
+// stop refuses further jobs and fails those still waiting, so none stays
+// pending once the workers are gone.
+func (q *GenerationQueue) stop(ctx context.Context) {
+	q.mu.Lock()
+	q.stopped = true
+	q.mu.Unlock()
+
+	for {
+		select {
+		case job := <-q.jobs:
+			<-q.slots
+			q.fail(ctx, job.avatarID, nil)
+		default:
+			return
+		}
+	}
+}
+
+// statusContext returns a context for a status write that is not cancelled
+// with ctx.
+func statusContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), statusWriteTimeout)
+}
+
+func (q *GenerationQueue) fail(ctx context.Context, id string, fieldErrs []model.FieldError) {
+	writeCtx, cancel := statusContext(ctx)
+	defer cancel()
+	if err := q.store.MarkFailed(writeCtx, id, fieldErrs); err != nil {
+		log.Printf("Failed to mark avatar %s as %s: %v", id, model.StatusError, err)
+	}
+}
+
+func (q *GenerationQueue) setStatus(ctx context.Context, id, status, imageURL string) error {
+	writeCtx, cancel := statusContext(ctx)
+	defer cancel()
+	return q.store.UpdateStatus(writeCtx, id, status, imageURL)
+}
+
+func (q *GenerationQueue) process(ctx context.Context, job generationJob) {
+	if err := q.setStatus(ctx, job.avatarID, model.StatusGenerating, ""); err != nil {
+		log.Printf("Failed to mark avatar %s as generating: %v", job.avatarID, err)
+		return
+	}
+
+	genCtx, cancel := context.WithTimeout(ctx, q.timeout)
+	generated, err := q.vroidClient.GenerateAvatar(genCtx, &job.req)
+	cancel()
+
+	if err != nil {
+		log.Printf("Failed to generate avatar %s: %v", job.avatarID, err)
+		// Pass on what VRoid found wrong with the request, if anything
+		var fieldErrs []model.FieldError
+		var vroidErr *client.VRoidError
+		if errors.As(err, &vroidErr) {
+			fieldErrs = vroidErr.FieldErrors()
+		}
+		q.fail(ctx, job.avatarID, fieldErrs)
+		return
+	}
+	if err := q.setStatus(ctx, job.avatarID, model.StatusReady, generated.ImageURL); err != nil {
+		log.Printf("Failed to mark avatar %s as %s: %v", job.avatarID, model.StatusReady, err)
+	}
+}
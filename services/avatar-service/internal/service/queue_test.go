@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/avatar-service/internal/model"
+)
+
+// fakeStore keeps avatars in memory. Like Mongo, it refuses status writes
+// with a done ctx.
+type fakeStore struct {
+	mu      sync.Mutex
+	avatars map[string]*model.Avatar
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{avatars: make(map[string]*model.Avatar)}
+}
+
+func (s *fakeStore) Create(ctx context.Context, avatar *model.Avatar) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	avatar.ID = fmt.Sprintf("avatar-%d", len(s.avatars)+1)
+	stored := *avatar
+	s.avatars[avatar.ID] = &stored
+	return nil
+}
+
+func (s *fakeStore) GetByID(ctx context.Context, id string) (*model.Avatar, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	avatar, ok := s.avatars[id]
+	if !ok {
+		return nil, errors.New("avatar not found")
+	}
+	copied := *avatar
+	return &copied, nil
+}
+
+func (s *fakeStore) UpdateStatus(ctx context.Context, id, status, imageURL string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	avatar, ok := s.avatars[id]
+	if !ok {
+		return errors.New("avatar not found")
+	}
+	avatar.Status = status
+	if imageURL != "" {
+		avatar.ImageURL = imageURL
+	}
+	return nil
+}
+
+func (s *fakeStore) MarkFailed(ctx context.Context, id string, fieldErrs []model.FieldError) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	avatar, ok := s.avatars[id]
+	if !ok {
+		return errors.New("avatar not found")
+	}
+	avatar.Status = model.StatusError
+	avatar.Errors = fieldErrs
+	return nil
+}
+
+func (s *fakeStore) FailUnfinished(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var failed int64
+	for _, avatar := range s.avatars {
+		if avatar.Status == model.StatusPending || avatar.Status == model.StatusGenerating {
+			avatar.Status = model.StatusError
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+func (s *fakeStore) Update(ctx context.Context, id string, version int64, update *model.AvatarUpdateRequest) (*model.Avatar, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *fakeStore) Delete(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+func (s *fakeStore) Restore(ctx context.Context, id string, window time.Duration) error {
+	return errors.New("not implemented")
+}
+
+// blockingVRoid holds each generation until release is closed, then returns
+// err or an avatar. It gives up when ctx is done.
+type blockingVRoid struct {
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func newBlockingVRoid(err error) *blockingVRoid {
+	return &blockingVRoid{started: make(chan struct{}, 10), release: make(chan struct{}), err: err}
+}
+
+func (c *blockingVRoid) GenerateAvatar(ctx context.Context, req *model.AvatarGenerationRequest) (*model.Avatar, error) {
+	c.started <- struct{}{}
+	select {
+	case <-c.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &model.Avatar{Style: req.Style, ImageURL: "https://example.com/avatar.png", Status: model.StatusReady}, nil
+}
+
+func (c *blockingVRoid) GetAvatar(ctx context.Context, id string) (*model.Avatar, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *blockingVRoid) UpdateAvatar(ctx context.Context, id string, req *model.AvatarUpdateRequest) (*model.Avatar, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *blockingVRoid) DeleteAvatar(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+var generationRequest = &model.AvatarGenerationRequest{Style: "anime", Features: map[string]string{"hair": "short"}}
+
+// waitForStatus polls the store until the avatar reaches status.
+func waitForStatus(t *testing.T, store *fakeStore, id, status string) *model.Avatar {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		avatar, err := store.GetByID(context.Background(), id)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if avatar.Status == status {
+			return avatar
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("avatar status = %q, want %q", avatar.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGenerationQueue_Transitions(t *testing.T) {
+	t.Run("pending to ready", func(t *testing.T) {
+		store := newFakeStore()
+		vroid := newBlockingVRoid(nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		q := NewGenerationQueue(store, vroid, 1)
+
+		avatar, err := q.Enqueue(ctx, generationRequest)
+		if err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+		if avatar.Status != model.StatusPending || avatar.ID == "" {
+			t.Fatalf("unexpected enqueued avatar: %+v", avatar)
+		}
+		waitForStatus(t, store, avatar.ID, model.StatusPending)
+
+		q.Start(ctx, 1)
+		<-vroid.started
+		waitForStatus(t, store, avatar.ID, model.StatusGenerating)
+
+		close(vroid.release)
+		ready := waitForStatus(t, store, avatar.ID, model.StatusReady)
+		if ready.ImageURL != "https://example.com/avatar.png" {
+			t.Fatalf("image_url = %q", ready.ImageURL)
+		}
+	})
+
+	t.Run("failed generation", func(t *testing.T) {
+		store := newFakeStore()
+		vroid := newBlockingVRoid(errors.New("vroid unavailable"))
+		close(vroid.release)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		q := NewGenerationQueue(store, vroid, 1)
+		q.Start(ctx, 1)
+
+		avatar, err := q.Enqueue(ctx, generationRequest)
+		if err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+		failed := waitForStatus(t, store, avatar.ID, model.StatusError)
+		if len(failed.Errors) != 0 {
+			t.Fatalf("errors = %+v, want none", failed.Errors)
+		}
+	})
+
+	t.Run("rejected generation keeps field errors", func(t *testing.T) {
+		store := newFakeStore()
+		vroid := newBlockingVRoid(fmt.Errorf("generate: %w", &client.VRoidError{
+			StatusCode: 422,
+			Code:       client.VRoidCodeIncompatibleStyle,
+			Field:      "features.hair",
+			Details: []client.VRoidErrorDetail{
+				{Code: client.VRoidCodeIncompatibleStyle, Field: "features.hair", Message: "twin tails do not suit the realistic style"},
+				{Code: "quota_exceeded", Message: "not a field error"},
+			},
+		}))
+		close(vroid.release)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		q := NewGenerationQueue(store, vroid, 1)
+		q.Start(ctx, 1)
+
+		avatar, err := q.Enqueue(ctx, generationRequest)
+		if err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+		failed := waitForStatus(t, store, avatar.ID, model.StatusError)
+		want := []model.FieldError{{Field: "features.hair", Code: client.VRoidCodeIncompatibleStyle, Message: "twin tails do not suit the realistic style"}}
+		if !reflect.DeepEqual(failed.Errors, want) {
+			t.Fatalf("errors = %+v, want %+v", failed.Errors, want)
+		}
+	})
+}
+
+func TestGenerationQueue_Full(t *testing.T) {
+	store := newFakeStore()
+	vroid := newBlockingVRoid(nil)
+	close(vroid.release)
+	ctx, cancel := context.WithCancel(context.Background())
+	q := NewGenerationQueue(store, vroid, 1)
+
+	first, err := q.Enqueue(ctx, generationRequest)
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if _, err := q.Enqueue(ctx, generationRequest); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if len(store.avatars) != 1 {
+		t.Fatalf("rejected job was stored: %d avatars", len(store.avatars))
+	}
+
+	// Room frees up once a worker takes the waiting job
+	q.Start(ctx, 1)
+	waitForStatus(t, store, first.ID, model.StatusReady)
+	if _, err := q.Enqueue(ctx, generationRequest); err != nil {
+		t.Fatalf("Enqueue after drain: %v", err)
+	}
+
+	cancel()
+	q.Wait()
+}
+
+func TestGenerationQueue_Shutdown(t *testing.T) {
+	store := newFakeStore()
+	vroid := newBlockingVRoid(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	q := NewGenerationQueue(store, vroid, 3)
+	q.Start(ctx, 1)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		avatar, err := q.Enqueue(ctx, generationRequest)
+		if err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+		ids = append(ids, avatar.ID)
+	}
+	<-vroid.started
+	waitForStatus(t, store, ids[0], model.StatusGenerating)
+
+	// The job being generated and those still queued all end in error
+	cancel()
+	q.Wait()
+	for _, id := range ids {
+		if avatar, _ := store.GetByID(context.Background(), id); avatar.Status != model.StatusError {
+			t.Fatalf("avatar %s status = %q, want %s", id, avatar.Status, model.StatusError)
+		}
+	}
+
+	if _, err := q.Enqueue(context.Background(), generationRequest); !errors.Is(err, ErrQueueStopped) {
+		t.Fatalf("expected ErrQueueStopped, got %v", err)
+	}
+}
+
+func TestGenerationQueue_Recover(t *testing.T) {
+	store := newFakeStore()
+	for _, status := range []string{model.StatusPending, model.StatusGenerating, model.StatusReady} {
+		if err := store.Create(context.Background(), &model.Avatar{Style: "anime", Status: status}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	q := NewGenerationQueue(store, newBlockingVRoid(nil), 1)
+
+	if err := q.Recover(context.Background()); err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	want := map[string]string{"avatar-1": model.StatusError, "avatar-2": model.StatusError, "avatar-3": model.StatusReady}
+	for id, status := range want {
+		if avatar, _ := store.GetByID(context.Background(), id); avatar.Status != status {
+			t.Fatalf("avatar %s status = %q, want %s", id, avatar.Status, status)
+		}
+	}
+}
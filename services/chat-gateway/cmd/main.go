@@ -8,54 +8,58 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/grpcclient"
 	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
 	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/deadline"
 	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/server"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	// Configuration (consider using a config file/library like Viper or envconfig)
-	grpcPort := os.Getenv("GRPC_PORT")
-	if grpcPort == "" {
-		grpcPort = "8082" // Default gRPC port for chat-gateway
-	}
-	grpcAddr := fmt.Sprintf(":%s", grpcPort)
-
-	llmServiceAddr := os.Getenv("LLM_SERVICE_ADDR")
-	if llmServiceAddr == "" {
-		llmServiceAddr = "llm-gateway-py:50054" // Default address for llm-gateway (service name in Docker)
+	cfg, err := config.LoadConfig("./configs/config.yaml")
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
 	}
+	grpcAddr := fmt.Sprintf(":%d", cfg.Server.GRPCPort)
 
 	log.Printf("Starting Chat Gateway gRPC server on %s", grpcAddr)
-	log.Printf("Connecting to LLM Service at %s", llmServiceAddr)
+	log.Printf("Connecting to LLM Service at %s", cfg.LLM.ServiceAddr)
 
 	lis, err := net.Listen("tcp", grpcAddr)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	// Create gRPC server
-	grpcServer := grpc.NewServer(
-	// Add interceptors if needed (logging, metrics, auth propagation)
-	// grpc.StreamInterceptor(...),
-	)
+	// Create gRPC server; calls that arrive without a deadline get a default
+	grpcServer := grpc.NewServer(append(server.ServerOptions(cfg.Server),
+		grpc.UnaryInterceptor(deadline.UnaryServerInterceptor(cfg.Deadlines.ServerUnary)),
+		grpc.StreamInterceptor(deadline.StreamServerInterceptor(cfg.Deadlines.ServerStream)),
+	)...)
+
+	// Outgoing calls made without a deadline get a default too, and accept
+	// responses as large as the backends may send
+	clientOptions := []grpc.DialOption{
+		grpc.WithUnaryInterceptor(deadline.UnaryClientInterceptor(cfg.Deadlines.ClientUnary)),
+		grpc.WithStreamInterceptor(deadline.StreamClientInterceptor(cfg.Deadlines.ClientStream)),
+		grpcclient.MaxRecvMsgSize(cfg.GRPCClient.MaxRecvMsgSize),
+	}
+
+	// Spread calls over the replicas of each backend
+	balancing := grpcclient.Balancing{Scheme: cfg.GRPCClient.ResolverScheme, Policy: cfg.GRPCClient.LoadBalancingPolicy}
 
 	// Create LLM gRPC client
-	llmClient, err := client.NewLLMClient(llmServiceAddr)
+	llmClient, err := client.NewLLMClient(cfg.LLM.ServiceAddr, balancing, clientOptions...)
 	if err != nil {
 		log.Fatalf("Failed to create LLM client: %v", err)
 	}
 	defer llmClient.Close() // Ensure connection is closed on shutdown
 
-	// Create ILO gRPC client connection (reuse llmServiceAddr for now, or use env var ILO_SERVICE_ADDR)
-	iloServiceAddr := os.Getenv("ILO_SERVICE_ADDR")
-	if iloServiceAddr == "" {
-		iloServiceAddr = "auth-core:9091"
-	}
-	connIlo, err := grpc.NewClient(iloServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// Create ILO gRPC client connection
+	connIlo, err := balancing.Dial(cfg.Ilo.ServiceAddr, clientOptions...)
 	if err != nil {
 		log.Fatalf("Failed to connect to ILO service: %v", err)
 	}
@@ -63,8 +67,17 @@ func main() {
 
 	iloClient := client.NewIloClient(connIlo)
 
+	// Conversations are shared through Redis when replicas must see each
+	// other's
+	var redisClient *redis.Client
+	if cfg.Chat.History.Backend == "redis" {
+		redisClient = redis.NewClient(&redis.Options{Addr: cfg.Chat.History.RedisAddr})
+		defer redisClient.Close()
+		log.Printf("Keeping conversations in Redis at %s", cfg.Chat.History.RedisAddr)
+	}
+
 	// Create and register Chat service implementation
-	chatSvc := server.NewChatServer(llmClient, iloClient)
+	chatSvc := server.NewChatServer(llmClient, iloClient, cfg, redisClient)
 	// Use the correct registration function based on the generated code
 	pbChat.RegisterConversationServiceServer(grpcServer, chatSvc)
 	log.Println("ConversationService registered")
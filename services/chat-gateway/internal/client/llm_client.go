@@ -4,8 +4,8 @@ import (
 	"log"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure" // Use insecure for local development
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/grpcclient"
 	pbllm "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
 )
 
@@ -15,15 +15,13 @@ type LLMClient struct {
 	conn       *grpc.ClientConn // Keep a reference to close it later
 }
 
-// NewLLMClient creates a new gRPC client for the LLM service.
-func NewLLMClient(llmServiceAddr string) (*LLMClient, error) {
+// NewLLMClient creates a new gRPC client for the LLM service, spreading
+// calls over its replicas as balancing says. opts are added to the
+// connection's options, e.g. interceptors.
+func NewLLMClient(llmServiceAddr string, balancing grpcclient.Balancing, opts ...grpc.DialOption) (*LLMClient, error) {
 	log.Printf("Attempting to connect to LLM gRPC service at %s", llmServiceAddr)
 	// Establish gRPC connection (use insecure credentials for local dev)
-	// Add options for retry, timeout, etc. in production
-	conn, err := grpc.NewClient(
-		llmServiceAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := balancing.Dial(llmServiceAddr, opts...)
 	if err != nil {
 		log.Printf("Failed to connect to LLM service at %s: %v", llmServiceAddr, err)
 		return nil, err
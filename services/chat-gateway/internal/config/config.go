@@ -0,0 +1,424 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Server ServerConfig `mapstructure:"server"`
+	LLM    LLMConfig    `mapstructure:"llm"`
+	Ilo    IloConfig    `mapstructure:"ilo"`
+	Retry  RetryConfig  `mapstructure:"retry"`
+	RAG    RAGConfig    `mapstructure:"rag"`
+	Chat   ChatConfig   `mapstructure:"chat"`
+
+	Deadlines DeadlineConfig `mapstructure:"deadlines"`
+
+	Moderation ModerationConfig `mapstructure:"moderation"`
+
+	GRPCClient GRPCClientConfig `mapstructure:"grpc_client"`
+}
+
+type ServerConfig struct {
+	GRPCPort int `mapstructure:"grpc_port"`
+	// MaxRecvMsgSize and MaxSendMsgSize bound single gRPC messages, in
+	// bytes; a larger message fails its call with ResourceExhausted
+	MaxRecvMsgSize int             `mapstructure:"max_recv_msg_size"`
+	MaxSendMsgSize int             `mapstructure:"max_send_msg_size"`
+	Keepalive      KeepaliveConfig `mapstructure:"keepalive"`
+}
+
+// KeepaliveConfig keeps long chat streams' connections alive and clears
+// dead ones. The server pings a connection idle for Time and closes it
+// when the ping is not answered within Timeout. Clients may ping at most
+// every MinPingInterval, and between calls only with PermitWithoutStream;
+// a client pinging more often is disconnected.
+type KeepaliveConfig struct {
+	Time                time.Duration `mapstructure:"time"`
+	Timeout             time.Duration `mapstructure:"timeout"`
+	MinPingInterval     time.Duration `mapstructure:"min_ping_interval"`
+	PermitWithoutStream bool          `mapstructure:"permit_without_stream"`
+}
+
+type LLMConfig struct {
+	ServiceAddr string `mapstructure:"service_addr"`
+	// Timeout bounds each generation when AdaptiveTimeout is off
+	Timeout         time.Duration         `mapstructure:"timeout"`
+	AdaptiveTimeout AdaptiveTimeoutConfig `mapstructure:"adaptive_timeout"`
+}
+
+// AdaptiveTimeoutConfig scales the generation timeout with the estimated
+// prompt size: Base plus PerThousandTokens for every 1000 tokens, clamped
+// to [Min, Max].
+type AdaptiveTimeoutConfig struct {
+	Enabled           bool          `mapstructure:"enabled"`
+	Base              time.Duration `mapstructure:"base"`
+	PerThousandTokens time.Duration `mapstructure:"per_1k_tokens"`
+	Min               time.Duration `mapstructure:"min"`
+	Max               time.Duration `mapstructure:"max"`
+}
+
+type IloConfig struct {
+	ServiceAddr string `mapstructure:"service_addr"`
+	// Timeout bounds each fetch of a user's latest result for the chat
+	// prompt; a failed fetch is retried once, after about RetryBackoff
+	Timeout      time.Duration `mapstructure:"timeout"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	// CacheTTL is how long a user's latest result is reused by later chat
+	// turns; zero fetches it for every message
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// CacheSize bounds the users whose results are cached; beyond it the
+	// least recently used are dropped
+	CacheSize int `mapstructure:"cache_size"`
+}
+
+type RetryConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+}
+
+type RAGConfig struct {
+	Collection string `mapstructure:"collection"`
+	// Collections, when set, are all queried for every message instead of
+	// Collection alone
+	Collections []string `mapstructure:"collections"`
+	Adaptive    bool     `mapstructure:"adaptive"`
+	// ScaffoldingPrefixes are prompt labels stripped when the model echoes
+	// them at the start of an answer
+	ScaffoldingPrefixes []string `mapstructure:"scaffolding_prefixes"`
+}
+
+type ChatConfig struct {
+	// DefaultLanguage is the response language ("vi" or "en") used when
+	// neither the message nor the user's profile indicates one
+	DefaultLanguage string `mapstructure:"default_language"`
+	// RegenerateTemperature is the sampling temperature for regenerated
+	// answers, a little above the LLM default so they come out different
+	RegenerateTemperature float32 `mapstructure:"regenerate_temperature"`
+	// MaxContinuations caps how many times an answer cut off at the token
+	// limit may be continued; 0 disables continuing
+	MaxContinuations int `mapstructure:"max_continuations"`
+	// SendTimeout bounds each message sent to api-gateway. A send that
+	// takes longer means api-gateway stopped reading, and the stream is
+	// ended as if it had disconnected; 0 lets sends block indefinitely
+	SendTimeout time.Duration `mapstructure:"send_timeout"`
+	// ConversationLockWait is how long a message waits for the one being
+	// answered in the same conversation, e.g. sent from another tab, before
+	// it is refused; 0 refuses it at once
+	ConversationLockWait time.Duration `mapstructure:"conversation_lock_wait"`
+	// MessageRate throttles bursts of messages
+	MessageRate MessageRateConfig `mapstructure:"message_rate"`
+	// Suggestions are follow-up questions offered after each answer
+	Suggestions SuggestionsConfig `mapstructure:"suggestions"`
+	// Context is the earlier conversation sent along with each message
+	Context ContextConfig `mapstructure:"context"`
+	// History is where conversations are kept
+	History HistoryConfig `mapstructure:"history"`
+}
+
+// HistoryConfig is where conversation histories, the per-conversation
+// locks and the message limits are kept. Backend "memory" keeps them per
+// instance, which only suits a single replica; "redis" shares them
+// through the Redis at RedisAddr, so any replica can serve any message.
+// A conversation keeps its MaxMessages latest messages, and is dropped
+// once nothing was recorded in it for TTL.
+type HistoryConfig struct {
+	Backend     string        `mapstructure:"backend"`
+	RedisAddr   string        `mapstructure:"redis_addr"`
+	MaxMessages int           `mapstructure:"max_messages"`
+	TTL         time.Duration `mapstructure:"ttl"`
+}
+
+// SuggestionsConfig controls the follow-up questions generated by
+// llm-gateway's light model after an answer completes.
+type SuggestionsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Count is how many questions are offered at most
+	Count int `mapstructure:"count"`
+	// Timeout bounds generating them; on expiry the answer goes without
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// ContextConfig controls the earlier turns of a conversation sent to the
+// model with each message. The RecentMessages latest go verbatim; older
+// ones are compressed by llm-gateway's light model into the conversation's
+// summary, which goes in their place. They are compressed RecentMessages
+// at a time, and a compression taking longer than SummaryTimeout is tried
+// again with the next message. RecentMessages of 0 sends no earlier turns.
+type ContextConfig struct {
+	RecentMessages int           `mapstructure:"recent_messages"`
+	SummaryTimeout time.Duration `mapstructure:"summary_timeout"`
+}
+
+// MessageRateConfig limits how often messages, each of which may start a
+// RAG answer, are sent. A conversation may have ConversationBurst messages
+// sent at once and then one every ConversationInterval, and a user
+// UserBurst across their conversations and then one every UserInterval. A
+// message beyond either limit is refused with a retry hint. An interval of
+// 0 turns its limit off.
+type MessageRateConfig struct {
+	ConversationBurst    int           `mapstructure:"conversation_burst"`
+	ConversationInterval time.Duration `mapstructure:"conversation_interval"`
+	UserBurst            int           `mapstructure:"user_burst"`
+	UserInterval         time.Duration `mapstructure:"user_interval"`
+}
+
+// DeadlineConfig sets the default deadlines given to gRPC calls that have
+// none, for calls served and calls made. Zero leaves such calls unbounded.
+type DeadlineConfig struct {
+	ServerUnary time.Duration `mapstructure:"server_unary"`
+	// ServerStream is off by default: a chat stream lasts as long as the
+	// user's WebSocket session
+	ServerStream time.Duration `mapstructure:"server_stream"`
+	ClientUnary  time.Duration `mapstructure:"client_unary"`
+	ClientStream time.Duration `mapstructure:"client_stream"`
+}
+
+type ModerationConfig struct {
+	// Enabled turns on the pre-check that refuses flagged messages before
+	// they reach the model
+	Enabled bool `mapstructure:"enabled"`
+	// Categories maps each moderation category to the phrases that flag it.
+	// There are no defaults: the file lists them so a category can be dropped
+	Categories map[string][]string `mapstructure:"categories"`
+}
+
+// GRPCClientConfig is how the LLM and ILO clients find and pick replicas.
+type GRPCClientConfig struct {
+	// ResolverScheme resolves the service addresses: "dns" finds every
+	// replica behind a headless service, "passthrough" hands the address to
+	// the dialer as is
+	ResolverScheme string `mapstructure:"resolver_scheme"`
+	// LoadBalancingPolicy picks a replica per call: "round_robin" spreads
+	// calls, "pick_first" sticks to one
+	LoadBalancingPolicy string `mapstructure:"load_balancing_policy"`
+	// MaxRecvMsgSize is the largest response accepted from a backend, in
+	// bytes, in line with llm-gateway's grpc_max_send_message_bytes
+	MaxRecvMsgSize int `mapstructure:"max_recv_msg_size"`
+}
+
+// maxSuggestions is the most follow-up questions llm-gateway generates at
+// once.
+const maxSuggestions = 5
+
+// legacyEnv maps config keys to the environment variables the service read
+// before it had a config file, so existing deployments keep working.
+var legacyEnv = map[string]string{
+	"server.grpc_port": "GRPC_PORT",
+	"llm.service_addr": "LLM_SERVICE_ADDR",
+	"ilo.service_addr": "ILO_SERVICE_ADDR",
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("server.grpc_port", 8082)
+	v.SetDefault("server.max_recv_msg_size", 16<<20)
+	v.SetDefault("server.max_send_msg_size", 16<<20)
+	v.SetDefault("server.keepalive.time", 60*time.Second)
+	v.SetDefault("server.keepalive.timeout", 20*time.Second)
+	v.SetDefault("server.keepalive.min_ping_interval", 10*time.Second)
+	v.SetDefault("server.keepalive.permit_without_stream", true)
+	v.SetDefault("llm.service_addr", "llm-gateway-py:50054")
+	v.SetDefault("llm.timeout", 60*time.Second)
+	v.SetDefault("llm.adaptive_timeout.enabled", false)
+	v.SetDefault("llm.adaptive_timeout.base", 20*time.Second)
+	v.SetDefault("llm.adaptive_timeout.per_1k_tokens", 15*time.Second)
+	v.SetDefault("llm.adaptive_timeout.min", 20*time.Second)
+	v.SetDefault("llm.adaptive_timeout.max", 180*time.Second)
+	v.SetDefault("ilo.service_addr", "auth-core:9091")
+	v.SetDefault("ilo.timeout", 2*time.Second)
+	v.SetDefault("ilo.retry_backoff", 100*time.Millisecond)
+	v.SetDefault("ilo.cache_ttl", 2*time.Minute)
+	v.SetDefault("ilo.cache_size", 10000)
+	v.SetDefault("retry.max_attempts", 3)
+	v.SetDefault("retry.initial_backoff", 200*time.Millisecond)
+	v.SetDefault("retry.max_backoff", 2*time.Second)
+	v.SetDefault("rag.collection", "university-scores")
+	v.SetDefault("rag.adaptive", true)
+	v.SetDefault("rag.scaffolding_prefixes", []string{
+		"Answer:",
+		"Trả lời:",
+		"Context from retrieved sources:",
+		"Thông tin tham khảo:",
+	})
+	v.SetDefault("chat.default_language", "vi")
+	v.SetDefault("chat.regenerate_temperature", 0.9)
+	v.SetDefault("chat.max_continuations", 3)
+	v.SetDefault("chat.send_timeout", "10s")
+	v.SetDefault("chat.conversation_lock_wait", "30s")
+	v.SetDefault("chat.message_rate.conversation_burst", 5)
+	v.SetDefault("chat.message_rate.conversation_interval", "3s")
+	v.SetDefault("chat.message_rate.user_burst", 20)
+	v.SetDefault("chat.message_rate.user_interval", "1s")
+	v.SetDefault("chat.suggestions.enabled", true)
+	v.SetDefault("chat.suggestions.count", 3)
+	v.SetDefault("chat.suggestions.timeout", "5s")
+	v.SetDefault("chat.context.recent_messages", 6)
+	v.SetDefault("chat.context.summary_timeout", "5s")
+	v.SetDefault("chat.history.backend", "memory")
+	v.SetDefault("chat.history.redis_addr", "redis:6379")
+	v.SetDefault("chat.history.max_messages", 500)
+	v.SetDefault("chat.history.ttl", "720h")
+	v.SetDefault("moderation.enabled", false)
+	v.SetDefault("deadlines.server_unary", 30*time.Second)
+	v.SetDefault("deadlines.server_stream", 0)
+	v.SetDefault("deadlines.client_unary", 30*time.Second)
+	v.SetDefault("deadlines.client_stream", 5*time.Minute)
+	v.SetDefault("grpc_client.resolver_scheme", "dns")
+	v.SetDefault("grpc_client.load_balancing_policy", "round_robin")
+	v.SetDefault("grpc_client.max_recv_msg_size", 16<<20)
+}
+
+// LoadConfig reads the YAML file at path and applies environment overrides.
+// Env vars take precedence over the file: a key like llm.timeout is
+// overridden by LLM_TIMEOUT.
+func LoadConfig(path string) (*Config, error) {
+	v := viper.New()
+	setDefaults(v)
+
+	v.SetConfigFile(path)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	for key, env := range legacyEnv {
+		if err := v.BindEnv(key, strings.ToUpper(strings.ReplaceAll(key, ".", "_")), env); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// Validate reports every invalid field at once so a bad deployment fails on
+// startup with the full list.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.GRPCPort <= 0 || c.Server.GRPCPort > 65535 {
+		errs = append(errs, fmt.Errorf("server.grpc_port must be between 1 and 65535, got %d", c.Server.GRPCPort))
+	}
+	if c.Server.MaxRecvMsgSize <= 0 || c.Server.MaxSendMsgSize <= 0 {
+		errs = append(errs, errors.New("server.max_recv_msg_size and server.max_send_msg_size must be positive"))
+	}
+	if k := c.Server.Keepalive; k.Time <= 0 || k.Timeout <= 0 || k.MinPingInterval <= 0 {
+		errs = append(errs, errors.New("server.keepalive.time, timeout and min_ping_interval must be positive"))
+	}
+	if c.LLM.ServiceAddr == "" {
+		errs = append(errs, errors.New("llm.service_addr is required"))
+	}
+	if c.LLM.Timeout <= 0 {
+		errs = append(errs, errors.New("llm.timeout must be positive"))
+	}
+	if t := c.LLM.AdaptiveTimeout; t.Enabled {
+		if t.Base < 0 || t.PerThousandTokens < 0 {
+			errs = append(errs, errors.New("llm.adaptive_timeout.base and per_1k_tokens must not be negative"))
+		}
+		if t.Min <= 0 || t.Max < t.Min {
+			errs = append(errs, errors.New("llm.adaptive_timeout.max must be >= llm.adaptive_timeout.min > 0"))
+		}
+	}
+	if c.Ilo.ServiceAddr == "" {
+		errs = append(errs, errors.New("ilo.service_addr is required"))
+	}
+	if c.Ilo.Timeout <= 0 {
+		errs = append(errs, errors.New("ilo.timeout must be positive"))
+	}
+	if c.Ilo.RetryBackoff < 0 || c.Ilo.CacheTTL < 0 {
+		errs = append(errs, errors.New("ilo.retry_backoff and ilo.cache_ttl must not be negative"))
+	}
+	if c.Ilo.CacheTTL > 0 && c.Ilo.CacheSize < 1 {
+		errs = append(errs, errors.New("ilo.cache_size must be at least 1 when ilo.cache_ttl is set"))
+	}
+	if c.Retry.MaxAttempts < 1 {
+		errs = append(errs, errors.New("retry.max_attempts must be at least 1"))
+	}
+	if c.Retry.InitialBackoff < 0 || c.Retry.MaxBackoff < c.Retry.InitialBackoff {
+		errs = append(errs, errors.New("retry.max_backoff must be >= retry.initial_backoff >= 0"))
+	}
+	if c.RAG.Collection == "" {
+		errs = append(errs, errors.New("rag.collection is required"))
+	}
+	if c.Chat.DefaultLanguage != "vi" && c.Chat.DefaultLanguage != "en" {
+		errs = append(errs, fmt.Errorf("chat.default_language must be \"vi\" or \"en\", got %q", c.Chat.DefaultLanguage))
+	}
+	if c.Chat.RegenerateTemperature < 0 || c.Chat.RegenerateTemperature > 2 {
+		errs = append(errs, fmt.Errorf("chat.regenerate_temperature must be between 0 and 2, got %g", c.Chat.RegenerateTemperature))
+	}
+	if c.Chat.MaxContinuations < 0 {
+		errs = append(errs, fmt.Errorf("chat.max_continuations must not be negative, got %d", c.Chat.MaxContinuations))
+	}
+	if c.Chat.SendTimeout < 0 {
+		errs = append(errs, fmt.Errorf("chat.send_timeout must not be negative, got %s", c.Chat.SendTimeout))
+	}
+	if c.Chat.ConversationLockWait < 0 {
+		errs = append(errs, fmt.Errorf("chat.conversation_lock_wait must not be negative, got %s", c.Chat.ConversationLockWait))
+	}
+	if r := c.Chat.MessageRate; r.ConversationInterval < 0 || r.UserInterval < 0 {
+		errs = append(errs, errors.New("chat.message_rate.conversation_interval and user_interval must not be negative"))
+	}
+	if r := c.Chat.MessageRate; (r.ConversationInterval > 0 && r.ConversationBurst < 1) || (r.UserInterval > 0 && r.UserBurst < 1) {
+		errs = append(errs, errors.New("chat.message_rate.conversation_burst and user_burst must be at least 1 when their limit is on"))
+	}
+	if c.Chat.Suggestions.Enabled {
+		if c.Chat.Suggestions.Count < 1 || c.Chat.Suggestions.Count > maxSuggestions {
+			errs = append(errs, fmt.Errorf("chat.suggestions.count must be between 1 and %d, got %d", maxSuggestions, c.Chat.Suggestions.Count))
+		}
+		if c.Chat.Suggestions.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("chat.suggestions.timeout must be positive, got %s", c.Chat.Suggestions.Timeout))
+		}
+	}
+	if c.Chat.Context.RecentMessages < 0 {
+		errs = append(errs, fmt.Errorf("chat.context.recent_messages must not be negative, got %d", c.Chat.Context.RecentMessages))
+	}
+	if c.Chat.Context.RecentMessages > 0 && c.Chat.Context.SummaryTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("chat.context.summary_timeout must be positive, got %s", c.Chat.Context.SummaryTimeout))
+	}
+	if b := c.Chat.History.Backend; b != "memory" && b != "redis" {
+		errs = append(errs, fmt.Errorf("chat.history.backend must be \"memory\" or \"redis\", got %q", b))
+	}
+	if c.Chat.History.Backend == "redis" && c.Chat.History.RedisAddr == "" {
+		errs = append(errs, errors.New("chat.history.redis_addr is required with the redis backend"))
+	}
+	if c.Chat.History.MaxMessages < 1 || c.Chat.History.TTL <= 0 {
+		errs = append(errs, errors.New("chat.history.max_messages and chat.history.ttl must be positive"))
+	}
+	if d := c.Deadlines; d.ServerUnary < 0 || d.ServerStream < 0 || d.ClientUnary < 0 || d.ClientStream < 0 {
+		errs = append(errs, errors.New("deadlines must not be negative"))
+	}
+	if s := c.GRPCClient.ResolverScheme; s != "dns" && s != "passthrough" {
+		errs = append(errs, fmt.Errorf("grpc_client.resolver_scheme must be \"dns\" or \"passthrough\", got %q", s))
+	}
+	if p := c.GRPCClient.LoadBalancingPolicy; p != "round_robin" && p != "pick_first" {
+		errs = append(errs, fmt.Errorf("grpc_client.load_balancing_policy must be \"round_robin\" or \"pick_first\", got %q", p))
+	}
+	if c.GRPCClient.MaxRecvMsgSize <= 0 {
+		errs = append(errs, errors.New("grpc_client.max_recv_msg_size must be positive"))
+	}
+	if c.Moderation.Enabled {
+		if len(c.Moderation.Categories) == 0 {
+			errs = append(errs, errors.New("moderation.categories must not be empty when moderation is enabled"))
+		}
+		for category, phrases := range c.Moderation.Categories {
+			if len(phrases) == 0 {
+				errs = append(errs, fmt.Errorf("moderation.categories.%s has no phrases", category))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+const testConfig = `
+server:
+  grpc_port: 9000
+llm:
+  service_addr: "llm-from-file:50054"
+  timeout: 30s
+ilo:
+  service_addr: "ilo-from-file:9091"
+rag:
+  collection: "file-collection"
+`
+
+func TestLoadConfig_FileValues(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, 9000, cfg.Server.GRPCPort)
+	assert.Equal(t, "llm-from-file:50054", cfg.LLM.ServiceAddr)
+	assert.Equal(t, 30*time.Second, cfg.LLM.Timeout)
+	assert.Equal(t, "file-collection", cfg.RAG.Collection)
+	// Keys missing from the file fall back to defaults
+	assert.Equal(t, 2*time.Second, cfg.Ilo.Timeout)
+	assert.Equal(t, 2*time.Minute, cfg.Ilo.CacheTTL)
+	assert.Equal(t, 3, cfg.Retry.MaxAttempts)
+	assert.Equal(t, "vi", cfg.Chat.DefaultLanguage)
+	assert.True(t, cfg.Chat.Suggestions.Enabled)
+	assert.Equal(t, 3, cfg.Chat.Suggestions.Count)
+	assert.Equal(t, 30*time.Second, cfg.Chat.ConversationLockWait)
+	assert.Equal(t, ContextConfig{RecentMessages: 6, SummaryTimeout: 5 * time.Second}, cfg.Chat.Context)
+	assert.Equal(t, MessageRateConfig{
+		ConversationBurst: 5, ConversationInterval: 3 * time.Second, UserBurst: 20, UserInterval: time.Second,
+	}, cfg.Chat.MessageRate)
+	assert.Equal(t, HistoryConfig{
+		Backend: "memory", RedisAddr: "redis:6379", MaxMessages: 500, TTL: 720 * time.Hour,
+	}, cfg.Chat.History)
+	assert.Equal(t, 30*time.Second, cfg.Deadlines.ServerUnary)
+	assert.Zero(t, cfg.Deadlines.ServerStream)
+}
+
+func TestLoadConfig_EnvOverridesFile(t *testing.T) {
+	t.Setenv("LLM_TIMEOUT", "90s")
+	t.Setenv("RAG_COLLECTION", "env-collection")
+	t.Setenv("RETRY_MAX_ATTEMPTS", "5")
+
+	cfg, err := LoadConfig(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, 90*time.Second, cfg.LLM.Timeout)
+	assert.Equal(t, "env-collection", cfg.RAG.Collection)
+	assert.Equal(t, 5, cfg.Retry.MaxAttempts)
+}
+
+func TestLoadConfig_LegacyEnvNames(t *testing.T) {
+	t.Setenv("GRPC_PORT", "9100")
+	t.Setenv("LLM_SERVICE_ADDR", "llm-from-env:50054")
+	t.Setenv("ILO_SERVICE_ADDR", "ilo-from-env:9091")
+
+	cfg, err := LoadConfig(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, 9100, cfg.Server.GRPCPort)
+	assert.Equal(t, "llm-from-env:50054", cfg.LLM.ServiceAddr)
+	assert.Equal(t, "ilo-from-env:9091", cfg.Ilo.ServiceAddr)
+}
+
+func TestLoadConfig_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "invalid port",
+			content: "server:\n  grpc_port: 70000\n",
+			wantErr: "server.grpc_port",
+		},
+		{
+			name:    "zero message size limit",
+			content: "server:\n  max_recv_msg_size: 0\n",
+			wantErr: "server.max_recv_msg_size",
+		},
+		{
+			name:    "zero keepalive time",
+			content: "server:\n  keepalive:\n    time: 0s\n",
+			wantErr: "server.keepalive.time",
+		},
+		{
+			name:    "empty llm address",
+			content: "llm:\n  service_addr: \"\"\n",
+			wantErr: "llm.service_addr is required",
+		},
+		{
+			name:    "zero attempts",
+			content: "retry:\n  max_attempts: 0\n",
+			wantErr: "retry.max_attempts",
+		},
+		{
+			name:    "backoff out of order",
+			content: "retry:\n  initial_backoff: 5s\n  max_backoff: 1s\n",
+			wantErr: "retry.max_backoff",
+		},
+		{
+			name:    "adaptive timeout bounds out of order",
+			content: "llm:\n  adaptive_timeout:\n    enabled: true\n    min: 2m\n    max: 30s\n",
+			wantErr: "llm.adaptive_timeout.max",
+		},
+		{
+			name:    "unsupported default language",
+			content: "chat:\n  default_language: \"fr\"\n",
+			wantErr: "chat.default_language",
+		},
+		{
+			name:    "negative continuation limit",
+			content: "chat:\n  max_continuations: -1\n",
+			wantErr: "chat.max_continuations",
+		},
+		{
+			name:    "negative send timeout",
+			content: "chat:\n  send_timeout: -1s\n",
+			wantErr: "chat.send_timeout",
+		},
+		{
+			name:    "negative conversation lock wait",
+			content: "chat:\n  conversation_lock_wait: -1s\n",
+			wantErr: "chat.conversation_lock_wait",
+		},
+		{
+			name:    "negative message interval",
+			content: "chat:\n  message_rate:\n    user_interval: -1s\n",
+			wantErr: "chat.message_rate.conversation_interval and user_interval",
+		},
+		{
+			name:    "no message burst",
+			content: "chat:\n  message_rate:\n    conversation_burst: 0\n",
+			wantErr: "chat.message_rate.conversation_burst and user_burst",
+		},
+		{
+			name:    "too many suggestions",
+			content: "chat:\n  suggestions:\n    count: 6\n",
+			wantErr: "chat.suggestions.count",
+		},
+		{
+			name:    "negative recent messages",
+			content: "chat:\n  context:\n    recent_messages: -1\n",
+			wantErr: "chat.context.recent_messages",
+		},
+		{
+			name:    "no summary timeout",
+			content: "chat:\n  context:\n    summary_timeout: 0s\n",
+			wantErr: "chat.context.summary_timeout",
+		},
+		{
+			name:    "unbounded ILO cache",
+			content: "ilo:\n  cache_size: 0\n",
+			wantErr: "ilo.cache_size",
+		},
+		{
+			name:    "unknown history backend",
+			content: "chat:\n  history:\n    backend: \"mongo\"\n",
+			wantErr: "chat.history.backend",
+		},
+		{
+			name:    "redis history without an address",
+			content: "chat:\n  history:\n    backend: \"redis\"\n    redis_addr: \"\"\n",
+			wantErr: "chat.history.redis_addr",
+		},
+		{
+			name:    "unbounded history",
+			content: "chat:\n  history:\n    max_messages: 0\n",
+			wantErr: "chat.history.max_messages and chat.history.ttl",
+		},
+		{
+			name:    "negative deadline",
+			content: "deadlines:\n  client_unary: -1s\n",
+			wantErr: "deadlines must not be negative",
+		},
+		{
+			name:    "unknown resolver scheme",
+			content: "grpc_client:\n  resolver_scheme: \"xds\"\n",
+			wantErr: "grpc_client.resolver_scheme",
+		},
+		{
+			name:    "unknown load balancing policy",
+			content: "grpc_client:\n  load_balancing_policy: \"least_request\"\n",
+			wantErr: "grpc_client.load_balancing_policy",
+		},
+		{
+			name:    "zero max receive size",
+			content: "grpc_client:\n  max_recv_msg_size: 0\n",
+			wantErr: "grpc_client.max_recv_msg_size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_ReportsEveryProblem(t *testing.T) {
+	_, err := LoadConfig(writeConfig(t, "llm:\n  service_addr: \"\"\nilo:\n  service_addr: \"\"\nrag:\n  collection: \"\"\n"))
+	require.Error(t, err)
+	for _, want := range []string{"llm.service_addr is required", "ilo.service_addr is required", "rag.collection is required"} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadConfig_MissingFile(t *testing.T) {
+	_, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestLoadConfig_Moderation(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, testConfig+`
+moderation:
+  enabled: true
+  categories:
+    self_harm: ["tự tử", "suicide"]
+`))
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Moderation.Enabled)
+	assert.Equal(t, map[string][]string{"self_harm": {"tự tử", "suicide"}}, cfg.Moderation.Categories)
+
+	_, err = LoadConfig(writeConfig(t, "moderation:\n  enabled: true\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "moderation.categories")
+}
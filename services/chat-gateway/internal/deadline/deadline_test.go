@@ -0,0 +1,120 @@
+package deadline
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// remaining returns how long until ctx's deadline, failing without one.
+func remaining(t *testing.T, ctx context.Context) time.Duration {
+	t.Helper()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok, "context has no deadline")
+	return time.Until(deadline)
+}
+
+// withTimeout returns a context with a deadline d from now.
+func withTimeout(t *testing.T, d time.Duration) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	intercept := UnaryServerInterceptor(30 * time.Second)
+	call := func(ctx context.Context) time.Duration {
+		var left time.Duration
+		_, err := intercept(ctx, "req", &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+			left = remaining(t, ctx)
+			return nil, nil
+		})
+		require.NoError(t, err)
+		return left
+	}
+
+	t.Run("default applied without a deadline", func(t *testing.T) {
+		assert.InDelta(t, 30*time.Second, call(context.Background()), float64(time.Second))
+	})
+	t.Run("shorter deadline preserved", func(t *testing.T) {
+		assert.LessOrEqual(t, call(withTimeout(t, 2*time.Second)), 2*time.Second)
+	})
+	t.Run("longer deadline preserved", func(t *testing.T) {
+		assert.Greater(t, call(withTimeout(t, time.Minute)), 30*time.Second)
+	})
+}
+
+func TestUnaryServerInterceptor_ZeroDisables(t *testing.T) {
+	_, err := UnaryServerInterceptor(0)(context.Background(), "req", &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		return nil, nil
+	})
+	require.NoError(t, err)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeServerStream) Context() context.Context { return f.ctx }
+
+func TestStreamServerInterceptor(t *testing.T) {
+	intercept := StreamServerInterceptor(time.Minute)
+	call := func(ctx context.Context) time.Duration {
+		var left time.Duration
+		err := intercept(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(_ any, ss grpc.ServerStream) error {
+			left = remaining(t, ss.Context())
+			return nil
+		})
+		require.NoError(t, err)
+		return left
+	}
+
+	assert.InDelta(t, time.Minute, call(context.Background()), float64(time.Second))
+	assert.LessOrEqual(t, call(withTimeout(t, 2*time.Second)), 2*time.Second)
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	intercept := UnaryClientInterceptor(10 * time.Second)
+	call := func(ctx context.Context) time.Duration {
+		var left time.Duration
+		err := intercept(ctx, "/svc/Method", "req", nil, nil, func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			left = remaining(t, ctx)
+			return nil
+		})
+		require.NoError(t, err)
+		return left
+	}
+
+	assert.InDelta(t, 10*time.Second, call(context.Background()), float64(time.Second))
+	assert.LessOrEqual(t, call(withTimeout(t, 2*time.Second)), 2*time.Second)
+}
+
+type fakeClientStream struct {
+	grpc.ClientStream
+	ctx context.Context
+}
+
+func (f *fakeClientStream) RecvMsg(any) error { return io.EOF }
+
+func TestStreamClientInterceptor(t *testing.T) {
+	var stream *fakeClientStream
+	cs, err := StreamClientInterceptor(5*time.Minute)(context.Background(), &grpc.StreamDesc{}, nil, "/svc/Stream",
+		func(ctx context.Context, _ *grpc.StreamDesc, _ *grpc.ClientConn, _ string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+			stream = &fakeClientStream{ctx: ctx}
+			return stream, nil
+		})
+	require.NoError(t, err)
+	assert.InDelta(t, 5*time.Minute, remaining(t, stream.ctx), float64(time.Second))
+
+	// The default deadline's context is released when the stream ends
+	assert.Equal(t, io.EOF, cs.RecvMsg(nil))
+	assert.ErrorIs(t, stream.ctx.Err(), context.Canceled)
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	pbllm "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+)
+
+// summarySchema is llm-gateway's structured output schema for conversation
+// summaries.
+const summarySchema = "conversation_summary"
+
+// summaryMaxChars bounds a conversation's summary, as asked of the model
+// and enforced on what it returns.
+const summaryMaxChars = 1000
+
+// contextMessageMaxBytes bounds each earlier message sent verbatim; long
+// answers are cut short.
+const contextMessageMaxBytes = 2000
+
+// summaryPrompts ask for the summary of a conversation in each response
+// language, given the length limit, the previous summary and the messages
+// to add to it.
+var summaryPrompts = map[string]string{
+	langVietnamese: "Tóm tắt cuộc trò chuyện tư vấn hướng nghiệp dưới đây bằng tiếng Việt, tối đa %d ký tự. Giữ lại những gì học sinh cho biết về bản thân (sở thích, điểm số, ngành và trường quan tâm), những gì đã hỏi và đã được trả lời.\n\nTóm tắt trước đó:\n%s\n\nCác tin nhắn tiếp theo:\n%s",
+	langEnglish:    "Summarize the career-guidance conversation below in English, in at most %d characters. Keep what the student shared about themselves (interests, grades, majors and universities of interest), what they asked and what they were told.\n\nPrevious summary:\n%s\n\nLater messages:\n%s",
+}
+
+// contextLabels are the headings of the earlier turns in prompts, and the
+// names of the roles, in each response language.
+var contextLabels = map[string]struct {
+	summary, recent, question string
+	roles                     map[string]string
+}{
+	langVietnamese: {
+		summary:  "Tóm tắt cuộc trò chuyện trước đó:",
+		recent:   "Các tin nhắn gần đây:",
+		question: "Câu hỏi hiện tại:",
+		roles:    map[string]string{roleUser: "Học sinh", roleAssistant: "Trợ lý"},
+	},
+	langEnglish: {
+		summary:  "Summary of the conversation so far:",
+		recent:   "Recent messages:",
+		question: "Current question:",
+		roles:    map[string]string{roleUser: "Student", roleAssistant: "Assistant"},
+	},
+}
+
+// formatMessages renders messages one per line, each after its role.
+func formatMessages(lang string, messages []*pbChat.ConversationMessage) string {
+	labels, ok := contextLabels[lang]
+	if !ok {
+		labels = contextLabels[langEnglish]
+	}
+	var sb strings.Builder
+	for _, msg := range messages {
+		role, ok := labels.roles[msg.GetRole()]
+		if !ok {
+			role = msg.GetRole()
+		}
+		text := truncateText(strings.Join(strings.Fields(msg.GetText()), " "), contextMessageMaxBytes)
+		fmt.Fprintf(&sb, "%s: %s\n", role, text)
+	}
+	return sb.String()
+}
+
+// formatContext renders the earlier turns of a conversation to go before
+// the latest message in its prompt, or "" when there are none.
+func formatContext(lang, summary string, messages []*pbChat.ConversationMessage) string {
+	if summary == "" && len(messages) == 0 {
+		return ""
+	}
+	labels, ok := contextLabels[lang]
+	if !ok {
+		labels = contextLabels[langEnglish]
+	}
+	var sb strings.Builder
+	if summary != "" {
+		fmt.Fprintf(&sb, "%s\n%s\n\n", labels.summary, summary)
+	}
+	if len(messages) > 0 {
+		fmt.Fprintf(&sb, "%s\n%s\n", labels.recent, formatMessages(lang, messages))
+	}
+	sb.WriteString(labels.question)
+	sb.WriteString(" ")
+	return sb.String()
+}
+
+// conversationContext returns the earlier turns of a conversation to send
+// with its latest user message: its summary and the recent messages since.
+// Once the messages the summary leaves out are twice the recent ones, the
+// older half is compressed into it by llm-gateway's light model. Earlier
+// turns are an extra: failures are logged, and a failed compression leaves
+// the summary as it was.
+func (s *ChatServer) conversationContext(ctx context.Context, convID, userID, lang string) string {
+	recent := s.cfg.Chat.Context.RecentMessages
+	if recent <= 0 || convID == "" {
+		return ""
+	}
+	turns, err := s.history.priorTurns(ctx, convID, userID)
+	if err != nil {
+		if !errors.Is(err, errConversationNotFound) {
+			log.Printf("Not sending earlier turns of conversation %s of %s: %v", convID, userID, err)
+		}
+		return ""
+	}
+
+	if len(turns.messages) >= 2*recent {
+		older := turns.messages[:len(turns.messages)-recent]
+		summary, err := s.summarize(ctx, userID, lang, turns.summary, older)
+		if err == nil {
+			err = s.history.setSummary(ctx, convID, userID, summary, turns.summarized, turns.summarized+len(older))
+		}
+		if err != nil {
+			log.Printf("Failed to summarize conversation %s of %s: %v", convID, userID, err)
+		} else {
+			turns.summary = summary
+			turns.messages = turns.messages[len(older):]
+		}
+	}
+	// Bounded while compressions fail
+	messages := turns.messages[max(len(turns.messages)-2*recent, 0):]
+	return formatContext(lang, turns.summary, messages)
+}
+
+// summarize asks llm-gateway's light model for summary extended with
+// messages.
+func (s *ChatServer) summarize(ctx context.Context, userID, lang, summary string, messages []*pbChat.ConversationMessage) (string, error) {
+	prompt, ok := summaryPrompts[lang]
+	if !ok {
+		prompt = summaryPrompts[langEnglish]
+	}
+	previous := summary
+	if previous == "" {
+		previous = "-"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Chat.Context.SummaryTimeout)
+	defer cancel()
+	res, err := s.llmClient.GetLLMServiceClient().GenerateStructured(ctx, &pbllm.GenerateStructuredRequest{
+		Prompt: fmt.Sprintf(prompt, summaryMaxChars, previous, formatMessages(lang, messages)),
+		UserId: userID,
+		Schema: summarySchema,
+		Light:  true,
+	})
+	if err != nil {
+		return "", err
+	}
+	var output struct {
+		Summary string `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(res.GetJson()), &output); err != nil {
+		return "", fmt.Errorf("decode summary: %w", err)
+	}
+	if output.Summary = strings.TrimSpace(output.Summary); output.Summary == "" {
+		return "", errors.New("empty summary")
+	}
+	return truncateText(output.Summary, summaryMaxChars), nil
+}
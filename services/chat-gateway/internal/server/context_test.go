@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	pbllm "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// summarizingLLMServer answers like fakeLLMServer and numbers the summaries
+// it is asked for.
+type summarizingLLMServer struct {
+	*fakeLLMServer
+	mu        sync.Mutex
+	summaries []*pbllm.GenerateStructuredRequest
+}
+
+func (f *summarizingLLMServer) GenerateStructured(_ context.Context, req *pbllm.GenerateStructuredRequest) (*pbllm.GenerateStructuredResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.summaries = append(f.summaries, req)
+	return &pbllm.GenerateStructuredResponse{Json: fmt.Sprintf(`{"summary":"Summary %d"}`, len(f.summaries))}, nil
+}
+
+func enableContext(s *ChatServer, recent int) {
+	s.cfg.Chat.Context = config.ContextConfig{RecentMessages: recent, SummaryTimeout: 5 * time.Second}
+}
+
+func TestStream_SendsEarlierTurns(t *testing.T) {
+	llmServer := &summarizingLLMServer{fakeLLMServer: &fakeLLMServer{}}
+	s := newTestChatServer(t, llmServer)
+	enableContext(s, 1)
+
+	stream := newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Which careers suit me?"},
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "What about scholarships?"},
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "And dormitories?"},
+	)
+	require.NoError(t, s.Stream(stream))
+	require.Len(t, llmServer.requests, 3)
+
+	assert.Equal(t, "Please respond in English. Which careers suit me?", llmServer.requests[0].GetPrompt(), "nothing precedes the first message")
+
+	// The first question is compressed, its answer is recent
+	require.Len(t, llmServer.summaries, 2)
+	first := llmServer.summaries[0]
+	assert.Equal(t, summarySchema, first.GetSchema())
+	assert.True(t, first.GetLight(), "summaries are generated by the light model")
+	assert.Contains(t, first.GetPrompt(), "Student: Which careers suit me?")
+	assert.NotContains(t, first.GetPrompt(), "Answer 1")
+	assert.Equal(t, "Please respond in English. Summary of the conversation so far:\nSummary 1\n\n"+
+		"Recent messages:\nAssistant: Answer 1\n\nCurrent question: What about scholarships?", llmServer.requests[1].GetPrompt())
+
+	// The previous summary is extended with what it left out
+	second := llmServer.summaries[1]
+	assert.Contains(t, second.GetPrompt(), "Previous summary:\nSummary 1")
+	assert.Contains(t, second.GetPrompt(), "Assistant: Answer 1\nStudent: What about scholarships?")
+	assert.Contains(t, llmServer.requests[2].GetPrompt(), "Summary 2\n\nRecent messages:\nAssistant: Answer 2\n\nCurrent question: And dormitories?")
+
+	summary, err := s.GetConversationSummary(userContext("user-1"), &pbChat.GetConversationSummaryRequest{ConversationId: "conv-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "Summary 2", summary.GetSummary())
+}
+
+func TestStream_EarlierTurnsWithoutSummaries(t *testing.T) {
+	// fakeLLMServer does not implement GenerateStructured
+	llmServer := &fakeLLMServer{}
+	s := newTestChatServer(t, llmServer)
+	enableContext(s, 1)
+
+	var reqs []*pbChat.StreamRequest
+	for i := range 3 {
+		reqs = append(reqs, &pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: fmt.Sprintf("Question %d?", i+1)})
+	}
+	require.NoError(t, s.Stream(newUserStream(reqs...)))
+
+	require.Len(t, llmServer.requests, 3)
+	assert.Equal(t, "Please respond in English. Recent messages:\nStudent: Question 2?\nAssistant: Answer 2\n\n"+
+		"Current question: Question 3?", llmServer.requests[2].GetPrompt(), "only the latest turns while summaries fail")
+}
+
+func TestStream_RegenerateLeavesOutTheAnswerItReplaces(t *testing.T) {
+	llmServer := &summarizingLLMServer{fakeLLMServer: &fakeLLMServer{}}
+	s := newTestChatServer(t, llmServer)
+	enableContext(s, 2)
+
+	stream := newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Which careers suit me?"},
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "What about scholarships?"},
+		&pbChat.StreamRequest{Type: msgTypeRegenerate, ConversationId: "conv-1"},
+	)
+	require.NoError(t, s.Stream(stream))
+
+	require.Len(t, llmServer.requests, 3)
+	assert.Equal(t, llmServer.requests[1].GetPrompt(), llmServer.requests[2].GetPrompt())
+	assert.NotContains(t, llmServer.requests[2].GetPrompt(), "Answer 2")
+}
+
+func TestPriorTurns_FollowTrimmedMessages(t *testing.T) {
+	h := newConversationHistory(newMemoryConversationStore(0), 4)
+	ctx := context.Background()
+	for i := range 3 {
+		require.NoError(t, h.record(ctx, "conv-1", "user-1", roleUser, fmt.Sprintf("q%d", i), nil))
+		require.NoError(t, h.record(ctx, "conv-1", "user-1", roleAssistant, fmt.Sprintf("a%d", i), nil))
+		if i == 1 {
+			require.NoError(t, h.setSummary(ctx, "conv-1", "user-1", "q0 and a0", 0, 2))
+		}
+	}
+	require.NoError(t, h.record(ctx, "conv-1", "user-1", roleUser, "q3", nil))
+
+	turns, err := h.priorTurns(ctx, "conv-1", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "q0 and a0", turns.summary, "it still stands in for the trimmed messages")
+	var texts []string
+	for _, msg := range turns.messages {
+		texts = append(texts, msg.GetText())
+	}
+	assert.Equal(t, []string{"a1", "q2", "a2"}, texts)
+	assert.Zero(t, turns.summarized)
+
+	// A stale summary is not saved
+	require.NoError(t, h.setSummary(ctx, "conv-1", "user-1", "stale", 2, 3))
+	turns, err = h.priorTurns(ctx, "conv-1", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "q0 and a0", turns.summary)
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// errConversationBusy means another message in the conversation was still
+// being answered when the wait for it ran out.
+var errConversationBusy = errors.New("conversation is busy answering another message")
+
+// conversationLocker lets one message at a time be answered in each
+// conversation, so sends racing on the same conversation from several
+// streams cannot interleave its history.
+type conversationLocker interface {
+	// acquire locks the conversation, waiting up to wait for the message
+	// being answered in it; a wait of 0 does not wait at all. It fails with
+	// errConversationBusy when the wait runs out, or with ctx's error.
+	// Messages without a conversation are not recorded and need no lock.
+	// The returned release must be called once the answer is recorded.
+	acquire(ctx context.Context, convID string, wait time.Duration) (release func(), err error)
+}
+
+// conversationLocks is a conversationLocker covering this instance only.
+type conversationLocks struct {
+	mu    sync.Mutex
+	locks map[string]*conversationLock
+}
+
+// conversationLock is held while its channel holds a value. users counts the
+// holder and waiters, so the lock is dropped once nobody needs it.
+type conversationLock struct {
+	held  chan struct{}
+	users int
+}
+
+func newConversationLocks() *conversationLocks {
+	return &conversationLocks{locks: make(map[string]*conversationLock)}
+}
+
+func (l *conversationLocks) acquire(ctx context.Context, convID string, wait time.Duration) (release func(), err error) {
+	if convID == "" {
+		return func() {}, nil
+	}
+	l.mu.Lock()
+	lock, ok := l.locks[convID]
+	if !ok {
+		lock = &conversationLock{held: make(chan struct{}, 1)}
+		l.locks[convID] = lock
+	}
+	lock.users++
+	l.mu.Unlock()
+
+	release = func() {
+		<-lock.held
+		l.done(convID, lock)
+	}
+	select {
+	case lock.held <- struct{}{}:
+		return release, nil
+	default:
+	}
+	if wait <= 0 {
+		l.done(convID, lock)
+		return nil, errConversationBusy
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case lock.held <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		err = errConversationBusy
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	l.done(convID, lock)
+	return nil, err
+}
+
+// done drops a user of the conversation's lock, and the lock with its last.
+func (l *conversationLocks) done(convID string, lock *conversationLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock.users--
+	if lock.users == 0 {
+		delete(l.locks, convID)
+	}
+}
+
+// Locks in Redis are leases renewed while held, so the lock of a replica
+// that died is freed after lockLease. Waiters poll every lockPollInterval.
+const (
+	lockLease        = 30 * time.Second
+	lockPollInterval = 100 * time.Millisecond
+)
+
+// Scripts that renew and release a lock only while the token still holds
+// it, so a lease that ran out and was taken by another is left alone.
+var (
+	renewLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// redisConversationLocks is a conversationLocker shared by every replica.
+type redisConversationLocks struct {
+	client *redis.Client
+}
+
+func newRedisConversationLocks(client *redis.Client) *redisConversationLocks {
+	return &redisConversationLocks{client: client}
+}
+
+func (l *redisConversationLocks) acquire(ctx context.Context, convID string, wait time.Duration) (release func(), err error) {
+	if convID == "" {
+		return func() {}, nil
+	}
+	key := "chat:conversation_lock:" + convID
+	token, err := lockToken()
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(wait)
+	for {
+		locked, err := l.client.SetNX(ctx, key, token, lockLease).Result()
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			break
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, errConversationBusy
+		}
+		timer := time.NewTimer(min(lockPollInterval, remaining))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	// Renewed until released, even once the stream has ended, as the
+	// answer is still recorded then
+	renewCtx, stopRenewing := context.WithCancel(context.WithoutCancel(ctx))
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		ticker := time.NewTicker(lockLease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				err := renewLockScript.Run(renewCtx, l.client, []string{key}, token, lockLease.Milliseconds()).Err()
+				if err != nil && renewCtx.Err() == nil {
+					log.Printf("Failed to renew the lock of conversation %s: %v", convID, err)
+				}
+			}
+		}
+	}()
+	return func() {
+		stopRenewing()
+		<-renewed
+		if err := releaseLockScript.Run(context.WithoutCancel(ctx), l.client, []string{key}, token).Err(); err != nil {
+			log.Printf("Failed to release the lock of conversation %s: %v", convID, err)
+		}
+	}, nil
+}
+
+// lockToken returns a random token identifying one holder of a lock.
+func lockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,258 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/careerup-Inc/careerup-monorepo/pkg/chaterr"
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	pbllm "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationLocks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("second acquire waits for release", func(t *testing.T) {
+		l := newConversationLocks()
+		release, err := l.acquire(ctx, "conv-1", 0)
+		require.NoError(t, err)
+
+		acquired := make(chan func())
+		go func() {
+			second, err := l.acquire(ctx, "conv-1", time.Minute)
+			assert.NoError(t, err)
+			acquired <- second
+		}()
+		select {
+		case <-acquired:
+			t.Fatal("acquired a held lock")
+		case <-time.After(50 * time.Millisecond):
+		}
+		release()
+		second := <-acquired
+		second()
+		assert.Empty(t, l.locks, "unused locks are dropped")
+	})
+
+	t.Run("busy after the wait", func(t *testing.T) {
+		l := newConversationLocks()
+		release, err := l.acquire(ctx, "conv-1", 0)
+		require.NoError(t, err)
+		defer release()
+
+		_, err = l.acquire(ctx, "conv-1", 0)
+		assert.ErrorIs(t, err, errConversationBusy)
+		_, err = l.acquire(ctx, "conv-1", 20*time.Millisecond)
+		assert.ErrorIs(t, err, errConversationBusy)
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = l.acquire(cancelled, "conv-1", time.Minute)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, l.locks["conv-1"].users, "failed acquires leave only the holder")
+	})
+
+	t.Run("conversations are locked separately", func(t *testing.T) {
+		l := newConversationLocks()
+		first, err := l.acquire(ctx, "conv-1", 0)
+		require.NoError(t, err)
+		defer first()
+		second, err := l.acquire(ctx, "conv-2", 0)
+		require.NoError(t, err)
+		defer second()
+
+		// Messages without a conversation are never locked
+		for range 2 {
+			release, err := l.acquire(ctx, "", 0)
+			require.NoError(t, err)
+			defer release()
+		}
+	})
+}
+
+func TestRedisConversationLocks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("second acquire waits for release", func(t *testing.T) {
+		l := newRedisConversationLocks(newTestRedis(t))
+		release, err := l.acquire(ctx, "conv-1", 0)
+		require.NoError(t, err)
+
+		acquired := make(chan func())
+		go func() {
+			second, err := l.acquire(ctx, "conv-1", time.Minute)
+			assert.NoError(t, err)
+			acquired <- second
+		}()
+		select {
+		case <-acquired:
+			t.Fatal("acquired a held lock")
+		case <-time.After(3 * lockPollInterval):
+		}
+		release()
+		second := <-acquired
+		second()
+	})
+
+	t.Run("busy after the wait", func(t *testing.T) {
+		// Another replica holds the lock
+		client := newTestRedis(t)
+		release, err := newRedisConversationLocks(client).acquire(ctx, "conv-1", 0)
+		require.NoError(t, err)
+		defer release()
+
+		l := newRedisConversationLocks(client)
+		_, err = l.acquire(ctx, "conv-1", 0)
+		assert.ErrorIs(t, err, errConversationBusy)
+		_, err = l.acquire(ctx, "conv-1", 2*lockPollInterval)
+		assert.ErrorIs(t, err, errConversationBusy)
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = l.acquire(cancelled, "conv-1", time.Minute)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("lease of a dead holder runs out", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+		l := newRedisConversationLocks(client)
+
+		// Taken as a replica that died would have, with nothing renewing it
+		require.NoError(t, client.SetNX(ctx, "chat:conversation_lock:conv-1", "dead", lockLease).Err())
+		_, err := l.acquire(ctx, "conv-1", 0)
+		assert.ErrorIs(t, err, errConversationBusy)
+
+		mr.FastForward(lockLease)
+		release, err := l.acquire(ctx, "conv-1", 0)
+		require.NoError(t, err)
+		release()
+		assert.False(t, mr.Exists("chat:conversation_lock:conv-1"))
+	})
+
+	t.Run("release leaves another holder's lock", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+		release, err := newRedisConversationLocks(client).acquire(ctx, "conv-1", 0)
+		require.NoError(t, err)
+
+		// The lease ran out and another replica took the lock
+		mr.Set("chat:conversation_lock:conv-1", "other")
+		release()
+		got, err := mr.Get("chat:conversation_lock:conv-1")
+		require.NoError(t, err)
+		assert.Equal(t, "other", got)
+	})
+}
+
+// gatedLLMServer answers like fakeLLMServer, holding its first answer until
+// proceed is closed.
+type gatedLLMServer struct {
+	*fakeLLMServer
+	started chan struct{}
+	proceed chan struct{}
+	once    sync.Once
+}
+
+func newGatedLLMServer() *gatedLLMServer {
+	return &gatedLLMServer{fakeLLMServer: &fakeLLMServer{}, started: make(chan struct{}), proceed: make(chan struct{})}
+}
+
+func (f *gatedLLMServer) GenerateWithRAG(req *pbllm.GenerateWithRAGRequest, stream pbllm.LLMService_GenerateWithRAGServer) error {
+	first := false
+	f.once.Do(func() {
+		first = true
+		close(f.started)
+	})
+	if first {
+		<-f.proceed
+	}
+	return f.fakeLLMServer.GenerateWithRAG(req, stream)
+}
+
+// sendConcurrently sends "first" to conv-1 on one stream and, while it is
+// being answered, "second" on another, as two tabs of one user would. It
+// returns the second stream once both are done.
+func sendConcurrently(t *testing.T, s *ChatServer, llmServer *gatedLLMServer) *fakeChatStream {
+	t.Helper()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	first := newUserStream(&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "first"})
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, s.Stream(first))
+	}()
+	<-llmServer.started
+
+	second := newUserStream(&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "second"})
+	secondDone := make(chan struct{})
+	go func() {
+		defer wg.Done()
+		defer close(secondDone)
+		assert.NoError(t, s.Stream(second))
+	}()
+	// The second send waits for the lock, or has been refused
+	require.Eventually(t, func() bool {
+		select {
+		case <-secondDone:
+			return true
+		default:
+		}
+		locks := s.conversationLocks.(*conversationLocks)
+		locks.mu.Lock()
+		defer locks.mu.Unlock()
+		return locks.locks["conv-1"].users == 2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	close(llmServer.proceed)
+	wg.Wait()
+	return second
+}
+
+func historyTexts(t *testing.T, s *ChatServer, convID string) []string {
+	t.Helper()
+	res, err := s.history.get(context.Background(), convID, "user-1", historyQuery{})
+	require.NoError(t, err)
+	var texts []string
+	for _, msg := range res.GetMessages() {
+		texts = append(texts, msg.GetRole()+": "+msg.GetText())
+	}
+	return texts
+}
+
+func TestStream_ConcurrentSendsAreSerialized(t *testing.T) {
+	llmServer := newGatedLLMServer()
+	s := newTestChatServer(t, llmServer)
+	s.cfg.Chat.ConversationLockWait = 5 * time.Second
+
+	second := sendConcurrently(t, s, llmServer)
+
+	assert.Equal(t, []string{
+		"user: first", "assistant: Answer 1",
+		"user: second", "assistant: Answer 2",
+	}, historyTexts(t, s, "conv-1"), "each answer follows its own question")
+	require.NotEmpty(t, second.sent)
+	assert.Equal(t, "Answer 2", second.sent[len(second.sent)-1].GetToken())
+	assert.Empty(t, s.conversationLocks.(*conversationLocks).locks)
+}
+
+func TestStream_ConcurrentSendRefusedWithoutWait(t *testing.T) {
+	llmServer := newGatedLLMServer()
+	s := newTestChatServer(t, llmServer)
+
+	second := sendConcurrently(t, s, llmServer)
+
+	require.Len(t, second.sent, 1)
+	assert.Equal(t, "error", second.sent[0].GetType())
+	assert.Equal(t, string(chaterr.ConversationBusy), second.sent[0].GetErrorCode())
+	assert.Equal(t, []string{"user: first", "assistant: Answer 1"}, historyTexts(t, s, "conv-1"))
+	assert.Len(t, llmServer.requests, 1)
+}
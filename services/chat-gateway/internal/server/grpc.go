@@ -5,16 +5,30 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"strings"
-	"time"
+	"math"
 
+	"github.com/careerup-Inc/careerup-monorepo/pkg/chaterr"
+	"github.com/careerup-Inc/careerup-monorepo/pkg/ilo"
 	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
 	pbllm "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+)
+
+// Client message types.
+const (
+	msgTypeUser = "user_msg"
+	// msgTypeRegenerate asks for a fresh answer to the conversation's last
+	// user message, replacing the previous answer
+	msgTypeRegenerate = "regenerate"
+	// msgTypeContinue asks for the rest of the conversation's last answer,
+	// which was cut off at the token limit
+	msgTypeContinue = "continue"
 )
 
 // ChatServer implements the ConversationService gRPC interface.
@@ -22,14 +36,36 @@ type ChatServer struct {
 	pbChat.UnimplementedConversationServiceServer                   // Embed the unimplemented server
 	llmClient                                     *client.LLMClient // Use the gRPC client wrapper
 	iloClient                                     *client.IloClient // ILO client for user context
+	moderator                                     moderator         // nil when moderation is disabled
+	history                                       *conversationHistory
+	conversationLocks                             conversationLocker
+	messageThrottle                               messageLimiter
+	iloResults                                    *iloResultCache
+	cfg                                           *config.Config
 }
 
-// NewChatServer creates a new chat server instance.
-func NewChatServer(llmClient *client.LLMClient, iloClient *client.IloClient) *ChatServer {
-	return &ChatServer{
-		llmClient: llmClient,
-		iloClient: iloClient,
+// NewChatServer creates a new chat server instance. redisClient holds the
+// conversations with the "redis" history backend, and is unused otherwise.
+func NewChatServer(llmClient *client.LLMClient, iloClient *client.IloClient, cfg *config.Config, redisClient *redis.Client) *ChatServer {
+	s := &ChatServer{
+		llmClient:  llmClient,
+		iloClient:  iloClient,
+		iloResults: newIloResultCache(cfg.Ilo.CacheTTL, cfg.Ilo.CacheSize),
+		cfg:        cfg,
+	}
+	if cfg.Chat.History.Backend == "redis" {
+		s.history = newConversationHistory(newRedisConversationStore(redisClient, cfg.Chat.History.TTL), cfg.Chat.History.MaxMessages)
+		s.conversationLocks = newRedisConversationLocks(redisClient)
+		s.messageThrottle = newRedisMessageThrottle(redisClient)
+	} else {
+		s.history = newConversationHistory(newMemoryConversationStore(cfg.Chat.History.TTL), cfg.Chat.History.MaxMessages)
+		s.conversationLocks = newConversationLocks()
+		s.messageThrottle = newMessageThrottle()
 	}
+	if cfg.Moderation.Enabled {
+		s.moderator = newKeywordModerator(cfg.Moderation.Categories)
+	}
+	return s
 }
 
 // Stream handles the bidirectional stream between api-gateway and chat-gateway.
@@ -44,14 +80,28 @@ func (s *ChatServer) Stream(stream pbChat.ConversationService_StreamServer) erro
 		userID = md.Get("user-id")[0]
 	}
 	log.Printf("User ID from metadata: %s", userID)
+	var userLanguage string
+	if ok && len(md.Get("user-language")) > 0 {
+		userLanguage = md.Get("user-language")[0]
+	}
+
+	// A message api-gateway doesn't take in time ends the stream as a
+	// disconnect would
+	sender := newStreamSender(ctx, stream.Send, s.cfg.Chat.SendTimeout)
+	defer sender.close()
+	send := sender.send
 
-	// Channel to signal when LLM processing for a message is done
-	llmDone := make(chan struct{}, 1) // Buffered channel to avoid blocking sender
+	// Closed when the goroutine below exits
+	recvDone := make(chan struct{})
+	// Follow-up questions to the last answer, sent in the background
+	var suggestions followUps
 
 	// Goroutine to handle receiving messages from the client (api-gateway)
 	// and triggering LLM calls.
 	go func() {
-		defer close(llmDone) // Ensure channel is closed when this goroutine exits
+		defer close(recvDone) // Ensure channel is closed when this goroutine exits
+		// A client done sending still gets the questions to its last answer
+		defer suggestions.wait()
 		for {
 			// Check if the client context is cancelled first
 			select {
@@ -77,126 +127,59 @@ func (s *ChatServer) Stream(stream pbChat.ConversationService_StreamServer) erro
 				}
 				return // Terminate this goroutine on error
 			}
+			// The questions to the previous answer are stale now
+			suggestions.drop()
 
-			// Validate message type (add more checks as needed)
-			if req.Type != "user_msg" || req.Text == "" {
-				log.Printf("Received invalid message type or empty text: Type=%s", req.Type)
+			// Bursts, e.g. from a client stuck resending, are refused before
+			// they start answers
+			wait, err := s.messageThrottle.allow(ctx, s.cfg.Chat.MessageRate, userID, req.ConversationId)
+			if err != nil {
+				// Like api-gateway's rate limiter, a failing store lets
+				// messages through rather than refusing them all
+				log.Printf("Message throttle unavailable, not throttling: %v", err)
+			}
+			if wait > 0 {
+				log.Printf("Throttling %s of user %s in conversation %s for %s", req.Type, userID, req.ConversationId, wait)
+				seconds := int32(math.Ceil(wait.Seconds()))
 				errMsg := &pbChat.StreamResponse{
-					Type:    "error",
-					Content: &pbChat.StreamResponse_ErrorMessage{ErrorMessage: "Invalid message format"},
+					Type:              "error",
+					Content:           &pbChat.StreamResponse_ErrorMessage{ErrorMessage: fmt.Sprintf("Too many messages; try again in %d seconds", seconds)},
+					ErrorCode:         string(chaterr.MessageRateLimited),
+					RetryAfterSeconds: seconds,
 				}
-				if sendErr := stream.Send(errMsg); sendErr != nil {
+				if sendErr := send(errMsg); sendErr != nil {
 					log.Printf("Failed to send error message back to api-gateway: %v", sendErr)
 					return // Assume connection is broken
 				}
-				continue // Wait for next valid message
-			}
-
-			log.Printf("Received user_msg from api-gateway: ConvID=%s", req.ConversationId)
-
-			// Fetch latest ILO test result for user (if available)
-			var iloContext string
-			if s.iloClient != nil && userID != "unknown" {
-				result, err := s.iloClient.GetLatestIloTestResult(ctx, userID)
-				if err != nil {
-					log.Printf("Failed to fetch ILO test result for user %s: %v", userID, err)
-				} else if result != nil {
-					// Compose a context string from ILO result (top domains, scores, suggestions)
-					iloContext = "User ILO profile: "
-					if len(result.TopDomains) > 0 {
-						iloContext += "Top domains: " + strings.Join(result.TopDomains, ", ") + ". "
-					}
-					if len(result.SuggestedCareers) > 0 {
-						iloContext += "Suggested careers: " + strings.Join(result.SuggestedCareers, ", ") + ". "
-					}
-					if len(result.Scores) > 0 {
-						scoreStrs := make([]string, 0, len(result.Scores))
-						for _, s := range result.Scores {
-							scoreStrs = append(scoreStrs, s.DomainCode+":"+fmt.Sprintf("%.0f%%", s.Percent))
-						}
-						iloContext += "Domain scores: " + strings.Join(scoreStrs, ", ") + ". "
-					}
-				}
-			}
-
-			// --- Trigger LLM Streaming Call with RAG ---
-			llmReq := &pbllm.GenerateWithRAGRequest{
-				Prompt:         iloContext + req.Text,
-				UserId:         userID,
-				ConversationId: req.ConversationId,
-				RagCollection:  "university-scores", // TODO Example collection, adjust as needed
-				Adaptive:       true,                  // Enable adaptive RAG
+				continue
 			}
 
-			llmCtx, llmCancel := context.WithTimeout(ctx, 60*time.Second)
-			log.Println("Calling LLMService.GenerateWithRAG...")
-			llmStream, err := s.llmClient.GetLLMServiceClient().GenerateWithRAG(llmCtx, llmReq)
+			// One generation at a time per conversation, so sends racing on
+			// the same conversation, e.g. from two tabs, cannot interleave its
+			// history: a send waits for the one before, and is refused if it
+			// waits too long
+			release, err := s.conversationLocks.acquire(ctx, req.ConversationId, s.cfg.Chat.ConversationLockWait)
 			if err != nil {
-				log.Printf("Failed to start LLM RAG stream: %v", err)
-				llmCancel()
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("Refusing %s in conversation %s: %v", req.Type, req.ConversationId, err)
 				errMsg := &pbChat.StreamResponse{
-					Type:    "error",
-					Content: &pbChat.StreamResponse_ErrorMessage{ErrorMessage: "Failed to connect to LLM RAG service"},
+					Type:      "error",
+					Content:   &pbChat.StreamResponse_ErrorMessage{ErrorMessage: "The previous message in this conversation is still being answered"},
+					ErrorCode: string(chaterr.ConversationBusy),
 				}
-				if sendErr := stream.Send(errMsg); sendErr != nil {
+				if sendErr := send(errMsg); sendErr != nil {
 					log.Printf("Failed to send error message back to api-gateway: %v", sendErr)
 					return // Assume connection is broken
 				}
 				continue
 			}
-
-			log.Println("LLM RAG stream started, receiving tokens...")
-			var llmReceiveErr error
-			for {
-				llmRes, err := llmStream.Recv()
-				if err == io.EOF {
-					log.Println("LLM RAG stream ended.")
-					break
-				}
-				if err != nil {
-					st, ok := status.FromError(err)
-					if ok && st.Code() == codes.Canceled {
-						log.Println("LLM RAG stream context cancelled.")
-					} else {
-						log.Printf("Error receiving from LLM RAG stream: %v", err)
-						llmReceiveErr = err
-					}
-					break
-				}
-				chatRes := &pbChat.StreamResponse{
-					Type:    "assistant_token",
-					Content: &pbChat.StreamResponse_Token{Token: llmRes.Token},
-				}
-				if err := stream.Send(chatRes); err != nil {
-					log.Printf("Error sending token to api-gateway stream: %v", err)
-					llmCancel()
-					return
-				}
+			reachable := s.handleMessage(ctx, send, &suggestions, userID, userLanguage, req)
+			release()
+			if !reachable {
+				return
 			}
-			llmCancel()
-			if llmReceiveErr != nil {
-				errMsg := &pbChat.StreamResponse{
-					Type:    "error",
-					Content: &pbChat.StreamResponse_ErrorMessage{ErrorMessage: "Error receiving response from LLM RAG"},
-				}
-				if sendErr := stream.Send(errMsg); sendErr != nil {
-					log.Printf("Failed to send LLM error message back to api-gateway: %v", sendErr)
-					return
-				}
-			}
-			// --- End LLM RAG Streaming Call ---
-
-			// TODO: Add Avatar Service call here if needed, send avatar_url message
-			// Example:
-			// avatarURL := getAvatarURL(req.ConversationId, ...) // Call avatar service
-			// avatarMsg := &pbChat.StreamResponse{
-			// 	Type: "avatar_url",
-			// 	Content: &pbChat.StreamResponse_Url{Url: avatarURL},
-			// }
-			// if err := stream.Send(avatarMsg); err != nil { ... }
-
-			// Signal that processing for this message is done (optional, might be useful for flow control)
-			// llmDone <- struct{}{}
 		}
 	}()
 
@@ -206,9 +189,298 @@ func (s *ChatServer) Stream(stream pbChat.ConversationService_StreamServer) erro
 	select {
 	case <-ctx.Done():
 		log.Printf("Chat stream context done (client disconnected): %v", ctx.Err())
-	case <-llmDone:
+	case <-recvDone:
 		log.Println("Chat stream processing goroutine finished.")
 	}
+	// The goroutine must not outlive the handler: gRPC forbids Send once
+	// it has returned. The cancelled context ends its Recv, LLM call or
+	// ILO lookup promptly.
+	<-recvDone
 
 	return ctx.Err() // Return the context error, if any
 }
+
+// handleMessage answers one client message, recording it and the answer in
+// the conversation's history. It reports false when api-gateway can no
+// longer be reached, which ends the stream.
+func (s *ChatServer) handleMessage(ctx context.Context, send func(*pbChat.StreamResponse) error, suggestions *followUps, userID, userLanguage string, req *pbChat.StreamRequest) bool {
+	// Validate message type (add more checks as needed)
+	text := req.Text
+	regenerate := req.Type == msgTypeRegenerate
+	continued := req.Type == msgTypeContinue
+	var partial string // the cut-off answer a continuation resumes
+	if continued {
+		// Resume the last answer, from the recorded history
+		question, answer, continuations, err := s.history.continuation(ctx, req.ConversationId, userID)
+		errMsg := &pbChat.StreamResponse{Type: "error"}
+		switch {
+		case err != nil:
+			log.Printf("Cannot continue in conversation %s: %v", req.ConversationId, err)
+			errMsg.Content = &pbChat.StreamResponse_ErrorMessage{ErrorMessage: "There is no cut-off response to continue"}
+			errMsg.ErrorCode = string(chaterr.NothingToContinue)
+		case continuations >= s.cfg.Chat.MaxContinuations:
+			log.Printf("Not continuing in conversation %s: already continued %d times", req.ConversationId, continuations)
+			errMsg.Content = &pbChat.StreamResponse_ErrorMessage{ErrorMessage: "This response cannot be continued any further"}
+			errMsg.ErrorCode = string(chaterr.ContinueLimitReached)
+		default:
+			errMsg = nil
+		}
+		if errMsg != nil {
+			if sendErr := send(errMsg); sendErr != nil {
+				log.Printf("Failed to send error message back to api-gateway: %v", sendErr)
+				return false // Assume connection is broken
+			}
+			return true
+		}
+		text, partial = question, answer
+	} else if regenerate {
+		// Answer the last user message again, from the recorded history
+		last, err := s.history.lastUserMessage(ctx, req.ConversationId, userID)
+		if err != nil {
+			log.Printf("Cannot regenerate in conversation %s: %v", req.ConversationId, err)
+			errMsg := &pbChat.StreamResponse{
+				Type:      "error",
+				Content:   &pbChat.StreamResponse_ErrorMessage{ErrorMessage: "There is no previous message to regenerate a response for"},
+				ErrorCode: string(chaterr.NothingToRegenerate),
+			}
+			if sendErr := send(errMsg); sendErr != nil {
+				log.Printf("Failed to send error message back to api-gateway: %v", sendErr)
+				return false // Assume connection is broken
+			}
+			return true
+		}
+		text = last
+	} else if req.Type != msgTypeUser || req.Text == "" {
+		log.Printf("Received invalid message type or empty text: Type=%s", req.Type)
+		errMsg := &pbChat.StreamResponse{
+			Type:      "error",
+			Content:   &pbChat.StreamResponse_ErrorMessage{ErrorMessage: "Invalid message format"},
+			ErrorCode: string(chaterr.InvalidMessage),
+		}
+		if sendErr := send(errMsg); sendErr != nil {
+			log.Printf("Failed to send error message back to api-gateway: %v", sendErr)
+			return false // Assume connection is broken
+		}
+		return true // Wait for next valid message
+	}
+
+	log.Printf("Received %s from api-gateway: ConvID=%s", req.Type, req.ConversationId)
+	kind := answerNew
+	switch {
+	case regenerate:
+		kind = answerRegenerated
+	case continued:
+		kind = answerContinued
+	default:
+		s.recordMessage(ctx, req.ConversationId, userID, roleUser, text)
+	}
+	recorder := &answerRecorder{send: send}
+
+	lang := resolveLanguage(text, userLanguage, s.cfg.Chat.DefaultLanguage)
+
+	// A continued answer's question was screened when first answered
+	if !continued {
+		refused, sendErr := s.screenMessage(ctx, userID, req.ConversationId, lang, text, recorder.Send)
+		if sendErr != nil {
+			log.Printf("Failed to send moderation refusal back to api-gateway: %v", sendErr)
+			return false
+		}
+		if refused {
+			s.recordAnswer(ctx, req.ConversationId, userID, recorder, kind)
+			return true
+		}
+	}
+
+	// Only fetched for messages that passed moderation
+	iloContext := s.iloContext(ctx, userID)
+	conversationContext := s.conversationContext(ctx, req.ConversationId, userID, lang)
+
+	// --- Trigger LLM Streaming Call with RAG ---
+	llmReq := &pbllm.GenerateWithRAGRequest{
+		Prompt:         buildPrompt(lang, iloContext, conversationContext, text),
+		UserId:         userID,
+		ConversationId: req.ConversationId,
+		RagCollection:  s.cfg.RAG.Collection,
+		RagCollections: s.cfg.RAG.Collections,
+		Adaptive:       s.cfg.RAG.Adaptive,
+		ContinueFrom:   partial,
+		Verbosity:      req.Verbosity,
+	}
+	if regenerate {
+		// A little more randomness so the new answer differs
+		temperature := s.cfg.Chat.RegenerateTemperature
+		llmReq.Params = &pbllm.GenerationParams{Temperature: &temperature}
+	}
+
+	llmCtx, llmCancel := context.WithTimeout(ctx, generationTimeout(s.cfg.LLM, llmReq.Prompt))
+	log.Println("Calling LLMService.GenerateWithRAG...")
+	llmStream, err := s.llmClient.GetLLMServiceClient().GenerateWithRAG(llmCtx, llmReq)
+	if err != nil {
+		log.Printf("Failed to start LLM RAG stream: %v", err)
+		llmCancel()
+		errMsg := &pbChat.StreamResponse{
+			Type:      "error",
+			Content:   &pbChat.StreamResponse_ErrorMessage{ErrorMessage: "Failed to connect to LLM RAG service"},
+			ErrorCode: string(chaterr.LLMUnavailable),
+		}
+		if sendErr := send(errMsg); sendErr != nil {
+			log.Printf("Failed to send error message back to api-gateway: %v", sendErr)
+			return false // Assume connection is broken
+		}
+		return true
+	}
+
+	log.Println("LLM RAG stream started, receiving tokens...")
+	stripper := newScaffoldStripper(s.cfg.RAG.ScaffoldingPrefixes)
+	llmReceiveErr, sendErr := relayLLMStream(llmStream, recorder.Send, recorder.setSources, stripper)
+	if sendErr != nil {
+		// Stuck or broken, api-gateway is treated as gone: the
+		// generation is cancelled and the stream ended
+		log.Printf("Error sending to api-gateway stream: %v", sendErr)
+		llmCancel()
+		return false
+	}
+	llmCancel()
+	s.recordAnswer(ctx, req.ConversationId, userID, recorder, kind)
+	if llmReceiveErr != nil {
+		errMsg := &pbChat.StreamResponse{
+			Type:      "error",
+			Content:   &pbChat.StreamResponse_ErrorMessage{ErrorMessage: "Error receiving response from LLM RAG"},
+			ErrorCode: string(chaterr.LLMError),
+		}
+		if llmBusy(llmReceiveErr, llmStream.Trailer()) {
+			errMsg.Content = &pbChat.StreamResponse_ErrorMessage{ErrorMessage: status.Convert(llmReceiveErr).Message()}
+			errMsg.ErrorCode = string(chaterr.LLMBusy)
+		}
+		if sendErr := send(errMsg); sendErr != nil {
+			log.Printf("Failed to send LLM error message back to api-gateway: %v", sendErr)
+			return false
+		}
+	} else if !recorder.truncated && s.cfg.Chat.Suggestions.Enabled {
+		// A continuation completes the answer it resumes
+		answer := partial + recorder.String()
+		suggestions.start(ctx, send, func(ctx context.Context) []string {
+			return s.suggestFollowUps(ctx, userID, lang, text, answer)
+		})
+	}
+	// --- End LLM RAG Streaming Call ---
+
+	// TODO: Add Avatar Service call here if needed, send avatar_url message
+	// Example:
+	// avatarURL := getAvatarURL(req.ConversationId, ...) // Call avatar service
+	// avatarMsg := &pbChat.StreamResponse{
+	// 	Type: "avatar_url",
+	// 	Content: &pbChat.StreamResponse_Url{Url: avatarURL},
+	// }
+	// if err := stream.Send(avatarMsg); err != nil { ... }
+	return true
+}
+
+// statusRestarting is sent by llm-gateway when it failed over to a fallback
+// model mid-answer; the tokens relayed so far are void and the answer starts
+// over.
+const statusRestarting = "restarting"
+
+// statusTruncated is sent after the last token of an answer cut off at the
+// token limit; a "continue" request resumes it.
+const statusTruncated = "truncated"
+
+// llmBusy reports whether err is llm-gateway giving up on rate limited
+// models. The user's quota running out is ResourceExhausted as well, but only
+// a rate limit comes with a retry-after trailer.
+func llmBusy(err error, trailer metadata.MD) bool {
+	return status.Code(err) == codes.ResourceExhausted && len(trailer.Get("retry-after")) > 0
+}
+
+// relayLLMStream forwards llm-gateway output to api-gateway until the LLM
+// stream ends: pipeline statuses as "status" messages (consecutive repeats
+// dropped) and answer tokens, with echoed scaffolding stripped, as
+// "assistant_token" messages. An answer cut off at the token limit is
+// followed by a "truncated" status. The answer's sources are passed to
+// onSources rather than forwarded. recvErr reports a failed LLM stream; sendErr means
+// api-gateway can no longer be reached.
+func relayLLMStream(llmStream pbllm.LLMService_GenerateWithRAGClient, send func(*pbChat.StreamResponse) error, onSources func([]*pbllm.Source), stripper *scaffoldStripper) (recvErr, sendErr error) {
+	sendToken := func(token string) error {
+		return send(&pbChat.StreamResponse{
+			Type:    "assistant_token",
+			Content: &pbChat.StreamResponse_Token{Token: token},
+		})
+	}
+
+	var lastStatus string
+	for {
+		llmRes, err := llmStream.Recv()
+		if err == io.EOF {
+			log.Println("LLM RAG stream ended.")
+			if rest := stripper.Flush(); rest != "" {
+				return nil, sendToken(rest)
+			}
+			return nil, nil
+		}
+		if err != nil {
+			st, ok := status.FromError(err)
+			if ok && st.Code() == codes.Canceled {
+				log.Println("LLM RAG stream context cancelled.")
+				return nil, nil
+			}
+			log.Printf("Error receiving from LLM RAG stream: %v", err)
+			return err, nil
+		}
+
+		if sources := llmRes.GetSources(); len(sources) > 0 && onSources != nil {
+			onSources(sources)
+		}
+		if llmRes.GetTruncated() {
+			if rest := stripper.Flush(); rest != "" {
+				if err := sendToken(rest); err != nil {
+					return nil, err
+				}
+			}
+			if err := send(&pbChat.StreamResponse{
+				Type:    "status",
+				Content: &pbChat.StreamResponse_Status{Status: statusTruncated},
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if stage := llmRes.GetStatus(); stage != "" {
+			if stage == statusRestarting {
+				// The new answer may echo scaffolding again
+				stripper.Reset()
+			} else if stage == lastStatus {
+				continue
+			}
+			lastStatus = stage
+			if err := send(&pbChat.StreamResponse{
+				Type:    "status",
+				Content: &pbChat.StreamResponse_Status{Status: stage},
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		token := stripper.Push(llmRes.GetToken())
+		if token == "" {
+			continue
+		}
+		if err := sendToken(token); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// iloScores converts proto domain scores to the shared ilo representation.
+func iloScores(scores []*pbChat.IloDomainScore) []ilo.DomainScore {
+	out := make([]ilo.DomainScore, 0, len(scores))
+	for _, s := range scores {
+		out = append(out, ilo.DomainScore{
+			DomainCode: s.GetDomainCode(),
+			Percent:    s.GetPercent(),
+			Level:      s.GetLevel(),
+			Rank:       s.GetRank(),
+		})
+	}
+	return out
+}
@@ -0,0 +1,569 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/pkg/chaterr"
+	"github.com/careerup-Inc/careerup-monorepo/pkg/grpcclient"
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	pbllm "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeLLMStream replays canned responses, then err (io.EOF by default).
+type fakeLLMStream struct {
+	grpc.ClientStream
+	responses []*pbllm.GenerateWithRAGResponse
+	err       error
+}
+
+func (f *fakeLLMStream) Recv() (*pbllm.GenerateWithRAGResponse, error) {
+	if len(f.responses) == 0 {
+		if f.err != nil {
+			return nil, f.err
+		}
+		return nil, io.EOF
+	}
+	res := f.responses[0]
+	f.responses = f.responses[1:]
+	return res, nil
+}
+
+func statusRes(stage string) *pbllm.GenerateWithRAGResponse {
+	return &pbllm.GenerateWithRAGResponse{Status: stage}
+}
+
+func tokenRes(token string) *pbllm.GenerateWithRAGResponse {
+	return &pbllm.GenerateWithRAGResponse{Token: token}
+}
+
+func relay(t *testing.T, llmStream *fakeLLMStream) ([]*pbChat.StreamResponse, error) {
+	t.Helper()
+	var sent []*pbChat.StreamResponse
+	recvErr, sendErr := relayLLMStream(llmStream, func(res *pbChat.StreamResponse) error {
+		sent = append(sent, res)
+		return nil
+	}, nil, newScaffoldStripper(nil))
+	require.NoError(t, sendErr)
+	return sent, recvErr
+}
+
+func TestRelayLLMStream_AdaptiveStatusSequence(t *testing.T) {
+	sent, err := relay(t, &fakeLLMStream{responses: []*pbllm.GenerateWithRAGResponse{
+		statusRes("retrieving"),
+		statusRes("searching_web"),
+		statusRes("generating"),
+		tokenRes("Hello"),
+		tokenRes(" world"),
+	}})
+	require.NoError(t, err)
+
+	var kinds, values []string
+	for _, res := range sent {
+		kinds = append(kinds, res.Type)
+		if res.Type == "status" {
+			values = append(values, res.GetStatus())
+		} else {
+			values = append(values, res.GetToken())
+		}
+	}
+	assert.Equal(t, []string{"status", "status", "status", "assistant_token", "assistant_token"}, kinds)
+	assert.Equal(t, []string{"retrieving", "searching_web", "generating", "Hello", " world"}, values)
+}
+
+func TestRelayLLMStream_DropsRepeatedStatus(t *testing.T) {
+	// Adaptive retries report "generating" once per attempt
+	sent, err := relay(t, &fakeLLMStream{responses: []*pbllm.GenerateWithRAGResponse{
+		statusRes("generating"),
+		statusRes("generating"),
+		tokenRes("ok"),
+	}})
+	require.NoError(t, err)
+	require.Len(t, sent, 2)
+	assert.Equal(t, "generating", sent[0].GetStatus())
+	assert.Equal(t, "ok", sent[1].GetToken())
+}
+
+func TestRelayLLMStream_RestartOnFallback(t *testing.T) {
+	var sent []*pbChat.StreamResponse
+	recvErr, sendErr := relayLLMStream(&fakeLLMStream{responses: []*pbllm.GenerateWithRAGResponse{
+		statusRes("generating"),
+		tokenRes("Answer: The ans"),
+		statusRes("restarting"),
+		tokenRes("Answer: The answer"),
+		statusRes("restarting"),
+		tokenRes("Again"),
+	}}, func(res *pbChat.StreamResponse) error {
+		sent = append(sent, res)
+		return nil
+	}, nil, newScaffoldStripper([]string{"Answer:"}))
+	require.NoError(t, recvErr)
+	require.NoError(t, sendErr)
+
+	var values []string
+	for _, res := range sent {
+		values = append(values, res.GetStatus()+res.GetToken())
+	}
+	// Every restart is forwarded and scaffolding is stripped from each answer
+	assert.Equal(t, []string{"generating", "The ans", "restarting", "The answer", "restarting", "Again"}, values)
+}
+
+func TestRelayLLMStream_ReceiveError(t *testing.T) {
+	boom := errors.New("boom")
+	sent, err := relay(t, &fakeLLMStream{
+		responses: []*pbllm.GenerateWithRAGResponse{statusRes("retrieving")},
+		err:       boom,
+	})
+	assert.Equal(t, boom, err)
+	assert.Len(t, sent, 1)
+}
+
+func TestRelayLLMStream_SendError(t *testing.T) {
+	broken := errors.New("api-gateway gone")
+	recvErr, sendErr := relayLLMStream(&fakeLLMStream{responses: []*pbllm.GenerateWithRAGResponse{
+		statusRes("retrieving"),
+	}}, func(*pbChat.StreamResponse) error { return broken }, nil, newScaffoldStripper(nil))
+	assert.NoError(t, recvErr)
+	assert.Equal(t, broken, sendErr)
+}
+
+func TestRelayLLMStream_PassesOnSources(t *testing.T) {
+	sources := []*pbllm.Source{{Type: "knowledge_base", Uri: "hust.pdf"}}
+	var got []*pbllm.Source
+	var sent []*pbChat.StreamResponse
+	recvErr, sendErr := relayLLMStream(&fakeLLMStream{responses: []*pbllm.GenerateWithRAGResponse{
+		{Status: "generating", Sources: sources},
+		tokenRes("ok"),
+	}}, func(res *pbChat.StreamResponse) error {
+		sent = append(sent, res)
+		return nil
+	}, func(s []*pbllm.Source) { got = s }, newScaffoldStripper(nil))
+	require.NoError(t, recvErr)
+	require.NoError(t, sendErr)
+
+	assert.Equal(t, sources, got)
+	require.Len(t, sent, 2)
+	assert.Equal(t, "generating", sent[0].GetStatus())
+}
+
+func TestRelayLLMStream_Truncated(t *testing.T) {
+	var sent []*pbChat.StreamResponse
+	recvErr, sendErr := relayLLMStream(&fakeLLMStream{responses: []*pbllm.GenerateWithRAGResponse{
+		tokenRes("Answ"),
+		{Truncated: true},
+	}}, func(res *pbChat.StreamResponse) error {
+		sent = append(sent, res)
+		return nil
+	}, nil, newScaffoldStripper([]string{"Answer:"}))
+	require.NoError(t, recvErr)
+	require.NoError(t, sendErr)
+
+	// The buffered text is sent before the status that ends the answer
+	require.Len(t, sent, 2)
+	assert.Equal(t, "Answ", sent[0].GetToken())
+	assert.Equal(t, statusTruncated, sent[1].GetStatus())
+}
+
+// fakeLLMServer answers each GenerateWithRAG call with "Answer N", drawn
+// from a knowledge base document and a web page, and records the requests.
+type fakeLLMServer struct {
+	pbllm.UnimplementedLLMServiceServer
+	mu       sync.Mutex
+	requests []*pbllm.GenerateWithRAGRequest
+}
+
+func (f *fakeLLMServer) GenerateWithRAG(req *pbllm.GenerateWithRAGRequest, stream pbllm.LLMService_GenerateWithRAGServer) error {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	n := len(f.requests)
+	f.mu.Unlock()
+	sources := []*pbllm.Source{
+		{Type: "knowledge_base", Uri: fmt.Sprintf("scores-%d.pdf", n), Collection: "university-scores"},
+		{Type: "web", Title: "Admissions", Uri: "https://example.edu.vn/admissions"},
+	}
+	if err := stream.Send(&pbllm.GenerateWithRAGResponse{Status: "generating", Sources: sources}); err != nil {
+		return err
+	}
+	return stream.Send(&pbllm.GenerateWithRAGResponse{Token: fmt.Sprintf("Answer %d", n)})
+}
+
+// truncatingLLMServer streams answer a word at a time, stopping at maxWords
+// words with the answer marked truncated. A continuation resumes answer
+// after the partial answer it is given.
+type truncatingLLMServer struct {
+	pbllm.UnimplementedLLMServiceServer
+	answer   string
+	maxWords int
+	mu       sync.Mutex
+	requests []*pbllm.GenerateWithRAGRequest
+}
+
+func (f *truncatingLLMServer) GenerateWithRAG(req *pbllm.GenerateWithRAGRequest, stream pbllm.LLMService_GenerateWithRAGServer) error {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+	rest := strings.TrimPrefix(f.answer, req.GetContinueFrom())
+	words := strings.SplitAfter(rest, " ")
+	truncated := len(words) > f.maxWords
+	if truncated {
+		words = words[:f.maxWords]
+	}
+	for _, word := range words {
+		if err := stream.Send(&pbllm.GenerateWithRAGResponse{Token: word}); err != nil {
+			return err
+		}
+	}
+	if truncated {
+		return stream.Send(&pbllm.GenerateWithRAGResponse{Truncated: true})
+	}
+	return nil
+}
+
+// busyLLMServer fails every answer the way llm-gateway does when all its
+// models are rate limited.
+type busyLLMServer struct {
+	pbllm.UnimplementedLLMServiceServer
+}
+
+func (busyLLMServer) GenerateWithRAG(req *pbllm.GenerateWithRAGRequest, stream pbllm.LLMService_GenerateWithRAGServer) error {
+	stream.SetTrailer(metadata.Pairs("retry-after", "2"))
+	return status.Error(codes.ResourceExhausted, "The assistant is in high demand right now. Please try again shortly.")
+}
+
+// fakeChatStream feeds queued requests to Stream and collects its responses.
+type fakeChatStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	reqs chan *pbChat.StreamRequest
+	mu   sync.Mutex
+	sent []*pbChat.StreamResponse
+}
+
+func (f *fakeChatStream) Context() context.Context { return f.ctx }
+
+// Recv returns the next queued request; like a real stream it fails once
+// the stream's context is done.
+func (f *fakeChatStream) Recv() (*pbChat.StreamRequest, error) {
+	select {
+	case req, ok := <-f.reqs:
+		if !ok {
+			return nil, io.EOF
+		}
+		return req, nil
+	case <-f.ctx.Done():
+		return nil, status.FromContextError(f.ctx.Err()).Err()
+	}
+}
+
+func (f *fakeChatStream) Send(res *pbChat.StreamResponse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, res)
+	return nil
+}
+
+// newTestChatServer returns a ChatServer backed by llmServer.
+func newTestChatServer(t *testing.T, llmServer pbllm.LLMServiceServer) *ChatServer {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	pbllm.RegisterLLMServiceServer(srv, llmServer)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	llmClient, err := client.NewLLMClient(lis.Addr().String(), grpcclient.Balancing{})
+	require.NoError(t, err)
+	t.Cleanup(func() { llmClient.Close() })
+
+	cfg := &config.Config{
+		LLM:  config.LLMConfig{Timeout: 5 * time.Second},
+		RAG:  config.RAGConfig{Collection: "university-scores"},
+		Chat: config.ChatConfig{DefaultLanguage: "en", RegenerateTemperature: 0.9, MaxContinuations: 2},
+	}
+	return NewChatServer(llmClient, nil, cfg, nil)
+}
+
+// newUserStream returns a stream for user-1 that sends reqs and then ends.
+func newUserStream(reqs ...*pbChat.StreamRequest) *fakeChatStream {
+	stream := &fakeChatStream{
+		ctx:  metadata.NewIncomingContext(context.Background(), metadata.Pairs("user-id", "user-1")),
+		reqs: make(chan *pbChat.StreamRequest, len(reqs)),
+	}
+	for _, req := range reqs {
+		stream.reqs <- req
+	}
+	close(stream.reqs)
+	return stream
+}
+
+func TestStream_Regenerate(t *testing.T) {
+	llmServer := &fakeLLMServer{}
+	s := newTestChatServer(t, llmServer)
+
+	// Nothing to regenerate yet, then a question and a regenerate
+	stream := newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeRegenerate, ConversationId: "conv-1"},
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Which careers suit me?"},
+		&pbChat.StreamRequest{Type: msgTypeRegenerate, ConversationId: "conv-1"},
+	)
+	require.NoError(t, s.Stream(stream))
+
+	require.NotEmpty(t, stream.sent)
+	assert.Equal(t, "error", stream.sent[0].GetType())
+	assert.Contains(t, stream.sent[0].GetErrorMessage(), "no previous message")
+	assert.Equal(t, string(chaterr.NothingToRegenerate), stream.sent[0].GetErrorCode())
+
+	require.Len(t, llmServer.requests, 2)
+	first, regenerated := llmServer.requests[0], llmServer.requests[1]
+	assert.Equal(t, first.GetPrompt(), regenerated.GetPrompt(), "regenerate reuses the last user message")
+	assert.Contains(t, regenerated.GetPrompt(), "Which careers suit me?")
+	assert.Nil(t, first.GetParams())
+	assert.Equal(t, float32(0.9), regenerated.GetParams().GetTemperature())
+
+	res, err := s.history.get(context.Background(), "conv-1", "user-1", historyQuery{})
+	require.NoError(t, err)
+	require.Len(t, res.GetMessages(), 2)
+	assert.Equal(t, "Which careers suit me?", res.GetMessages()[0].GetText())
+	assert.Equal(t, "Answer 2", res.GetMessages()[1].GetText(), "the regenerated answer replaces the first")
+	assert.Equal(t, "scores-2.pdf", res.GetMessages()[1].GetSources()[0].GetUri(), "and so do its sources")
+}
+
+// stuckChatStream is a stream whose api-gateway side stopped reading: sends
+// block until the stream ends.
+type stuckChatStream struct {
+	*fakeChatStream
+}
+
+func (s stuckChatStream) Send(*pbChat.StreamResponse) error {
+	<-s.ctx.Done()
+	return status.FromContextError(s.ctx.Err()).Err()
+}
+
+// endlessLLMServer streams tokens until the call is cancelled, then closes
+// cancelled.
+type endlessLLMServer struct {
+	pbllm.UnimplementedLLMServiceServer
+	cancelled chan struct{}
+}
+
+func (s *endlessLLMServer) GenerateWithRAG(req *pbllm.GenerateWithRAGRequest, stream pbllm.LLMService_GenerateWithRAGServer) error {
+	defer close(s.cancelled)
+	for {
+		if err := stream.Send(&pbllm.GenerateWithRAGResponse{Token: "word "}); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestStream_StuckSendCancelsGeneration(t *testing.T) {
+	llmServer := &endlessLLMServer{cancelled: make(chan struct{})}
+	s := newTestChatServer(t, llmServer)
+	s.cfg.Chat.SendTimeout = 50 * time.Millisecond
+
+	// The stream itself stays open: only the stuck send shows api-gateway is gone
+	ctx, cancel := context.WithCancel(userContext("user-1"))
+	defer cancel()
+	stream := &fakeChatStream{ctx: ctx, reqs: make(chan *pbChat.StreamRequest, 1)}
+	stream.reqs <- &pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Which careers suit me?"}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Stream(stuckChatStream{stream}) }()
+	select {
+	case <-llmServer.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the generation went on after api-gateway stopped reading")
+	}
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stream did not return after a send got stuck")
+	}
+}
+
+func TestStream_PassesOnVerbosity(t *testing.T) {
+	llmServer := &fakeLLMServer{}
+	s := newTestChatServer(t, llmServer)
+
+	require.NoError(t, s.Stream(newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Which careers suit me?", Verbosity: "short"},
+		&pbChat.StreamRequest{Type: msgTypeRegenerate, ConversationId: "conv-1", Verbosity: "detailed"},
+	)))
+
+	require.Len(t, llmServer.requests, 2)
+	assert.Equal(t, "short", llmServer.requests[0].GetVerbosity())
+	assert.Equal(t, "detailed", llmServer.requests[1].GetVerbosity(), "a regenerated answer may ask for another length")
+}
+
+func TestStream_RecordsAnswerSources(t *testing.T) {
+	s := newTestChatServer(t, &fakeLLMServer{})
+	stream := newUserStream(&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "What was the HUST cutoff?"})
+	require.NoError(t, s.Stream(stream))
+
+	res, err := s.GetConversation(userContext("user-1"), &pbChat.GetConversationRequest{ConversationId: "conv-1"})
+	require.NoError(t, err)
+	require.Len(t, res.GetMessages(), 2)
+	assert.Empty(t, res.GetMessages()[0].GetSources(), "user messages have no sources")
+
+	answer := res.GetMessages()[1]
+	assert.Equal(t, "Answer 1", answer.GetText())
+	require.Len(t, answer.GetSources(), 2)
+	kb, web := answer.GetSources()[0], answer.GetSources()[1]
+	assert.Equal(t, "knowledge_base", kb.GetType())
+	assert.Equal(t, "scores-1.pdf", kb.GetUri())
+	assert.Equal(t, "university-scores", kb.GetCollection())
+	assert.Equal(t, "web", web.GetType())
+	assert.Equal(t, "https://example.edu.vn/admissions", web.GetUri())
+	assert.Equal(t, "Admissions", web.GetTitle())
+	assert.Empty(t, web.GetCollection())
+}
+
+func TestStream_LLMBusy(t *testing.T) {
+	s := newTestChatServer(t, busyLLMServer{})
+	stream := newUserStream(&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Which careers suit me?"})
+	require.NoError(t, s.Stream(stream))
+
+	require.NotEmpty(t, stream.sent)
+	last := stream.sent[len(stream.sent)-1]
+	assert.Equal(t, "error", last.GetType())
+	assert.Equal(t, string(chaterr.LLMBusy), last.GetErrorCode())
+	assert.Contains(t, last.GetErrorMessage(), "high demand")
+}
+
+func TestLLMBusy(t *testing.T) {
+	exhausted := status.Error(codes.ResourceExhausted, "busy")
+	assert.True(t, llmBusy(exhausted, metadata.Pairs("retry-after", "1")))
+	assert.False(t, llmBusy(exhausted, nil), "without retry-after it is the user's quota")
+	assert.False(t, llmBusy(status.Error(codes.Internal, "boom"), metadata.Pairs("retry-after", "1")))
+}
+
+func TestStream_Continue(t *testing.T) {
+	const answer = "Software engineering suits your logical thinking and your interest in robotics."
+	llmServer := &truncatingLLMServer{answer: answer, maxWords: 4}
+	s := newTestChatServer(t, llmServer)
+
+	stream := newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Which careers suit me?"},
+		&pbChat.StreamRequest{Type: msgTypeContinue, ConversationId: "conv-1"},
+		&pbChat.StreamRequest{Type: msgTypeContinue, ConversationId: "conv-1"},
+	)
+	require.NoError(t, s.Stream(stream))
+
+	var statuses []string
+	for _, res := range stream.sent {
+		assert.NotEqual(t, "error", res.GetType(), res.GetErrorMessage())
+		if res.GetType() == "status" {
+			statuses = append(statuses, res.GetStatus())
+		}
+	}
+	assert.Equal(t, []string{statusTruncated, statusTruncated}, statuses, "the last continuation completes the answer")
+
+	require.Len(t, llmServer.requests, 3)
+	first := llmServer.requests[0]
+	assert.Empty(t, first.GetContinueFrom())
+	for _, req := range llmServer.requests[1:] {
+		assert.Equal(t, first.GetPrompt(), req.GetPrompt(), "continuing reuses the last user message")
+	}
+	assert.Equal(t, "Software engineering suits your ", llmServer.requests[1].GetContinueFrom())
+	assert.Equal(t, "Software engineering suits your logical thinking and your ", llmServer.requests[2].GetContinueFrom())
+
+	res, err := s.history.get(context.Background(), "conv-1", "user-1", historyQuery{})
+	require.NoError(t, err)
+	require.Len(t, res.GetMessages(), 2)
+	assert.Equal(t, answer, res.GetMessages()[1].GetText(), "continuations are appended to the answer")
+}
+
+func TestStream_ContinueLimit(t *testing.T) {
+	llmServer := &truncatingLLMServer{answer: "one two three four five six seven eight nine ten", maxWords: 2}
+	s := newTestChatServer(t, llmServer)
+
+	stream := newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeContinue, ConversationId: "conv-1"},
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Count to ten"},
+		&pbChat.StreamRequest{Type: msgTypeContinue, ConversationId: "conv-1"},
+		&pbChat.StreamRequest{Type: msgTypeContinue, ConversationId: "conv-1"},
+		&pbChat.StreamRequest{Type: msgTypeContinue, ConversationId: "conv-1"},
+	)
+	require.NoError(t, s.Stream(stream))
+
+	var errCodes []string
+	for _, res := range stream.sent {
+		if res.GetType() == "error" {
+			errCodes = append(errCodes, res.GetErrorCode())
+		}
+	}
+	// Nothing to continue before the first answer, and at most two
+	// continuations after it
+	assert.Equal(t, []string{string(chaterr.NothingToContinue), string(chaterr.ContinueLimitReached)}, errCodes)
+	assert.Len(t, llmServer.requests, 3)
+
+	res, err := s.history.get(context.Background(), "conv-1", "user-1", historyQuery{})
+	require.NoError(t, err)
+	require.Len(t, res.GetMessages(), 2)
+	assert.Equal(t, "one two three four five six ", res.GetMessages()[1].GetText())
+
+	// A new question starts a new answer, which may be continued again
+	stream = newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Count to ten again"},
+		&pbChat.StreamRequest{Type: msgTypeContinue, ConversationId: "conv-1"},
+	)
+	require.NoError(t, s.Stream(stream))
+	for _, res := range stream.sent {
+		assert.NotEqual(t, "error", res.GetType(), res.GetErrorMessage())
+	}
+	assert.Len(t, llmServer.requests, 5)
+}
+
+func TestStream_AfterReset(t *testing.T) {
+	llmServer := &fakeLLMServer{}
+	s := newTestChatServer(t, llmServer)
+	require.NoError(t, s.Stream(newUserStream(&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Which careers suit me?"})))
+
+	res, err := s.ResetConversation(userContext("user-1"), &pbChat.ResetConversationRequest{ConversationId: "conv-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "conv-1", res.GetConversation().GetConversationId())
+	assert.Equal(t, "Which careers suit me?", res.GetConversation().GetTitle(), "the title is kept")
+	assert.Zero(t, res.GetConversation().GetMessageCount())
+
+	// The earlier turns can no longer be regenerated or built upon
+	stream := newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeRegenerate, ConversationId: "conv-1"},
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "What about scholarships?"},
+	)
+	require.NoError(t, s.Stream(stream))
+	assert.Equal(t, string(chaterr.NothingToRegenerate), stream.sent[0].GetErrorCode())
+
+	require.Len(t, llmServer.requests, 2)
+	next := llmServer.requests[1]
+	assert.Contains(t, next.GetPrompt(), "What about scholarships?")
+	assert.NotContains(t, next.GetPrompt(), "Which careers suit me?")
+	assert.NotContains(t, next.GetPrompt(), "Answer 1")
+
+	history, err := s.history.get(context.Background(), "conv-1", "user-1", historyQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"What about scholarships?", "Answer 2"}, texts(history))
+	summary, err := s.GetConversationSummary(userContext("user-1"), &pbChat.GetConversationSummaryRequest{ConversationId: "conv-1"})
+	require.NoError(t, err)
+	assert.Empty(t, summary.GetSummary())
+}
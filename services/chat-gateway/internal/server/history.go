@@ -0,0 +1,654 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/careerup-Inc/careerup-monorepo/pkg/errs"
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	pbllm "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Errors of requests about one conversation.
+var (
+	errConversationNotFound = errs.New(errs.NotFound, "conversation not found")
+	errNotConversationOwner = errs.New(errs.PermissionDenied, "conversation belongs to another user")
+)
+
+// Roles of recorded conversation messages.
+const (
+	roleUser      = "user"
+	roleAssistant = "assistant"
+)
+
+// maxHistoryPageSize caps the page_size of GetConversation.
+const maxHistoryPageSize = 200
+
+// conversationTitleMaxChars bounds the title listed for a conversation.
+const conversationTitleMaxChars = 80
+
+// conversationHistory records the messages of each conversation so they can
+// be exported later. Where they are kept depends on its store.
+type conversationHistory struct {
+	store conversationStore
+	// maxMessages caps each conversation, dropping its oldest messages; 0
+	// keeps them all
+	maxMessages int
+	now         func() time.Time
+}
+
+type recordedConversation struct {
+	userID   string
+	messages []*pbChat.ConversationMessage
+	// title is the first user message, cut short; it is kept when the
+	// conversation is reset
+	title string
+	// summary stands in for the first summarized messages in prompts; see
+	// ChatServer.conversationContext
+	summary    string
+	summarized int
+	// archived conversations are left out of the list by default
+	archived bool
+	// truncated is set when the last answer was cut off at the token limit,
+	// and continuations counts how often it has been continued
+	truncated     bool
+	continuations int
+	// lastMessageID is the ID of the latest message recorded, which may
+	// since have been dropped
+	lastMessageID int64
+}
+
+// newMessage returns a message to add to the conversation, with the next ID.
+func (c *recordedConversation) newMessage(role, text, createdAt string, sources []*pbChat.MessageSource) *pbChat.ConversationMessage {
+	c.lastMessageID++
+	return &pbChat.ConversationMessage{
+		Id:        c.lastMessageID,
+		Role:      role,
+		Text:      text,
+		CreatedAt: createdAt,
+		Sources:   sources,
+	}
+}
+
+// info describes the conversation without its messages.
+func (c *recordedConversation) info(convID string) *pbChat.ConversationInfo {
+	info := &pbChat.ConversationInfo{
+		ConversationId: convID,
+		MessageCount:   int32(len(c.messages)),
+		Archived:       c.archived,
+	}
+	if n := len(c.messages); n > 0 {
+		info.LastMessageAt = c.messages[n-1].GetCreatedAt()
+	}
+	info.Title = c.title
+	return info
+}
+
+func newConversationHistory(store conversationStore, maxMessages int) *conversationHistory {
+	return &conversationHistory{
+		store:       store,
+		maxMessages: maxMessages,
+		now:         time.Now,
+	}
+}
+
+// owned loads one of the owner's conversations.
+func (h *conversationHistory) owned(ctx context.Context, convID, userID string) (*recordedConversation, error) {
+	conv, err := h.store.load(ctx, convID)
+	if err != nil {
+		return nil, err
+	}
+	return checkOwner(conv, userID)
+}
+
+// updateOwned applies fn to one of the owner's conversations.
+func (h *conversationHistory) updateOwned(ctx context.Context, convID, userID string, fn func(*recordedConversation) error) error {
+	return h.store.update(ctx, convID, func(conv *recordedConversation) (*recordedConversation, error) {
+		conv, err := checkOwner(conv, userID)
+		if err != nil {
+			return nil, err
+		}
+		return conv, fn(conv)
+	})
+}
+
+func checkOwner(conv *recordedConversation, userID string) (*recordedConversation, error) {
+	if conv == nil {
+		return nil, errConversationNotFound
+	}
+	if conv.userID != userID {
+		return nil, errNotConversationOwner
+	}
+	return conv, nil
+}
+
+// record appends a message, with the sources of an assistant answer, to a
+// conversation, which belongs to the user who started it. It fails with
+// errNotConversationOwner when the conversation belongs to someone else.
+func (h *conversationHistory) record(ctx context.Context, convID, userID, role, text string, sources []*pbChat.MessageSource) error {
+	return h.store.update(ctx, convID, func(conv *recordedConversation) (*recordedConversation, error) {
+		if conv == nil {
+			conv = &recordedConversation{userID: userID}
+		} else if conv.userID != userID {
+			return nil, errNotConversationOwner
+		}
+		conv.messages = append(conv.messages, conv.newMessage(role, text, h.now().UTC().Format(time.RFC3339), sources))
+		if h.maxMessages > 0 && len(conv.messages) > h.maxMessages {
+			dropped := len(conv.messages) - h.maxMessages
+			conv.messages = conv.messages[dropped:]
+			conv.summarized = max(conv.summarized-dropped, 0)
+		}
+		if role == roleUser {
+			if conv.title == "" {
+				conv.title = truncateText(strings.Join(strings.Fields(text), " "), conversationTitleMaxChars)
+			}
+		}
+		conv.truncated, conv.continuations = false, 0
+		return conv, nil
+	})
+}
+
+// truncateText cuts s to at most maxBytes bytes without splitting a UTF-8
+// sequence, marking the cut with an ellipsis.
+func truncateText(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	const ellipsis = "…"
+	cut := maxBytes - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	if cut <= 0 {
+		return ""
+	}
+	return strings.TrimRight(s[:cut], " ") + ellipsis
+}
+
+// lastUserMessage returns the text of the latest user message in one of the
+// owner's conversations.
+func (h *conversationHistory) lastUserMessage(ctx context.Context, convID, userID string) (string, error) {
+	conv, err := h.owned(ctx, convID, userID)
+	if err != nil {
+		return "", err
+	}
+	for i := len(conv.messages) - 1; i >= 0; i-- {
+		if conv.messages[i].GetRole() == roleUser {
+			return conv.messages[i].GetText(), nil
+		}
+	}
+	return "", errs.New(errs.NotFound, "conversation has no user message")
+}
+
+// priorTurns is what precedes the latest user message of a conversation:
+// the summary of its first summarized messages, then the messages since.
+type priorTurns struct {
+	summary    string
+	summarized int
+	messages   []*pbChat.ConversationMessage
+}
+
+// priorTurns returns what precedes the latest user message of one of the
+// owner's conversations.
+func (h *conversationHistory) priorTurns(ctx context.Context, convID, userID string) (*priorTurns, error) {
+	conv, err := h.owned(ctx, convID, userID)
+	if err != nil {
+		return nil, err
+	}
+	end := len(conv.messages)
+	for end > 0 && conv.messages[end-1].GetRole() != roleUser {
+		end--
+	}
+	// The latest user message itself
+	end = max(end-1, 0)
+	turns := &priorTurns{summary: conv.summary, summarized: min(conv.summarized, end)}
+	turns.messages = conv.messages[turns.summarized:end]
+	return turns, nil
+}
+
+// setSummary replaces the summary of one of the owner's conversations,
+// which covered its first from messages, with summary, covering its first
+// through messages. It does nothing if the summary changed meanwhile.
+func (h *conversationHistory) setSummary(ctx context.Context, convID, userID, summary string, from, through int) error {
+	return h.updateOwned(ctx, convID, userID, func(conv *recordedConversation) error {
+		if conv.summarized != from || len(conv.messages) < through {
+			return nil
+		}
+		conv.summary, conv.summarized = summary, through
+		return nil
+	})
+}
+
+// replaceLastAnswer replaces the assistant's reply to the latest user
+// message, sources included, or appends one if it has none.
+func (h *conversationHistory) replaceLastAnswer(ctx context.Context, convID, userID, text string, sources []*pbChat.MessageSource) error {
+	return h.updateOwned(ctx, convID, userID, func(conv *recordedConversation) error {
+		answer := conv.newMessage(roleAssistant, text, h.now().UTC().Format(time.RFC3339), sources)
+		if n := len(conv.messages); n > 0 && conv.messages[n-1].GetRole() == roleAssistant {
+			conv.messages[n-1] = answer
+		} else {
+			conv.messages = append(conv.messages, answer)
+		}
+		conv.truncated, conv.continuations = false, 0
+		return nil
+	})
+}
+
+// continuation returns what a "continue" request resumes in one of the
+// owner's conversations: the latest user message, the answer to it that was
+// cut off at the token limit, and how often that answer was continued.
+func (h *conversationHistory) continuation(ctx context.Context, convID, userID string) (question, answer string, continuations int, err error) {
+	conv, err := h.owned(ctx, convID, userID)
+	if err != nil {
+		return "", "", 0, err
+	}
+	n := len(conv.messages)
+	if !conv.truncated || n < 2 || conv.messages[n-1].GetRole() != roleAssistant {
+		return "", "", 0, errs.New(errs.Invalid, "the last answer was not cut off")
+	}
+	for i := n - 2; i >= 0; i-- {
+		if conv.messages[i].GetRole() == roleUser {
+			return conv.messages[i].GetText(), conv.messages[n-1].GetText(), conv.continuations, nil
+		}
+	}
+	return "", "", 0, errs.New(errs.NotFound, "conversation has no user message")
+}
+
+// extendLastAnswer appends the continuation of a cut-off answer to it. The
+// answer keeps its sources, as the continuation is generated from the same
+// question.
+func (h *conversationHistory) extendLastAnswer(ctx context.Context, convID, userID, text string) error {
+	return h.updateOwned(ctx, convID, userID, func(conv *recordedConversation) error {
+		n := len(conv.messages)
+		if n == 0 || conv.messages[n-1].GetRole() != roleAssistant {
+			return errs.New(errs.NotFound, "conversation has no answer to extend")
+		}
+		extended := proto.Clone(conv.messages[n-1]).(*pbChat.ConversationMessage)
+		extended.Text += text
+		conv.messages[n-1] = extended
+		conv.truncated = false
+		conv.continuations++
+		return nil
+	})
+}
+
+// markTruncated notes that the last answer of one of the owner's
+// conversations was cut off at the token limit, so it may be continued.
+func (h *conversationHistory) markTruncated(ctx context.Context, convID, userID string) error {
+	return h.updateOwned(ctx, convID, userID, func(conv *recordedConversation) error {
+		conv.truncated = true
+		return nil
+	})
+}
+
+// historyQuery narrows down and pages a conversation's history. The zero
+// value returns all of it.
+type historyQuery struct {
+	// since and until bound the creation time; zero means unbounded
+	since, until time.Time
+	role         string
+	// pageSize of 0 returns every matching message
+	pageSize int
+	// after is the position of the previous page's last message
+	after *historyCursor
+}
+
+// historyCursor is the position of a message in a conversation: its
+// creation time, then its ID, which orders messages created in the same
+// second. Unlike a message's index, the ID stays the same when older
+// messages are dropped.
+type historyCursor struct {
+	createdAt time.Time
+	id        int64
+}
+
+func (c historyCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.createdAt.UTC().Format(time.RFC3339) + "|" + strconv.FormatInt(c.id, 10)))
+}
+
+// precedes reports whether c comes before the message with the given ID
+// created at createdAt.
+func (c historyCursor) precedes(createdAt time.Time, id int64) bool {
+	return c.createdAt.Before(createdAt) || (c.createdAt.Equal(createdAt) && c.id < id)
+}
+
+func decodeHistoryCursor(token string) (*historyCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	c := &historyCursor{}
+	if c.createdAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+		return nil, err
+	}
+	if c.id, err = strconv.ParseInt(id, 10, 64); err != nil || c.id < 0 {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	return c, nil
+}
+
+// parseHistoryQuery checks the filters and paging of req.
+func parseHistoryQuery(req *pbChat.GetConversationRequest) (historyQuery, error) {
+	var q historyQuery
+	var err error
+	if req.GetSince() != "" {
+		if q.since, err = time.Parse(time.RFC3339, req.GetSince()); err != nil {
+			return q, errs.New(errs.Invalid, "since must be an RFC 3339 time")
+		}
+	}
+	if req.GetUntil() != "" {
+		if q.until, err = time.Parse(time.RFC3339, req.GetUntil()); err != nil {
+			return q, errs.New(errs.Invalid, "until must be an RFC 3339 time")
+		}
+	}
+	switch req.GetRole() {
+	case "", roleUser, roleAssistant:
+		q.role = req.GetRole()
+	default:
+		return q, errs.Newf(errs.Invalid, "role must be %q or %q", roleUser, roleAssistant)
+	}
+	if req.GetPageSize() < 0 {
+		return q, errs.New(errs.Invalid, "page_size must not be negative")
+	}
+	q.pageSize = min(int(req.GetPageSize()), maxHistoryPageSize)
+	if req.GetPageToken() != "" {
+		if q.after, err = decodeHistoryCursor(req.GetPageToken()); err != nil {
+			return q, errs.New(errs.Invalid, "invalid page_token")
+		}
+	}
+	return q, nil
+}
+
+// get returns the messages of a conversation matching q, oldest first, for
+// its owner.
+func (h *conversationHistory) get(ctx context.Context, convID, userID string, q historyQuery) (*pbChat.GetConversationResponse, error) {
+	conv, err := h.owned(ctx, convID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pbChat.GetConversationResponse{
+		ConversationId: convID,
+		UserId:         conv.userID,
+		Archived:       conv.archived,
+	}
+	var last historyCursor
+	for _, msg := range conv.messages {
+		createdAt, _ := time.Parse(time.RFC3339, msg.GetCreatedAt())
+		switch {
+		case q.after != nil && !q.after.precedes(createdAt, msg.GetId()),
+			!q.since.IsZero() && createdAt.Before(q.since),
+			!q.until.IsZero() && !createdAt.Before(q.until),
+			q.role != "" && msg.GetRole() != q.role:
+			continue
+		}
+		if q.pageSize > 0 && len(res.Messages) == q.pageSize {
+			// Only set when another message matches, so the last page has none
+			res.NextPageToken = last.encode()
+			break
+		}
+		res.Messages = append(res.Messages, msg)
+		last = historyCursor{createdAt: createdAt, id: msg.GetId()}
+	}
+	return res, nil
+}
+
+// summary returns the summary of the earlier turns of one of the owner's
+// conversations. A conversation with no recorded messages yet has an empty
+// summary.
+func (h *conversationHistory) summary(ctx context.Context, convID, userID string) (*pbChat.GetConversationSummaryResponse, error) {
+	res := &pbChat.GetConversationSummaryResponse{ConversationId: convID}
+	conv, err := h.store.load(ctx, convID)
+	if err != nil || conv == nil {
+		return res, err
+	}
+	if conv.userID != userID {
+		return nil, errNotConversationOwner
+	}
+	res.Summary = conv.summary
+	res.MessageCount = int32(len(conv.messages))
+	return res, nil
+}
+
+// list returns the user's conversations, most recently active first.
+// Archived ones are only included with includeArchived.
+func (h *conversationHistory) list(ctx context.Context, userID string, includeArchived bool) ([]*pbChat.ConversationInfo, error) {
+	convs, err := h.store.userConversations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var infos []*pbChat.ConversationInfo
+	for convID, conv := range convs {
+		if conv.archived && !includeArchived {
+			continue
+		}
+		infos = append(infos, conv.info(convID))
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		// RFC 3339 times in UTC sort as strings
+		if infos[i].GetLastMessageAt() != infos[j].GetLastMessageAt() {
+			return infos[i].GetLastMessageAt() > infos[j].GetLastMessageAt()
+		}
+		return infos[i].GetConversationId() < infos[j].GetConversationId()
+	})
+	return infos, nil
+}
+
+// setArchived archives or unarchives one of the owner's conversations.
+func (h *conversationHistory) setArchived(ctx context.Context, convID, userID string, archived bool) (*pbChat.ConversationInfo, error) {
+	var info *pbChat.ConversationInfo
+	err := h.updateOwned(ctx, convID, userID, func(conv *recordedConversation) error {
+		conv.archived = archived
+		info = conv.info(convID)
+		return nil
+	})
+	return info, err
+}
+
+// reset clears the messages and summary of one of the owner's
+// conversations, keeping its title and archived state.
+func (h *conversationHistory) reset(ctx context.Context, convID, userID string) (*pbChat.ConversationInfo, error) {
+	var info *pbChat.ConversationInfo
+	err := h.updateOwned(ctx, convID, userID, func(conv *recordedConversation) error {
+		conv.messages = nil
+		conv.summary, conv.summarized = "", 0
+		conv.truncated, conv.continuations = false, 0
+		info = conv.info(convID)
+		return nil
+	})
+	return info, err
+}
+
+// answerRecorder passes stream responses through to send while collecting
+// the assistant's answer and the sources it was generated from. A
+// "restarting" status discards the answer collected, as the client does;
+// the sources stay, as the new answer is generated from the same ones.
+type answerRecorder struct {
+	send    func(*pbChat.StreamResponse) error
+	answer  strings.Builder
+	sources []*pbChat.MessageSource
+	// truncated is set by a "truncated" status
+	truncated bool
+}
+
+func (r *answerRecorder) Send(res *pbChat.StreamResponse) error {
+	switch {
+	case res.Type == "assistant_token":
+		r.answer.WriteString(res.GetToken())
+	case res.GetStatus() == statusRestarting:
+		r.answer.Reset()
+		r.truncated = false
+	case res.GetStatus() == statusTruncated:
+		r.truncated = true
+	}
+	return r.send(res)
+}
+
+// String returns the answer collected so far.
+func (r *answerRecorder) String() string {
+	return r.answer.String()
+}
+
+// setSources records the sources llm-gateway reported for the answer.
+func (r *answerRecorder) setSources(sources []*pbllm.Source) {
+	r.sources = make([]*pbChat.MessageSource, 0, len(sources))
+	for _, src := range sources {
+		r.sources = append(r.sources, &pbChat.MessageSource{
+			Type:       src.GetType(),
+			Title:      src.GetTitle(),
+			Uri:        src.GetUri(),
+			Collection: src.GetCollection(),
+		})
+	}
+}
+
+// recordMessage adds a message to the conversation's history, skipping
+// messages without a conversation to attach them to.
+func (s *ChatServer) recordMessage(ctx context.Context, convID, userID, role, text string) {
+	if convID == "" || text == "" {
+		return
+	}
+	if err := s.history.record(ctx, convID, userID, role, text, nil); err != nil {
+		log.Printf("Not recording %s message in conversation %s of %s: %v", role, convID, userID, err)
+	}
+}
+
+// answerKind tells recordAnswer how an answer relates to the previous one.
+type answerKind int
+
+const (
+	answerNew         answerKind = iota
+	answerRegenerated            // replaces the previous answer
+	answerContinued              // resumes the previous, cut-off answer
+)
+
+// recordAnswer records the assistant's answer collected by r, with its
+// sources; a regenerated answer replaces the one it was generated in place
+// of, and a continuation is appended to the answer it continues. An answer
+// cut off at the token limit is marked so that it may be continued.
+func (s *ChatServer) recordAnswer(ctx context.Context, convID, userID string, r *answerRecorder, kind answerKind) {
+	text := r.String()
+	if convID == "" || text == "" {
+		return
+	}
+	// An answer cut short by a disconnect is still recorded
+	ctx = context.WithoutCancel(ctx)
+	var err error
+	switch kind {
+	case answerNew:
+		err = s.history.record(ctx, convID, userID, roleAssistant, text, r.sources)
+	case answerRegenerated:
+		err = s.history.replaceLastAnswer(ctx, convID, userID, text, r.sources)
+	case answerContinued:
+		err = s.history.extendLastAnswer(ctx, convID, userID, text)
+	}
+	if err == nil && r.truncated {
+		err = s.history.markTruncated(ctx, convID, userID)
+	}
+	if err != nil {
+		log.Printf("Not recording answer in conversation %s of %s: %v", convID, userID, err)
+	}
+}
+
+// GetConversation returns the recorded history of one of the caller's
+// conversations, optionally filtered by time and role and split into pages.
+func (s *ChatServer) GetConversation(ctx context.Context, req *pbChat.GetConversationRequest) (*pbChat.GetConversationResponse, error) {
+	userID := incomingUserID(ctx)
+	if userID == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing user identity")
+	}
+	if req.GetConversationId() == "" {
+		return nil, errs.New(errs.Invalid, "conversation_id is required")
+	}
+	q, err := parseHistoryQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.history.get(ctx, req.GetConversationId(), userID, q)
+}
+
+// GetConversationSummary returns the summary of the earlier turns of one of
+// the caller's conversations; it is empty until turns were compressed.
+func (s *ChatServer) GetConversationSummary(ctx context.Context, req *pbChat.GetConversationSummaryRequest) (*pbChat.GetConversationSummaryResponse, error) {
+	userID := incomingUserID(ctx)
+	if userID == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing user identity")
+	}
+	if req.GetConversationId() == "" {
+		return nil, errs.New(errs.Invalid, "conversation_id is required")
+	}
+	return s.history.summary(ctx, req.GetConversationId(), userID)
+}
+
+// ListConversations lists the caller's conversations, most recently active
+// first, leaving out archived ones unless include_archived is set.
+func (s *ChatServer) ListConversations(ctx context.Context, req *pbChat.ListConversationsRequest) (*pbChat.ListConversationsResponse, error) {
+	userID := incomingUserID(ctx)
+	if userID == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing user identity")
+	}
+	infos, err := s.history.list(ctx, userID, req.GetIncludeArchived())
+	if err != nil {
+		return nil, err
+	}
+	return &pbChat.ListConversationsResponse{Conversations: infos}, nil
+}
+
+// SetConversationArchived archives or unarchives one of the caller's
+// conversations. Archived conversations can still be read and continued.
+func (s *ChatServer) SetConversationArchived(ctx context.Context, req *pbChat.SetConversationArchivedRequest) (*pbChat.SetConversationArchivedResponse, error) {
+	userID := incomingUserID(ctx)
+	if userID == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing user identity")
+	}
+	if req.GetConversationId() == "" {
+		return nil, errs.New(errs.Invalid, "conversation_id is required")
+	}
+	info, err := s.history.setArchived(ctx, req.GetConversationId(), userID, req.GetArchived())
+	if err != nil {
+		return nil, err
+	}
+	return &pbChat.SetConversationArchivedResponse{Conversation: info}, nil
+}
+
+// ResetConversation clears the history of one of the caller's
+// conversations, so the next message is answered without any prior context.
+// The conversation keeps its ID and title.
+func (s *ChatServer) ResetConversation(ctx context.Context, req *pbChat.ResetConversationRequest) (*pbChat.ResetConversationResponse, error) {
+	userID := incomingUserID(ctx)
+	if userID == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing user identity")
+	}
+	if req.GetConversationId() == "" {
+		return nil, errs.New(errs.Invalid, "conversation_id is required")
+	}
+	info, err := s.history.reset(ctx, req.GetConversationId(), userID)
+	if err != nil {
+		return nil, err
+	}
+	return &pbChat.ResetConversationResponse{Conversation: info}, nil
+}
+
+// incomingUserID returns the caller's "user-id" metadata, or "" if missing.
+func incomingUserID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if userIDs := md.Get("user-id"); len(userIDs) > 0 {
+		return userIDs[0]
+	}
+	return ""
+}
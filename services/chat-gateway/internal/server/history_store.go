@@ -0,0 +1,331 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// conversationStore keeps the recorded conversations. Conversations it
+// returns are snapshots: they are never modified afterwards, so they can
+// be read without holding any lock.
+type conversationStore interface {
+	// load returns the conversation, or nil when there is none
+	load(ctx context.Context, convID string) (*recordedConversation, error)
+	// update calls fn with a copy of the conversation, nil when there is
+	// none, and saves the conversation fn returns; nil saves nothing. No
+	// other update of the conversation comes in between, on any replica.
+	// fn may be called more than once, and must not modify the messages
+	// it is given, only replace them.
+	update(ctx context.Context, convID string, fn func(*recordedConversation) (*recordedConversation, error)) error
+	// userConversations returns the user's conversations by ID
+	userConversations(ctx context.Context, userID string) (map[string]*recordedConversation, error)
+}
+
+// clone copies the conversation, sharing its messages.
+func (c *recordedConversation) clone() *recordedConversation {
+	copied := *c
+	copied.messages = append([]*pbChat.ConversationMessage(nil), c.messages...)
+	return &copied
+}
+
+// storeSweepInterval is how often the memory store drops the
+// conversations idle for longer than its TTL.
+const storeSweepInterval = time.Minute
+
+// memoryConversationStore keeps conversations in this instance's memory.
+type memoryConversationStore struct {
+	mu            sync.Mutex
+	conversations map[string]*memoryConversation
+	// Conversation IDs by user, so listing a user's conversations does not
+	// scan everyone's
+	byUser map[string]map[string]struct{}
+	// ttl of 0 keeps conversations until restart
+	ttl       time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryConversation struct {
+	conv       *recordedConversation
+	lastActive time.Time
+}
+
+func newMemoryConversationStore(ttl time.Duration) *memoryConversationStore {
+	return &memoryConversationStore{
+		conversations: make(map[string]*memoryConversation),
+		byUser:        make(map[string]map[string]struct{}),
+		ttl:           ttl,
+		now:           time.Now,
+	}
+}
+
+func (m *memoryConversationStore) load(ctx context.Context, convID string) (*recordedConversation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if entry := m.live(convID, m.now()); entry != nil {
+		return entry.conv, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryConversationStore) update(ctx context.Context, convID string, fn func(*recordedConversation) (*recordedConversation, error)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	m.sweep(now)
+
+	var conv *recordedConversation
+	if entry := m.live(convID, now); entry != nil {
+		conv = entry.conv.clone()
+	}
+	updated, err := fn(conv)
+	if err != nil || updated == nil {
+		return err
+	}
+	if conv != nil && conv.userID != updated.userID {
+		m.unindex(conv.userID, convID)
+	}
+	m.conversations[convID] = &memoryConversation{conv: updated, lastActive: now}
+	ids, ok := m.byUser[updated.userID]
+	if !ok {
+		ids = make(map[string]struct{})
+		m.byUser[updated.userID] = ids
+	}
+	ids[convID] = struct{}{}
+	return nil
+}
+
+func (m *memoryConversationStore) userConversations(ctx context.Context, userID string) (map[string]*recordedConversation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	convs := make(map[string]*recordedConversation)
+	for convID := range m.byUser[userID] {
+		if entry := m.live(convID, now); entry != nil {
+			convs[convID] = entry.conv
+		}
+	}
+	return convs, nil
+}
+
+// unindex drops convID from userID's conversations.
+func (m *memoryConversationStore) unindex(userID, convID string) {
+	ids := m.byUser[userID]
+	delete(ids, convID)
+	if len(ids) == 0 {
+		delete(m.byUser, userID)
+	}
+}
+
+// live returns the conversation's entry unless it is missing or expired.
+func (m *memoryConversationStore) live(convID string, now time.Time) *memoryConversation {
+	entry, ok := m.conversations[convID]
+	if !ok || m.expired(entry, now) {
+		return nil
+	}
+	return entry
+}
+
+func (m *memoryConversationStore) expired(entry *memoryConversation, now time.Time) bool {
+	return m.ttl > 0 && now.Sub(entry.lastActive) >= m.ttl
+}
+
+// sweep drops expired conversations once every storeSweepInterval.
+func (m *memoryConversationStore) sweep(now time.Time) {
+	if m.ttl <= 0 || now.Sub(m.lastSweep) < storeSweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for convID, entry := range m.conversations {
+		if m.expired(entry, now) {
+			delete(m.conversations, convID)
+			m.unindex(entry.conv.userID, convID)
+		}
+	}
+}
+
+// maxUpdateAttempts bounds how often the Redis store retries an update
+// that raced with one from another replica.
+const maxUpdateAttempts = 10
+
+// redisConversationStore keeps conversations in Redis, shared by every
+// replica. Each conversation is a JSON document that expires ttl after its
+// last update, and each user has a set of their conversation IDs.
+type redisConversationStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func newRedisConversationStore(client *redis.Client, ttl time.Duration) *redisConversationStore {
+	return &redisConversationStore{client: client, ttl: ttl}
+}
+
+func conversationKey(convID string) string {
+	return "chat:conversation:" + convID
+}
+
+func userConversationsKey(userID string) string {
+	return "chat:user_conversations:" + userID
+}
+
+func (r *redisConversationStore) load(ctx context.Context, convID string) (*recordedConversation, error) {
+	return r.get(ctx, r.client, convID)
+}
+
+func (r *redisConversationStore) get(ctx context.Context, c redis.Cmdable, convID string) (*recordedConversation, error) {
+	data, err := c.Get(ctx, conversationKey(convID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalConversation(data)
+}
+
+func (r *redisConversationStore) update(ctx context.Context, convID string, fn func(*recordedConversation) (*recordedConversation, error)) error {
+	key := conversationKey(convID)
+	for range maxUpdateAttempts {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			conv, err := r.get(ctx, tx, convID)
+			if err != nil {
+				return err
+			}
+			updated, err := fn(conv)
+			if err != nil || updated == nil {
+				return err
+			}
+			data, err := marshalConversation(updated)
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, data, r.ttl)
+				pipe.SAdd(ctx, userConversationsKey(updated.userID), convID)
+				if r.ttl > 0 {
+					pipe.Expire(ctx, userConversationsKey(updated.userID), r.ttl)
+				}
+				return nil
+			})
+			return err
+		}, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("conversation %s kept changing; gave up updating it", convID)
+}
+
+func (r *redisConversationStore) userConversations(ctx context.Context, userID string) (map[string]*recordedConversation, error) {
+	convIDs, err := r.client.SMembers(ctx, userConversationsKey(userID)).Result()
+	if err != nil || len(convIDs) == 0 {
+		return nil, err
+	}
+	keys := make([]string, len(convIDs))
+	for i, convID := range convIDs {
+		keys[i] = conversationKey(convID)
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	convs := make(map[string]*recordedConversation, len(convIDs))
+	var expired []any
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			expired = append(expired, convIDs[i])
+			continue
+		}
+		conv, err := unmarshalConversation([]byte(data))
+		if err != nil {
+			return nil, err
+		}
+		convs[convIDs[i]] = conv
+	}
+	if len(expired) > 0 {
+		// Best effort: a later listing drops them again
+		r.client.SRem(ctx, userConversationsKey(userID), expired...)
+	}
+	return convs, nil
+}
+
+// storedConversation is the JSON form of a recordedConversation. Messages
+// are protojson documents.
+type storedConversation struct {
+	UserID   string            `json:"user_id"`
+	Messages []json.RawMessage `json:"messages"`
+	Title    string            `json:"title,omitempty"`
+	// Earlier records kept a list of the user's questions as "summary",
+	// which is left behind
+	Summary       string `json:"context_summary,omitempty"`
+	Summarized    int    `json:"summarized,omitempty"`
+	Archived      bool   `json:"archived,omitempty"`
+	Truncated     bool   `json:"truncated,omitempty"`
+	Continuations int    `json:"continuations,omitempty"`
+	// Earlier records have no message IDs; see unmarshalConversation
+	LastMessageID int64 `json:"last_message_id,omitempty"`
+}
+
+func marshalConversation(c *recordedConversation) ([]byte, error) {
+	stored := storedConversation{
+		UserID:        c.userID,
+		Messages:      make([]json.RawMessage, len(c.messages)),
+		Title:         c.title,
+		Summary:       c.summary,
+		Summarized:    c.summarized,
+		Archived:      c.archived,
+		Truncated:     c.truncated,
+		Continuations: c.continuations,
+		LastMessageID: c.lastMessageID,
+	}
+	for i, msg := range c.messages {
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		stored.Messages[i] = data
+	}
+	return json.Marshal(stored)
+}
+
+func unmarshalConversation(data []byte) (*recordedConversation, error) {
+	var stored storedConversation
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("decode conversation: %w", err)
+	}
+	c := &recordedConversation{
+		userID:        stored.UserID,
+		messages:      make([]*pbChat.ConversationMessage, len(stored.Messages)),
+		title:         stored.Title,
+		summary:       stored.Summary,
+		summarized:    stored.Summarized,
+		archived:      stored.Archived,
+		truncated:     stored.Truncated,
+		continuations: stored.Continuations,
+		lastMessageID: stored.LastMessageID,
+	}
+	for i, raw := range stored.Messages {
+		c.messages[i] = &pbChat.ConversationMessage{}
+		if err := protojson.Unmarshal(raw, c.messages[i]); err != nil {
+			return nil, fmt.Errorf("decode conversation message: %w", err)
+		}
+	}
+	if c.lastMessageID == 0 {
+		// Recorded before messages had IDs: number them in order
+		for _, msg := range c.messages {
+			c.lastMessageID++
+			msg.Id = c.lastMessageID
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRedis returns a client of an in-process Redis.
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// clockedStore returns a store keeping conversations for ttl, whose time
+// only moves with the returned advance, for one of the backends.
+type clockedStore func(t *testing.T, ttl time.Duration) (conversationStore, func(time.Duration))
+
+func TestConversationStores(t *testing.T) {
+	backends := map[string]clockedStore{
+		"memory": func(t *testing.T, ttl time.Duration) (conversationStore, func(time.Duration)) {
+			now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+			store := newMemoryConversationStore(ttl)
+			store.now = func() time.Time { return now }
+			return store, func(d time.Duration) { now = now.Add(d) }
+		},
+		"redis": func(t *testing.T, ttl time.Duration) (conversationStore, func(time.Duration)) {
+			mr := miniredis.RunT(t)
+			client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+			t.Cleanup(func() { client.Close() })
+			return newRedisConversationStore(client, ttl), mr.FastForward
+		},
+	}
+	for name, newStore := range backends {
+		t.Run(name, func(t *testing.T) { testConversationStore(t, newStore) })
+	}
+}
+
+// testConversationStore checks what every conversationStore keeps.
+func testConversationStore(t *testing.T, newStore clockedStore) {
+	ctx := context.Background()
+
+	t.Run("keeps every field", func(t *testing.T) {
+		store, _ := newStore(t, time.Hour)
+		conv := &recordedConversation{
+			userID: "user-1",
+			messages: []*pbChat.ConversationMessage{
+				{Role: roleUser, Text: "Ngành nào hợp với tôi?", CreatedAt: "2026-10-16T09:00:00Z"},
+				{Role: roleAssistant, Text: "Công nghệ thông tin.", CreatedAt: "2026-10-16T09:00:01Z", Sources: []*pbChat.MessageSource{
+					{Type: "document", Title: "Điểm chuẩn", Uri: "https://example.com/diem-chuan", Collection: "university-scores"},
+				}},
+			},
+			title:         "Ngành nào hợp với tôi?",
+			summary:       "Học sinh hỏi ngành nào hợp với mình.",
+			summarized:    1,
+			archived:      true,
+			truncated:     true,
+			continuations: 2,
+		}
+		require.NoError(t, store.update(ctx, "conv-1", func(got *recordedConversation) (*recordedConversation, error) {
+			assert.Nil(t, got, "a new conversation")
+			return conv, nil
+		}))
+
+		got, err := store.load(ctx, "conv-1")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, conv.userID, got.userID)
+		assert.Equal(t, []string{"Ngành nào hợp với tôi?", "Công nghệ thông tin."}, []string{got.messages[0].GetText(), got.messages[1].GetText()})
+		assert.Equal(t, "https://example.com/diem-chuan", got.messages[1].GetSources()[0].GetUri())
+		assert.Equal(t, "2026-10-16T09:00:01Z", got.messages[1].GetCreatedAt())
+		assert.Equal(t, conv.title, got.title)
+		assert.Equal(t, conv.summary, got.summary)
+		assert.Equal(t, 1, got.summarized)
+		assert.True(t, got.archived)
+		assert.True(t, got.truncated)
+		assert.Equal(t, 2, got.continuations)
+
+		missing, err := store.load(ctx, "conv-2")
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+
+	t.Run("an update that fails saves nothing", func(t *testing.T) {
+		store, _ := newStore(t, time.Hour)
+		err := store.update(ctx, "conv-1", func(*recordedConversation) (*recordedConversation, error) {
+			return nil, errConversationNotFound
+		})
+		assert.ErrorIs(t, err, errConversationNotFound)
+		conv, err := store.load(ctx, "conv-1")
+		require.NoError(t, err)
+		assert.Nil(t, conv)
+	})
+
+	t.Run("lists the user's conversations", func(t *testing.T) {
+		store, _ := newStore(t, time.Hour)
+		for convID, userID := range map[string]string{"conv-1": "user-1", "conv-2": "user-1", "conv-3": "user-2"} {
+			require.NoError(t, store.update(ctx, convID, func(*recordedConversation) (*recordedConversation, error) {
+				return &recordedConversation{userID: userID}, nil
+			}))
+		}
+		convs, err := store.userConversations(ctx, "user-1")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"conv-1", "conv-2"}, mapKeys(convs))
+	})
+
+	t.Run("idle conversations expire", func(t *testing.T) {
+		store, advance := newStore(t, time.Hour)
+		record := func(convID string) {
+			require.NoError(t, store.update(ctx, convID, func(*recordedConversation) (*recordedConversation, error) {
+				return &recordedConversation{userID: "user-1"}, nil
+			}))
+		}
+		record("conv-1")
+		record("conv-2")
+		advance(40 * time.Minute)
+		record("conv-2")
+		advance(40 * time.Minute)
+
+		conv, err := store.load(ctx, "conv-1")
+		require.NoError(t, err)
+		assert.Nil(t, conv, "idle for longer than the TTL")
+		convs, err := store.userConversations(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"conv-2"}, mapKeys(convs), "recorded in since")
+	})
+}
+
+func TestMemoryConversationStore_SweepDropsUserIndex(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store := newMemoryConversationStore(time.Hour)
+	store.now = func() time.Time { return now }
+	record := func(convID, userID string) {
+		require.NoError(t, store.update(ctx, convID, func(*recordedConversation) (*recordedConversation, error) {
+			return &recordedConversation{userID: userID}, nil
+		}))
+	}
+	record("conv-1", "user-1")
+	now = now.Add(storeSweepInterval + time.Hour)
+	record("conv-2", "user-2")
+
+	assert.NotContains(t, store.conversations, "conv-1")
+	assert.NotContains(t, store.byUser, "user-1")
+	assert.Equal(t, map[string]struct{}{"conv-2": {}}, store.byUser["user-2"])
+}
+
+func mapKeys(convs map[string]*recordedConversation) []string {
+	var keys []string
+	for key := range convs {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestConversationHistory_KeepsLatestMessages(t *testing.T) {
+	h := newConversationHistory(newMemoryConversationStore(0), 3)
+	ctx := context.Background()
+	for i := range 5 {
+		require.NoError(t, h.record(ctx, "conv-1", "user-1", roleUser, fmt.Sprintf("q%d", i), nil))
+	}
+
+	res, err := h.get(ctx, "conv-1", "user-1", historyQuery{})
+	require.NoError(t, err)
+	var texts []string
+	for _, msg := range res.GetMessages() {
+		texts = append(texts, msg.GetText())
+	}
+	assert.Equal(t, []string{"q2", "q3", "q4"}, texts)
+	info, err := h.setArchived(ctx, "conv-1", "user-1", false)
+	require.NoError(t, err)
+	assert.Equal(t, "q0", info.GetTitle(), "the title outlives the dropped messages")
+}
+
+func TestConversationHistory_PagesSurviveDroppedMessages(t *testing.T) {
+	h := newConversationHistory(newMemoryConversationStore(0), 4)
+	h.now = func() time.Time { return time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+	for i := range 4 {
+		require.NoError(t, h.record(ctx, "conv-1", "user-1", roleUser, fmt.Sprintf("q%d", i), nil))
+	}
+	page, err := h.get(ctx, "conv-1", "user-1", historyQuery{pageSize: 2})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.GetNextPageToken())
+
+	// All in the same second, and q0 and q1 are dropped before the next page
+	for i := 4; i < 6; i++ {
+		require.NoError(t, h.record(ctx, "conv-1", "user-1", roleUser, fmt.Sprintf("q%d", i), nil))
+	}
+	after, err := decodeHistoryCursor(page.GetNextPageToken())
+	require.NoError(t, err)
+	page, err = h.get(ctx, "conv-1", "user-1", historyQuery{after: after})
+	require.NoError(t, err)
+	var texts []string
+	for _, msg := range page.GetMessages() {
+		texts = append(texts, msg.GetText())
+	}
+	assert.Equal(t, []string{"q2", "q3", "q4", "q5"}, texts)
+}
+
+func TestUnmarshalConversation_NumbersLegacyMessages(t *testing.T) {
+	conv, err := unmarshalConversation([]byte(`{"user_id":"user-1","messages":[
+		{"role":"user","text":"q0"},{"role":"assistant","text":"a0"}]}`))
+	require.NoError(t, err)
+	require.Len(t, conv.messages, 2)
+	assert.Equal(t, int64(1), conv.messages[0].GetId())
+	assert.Equal(t, int64(2), conv.messages[1].GetId())
+	assert.Equal(t, int64(3), conv.newMessage(roleUser, "q1", "", nil).GetId())
+
+	data, err := marshalConversation(conv)
+	require.NoError(t, err)
+	again, err := unmarshalConversation(data)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), again.lastMessageID)
+}
+
+func TestRedisConversationStore_ConcurrentReplicas(t *testing.T) {
+	client := newTestRedis(t)
+	ctx := context.Background()
+	// Each replica has its own client and history
+	replicas := []*conversationHistory{
+		newConversationHistory(newRedisConversationStore(client, time.Hour), 0),
+		newConversationHistory(newRedisConversationStore(redis.NewClient(client.Options()), time.Hour), 0),
+	}
+
+	var wg sync.WaitGroup
+	for r, h := range replicas {
+		for i := range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, h.record(ctx, "conv-1", "user-1", roleUser, fmt.Sprintf("r%d-%d", r, i), nil))
+			}()
+		}
+	}
+	wg.Wait()
+
+	res, err := replicas[0].get(ctx, "conv-1", "user-1", historyQuery{})
+	require.NoError(t, err)
+	assert.Len(t, res.GetMessages(), 10, "no update was lost")
+}
+
+func TestStream_ConversationMovesBetweenReplicas(t *testing.T) {
+	llmServer := &fakeLLMServer{}
+	client := newTestRedis(t)
+	// Both replicas answer through the same llm-gateway
+	first := newTestChatServer(t, llmServer)
+	cfg := *first.cfg
+	cfg.Chat.History = config.HistoryConfig{Backend: "redis", MaxMessages: 100, TTL: time.Hour}
+	first = NewChatServer(first.llmClient, nil, &cfg, client)
+	second := NewChatServer(first.llmClient, nil, &cfg, client)
+
+	require.NoError(t, first.Stream(newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "first"},
+	)))
+	// The client reconnected to the other replica
+	stream := newUserStream(&pbChat.StreamRequest{Type: msgTypeRegenerate, ConversationId: "conv-1"})
+	require.NoError(t, second.Stream(stream))
+
+	assert.Equal(t, "Answer 2", stream.sent[len(stream.sent)-1].GetToken())
+	assert.Equal(t, []string{"user: first", "assistant: Answer 2"}, historyTexts(t, first, "conv-1"))
+	res, err := second.ListConversations(userContext("user-1"), &pbChat.ListConversationsRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.GetConversations(), 1)
+	assert.Equal(t, "first", res.GetConversations()[0].GetTitle())
+}
@@ -0,0 +1,400 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func userContext(userID string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("user-id", userID))
+}
+
+// newTestHistory returns a history kept in memory without limits.
+func newTestHistory() *conversationHistory {
+	return newConversationHistory(newMemoryConversationStore(0), 0)
+}
+
+func TestGetConversation_ReturnsOwnersHistory(t *testing.T) {
+	s := &ChatServer{history: newTestHistory()}
+	s.history.now = func() time.Time { return time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC) }
+	s.recordMessage(context.Background(), "conv-1", "user-1", roleUser, "Ngành nào hợp với tôi?")
+	s.recordMessage(context.Background(), "conv-1", "user-1", roleAssistant, "Công nghệ thông tin.")
+
+	res, err := s.GetConversation(userContext("user-1"), &pbChat.GetConversationRequest{ConversationId: "conv-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", res.GetUserId())
+	require.Len(t, res.GetMessages(), 2)
+	assert.Equal(t, roleUser, res.GetMessages()[0].GetRole())
+	assert.Equal(t, "Công nghệ thông tin.", res.GetMessages()[1].GetText())
+	assert.Equal(t, "2025-05-01T10:00:00Z", res.GetMessages()[1].GetCreatedAt())
+}
+
+func TestGetConversation_Errors(t *testing.T) {
+	s := &ChatServer{history: newTestHistory()}
+	s.recordMessage(context.Background(), "conv-1", "user-1", roleUser, "hello")
+
+	_, err := s.GetConversation(userContext("user-2"), &pbChat.GetConversationRequest{ConversationId: "conv-1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.GetConversation(userContext("user-1"), &pbChat.GetConversationRequest{ConversationId: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.GetConversation(context.Background(), &pbChat.GetConversationRequest{ConversationId: "conv-1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRecordMessage_IgnoresOtherUsersMessages(t *testing.T) {
+	s := &ChatServer{history: newTestHistory()}
+	s.recordMessage(context.Background(), "conv-1", "user-1", roleUser, "mine")
+	s.recordMessage(context.Background(), "conv-1", "user-2", roleUser, "not mine")
+	s.recordMessage(context.Background(), "", "user-1", roleUser, "no conversation")
+
+	res, err := s.history.get(context.Background(), "conv-1", "user-1", historyQuery{})
+	require.NoError(t, err)
+	require.Len(t, res.GetMessages(), 1)
+	assert.Equal(t, "mine", res.GetMessages()[0].GetText())
+}
+
+func TestAnswerRecorder_DropsAnswerOnRestart(t *testing.T) {
+	var forwarded int
+	r := &answerRecorder{send: func(*pbChat.StreamResponse) error {
+		forwarded++
+		return nil
+	}}
+	token := func(tok string) *pbChat.StreamResponse {
+		return &pbChat.StreamResponse{Type: "assistant_token", Content: &pbChat.StreamResponse_Token{Token: tok}}
+	}
+	statusMsg := func(stage string) *pbChat.StreamResponse {
+		return &pbChat.StreamResponse{Type: "status", Content: &pbChat.StreamResponse_Status{Status: stage}}
+	}
+
+	for _, res := range []*pbChat.StreamResponse{
+		statusMsg("generating"), token("The ans"), statusMsg(statusRestarting), token("The "), token("answer"),
+	} {
+		require.NoError(t, r.Send(res))
+	}
+	assert.Equal(t, "The answer", r.String())
+	assert.Equal(t, 5, forwarded)
+}
+
+func TestLastUserMessageAndReplaceLastAnswer(t *testing.T) {
+	h := newTestHistory()
+	ctx := context.Background()
+	h.record(ctx, "conv-1", "user-1", roleUser, "first question", nil)
+	h.record(ctx, "conv-1", "user-1", roleAssistant, "first answer", nil)
+	h.record(ctx, "conv-1", "user-1", roleUser, "second question", nil)
+
+	last, err := h.lastUserMessage(ctx, "conv-1", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "second question", last)
+
+	// The second question has no answer yet, so one is added
+	require.NoError(t, h.replaceLastAnswer(ctx, "conv-1", "user-1", "second answer", nil))
+	require.NoError(t, h.replaceLastAnswer(ctx, "conv-1", "user-1", "better second answer", nil))
+	res, err := h.get(ctx, "conv-1", "user-1", historyQuery{})
+	require.NoError(t, err)
+	var texts []string
+	for _, m := range res.GetMessages() {
+		texts = append(texts, m.GetText())
+	}
+	assert.Equal(t, []string{"first question", "first answer", "second question", "better second answer"}, texts)
+
+	_, err = h.lastUserMessage(ctx, "conv-1", "user-2")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = h.lastUserMessage(ctx, "missing", "user-1")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, codes.PermissionDenied, status.Code(h.replaceLastAnswer(ctx, "conv-1", "user-2", "hijack", nil)))
+}
+
+func TestContinuation(t *testing.T) {
+	h := newTestHistory()
+	ctx := context.Background()
+	h.record(ctx, "conv-1", "user-1", roleUser, "question", nil)
+	h.record(ctx, "conv-1", "user-1", roleAssistant, "The answer is", nil)
+
+	_, _, _, err := h.continuation(ctx, "conv-1", "user-1")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "the answer was not cut off")
+
+	h.markTruncated(ctx, "conv-1", "user-1")
+	question, answer, continuations, err := h.continuation(ctx, "conv-1", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "question", question)
+	assert.Equal(t, "The answer is", answer)
+	assert.Zero(t, continuations)
+
+	require.NoError(t, h.extendLastAnswer(ctx, "conv-1", "user-1", " forty-two"))
+	_, _, _, err = h.continuation(ctx, "conv-1", "user-1")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "the continuation was complete")
+	h.markTruncated(ctx, "conv-1", "user-1")
+	_, answer, continuations, err = h.continuation(ctx, "conv-1", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "The answer is forty-two", answer)
+	assert.Equal(t, 1, continuations)
+
+	_, _, _, err = h.continuation(ctx, "conv-1", "user-2")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, codes.PermissionDenied, status.Code(h.extendLastAnswer(ctx, "conv-1", "user-2", " hijack")))
+
+	// A regenerated answer starts over
+	require.NoError(t, h.replaceLastAnswer(ctx, "conv-1", "user-1", "Another answer", nil))
+	_, _, _, err = h.continuation(ctx, "conv-1", "user-1")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetConversationSummary(t *testing.T) {
+	t.Run("existing conversation", func(t *testing.T) {
+		s := &ChatServer{history: newTestHistory()}
+		s.recordMessage(context.Background(), "conv-1", "user-1", roleUser, "Ngành nào hợp với tôi?")
+		s.recordMessage(context.Background(), "conv-1", "user-1", roleAssistant, "Công nghệ thông tin.")
+		s.recordMessage(context.Background(), "conv-1", "user-1", roleUser, "Điểm chuẩn năm ngoái là bao nhiêu?")
+		require.NoError(t, s.history.setSummary(context.Background(), "conv-1", "user-1", "Học sinh hợp với ngành Công nghệ thông tin.", 0, 2))
+
+		res, err := s.GetConversationSummary(userContext("user-1"), &pbChat.GetConversationSummaryRequest{ConversationId: "conv-1"})
+		require.NoError(t, err)
+		assert.Equal(t, "conv-1", res.GetConversationId())
+		assert.Equal(t, "Học sinh hợp với ngành Công nghệ thông tin.", res.GetSummary())
+		assert.Equal(t, int32(3), res.GetMessageCount())
+	})
+
+	t.Run("new conversation", func(t *testing.T) {
+		s := &ChatServer{history: newTestHistory()}
+
+		res, err := s.GetConversationSummary(userContext("user-1"), &pbChat.GetConversationSummaryRequest{ConversationId: "conv-new"})
+		require.NoError(t, err)
+		assert.Equal(t, "conv-new", res.GetConversationId())
+		assert.Empty(t, res.GetSummary())
+		assert.Zero(t, res.GetMessageCount())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		s := &ChatServer{history: newTestHistory()}
+		s.recordMessage(context.Background(), "conv-1", "user-1", roleUser, "hello")
+
+		_, err := s.GetConversationSummary(userContext("user-2"), &pbChat.GetConversationSummaryRequest{ConversationId: "conv-1"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = s.GetConversationSummary(context.Background(), &pbChat.GetConversationSummaryRequest{ConversationId: "conv-1"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, err = s.GetConversationSummary(userContext("user-1"), &pbChat.GetConversationSummaryRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+// pagedServer records six messages: two per minute from 10:00, each pair a
+// user message and its answer created in the same second
+func pagedServer() *ChatServer {
+	s := &ChatServer{history: newTestHistory()}
+	start := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		at := start.Add(time.Duration(i) * time.Minute)
+		s.history.now = func() time.Time { return at }
+		s.recordMessage(context.Background(), "conv-1", "user-1", roleUser, fmt.Sprintf("q%d", i))
+		s.recordMessage(context.Background(), "conv-1", "user-1", roleAssistant, fmt.Sprintf("a%d", i))
+	}
+	return s
+}
+
+func texts(res *pbChat.GetConversationResponse) []string {
+	var out []string
+	for _, m := range res.GetMessages() {
+		out = append(out, m.GetText())
+	}
+	return out
+}
+
+func TestGetConversation_Pagination(t *testing.T) {
+	s := pagedServer()
+	get := func(req *pbChat.GetConversationRequest) *pbChat.GetConversationResponse {
+		t.Helper()
+		req.ConversationId = "conv-1"
+		res, err := s.GetConversation(userContext("user-1"), req)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("pages cover every message once, in order", func(t *testing.T) {
+		var got []string
+		page := get(&pbChat.GetConversationRequest{PageSize: 3})
+		got = append(got, texts(page)...)
+		// The page boundary splits messages created in the same second
+		for page.GetNextPageToken() != "" {
+			page = get(&pbChat.GetConversationRequest{PageSize: 1, PageToken: page.GetNextPageToken()})
+			got = append(got, texts(page)...)
+		}
+		assert.Equal(t, []string{"q0", "a0", "q1", "a1", "q2", "a2"}, got)
+	})
+
+	t.Run("last full page has no token", func(t *testing.T) {
+		page := get(&pbChat.GetConversationRequest{PageSize: 3})
+		require.NotEmpty(t, page.GetNextPageToken())
+		page = get(&pbChat.GetConversationRequest{PageSize: 3, PageToken: page.GetNextPageToken()})
+		assert.Equal(t, []string{"a1", "q2", "a2"}, texts(page))
+		assert.Empty(t, page.GetNextPageToken())
+	})
+
+	t.Run("no page size returns everything", func(t *testing.T) {
+		page := get(&pbChat.GetConversationRequest{})
+		assert.Len(t, page.GetMessages(), 6)
+		assert.Empty(t, page.GetNextPageToken())
+	})
+
+	t.Run("filters apply across pages", func(t *testing.T) {
+		page := get(&pbChat.GetConversationRequest{Role: roleUser, PageSize: 2})
+		assert.Equal(t, []string{"q0", "q1"}, texts(page))
+		page = get(&pbChat.GetConversationRequest{Role: roleUser, PageSize: 2, PageToken: page.GetNextPageToken()})
+		assert.Equal(t, []string{"q2"}, texts(page))
+		assert.Empty(t, page.GetNextPageToken())
+	})
+}
+
+func TestGetConversation_DateRange(t *testing.T) {
+	s := pagedServer()
+	get := func(since, until string) []string {
+		t.Helper()
+		res, err := s.GetConversation(userContext("user-1"), &pbChat.GetConversationRequest{
+			ConversationId: "conv-1", Since: since, Until: until,
+		})
+		require.NoError(t, err)
+		return texts(res)
+	}
+
+	// since is inclusive, until exclusive
+	assert.Equal(t, []string{"q1", "a1", "q2", "a2"}, get("2025-05-01T10:01:00Z", ""))
+	assert.Equal(t, []string{"q0", "a0"}, get("", "2025-05-01T10:01:00Z"))
+	assert.Equal(t, []string{"q1", "a1"}, get("2025-05-01T10:01:00Z", "2025-05-01T10:02:00Z"))
+	// Offsets are honoured
+	assert.Equal(t, []string{"q2", "a2"}, get("2025-05-01T17:02:00+07:00", ""))
+	assert.Empty(t, get("2025-05-02T00:00:00Z", ""))
+}
+
+func TestGetConversation_InvalidQuery(t *testing.T) {
+	s := pagedServer()
+	for name, req := range map[string]*pbChat.GetConversationRequest{
+		"since":      {Since: "yesterday"},
+		"until":      {Until: "2025-05-01"},
+		"role":       {Role: "system"},
+		"page size":  {PageSize: -1},
+		"page token": {PageToken: "not-a-cursor"},
+	} {
+		req.ConversationId = "conv-1"
+		_, err := s.GetConversation(userContext("user-1"), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), name)
+	}
+}
+
+func TestListConversations_Archive(t *testing.T) {
+	s := &ChatServer{history: newTestHistory()}
+	for i, convID := range []string{"conv-1", "conv-2", "conv-3"} {
+		at := time.Date(2025, 5, 1, 10, i, 0, 0, time.UTC)
+		s.history.now = func() time.Time { return at }
+		s.recordMessage(context.Background(), convID, "user-1", roleUser, "  Question\n"+convID)
+	}
+	s.recordMessage(context.Background(), "conv-other", "user-2", roleUser, "someone else's")
+
+	list := func(includeArchived bool) []string {
+		t.Helper()
+		res, err := s.ListConversations(userContext("user-1"), &pbChat.ListConversationsRequest{IncludeArchived: includeArchived})
+		require.NoError(t, err)
+		var ids []string
+		for _, info := range res.GetConversations() {
+			ids = append(ids, info.GetConversationId())
+		}
+		return ids
+	}
+	setArchived := func(convID string, archived bool) *pbChat.ConversationInfo {
+		t.Helper()
+		res, err := s.SetConversationArchived(userContext("user-1"), &pbChat.SetConversationArchivedRequest{ConversationId: convID, Archived: archived})
+		require.NoError(t, err)
+		return res.GetConversation()
+	}
+
+	assert.Equal(t, []string{"conv-3", "conv-2", "conv-1"}, list(false))
+
+	info := setArchived("conv-2", true)
+	assert.True(t, info.GetArchived())
+	assert.Equal(t, "Question conv-2", info.GetTitle())
+	assert.Equal(t, int32(1), info.GetMessageCount())
+	assert.Equal(t, "2025-05-01T10:01:00Z", info.GetLastMessageAt())
+
+	t.Run("archived conversations are left out by default", func(t *testing.T) {
+		assert.Equal(t, []string{"conv-3", "conv-1"}, list(false))
+		assert.Equal(t, []string{"conv-3", "conv-2", "conv-1"}, list(true))
+	})
+
+	t.Run("archived conversations stay readable", func(t *testing.T) {
+		res, err := s.GetConversation(userContext("user-1"), &pbChat.GetConversationRequest{ConversationId: "conv-2"})
+		require.NoError(t, err)
+		assert.True(t, res.GetArchived())
+		assert.Equal(t, []string{"  Question\nconv-2"}, texts(res))
+	})
+
+	t.Run("unarchive round-trips", func(t *testing.T) {
+		assert.False(t, setArchived("conv-2", false).GetArchived())
+		assert.Equal(t, []string{"conv-3", "conv-2", "conv-1"}, list(false))
+	})
+}
+
+func TestSetConversationArchived_Errors(t *testing.T) {
+	s := &ChatServer{history: newTestHistory()}
+	s.recordMessage(context.Background(), "conv-1", "user-1", roleUser, "hello")
+
+	_, err := s.SetConversationArchived(userContext("user-2"), &pbChat.SetConversationArchivedRequest{ConversationId: "conv-1", Archived: true})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.SetConversationArchived(userContext("user-1"), &pbChat.SetConversationArchivedRequest{ConversationId: "missing", Archived: true})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.SetConversationArchived(userContext("user-1"), &pbChat.SetConversationArchivedRequest{Archived: true})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.ListConversations(context.Background(), &pbChat.ListConversationsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	res, err := s.GetConversation(userContext("user-1"), &pbChat.GetConversationRequest{ConversationId: "conv-1"})
+	require.NoError(t, err)
+	assert.False(t, res.GetArchived())
+}
+
+func TestResetConversation(t *testing.T) {
+	s := &ChatServer{history: newTestHistory()}
+	s.recordMessage(context.Background(), "conv-1", "user-1", roleUser, "hello")
+	s.recordMessage(context.Background(), "conv-1", "user-1", roleAssistant, "hi")
+	_, err := s.SetConversationArchived(userContext("user-1"), &pbChat.SetConversationArchivedRequest{ConversationId: "conv-1", Archived: true})
+	require.NoError(t, err)
+
+	_, err = s.ResetConversation(userContext("user-2"), &pbChat.ResetConversationRequest{ConversationId: "conv-1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.ResetConversation(userContext("user-1"), &pbChat.ResetConversationRequest{ConversationId: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = s.ResetConversation(userContext("user-1"), &pbChat.ResetConversationRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.ResetConversation(context.Background(), &pbChat.ResetConversationRequest{ConversationId: "conv-1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	res, err := s.ResetConversation(userContext("user-1"), &pbChat.ResetConversationRequest{ConversationId: "conv-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", res.GetConversation().GetTitle())
+	assert.True(t, res.GetConversation().GetArchived(), "resetting does not unarchive")
+
+	history, err := s.GetConversation(userContext("user-1"), &pbChat.GetConversationRequest{ConversationId: "conv-1"})
+	require.NoError(t, err)
+	assert.Empty(t, history.GetMessages())
+
+	// The title stays the first question asked, not the first after the reset
+	s.recordMessage(context.Background(), "conv-1", "user-1", roleUser, "new topic")
+	list, err := s.ListConversations(userContext("user-1"), &pbChat.ListConversationsRequest{IncludeArchived: true})
+	require.NoError(t, err)
+	require.Len(t, list.GetConversations(), 1)
+	assert.Equal(t, "hello", list.GetConversations()[0].GetTitle())
+	assert.Equal(t, int32(1), list.GetConversations()[0].GetMessageCount())
+}
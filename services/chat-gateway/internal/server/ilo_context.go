@@ -0,0 +1,167 @@
+package server
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/pkg/errs"
+	"github.com/careerup-Inc/careerup-monorepo/pkg/ilo"
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+)
+
+// iloCacheSweepInterval is how often expired ILO results are dropped, so
+// users who stopped chatting don't hold on to theirs.
+const iloCacheSweepInterval = time.Minute
+
+// iloContext returns the prompt context for the user's latest ILO result.
+// It is empty when the user has no result or it can't be fetched in time:
+// the message is then answered without it rather than held up.
+func (s *ChatServer) iloContext(ctx context.Context, userID string) string {
+	if s.iloClient == nil || userID == "unknown" {
+		return ""
+	}
+	result, err := s.latestIloResult(ctx, userID)
+	if err != nil {
+		log.Printf("Answering without ILO context for user %s: %v", userID, err)
+		return ""
+	}
+	if result == nil {
+		return ""
+	}
+	return ilo.Profile(result.TopDomains, result.SuggestedCareers, iloScores(result.Scores))
+}
+
+// latestIloResult returns the user's latest ILO result, nil if they have
+// none, from the cache or else from the ILO service. A fetch that times out
+// or finds the service unavailable is retried once, after a jittered
+// backoff so that retries from many streams don't arrive together.
+func (s *ChatServer) latestIloResult(ctx context.Context, userID string) (*pbChat.IloTestResult, error) {
+	if result, ok := s.iloResults.get(userID); ok {
+		return result, nil
+	}
+
+	var err error
+	for attempt := 1; attempt <= 2; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(jitter(s.cfg.Ilo.RetryBackoff)):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		callCtx, cancel := context.WithTimeout(ctx, s.cfg.Ilo.Timeout)
+		var result *pbChat.IloTestResult
+		result, err = s.iloClient.GetLatestIloTestResult(callCtx, userID)
+		cancel()
+		if err == nil {
+			s.iloResults.put(userID, result)
+			return result, nil
+		}
+		if ctx.Err() != nil || !retryableIloError(err) {
+			break
+		}
+	}
+	return nil, err
+}
+
+func retryableIloError(err error) bool {
+	return errs.Is(err, errs.Unavailable)
+}
+
+// jitter returns a random duration in [d/2, 3d/2).
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d)
+}
+
+// iloResultCache holds users' latest ILO results for a short while, so the
+// turns of a chat session don't each fetch it. A user without a result is
+// cached too. Beyond maxEntries users, the least recently used are dropped.
+type iloResultCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	// order holds the entries, the most recently used first
+	order     *list.List
+	lastSweep time.Time
+}
+
+type iloCacheEntry struct {
+	userID  string
+	result  *pbChat.IloTestResult
+	expires time.Time
+}
+
+// newIloResultCache caches the results of up to maxEntries users for ttl;
+// a ttl or maxEntries of zero or less caches nothing.
+func newIloResultCache(ttl time.Duration, maxEntries int) *iloResultCache {
+	return &iloResultCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (c *iloResultCache) get(userID string) (*pbChat.IloTestResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[userID]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*iloCacheEntry)
+	if !time.Now().Before(entry.expires) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.result, true
+}
+
+func (c *iloResultCache) put(userID string, result *pbChat.IloTestResult) {
+	if c.ttl <= 0 || c.maxEntries <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	c.sweep(now)
+	if elem, ok := c.entries[userID]; ok {
+		entry := elem.Value.(*iloCacheEntry)
+		entry.result, entry.expires = result, now.Add(c.ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[userID] = c.order.PushFront(&iloCacheEntry{userID: userID, result: result, expires: now.Add(c.ttl)})
+	for len(c.entries) > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// sweep drops expired entries once every iloCacheSweepInterval.
+func (c *iloResultCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < iloCacheSweepInterval {
+		return
+	}
+	c.lastSweep = now
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		if !now.Before(elem.Value.(*iloCacheEntry).expires) {
+			c.remove(elem)
+		}
+		elem = next
+	}
+}
+
+func (c *iloResultCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*iloCacheEntry).userID)
+}
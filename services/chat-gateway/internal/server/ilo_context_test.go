@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/client"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// fakeIloServer answers GetIloTestResults with one result, after delay,
+// and counts the calls.
+type fakeIloServer struct {
+	pbChat.UnimplementedIloServiceServer
+	delay time.Duration
+	mu    sync.Mutex
+	calls int
+}
+
+func (f *fakeIloServer) GetIloTestResults(ctx context.Context, req *pbChat.GetIloTestResultsRequest) (*pbChat.GetIloTestResultsResponse, error) {
+	f.mu.Lock()
+	f.calls++
+	delay := f.delay
+	f.mu.Unlock()
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &pbChat.GetIloTestResultsResponse{Results: []*pbChat.IloTestResult{{
+		UserId:           req.GetUserId(),
+		TopDomains:       []string{"LOGIC"},
+		SuggestedCareers: []string{"Software engineer"},
+	}}}, nil
+}
+
+func (f *fakeIloServer) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// newIloChatServer returns a ChatServer whose ILO client talks to iloServer.
+func newIloChatServer(t *testing.T, iloServer pbChat.IloServiceServer, iloCfg config.IloConfig) *ChatServer {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	pbChat.RegisterIloServiceServer(srv, iloServer)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return NewChatServer(nil, client.NewIloClient(conn), &config.Config{Ilo: iloCfg}, nil)
+}
+
+func TestIloContext_CachedBetweenTurns(t *testing.T) {
+	iloServer := &fakeIloServer{}
+	s := newIloChatServer(t, iloServer, config.IloConfig{Timeout: time.Second, CacheTTL: time.Minute, CacheSize: 100})
+
+	first := s.iloContext(context.Background(), "user-1")
+	assert.Contains(t, first, "LOGIC")
+	assert.Equal(t, first, s.iloContext(context.Background(), "user-1"))
+	assert.Equal(t, 1, iloServer.callCount())
+
+	// Other users aren't served from user-1's entry
+	s.iloContext(context.Background(), "user-2")
+	assert.Equal(t, 2, iloServer.callCount())
+}
+
+func TestIloContext_NotCachedWithoutTTL(t *testing.T) {
+	iloServer := &fakeIloServer{}
+	s := newIloChatServer(t, iloServer, config.IloConfig{Timeout: time.Second})
+
+	s.iloContext(context.Background(), "user-1")
+	s.iloContext(context.Background(), "user-1")
+	assert.Equal(t, 2, iloServer.callCount())
+}
+
+func TestIloContext_TimeoutDegradesToNoContext(t *testing.T) {
+	iloServer := &fakeIloServer{delay: time.Second}
+	s := newIloChatServer(t, iloServer, config.IloConfig{
+		Timeout:      20 * time.Millisecond,
+		RetryBackoff: 10 * time.Millisecond,
+		CacheTTL:     time.Minute,
+		CacheSize:    100,
+	})
+
+	start := time.Now()
+	assert.Empty(t, s.iloContext(context.Background(), "user-1"))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	// Retried once, and the failure isn't cached
+	assert.Equal(t, 2, iloServer.callCount())
+
+	iloServer.mu.Lock()
+	iloServer.delay = 0
+	iloServer.mu.Unlock()
+	assert.Contains(t, s.iloContext(context.Background(), "user-1"), "LOGIC")
+}
+
+func TestIloResultCache_DropsLeastRecentlyUsed(t *testing.T) {
+	c := newIloResultCache(time.Minute, 2)
+	c.put("user-1", &pbChat.IloTestResult{})
+	c.put("user-2", &pbChat.IloTestResult{})
+	_, ok := c.get("user-1")
+	require.True(t, ok)
+
+	c.put("user-3", &pbChat.IloTestResult{})
+	_, ok = c.get("user-2")
+	assert.False(t, ok, "user-2 was used least recently")
+	for _, userID := range []string{"user-1", "user-3"} {
+		_, ok = c.get(userID)
+		assert.True(t, ok, userID)
+	}
+	assert.Len(t, c.entries, 2)
+}
+
+func TestIloResultCache_SweepsExpiredEntries(t *testing.T) {
+	c := newIloResultCache(time.Minute, 100)
+	c.put("user-1", &pbChat.IloTestResult{})
+	c.put("user-2", &pbChat.IloTestResult{})
+	c.entries["user-1"].Value.(*iloCacheEntry).expires = time.Now().Add(-time.Second)
+
+	// Sweeps are spaced out
+	c.put("user-3", &pbChat.IloTestResult{})
+	assert.Len(t, c.entries, 3)
+
+	c.lastSweep = time.Now().Add(-iloCacheSweepInterval)
+	c.put("user-3", &pbChat.IloTestResult{})
+	assert.Len(t, c.entries, 2)
+	assert.NotContains(t, c.entries, "user-1")
+	assert.Equal(t, 2, c.order.Len())
+}
+
+func TestJitter(t *testing.T) {
+	assert.Zero(t, jitter(0))
+	for i := 0; i < 100; i++ {
+		d := jitter(100 * time.Millisecond)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.Less(t, d, 150*time.Millisecond)
+	}
+}
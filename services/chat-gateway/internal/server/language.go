@@ -0,0 +1,137 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Supported response languages, as BCP 47 primary tags.
+const (
+	langVietnamese = "vi"
+	langEnglish    = "en"
+)
+
+// supportedLanguage reports whether responses can be localized to lang.
+func supportedLanguage(lang string) bool {
+	return lang == langVietnamese || lang == langEnglish
+}
+
+// languageInstructions are written in the target language so the instruction
+// itself nudges the model (and llm-gateway's own detection) the same way.
+var languageInstructions = map[string]string{
+	langVietnamese: "Hãy trả lời bằng tiếng Việt.",
+	langEnglish:    "Please respond in English.",
+}
+
+// Common words that tell the languages apart when Vietnamese is typed
+// without diacritics.
+var (
+	vietnameseWords = map[string]bool{
+		"toi": true, "ban": true, "la": true, "cua": true, "khong": true, "nganh": true,
+		"truong": true, "hoc": true, "nao": true, "gi": true, "em": true, "va": true,
+		"cho": true, "nhung": true, "duoc": true, "diem": true, "lam": true,
+	}
+	englishWords = map[string]bool{
+		"the": true, "is": true, "are": true, "what": true, "which": true, "how": true,
+		"i": true, "you": true, "my": true, "to": true, "and": true, "of": true,
+		"for": true, "in": true, "should": true, "can": true, "university": true,
+	}
+)
+
+// vietnameseLetters are letters that only occur in Vietnamese among the two
+// supported languages.
+const vietnameseLetters = "ăâđêôơưàáảãạằắẳẵặầấẩẫậèéẻẽẹềếểễệìíỉĩịòóỏõọồốổỗộờớởỡợùúủũụừứửữựỳýỷỹỵ"
+
+// detectLanguage guesses the language of text. It returns "" when the text
+// gives no clear signal, e.g. a short greeting or a bare number.
+func detectLanguage(text string) string {
+	lower := strings.ToLower(text)
+	for _, r := range lower {
+		if strings.ContainsRune(vietnameseLetters, r) {
+			return langVietnamese
+		}
+	}
+
+	var vi, en int
+	for _, word := range strings.FieldsFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if vietnameseWords[word] {
+			vi++
+		}
+		if englishWords[word] {
+			en++
+		}
+	}
+	switch {
+	case vi > en:
+		return langVietnamese
+	case en > vi:
+		return langEnglish
+	default:
+		return ""
+	}
+}
+
+// profileLanguage extracts the supported language with the highest weight
+// from an Accept-Language style preference list ("en;q=0.5,vi-VN"). Tags
+// without a q value weigh 1, ties go to the earlier tag, and q=0 or an
+// unparseable weight rules a tag out.
+func profileLanguage(pref string) string {
+	var best string
+	bestWeight := 0.0
+	for _, part := range strings.Split(pref, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !supportedLanguage(primary) {
+			continue
+		}
+		if weight := languageWeight(params); weight > bestWeight {
+			best, bestWeight = primary, weight
+		}
+	}
+	return best
+}
+
+// languageWeight returns the q value among the parameters of one
+// Accept-Language entry, 1 if there is none and 0 if it is invalid.
+func languageWeight(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 || weight > 1 {
+			return 0
+		}
+		return weight
+	}
+	return 1
+}
+
+// resolveLanguage picks the response language for a message: the detected
+// language of the text, else the user's profile language, else fallback.
+func resolveLanguage(text, profile, fallback string) string {
+	if lang := detectLanguage(text); lang != "" {
+		return lang
+	}
+	if lang := profileLanguage(profile); lang != "" {
+		return lang
+	}
+	return fallback
+}
+
+// buildPrompt assembles the prompt sent to llm-gateway: the response-language
+// instruction, the user's ILO context, the earlier turns of the
+// conversation, then the message itself.
+func buildPrompt(lang, iloContext, conversationContext, text string) string {
+	var sb strings.Builder
+	if instruction, ok := languageInstructions[lang]; ok {
+		sb.WriteString(instruction)
+		sb.WriteString(" ")
+	}
+	sb.WriteString(iloContext)
+	sb.WriteString(conversationContext)
+	sb.WriteString(text)
+	return sb.String()
+}
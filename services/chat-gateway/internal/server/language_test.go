@@ -0,0 +1,88 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"vietnamese with diacritics", "Điểm chuẩn ngành CNTT năm nay là bao nhiêu?", "vi"},
+		{"vietnamese without diacritics", "toi muon hoc nganh cntt o truong nao", "vi"},
+		{"english", "Which university should I choose for computer science?", "en"},
+		{"greeting is ambiguous", "ok", ""},
+		{"number is ambiguous", "27.5", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectLanguage(tt.text))
+		})
+	}
+}
+
+func TestProfileLanguage(t *testing.T) {
+	assert.Equal(t, "vi", profileLanguage("vi-VN,vi;q=0.9,en;q=0.8"))
+	assert.Equal(t, "en", profileLanguage("fr-FR, en-US;q=0.7"))
+	assert.Equal(t, "", profileLanguage("fr"))
+	assert.Equal(t, "", profileLanguage(""))
+}
+
+func TestProfileLanguage_Weights(t *testing.T) {
+	tests := []struct {
+		name string
+		pref string
+		want string
+	}{
+		{"highest weight wins over order", "en;q=0.5,vi-VN", "vi"},
+		{"explicit weights", "vi;q=0.3, en;q=0.8", "en"},
+		{"ties keep order", "en;q=0.7,vi;q=0.7", "en"},
+		{"q=0 is not acceptable", "vi;q=0,en;q=0.1", "en"},
+		{"invalid weight is ignored", "vi;q=high,en;q=0.2", "en"},
+		{"unsupported tag outweighs nothing", "fr;q=1,vi;q=0.4", "vi"},
+		{"only q=0", "en;q=0", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, profileLanguage(tt.pref))
+		})
+	}
+}
+
+func TestResolveLanguage(t *testing.T) {
+	// Detected language wins over the profile
+	assert.Equal(t, "en", resolveLanguage("What is the cutoff score?", "vi", "vi"))
+	// Ambiguous text falls back to the profile, then the configured default
+	assert.Equal(t, "en", resolveLanguage("ok", "en-US", "vi"))
+	assert.Equal(t, "vi", resolveLanguage("ok", "", "vi"))
+}
+
+func TestBuildPrompt_LanguageInstruction(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"vietnamese input", "Tôi nên học ngành gì?", languageInstructions[langVietnamese]},
+		{"english input", "What should I study?", languageInstructions[langEnglish]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lang := resolveLanguage(tt.text, "", "vi")
+			prompt := buildPrompt(lang, "User ILO profile: Top domains: R. ", "", tt.text)
+
+			assert.True(t, strings.HasPrefix(prompt, tt.want), "prompt %q should start with %q", prompt, tt.want)
+			assert.True(t, strings.HasSuffix(prompt, tt.text))
+			assert.Contains(t, prompt, "User ILO profile")
+		})
+	}
+}
+
+func TestBuildPrompt_UnknownLanguage(t *testing.T) {
+	assert.Equal(t, "hello", buildPrompt("", "", "", "hello"))
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	pbllm "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/status"
+)
+
+// verifyNoLeaks fails t if goroutines started after this call still run
+// once the test and its cleanups, which stop the servers
+// newTestChatServer starts, are done. Call it before newTestChatServer.
+func verifyNoLeaks(t *testing.T) {
+	t.Helper()
+	running := goleak.IgnoreCurrent()
+	t.Cleanup(func() { goleak.VerifyNone(t, running) })
+}
+
+// stallingLLMServer sends the start of an answer, then holds the rest back
+// until the call is cancelled.
+type stallingLLMServer struct {
+	pbllm.UnimplementedLLMServiceServer
+}
+
+func (stallingLLMServer) GenerateWithRAG(req *pbllm.GenerateWithRAGRequest, stream pbllm.LLMService_GenerateWithRAGServer) error {
+	if err := stream.Send(&pbllm.GenerateWithRAGResponse{Token: "Soft"}); err != nil {
+		return err
+	}
+	<-stream.Context().Done()
+	return status.FromContextError(stream.Context().Err()).Err()
+}
+
+// brokenChatStream is a stream whose api-gateway side is gone: sends fail.
+type brokenChatStream struct {
+	*fakeChatStream
+}
+
+func (brokenChatStream) Send(*pbChat.StreamResponse) error {
+	return errors.New("api-gateway gone")
+}
+
+func TestStream_NoGoroutineLeaks(t *testing.T) {
+	question := &pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Which careers suit me?"}
+
+	t.Run("client ends the stream", func(t *testing.T) {
+		verifyNoLeaks(t)
+		s := newTestChatServer(t, &fakeLLMServer{})
+		require.NoError(t, s.Stream(newUserStream(question)))
+	})
+
+	t.Run("client disconnects mid-answer", func(t *testing.T) {
+		verifyNoLeaks(t)
+		s := newTestChatServer(t, stallingLLMServer{})
+		ctx, cancel := context.WithCancel(userContext("user-1"))
+		defer cancel()
+		stream := &fakeChatStream{ctx: ctx, reqs: make(chan *pbChat.StreamRequest, 1)}
+		stream.reqs <- question
+
+		done := make(chan error, 1)
+		go func() { done <- s.Stream(stream) }()
+		require.Eventually(t, func() bool {
+			stream.mu.Lock()
+			defer stream.mu.Unlock()
+			return len(stream.sent) > 0
+		}, 5*time.Second, 10*time.Millisecond, "the answer never started")
+
+		cancel()
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Stream did not return after the client disconnected")
+		}
+	})
+
+	t.Run("LLM fails", func(t *testing.T) {
+		verifyNoLeaks(t)
+		s := newTestChatServer(t, busyLLMServer{})
+		require.NoError(t, s.Stream(newUserStream(question, question)))
+	})
+
+	t.Run("api-gateway is gone", func(t *testing.T) {
+		verifyNoLeaks(t)
+		s := newTestChatServer(t, &fakeLLMServer{})
+		// The requests never end, so only the failed send ends the stream
+		stream := &fakeChatStream{ctx: userContext("user-1"), reqs: make(chan *pbChat.StreamRequest, 1)}
+		stream.reqs <- question
+		require.NoError(t, s.Stream(brokenChatStream{stream}))
+	})
+
+	t.Run("api-gateway stops reading", func(t *testing.T) {
+		verifyNoLeaks(t)
+		s := newTestChatServer(t, stallingLLMServer{})
+		s.cfg.Chat.SendTimeout = 20 * time.Millisecond
+		ctx, cancel := context.WithCancel(userContext("user-1"))
+		stream := &fakeChatStream{ctx: ctx, reqs: make(chan *pbChat.StreamRequest, 1)}
+		stream.reqs <- question
+		require.NoError(t, s.Stream(stuckChatStream{stream}))
+		// gRPC ends the stream once Stream returns, which ends the stuck send
+		cancel()
+	})
+}
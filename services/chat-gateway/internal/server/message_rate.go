@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+)
+
+// throttleSweepInterval is how often limiters that are back to a full
+// burst, and so no different from new ones, are dropped.
+const throttleSweepInterval = time.Minute
+
+// messageLimiter limits how often messages, each of which may start a RAG
+// answer, are sent in a conversation and by a user across their
+// conversations.
+type messageLimiter interface {
+	// allow takes a message of userID in convID against the limits of cfg.
+	// It returns 0 when the message may be answered, else how long until
+	// it may be sent again; a refused message counts against neither
+	// limit.
+	allow(ctx context.Context, cfg config.MessageRateConfig, userID, convID string) (time.Duration, error)
+}
+
+// messageThrottle is a messageLimiter covering this instance only.
+type messageThrottle struct {
+	mu            sync.Mutex
+	conversations map[string]*rate.Limiter
+	users         map[string]*rate.Limiter
+	lastSweep     time.Time
+	now           func() time.Time
+}
+
+func newMessageThrottle() *messageThrottle {
+	return &messageThrottle{
+		conversations: make(map[string]*rate.Limiter),
+		users:         make(map[string]*rate.Limiter),
+		now:           time.Now,
+	}
+}
+
+func (t *messageThrottle) allow(ctx context.Context, cfg config.MessageRateConfig, userID, convID string) (time.Duration, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	t.sweep(now)
+
+	var reservations []*rate.Reservation
+	var wait time.Duration
+	take := func(limiters map[string]*rate.Limiter, key string, burst int, interval time.Duration) {
+		if interval <= 0 {
+			return
+		}
+		limiter, ok := limiters[key]
+		if !ok {
+			limiter = rate.NewLimiter(rate.Every(interval), burst)
+			limiters[key] = limiter
+		}
+		r := limiter.ReserveN(now, 1)
+		reservations = append(reservations, r)
+		wait = max(wait, r.DelayFrom(now))
+	}
+	if convID != "" {
+		// Keyed by user too, so nobody can use up another's conversation
+		take(t.conversations, userID+"/"+convID, cfg.ConversationBurst, cfg.ConversationInterval)
+	}
+	take(t.users, userID, cfg.UserBurst, cfg.UserInterval)
+
+	if wait > 0 {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+	}
+	return wait, nil
+}
+
+// sweep drops the limiters with a full burst once every
+// throttleSweepInterval, so idle conversations do not pile up.
+func (t *messageThrottle) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < throttleSweepInterval {
+		return
+	}
+	t.lastSweep = now
+	for _, limiters := range []map[string]*rate.Limiter{t.conversations, t.users} {
+		for key, limiter := range limiters {
+			if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+				delete(limiters, key)
+			}
+		}
+	}
+}
+
+// throttleScript applies the limits of a redisMessageThrottle with the
+// generic cell rate algorithm. Each key holds the time, in milliseconds,
+// at which its limit is back to a full burst; ARGV holds the current time
+// then each key's interval and burst. It returns how many milliseconds to
+// wait, 0 having taken the message against every limit.
+var throttleScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local wait = 0
+local full = {}
+for i, key in ipairs(KEYS) do
+  local interval = tonumber(ARGV[2 * i])
+  local burst = tonumber(ARGV[2 * i + 1])
+  full[i] = math.max(tonumber(redis.call("GET", key) or now), now) + interval
+  wait = math.max(wait, full[i] - burst * interval - now)
+end
+if wait > 0 then
+  return wait
+end
+for i, key in ipairs(KEYS) do
+  redis.call("SET", key, full[i], "PX", full[i] - now)
+end
+return 0`)
+
+// redisMessageThrottle is a messageLimiter shared by every replica.
+type redisMessageThrottle struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+func newRedisMessageThrottle(client *redis.Client) *redisMessageThrottle {
+	return &redisMessageThrottle{client: client, now: time.Now}
+}
+
+func (t *redisMessageThrottle) allow(ctx context.Context, cfg config.MessageRateConfig, userID, convID string) (time.Duration, error) {
+	var keys []string
+	args := []any{t.now().UnixMilli()}
+	limit := func(key string, burst int, interval time.Duration) {
+		if interval <= 0 {
+			return
+		}
+		keys = append(keys, key)
+		args = append(args, interval.Milliseconds(), burst)
+	}
+	if convID != "" {
+		limit("chat:message_rate:conversation:"+userID+"/"+convID, cfg.ConversationBurst, cfg.ConversationInterval)
+	}
+	limit("chat:message_rate:user:"+userID, cfg.UserBurst, cfg.UserInterval)
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	wait, err := throttleScript.Run(ctx, t.client, keys, args...).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
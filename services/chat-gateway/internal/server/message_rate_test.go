@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/careerup-Inc/careerup-monorepo/pkg/chaterr"
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newClockedThrottle returns a throttle whose time only moves with the
+// returned advance.
+func newClockedThrottle() (*messageThrottle, func(time.Duration)) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	t := newMessageThrottle()
+	t.now = func() time.Time { return now }
+	return t, func(d time.Duration) { now = now.Add(d) }
+}
+
+// clockedLimiter returns a limiter whose time only moves with the returned
+// advance, for one of the backends.
+type clockedLimiter func(t *testing.T) (messageLimiter, func(time.Duration))
+
+func newClockedRedisThrottle(t *testing.T) (messageLimiter, func(time.Duration)) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	throttle := newRedisMessageThrottle(newTestRedis(t))
+	throttle.now = func() time.Time { return now }
+	return throttle, func(d time.Duration) { now = now.Add(d) }
+}
+
+// allowed takes a message against limiter, which must not fail.
+func allowed(t *testing.T, limiter messageLimiter, cfg config.MessageRateConfig, userID, convID string) time.Duration {
+	t.Helper()
+	wait, err := limiter.allow(context.Background(), cfg, userID, convID)
+	require.NoError(t, err)
+	return wait
+}
+
+func TestMessageThrottle(t *testing.T) {
+	backends := map[string]clockedLimiter{
+		"memory": func(*testing.T) (messageLimiter, func(time.Duration)) { return newClockedThrottle() },
+		"redis":  newClockedRedisThrottle,
+	}
+	for name, newLimiter := range backends {
+		t.Run(name, func(t *testing.T) { testMessageLimits(t, newLimiter) })
+	}
+
+	t.Run("limits off", func(t *testing.T) {
+		throttle, _ := newClockedThrottle()
+		for range 100 {
+			require.Zero(t, allowed(t, throttle, config.MessageRateConfig{}, "user-1", "conv-1"))
+		}
+		assert.Empty(t, throttle.conversations)
+		assert.Empty(t, throttle.users)
+	})
+
+	t.Run("idle limiters are dropped", func(t *testing.T) {
+		throttle, advance := newClockedThrottle()
+		cfg := config.MessageRateConfig{ConversationBurst: 2, ConversationInterval: time.Second, UserBurst: 2, UserInterval: time.Second}
+		allowed(t, throttle, cfg, "user-1", "conv-1")
+		advance(throttleSweepInterval)
+		allowed(t, throttle, cfg, "user-2", "conv-2")
+
+		assert.Len(t, throttle.conversations, 1)
+		assert.Contains(t, throttle.users, "user-2")
+		assert.NotContains(t, throttle.users, "user-1")
+	})
+}
+
+// testMessageLimits checks the limits every messageLimiter applies.
+func testMessageLimits(t *testing.T, newLimiter clockedLimiter) {
+	t.Run("burst beyond the conversation limit", func(t *testing.T) {
+		throttle, advance := newLimiter(t)
+		cfg := config.MessageRateConfig{ConversationBurst: 2, ConversationInterval: 3 * time.Second}
+
+		assert.Zero(t, allowed(t, throttle, cfg, "user-1", "conv-1"))
+		assert.Zero(t, allowed(t, throttle, cfg, "user-1", "conv-1"))
+		assert.Equal(t, 3*time.Second, allowed(t, throttle, cfg, "user-1", "conv-1"))
+		assert.Zero(t, allowed(t, throttle, cfg, "user-1", "conv-2"), "other conversations are limited separately")
+		assert.Zero(t, allowed(t, throttle, cfg, "user-2", "conv-1"), "other users are limited separately")
+
+		advance(time.Second)
+		assert.Equal(t, 2*time.Second, allowed(t, throttle, cfg, "user-1", "conv-1"))
+		advance(2 * time.Second)
+		assert.Zero(t, allowed(t, throttle, cfg, "user-1", "conv-1"))
+	})
+
+	t.Run("normal cadence passes", func(t *testing.T) {
+		throttle, advance := newLimiter(t)
+		cfg := config.MessageRateConfig{ConversationBurst: 1, ConversationInterval: 3 * time.Second, UserBurst: 1, UserInterval: time.Second}
+
+		for i := range 20 {
+			require.Zero(t, allowed(t, throttle, cfg, "user-1", "conv-1"), "message %d", i)
+			advance(3 * time.Second)
+		}
+	})
+
+	t.Run("user limit across conversations", func(t *testing.T) {
+		throttle, _ := newLimiter(t)
+		cfg := config.MessageRateConfig{
+			ConversationBurst: 2, ConversationInterval: time.Minute,
+			UserBurst: 3, UserInterval: 2 * time.Second,
+		}
+
+		for _, conv := range []string{"conv-1", "conv-2", "conv-3"} {
+			require.Zero(t, allowed(t, throttle, cfg, "user-1", conv))
+		}
+		assert.Equal(t, 2*time.Second, allowed(t, throttle, cfg, "user-1", "conv-4"))
+
+		// The refused message took nothing from conv-1's limit
+		throttle, advance := newLimiter(t)
+		cfg.UserBurst = 1
+		require.Zero(t, allowed(t, throttle, cfg, "user-1", "conv-1"))
+		assert.Equal(t, 2*time.Second, allowed(t, throttle, cfg, "user-1", "conv-1"))
+		advance(2 * time.Second)
+		assert.Zero(t, allowed(t, throttle, cfg, "user-1", "conv-1"), "conv-1 still has its second message")
+	})
+}
+
+func TestStream_BurstIsThrottled(t *testing.T) {
+	llmServer := &fakeLLMServer{}
+	s := newTestChatServer(t, llmServer)
+	s.cfg.Chat.MessageRate = config.MessageRateConfig{ConversationBurst: 2, ConversationInterval: time.Minute}
+
+	stream := newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "first"},
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "second"},
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "third"},
+	)
+	require.NoError(t, s.Stream(stream))
+
+	last := stream.sent[len(stream.sent)-1]
+	assert.Equal(t, "error", last.GetType())
+	assert.Equal(t, string(chaterr.MessageRateLimited), last.GetErrorCode())
+	assert.Equal(t, int32(60), last.GetRetryAfterSeconds())
+	assert.Contains(t, last.GetErrorMessage(), "try again in 60 seconds")
+	assert.Equal(t, []string{
+		"user: first", "assistant: Answer 1",
+		"user: second", "assistant: Answer 2",
+	}, historyTexts(t, s, "conv-1"), "the throttled message is not answered")
+	assert.Len(t, llmServer.requests, 2)
+}
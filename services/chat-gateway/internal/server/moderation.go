@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+)
+
+// moderationDecision is a moderator's verdict on one user message.
+type moderationDecision struct {
+	Flagged bool
+	// Categories lists the configured categories the message matched
+	Categories []string
+}
+
+// moderator screens user messages before they reach the model.
+type moderator interface {
+	Moderate(ctx context.Context, text string) (moderationDecision, error)
+}
+
+// refusalMessages are sent in place of an answer when a message is flagged.
+var refusalMessages = map[string]string{
+	langVietnamese: "Xin lỗi, mình không thể hỗ trợ nội dung này. Nếu bạn đang gặp khó khăn, hãy chia sẻ với người thân hoặc một chuyên gia để được giúp đỡ. Mình luôn sẵn sàng trao đổi với bạn về học tập và định hướng nghề nghiệp.",
+	langEnglish:    "Sorry, I can't help with that. If you are going through a hard time, please reach out to someone you trust or a professional. I'm always happy to talk about your studies and career plans.",
+}
+
+// keywordModerator flags messages containing any configured phrase. Phrases
+// match on whole words, so "kill" does not flag "skill".
+type keywordModerator struct {
+	categories map[string][]string // category -> normalized phrases
+}
+
+func newKeywordModerator(categories map[string][]string) *keywordModerator {
+	m := &keywordModerator{categories: make(map[string][]string, len(categories))}
+	for category, phrases := range categories {
+		for _, phrase := range phrases {
+			if normalized := normalizeWords(phrase); normalized != "" {
+				m.categories[category] = append(m.categories[category], normalized)
+			}
+		}
+	}
+	return m
+}
+
+func (m *keywordModerator) Moderate(_ context.Context, text string) (moderationDecision, error) {
+	// Padding lets a phrase match only at word boundaries
+	padded := " " + normalizeWords(text) + " "
+	var decision moderationDecision
+	for category, phrases := range m.categories {
+		for _, phrase := range phrases {
+			if strings.Contains(padded, " "+phrase+" ") {
+				decision.Categories = append(decision.Categories, category)
+				break
+			}
+		}
+	}
+	sort.Strings(decision.Categories)
+	decision.Flagged = len(decision.Categories) > 0
+	return decision, nil
+}
+
+// normalizeWords lowercases s and collapses everything but letters and digits
+// into single spaces.
+func normalizeWords(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// screenMessage runs the moderation pre-check on a user message and, when it
+// is flagged, answers with a refusal in lang instead. It reports whether the
+// message was refused; sendErr means api-gateway can no longer be reached.
+// A failing moderator lets the message through so an outage does not take
+// chat down with it.
+func (s *ChatServer) screenMessage(ctx context.Context, userID, conversationID, lang, text string, send func(*pbChat.StreamResponse) error) (refused bool, sendErr error) {
+	if s.moderator == nil {
+		return false, nil
+	}
+
+	decision, err := s.moderator.Moderate(ctx, text)
+	if err != nil {
+		log.Printf("Moderation check failed for user %s (conv %s), allowing message: %v", userID, conversationID, err)
+		return false, nil
+	}
+	if !decision.Flagged {
+		log.Printf("Moderation: allowed message from user %s (conv %s)", userID, conversationID)
+		return false, nil
+	}
+
+	log.Printf("Moderation: refused message from user %s (conv %s), categories=%s", userID, conversationID, strings.Join(decision.Categories, ","))
+	refusal, ok := refusalMessages[lang]
+	if !ok {
+		refusal = refusalMessages[langEnglish]
+	}
+	return true, send(&pbChat.StreamResponse{
+		Type:    "assistant_token",
+		Content: &pbChat.StreamResponse_Token{Token: refusal},
+	})
+}
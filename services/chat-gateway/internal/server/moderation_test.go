@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockModerator returns a canned decision and records what it screened.
+type mockModerator struct {
+	decision moderationDecision
+	err      error
+	seen     []string
+}
+
+func (m *mockModerator) Moderate(_ context.Context, text string) (moderationDecision, error) {
+	m.seen = append(m.seen, text)
+	return m.decision, m.err
+}
+
+func screen(t *testing.T, s *ChatServer, lang, text string) (bool, []*pbChat.StreamResponse) {
+	t.Helper()
+	var sent []*pbChat.StreamResponse
+	refused, err := s.screenMessage(context.Background(), "user-1", "conv-1", lang, text, func(res *pbChat.StreamResponse) error {
+		sent = append(sent, res)
+		return nil
+	})
+	require.NoError(t, err)
+	return refused, sent
+}
+
+func TestScreenMessage_RefusesFlaggedContent(t *testing.T) {
+	mod := &mockModerator{decision: moderationDecision{Flagged: true, Categories: []string{"violence"}}}
+	refused, sent := screen(t, &ChatServer{moderator: mod}, langVietnamese, "flagged text")
+
+	assert.True(t, refused)
+	assert.Equal(t, []string{"flagged text"}, mod.seen)
+	require.Len(t, sent, 1)
+	assert.Equal(t, "assistant_token", sent[0].Type)
+	assert.Equal(t, refusalMessages[langVietnamese], sent[0].GetToken())
+}
+
+func TestScreenMessage_PassesBenignContent(t *testing.T) {
+	refused, sent := screen(t, &ChatServer{moderator: &mockModerator{}}, langEnglish, "Which majors suit me?")
+
+	assert.False(t, refused)
+	assert.Empty(t, sent)
+}
+
+func TestScreenMessage_FailsOpen(t *testing.T) {
+	mod := &mockModerator{err: errors.New("moderation backend down")}
+	refused, sent := screen(t, &ChatServer{moderator: mod}, langEnglish, "anything")
+
+	assert.False(t, refused)
+	assert.Empty(t, sent)
+}
+
+func TestScreenMessage_Disabled(t *testing.T) {
+	refused, sent := screen(t, &ChatServer{}, langEnglish, "anything")
+
+	assert.False(t, refused)
+	assert.Empty(t, sent)
+}
+
+func TestKeywordModerator(t *testing.T) {
+	mod := newKeywordModerator(map[string][]string{
+		"self_harm": {"Tự tử", "kill myself"},
+		"violence":  {"kill"},
+	})
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"vietnamese phrase", "Em muốn tự tử.", []string{"self_harm"}},
+		{"several categories", "I want to KILL myself", []string{"self_harm", "violence"}},
+		{"matches whole words only", "Which skills does a data analyst need?", nil},
+		{"benign", "Ngành nào phù hợp với em?", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := mod.Moderate(context.Background(), tt.text)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, decision.Categories)
+			assert.Equal(t, tt.want != nil, decision.Flagged)
+		})
+	}
+}
+
+func TestHandleMessage_RefusedMessageFetchesNoIloContext(t *testing.T) {
+	iloServer := &fakeIloServer{}
+	s := newIloChatServer(t, iloServer, config.IloConfig{Timeout: time.Second})
+	s.moderator = &mockModerator{decision: moderationDecision{Flagged: true}}
+
+	var sent []*pbChat.StreamResponse
+	ok := s.handleMessage(context.Background(), func(res *pbChat.StreamResponse) error {
+		sent = append(sent, res)
+		return nil
+	}, nil, "user-1", "", &pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "flagged text"})
+
+	assert.True(t, ok)
+	require.NotEmpty(t, sent)
+	assert.Equal(t, refusalMessages[langEnglish], sent[0].GetToken())
+	assert.Zero(t, iloServer.callCount(), "ILO context is fetched only for screened messages")
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// ServerOptions returns the gRPC server options for cfg's message size
+// limits and keepalive policy.
+func ServerOptions(cfg config.ServerConfig) []grpc.ServerOption {
+	k := cfg.Keepalive
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.MaxSendMsgSize),
+		grpc.KeepaliveParams(keepalive.ServerParameters{Time: k.Time, Timeout: k.Timeout}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             k.MinPingInterval,
+			PermitWithoutStream: k.PermitWithoutStream,
+		}),
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// serveChat serves s with opts and returns a client for it whose own
+// message limits are well above the server's.
+func serveChat(t *testing.T, s *ChatServer, opts ...grpc.ServerOption) pbChat.ConversationServiceClient {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer(opts...)
+	pbChat.RegisterConversationServiceServer(srv, s)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pbChat.NewConversationServiceClient(conn)
+}
+
+func TestServerOptions_MessageSizeLimits(t *testing.T) {
+	s := newTestChatServer(t, &fakeLLMServer{})
+	c := serveChat(t, s, ServerOptions(config.ServerConfig{
+		MaxRecvMsgSize: 1024,
+		MaxSendMsgSize: 1024,
+		Keepalive:      config.KeepaliveConfig{Time: time.Minute, Timeout: 20 * time.Second, MinPingInterval: 10 * time.Second},
+	})...)
+	ctx, cancel := context.WithTimeout(metadata.AppendToOutgoingContext(context.Background(), "user-id", "user-1"), 5*time.Second)
+	defer cancel()
+
+	get := func(convID string) error {
+		_, err := c.GetConversation(ctx, &pbChat.GetConversationRequest{ConversationId: convID})
+		return err
+	}
+
+	t.Run("request just under the limit", func(t *testing.T) {
+		// 1000 bytes of ID plus 3 of framing; the handler answers it
+		assert.Equal(t, codes.NotFound, status.Code(get(strings.Repeat("c", 1000))))
+	})
+
+	t.Run("request over the limit", func(t *testing.T) {
+		assert.Equal(t, codes.ResourceExhausted, status.Code(get(strings.Repeat("c", 1100))))
+	})
+
+	t.Run("response over the limit", func(t *testing.T) {
+		require.NoError(t, s.history.record(context.Background(), "conv-1", "user-1", roleUser, strings.Repeat("x", 2000), nil))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(get("conv-1")))
+	})
+
+	t.Run("response under the limit", func(t *testing.T) {
+		require.NoError(t, s.history.record(context.Background(), "conv-2", "user-1", roleUser, "Which careers suit me?", nil))
+		assert.NoError(t, get("conv-2"))
+	})
+}
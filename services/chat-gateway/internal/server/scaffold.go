@@ -0,0 +1,95 @@
+package server
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scaffoldStripper removes prompt scaffolding ("Answer:", "Context from
+// retrieved sources:", ...) that the model sometimes echoes at the start of
+// a streamed response. Only the beginning of the stream is inspected, and at
+// most the longest pattern is buffered before tokens flow through unchanged.
+type scaffoldStripper struct {
+	patterns []string
+	buf      string
+	stripped bool
+	done     bool
+}
+
+func newScaffoldStripper(patterns []string) *scaffoldStripper {
+	// Empty patterns would match forever
+	nonEmpty := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return &scaffoldStripper{patterns: nonEmpty, done: len(nonEmpty) == 0}
+}
+
+// Push accepts the next token and returns the text that is safe to emit now,
+// which may be empty while the stripper is still deciding.
+func (s *scaffoldStripper) Push(token string) string {
+	if s.done {
+		return token
+	}
+	s.buf += token
+
+	for {
+		rest := strings.TrimLeftFunc(s.buf, unicode.IsSpace)
+		matched := false
+		for _, p := range s.patterns {
+			if hasPrefixFold(rest, p) {
+				s.buf = strings.TrimLeftFunc(rest[len(p):], unicode.IsSpace)
+				matched, s.stripped = true, true
+				break
+			}
+		}
+		if !matched {
+			break
+		}
+	}
+
+	rest := strings.TrimLeftFunc(s.buf, unicode.IsSpace)
+	if rest == "" {
+		return ""
+	}
+	for _, p := range s.patterns {
+		if couldBecomePrefix(rest, p) {
+			// Still ambiguous, wait for more tokens
+			return ""
+		}
+	}
+
+	s.done = true
+	out := s.buf
+	if s.stripped {
+		out = rest
+	}
+	s.buf = ""
+	return out
+}
+
+// Flush returns whatever is still buffered when the stream ends.
+func (s *scaffoldStripper) Flush() string {
+	out := s.buf
+	s.buf = ""
+	s.done = true
+	return out
+}
+
+// Reset discards anything buffered and inspects the next token as the start
+// of a new stream.
+func (s *scaffoldStripper) Reset() {
+	s.buf = ""
+	s.stripped = false
+	s.done = len(s.patterns) == 0
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+func couldBecomePrefix(s, pattern string) bool {
+	return len(s) < len(pattern) && strings.EqualFold(s, pattern[:len(s)])
+}
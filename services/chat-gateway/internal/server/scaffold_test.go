@@ -0,0 +1,99 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runStripper(patterns []string, tokens []string) string {
+	s := newScaffoldStripper(patterns)
+	var out strings.Builder
+	for _, tok := range tokens {
+		out.WriteString(s.Push(tok))
+	}
+	out.WriteString(s.Flush())
+	return out.String()
+}
+
+func TestScaffoldStripper(t *testing.T) {
+	patterns := []string{"Answer:", "Context from retrieved sources:", "Trả lời:"}
+
+	tests := []struct {
+		name   string
+		tokens []string
+		want   string
+	}{
+		{
+			name:   "no scaffolding passes through",
+			tokens: []string{"The", " score", " is", " 27.5"},
+			want:   "The score is 27.5",
+		},
+		{
+			name:   "prefix in a single token",
+			tokens: []string{"Answer:", " The", " score"},
+			want:   "The score",
+		},
+		{
+			name:   "prefix split across tokens",
+			tokens: []string{"Ans", "wer", ":", " Hello"},
+			want:   "Hello",
+		},
+		{
+			name:   "leading whitespace before prefix",
+			tokens: []string{"\n", "Answer", ": Hi"},
+			want:   "Hi",
+		},
+		{
+			name:   "case insensitive",
+			tokens: []string{"answer: ", "ok"},
+			want:   "ok",
+		},
+		{
+			name:   "repeated scaffolding",
+			tokens: []string{"Context from retrieved sources:", "\nAnswer:", " yes"},
+			want:   "yes",
+		},
+		{
+			name:   "vietnamese prefix",
+			tokens: []string{"Trả ", "lời:", " Điểm chuẩn"},
+			want:   "Điểm chuẩn",
+		},
+		{
+			name:   "word sharing a prefix is kept",
+			tokens: []string{"Ans", "ible", " is not a word"},
+			want:   "Ansible is not a word",
+		},
+		{
+			name:   "scaffolding later in the stream is kept",
+			tokens: []string{"Hi.", " Answer:", " 42"},
+			want:   "Hi. Answer: 42",
+		},
+		{
+			name:   "stream ends while ambiguous",
+			tokens: []string{"Ans"},
+			want:   "Ans",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, runStripper(patterns, tt.tokens))
+		})
+	}
+}
+
+func TestScaffoldStripper_BuffersOnlyAtStart(t *testing.T) {
+	s := newScaffoldStripper([]string{"Answer:"})
+
+	assert.Equal(t, "", s.Push("Ans"))
+	assert.Equal(t, "Hello", s.Push("wer: Hello"))
+	// Once decided, tokens are emitted immediately
+	assert.Equal(t, " Answer:", s.Push(" Answer:"))
+}
+
+func TestScaffoldStripper_NoPatterns(t *testing.T) {
+	s := newScaffoldStripper(nil)
+	assert.Equal(t, "Answer: x", s.Push("Answer: x"))
+}
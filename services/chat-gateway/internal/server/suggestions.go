@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	pbllm "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+)
+
+// typeSuggestions is the response carrying follow-up questions, sent
+// after the last token of a completed answer.
+const typeSuggestions = "suggestions"
+
+// suggestionsSchema is llm-gateway's structured output schema for
+// follow-up questions.
+const suggestionsSchema = "follow_up_questions"
+
+// suggestionsAnswerMaxBytes bounds how much of the answer the light model
+// is shown; the start of an answer says what it is about.
+const suggestionsAnswerMaxBytes = 4000
+
+// suggestionsPrompts ask for follow-up questions in each response language,
+// given the count, the user's question and the answer.
+var suggestionsPrompts = map[string]string{
+	langVietnamese: "Đề xuất %d câu hỏi ngắn bằng tiếng Việt mà học sinh có thể hỏi tiếp sau câu trả lời dưới đây, về học tập và định hướng nghề nghiệp. Không lặp lại câu hỏi ban đầu.\n\nCâu hỏi: %s\n\nCâu trả lời: %s",
+	langEnglish:    "Suggest %d short questions in English the student might ask next after the answer below, about their studies and career plans. Do not repeat the original question.\n\nQuestion: %s\n\nAnswer: %s",
+}
+
+// suggestionsPrompt returns the prompt asking for count follow-up questions
+// in lang to question and its answer.
+func suggestionsPrompt(lang, question, answer string, count int) string {
+	prompt, ok := suggestionsPrompts[lang]
+	if !ok {
+		prompt = suggestionsPrompts[langEnglish]
+	}
+	return fmt.Sprintf(prompt, count, question, truncateText(answer, suggestionsAnswerMaxBytes))
+}
+
+// suggestFollowUps asks llm-gateway's light model for questions the user
+// may ask after answer, when suggestions are enabled. Suggestions are an
+// extra: failures are logged and yield none.
+func (s *ChatServer) suggestFollowUps(ctx context.Context, userID, lang, question, answer string) []string {
+	cfg := s.cfg.Chat.Suggestions
+	if !cfg.Enabled || answer == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	res, err := s.llmClient.GetLLMServiceClient().GenerateStructured(ctx, &pbllm.GenerateStructuredRequest{
+		Prompt: suggestionsPrompt(lang, question, answer, cfg.Count),
+		UserId: userID,
+		Schema: suggestionsSchema,
+		Light:  true,
+	})
+	if err != nil {
+		log.Printf("Failed to generate follow-up questions for user %s: %v", userID, err)
+		return nil
+	}
+	questions := make([]string, 0, cfg.Count)
+	for _, q := range res.GetFollowUpQuestions() {
+		if q = strings.TrimSpace(q); q != "" && len(questions) < cfg.Count {
+			questions = append(questions, q)
+		}
+	}
+	return questions
+}
+
+// followUps sends the follow-up questions to a stream's answers, generated
+// in the background so the stream goes on receiving meanwhile. Questions to
+// an answer are dropped once the user sends another message, which is
+// answered instead.
+type followUps struct {
+	mu sync.Mutex
+	// Of the questions being generated; nil when there are none
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// start generates questions with generate and sends them, if any, unless
+// they are dropped first. Questions still pending are dropped.
+func (f *followUps) start(ctx context.Context, send func(*pbChat.StreamResponse) error, generate func(context.Context) []string) {
+	f.drop()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	f.mu.Lock()
+	f.cancel, f.done = cancel, done
+	f.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		defer cancel()
+		questions := generate(ctx)
+		if len(questions) == 0 {
+			return
+		}
+		// Held while sending, so a message dropping the questions waits
+		// until they are sent rather than having them follow its answer
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		if err := send(&pbChat.StreamResponse{Type: typeSuggestions, Suggestions: questions}); err != nil {
+			// The stream is broken: its next send fails as well and ends it
+			log.Printf("Failed to send follow-up questions back to api-gateway: %v", err)
+		}
+	}()
+}
+
+// drop cancels the pending questions, unless already sent, and waits for
+// them to end.
+func (f *followUps) drop() {
+	f.mu.Lock()
+	if f.cancel != nil {
+		f.cancel()
+	}
+	done := f.done
+	f.cancel, f.done = nil, nil
+	f.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// wait waits for the pending questions to be sent or given up on.
+func (f *followUps) wait() {
+	f.mu.Lock()
+	done := f.done
+	f.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
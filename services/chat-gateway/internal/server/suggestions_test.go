@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	pbllm "github.com/careerup-Inc/careerup-monorepo/proto/llm/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// suggestingLLMServer answers like fakeLLMServer and suggests questions as
+// follow-ups to every answer.
+type suggestingLLMServer struct {
+	*fakeLLMServer
+	questions  []string
+	mu         sync.Mutex
+	structured []*pbllm.GenerateStructuredRequest
+}
+
+func (f *suggestingLLMServer) GenerateStructured(_ context.Context, req *pbllm.GenerateStructuredRequest) (*pbllm.GenerateStructuredResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.structured = append(f.structured, req)
+	return &pbllm.GenerateStructuredResponse{FollowUpQuestions: f.questions}, nil
+}
+
+func enableSuggestions(s *ChatServer, count int) {
+	s.cfg.Chat.Suggestions = config.SuggestionsConfig{Enabled: true, Count: count, Timeout: 5 * time.Second}
+}
+
+func TestStream_SuggestsFollowUps(t *testing.T) {
+	llmServer := &suggestingLLMServer{
+		fakeLLMServer: &fakeLLMServer{},
+		questions:     []string{"What are the tuition fees?", " ", "Is there a dormitory?", "Which majors need physics?"},
+	}
+	s := newTestChatServer(t, llmServer)
+	enableSuggestions(s, 2)
+
+	stream := newUserStream(&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "What was the HUST cutoff?"})
+	require.NoError(t, s.Stream(stream))
+
+	require.GreaterOrEqual(t, len(stream.sent), 2)
+	last := stream.sent[len(stream.sent)-1]
+	assert.Equal(t, typeSuggestions, last.GetType())
+	assert.Equal(t, []string{"What are the tuition fees?", "Is there a dormitory?"}, last.GetSuggestions(), "blank questions are dropped and the rest capped at the count")
+	assert.Equal(t, "Answer 1", stream.sent[len(stream.sent)-2].GetToken(), "suggestions follow the last token")
+
+	require.Len(t, llmServer.structured, 1)
+	req := llmServer.structured[0]
+	assert.Equal(t, suggestionsSchema, req.GetSchema())
+	assert.True(t, req.GetLight(), "suggestions are generated by the light model")
+	assert.Equal(t, "user-1", req.GetUserId())
+	assert.Contains(t, req.GetPrompt(), "What was the HUST cutoff?")
+	assert.Contains(t, req.GetPrompt(), "Answer 1")
+}
+
+func TestStream_SuggestionsDisabled(t *testing.T) {
+	llmServer := &suggestingLLMServer{fakeLLMServer: &fakeLLMServer{}, questions: []string{"What are the tuition fees?"}}
+	s := newTestChatServer(t, llmServer)
+
+	stream := newUserStream(&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "What was the HUST cutoff?"})
+	require.NoError(t, s.Stream(stream))
+
+	assert.Empty(t, llmServer.structured)
+	for _, res := range stream.sent {
+		assert.NotEqual(t, typeSuggestions, res.GetType())
+	}
+}
+
+func TestStream_SuggestionsFailureIsQuiet(t *testing.T) {
+	// fakeLLMServer does not implement GenerateStructured
+	s := newTestChatServer(t, &fakeLLMServer{})
+	enableSuggestions(s, 3)
+
+	stream := newUserStream(&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "What was the HUST cutoff?"})
+	require.NoError(t, s.Stream(stream))
+
+	require.NotEmpty(t, stream.sent)
+	for _, res := range stream.sent {
+		assert.NotEqual(t, "error", res.GetType(), res.GetErrorMessage())
+		assert.NotEqual(t, typeSuggestions, res.GetType())
+	}
+	assert.Equal(t, "Answer 1", stream.sent[len(stream.sent)-1].GetToken())
+}
+
+// slowSuggestingLLMServer suggests questions like suggestingLLMServer,
+// except to questions mentioning stall, whose suggestions take until the call
+// is cancelled.
+type slowSuggestingLLMServer struct {
+	*suggestingLLMServer
+}
+
+func (f *slowSuggestingLLMServer) GenerateStructured(ctx context.Context, req *pbllm.GenerateStructuredRequest) (*pbllm.GenerateStructuredResponse, error) {
+	if strings.Contains(req.GetPrompt(), "stall") {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return f.suggestingLLMServer.GenerateStructured(ctx, req)
+}
+
+func TestStream_NewMessageDropsPendingSuggestions(t *testing.T) {
+	llmServer := &slowSuggestingLLMServer{&suggestingLLMServer{
+		fakeLLMServer: &fakeLLMServer{},
+		questions:     []string{"Is there a dormitory?"},
+	}}
+	s := newTestChatServer(t, llmServer)
+	enableSuggestions(s, 3)
+
+	stream := newUserStream(
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "Which universities stall admissions?"},
+		&pbChat.StreamRequest{Type: msgTypeUser, ConversationId: "conv-1", Text: "What was the HUST cutoff?"},
+	)
+	require.NoError(t, s.Stream(stream))
+
+	var suggestions [][]string
+	for _, res := range stream.sent {
+		if res.GetType() == typeSuggestions {
+			suggestions = append(suggestions, res.GetSuggestions())
+		}
+	}
+	assert.Equal(t, [][]string{{"Is there a dormitory?"}}, suggestions, "only the last answer gets suggestions")
+	last := stream.sent[len(stream.sent)-1]
+	assert.Equal(t, typeSuggestions, last.GetType())
+	assert.Equal(t, "Answer 2", stream.sent[len(stream.sent)-2].GetToken())
+}
+
+func TestSuggestionsPrompt(t *testing.T) {
+	vi := suggestionsPrompt(langVietnamese, "Ngành nào hợp với em?", "Công nghệ thông tin.", 3)
+	assert.Contains(t, vi, "Đề xuất 3 câu hỏi")
+	assert.Contains(t, vi, "Ngành nào hợp với em?")
+	assert.Contains(t, vi, "Công nghệ thông tin.")
+	assert.Contains(t, suggestionsPrompt("fr", "Which careers?", "Law.", 2), "Suggest 2 short questions in English")
+}
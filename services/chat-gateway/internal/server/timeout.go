@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+)
+
+// estimateTokens approximates the token count of text at four characters
+// per token. Counting runes rather than bytes keeps Vietnamese text from
+// being overestimated.
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// generationTimeout returns how long a generation for prompt may take:
+// cfg.Timeout, or when adaptive timeouts are on, a budget that grows with
+// the prompt size within the configured bounds.
+func generationTimeout(cfg config.LLMConfig, prompt string) time.Duration {
+	t := cfg.AdaptiveTimeout
+	if !t.Enabled {
+		return cfg.Timeout
+	}
+	timeout := t.Base + time.Duration(estimateTokens(prompt))*t.PerThousandTokens/1000
+	return min(max(timeout, t.Min), t.Max)
+}
+
+// errSendTimeout means a message to api-gateway was not sent in time:
+// api-gateway has stopped reading the stream.
+var errSendTimeout = errors.New("send to api-gateway timed out")
+
+// errSenderClosed means a message was sent after the stream ended.
+var errSenderClosed = errors.New("stream to api-gateway has ended")
+
+// streamSender sends a stream's messages to api-gateway from one writer
+// goroutine, so sends never overlap and none starts once the stream handler
+// has closed the sender. A send not done within timeout fails with
+// errSendTimeout, and one still waiting when ctx is done with ctx's error;
+// either way the stream is broken, and every later send fails with the
+// same error without reaching gRPC. A timeout of 0 lets sends block.
+type streamSender struct {
+	ctx     context.Context
+	timeout time.Duration
+
+	messages chan *pbChat.StreamResponse
+	results  chan error
+	stop     chan struct{}
+	done     chan struct{}
+	// abandoned is set when a send timed out while the writer is still in
+	// it; see close
+	abandoned atomic.Bool
+
+	// mu serializes callers, pairing each message with its result
+	mu  sync.Mutex
+	err error
+}
+
+func newStreamSender(ctx context.Context, send func(*pbChat.StreamResponse) error, timeout time.Duration) *streamSender {
+	s := &streamSender{
+		ctx:      ctx,
+		timeout:  timeout,
+		messages: make(chan *pbChat.StreamResponse),
+		results:  make(chan error, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		for {
+			select {
+			case res := <-s.messages:
+				s.results <- send(res)
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// send sends res and waits for it to be sent.
+func (s *streamSender) send(res *pbChat.StreamResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	select {
+	case s.messages <- res:
+	case <-s.stop:
+		return errSenderClosed
+	case <-s.ctx.Done():
+		s.err = s.ctx.Err()
+		return s.err
+	}
+
+	var timeout <-chan time.Time
+	if s.timeout > 0 {
+		timer := time.NewTimer(s.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case err := <-s.results:
+		s.err = err
+	case <-timeout:
+		s.abandoned.Store(true)
+		s.err = errSendTimeout
+	case <-s.ctx.Done():
+		s.err = s.ctx.Err()
+	}
+	return s.err
+}
+
+// close stops the writer and waits for its last send to end, so no send
+// outlives the stream handler. The one exception is a send that timed out:
+// it only ends once the handler returns and gRPC closes the stream, so
+// waiting for it would never end.
+func (s *streamSender) close() {
+	close(s.stop)
+	if !s.abandoned.Load() {
+		<-s.done
+	}
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pbChat "github.com/careerup-Inc/careerup-monorepo/proto/careerup/v1"
+	"github.com/careerup-Inc/careerup-monorepo/services/chat-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, estimateTokens(""))
+	assert.Equal(t, 1, estimateTokens("Hi"))
+	assert.Equal(t, 2, estimateTokens("Hello!!!"))
+	// Runes, not bytes: "điểm chuẩn" is 10 characters but 14 bytes
+	assert.Equal(t, 3, estimateTokens("điểm chuẩn"))
+}
+
+func TestGenerationTimeout(t *testing.T) {
+	cfg := config.LLMConfig{
+		Timeout: 60 * time.Second,
+		AdaptiveTimeout: config.AdaptiveTimeoutConfig{
+			Enabled:           true,
+			Base:              20 * time.Second,
+			PerThousandTokens: 15 * time.Second,
+			Min:               25 * time.Second,
+			Max:               180 * time.Second,
+		},
+	}
+	// tokens returns a prompt of roughly n tokens
+	tokens := func(n int) string { return strings.Repeat("abcd", n) }
+
+	t.Run("small prompts get the minimum", func(t *testing.T) {
+		assert.Equal(t, 25*time.Second, generationTimeout(cfg, "What is the cut-off score?"))
+	})
+
+	t.Run("scales with prompt size", func(t *testing.T) {
+		assert.Equal(t, 50*time.Second, generationTimeout(cfg, tokens(2000)))
+		assert.Equal(t, 80*time.Second, generationTimeout(cfg, tokens(4000)))
+	})
+
+	t.Run("large prompts are capped", func(t *testing.T) {
+		assert.Equal(t, 180*time.Second, generationTimeout(cfg, tokens(50000)))
+	})
+
+	t.Run("fixed timeout when disabled", func(t *testing.T) {
+		fixed := cfg
+		fixed.AdaptiveTimeout.Enabled = false
+		assert.Equal(t, 60*time.Second, generationTimeout(fixed, tokens(50000)))
+	})
+}
+
+func TestStreamSender(t *testing.T) {
+	msg := &pbChat.StreamResponse{Type: "assistant_token"}
+	unblock := make(chan struct{})
+	defer close(unblock)
+	blocking := func(*pbChat.StreamResponse) error {
+		<-unblock
+		return nil
+	}
+
+	t.Run("send in time", func(t *testing.T) {
+		var sent []*pbChat.StreamResponse
+		sender := newStreamSender(context.Background(), func(res *pbChat.StreamResponse) error {
+			sent = append(sent, res)
+			return nil
+		}, time.Second)
+		assert.NoError(t, sender.send(msg))
+		assert.NoError(t, sender.send(msg))
+		sender.close()
+		assert.Equal(t, []*pbChat.StreamResponse{msg, msg}, sent)
+	})
+
+	t.Run("send error is returned", func(t *testing.T) {
+		failed := errors.New("transport closed")
+		sender := newStreamSender(context.Background(), func(*pbChat.StreamResponse) error { return failed }, time.Second)
+		defer sender.close()
+		assert.ErrorIs(t, sender.send(msg), failed)
+	})
+
+	t.Run("stuck send times out", func(t *testing.T) {
+		var calls atomic.Int32
+		sender := newStreamSender(context.Background(), func(res *pbChat.StreamResponse) error {
+			calls.Add(1)
+			return blocking(res)
+		}, 20*time.Millisecond)
+		start := time.Now()
+		assert.ErrorIs(t, sender.send(msg), errSendTimeout)
+		assert.Less(t, time.Since(start), time.Second)
+
+		assert.ErrorIs(t, sender.send(msg), errSendTimeout, "the stream is broken")
+		sender.close()
+		assert.Equal(t, int32(1), calls.Load(), "no send started beside the stuck one")
+	})
+
+	t.Run("stuck send ends with the stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		sender := newStreamSender(ctx, blocking, time.Hour)
+		defer sender.close()
+		assert.ErrorIs(t, sender.send(msg), context.Canceled)
+	})
+
+	t.Run("close waits for the last send", func(t *testing.T) {
+		taken := make(chan struct{})
+		var finished atomic.Bool
+		sender := newStreamSender(context.Background(), func(*pbChat.StreamResponse) error {
+			close(taken)
+			time.Sleep(20 * time.Millisecond)
+			finished.Store(true)
+			return nil
+		}, time.Hour)
+		go sender.send(msg)
+		<-taken
+		sender.close()
+		assert.True(t, finished.Load(), "no send outlives close")
+		assert.ErrorIs(t, sender.send(msg), errSenderClosed)
+	})
+
+	t.Run("sends never overlap", func(t *testing.T) {
+		var inFlight, overlaps atomic.Int32
+		sender := newStreamSender(context.Background(), func(*pbChat.StreamResponse) error {
+			if inFlight.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			time.Sleep(time.Millisecond)
+			inFlight.Add(-1)
+			return nil
+		}, time.Second)
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, sender.send(msg))
+			}()
+		}
+		wg.Wait()
+		sender.close()
+		assert.Zero(t, overlaps.Load())
+	})
+}
@@ -5,11 +5,11 @@ try:
     __all__ = ["get_admin_app", "create_admin_app"]
 except ImportError:
     # FastAPI not available, provide dummy functions
-    def get_admin_app():
+    def get_admin_app(llm_service=None):
         """Dummy admin app when FastAPI is not available."""
         return None
     
-    def create_admin_app():
+    def create_admin_app(llm_service=None):
         """Dummy admin app creation when FastAPI is not available."""
         return None
     
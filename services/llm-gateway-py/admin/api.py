@@ -1,9 +1,9 @@
 """FastAPI admin endpoints for HTTP management of the LLM Gateway service."""
 
-from fastapi import FastAPI, HTTPException, Depends, status, Request
+from fastapi import FastAPI, HTTPException, Depends, Header, Query, status, Request
 from fastapi.security import HTTPBearer, HTTPAuthorizationCredentials
 from fastapi.middleware.cors import CORSMiddleware
-from fastapi.responses import JSONResponse, PlainTextResponse
+from fastapi.responses import JSONResponse, PlainTextResponse, StreamingResponse
 from pydantic import BaseModel, Field
 from typing import Dict, Any, Optional, List
 import asyncio
@@ -14,6 +14,10 @@ from utils.metrics import get_metrics_collector
 from utils.security import validate_api_key, SecurityHeaders
 from utils.logger import get_logger
 from utils.helpers import sanitize_text, get_timestamp
+from utils import audit, ingest_jobs
+from utils.collection_export import InvalidExportLine, split_lines
+from utils.embeddings import EmbeddingDimensionMismatch
+from utils.pagination import InvalidPageToken, pagination_envelope
 from services.llm_service import LLMServicer
 
 # Initialize logger
@@ -56,11 +60,20 @@ class ConfigResponse(BaseModel):
     http_port: int
     version: str
 
+class CreateCollectionRequest(BaseModel):
+    collection_name: str = Field(..., min_length=1, max_length=45)
+    metadata: Dict[str, str] = Field(default_factory=dict)
+
 class IngestDataRequest(BaseModel):
     file_path: str = Field(..., description="Path to the data file (PDF or JSON)")
     file_type: str = Field(default="auto", description="File type: 'pdf', 'json', or 'auto'")
     collection_name: Optional[str] = Field(None, description="Target collection name")
 
+class IngestJobRequest(BaseModel):
+    documents: List[Dict[str, Any]] = Field(..., min_length=1)
+    collection: Optional[str] = Field(None, description="Target collection; the default index if omitted")
+    scrub: Optional[bool] = Field(None, description="Redact PII and blocklisted terms; the configured default if omitted")
+
 class IngestDataResponse(BaseModel):
     success: bool
     message: str
@@ -70,13 +83,41 @@ class IngestDataResponse(BaseModel):
     duration: Optional[float] = None
 
 
-def create_admin_app() -> FastAPI:
+def document_metadata(doc: Dict[str, Any]) -> Dict[str, str]:
+    """Metadata to store with an ingested document, including its URL and title."""
+    metadata = {k: str(v) for k, v in doc.get("metadata", {}).items()}
+    if doc.get("url"):
+        metadata.setdefault("source", doc["url"])
+    if doc.get("title"):
+        metadata.setdefault("title", sanitize_text(doc["title"]))
+    return metadata
+
+
+def create_admin_app(llm_service: Optional[LLMServicer] = None) -> FastAPI:
     """Create FastAPI admin application.
     
+    Args:
+        llm_service: The service answering gRPC traffic; every endpoint
+            acts on it, so what they ingest, create or clear is what it
+            serves
+    
     Returns:
         FastAPI application instance
     """
     settings = get_settings()
+    standalone_service: Optional[LLMServicer] = None
+
+    def shared_service() -> LLMServicer:
+        """The service answering gRPC traffic, so documents ingested and
+        collections created here are the ones it serves. Without one, as
+        for the status report, a single service of the admin API's own is
+        created and reused."""
+        nonlocal standalone_service
+        if llm_service is not None:
+            return llm_service
+        if standalone_service is None:
+            standalone_service = LLMServicer()
+        return standalone_service
     
     app = FastAPI(
         title="LLM Gateway Admin API",
@@ -107,6 +148,14 @@ def create_admin_app() -> FastAPI:
     # Startup time for uptime calculation
     startup_time = datetime.utcnow()
     
+    audit_log = audit.AuditLog(settings.admin_audit_log_path)
+    job_store = ingest_jobs.IngestJobStore(settings.ingest_jobs_dir)
+    # Jobs running in this process, by ID
+    running_jobs: Dict[str, asyncio.Task] = {}
+    job_events = ingest_jobs.JobEvents()
+    # Set to stop a running job after its current batch
+    cancel_requests: Dict[str, asyncio.Event] = {}
+    
     # Dependency for API key validation
     async def verify_api_key(credentials: Optional[HTTPAuthorizationCredentials] = Depends(security)):
         if not credentials:
@@ -123,6 +172,13 @@ def create_admin_app() -> FastAPI:
         
         return credentials.credentials
     
+    # Dependency identifying the caller for the audit trail
+    async def admin_actor(
+        api_key: str = Depends(verify_api_key),
+        x_admin_user: Optional[str] = Header(None)
+    ) -> str:
+        return audit.admin_actor(api_key, x_admin_user)
+    
     @app.get("/health", response_model=HealthResponse, tags=["Health"])
     async def health_check():
         """Get service health status."""
@@ -195,8 +251,7 @@ def create_admin_app() -> FastAPI:
             sanitized_query = sanitize_text(request.query)
             sanitized_context = sanitize_text(request.context) if request.context else ""
             
-            # Create LLM service instance
-            llm_service = LLMServicer()
+            llm_service = shared_service()
             
             # Prepare request
             from llm.v1 import llm_pb2
@@ -241,37 +296,106 @@ def create_admin_app() -> FastAPI:
     @app.post("/admin/ingest", tags=["Admin"])
     async def ingest_documents(
         documents: List[Dict[str, Any]],
-        api_key: str = Depends(verify_api_key)
+        dry_run: bool = False,
+        scrub: Optional[bool] = None,
+        verify: bool = False,
+        actor: str = Depends(admin_actor)
     ):
-        """Ingest documents into the vector store."""
+        """Ingest documents into the vector store.
+
+        With dry_run=true, documents are only chunked and costed; nothing is
+        embedded or written to the vector store. scrub=true or false
+        overrides whether PII and blocklisted terms are redacted first.
+        verify=true reads a sample of each document's stored chunks back
+        and reports whether they could all be found.
+
+        A document may list sections, each with a title and content,
+        ingested after its content. Chunks stay within one section and keep
+        its title.
+        """
         try:
-            # Create LLM service instance
-            llm_service = LLMServicer()
+            llm_service = shared_service()
             
             # Prepare request
             from llm.v1 import llm_pb2
             
-            doc_requests = []
+            processed_count = 0
+            errors = []
+            results = []
             for doc in documents:
-                doc_request = llm_pb2.Document(
-                    id=doc.get("id", ""),
+                grpc_request = llm_pb2.IngestDocumentRequest(
                     content=sanitize_text(doc.get("content", "")),
-                    url=doc.get("url", ""),
-                    title=sanitize_text(doc.get("title", "")),
-                    metadata=doc.get("metadata", {})
+                    collection=doc.get("collection", ""),
+                    metadata=document_metadata(doc),
+                    document_id=doc.get("id", ""),
+                    dry_run=dry_run,
+                    scrub=scrub,
+                    verify=verify,
+                    sections=[
+                        llm_pb2.DocumentSection(
+                            title=sanitize_text(section.get("title", "")),
+                            content=sanitize_text(section.get("content", ""))
+                        )
+                        for section in doc.get("sections", [])
+                    ]
                 )
-                doc_requests.append(doc_request)
-            
-            grpc_request = llm_pb2.IngestRequest(documents=doc_requests)
-            
-            # Execute ingestion
-            response = await llm_service.IngestDocuments(grpc_request, None)
+                
+                # Execute ingestion; dry runs change nothing and are not audited
+                if dry_run:
+                    response = await llm_service.IngestDocument(grpc_request, None)
+                else:
+                    collection = grpc_request.collection or llm_service.config.vector_store.default_index
+                    with audit_log.track(actor, audit.DOCUMENT_INGEST, collection) as outcome:
+                        response = await llm_service.IngestDocument(grpc_request, None)
+                        outcome["detail"] = response.message
+                        if not response.success:
+                            outcome["outcome"] = audit.OUTCOME_FAILURE
+                if not response.success:
+                    errors.append(response.message)
+                    if response.partial:
+                        results.append({
+                            "document_id": response.document_id,
+                            "chunks": response.chunks_created,
+                            "partial": True,
+                            "succeeded_ids": list(response.succeeded_ids),
+                            "failed_ids": list(response.failed_ids)
+                        })
+                    continue
+                
+                processed_count += 1
+                result = {
+                    "document_id": response.document_id,
+                    "chunks": response.chunks_created,
+                    "redactions": response.redactions
+                }
+                if dry_run:
+                    result["chunk_previews"] = [
+                        {
+                            "index": p.index,
+                            "preview": p.preview,
+                            "char_count": p.char_count,
+                            "estimated_tokens": p.estimated_tokens
+                        }
+                        for p in response.chunk_previews
+                    ]
+                    result["estimated_tokens"] = response.estimated_tokens
+                    result["estimated_embedding_cost_usd"] = response.estimated_embedding_cost_usd
+                if response.HasField("verification"):
+                    result["verification"] = {
+                        "visible": response.verification.visible,
+                        "sampled_ids": list(response.verification.sampled_ids),
+                        "missing_ids": list(response.verification.missing_ids),
+                        "attempts": response.verification.attempts
+                    }
+                results.append(result)
             
             return {
-                "message": response.message,
-                "processed_count": response.processed_count,
-                "failed_count": response.failed_count,
-                "errors": list(response.errors)
+                "message": f"{'Dry run of' if dry_run else 'Ingested'} {processed_count}/{len(documents)} documents",
+                "dry_run": dry_run,
+                "processed_count": processed_count,
+                "failed_count": len(errors),
+                "errors": errors,
+                "documents": results
             }
             
         except Exception as e:
@@ -281,24 +405,165 @@ def create_admin_app() -> FastAPI:
                 detail=f"Document ingestion failed: {str(e)}"
             )
     
+    def start_job(job: ingest_jobs.IngestJob, actor: str):
+        """Run a job in the background, auditing its outcome."""
+        cancel = asyncio.Event()
+        
+        async def run():
+            llm_service = shared_service()
+            with audit_log.track(actor, audit.DOCUMENT_INGEST, job.collection) as outcome:
+                finished = await llm_service.run_ingest_job(
+                    job_store, job, cancelled=cancel.is_set,
+                    on_event=lambda event, data: job_events.publish(job.job_id, event, data)
+                )
+                progress = finished.progress()
+                outcome["detail"] = f"job {job.job_id}: {progress['stored_chunks']}/{progress['total_chunks']} chunks stored"
+                if finished.status != ingest_jobs.JOB_COMPLETED:
+                    outcome["outcome"] = audit.OUTCOME_FAILURE
+        
+        task = asyncio.create_task(run())
+        running_jobs[job.job_id] = task
+        cancel_requests[job.job_id] = cancel
+        
+        def done(_):
+            running_jobs.pop(job.job_id, None)
+            cancel_requests.pop(job.job_id, None)
+            job_events.finish(job.job_id)
+        task.add_done_callback(done)
+    
+    @app.post("/admin/ingest-jobs", status_code=status.HTTP_202_ACCEPTED, tags=["Admin"])
+    async def create_ingest_job(
+        request: IngestJobRequest,
+        actor: str = Depends(admin_actor)
+    ):
+        """Start ingesting documents in the background.
+
+        Progress is checkpointed after every stored batch; poll
+        GET /admin/ingest-jobs/{job_id}, stop it with
+        POST /admin/ingest-jobs/{job_id}/cancel, and resume a failed or
+        cancelled job with POST /admin/ingest-jobs/{job_id}/resume.
+        """
+        collection = request.collection or settings.vector_store.default_index
+        documents = [
+            ingest_jobs.JobDocument(
+                document_id=str(doc.get("id") or f"doc-{i}"),
+                content=sanitize_text(doc.get("content", "")),
+                metadata=document_metadata(doc)
+            )
+            for i, doc in enumerate(request.documents)
+        ]
+        if len({doc.document_id for doc in documents}) != len(documents):
+            raise HTTPException(
+                status_code=status.HTTP_400_BAD_REQUEST,
+                detail="Document IDs must be unique within a job"
+            )
+        
+        scrub = request.scrub if request.scrub is not None else settings.vector_store.scrub_by_default
+        job = job_store.create(collection, documents, scrub=scrub, scrubber=shared_service().scrubber if scrub else None)
+        start_job(job, actor)
+        return job.progress()
+    
+    @app.get("/admin/ingest-jobs/{job_id}", tags=["Admin"])
+    async def get_ingest_job(job_id: str, api_key: str = Depends(verify_api_key)):
+        """Report an ingestion job's progress."""
+        job = job_store.get(job_id)
+        if job is None:
+            raise HTTPException(status_code=status.HTTP_404_NOT_FOUND, detail=f"Ingestion job '{job_id}' not found")
+        return job.progress()
+    
+    @app.get("/admin/ingest-jobs/{job_id}/events", tags=["Admin"])
+    async def stream_ingest_job_events(job_id: str, api_key: str = Depends(verify_api_key)):
+        """Stream an ingestion job's progress as server-sent events.
+
+        A running job's stream opens with a progress event holding the
+        progress so far, then has a document event per document started, a
+        batch event per stored batch and an error event per failed one. It
+        ends with a summary event once the job stops; a job that is not
+        running only gets the summary.
+        """
+        job = job_store.get(job_id)
+        if job is None:
+            raise HTTPException(status_code=status.HTTP_404_NOT_FOUND, detail=f"Ingestion job '{job_id}' not found")
+        # Subscribed before the stream starts, so no event falls in between
+        queue = job_events.subscribe(job_id) if job_id in running_jobs else None
+        
+        async def stream():
+            if queue is None:
+                yield ingest_jobs.sse_event(ingest_jobs.EVENT_SUMMARY, job.progress())
+                return
+            try:
+                yield ingest_jobs.sse_event(ingest_jobs.EVENT_PROGRESS, job.progress())
+                summarized = False
+                async for event, data in ingest_jobs.queued_events(queue):
+                    summarized = event == ingest_jobs.EVENT_SUMMARY
+                    yield ingest_jobs.sse_event(event, data)
+                if not summarized:
+                    # The job stopped without reporting its outcome
+                    yield ingest_jobs.sse_event(ingest_jobs.EVENT_SUMMARY, job_store.get(job_id).progress())
+            finally:
+                job_events.unsubscribe(job_id, queue)
+        
+        return StreamingResponse(
+            stream(),
+            media_type="text/event-stream",
+            headers={"Cache-Control": "no-cache", "X-Accel-Buffering": "no"}
+        )
+    
+    @app.post("/admin/ingest-jobs/{job_id}/resume", status_code=status.HTTP_202_ACCEPTED, tags=["Admin"])
+    async def resume_ingest_job(job_id: str, actor: str = Depends(admin_actor)):
+        """Resume a job from its checkpoint; stored chunks are not embedded again."""
+        job = job_store.get(job_id)
+        if job is None:
+            raise HTTPException(status_code=status.HTTP_404_NOT_FOUND, detail=f"Ingestion job '{job_id}' not found")
+        if job_id in running_jobs:
+            raise HTTPException(status_code=status.HTTP_409_CONFLICT, detail=f"Ingestion job '{job_id}' is already running")
+        if job.status == ingest_jobs.JOB_COMPLETED:
+            raise HTTPException(status_code=status.HTTP_409_CONFLICT, detail=f"Ingestion job '{job_id}' is already completed")
+        
+        start_job(job, actor)
+        return job.progress()
+    
+    @app.post("/admin/ingest-jobs/{job_id}/cancel", status_code=status.HTTP_202_ACCEPTED, tags=["Admin"])
+    async def cancel_ingest_job(job_id: str, actor: str = Depends(admin_actor)):
+        """Stop a running job before its next batch.
+
+        Chunks already stored are kept and stay checkpointed, so the job can
+        be resumed later. Poll GET /admin/ingest-jobs/{job_id} until its
+        status is cancelled.
+        """
+        job = job_store.get(job_id)
+        if job is None:
+            raise HTTPException(status_code=status.HTTP_404_NOT_FOUND, detail=f"Ingestion job '{job_id}' not found")
+        cancel = cancel_requests.get(job_id)
+        if cancel is None:
+            raise HTTPException(status_code=status.HTTP_409_CONFLICT, detail=f"Ingestion job '{job_id}' is not running")
+        
+        logger.info(f"Ingestion job {job_id} cancelled by {actor}")
+        cancel.set()
+        return job.progress()
+    
     @app.post("/admin/ingest/vietnamese-university-data", response_model=IngestDataResponse, tags=["Admin"])
     async def ingest_vietnamese_university_data(
         request: IngestDataRequest,
-        api_key: str = Depends(verify_api_key)
+        actor: str = Depends(admin_actor)
     ):
         """Ingest Vietnamese university data (PDF and JSON) with adaptive RAG capabilities."""
         try:
-            # Create LLM service instance
-            llm_service = LLMServicer()
+            llm_service = shared_service()
             
             # Execute enhanced ingestion with multi-representation indexing
             from datetime import datetime, timezone
             start_time = datetime.now(timezone.utc)
-            result = await llm_service.ingest_vietnamese_university_data(
-                file_path=request.file_path,
-                file_type=request.file_type,
-                collection_name=request.collection_name or "vietnamese-university-data"
-            )
+            collection_name = request.collection_name or "vietnamese-university-data"
+            with audit_log.track(actor, audit.DOCUMENT_INGEST, collection_name) as outcome:
+                result = await llm_service.ingest_vietnamese_university_data(
+                    file_path=request.file_path,
+                    file_type=request.file_type,
+                    collection_name=collection_name
+                )
+                outcome["detail"] = result["message"]
+                if not result["success"]:
+                    outcome["outcome"] = audit.OUTCOME_FAILURE
             duration = (datetime.now(timezone.utc) - start_time).total_seconds()
             
             return IngestDataResponse(
@@ -386,15 +651,17 @@ def create_admin_app() -> FastAPI:
     @app.delete("/admin/collections/{collection_name}", tags=["Admin"])
     async def clear_collection(
         collection_name: str,
-        api_key: str = Depends(verify_api_key)
+        actor: str = Depends(admin_actor)
     ):
         """Clear all data from a specific collection/index."""
         try:
-            # Create LLM service instance
-            llm_service = LLMServicer()
+            llm_service = shared_service()
             
             # Clear the collection
-            result = await llm_service.clear_collection(collection_name)
+            with audit_log.track(actor, audit.COLLECTION_DELETE, collection_name) as outcome:
+                result = await llm_service.clear_collection(collection_name)
+                if not result:
+                    outcome["outcome"] = audit.OUTCOME_FAILURE
             
             return {
                 "success": True,
@@ -410,24 +677,215 @@ def create_admin_app() -> FastAPI:
                 detail=f"Collection cleanup failed: {str(e)}"
             )
 
+    @app.post("/admin/collections", tags=["Admin"])
+    async def create_collection(
+        request: CreateCollectionRequest,
+        actor: str = Depends(admin_actor)
+    ):
+        """Create a new collection/index."""
+        try:
+            llm_service = shared_service()
+            
+            from llm.v1 import llm_pb2
+            
+            grpc_request = llm_pb2.CreateCollectionRequest(
+                collection_name=request.collection_name,
+                metadata=request.metadata
+            )
+            with audit_log.track(actor, audit.COLLECTION_CREATE, request.collection_name) as outcome:
+                response = await llm_service.CreateCollection(grpc_request, None)
+                outcome["detail"] = response.message
+                if not response.success:
+                    outcome["outcome"] = audit.OUTCOME_FAILURE
+            
+            return {
+                "success": response.success,
+                "message": response.message,
+                "collection_name": request.collection_name,
+                "status": response.status
+            }
+            
+        except Exception as e:
+            logger.error(f"Collection creation failed: {str(e)}", exc_info=True)
+            raise HTTPException(
+                status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
+                detail=f"Collection creation failed: {str(e)}"
+            )
+
+    @app.post("/admin/collections/purge-orphans", tags=["Admin"])
+    async def purge_orphaned_collections(
+        confirm: bool = False,
+        names: List[str] = Query(default=[]),
+        actor: str = Depends(admin_actor)
+    ):
+        """Report indexes that are neither known nor in use; delete those
+        listed in names only with confirm=true."""
+        if confirm and not names:
+            raise HTTPException(
+                status_code=status.HTTP_400_BAD_REQUEST,
+                detail="confirm=true needs the orphans to delete listed in names"
+            )
+        if llm_service is None:
+            # A fresh service knows nothing of the collections in use
+            raise HTTPException(
+                status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+                detail="Purging orphans needs the admin API running alongside the gRPC server"
+            )
+        try:
+            result = await llm_service.purge_orphaned_indexes(confirm=confirm, names=names)
+        except Exception as e:
+            logger.error(f"Orphaned index purge failed: {str(e)}", exc_info=True)
+            raise HTTPException(
+                status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
+                detail=f"Orphaned index purge failed: {str(e)}"
+            )
+        for name in result["deleted"]:
+            audit_log.record(actor, audit.COLLECTION_DELETE, name, audit.OUTCOME_SUCCESS, "orphaned index")
+        for failure in result["failed"]:
+            audit_log.record(actor, audit.COLLECTION_DELETE, failure["name"], audit.OUTCOME_FAILURE, failure["error"])
+        return result
+
+    @app.post("/admin/collections/{collection_name}/warmup", tags=["Admin"])
+    async def warmup_collection(
+        collection_name: str,
+        api_key: str = Depends(verify_api_key)
+    ):
+        """Connect to a collection ahead of traffic, so its first query doesn't pay for it."""
+        if llm_service is None:
+            raise HTTPException(
+                status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+                detail="Warmup needs the admin API running alongside the gRPC server"
+            )
+        result = (await llm_service.warmup_collections([collection_name]))[0]
+        return result
+
+    async def existing_collection_service(collection_name: str) -> LLMServicer:
+        """A service for acting on collection_name, failing the request if
+        there is no vector database or no such collection."""
+        llm_service = shared_service()
+        if not llm_service.vector_db:
+            raise HTTPException(
+                status_code=status.HTTP_503_SERVICE_UNAVAILABLE,
+                detail="Vector store is not configured"
+            )
+        try:
+            await asyncio.get_event_loop().run_in_executor(
+                None, llm_service.vector_db.describe_collection, collection_name
+            )
+        except Exception as e:
+            raise HTTPException(
+                status_code=status.HTTP_404_NOT_FOUND,
+                detail=f"Collection '{collection_name}' not found: {e}"
+            )
+        return llm_service
+
+    @app.get("/admin/collections/{collection_name}/export", tags=["Admin"])
+    async def export_collection(
+        collection_name: str,
+        api_key: str = Depends(verify_api_key)
+    ):
+        """Stream a collection's documents, with their ids and metadata, as JSON lines.
+
+        Documents are read from the vector store a page at a time. The
+        export can be imported into a collection of another environment
+        with POST /admin/collections/{collection_name}/import.
+        """
+        llm_service = await existing_collection_service(collection_name)
+        return StreamingResponse(
+            llm_service.export_collection(collection_name),
+            media_type="application/x-ndjson",
+            headers={"Content-Disposition": f'attachment; filename="{collection_name}.jsonl"'}
+        )
+
+    @app.post("/admin/collections/{collection_name}/import", tags=["Admin"])
+    async def import_collection(
+        collection_name: str,
+        request: Request,
+        actor: str = Depends(admin_actor)
+    ):
+        """Store the documents of an export in a collection.
+
+        The body is an export's JSON lines, read as it arrives. Documents
+        are re-embedded with this environment's model and keep their ids,
+        so importing the same export again replaces them rather than adding
+        copies. Batches stored before an invalid line stay stored.
+        """
+        llm_service = await existing_collection_service(collection_name)
+        try:
+            with audit_log.track(actor, audit.COLLECTION_IMPORT, collection_name) as outcome:
+                result = await llm_service.import_collection(collection_name, split_lines(request.stream()))
+                outcome["detail"] = f"{result.imported} documents imported, {len(result.failed_ids)} failed"
+                if result.failed_ids:
+                    outcome["outcome"] = audit.OUTCOME_FAILURE
+        except (InvalidExportLine, EmbeddingDimensionMismatch) as e:
+            raise HTTPException(
+                status_code=status.HTTP_400_BAD_REQUEST,
+                detail=str(e)
+            )
+        except Exception as e:
+            logger.error(f"Collection import failed: {str(e)}", exc_info=True)
+            raise HTTPException(
+                status_code=status.HTTP_500_INTERNAL_SERVER_ERROR,
+                detail=f"Collection import failed: {str(e)}"
+            )
+        return {
+            "success": not result.failed_ids,
+            "collection_name": collection_name,
+            "imported": result.imported,
+            "failed_ids": result.failed_ids
+        }
+
+    @app.get("/admin/audit", tags=["Admin"])
+    async def get_audit_log(
+        limit: int = 100,
+        action: Optional[str] = None,
+        api_key: str = Depends(verify_api_key)
+    ):
+        """Get recent admin audit entries, newest first."""
+        if not 1 <= limit <= 1000:
+            raise HTTPException(
+                status_code=status.HTTP_400_BAD_REQUEST,
+                detail="limit must be between 1 and 1000"
+            )
+        entries = audit_log.recent(limit=limit, action=action)
+        return {
+            "entries": entries,
+            "count": len(entries)
+        }
+
     @app.get("/admin/collections", tags=["Admin"])
     async def list_collections(
+        page_size: int = 0,
+        page_token: str = "",
+        include_stats: bool = False,
         api_key: str = Depends(verify_api_key)
     ):
-        """List all available collections/indexes."""
+        """List one page of collections/indexes, in name order."""
         try:
-            # Create LLM service instance
-            llm_service = LLMServicer()
+            llm_service = shared_service()
             
             # List collections
-            collections = await llm_service.list_collections()
+            collections, page_info = await llm_service.list_collections(
+                page_size=page_size,
+                page_token=page_token,
+                include_stats=include_stats
+            )
             
             return {
                 "success": True,
+                **pagination_envelope(collections, page_info),
+                # Deprecated: the same page as data/pagination, kept until
+                # clients read those
                 "collections": collections,
-                "count": len(collections)
+                "count": len(collections),
+                "next_page_token": page_info.next_token
             }
             
+        except InvalidPageToken as e:
+            raise HTTPException(
+                status_code=status.HTTP_400_BAD_REQUEST,
+                detail=str(e)
+            )
         except Exception as e:
             logger.error(f"Collection listing failed: {str(e)}", exc_info=True)
             raise HTTPException(
@@ -442,13 +900,16 @@ def create_admin_app() -> FastAPI:
 admin_app = None
 
 
-def get_admin_app() -> FastAPI:
+def get_admin_app(llm_service: Optional[LLMServicer] = None) -> FastAPI:
     """Get the admin FastAPI application instance.
     
+    Args:
+        llm_service: The service answering gRPC traffic
+    
     Returns:
         FastAPI admin application
     """
     global admin_app
     # Always create a new app instance to ensure latest endpoints are registered
-    admin_app = create_admin_app()
+    admin_app = create_admin_app(llm_service)
     return admin_app
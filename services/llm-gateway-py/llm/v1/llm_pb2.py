@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10llm/v1/llm.proto\x12\x06llm.v1\"Q\n\x15GenerateStreamRequest\x12\x0e\n\x06prompt\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x17\n\x0f\x63onversation_id\x18\x03 \x01(\t\"\'\n\x16GenerateStreamResponse\x12\r\n\x05token\x18\x01 \x01(\t\"|\n\x16GenerateWithRAGRequest\x12\x0e\n\x06prompt\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x17\n\x0f\x63onversation_id\x18\x03 \x01(\t\x12\x16\n\x0erag_collection\x18\x04 \x01(\t\x12\x10\n\x08\x61\x64\x61ptive\x18\x05 \x01(\x08\"(\n\x17GenerateWithRAGResponse\x12\r\n\x05token\x18\x01 \x01(\t\"\xc1\x01\n\x15IngestDocumentRequest\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x12\n\ncollection\x18\x02 \x01(\t\x12=\n\x08metadata\x18\x03 \x03(\x0b\x32+.llm.v1.IngestDocumentRequest.MetadataEntry\x12\x13\n\x0b\x64ocument_id\x18\x04 \x01(\t\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"g\n\x16IngestDocumentResponse\x12\x13\n\x0b\x64ocument_id\x18\x01 \x01(\t\x12\x0f\n\x07success\x18\x02 \x01(\x08\x12\x0f\n\x07message\x18\x03 \x01(\t\x12\x16\n\x0e\x63hunks_created\x18\x04 \x01(\x05\"\xa4\x01\n\x17\x43reateCollectionRequest\x12\x17\n\x0f\x63ollection_name\x18\x01 \x01(\t\x12?\n\x08metadata\x18\x02 \x03(\x0b\x32-.llm.v1.CreateCollectionRequest.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"U\n\x18\x43reateCollectionResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x17\n\x0f\x63ollection_name\x18\x03 \x01(\t\"\x18\n\x16ListCollectionsRequest\"F\n\x17ListCollectionsResponse\x12+\n\x0b\x63ollections\x18\x01 \x03(\x0b\x32\x16.llm.v1.CollectionInfo\"\xb3\x01\n\x0e\x43ollectionInfo\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x16\n\x0e\x64ocument_count\x18\x02 \x01(\x05\x12\x12\n\ncreated_at\x18\x03 \x01(\t\x12\x36\n\x08metadata\x18\x04 \x03(\x0b\x32$.llm.v1.CollectionInfo.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"2\n\x17\x44\x65leteCollectionRequest\x12\x17\n\x0f\x63ollection_name\x18\x01 \x01(\t\"<\n\x18\x44\x65leteCollectionResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t2\x88\x04\n\nLLMService\x12Q\n\x0eGenerateStream\x12\x1d.llm.v1.GenerateStreamRequest\x1a\x1e.llm.v1.GenerateStreamResponse0\x01\x12T\n\x0fGenerateWithRAG\x12\x1e.llm.v1.GenerateWithRAGRequest\x1a\x1f.llm.v1.GenerateWithRAGResponse0\x01\x12O\n\x0eIngestDocument\x12\x1d.llm.v1.IngestDocumentRequest\x1a\x1e.llm.v1.IngestDocumentResponse\x12U\n\x10\x43reateCollection\x12\x1f.llm.v1.CreateCollectionRequest\x1a .llm.v1.CreateCollectionResponse\x12R\n\x0fListCollections\x12\x1e.llm.v1.ListCollectionsRequest\x1a\x1f.llm.v1.ListCollectionsResponse\x12U\n\x10\x44\x65leteCollection\x12\x1f.llm.v1.DeleteCollectionRequest\x1a .llm.v1.DeleteCollectionResponseB>Z<github.com/careerup-Inc/careerup-monorepo/proto/llm/v1;llmv1b\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10llm/v1/llm.proto\x12\x06llm.v1\"\xa4\x02\n\x15GenerateStreamRequest\x12\x0e\n\x06prompt\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x17\n\x0f\x63onversation_id\x18\x03 \x01(\t\x12(\n\x06params\x18\x04 \x01(\x0b\x32\x18.llm.v1.GenerationParams\x12\x0f\n\x07use_rag\x18\x05 \x01(\x08\x12\x17\n\x0frag_collections\x18\x06 \x03(\t\x12\x10\n\x08\x61\x64\x61ptive\x18\x07 \x01(\x08\x12\x1d\n\x10strict_grounding\x18\x08 \x01(\x08H\x00\x88\x01\x01\x12\r\n\x05\x64\x65\x62ug\x18\t \x01(\x08\x12\x15\n\rcontinue_from\x18\n \x01(\t\x12\x11\n\tverbosity\x18\x0b \x01(\tB\x13\n\x11_strict_grounding\"\x8c\x01\n\x16GenerateStreamResponse\x12\r\n\x05token\x18\x01 \x01(\t\x12\x0e\n\x06status\x18\x03 \x01(\t\x12\x1f\n\x07sources\x18\x04 \x03(\x0b\x32\x0e.llm.v1.Source\x12\x1f\n\x05\x64\x65\x62ug\x18\x05 \x01(\x0b\x32\x10.llm.v1.RAGDebug\x12\x11\n\ttruncated\x18\x06 \x01(\x08\"[\n\x19GenerateStructuredRequest\x12\x0e\n\x06prompt\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x0e\n\x06schema\x18\x03 \x01(\t\x12\r\n\x05light\x18\x04 \x01(\x08\"\x8d\x01\n\x1aGenerateStructuredResponse\x12\x0c\n\x04json\x18\x01 \x01(\t\x12\x0e\n\x06method\x18\x02 \x01(\t\x12\x34\n\x12\x63\x61reer_suggestions\x18\x03 \x03(\x0b\x32\x18.llm.v1.CareerSuggestion\x12\x1b\n\x13\x66ollow_up_questions\x18\x04 \x03(\t\"h\n\x10\x43\x61reerSuggestion\x12\x14\n\x0c\x63\x61reer_field\x18\x01 \x01(\t\x12\x15\n\rmatch_percent\x18\x02 \x01(\x05\x12\x11\n\trationale\x18\x03 \x01(\t\x12\x14\n\x0c\x64omain_codes\x18\x04 \x03(\t\"\"\n\x0fGetUsageRequest\x12\x0f\n\x07user_id\x18\x01 \x01(\t\"\xaf\x01\n\x10GetUsageResponse\x12\x0f\n\x07user_id\x18\x01 \x01(\t\x12\x0e\n\x06period\x18\x02 \x01(\t\x12\x15\n\rprompt_tokens\x18\x03 \x01(\x03\x12\x19\n\x11\x63ompletion_tokens\x18\x04 \x01(\x03\x12\x14\n\x0ctotal_tokens\x18\x05 \x01(\x03\x12\x10\n\x08requests\x18\x06 \x01(\x03\x12\r\n\x05quota\x18\x07 \x01(\x03\x12\x11\n\tremaining\x18\x08 \x01(\x03\"\xac\x02\n\x16GenerateWithRAGRequest\x12\x0e\n\x06prompt\x18\x01 \x01(\t\x12\x0f\n\x07user_id\x18\x02 \x01(\t\x12\x17\n\x0f\x63onversation_id\x18\x03 \x01(\t\x12\x16\n\x0erag_collection\x18\x04 \x01(\t\x12\x10\n\x08\x61\x64\x61ptive\x18\x05 \x01(\x08\x12\x17\n\x0frag_collections\x18\x06 \x03(\t\x12(\n\x06params\x18\x07 \x01(\x0b\x32\x18.llm.v1.GenerationParams\x12\x1d\n\x10strict_grounding\x18\x08 \x01(\x08H\x00\x88\x01\x01\x12\r\n\x05\x64\x65\x62ug\x18\t \x01(\x08\x12\x15\n\rcontinue_from\x18\n \x01(\t\x12\x11\n\tverbosity\x18\x0b \x01(\tB\x13\n\x11_strict_grounding\"\xd2\x01\n\x10GenerationParams\x12\x18\n\x0btemperature\x18\x01 \x01(\x02H\x00\x88\x01\x01\x12\x12\n\x05top_p\x18\x02 \x01(\x02H\x01\x88\x01\x01\x12\x1d\n\x10presence_penalty\x18\x03 \x01(\x02H\x02\x88\x01\x01\x12\x1e\n\x11\x66requency_penalty\x18\x04 \x01(\x02H\x03\x88\x01\x01\x12\x0c\n\x04stop\x18\x05 \x03(\tB\x0e\n\x0c_temperatureB\x08\n\x06_top_pB\x13\n\x11_presence_penaltyB\x14\n\x12_frequency_penalty\"\x8d\x01\n\x17GenerateWithRAGResponse\x12\r\n\x05token\x18\x01 \x01(\t\x12\x0e\n\x06status\x18\x02 \x01(\t\x12\x1f\n\x07sources\x18\x03 \x03(\x0b\x32\x0e.llm.v1.Source\x12\x1f\n\x05\x64\x65\x62ug\x18\x04 \x01(\x0b\x32\x10.llm.v1.RAGDebug\x12\x11\n\ttruncated\x18\x05 \x01(\x08\"\xbe\x01\n\x08RAGDebug\x12\r\n\x05route\x18\x01 \x01(\t\x12+\n\tdocuments\x18\x02 \x03(\x0b\x32\x18.llm.v1.RAGDebugDocument\x12\x1b\n\x13hallucination_check\x18\x03 \x01(\t\x12\x10\n\x08\x61ttempts\x18\x04 \x01(\x05\x12\x1c\n\x14knowledge_base_empty\x18\x05 \x01(\x08\x12\x0e\n\x06notice\x18\x06 \x01(\t\x12\x19\n\x11\x63itations_retried\x18\x07 \x01(\x08\"\xa9\x01\n\x10RAGDebugDocument\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0e\n\x06source\x18\x02 \x01(\t\x12\x12\n\ncollection\x18\x03 \x01(\t\x12\x12\n\x05score\x18\x04 \x01(\x02H\x00\x88\x01\x01\x12\x11\n\trelevance\x18\x05 \x01(\t\x12\x0c\n\x04used\x18\x06 \x01(\x08\x12\x17\n\nbase_score\x18\x07 \x01(\x02H\x01\x88\x01\x01\x42\x08\n\x06_scoreB\r\n\x0b_base_score\"F\n\x06Source\x12\x0c\n\x04type\x18\x01 \x01(\t\x12\r\n\x05title\x18\x02 \x01(\t\x12\x0b\n\x03uri\x18\x03 \x01(\t\x12\x12\n\ncollection\x18\x04 \x01(\t\"\xab\x02\n\x15IngestDocumentRequest\x12\x0f\n\x07\x63ontent\x18\x01 \x01(\t\x12\x12\n\ncollection\x18\x02 \x01(\t\x12=\n\x08metadata\x18\x03 \x03(\x0b\x32+.llm.v1.IngestDocumentRequest.MetadataEntry\x12\x13\n\x0b\x64ocument_id\x18\x04 \x01(\t\x12\x0f\n\x07\x64ry_run\x18\x05 \x01(\x08\x12\x12\n\x05scrub\x18\x06 \x01(\x08H\x00\x88\x01\x01\x12\x0e\n\x06verify\x18\x07 \x01(\x08\x12)\n\x08sections\x18\x08 \x03(\x0b\x32\x17.llm.v1.DocumentSection\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x42\x08\n\x06_scrub\"1\n\x0f\x44ocumentSection\x12\r\n\x05title\x18\x01 \x01(\t\x12\x0f\n\x07\x63ontent\x18\x02 \x01(\t\"\x83\x03\n\x16IngestDocumentResponse\x12\x13\n\x0b\x64ocument_id\x18\x01 \x01(\t\x12\x0f\n\x07success\x18\x02 \x01(\x08\x12\x0f\n\x07message\x18\x03 \x01(\t\x12\x16\n\x0e\x63hunks_created\x18\x04 \x01(\x05\x12\x0f\n\x07\x64ry_run\x18\x05 \x01(\x08\x12,\n\x0e\x63hunk_previews\x18\x06 \x03(\x0b\x32\x14.llm.v1.ChunkPreview\x12\x18\n\x10\x65stimated_tokens\x18\x07 \x01(\x05\x12$\n\x1c\x65stimated_embedding_cost_usd\x18\x08 \x01(\x01\x12\x19\n\x11\x63ollection_status\x18\t \x01(\t\x12\x15\n\rsucceeded_ids\x18\n \x03(\t\x12\x12\n\nfailed_ids\x18\x0b \x03(\t\x12\x0f\n\x07partial\x18\x0c \x01(\x08\x12\x12\n\nredactions\x18\r \x01(\x05\x12\x30\n\x0cverification\x18\x0e \x01(\x0b\x32\x1a.llm.v1.UpsertVerification\"a\n\x12UpsertVerification\x12\x13\n\x0bsampled_ids\x18\x01 \x03(\t\x12\x13\n\x0bmissing_ids\x18\x02 \x03(\t\x12\x0f\n\x07visible\x18\x03 \x01(\x08\x12\x10\n\x08\x61ttempts\x18\x04 \x01(\x05\"\\\n\x0c\x43hunkPreview\x12\r\n\x05index\x18\x01 \x01(\x05\x12\x0f\n\x07preview\x18\x02 \x01(\t\x12\x12\n\nchar_count\x18\x03 \x01(\x05\x12\x18\n\x10\x65stimated_tokens\x18\x04 \x01(\x05\"\xa4\x01\n\x17\x43reateCollectionRequest\x12\x17\n\x0f\x63ollection_name\x18\x01 \x01(\t\x12?\n\x08metadata\x18\x02 \x03(\x0b\x32-.llm.v1.CreateCollectionRequest.MetadataEntry\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"e\n\x18\x43reateCollectionResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x17\n\x0f\x63ollection_name\x18\x03 \x01(\t\x12\x0e\n\x06status\x18\x04 \x01(\t\"V\n\x16ListCollectionsRequest\x12\x11\n\tpage_size\x18\x01 \x01(\x05\x12\x12\n\npage_token\x18\x02 \x01(\t\x12\x15\n\rinclude_stats\x18\x03 \x01(\x08\"_\n\x17ListCollectionsResponse\x12+\n\x0b\x63ollections\x18\x01 \x03(\x0b\x32\x16.llm.v1.CollectionInfo\x12\x17\n\x0fnext_page_token\x18\x02 \x01(\t\"\xc3\x01\n\x0e\x43ollectionInfo\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x16\n\x0e\x64ocument_count\x18\x02 \x01(\x05\x12\x12\n\ncreated_at\x18\x03 \x01(\t\x12\x36\n\x08metadata\x18\x04 \x03(\x0b\x32$.llm.v1.CollectionInfo.MetadataEntry\x12\x0e\n\x06status\x18\x05 \x01(\t\x1a/\n\rMetadataEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"2\n\x17\x44\x65leteCollectionRequest\x12\x17\n\x0f\x63ollection_name\x18\x01 \x01(\t\"<\n\x18\x44\x65leteCollectionResponse\x12\x0f\n\x07success\x18\x01 \x01(\x08\x12\x0f\n\x07message\x18\x02 \x01(\t\"\x1f\n\x1dGetKnowledgeBaseStatusRequest\"j\n\x1eGetKnowledgeBaseStatusResponse\x12\r\n\x05\x65mpty\x18\x01 \x01(\x08\x12\x13\n\x0b\x63ollections\x18\x02 \x01(\x05\x12\x11\n\tdocuments\x18\x03 \x01(\x03\x12\x11\n\tuncounted\x18\x04 \x01(\x05\x32\x8d\x06\n\nLLMService\x12Q\n\x0eGenerateStream\x12\x1d.llm.v1.GenerateStreamRequest\x1a\x1e.llm.v1.GenerateStreamResponse0\x01\x12T\n\x0fGenerateWithRAG\x12\x1e.llm.v1.GenerateWithRAGRequest\x1a\x1f.llm.v1.GenerateWithRAGResponse0\x01\x12=\n\x08GetUsage\x12\x17.llm.v1.GetUsageRequest\x1a\x18.llm.v1.GetUsageResponse\x12[\n\x12GenerateStructured\x12!.llm.v1.GenerateStructuredRequest\x1a\".llm.v1.GenerateStructuredResponse\x12O\n\x0eIngestDocument\x12\x1d.llm.v1.IngestDocumentRequest\x1a\x1e.llm.v1.IngestDocumentResponse\x12U\n\x10\x43reateCollection\x12\x1f.llm.v1.CreateCollectionRequest\x1a .llm.v1.CreateCollectionResponse\x12R\n\x0fListCollections\x12\x1e.llm.v1.ListCollectionsRequest\x1a\x1f.llm.v1.ListCollectionsResponse\x12U\n\x10\x44\x65leteCollection\x12\x1f.llm.v1.DeleteCollectionRequest\x1a .llm.v1.DeleteCollectionResponse\x12g\n\x16GetKnowledgeBaseStatus\x12%.llm.v1.GetKnowledgeBaseStatusRequest\x1a&.llm.v1.GetKnowledgeBaseStatusResponseB>Z<github.com/careerup-Inc/careerup-monorepo/proto/llm/v1;llmv1b\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -38,38 +38,66 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_CREATECOLLECTIONREQUEST_METADATAENTRY']._serialized_options = b'8\001'
   _globals['_COLLECTIONINFO_METADATAENTRY']._loaded_options = None
   _globals['_COLLECTIONINFO_METADATAENTRY']._serialized_options = b'8\001'
-  _globals['_GENERATESTREAMREQUEST']._serialized_start=28
-  _globals['_GENERATESTREAMREQUEST']._serialized_end=109
-  _globals['_GENERATESTREAMRESPONSE']._serialized_start=111
-  _globals['_GENERATESTREAMRESPONSE']._serialized_end=150
-  _globals['_GENERATEWITHRAGREQUEST']._serialized_start=152
-  _globals['_GENERATEWITHRAGREQUEST']._serialized_end=276
-  _globals['_GENERATEWITHRAGRESPONSE']._serialized_start=278
-  _globals['_GENERATEWITHRAGRESPONSE']._serialized_end=318
-  _globals['_INGESTDOCUMENTREQUEST']._serialized_start=321
-  _globals['_INGESTDOCUMENTREQUEST']._serialized_end=514
-  _globals['_INGESTDOCUMENTREQUEST_METADATAENTRY']._serialized_start=467
-  _globals['_INGESTDOCUMENTREQUEST_METADATAENTRY']._serialized_end=514
-  _globals['_INGESTDOCUMENTRESPONSE']._serialized_start=516
-  _globals['_INGESTDOCUMENTRESPONSE']._serialized_end=619
-  _globals['_CREATECOLLECTIONREQUEST']._serialized_start=622
-  _globals['_CREATECOLLECTIONREQUEST']._serialized_end=786
-  _globals['_CREATECOLLECTIONREQUEST_METADATAENTRY']._serialized_start=467
-  _globals['_CREATECOLLECTIONREQUEST_METADATAENTRY']._serialized_end=514
-  _globals['_CREATECOLLECTIONRESPONSE']._serialized_start=788
-  _globals['_CREATECOLLECTIONRESPONSE']._serialized_end=873
-  _globals['_LISTCOLLECTIONSREQUEST']._serialized_start=875
-  _globals['_LISTCOLLECTIONSREQUEST']._serialized_end=899
-  _globals['_LISTCOLLECTIONSRESPONSE']._serialized_start=901
-  _globals['_LISTCOLLECTIONSRESPONSE']._serialized_end=971
-  _globals['_COLLECTIONINFO']._serialized_start=974
-  _globals['_COLLECTIONINFO']._serialized_end=1153
-  _globals['_COLLECTIONINFO_METADATAENTRY']._serialized_start=467
-  _globals['_COLLECTIONINFO_METADATAENTRY']._serialized_end=514
-  _globals['_DELETECOLLECTIONREQUEST']._serialized_start=1155
-  _globals['_DELETECOLLECTIONREQUEST']._serialized_end=1205
-  _globals['_DELETECOLLECTIONRESPONSE']._serialized_start=1207
-  _globals['_DELETECOLLECTIONRESPONSE']._serialized_end=1267
-  _globals['_LLMSERVICE']._serialized_start=1270
-  _globals['_LLMSERVICE']._serialized_end=1790
+  _globals['_GENERATESTREAMREQUEST']._serialized_start=29
+  _globals['_GENERATESTREAMREQUEST']._serialized_end=321
+  _globals['_GENERATESTREAMRESPONSE']._serialized_start=324
+  _globals['_GENERATESTREAMRESPONSE']._serialized_end=464
+  _globals['_GENERATESTRUCTUREDREQUEST']._serialized_start=466
+  _globals['_GENERATESTRUCTUREDREQUEST']._serialized_end=557
+  _globals['_GENERATESTRUCTUREDRESPONSE']._serialized_start=560
+  _globals['_GENERATESTRUCTUREDRESPONSE']._serialized_end=701
+  _globals['_CAREERSUGGESTION']._serialized_start=703
+  _globals['_CAREERSUGGESTION']._serialized_end=807
+  _globals['_GETUSAGEREQUEST']._serialized_start=809
+  _globals['_GETUSAGEREQUEST']._serialized_end=843
+  _globals['_GETUSAGERESPONSE']._serialized_start=846
+  _globals['_GETUSAGERESPONSE']._serialized_end=1021
+  _globals['_GENERATEWITHRAGREQUEST']._serialized_start=1024
+  _globals['_GENERATEWITHRAGREQUEST']._serialized_end=1324
+  _globals['_GENERATIONPARAMS']._serialized_start=1327
+  _globals['_GENERATIONPARAMS']._serialized_end=1537
+  _globals['_GENERATEWITHRAGRESPONSE']._serialized_start=1540
+  _globals['_GENERATEWITHRAGRESPONSE']._serialized_end=1681
+  _globals['_RAGDEBUG']._serialized_start=1684
+  _globals['_RAGDEBUG']._serialized_end=1874
+  _globals['_RAGDEBUGDOCUMENT']._serialized_start=1877
+  _globals['_RAGDEBUGDOCUMENT']._serialized_end=2046
+  _globals['_SOURCE']._serialized_start=2048
+  _globals['_SOURCE']._serialized_end=2118
+  _globals['_INGESTDOCUMENTREQUEST']._serialized_start=2121
+  _globals['_INGESTDOCUMENTREQUEST']._serialized_end=2420
+  _globals['_INGESTDOCUMENTREQUEST_METADATAENTRY']._serialized_start=2363
+  _globals['_INGESTDOCUMENTREQUEST_METADATAENTRY']._serialized_end=2410
+  _globals['_DOCUMENTSECTION']._serialized_start=2422
+  _globals['_DOCUMENTSECTION']._serialized_end=2471
+  _globals['_INGESTDOCUMENTRESPONSE']._serialized_start=2474
+  _globals['_INGESTDOCUMENTRESPONSE']._serialized_end=2861
+  _globals['_UPSERTVERIFICATION']._serialized_start=2863
+  _globals['_UPSERTVERIFICATION']._serialized_end=2960
+  _globals['_CHUNKPREVIEW']._serialized_start=2962
+  _globals['_CHUNKPREVIEW']._serialized_end=3054
+  _globals['_CREATECOLLECTIONREQUEST']._serialized_start=3057
+  _globals['_CREATECOLLECTIONREQUEST']._serialized_end=3221
+  _globals['_CREATECOLLECTIONREQUEST_METADATAENTRY']._serialized_start=2363
+  _globals['_CREATECOLLECTIONREQUEST_METADATAENTRY']._serialized_end=2410
+  _globals['_CREATECOLLECTIONRESPONSE']._serialized_start=3223
+  _globals['_CREATECOLLECTIONRESPONSE']._serialized_end=3324
+  _globals['_LISTCOLLECTIONSREQUEST']._serialized_start=3326
+  _globals['_LISTCOLLECTIONSREQUEST']._serialized_end=3412
+  _globals['_LISTCOLLECTIONSRESPONSE']._serialized_start=3414
+  _globals['_LISTCOLLECTIONSRESPONSE']._serialized_end=3509
+  _globals['_COLLECTIONINFO']._serialized_start=3512
+  _globals['_COLLECTIONINFO']._serialized_end=3707
+  _globals['_COLLECTIONINFO_METADATAENTRY']._serialized_start=2363
+  _globals['_COLLECTIONINFO_METADATAENTRY']._serialized_end=2410
+  _globals['_DELETECOLLECTIONREQUEST']._serialized_start=3709
+  _globals['_DELETECOLLECTIONREQUEST']._serialized_end=3759
+  _globals['_DELETECOLLECTIONRESPONSE']._serialized_start=3761
+  _globals['_DELETECOLLECTIONRESPONSE']._serialized_end=3821
+  _globals['_GETKNOWLEDGEBASESTATUSREQUEST']._serialized_start=3823
+  _globals['_GETKNOWLEDGEBASESTATUSREQUEST']._serialized_end=3854
+  _globals['_GETKNOWLEDGEBASESTATUSRESPONSE']._serialized_start=3856
+  _globals['_GETKNOWLEDGEBASESTATUSRESPONSE']._serialized_end=3962
+  _globals['_LLMSERVICE']._serialized_start=3965
+  _globals['_LLMSERVICE']._serialized_end=4746
 # @@protoc_insertion_point(module_scope)
@@ -44,6 +44,16 @@ class LLMServiceStub(object):
                 request_serializer=llm_dot_v1_dot_llm__pb2.GenerateWithRAGRequest.SerializeToString,
                 response_deserializer=llm_dot_v1_dot_llm__pb2.GenerateWithRAGResponse.FromString,
                 _registered_method=True)
+        self.GetUsage = channel.unary_unary(
+                '/llm.v1.LLMService/GetUsage',
+                request_serializer=llm_dot_v1_dot_llm__pb2.GetUsageRequest.SerializeToString,
+                response_deserializer=llm_dot_v1_dot_llm__pb2.GetUsageResponse.FromString,
+                _registered_method=True)
+        self.GenerateStructured = channel.unary_unary(
+                '/llm.v1.LLMService/GenerateStructured',
+                request_serializer=llm_dot_v1_dot_llm__pb2.GenerateStructuredRequest.SerializeToString,
+                response_deserializer=llm_dot_v1_dot_llm__pb2.GenerateStructuredResponse.FromString,
+                _registered_method=True)
         self.IngestDocument = channel.unary_unary(
                 '/llm.v1.LLMService/IngestDocument',
                 request_serializer=llm_dot_v1_dot_llm__pb2.IngestDocumentRequest.SerializeToString,
@@ -64,6 +74,11 @@ class LLMServiceStub(object):
                 request_serializer=llm_dot_v1_dot_llm__pb2.DeleteCollectionRequest.SerializeToString,
                 response_deserializer=llm_dot_v1_dot_llm__pb2.DeleteCollectionResponse.FromString,
                 _registered_method=True)
+        self.GetKnowledgeBaseStatus = channel.unary_unary(
+                '/llm.v1.LLMService/GetKnowledgeBaseStatus',
+                request_serializer=llm_dot_v1_dot_llm__pb2.GetKnowledgeBaseStatusRequest.SerializeToString,
+                response_deserializer=llm_dot_v1_dot_llm__pb2.GetKnowledgeBaseStatusResponse.FromString,
+                _registered_method=True)
 
 
 class LLMServiceServicer(object):
@@ -83,6 +98,25 @@ class LLMServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def GetUsage(self, request, context):
+        """GetUsage returns a user's token usage this month. Generation calls for a
+        user over their monthly quota fail with RESOURCE_EXHAUSTED. They also fail
+        with RESOURCE_EXHAUSTED when every model stays rate limited, and then
+        carry a retry-after trailer with the seconds to wait.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GenerateStructured(self, request, context):
+        """GenerateStructured returns output matching one of the gateway's schemas,
+        such as career suggestions, validated before it is returned. Output the
+        model could not get into shape fails with INTERNAL.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
     def IngestDocument(self, request, context):
         """Admin endpoints for dynamic document management
         """
@@ -108,6 +142,15 @@ class LLMServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def GetKnowledgeBaseStatus(self, request, context):
+        """GetKnowledgeBaseStatus reports what the vector database holds, so
+        readiness checks can tell an empty knowledge base apart from a working
+        one. Fails with UNAVAILABLE when the collections cannot be listed.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_LLMServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -121,6 +164,16 @@ def add_LLMServiceServicer_to_server(servicer, server):
                     request_deserializer=llm_dot_v1_dot_llm__pb2.GenerateWithRAGRequest.FromString,
                     response_serializer=llm_dot_v1_dot_llm__pb2.GenerateWithRAGResponse.SerializeToString,
             ),
+            'GetUsage': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetUsage,
+                    request_deserializer=llm_dot_v1_dot_llm__pb2.GetUsageRequest.FromString,
+                    response_serializer=llm_dot_v1_dot_llm__pb2.GetUsageResponse.SerializeToString,
+            ),
+            'GenerateStructured': grpc.unary_unary_rpc_method_handler(
+                    servicer.GenerateStructured,
+                    request_deserializer=llm_dot_v1_dot_llm__pb2.GenerateStructuredRequest.FromString,
+                    response_serializer=llm_dot_v1_dot_llm__pb2.GenerateStructuredResponse.SerializeToString,
+            ),
             'IngestDocument': grpc.unary_unary_rpc_method_handler(
                     servicer.IngestDocument,
                     request_deserializer=llm_dot_v1_dot_llm__pb2.IngestDocumentRequest.FromString,
@@ -141,6 +194,11 @@ def add_LLMServiceServicer_to_server(servicer, server):
                     request_deserializer=llm_dot_v1_dot_llm__pb2.DeleteCollectionRequest.FromString,
                     response_serializer=llm_dot_v1_dot_llm__pb2.DeleteCollectionResponse.SerializeToString,
             ),
+            'GetKnowledgeBaseStatus': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetKnowledgeBaseStatus,
+                    request_deserializer=llm_dot_v1_dot_llm__pb2.GetKnowledgeBaseStatusRequest.FromString,
+                    response_serializer=llm_dot_v1_dot_llm__pb2.GetKnowledgeBaseStatusResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'llm.v1.LLMService', rpc_method_handlers)
@@ -206,6 +264,60 @@ class LLMService(object):
             metadata,
             _registered_method=True)
 
+    @staticmethod
+    def GetUsage(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/llm.v1.LLMService/GetUsage',
+            llm_dot_v1_dot_llm__pb2.GetUsageRequest.SerializeToString,
+            llm_dot_v1_dot_llm__pb2.GetUsageResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def GenerateStructured(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/llm.v1.LLMService/GenerateStructured',
+            llm_dot_v1_dot_llm__pb2.GenerateStructuredRequest.SerializeToString,
+            llm_dot_v1_dot_llm__pb2.GenerateStructuredResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
     @staticmethod
     def IngestDocument(request,
             target,
@@ -313,3 +425,30 @@ class LLMService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def GetKnowledgeBaseStatus(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/llm.v1.LLMService/GetKnowledgeBaseStatus',
+            llm_dot_v1_dot_llm__pb2.GetKnowledgeBaseStatusRequest.SerializeToString,
+            llm_dot_v1_dot_llm__pb2.GetKnowledgeBaseStatusResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
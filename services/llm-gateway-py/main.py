@@ -9,6 +9,7 @@ sys.path.insert(0, str(proto_path))
 
 import asyncio
 import logging
+import signal
 from concurrent.futures import ThreadPoolExecutor
 import grpc
 from grpc_reflection.v1alpha import reflection
@@ -17,19 +18,30 @@ import threading
 from datetime import datetime
 
 # Import configuration and utilities
-from config.settings import get_settings
+from config.settings import ConfigError, get_settings
 from utils.logger import setup_logger, get_logger
 from utils.metrics import get_metrics_collector
+from utils.deadlines import default_deadline_interceptor
+from utils.server_options import grpc_server_options
 from admin.api import get_admin_app
 
 # Configure logging
 settings = get_settings()
 logger = setup_logger("llm-gateway-main", level=settings.log_level)
 
-async def start_admin_server():
+# Fail fast on invalid configuration, listing every problem at once
+try:
+    settings.validate()
+except ConfigError as e:
+    logger.error("Invalid configuration:\n" + "\n".join(f"  - {error}" for error in e.errors))
+    sys.exit(1)
+for warning in settings.warnings():
+    logger.warning(warning)
+
+async def start_admin_server(llm_service=None):
     """Start the FastAPI admin server."""
     try:
-        admin_app = get_admin_app()
+        admin_app = get_admin_app(llm_service)
         config = uvicorn.Config(
             admin_app,
             host="0.0.0.0",
@@ -57,8 +69,16 @@ async def main():
         metrics_collector = get_metrics_collector()
         logger.info("Metrics collector initialized")
         
-        # Create gRPC server
-        server = grpc.aio.server(ThreadPoolExecutor(max_workers=settings.max_workers))
+        # Create gRPC server; calls that arrive without a deadline get a default
+        server = grpc.aio.server(
+            ThreadPoolExecutor(max_workers=settings.max_workers),
+            interceptors=[default_deadline_interceptor(
+                settings.grpc_default_deadline_seconds,
+                settings.grpc_default_stream_deadline_seconds,
+            )],
+            # Message size limits and keepalive from the settings
+            options=grpc_server_options(settings),
+        )
         
         # Create and register the LLM service
         llm_service = LLMServicer()
@@ -81,15 +101,28 @@ async def main():
         # Start the gRPC server
         await server.start()
         
+        if llm_service.config.vector_store.warmup_on_startup:
+            llm_service.start_warmup()
+        
         # Start HTTP admin server in background
         admin_task = None
         if settings.enable_admin_api:
-            admin_task = asyncio.create_task(start_admin_server())
+            admin_task = asyncio.create_task(start_admin_server(llm_service))
             logger.info(f"Admin API will be available at http://localhost:{settings.http_port}/admin/docs")
         
         logger.info("LLM Gateway Python service is ready")
         logger.info(f"Service configuration: environment={settings.environment}, debug={settings.debug}")
         
+        async def shutdown(sig):
+            logger.info(f"Received {sig.name}, shutting down")
+            # Cancel lingering generations first so server.stop doesn't wait on them
+            await llm_service.Shutdown()
+            await server.stop(grace=1)
+        
+        loop = asyncio.get_running_loop()
+        for sig in (signal.SIGTERM, signal.SIGINT):
+            loop.add_signal_handler(sig, lambda s=sig: asyncio.create_task(shutdown(s)))
+        
         try:
             # Keep the server running
             await server.wait_for_termination()
@@ -7,11 +7,14 @@ Based on LangGraph adaptive RAG patterns.
 """
 
 import asyncio
+import functools
+import hmac
 import json
 import logging
 import re
+import time
 import uuid
-from typing import List, Optional, Dict, Any, AsyncGenerator, Literal
+from typing import List, Optional, Dict, Any, AsyncGenerator, AsyncIterable, Iterator, Literal, Tuple, Callable
 from dataclasses import dataclass
 from enum import Enum
 
@@ -25,7 +28,7 @@ from langchain_core.prompts import ChatPromptTemplate
 from langchain_core.messages import SystemMessage, HumanMessage
 from langchain_openai import ChatOpenAI, OpenAIEmbeddings
 from langchain_pinecone import PineconeVectorStore
-from pinecone import Pinecone
+from pinecone import Pinecone, ServerlessSpec
 import openai
 
 # Import proto files
@@ -37,9 +40,94 @@ sys.path.insert(0, str(proto_path))
 
 from llm.v1 import llm_pb2, llm_pb2_grpc
 from config import get_config
+from utils.coalescing import StreamCoalescer, coalescing_key
+from utils.collection_export import EXPORT_PAGE_SIZE, ImportResult, export_lines, import_lines
+from utils.collection_registry import CollectionRegistry
+from utils.embeddings import (
+    PROVIDER_HUGGINGFACE,
+    CollectionDimensions,
+    EmbeddingDimensionMismatch,
+    NormalizedEmbeddings,
+    check_index_dimensions,
+    resolve_embedding_model,
+)
+from utils.fakes import (
+    FakeChatModel,
+    FakeEmbeddings,
+    FakePineconeClient,
+    fake_vector_store_factory,
+)
+from utils.fallback import EVENT_FALLBACK, stream_with_fallback
+from utils.rate_limits import HIGH_DEMAND_MESSAGE, is_rate_limit, retry_after_header, retry_rate_limited
+from utils.generation import (
+    FINISH_LENGTH,
+    FINISH_STOP,
+    VERBOSITY_NORMAL,
+    StopSequenceFilter,
+    bind_generation_options,
+    citation_instruction,
+    continuation_prompt,
+    guarded_prompt,
+    length_instruction,
+    no_results_message,
+    resolve_stop_sequences,
+    resolve_verbosity,
+    strict_grounding_enabled,
+    verbosity_max_tokens,
+)
+from utils.memory_store import MemoryVectorStore
+from utils.metrics import get_metrics_collector
+from utils.ingestion import TokenLimitedSplitter, ingest_document, verify_upsert
+from utils.scrubbing import Scrubber
+from utils import ingest_jobs
+from utils.provisioning import (
+    STATUS_FAILED,
+    STATUS_PROVISIONING,
+    STATUS_READY,
+    IndexProvisioningTimeout,
+    wait_until_ready,
+)
+from utils.retrieval import (
+    cites_sources,
+    dedupe_documents,
+    document_sources,
+    format_context,
+    gather_sources,
+    merge_documents,
+    parse_score_boosts,
+    resolve_collections,
+    retrieve_from_collections,
+    retrieval_limits,
+    truncate_text,
+)
+from utils.knowledge_base import EMPTY_KNOWLEDGE_BASE_NOTICE, KnowledgeBaseMonitor
+from utils.orphans import KEPT_IN_USE, KEPT_NOT_NAMED, find_orphans
+from utils.pagination import InvalidPageToken, PageInfo, paginate_with_info
+from utils.pinecone_store import PINECONE_TEXT_KEY, PineconeStore
+from utils.schemas import SCHEMAS, CareerSuggestions, FollowUpQuestions
+from utils.streams import StreamRegistry
+from utils.structured import StructuredOutputError, generate_structured
+from utils.usage import QuotaExceeded, UsageMeter, UsageStore
+from utils.vector_backend import (
+    BACKEND_MEMORY,
+    CollectionHandle,
+    CollectionInfo,
+    PineconeClient,
+    VectorStore,
+    VectorStoreCache,
+    VectorStoreFactory,
+)
 
 logger = logging.getLogger(__name__)
 
+class PipelineStatus(Enum):
+    """Progress stages reported to clients in the response status field."""
+    RETRIEVING = "retrieving"
+    SEARCHING_WEB = "searching_web"
+    GENERATING = "generating"
+    # Generation failed over to another model after tokens were sent
+    RESTARTING = "restarting"
+
 class QueryRoute(Enum):
     """Query routing options for adaptive RAG."""
     VECTORSTORE = "vectorstore"
@@ -57,6 +145,13 @@ class RAGState:
     max_retries: int = 3
     relevance_scores: List[str] = None
     hallucination_score: str = ""
+    # Every document retrieved, before grading; relevance_scores holds the
+    # grader's verdict for each when they were graded
+    retrieved: List[Document] = None
+    # Retrieval was skipped because no collection holds documents
+    knowledge_base_empty: bool = False
+    # The answer cited no document and was generated again asking for citations
+    citations_retried: bool = False
 
 # Pydantic models for structured LLM outputs
 class GradeDocuments(BaseModel):
@@ -78,58 +173,159 @@ class RouteQuery(BaseModel):
         description="Given a user question choose to route it to web search or a vectorstore.",
     )
 
+SHUTDOWN_MESSAGE = "llm-gateway is shutting down, please retry"
+
+def tracked_stream(method):
+    """Track a streaming handler so Shutdown can cancel it.
+
+    Streams cancelled by Shutdown end with UNAVAILABLE instead of an opaque
+    cancellation, and new streams are refused once shutdown has started.
+    """
+    @functools.wraps(method)
+    async def wrapper(self, request, context):
+        if self.streams.closing:
+            context.set_code(grpc.StatusCode.UNAVAILABLE)
+            context.set_details(SHUTDOWN_MESSAGE)
+            return
+        with self.streams.track():
+            try:
+                async for response in method(self, request, context):
+                    yield response
+            except asyncio.CancelledError:
+                if not self.streams.closing:
+                    raise
+                logger.info(f"{method.__name__} stream cancelled by shutdown")
+                context.set_code(grpc.StatusCode.UNAVAILABLE)
+                context.set_details(SHUTDOWN_MESSAGE)
+    return wrapper
+
 class LLMServicer(llm_pb2_grpc.LLMServiceServicer):
     """Python implementation of the LLM service."""
     
-    def __init__(self):
-        """Initialize the LLM service with all necessary components."""
+    def __init__(self, llm=None, embeddings=None, pinecone: Optional[PineconeClient] = None,
+                 web_search=None, vector_store_factory: Optional[VectorStoreFactory] = None,
+                 vector_db: Optional[VectorStore] = None):
+        """Initialize the LLM service with all necessary components.
+
+        Components that are not injected are built from configuration, or
+        from the deterministic fakes in utils.fakes when LLM_TEST_MODE is on.
+
+        Args:
+            llm: Chat model used for generation, grading and routing
+            embeddings: Embeddings model for the vector stores
+            pinecone: Pinecone client, used when vector_db is not given
+            web_search: Web search tool (run(query) -> list of results)
+            vector_store_factory: Builds a vector store from a Pinecone index
+                and embeddings
+            vector_db: Vector database; replaces the one vector_store.backend
+                configures
+        """
         self.config = get_config()
-        self._initialize_components()
+        self.streams = StreamRegistry()
+        self.coalescer = StreamCoalescer()
+        # Startup warmup running in the background, cancelled on shutdown
+        self._warmup_task: Optional[asyncio.Task] = None
+        self.usage = UsageStore(self.config.usage_dir)
+        # Handles on the non-default collections used since startup
+        self._collection_stores = VectorStoreCache()
+        self.collection_registry = CollectionRegistry(self.config.collection_registry_path)
+        self.retrieval_boosts = parse_score_boosts(self.config.rag.retrieval_boosts)
+        self._initialize_components(llm, embeddings, pinecone, web_search, vector_store_factory, vector_db)
         logger.info("LLM Service initialized successfully")
     
-    def _initialize_components(self):
+    def _initialize_components(self, llm=None, embeddings=None, pinecone=None,
+                               web_search=None, vector_store_factory=None, vector_db=None):
         """Initialize LLM, embeddings, and vector store components."""
-        # Initialize OpenAI LLM
-        if not self.config.openai_api_key:
-            raise ValueError("OPENAI_API_KEY environment variable not set")
-        
-        self.llm = ChatOpenAI(
-            model="gpt-4o",
-            temperature=self.config.rag.temperature,
-            max_tokens=self.config.rag.max_tokens,
-            openai_api_key=self.config.openai_api_key
-        )
+        test_mode = self.config.test_mode
+        if test_mode:
+            logger.warning(f"LLM_TEST_MODE is on: using deterministic fakes (seed {self.config.test_seed}) instead of OpenAI and Pinecone")
+
+        if llm is not None or test_mode:
+            # A single injected or fake model; fallbacks need real providers
+            self.model_chain = [self.config.rag.model]
+            self.llms = {self.config.rag.model: llm if llm is not None else FakeChatModel(seed=self.config.test_seed)}
+            # It serves every request, light ones included
+            self.llm = self.light_llm = self.llms[self.config.rag.model]
+        else:
+            # Initialize OpenAI LLM
+            if not self.config.openai_api_key:
+                raise ValueError("OPENAI_API_KEY environment variable not set")
+            
+            def chat_model(model: str, max_tokens: int, **options) -> ChatOpenAI:
+                return ChatOpenAI(
+                    model=model,
+                    temperature=self.config.rag.temperature,
+                    max_tokens=max_tokens,
+                    openai_api_key=self.config.openai_api_key,
+                    # Report token counts on the last chunk for usage accounting
+                    stream_usage=True,
+                    **options
+                )
+
+            # Answers are generated by the primary model, then each fallback in
+            # turn; grading and routing always use the primary. Answers are
+            # retried on rate limits by retry_rate_limited, honouring
+            # retry-after, so the SDK must not retry them too
+            self.model_chain = [self.config.rag.model] + list(self.config.rag.fallback_models)
+            self.llms = {
+                model: chat_model(model, self.config.rag.max_tokens, max_retries=0)
+                for model in self.model_chain
+            }
+            if self.config.rag.fallback_models:
+                logger.info(f"LLM fallback chain: {' -> '.join(self.model_chain)}")
+            # Grading, routing and structured output are not retried by the
+            # service, so they keep the SDK's retries
+            self.llm = chat_model(self.config.rag.model, self.config.rag.max_tokens)
+            # Light structured requests get a cheaper model with a low token
+            # limit
+            self.light_llm = chat_model(self.config.rag.light_model or self.config.rag.model, self.config.rag.light_max_tokens)
         
-        # Initialize embeddings based on the configured model
+        # Initialize embeddings based on the configured model; unknown names
+        # fail here rather than when the first document is embedded
         embedding_model = self.config.vector_store.embedding_model
+        embedding_spec = resolve_embedding_model(embedding_model)
+        self.embedding_dimensions = embedding_spec.dimensions
+        self.embedding_max_tokens = embedding_spec.max_input_tokens
         
-        if embedding_model == "llama" or embedding_model.startswith("sentence-transformers"):
+        if embeddings is not None:
+            self.embeddings = embeddings
+            # Query embeddings are checked against collection sizes, so an
+            # injected model's own size counts
+            self.embedding_dimensions = getattr(embeddings, "dimensions", None) or self.embedding_dimensions
+        elif test_mode:
+            self.embeddings = FakeEmbeddings(self.embedding_dimensions, seed=self.config.test_seed)
+        elif embedding_spec.provider == PROVIDER_HUGGINGFACE:
             # Use HuggingFace sentence-transformers for llama or similar models
             from langchain_huggingface import HuggingFaceEmbeddings
             
-            # Map llama to a specific sentence-transformers model
-            if embedding_model == "llama":
-                model_name = "sentence-transformers/paraphrase-multilingual-MiniLM-L12-v2"
-            else:
-                model_name = embedding_model
-                
             self.embeddings = HuggingFaceEmbeddings(
-                model_name=model_name,
+                model_name=embedding_spec.model_name,
                 model_kwargs={'device': 'cpu'},  # Use CPU for compatibility
                 encode_kwargs={'normalize_embeddings': True}
             )
-            logger.info(f"Initialized HuggingFace embeddings with model: {model_name}")
+            logger.info(f"Initialized HuggingFace embeddings with model: {embedding_spec.model_name} ({self.embedding_dimensions} dims)")
         else:
-            # Default to OpenAI embeddings
             self.embeddings = OpenAIEmbeddings(
-                model=embedding_model,
+                model=embedding_spec.model_name,
                 openai_api_key=self.config.openai_api_key
             )
-            logger.info(f"Initialized OpenAI embeddings with model: {embedding_model}")
+            logger.info(f"Initialized OpenAI embeddings with model: {embedding_spec.model_name} ({self.embedding_dimensions} dims)")
         
-        # Initialize Pinecone
-        if hasattr(self.config, 'pinecone_api_key') and self.config.pinecone_api_key:
-            self.pinecone = Pinecone(api_key=self.config.pinecone_api_key)
+        # Every vector store embeds with this one model, so ingestion and
+        # retrieval are normalized alike
+        if self.config.vector_store.normalize_embeddings:
+            self.embeddings = NormalizedEmbeddings(self.embeddings)
+            logger.info("Embeddings are normalized to unit length")
+        
+        # Initialize the vector database
+        self.vector_db = vector_db if vector_db is not None else self._build_vector_db(pinecone, vector_store_factory)
+        self.collection_dimensions = CollectionDimensions(
+            lambda name: self.vector_db.describe_collection(name).dimension,
+            self.config.vector_store.collection_dimensions,
+        )
+        self.knowledge_base = KnowledgeBaseMonitor(self.vector_db, self.config.rag.knowledge_base_check_ttl_seconds)
+        if self.vector_db:
+            self._validate_index_dimensions()
             self._initialize_vector_store()
             self._initialize_vietnamese_vector_store()
         else:
@@ -138,24 +334,92 @@ class LLMServicer(llm_pb2_grpc.LLMServiceServicer):
             self.vietnamese_vector_store = None
         
         # Initialize web search
-        if self.config.rag.web_search_enabled and self.config.rag.web_search_api_key:
+        if web_search is not None:
+            self.web_search = web_search
+        elif test_mode:
+            self.web_search = None
+        elif self.config.rag.web_search_enabled and self.config.rag.web_search_api_key:
             self.web_search = TavilySearchResults(
                 api_key=self.config.rag.web_search_api_key,
-                max_results=5
+                max_results=self.config.rag.web_search_max_results,
+                search_depth=self.config.rag.web_search_depth
             )
         else:
             logger.warning("Web search disabled or API key not provided")
             self.web_search = None
         
-        # Initialize text splitter
-        self.text_splitter = RecursiveCharacterTextSplitter(
-            chunk_size=self.config.rag.chunk_size,
-            chunk_overlap=self.config.rag.chunk_overlap
+        # Initialize text splitter; chunks too long for the embedder are
+        # split again rather than rejected or truncated when embedded
+        self.text_splitter = TokenLimitedSplitter(
+            RecursiveCharacterTextSplitter(
+                chunk_size=self.config.rag.chunk_size,
+                chunk_overlap=self.config.rag.chunk_overlap
+            ),
+            self.embedding_max_tokens,
+            self._resplitter,
         )
         
+        # Redacts documents whose ingest request asks for scrubbing
+        self.scrubber = Scrubber(self.config.vector_store.scrub_blocklist)
+        
         # Initialize adaptive RAG components
         self._initialize_adaptive_rag_components()
     
+    def _resplitter(self, chunk_size: int) -> RecursiveCharacterTextSplitter:
+        """Splitter for chunks over the embedder's limit, with the configured
+        overlap scaled down to the smaller chunks."""
+        overlap = min(self.config.rag.chunk_overlap, chunk_size // 5)
+        return RecursiveCharacterTextSplitter(chunk_size=chunk_size, chunk_overlap=overlap)
+
+    def _build_vector_db(self, pinecone=None, vector_store_factory=None) -> Optional[VectorStore]:
+        """Build the configured vector database, or None without one.
+
+        An injected Pinecone client, or test mode's fake one, is used
+        whatever the configured backend.
+        """
+        vector_config = self.config.vector_store
+        if pinecone is None and self.config.test_mode:
+            pinecone = FakePineconeClient(self.embedding_dimensions)
+        if pinecone is None and vector_config.backend == BACKEND_MEMORY:
+            logger.info("Using the in-memory vector store; documents are lost on restart")
+            return MemoryVectorStore(self.embeddings, self.embedding_dimensions, [vector_config.default_index])
+        if pinecone is None and self.config.pinecone_api_key:
+            pinecone = Pinecone(api_key=self.config.pinecone_api_key)
+        if pinecone is None:
+            return None
+
+        if vector_store_factory is None:
+            vector_store_factory = fake_vector_store_factory if self.config.test_mode else self._pinecone_vector_store
+        return PineconeStore(
+            pinecone,
+            self.embeddings,
+            vector_store_factory,
+            spec=ServerlessSpec(cloud="aws", region=vector_config.pinecone_environment),
+        )
+
+    @staticmethod
+    def _pinecone_vector_store(index, embeddings) -> PineconeVectorStore:
+        """Wrap a Pinecone index in a LangChain vector store."""
+        return PineconeVectorStore(index=index, embedding=embeddings, text_key=PINECONE_TEXT_KEY)
+
+    def _validate_index_dimensions(self):
+        """Refuse to start when a known collection was built with vectors of
+        a different size than the embedding model produces.
+
+        Known collections are the default index, the configured known
+        indexes and collection sizes, and those created through the service.
+        Ones that cannot be described, e.g. because they are missing, are
+        skipped; _initialize_vector_store reports a missing default index.
+        """
+        vs_config = self.config.vector_store
+        names = [vs_config.default_index, *vs_config.known_indexes,
+                 *vs_config.collection_dimensions, *self.collection_registry.names()]
+        self.collection_dimensions.check(
+            vs_config.embedding_model,
+            self.embedding_dimensions,
+            list(dict.fromkeys(names)),
+        )
+
     def _initialize_vector_store(self):
         """Initialize vector store connection."""
         try:
@@ -166,16 +430,10 @@ class LLMServicer(llm_pb2_grpc.LLMServiceServicer):
                 raise ValueError("No index name specified in configuration")
 
             # Connect to existing index
-            index = self.pinecone.Index(name=index_name)
-            
-            # Create LangChain Pinecone wrapper
-            self.vector_store = PineconeVectorStore(
-                index=index,
-                embedding=self.embeddings,
-                text_key="text"
-            )
+            self.vector_db.connect(index_name)
+            self.vector_store = CollectionHandle(self.vector_db, index_name)
             
-            logger.info(f"Connected to Pinecone index: {index_name}")
+            logger.info(f"Connected to vector store index: {index_name}")
             
         except Exception as e:
             logger.error(f"Failed to initialize vector store: {e}")
@@ -195,16 +453,10 @@ class LLMServicer(llm_pb2_grpc.LLMServiceServicer):
                 raise ValueError("No Vietnamese index name specified")
         
             # Connect to Vietnamese index with explicit name
-            vietnamese_index = self.pinecone.Index(name=index_name)
-            
-            # Create LangChain Pinecone wrapper with Llama embeddings (384 dimensions)
-            self.vietnamese_vector_store = PineconeVectorStore(
-                index=vietnamese_index,
-                embedding=self.embeddings,  # Use Llama embeddings
-                text_key="text"
-            )
+            self.vector_db.connect(index_name)
+            self.vietnamese_vector_store = CollectionHandle(self.vector_db, index_name)
             
-            logger.info(f"Connected to Vietnamese Pinecone index: {vietnamese_index}")
+            logger.info(f"Connected to Vietnamese vector store index: {index_name}")
             
         except Exception as e:
             logger.error(f"Failed to initialize Vietnamese vector store: {e}")
@@ -252,6 +504,8 @@ Nếu bạn không biết câu trả lời dựa trên tài liệu, hãy nói r
 - Phương thức xét tuyển
 - Năm áp dụng
 
+{length_instruction}
+
 Câu hỏi: {question}
 
 Thông tin tham khảo: {context}
@@ -271,6 +525,8 @@ For admission score and university information, provide:
 - Admission methods
 - Applicable year
 
+{length_instruction}
+
 Question: {question}
 
 Context: {context}
@@ -312,18 +568,77 @@ Answer:"""
         # Default to web search for other queries
         return QueryRoute.WEB_SEARCH
     
-    async def _retrieve_documents(self, query: str, top_k: int = None) -> List[Document]:
-        """Retrieve documents from vector store."""
+    def _vector_store_for(self, collection: str) -> CollectionHandle:
+        """Return the vector store for a collection (index)."""
+        if collection == self.config.vector_store.default_index:
+            return self.vector_store
+
+        def open_store():
+            self.vector_db.connect(collection)
+            logger.info(f"Connected to vector store index: {collection}")
+            return CollectionHandle(self.vector_db, collection)
+        return self._collection_stores.get(collection, open_store)
+
+    def _forget_collection(self, collection: str) -> None:
+        """Drop what is remembered of a collection that was created, cleared
+        or deleted: its open store, its vector size and the knowledge base's
+        document count. The admin API acts through this servicer, so its
+        changes are seen by the gRPC traffic as well."""
+        self._collection_stores.evict(collection)
+        self.collection_dimensions.forget(collection)
+        self.knowledge_base.forget()
+
+    async def _check_collection_dimensions(self, collections: List[str]):
+        """Raise EmbeddingDimensionMismatch if a collection holds vectors of
+        another size than query embeddings. Similarity to them is
+        meaningless rather than an error, so searches must not reach them."""
+        await asyncio.get_event_loop().run_in_executor(None, lambda: self.collection_dimensions.check(
+            self.config.vector_store.embedding_model, self.embedding_dimensions, collections
+        ))
+
+    async def _knowledge_base_empty(self) -> bool:
+        """Report whether onboarding mode is on and no collection holds
+        documents. A failed check counts as not empty, so retrieval runs
+        and reports its own errors."""
+        if not self.config.rag.onboarding_mode or not self.vector_db:
+            return False
+        try:
+            status = await asyncio.get_event_loop().run_in_executor(None, self.knowledge_base.status)
+        except Exception as e:
+            logger.warning(f"Could not check whether the knowledge base is empty: {e}")
+            return False
+        return status.empty
+
+    async def _retrieve_documents(self, query: str, top_k: int = None,
+                                  collections: List[str] = None) -> List[Document]:
+        """Retrieve documents from one or more collections concurrently.
+
+        Each collection is asked for oversampled candidates, and the best
+        top_k across them are kept; see retrieval_limits.
+
+        Raises:
+            EmbeddingDimensionMismatch: A collection holds vectors of another
+                size than the query embeddings
+        """
         if not self.vector_store:
             return []
         
+        collections = collections or [self.config.vector_store.default_index]
+        await self._check_collection_dimensions(collections)
+        loop = asyncio.get_event_loop()
         try:
-            top_k = top_k or self.config.rag.retrieval_top_k
-            docs = await asyncio.get_event_loop().run_in_executor(
-                None, 
-                lambda: self.vector_store.similarity_search(query, k=top_k)
-            )
-            logger.info(f"Retrieved {len(docs)} documents for query")
+            rag = self.config.rag
+            top_k, fetch_k = retrieval_limits(top_k or rag.retrieval_top_k,
+                                              rag.retrieval_oversample, rag.retrieval_max_top_k)
+
+            async def search(collection: str):
+                return await loop.run_in_executor(
+                    None,
+                    lambda: self._vector_store_for(collection).similarity_search_with_score(query, k=fetch_k)
+                )
+
+            docs = await retrieve_from_collections(search, collections, top_k, self.retrieval_boosts)
+            logger.info(f"Retrieved {len(docs)} documents for query from {len(collections)} collection(s)")
             return docs
         except Exception as e:
             logger.error(f"Error retrieving documents: {e}")
@@ -341,13 +656,17 @@ Answer:"""
             )
             
             documents = []
+            max_chars = self.config.rag.web_search_max_content_chars
             for result in results:
+                # Long pages would crowd knowledge base documents out of the context
+                content = result.get("content", "")
                 doc = Document(
-                    page_content=result.get("content", ""),
+                    page_content=truncate_text(content, max_chars),
                     metadata={
                         "source": result.get("url", "web_search"),
                         "title": result.get("title", ""),
-                        "type": "web_search"
+                        "type": "web_search",
+                        "original_length": len(content)
                     }
                 )
                 documents.append(doc)
@@ -359,8 +678,13 @@ Answer:"""
             logger.error(f"Error in web search: {e}")
             return []
 
-    def _grade_documents(self, documents: List[Document], query: str) -> List[Document]:
-        """Grade documents for relevance using structured LLM grader."""
+    def _grade_documents(self, documents: List[Document], query: str,
+                         verdicts: Optional[List[str]] = None) -> List[Document]:
+        """Grade documents for relevance using structured LLM grader.
+
+        The verdict for each document ("yes", "no" or "error") is appended
+        to verdicts when given.
+        """
         if not documents:
             return documents
             
@@ -378,6 +702,8 @@ Answer:"""
                 
                 score = self.grade_documents_llm.invoke(messages)
                 grade = score.binary_score
+                if verdicts is not None:
+                    verdicts.append(grade)
                 
                 if grade == "yes":
                     logger.info("---GRADE: DOCUMENT RELEVANT---")
@@ -388,6 +714,8 @@ Answer:"""
                     
             except Exception as e:
                 logger.error(f"Error grading document: {e}")
+                if verdicts is not None:
+                    verdicts.append("error")
                 # On error, include the document to be safe
                 filtered_docs.append(doc)
         
@@ -449,89 +777,344 @@ Answer:"""
             logger.error(f"Error routing query: {e}")
             return self._route_query(query)  # Fallback to keyword-based routing
     
-    def _build_vietnamese_rag_prompt(self, query: str, documents: List[Document]) -> str:
+    def _build_rag_prompt(self, query: str, documents: List[Document],
+                          verbosity: str = VERBOSITY_NORMAL, cite: bool = False) -> str:
+        """Build the RAG prompt in the query's language, telling the model
+        to cite its sources when cite is set."""
+        if self._is_vietnamese_text(query):
+            return self._build_vietnamese_rag_prompt(query, documents, verbosity, cite)
+        return self._build_english_rag_prompt(query, documents, verbosity, cite)
+    
+    def _build_vietnamese_rag_prompt(self, query: str, documents: List[Document],
+                                     verbosity: str = VERBOSITY_NORMAL, cite: bool = False) -> str:
         """Build Vietnamese-specific RAG prompt using enhanced template."""
+        instruction = length_instruction(verbosity, vietnamese=True)
+        if cite:
+            instruction += f"\n{citation_instruction(vietnamese=True)}"
         if not documents:
             return f"""Bạn là một trợ lý AI chuyên về hướng nghiệp và giáo dục tại Việt Nam.
 Tôi không có thông tin cụ thể cho câu hỏi này, vì vậy tôi sẽ cung cấp câu trả lời chung dựa trên kiến thức của mình.
+{instruction}
 
 Câu hỏi: {query}
 
 Trả lời:"""
         
-        context = ""
-        for i, doc in enumerate(documents, 1):
-            source = doc.metadata.get("source", "cơ sở dữ liệu")
-            context += f"\n[Nguồn {i} - {source}]:\n{doc.page_content}\n"
+        context = format_context(documents, "Nguồn", "cơ sở dữ liệu", "Mục")
         
-        return self.vietnamese_rag_prompt.format(question=query, context=context)
+        return self.vietnamese_rag_prompt.format(question=query, context=context, length_instruction=instruction)
     
-    def _build_english_rag_prompt(self, query: str, documents: List[Document]) -> str:
+    def _build_english_rag_prompt(self, query: str, documents: List[Document],
+                                  verbosity: str = VERBOSITY_NORMAL, cite: bool = False) -> str:
         """Build English RAG prompt using enhanced template."""
+        instruction = length_instruction(verbosity, vietnamese=False)
+        if cite:
+            instruction += f"\n{citation_instruction(vietnamese=False)}"
         if not documents:
             return f"""You are an AI assistant specializing in career guidance and education in Vietnam.
 I don't have specific information for this question, so I'll provide a general answer based on my knowledge.
+{instruction}
 
 Question: {query}
 
 Answer:"""
         
-        context = ""
-        for i, doc in enumerate(documents, 1):
-            source = doc.metadata.get("source", "database")
-            context += f"\n[Source {i} - {source}]:\n{doc.page_content}\n"
+        context = format_context(documents, "Source", "database", "Section")
         
-        return self.english_rag_prompt.format(question=query, context=context)
-        if not documents:
-            return f"""You are an AI assistant helping with career guidance and educational content.
-I don't have specific context available for this question, so I'll provide a general response based on my knowledge.
+        return self.english_rag_prompt.format(question=query, context=context, length_instruction=instruction)
+    
+    async def Shutdown(self, grace: float = None) -> int:
+        """Stop the startup warmup, and cancel generation streams still
+        running after the grace period.
 
-Question: {query}
+        Returns:
+            Number of streams that were cancelled
+        """
+        if self._warmup_task and not self._warmup_task.done():
+            self._warmup_task.cancel()
+            try:
+                await self._warmup_task
+            except asyncio.CancelledError:
+                pass
+        if grace is None:
+            grace = self.config.shutdown_grace_seconds
+        return await self.streams.shutdown(grace)
+    
+    @staticmethod
+    def _request_params(request):
+        """Return the request's sampling overrides, or None when it has none."""
+        return request.params if request.HasField("params") else None
 
-Answer:"""
-        
-        context = ""
-        for i, doc in enumerate(documents, 1):
-            source = doc.metadata.get("source", "knowledge base")
-            context += f"\n[Source {i} - {source}]:\n{doc.page_content}\n"
-        
-        return f"""You are an AI assistant helping with career guidance and educational content.
-Use the following retrieved context to answer the question accurately and helpfully.
-If the context doesn't contain enough information, say so clearly.
-Keep your answer concise but comprehensive.
+    @staticmethod
+    def _record_fallback(failed_model: str, next_model: str, error: BaseException):
+        get_metrics_collector().record_fallback(failed_model, next_model)
 
-Context from retrieved sources:{context}
+    def _stream_tokens(self, prompt: str, params, meter: Optional[UsageMeter] = None,
+                       finish: Optional[Dict[str, str]] = None, max_tokens: Optional[int] = None):
+        """Stream a generation through the model chain.
 
-Question: {query}
+        Yields (event, value) tuples from stream_with_fallback: tokens, and a
+        fallback event whenever a model fails and the next one starts over.
+        A rate-limited model is retried after the wait it asks for, within
+        the configured bounds, before falling back. Every model attempt,
+        including failed ones, is counted on meter. The
+        finish reason the model reports, such as FINISH_LENGTH, is stored in
+        finish["reason"]. The answer ends before the first configured or
+        requested stop sequence, which is not streamed. max_tokens, when
+        given, replaces the configured token limit. The configured
+        guardrails lead the prompt.
+        """
+        prompt = guarded_prompt(self.config.rag.guardrails_prompt, prompt)
+        stops = resolve_stop_sequences(params, self.config.rag)
 
-Answer:"""
-    
+        async def open_stream(model: str):
+            llm = bind_generation_options(self.llms[model], params, self.config.rag, max_tokens)
+            stop_filter = StopSequenceFilter(stops)
+            completion = []
+            usage_metadata = {}
+            try:
+                async for chunk in llm.astream(prompt):
+                    for key, value in (getattr(chunk, 'usage_metadata', None) or {}).items():
+                        if isinstance(value, int):
+                            usage_metadata[key] = usage_metadata.get(key, 0) + value
+                    reason = (getattr(chunk, 'response_metadata', None) or {}).get('finish_reason')
+                    if reason and finish is not None:
+                        finish["reason"] = reason
+                    token = getattr(chunk, 'content', None)
+                    if token:
+                        completion.append(token)
+                        text = stop_filter.feed(token)
+                        if text:
+                            yield text
+                    if stop_filter.stopped:
+                        # The model went past a stop sequence it did not honour
+                        if finish is not None:
+                            finish["reason"] = FINISH_STOP
+                        break
+                tail = stop_filter.flush()
+                if tail:
+                    yield tail
+            finally:
+                if meter is not None:
+                    meter.add(prompt, "".join(completion), usage_metadata)
+
+        def open_with_retries(model: str):
+            return retry_rate_limited(
+                lambda: open_stream(model),
+                self.config.rag.rate_limit_max_wait_seconds,
+                self.config.rag.rate_limit_max_retries,
+            )
+
+        return stream_with_fallback(self.model_chain, open_with_retries, on_fallback=self._record_fallback)
+
+    @staticmethod
+    def _fail_rate_limited(context, error: BaseException):
+        """Fail a call whose generation stayed rate limited on every model.
+
+        The retry-after trailer, in seconds, tells clients when to try again
+        and sets this apart from an exhausted quota, which has the same code.
+        """
+        logger.warning(f"Generation rate limited on every model: {error}")
+        context.set_code(grpc.StatusCode.RESOURCE_EXHAUSTED)
+        context.set_details(HIGH_DEMAND_MESSAGE)
+        context.set_trailing_metadata((("retry-after", retry_after_header(error)),))
+
+    async def _check_quota(self, user_id: str, context) -> bool:
+        """Report whether the user may generate, failing the call if not.
+
+        Calls without a user ID are not metered. The usage store's file I/O
+        runs in the executor, off the event loop.
+        """
+        if not user_id:
+            return True
+        try:
+            await asyncio.get_event_loop().run_in_executor(
+                None, self.usage.check_quota, user_id, self.config.monthly_token_quota
+            )
+        except QuotaExceeded as e:
+            logger.info(f"Refusing generation for user_id={user_id}: {e}")
+            context.set_code(grpc.StatusCode.RESOURCE_EXHAUSTED)
+            context.set_details(str(e))
+            return False
+        return True
+
+    def _debug_authorized(self, context) -> bool:
+        """Report whether the caller presented the admin API key."""
+        metadata = dict(context.invocation_metadata() or ()) if context else {}
+        key = metadata.get("x-admin-api-key", "")
+        return bool(key) and hmac.compare_digest(key, self.config.admin_api_key)
+
+    @staticmethod
+    def _rag_debug(state: RAGState) -> llm_pb2.RAGDebug:
+        """Describe how a RAG answer was produced, for debug requests."""
+        verdicts = state.relevance_scores or []
+        documents = []
+        for i, doc in enumerate(state.retrieved or []):
+            metadata = doc.metadata or {}
+            document = llm_pb2.RAGDebugDocument(
+                id=str(getattr(doc, "id", None) or metadata.get("source", "")),
+                source=str(metadata.get("source", "")),
+                collection=str(metadata.get("collection", "")),
+                relevance=verdicts[i] if i < len(verdicts) else "",
+                used=any(doc is used for used in state.documents),
+            )
+            if metadata.get("score") is not None:
+                document.score = float(metadata["score"])
+            if metadata.get("base_score") is not None:
+                document.base_score = float(metadata["base_score"])
+            documents.append(document)
+        return llm_pb2.RAGDebug(
+            route=state.route.value,
+            documents=documents,
+            hallucination_check=state.hallucination_score,
+            attempts=state.iteration,
+            knowledge_base_empty=state.knowledge_base_empty,
+            notice=EMPTY_KNOWLEDGE_BASE_NOTICE if state.knowledge_base_empty else "",
+            citations_retried=state.citations_retried,
+        )
+
+    async def _record_usage(self, user_id: str, meter: UsageMeter):
+        """Add a request's metered tokens to the user's usage, in the
+        executor."""
+        if not user_id:
+            return
+        try:
+            await asyncio.get_event_loop().run_in_executor(
+                None, self.usage.record, user_id, meter.prompt_tokens, meter.completion_tokens
+            )
+        except OSError as e:
+            logger.error(f"Failed to record usage for user_id={user_id}: {e}")
+
+    @tracked_stream
     async def GenerateStream(self, request, context):
-        """Handle basic streaming generation requests."""
-        logger.info(f"GenerateStream request: user_id={request.user_id}, prompt='{request.prompt[:100]}...'")
+        """Handle streaming generation requests, with retrieval when use_rag is set."""
+        logger.info(f"GenerateStream request: user_id={request.user_id}, use_rag={request.use_rag}, prompt='{request.prompt[:100]}...'")
+        if request.use_rag:
+            async for response in self._rag_responses(self._rag_request(request), context):
+                stream_response = llm_pb2.GenerateStreamResponse(
+                    token=response.token,
+                    status=response.status,
+                    sources=response.sources,
+                    truncated=response.truncated,
+                )
+                if response.HasField("debug"):
+                    stream_response.debug.CopyFrom(response.debug)
+                yield stream_response
+            return
+        if not await self._check_quota(request.user_id, context):
+            return
         
+        meter = UsageMeter()
         try:
-            # Stream response from LLM
-            async for chunk in self.llm.astream(request.prompt):
-                if hasattr(chunk, 'content'):
-                    token = chunk.content
-                    if token:
-                        yield llm_pb2.GenerateStreamResponse(token=token)
+            sent = False
+            async for event, value in self._stream_tokens(request.prompt, self._request_params(request), meter):
+                if event == EVENT_FALLBACK:
+                    if sent:
+                        yield llm_pb2.GenerateStreamResponse(status=PipelineStatus.RESTARTING.value)
+                        sent = False
+                    continue
+                yield llm_pb2.GenerateStreamResponse(token=value)
+                sent = True
                         
         except Exception as e:
+            if is_rate_limit(e):
+                self._fail_rate_limited(context, e)
+                return
             logger.error(f"Error in GenerateStream: {e}")
             yield llm_pb2.GenerateStreamResponse(token=f"Error: {str(e)}")
+        finally:
+            await self._record_usage(request.user_id, meter)
     
+    @staticmethod
+    def _rag_request(request) -> llm_pb2.GenerateWithRAGRequest:
+        """The GenerateWithRAG request a GenerateStream request with use_rag
+        stands for."""
+        rag_request = llm_pb2.GenerateWithRAGRequest(
+            prompt=request.prompt,
+            user_id=request.user_id,
+            conversation_id=request.conversation_id,
+            rag_collections=request.rag_collections,
+            adaptive=request.adaptive,
+            debug=request.debug,
+            continue_from=request.continue_from,
+            verbosity=request.verbosity,
+        )
+        if request.HasField("params"):
+            rag_request.params.CopyFrom(request.params)
+        if request.HasField("strict_grounding"):
+            rag_request.strict_grounding = request.strict_grounding
+        return rag_request
+
+    @tracked_stream
     async def GenerateWithRAG(self, request, context):
-        """Handle adaptive RAG-augmented streaming generation requests."""
-        logger.info(f"GenerateWithRAG request: user_id={request.user_id}, collection={request.rag_collection}, adaptive={request.adaptive}")
-        
+        """Handle adaptive RAG-augmented streaming generation requests.
+
+        Deprecated in favour of GenerateStream with use_rag, which runs the
+        same pipeline.
+        """
+        async for response in self._rag_responses(request, context):
+            yield response
+
+    async def _rag_responses(self, request, context):
+        """Stream the answer to a GenerateWithRAG request.
+
+        Identical concurrent requests share one generation (see
+        coalescing_key), charged to each of their users as if it were
+        theirs alone: what it used by the time they stopped reading.
+        """
+        logger.info(f"GenerateWithRAG request: user_id={request.user_id}, collection={request.rag_collection}, collections={list(request.rag_collections)}, adaptive={request.adaptive}, verbosity={request.verbosity or VERBOSITY_NORMAL}, debug={request.debug}")
+        if request.debug and not self._debug_authorized(context):
+            context.set_code(grpc.StatusCode.PERMISSION_DENIED)
+            context.set_details("debug requires the admin API key")
+            return
+        if not await self._check_quota(request.user_id, context):
+            return
+
+        collections = resolve_collections(
+            list(request.rag_collections) or [request.rag_collection],
+            self.config.vector_store.default_index,
+        )
+        key = coalescing_key(request, collections) if self.config.rag.coalesce_requests else None
+        meter = charged = UsageMeter()
+
+        def charge(used: UsageMeter):
+            nonlocal charged
+            charged = used
+
+        if key is None:
+            responses = self._generate_with_rag(request, collections, meter)
+        else:
+            responses = self.coalescer.stream(
+                key, lambda: self._generate_with_rag(request, collections, meter), shared=meter, on_leave=charge,
+            )
+        try:
+            async for response in responses:
+                yield response
+        except EmbeddingDimensionMismatch as e:
+            logger.error(f"Refusing to search with mismatched embeddings: {e}")
+            context.set_code(grpc.StatusCode.FAILED_PRECONDITION)
+            context.set_details(str(e))
+        except Exception as e:
+            # Only rate limits and embedding size mismatches escape
+            # _generate_with_rag, reaching every request sharing the generation
+            if not is_rate_limit(e):
+                raise
+            self._fail_rate_limited(context, e)
+        finally:
+            # Stop reading before charging, so a shared generation is
+            # charged as far as this request got
+            await responses.aclose()
+            await self._record_usage(request.user_id, charged)
+
+    async def _generate_with_rag(self, request, collections: List[str], meter: UsageMeter):
+        """Run the RAG pipeline for a request, counting its tokens in meter."""
         try:
             # Initialize RAG state
             state = RAGState(
                 question=request.prompt,
                 documents=[],
-                max_retries=self.config.rag.max_retries
+                max_retries=self.config.rag.max_retries,
+                relevance_scores=[],
             )
             
             # Use adaptive routing if enabled
@@ -539,26 +1122,82 @@ Answer:"""
                 route = self._route_query_with_llm(request.prompt)
             else:
                 route = self._route_query(request.prompt)
+            # Strict grounding holds only to questions for the knowledge base;
+            # others were never meant to be answered from documents
+            strict = route == QueryRoute.VECTORSTORE and strict_grounding_enabled(request, self.config.rag)
+            # While onboarding there is nothing to retrieve; fall back as a
+            # search that found nothing relevant would
+            if route == QueryRoute.VECTORSTORE and await self._knowledge_base_empty():
+                logger.info("Knowledge base is empty, skipping retrieval")
+                state.knowledge_base_empty = True
+                route = QueryRoute.WEB_SEARCH if self.web_search and request.adaptive else QueryRoute.DIRECT_LLM
             state.route = route
             
             # Retrieve documents based on route
-            if route == QueryRoute.VECTORSTORE:
-                docs = await self._retrieve_documents(request.prompt)
-                # Grade documents for relevance using LLM grader
-                relevant_docs = self._grade_documents(docs, request.prompt)
-                state.documents = relevant_docs
-                
-                # Fallback to web search if no relevant documents and adaptive mode
-                if not relevant_docs and self.web_search and request.adaptive:
+            if route == QueryRoute.VECTORSTORE and self.web_search and request.adaptive:
+                yield llm_pb2.GenerateWithRAGResponse(status=PipelineStatus.RETRIEVING.value)
+                yield llm_pb2.GenerateWithRAGResponse(status=PipelineStatus.SEARCHING_WEB.value)
+                # A failed vector search falls back to the web; a mismatched
+                # collection is a misconfiguration that must not
+                if self.vector_store:
+                    await self._check_collection_dimensions(collections)
+                # Query both sources at once so the web fallback adds no latency
+                vector_docs, web_docs = await gather_sources(
+                    lambda: self._retrieve_documents(request.prompt, collections=collections),
+                    lambda: self._web_search_documents(request.prompt),
+                )
+                state.retrieved = merge_documents(vector_docs, web_docs)
+                relevant_docs = self._grade_documents(
+                    state.retrieved, request.prompt, state.relevance_scores
+                )
+                if relevant_docs:
+                    state.documents = relevant_docs
+                else:
                     logger.info("No relevant documents found, falling back to web search")
-                    web_docs = await self._web_search_documents(request.prompt)
                     state.documents = web_docs
                     state.route = QueryRoute.WEB_SEARCH
+
+            elif route == QueryRoute.VECTORSTORE:
+                yield llm_pb2.GenerateWithRAGResponse(status=PipelineStatus.RETRIEVING.value)
+                docs = await self._retrieve_documents(request.prompt, collections=collections)
+                state.retrieved = docs
+                # Grade documents for relevance using LLM grader
+                relevant_docs = self._grade_documents(docs, request.prompt, state.relevance_scores)
+                state.documents = relevant_docs
                     
             elif route == QueryRoute.WEB_SEARCH:
+                yield llm_pb2.GenerateWithRAGResponse(status=PipelineStatus.SEARCHING_WEB.value)
                 docs = await self._web_search_documents(request.prompt)
+                state.retrieved = docs
                 state.documents = docs
+
+            deduped = dedupe_documents(state.documents, self.config.rag.dedup_similarity_threshold)
+            if len(deduped) < len(state.documents):
+                logger.info(f"Dropped {len(state.documents) - len(deduped)} duplicate documents")
+            state.documents = deduped
             
+            if not state.documents and strict:
+                logger.info("No relevant documents found, answering with the no-results message")
+                yield llm_pb2.GenerateWithRAGResponse(
+                    token=no_results_message(self.config.rag, self._is_vietnamese_text(request.prompt))
+                )
+                if request.debug:
+                    yield llm_pb2.GenerateWithRAGResponse(debug=self._rag_debug(state))
+                return
+            
+            yield llm_pb2.GenerateWithRAGResponse(
+                status=PipelineStatus.GENERATING.value,
+                sources=[llm_pb2.Source(**source) for source in document_sources(state.documents)]
+            )
+            
+            params = self._request_params(request)
+            verbosity = resolve_verbosity(request)
+            max_tokens = verbosity_max_tokens(verbosity, self.config.rag)
+            # A continuation is checked as part of the whole answer, which
+            # the client already has, so it is streamed as it comes
+            check_grounding = request.adaptive and not request.continue_from
+            finish = {}
+
             # Generate response with retry logic for hallucination checking
             for attempt in range(state.max_retries):
                 state.iteration = attempt + 1
@@ -566,29 +1205,26 @@ Answer:"""
                 # Build prompt based on language and documents
                 is_vietnamese = self._is_vietnamese_text(request.prompt)
                 
-                if is_vietnamese:
-                    prompt = self._build_vietnamese_rag_prompt(request.prompt, state.documents)
-                else:
-                    prompt = self._build_english_rag_prompt(request.prompt, state.documents)
+                prompt = self._build_rag_prompt(request.prompt, state.documents, verbosity)
+                if request.continue_from:
+                    prompt = continuation_prompt(prompt, request.continue_from)
                 
                 logger.info(f"Generating {'Vietnamese' if is_vietnamese else 'English'} RAG response (attempt {attempt + 1}) with {len(state.documents)} documents")
                 
                 # Generate response
-                full_response = ""
-                async for chunk in self.llm.astream(prompt):
-                    if hasattr(chunk, 'content'):
-                        token = chunk.content
-                        if token:
-                            full_response += token
-                            # Stream tokens in real-time only on final attempt or if not checking hallucinations
-                            if not request.adaptive or attempt == state.max_retries - 1:
-                                yield llm_pb2.GenerateWithRAGResponse(token=token)
+                answer: List[str] = []
+                # Stream tokens in real-time only on final attempt or if not checking hallucinations
+                stream_live = not check_grounding or attempt == state.max_retries - 1
+                async for response in self._rag_answer(prompt, params, meter, finish, max_tokens, stream_live, answer):
+                    yield response
+                full_response = "".join(answer)
                 
                 state.generation = full_response
                 
                 # Check for hallucinations if adaptive mode and we have documents
-                if request.adaptive and state.documents and attempt < state.max_retries - 1:
+                if check_grounding and state.documents and attempt < state.max_retries - 1:
                     is_grounded = self._grade_hallucinations(full_response, state.documents)
+                    state.hallucination_score = "grounded" if is_grounded else "not_grounded"
                     if is_grounded:
                         logger.info("Generation is grounded, streaming response")
                         # If we didn't stream earlier, stream now
@@ -602,42 +1238,233 @@ Answer:"""
                 else:
                     # No hallucination checking or final attempt
                     break
+
+            if self._needs_citations(request, state):
+                logger.info("Answer cites none of its documents, generating it again asking for citations")
+                state.citations_retried = True
+                if state.generation:
+                    yield llm_pb2.GenerateWithRAGResponse(status=PipelineStatus.RESTARTING.value)
+                prompt = self._build_rag_prompt(request.prompt, state.documents, verbosity, cite=True)
+                answer = []
+                async for response in self._rag_answer(prompt, params, meter, finish, max_tokens, True, answer):
+                    yield response
+                state.generation = "".join(answer)
+                if not cites_sources(state.generation, state.documents):
+                    logger.warning("Answer still cites none of its documents")
+
+            if finish.get("reason") == FINISH_LENGTH:
+                logger.info("Answer cut off at the token limit")
+                yield llm_pb2.GenerateWithRAGResponse(truncated=True)
+
+            if request.debug:
+                yield llm_pb2.GenerateWithRAGResponse(debug=self._rag_debug(state))
                         
         except Exception as e:
+            if is_rate_limit(e) or isinstance(e, EmbeddingDimensionMismatch):
+                raise
             logger.error(f"Error in GenerateWithRAG: {e}")
             yield llm_pb2.GenerateWithRAGResponse(token=f"Error: {str(e)}")
     
+    async def _rag_answer(self, prompt: str, params, meter: UsageMeter, finish: Dict[str, str],
+                          max_tokens: int, live: bool, answer: List[str]):
+        """Generate an answer to a RAG prompt, collecting its tokens in
+        answer and, when live, yielding them as GenerateWithRAG responses.
+
+        A fallback model starts the answer over; a client that already got
+        tokens is told to drop them.
+        """
+        finish.clear()
+        async for event, token in self._stream_tokens(prompt, params, meter, finish, max_tokens):
+            if event == EVENT_FALLBACK:
+                if live and answer:
+                    yield llm_pb2.GenerateWithRAGResponse(status=PipelineStatus.RESTARTING.value)
+                answer.clear()
+                continue
+            answer.append(token)
+            if live:
+                yield llm_pb2.GenerateWithRAGResponse(token=token)
+    
+    def _needs_citations(self, request, state: RAGState) -> bool:
+        """Whether the answer must be generated again for citing none of
+        its documents. Continuations are judged with the whole answer, which
+        the client already has."""
+        if not self.config.rag.require_citations or not state.documents or request.continue_from:
+            return False
+        return not cites_sources(state.generation, state.documents)
+    
+    async def GetUsage(self, request, context):
+        """Return a user's token usage this month against the quota."""
+        if not request.user_id:
+            context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
+            context.set_details("user_id is required")
+            return llm_pb2.GetUsageResponse()
+
+        usage = await asyncio.get_event_loop().run_in_executor(None, self.usage.get, request.user_id)
+        quota = self.config.monthly_token_quota
+        return llm_pb2.GetUsageResponse(
+            user_id=request.user_id,
+            period=usage.period,
+            prompt_tokens=usage.prompt_tokens,
+            completion_tokens=usage.completion_tokens,
+            total_tokens=usage.total_tokens,
+            requests=usage.requests,
+            quota=quota,
+            remaining=max(0, quota - usage.total_tokens) if quota > 0 else 0,
+        )
+    
+    async def GenerateStructured(self, request, context):
+        """Generate output matching a named schema, such as career suggestions.
+
+        The primary model is used, or the light one for light requests;
+        providers with tool calling enforce the schema themselves, others
+        have their reply parsed (see utils.structured).
+        """
+        logger.info(f"GenerateStructured request: user_id={request.user_id}, schema={request.schema}, light={request.light}")
+        schema = SCHEMAS.get(request.schema)
+        if schema is None:
+            context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
+            context.set_details(f"unknown schema {request.schema!r}, expected one of {', '.join(sorted(SCHEMAS))}")
+            return llm_pb2.GenerateStructuredResponse()
+        if not request.prompt:
+            context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
+            context.set_details("prompt is required")
+            return llm_pb2.GenerateStructuredResponse()
+        if not await self._check_quota(request.user_id, context):
+            return llm_pb2.GenerateStructuredResponse()
+
+        meter = UsageMeter()
+        try:
+            llm = self.light_llm if request.light else self.llm
+            prompt = guarded_prompt(self.config.rag.guardrails_prompt, request.prompt)
+            output, method = await generate_structured(llm, prompt, schema, meter)
+        except StructuredOutputError as e:
+            logger.warning(f"Structured output for schema {request.schema} was invalid: {e}")
+            context.set_code(grpc.StatusCode.INTERNAL)
+            context.set_details(f"model output did not match the {request.schema} schema: {e}")
+            return llm_pb2.GenerateStructuredResponse()
+        except Exception as e:
+            if is_rate_limit(e):
+                self._fail_rate_limited(context, e)
+                return llm_pb2.GenerateStructuredResponse()
+            raise
+        finally:
+            await self._record_usage(request.user_id, meter)
+
+        response = llm_pb2.GenerateStructuredResponse(json=output.model_dump_json(), method=method)
+        if isinstance(output, CareerSuggestions):
+            response.career_suggestions.extend(
+                llm_pb2.CareerSuggestion(
+                    career_field=s.career_field,
+                    match_percent=s.match_percent,
+                    rationale=s.rationale,
+                    domain_codes=s.domain_codes,
+                )
+                for s in output.suggestions
+            )
+        elif isinstance(output, FollowUpQuestions):
+            response.follow_up_questions.extend(output.questions)
+        return response
+
     async def IngestDocument(self, request, context):
-        """Ingest a document into the vector store."""
+        """Ingest a document into the vector store, or preview it when dry_run is set."""
         try:
-            if not self.vector_store:
+            if not self.vector_store and not request.dry_run:
                 return llm_pb2.IngestDocumentResponse(
                     success=False,
                     message="Vector store not available"
                 )
             
+            collection = request.collection or self.config.vector_store.default_index
+            vector_store = self.vector_store
+            if collection != self.config.vector_store.default_index and not request.dry_run:
+                # A recently created collection may still be provisioning
+                status = await self._index_status(collection)
+                if status != STATUS_READY:
+                    return llm_pb2.IngestDocumentResponse(
+                        success=False,
+                        message=f"Collection '{collection}' is {status}; retry once it is ready",
+                        collection_status=status
+                    )
+                vector_store = self._vector_store_for(collection)
+            
             # Split document into chunks
             doc = Document(
                 page_content=request.content,
                 metadata=dict(request.metadata) if request.metadata else {}
             )
             
-            chunks = self.text_splitter.split_documents([doc])
-            
-            # Add to vector store
-            await asyncio.get_event_loop().run_in_executor(
-                None,
-                lambda: self.vector_store.add_documents(chunks)
+            document_id = request.document_id or uuid.uuid4().hex
+            vs_config = self.config.vector_store
+            scrub = request.scrub if request.HasField("scrub") else vs_config.scrub_by_default
+            result = await ingest_document(
+                self.text_splitter,
+                vector_store,
+                doc,
+                vs_config.embedding_model,
+                dry_run=request.dry_run,
+                document_id=document_id,
+                batch_size=vs_config.upsert_batch_size,
+                max_retries=vs_config.upsert_max_retries,
+                retry_delay=vs_config.upsert_retry_delay_seconds,
+                scrubber=self.scrubber if scrub else None,
+                sections=[(section.title, section.content) for section in request.sections]
             )
+            chunks = result.chunks
+            
+            if result.dry_run:
+                return llm_pb2.IngestDocumentResponse(
+                    document_id=document_id,
+                    success=True,
+                    message=f"Dry run: document would be ingested as {len(chunks)} chunks",
+                    chunks_created=len(chunks),
+                    dry_run=True,
+                    chunk_previews=[llm_pb2.ChunkPreview(**p) for p in result.previews],
+                    estimated_tokens=result.estimated_tokens,
+                    estimated_embedding_cost_usd=result.estimated_cost_usd,
+                    redactions=result.redactions
+                )
             
-            logger.info(f"Ingested document with {len(chunks)} chunks")
+            stored = len(result.succeeded_ids)
+            if stored:
+                self.knowledge_base.forget()
+            if result.failed_ids:
+                logger.error(f"Stored {stored}/{len(chunks)} chunks of document '{document_id}' in '{collection}'")
+                message = (f"Stored {stored} of {len(chunks)} chunks; "
+                           f"{len(result.failed_ids)} failed ({'; '.join(result.errors)})")
+            else:
+                logger.info(f"Ingested document with {len(chunks)} chunks into '{collection}'")
+                message = f"Successfully ingested document with {len(chunks)} chunks"
             
-            return llm_pb2.IngestDocumentResponse(
-                document_id=request.document_id or "auto_generated",
-                success=True,
-                message=f"Successfully ingested document with {len(chunks)} chunks",
-                chunks_created=len(chunks)
+            response = llm_pb2.IngestDocumentResponse(
+                document_id=document_id,
+                success=not result.failed_ids,
+                message=message,
+                chunks_created=stored,
+                succeeded_ids=result.succeeded_ids,
+                failed_ids=result.failed_ids,
+                partial=result.partial,
+                redactions=result.redactions
             )
+            if request.verify and result.succeeded_ids:
+                # Stored chunks that can't be read back yet don't fail the
+                # ingestion; the index usually catches up
+                verification = await verify_upsert(
+                    vector_store,
+                    result.succeeded_ids,
+                    sample_size=vs_config.verify_sample_size,
+                    max_attempts=vs_config.verify_max_attempts,
+                    retry_delay=vs_config.verify_retry_delay_seconds
+                )
+                response.verification.CopyFrom(llm_pb2.UpsertVerification(
+                    sampled_ids=verification.sampled_ids,
+                    missing_ids=verification.missing_ids,
+                    visible=verification.visible,
+                    attempts=verification.attempts
+                ))
+                if not verification.visible:
+                    response.message += (f"; {len(verification.missing_ids)} of {len(verification.sampled_ids)} "
+                                         f"sampled chunks not yet readable after {verification.attempts} attempts")
+            return response
             
         except Exception as e:
             logger.error(f"Error ingesting document: {e}")
@@ -646,27 +1473,147 @@ Answer:"""
                 message=f"Error: {str(e)}"
             )
     
+    async def run_ingest_job(self, store: ingest_jobs.IngestJobStore,
+                             job: ingest_jobs.IngestJob,
+                             cancelled: Optional[Callable[[], bool]] = None,
+                             on_event: Optional[ingest_jobs.EventHandler] = None) -> ingest_jobs.IngestJob:
+        """Run or resume an ingestion job into its collection.
+
+        A job whose collection can't be written to yet is marked failed with
+        the reason, and can be resumed later. cancelled is checked between
+        batches, and on_event receives the job's progress events; see
+        ingest_jobs.run_job.
+        """
+        vector_store = self.vector_store
+        error = None
+        if not vector_store:
+            error = "Vector store not available"
+        elif job.collection != self.config.vector_store.default_index:
+            status = await self._index_status(job.collection)
+            if status != STATUS_READY:
+                error = f"Collection '{job.collection}' is {status}; resume once it is ready"
+            else:
+                vector_store = self._vector_store_for(job.collection)
+        if error:
+            job.status = ingest_jobs.JOB_FAILED
+            job.errors = [error]
+            store.save(job)
+            if on_event is not None:
+                on_event(ingest_jobs.EVENT_SUMMARY, job.progress())
+            return job
+
+        vs_config = self.config.vector_store
+        try:
+            return await ingest_jobs.run_job(
+                store, job, self.text_splitter, vector_store,
+                make_document=Document,
+                batch_size=vs_config.upsert_batch_size,
+                max_retries=vs_config.upsert_max_retries,
+                retry_delay=vs_config.upsert_retry_delay_seconds,
+                cancelled=cancelled,
+                on_event=on_event
+            )
+        finally:
+            self.knowledge_base.forget()
+    
+    async def _index_status(self, name: str) -> str:
+        """Return the collection status of an index."""
+        description = await asyncio.get_event_loop().run_in_executor(
+            None,
+            lambda: self.vector_db.describe_collection(name)
+        )
+        return description.status
+    
     async def CreateCollection(self, request, context):
-        """Create a new collection (index)."""
-        # For now, return not implemented
+        """Create a new collection (index) and wait, bounded, for it to become ready."""
+        name = request.collection_name
+        if not getattr(self, "vector_db", None):
+            return llm_pb2.CreateCollectionResponse(
+                success=False,
+                message="Vector store not available",
+                collection_name=name,
+                status=STATUS_FAILED
+            )
+        
+        vector_config = self.config.vector_store
+        try:
+            await asyncio.get_event_loop().run_in_executor(
+                None,
+                # Readiness is polled below
+                lambda: self.vector_db.create_collection(name, self.embedding_dimensions, metric="cosine")
+            )
+        except Exception as e:
+            logger.error(f"Error creating collection '{name}': {e}")
+            return llm_pb2.CreateCollectionResponse(
+                success=False,
+                message=f"Error: {str(e)}",
+                collection_name=name,
+                status=STATUS_FAILED
+            )
+        # Recorded before it is ready, so orphan purging never takes a
+        # collection still provisioning once that is done
+        await asyncio.get_event_loop().run_in_executor(None, self.collection_registry.add, name)
+        
+        try:
+            status = await wait_until_ready(
+                name,
+                lambda: self._index_status(name),
+                vector_config.index_ready_timeout_seconds,
+                vector_config.index_ready_poll_seconds
+            )
+        except IndexProvisioningTimeout as e:
+            logger.warning(str(e))
+            return llm_pb2.CreateCollectionResponse(
+                success=False,
+                message=f"{e}; poll ListCollections until its status is '{STATUS_READY}'",
+                collection_name=name,
+                status=STATUS_PROVISIONING
+            )
+        
+        if status != STATUS_READY:
+            return llm_pb2.CreateCollectionResponse(
+                success=False,
+                message=f"Provisioning of collection '{name}' failed",
+                collection_name=name,
+                status=status
+            )
+        
+        # A store cached for an index of the same name deleted since points
+        # at the old index, and may have been of another size
+        self._forget_collection(name)
+        logger.info(f"Created collection '{name}'")
         return llm_pb2.CreateCollectionResponse(
-            success=False,
-            message="Collection creation not implemented in Python version"
+            success=True,
+            message=f"Collection '{name}' created",
+            collection_name=name,
+            status=status
         )
     
     async def ListCollections(self, request, context):
-        """List available collections."""
-        # For now, return the default collection
-        collections = []
-        if self.vector_store:
-            collections.append(llm_pb2.CollectionInfo(
-                name=self.config.vector_store.default_index,
-                document_count=0,  # Would need to query Pinecone for actual count
-                created_at="unknown",
-                metadata={}
-            ))
+        """List one page of collections with their provisioning status."""
+        try:
+            page, page_info = await self.list_collections(
+                page_size=request.page_size,
+                page_token=request.page_token,
+                include_stats=request.include_stats
+            )
+        except InvalidPageToken as e:
+            context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
+            context.set_details(str(e))
+            return llm_pb2.ListCollectionsResponse()
+
+        collections = [
+            llm_pb2.CollectionInfo(
+                name=c["name"],
+                document_count=c["document_count"],
+                created_at=c["created_at"],
+                metadata={k: str(v) for k, v in c["metadata"].items()},
+                status=c["status"]
+            )
+            for c in page
+        ]
         
-        return llm_pb2.ListCollectionsResponse(collections=collections)
+        return llm_pb2.ListCollectionsResponse(collections=collections, next_page_token=page_info.next_token)
     
     async def DeleteCollection(self, request, context):
         """Delete a collection."""
@@ -675,8 +1622,28 @@ Answer:"""
             message="Collection deletion not implemented in Python version"
         )
     
+    async def GetKnowledgeBaseStatus(self, request, context):
+        """Report what the vector database holds across its collections."""
+        if not self.vector_db:
+            context.set_code(grpc.StatusCode.UNAVAILABLE)
+            context.set_details("Vector store not available")
+            return llm_pb2.GetKnowledgeBaseStatusResponse()
+        try:
+            status = await asyncio.get_event_loop().run_in_executor(None, self.knowledge_base.status)
+        except Exception as e:
+            logger.error(f"Error checking the knowledge base: {e}")
+            context.set_code(grpc.StatusCode.UNAVAILABLE)
+            context.set_details(f"Could not list collections: {e}")
+            return llm_pb2.GetKnowledgeBaseStatusResponse()
+        return llm_pb2.GetKnowledgeBaseStatusResponse(
+            empty=status.empty,
+            collections=status.collections,
+            documents=status.documents,
+            uncounted=status.uncounted,
+        )
+
     async def clear_collection(self, collection_name: str) -> bool:
-        """Clear all data from a specific Pinecone collection/index.
+        """Clear all data from a specific collection/index.
         
         Args:
             collection_name: Name of the collection/index to clear
@@ -685,23 +1652,17 @@ Answer:"""
             bool: True if successful, False otherwise
         """
         try:
-            if not self.pinecone:
-                logger.error("Pinecone client not initialized")
+            if not self.vector_db:
+                logger.error("Vector store not initialized")
                 return False
             
-            # Get the index
-            index = self.pinecone.Index(collection_name)
-            
-            # Delete all vectors from the index by querying and deleting all namespaces
-            # First, try to delete all vectors in the default namespace
             try:
                 # Get index stats to see if it has vectors
-                stats = index.describe_index_stats()
-                total_vector_count = stats.get('total_vector_count', 0)
+                total_vector_count = self.vector_db.stats(collection_name).document_count
                 
                 if total_vector_count > 0:
-                    # Delete all vectors in the default namespace
-                    index.delete(delete_all=True)
+                    self.vector_db.clear(collection_name)
+                    self._forget_collection(collection_name)
                     logger.info(f"Cleared {total_vector_count} vectors from index '{collection_name}'")
                 else:
                     logger.info(f"Index '{collection_name}' is already empty")
@@ -716,63 +1677,219 @@ Answer:"""
             logger.error(f"Error clearing collection '{collection_name}': {e}")
             return False
     
-    async def list_collections(self) -> List[Dict[str, Any]]:
-        """List all available Pinecone collections/indexes.
-        
-        Returns:
-            List[Dict]: List of collection information
+    def export_collection(self, name: str, page_size: int = EXPORT_PAGE_SIZE) -> Iterator[str]:
+        """Return the collection's documents as JSON lines, read from the
+        vector database a page at a time; see utils.collection_export."""
+        return export_lines(self.vector_db, name, page_size)
+
+    async def import_collection(self, name: str, lines: AsyncIterable[str]) -> ImportResult:
+        """Store the documents of an export in a collection, re-embedding them.
+
+        Raises:
+            EmbeddingDimensionMismatch: The collection holds vectors of
+                another size than the embedding model makes
+            InvalidExportLine: A line is not an exported document
         """
+        await self._check_collection_dimensions([name])
+        vs_config = self.config.vector_store
         try:
-            if not self.pinecone:
-                logger.error("Pinecone client not initialized")
+            return await import_lines(
+                CollectionHandle(self.vector_db, name),
+                lines,
+                lambda content, metadata: Document(page_content=content, metadata=metadata),
+                batch_size=vs_config.upsert_batch_size,
+                max_retries=vs_config.upsert_max_retries,
+                retry_delay=vs_config.upsert_retry_delay_seconds,
+            )
+        finally:
+            self.knowledge_base.forget()
+
+    def start_warmup(self) -> asyncio.Task:
+        """Warm up every collection in the background, so the first requests
+        aren't held up. Shutdown cancels it if it is still running."""
+        self._warmup_task = asyncio.create_task(self.warmup_collections())
+        return self._warmup_task
+
+    async def warmup_collections(self, names: Optional[List[str]] = None) -> List[Dict[str, Any]]:
+        """Connect to collections ahead of traffic, so first queries skip it.
+
+        Each collection's index is described, which checks it exists and is
+        ready, and its vector store is opened and cached for queries.
+
+        Args:
+            names: Collections to warm; every collection when None
+
+        Returns:
+            One dict per collection with collection, success, error and
+            duration_ms keys
+        """
+        if not self.vector_db:
+            return [self._warmup_result(name, "Vector store not available") for name in names or []]
+        loop = asyncio.get_event_loop()
+        if names is None:
+            try:
+                indexes = await loop.run_in_executor(None, self.vector_db.list_collections)
+            except Exception as e:
+                logger.error(f"Error listing collections to warm up: {e}")
                 return []
+            names = [info.name for info in indexes]
+
+        async def warm(name: str) -> Dict[str, Any]:
+            start = time.monotonic()
+            error = ""
+            try:
+                status = await self._index_status(name)
+                if status == STATUS_READY:
+                    await loop.run_in_executor(None, self._vector_store_for, name)
+                else:
+                    error = f"Collection is {status}"
+            except Exception as e:
+                logger.error(f"Warming up collection '{name}' failed: {e}")
+                error = str(e)
+            return self._warmup_result(name, error, time.monotonic() - start)
+
+        results = await asyncio.gather(*(warm(name) for name in names))
+        warmed = sum(1 for r in results if r["success"])
+        logger.info(f"Warmed up {warmed} of {len(results)} collections")
+        return list(results)
+
+    @staticmethod
+    def _warmup_result(name: str, error: str, seconds: float = 0.0) -> Dict[str, Any]:
+        return {
+            "collection": name,
+            "success": not error,
+            "error": error,
+            "duration_ms": round(seconds * 1000, 1),
+        }
+
+    async def purge_orphaned_indexes(self, confirm: bool = False,
+                                     names: Optional[List[str]] = None) -> Dict[str, Any]:
+        """Find indexes that are neither known nor active, and delete the
+        named ones if confirmed.
+
+        Known indexes are the default index, vector_store.known_indexes and
+        those created through the service, as recorded in the collection
+        registry. Unknown indexes the service has opened, or that are still
+        provisioning, are kept and reported.
+
+        Args:
+            confirm: Delete the orphans in names; otherwise only report them
+            names: Orphans confirmed for deletion; others are kept, so
+                nothing is deleted that the caller did not name
+
+        Returns:
+            A dict with dry_run, orphans, kept, deleted and failed keys;
+            failed holds a dict with name and error keys per failed deletion
+
+        Raises:
+            ValueError: confirm without names
+        """
+        if confirm and not names:
+            raise ValueError("name the orphans to delete")
+        if not self.vector_db:
+            raise RuntimeError("Vector store not available")
+        loop = asyncio.get_event_loop()
+        indexes = await loop.run_in_executor(None, self.vector_db.list_collections)
+        registered = await loop.run_in_executor(None, self.collection_registry.names)
+        statuses = {info.name: info.status for info in indexes}
+        vector_config = self.config.vector_store
+        orphans, kept = find_orphans(
+            statuses,
+            known=[vector_config.default_index, *vector_config.known_indexes, *registered],
+            in_use=self._collection_stores.names()
+        )
+        result = {"dry_run": not confirm, "orphans": orphans, "kept": kept, "deleted": [], "failed": []}
+        if not confirm:
+            logger.info(f"Found {len(orphans)} orphaned indexes (dry run): {orphans}")
+            return result
+
+        confirmed = set(names)
+        registered = set(await loop.run_in_executor(None, self.collection_registry.names))
+        for name in orphans:
+            if name not in confirmed:
+                kept.append({"name": name, "reason": KEPT_NOT_NAMED})
+                continue
+            # An index opened or created since it was listed is no longer
+            # an orphan
+            if name in self._collection_stores.names() or name in registered:
+                kept.append({"name": name, "reason": KEPT_IN_USE})
+                continue
+            try:
+                await loop.run_in_executor(None, self.vector_db.delete, name)
+            except Exception as e:
+                logger.error(f"Deleting orphaned index '{name}' failed: {e}")
+                result["failed"].append({"name": name, "error": str(e)})
+                continue
+            self._forget_collection(name)
+            logger.info(f"Deleted orphaned index '{name}'")
+            result["deleted"].append(name)
+        return result
+
+    async def list_collections(self, page_size: int = 0, page_token: str = "",
+                               include_stats: bool = True) -> Tuple[List[Dict[str, Any]], PageInfo]:
+        """List one page of collections/indexes, in name order.
+        
+        Index stats are fetched only for the collections on the page, and
+        only when include_stats is set.
+        
+        Args:
+            page_size: Collections per page (0 for the default)
+            page_token: Token from the previous page, or empty for the first
+            include_stats: Fetch document counts and index stats
             
-            # List all indexes
-            indexes = self.pinecone.list_indexes()
-            collections = []
-            
-            for index_info in indexes:
-                # Get index stats
-                try:
-                    index_name = index_info.name
-                    index = self.pinecone.Index(index_name)
-                    stats = index.describe_index_stats()
-                    
-                    collection = {
-                        "name": index_name,
-                        "dimension": int(index_info.dimension),
-                        "metric": str(index_info.metric),
-                        "document_count": int(stats.get('total_vector_count', 0)),
-                        "host": str(index_info.host),
-                        "status": str(index_info.status.state) if index_info.status else "unknown",
-                        "created_at": "unknown",  # Pinecone doesn't provide creation time via API
-                        "metadata": {
-                            "index_fullness": float(stats.get('index_fullness', 0.0)),
-                            "namespace_count": len(stats.get('namespaces', {}))
-                        }
-                    }
-                    collections.append(collection)
-                    
-                except Exception as index_error:
-                    logger.error(f"Error getting stats for index '{index_info.name}': {index_error}")
-                    # Add basic info even if stats fail
-                    collections.append({
-                        "name": str(index_info.name),
-                        "dimension": int(index_info.dimension),
-                        "metric": str(index_info.metric),
-                        "document_count": 0,
-                        "host": str(index_info.host),
-                        "status": "error",
-                        "created_at": "unknown",
-                        "metadata": {"error": str(index_error)}
-                    })
-            
-            logger.info(f"Found {len(collections)} Pinecone collections")
-            return collections
+        Returns:
+            The page of collection information and its position, including
+            the next page's token (empty on the last page)
             
+        Raises:
+            InvalidPageToken: page_token was not issued by this service
+        """
+        if not self.vector_db:
+            logger.error("Vector store not initialized")
+            return paginate_with_info([], page_size, page_token)  # Bad tokens are still rejected
+        
+        try:
+            # Index descriptions are cheap and come back in one call
+            indexes = {info.name: info for info in self.vector_db.list_collections()}
         except Exception as e:
             logger.error(f"Error listing collections: {e}")
-            return []
+            return paginate_with_info([], page_size)
+        
+        names, page_info = paginate_with_info(list(indexes), page_size, page_token)
+        loop = asyncio.get_event_loop()
+        collections = await asyncio.gather(*(
+            loop.run_in_executor(None, self._collection_info, indexes[name], include_stats)
+            for name in names
+        ))
+        
+        logger.info(f"Listed {len(collections)} of {len(indexes)} collections")
+        return list(collections), page_info
+    
+    def _collection_info(self, index_info: CollectionInfo, include_stats: bool) -> Dict[str, Any]:
+        """Describe one collection, optionally with its index stats."""
+        collection = {
+            "name": index_info.name,
+            "dimension": index_info.dimension,
+            "metric": index_info.metric,
+            "document_count": 0,
+            "host": index_info.host,
+            "status": index_info.status,
+            "created_at": "unknown",  # Pinecone doesn't provide creation time via API
+            "metadata": {}
+        }
+        if not include_stats:
+            return collection
+        
+        try:
+            stats = self.vector_db.stats(index_info.name)
+            collection["document_count"] = stats.document_count
+            collection["metadata"] = dict(stats.metadata)
+        except Exception as index_error:
+            logger.error(f"Error getting stats for index '{index_info.name}': {index_error}")
+            # Keep the basic info even if stats fail
+            collection["status"] = "error"
+            collection["metadata"] = {"error": str(index_error)}
+        return collection
     
     async def ingest_vietnamese_university_data(
         self, 
@@ -0,0 +1,60 @@
+"""Tests that the admin API acts on the service answering gRPC traffic.
+
+Needs fastapi and the service's runtime dependencies; skipped without them.
+"""
+
+import os
+import sys
+import tempfile
+import unittest
+from unittest import mock
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+try:
+    from fastapi.testclient import TestClient
+    from admin import api as admin_api
+except ImportError:
+    admin_api = None
+
+from config.settings import get_settings
+
+
+@unittest.skipIf(admin_api is None, "admin API dependencies not installed")
+class TestAdminServiceSharing(unittest.TestCase):
+    def setUp(self):
+        self.tmp = tempfile.TemporaryDirectory()
+        self.addCleanup(self.tmp.cleanup)
+        settings = get_settings()
+        patcher = mock.patch.object(
+            settings, "admin_audit_log_path", os.path.join(self.tmp.name, "audit.jsonl")
+        )
+        patcher.start()
+        self.addCleanup(patcher.stop)
+        self.headers = {"Authorization": f"Bearer {settings.admin_api_key}"}
+
+    def test_endpoints_use_the_grpc_service(self):
+        service = mock.Mock()
+        service.clear_collection = mock.AsyncMock(return_value=True)
+
+        with mock.patch.object(admin_api, "LLMServicer") as new_service:
+            client = TestClient(admin_api.create_admin_app(service))
+            response = client.delete("/admin/collections/careers", headers=self.headers)
+
+        self.assertEqual(response.status_code, 200, response.text)
+        service.clear_collection.assert_awaited_once_with("careers")
+        new_service.assert_not_called()
+
+    def test_without_a_grpc_service_one_is_created_once(self):
+        with mock.patch.object(admin_api, "LLMServicer") as new_service:
+            new_service.return_value.clear_collection = mock.AsyncMock(return_value=True)
+            client = TestClient(admin_api.create_admin_app())
+            for _ in range(2):
+                response = client.delete("/admin/collections/careers", headers=self.headers)
+                self.assertEqual(response.status_code, 200, response.text)
+
+        new_service.assert_called_once_with()
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,88 @@
+"""Tests for the admin audit trail."""
+
+import os
+import sys
+import tempfile
+import unittest
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils import audit
+from utils.audit import AuditLog, admin_actor
+
+FIELDS = {"timestamp", "actor", "action", "collection", "outcome", "detail"}
+
+
+class AuditLogTest(unittest.TestCase):
+    def setUp(self):
+        self.dir = tempfile.TemporaryDirectory()
+        self.addCleanup(self.dir.cleanup)
+        self.path = os.path.join(self.dir.name, "logs", "audit.jsonl")
+        self.log = AuditLog(self.path)
+        self.actor = admin_actor("admin-secret-key-change-me", "alice")
+
+    def test_each_admin_action_is_recorded(self):
+        with self.log.track(self.actor, audit.COLLECTION_CREATE, "scholarships"):
+            pass
+        with self.log.track(self.actor, audit.DOCUMENT_INGEST, "scholarships") as outcome:
+            outcome["detail"] = "Document ingested with 3 chunks"
+        with self.log.track(self.actor, audit.COLLECTION_DELETE, "scholarships"):
+            pass
+
+        entries = self.log.recent()
+        self.assertEqual(
+            [e["action"] for e in entries],
+            [audit.COLLECTION_DELETE, audit.DOCUMENT_INGEST, audit.COLLECTION_CREATE],
+        )
+        for entry in entries:
+            self.assertEqual(set(entry), FIELDS)
+            self.assertEqual(entry["actor"], self.actor)
+            self.assertEqual(entry["collection"], "scholarships")
+            self.assertEqual(entry["outcome"], audit.OUTCOME_SUCCESS)
+            self.assertTrue(entry["timestamp"])
+        self.assertEqual(entries[1]["detail"], "Document ingested with 3 chunks")
+
+    def test_failures_are_recorded(self):
+        with self.log.track(self.actor, audit.COLLECTION_CREATE, "a") as outcome:
+            outcome["outcome"] = audit.OUTCOME_FAILURE
+            outcome["detail"] = "not implemented"
+        with self.assertRaises(RuntimeError):
+            with self.log.track(self.actor, audit.COLLECTION_DELETE, "b"):
+                raise RuntimeError("index not found")
+
+        delete, create = self.log.recent()
+        self.assertEqual((create["outcome"], create["detail"]), (audit.OUTCOME_FAILURE, "not implemented"))
+        self.assertEqual((delete["outcome"], delete["detail"]), (audit.OUTCOME_FAILURE, "index not found"))
+
+    def test_recent_filters_and_limits(self):
+        for i in range(5):
+            self.log.record(self.actor, audit.DOCUMENT_INGEST, f"c{i}", audit.OUTCOME_SUCCESS)
+        self.log.record(self.actor, audit.COLLECTION_DELETE, "c0", audit.OUTCOME_SUCCESS)
+
+        self.assertEqual([e["collection"] for e in self.log.recent(limit=2)], ["c0", "c4"])
+        ingests = self.log.recent(action=audit.DOCUMENT_INGEST)
+        self.assertEqual([e["collection"] for e in ingests], ["c4", "c3", "c2", "c1", "c0"])
+
+    def test_entries_survive_restart(self):
+        self.log.record(self.actor, audit.COLLECTION_DELETE, "c0", audit.OUTCOME_SUCCESS)
+        self.assertEqual(len(AuditLog(self.path).recent()), 1)
+
+    def test_recent_without_file(self):
+        self.assertEqual(AuditLog(os.path.join(self.dir.name, "missing.jsonl")).recent(), [])
+
+
+class AdminActorTest(unittest.TestCase):
+    def test_key_is_fingerprinted(self):
+        actor = admin_actor("admin-secret-key-change-me")
+        self.assertTrue(actor.startswith("key:"))
+        self.assertNotIn("secret", actor)
+        self.assertEqual(actor, admin_actor("admin-secret-key-change-me", "  "))
+
+    def test_name_is_kept(self):
+        self.assertEqual(
+            admin_actor("k" * 16, "alice"), f"alice ({admin_actor('k' * 16)})"
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
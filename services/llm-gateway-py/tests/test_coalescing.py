@@ -0,0 +1,181 @@
+"""Tests for sharing one generation among identical concurrent requests."""
+
+import asyncio
+import os
+import sys
+import unittest
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.coalescing import StreamCoalescer, coalescing_key
+
+
+class Request:
+    """The GenerateWithRAGRequest fields coalescing_key reads."""
+
+    def __init__(self, prompt, adaptive=False, debug=False, continue_from="", verbosity="", **optional):
+        self.prompt = prompt
+        self.adaptive = adaptive
+        self.debug = debug
+        self.continue_from = continue_from
+        self.verbosity = verbosity
+        self.optional = optional
+        self.strict_grounding = optional.get("strict_grounding", False)
+
+    def HasField(self, name):
+        return name in self.optional
+
+
+class CoalescingKeyTest(unittest.TestCase):
+    def test_prompt_is_normalized(self):
+        self.assertEqual(
+            coalescing_key(Request("What is the  HUST cutoff?\n"), ["a"]),
+            coalescing_key(Request("what is the HUST cutoff?"), ["a"]),
+        )
+
+    def test_collections_and_options_are_part_of_the_key(self):
+        key = coalescing_key(Request("q"), ["a"])
+        self.assertNotEqual(key, coalescing_key(Request("q"), ["b"]))
+        self.assertNotEqual(key, coalescing_key(Request("q", adaptive=True), ["a"]))
+        self.assertNotEqual(key, coalescing_key(Request("q", strict_grounding=True), ["a"]))
+        self.assertNotEqual(key, coalescing_key(Request("q", verbosity="short"), ["a"]))
+        self.assertEqual(key, coalescing_key(Request("q", verbosity="normal"), ["a"]))
+
+    def test_caller_specific_requests_are_not_coalesced(self):
+        self.assertIsNone(coalescing_key(Request("q", debug=True), ["a"]))
+        self.assertIsNone(coalescing_key(Request("q", params=object()), ["a"]))
+        self.assertIsNone(coalescing_key(Request("q", continue_from="The answer"), ["a"]))
+
+
+class StreamCoalescerTest(unittest.TestCase):
+    def setUp(self):
+        self.coalescer = StreamCoalescer()
+        self.started = 0
+        self.release = None
+
+    async def tokens(self, items=("a", "b", "c"), fail=False):
+        self.started += 1
+        for item in items:
+            await self.release.wait()
+            yield item
+            await asyncio.sleep(0)  # let callers take it, as a model stream would
+        if fail:
+            raise RuntimeError("model unavailable")
+
+    async def collect(self, key, **kwargs):
+        return [t async for t in self.coalescer.stream(key, lambda: self.tokens(**kwargs))]
+
+    def test_concurrent_callers_share_one_stream(self):
+        async def run():
+            self.release = asyncio.Event()
+            callers = [asyncio.create_task(self.collect("k")) for _ in range(3)]
+            await asyncio.sleep(0)
+            self.release.set()
+            return await asyncio.gather(*callers)
+
+        self.assertEqual([["a", "b", "c"]] * 3, asyncio.run(run()))
+        self.assertEqual(1, self.started)
+        self.assertEqual(0, self.coalescer.active)
+
+    def test_late_caller_gets_items_already_sent(self):
+        async def run():
+            self.release = asyncio.Event()
+            first = self.coalescer.stream("k", self.tokens)
+            self.release.set()
+            got = [await first.__anext__()]
+            self.release.clear()  # hold the stream after its first item
+            late = asyncio.create_task(self.collect("k"))
+            await asyncio.sleep(0)
+            self.release.set()
+            got += [t async for t in first]
+            return got, await late
+
+        first, late = asyncio.run(run())
+        self.assertEqual(["a", "b", "c"], first)
+        self.assertEqual(["a", "b", "c"], late)
+        self.assertEqual(1, self.started)
+
+    def test_different_keys_run_separately(self):
+        async def run():
+            self.release = asyncio.Event()
+            self.release.set()
+            return await asyncio.gather(self.collect("k1"), self.collect("k2"))
+
+        asyncio.run(run())
+        self.assertEqual(2, self.started)
+
+    def test_sequential_callers_do_not_share(self):
+        async def run():
+            self.release = asyncio.Event()
+            self.release.set()
+            await self.collect("k")
+            await self.collect("k")
+
+        asyncio.run(run())
+        self.assertEqual(2, self.started)
+
+    def test_error_reaches_every_caller(self):
+        async def run():
+            self.release = asyncio.Event()
+            callers = [asyncio.create_task(self.collect("k", fail=True)) for _ in range(2)]
+            await asyncio.sleep(0)
+            self.release.set()
+            return await asyncio.gather(*callers, return_exceptions=True)
+
+        for result in asyncio.run(run()):
+            self.assertIsInstance(result, RuntimeError)
+        self.assertEqual(0, self.coalescer.active)
+
+    def test_stream_continues_for_callers_that_stay(self):
+        async def run():
+            self.release = asyncio.Event()
+            leaving = asyncio.create_task(self.collect("k"))
+            staying = asyncio.create_task(self.collect("k"))
+            await asyncio.sleep(0)
+            leaving.cancel()
+            self.release.set()
+            return await staying
+
+        self.assertEqual(["a", "b", "c"], asyncio.run(run()))
+
+    def test_callers_get_the_starters_shared_value(self):
+        left = []
+
+        async def collect(shared):
+            stream = self.coalescer.stream("k", self.tokens, shared=shared, on_leave=left.append)
+            return [t async for t in stream]
+
+        async def run():
+            self.release = asyncio.Event()
+            callers = [asyncio.create_task(collect(shared)) for shared in ("first", "second")]
+            await asyncio.sleep(0)
+            self.release.set()
+            await asyncio.gather(*callers)
+
+        asyncio.run(run())
+        self.assertEqual(["first", "first"], left)
+
+    def test_stream_is_cancelled_when_every_caller_leaves(self):
+        cancelled = []
+
+        async def endless():
+            try:
+                while True:
+                    await asyncio.sleep(0.001)
+                    yield "t"
+            finally:
+                cancelled.append(True)
+
+        async def run():
+            caller = self.coalescer.stream("k", endless)
+            await caller.__anext__()
+            await caller.aclose()
+            await asyncio.sleep(0.01)
+
+        asyncio.run(run())
+        self.assertEqual([True], cancelled)
+        self.assertEqual(0, self.coalescer.active)
+
+
+if __name__ == "__main__":
+    unittest.main()
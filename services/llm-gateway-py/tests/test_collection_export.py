@@ -0,0 +1,105 @@
+"""Tests for exporting a collection's documents and importing them into
+another store."""
+
+import asyncio
+import json
+import os
+import sys
+import unittest
+from types import SimpleNamespace
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.collection_export import InvalidExportLine, export_lines, import_lines, split_lines
+from utils.fakes import FakeEmbeddings, FakePineconeClient, fake_vector_store_factory
+from utils.memory_store import MemoryVectorStore
+from utils.pinecone_store import PineconeStore
+from utils.vector_backend import CollectionHandle
+
+
+def make_document(content, metadata):
+    return SimpleNamespace(page_content=content, metadata=metadata)
+
+
+async def chunked(data, size):
+    for start in range(0, len(data), size):
+        yield data[start:start + size]
+
+
+def contents(store, collection):
+    page, _ = store.list_documents(collection, 1000)
+    return {doc_id: (d.page_content, d.metadata) for doc_id, d in page}
+
+
+class CollectionExportTest(unittest.TestCase):
+    def setUp(self):
+        self.source = MemoryVectorStore(FakeEmbeddings(16), 16, ["universities"])
+        self.source.add_documents("universities", [
+            make_document(f"Điểm chuẩn ngành {i}", {"source": f"{i}.pdf", "page": i, "section": "Tuyển sinh"})
+            for i in range(7)
+        ], ids=[f"hust#{i}" for i in range(7)])
+
+    def import_into(self, store, data, chunk_size=64, batch_size=3):
+        return asyncio.run(import_lines(CollectionHandle(store, "universities"),
+                                        split_lines(chunked(data, chunk_size)),
+                                        make_document, batch_size=batch_size, retry_delay=0))
+
+    def test_export_then_import_reproduces_the_documents(self):
+        data = "".join(export_lines(self.source, "universities", page_size=2)).encode("utf-8")
+
+        target = MemoryVectorStore(FakeEmbeddings(16), 16, ["universities"])
+        result = self.import_into(target, data)
+
+        self.assertEqual(7, result.imported)
+        self.assertEqual([], result.failed_ids)
+        self.assertEqual(contents(self.source, "universities"), contents(target, "universities"))
+        self.assertEqual(self.source.query("universities", "ngành 3", 1)[0][0].page_content,
+                         target.query("universities", "ngành 3", 1)[0][0].page_content)
+
+    def test_export_pages_through_the_collection(self):
+        lines = list(export_lines(self.source, "universities", page_size=3))
+        self.assertEqual([f"hust#{i}" for i in range(7)], [json.loads(line)["id"] for line in lines])
+        self.assertTrue(all(line.endswith("\n") for line in lines))
+
+    def test_importing_twice_keeps_one_copy(self):
+        data = "".join(export_lines(self.source, "universities")).encode("utf-8")
+        target = MemoryVectorStore(FakeEmbeddings(16), 16, ["universities"])
+        self.import_into(target, data)
+        self.import_into(target, data)
+        self.assertEqual(7, target.stats("universities").document_count)
+
+    def test_export_from_pinecone(self):
+        store = PineconeStore(FakePineconeClient(16), FakeEmbeddings(16), fake_vector_store_factory, spec="spec")
+        store.create_collection("universities", 16)
+        documents = contents(self.source, "universities")
+        store.add_documents("universities", [make_document(c, m) for c, m in documents.values()],
+                            ids=list(documents))
+
+        data = "".join(export_lines(store, "universities", page_size=2)).encode("utf-8")
+        target = MemoryVectorStore(FakeEmbeddings(16), 16, ["universities"])
+        self.import_into(target, data)
+
+        self.assertEqual(documents, contents(target, "universities"))
+
+    def test_invalid_line_is_reported_with_its_number(self):
+        data = b'{"id": "a", "content": "first", "metadata": {}}\n\n{"id": "b", "metadata": {}}\n'
+        target = MemoryVectorStore(FakeEmbeddings(16), 16, ["universities"])
+        with self.assertRaises(InvalidExportLine) as cm:
+            self.import_into(target, data)
+        self.assertIn("line 3", str(cm.exception))
+        self.assertIn("content", str(cm.exception))
+
+        with self.assertRaises(InvalidExportLine):
+            self.import_into(target, b"not json\n")
+
+    def test_lines_split_across_chunks(self):
+        data = "một\nhai\n\nba".encode("utf-8")
+
+        async def collect():
+            return [line async for line in split_lines(chunked(data, 1))]
+
+        self.assertEqual(["một", "hai", "", "ba"], asyncio.run(collect()))
+
+
+if __name__ == "__main__":
+    unittest.main()
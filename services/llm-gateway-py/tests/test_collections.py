@@ -0,0 +1,269 @@
+"""Tests for listing collections against the fake vector database.
+
+Needs the service's runtime dependencies (grpc, langchain); skipped without
+them.
+"""
+
+import asyncio
+import os
+import sys
+import tempfile
+import unittest
+from types import SimpleNamespace
+from unittest import mock
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+try:
+    from services.llm_service import LLMServicer
+except ImportError:
+    LLMServicer = None
+
+from config import get_config
+from utils.collection_registry import CollectionRegistry
+from utils.embeddings import EmbeddingDimensionMismatch
+from utils.fakes import FakeChatModel, FakeEmbeddings, FakePineconeClient, fake_vector_store_factory
+from utils.pagination import InvalidPageToken
+
+
+@unittest.skipIf(LLMServicer is None, "service dependencies not installed")
+class TestListCollections(unittest.TestCase):
+    def setUp(self):
+        pinecone = FakePineconeClient(64)
+        for name in ("gamma", "alpha", "delta", "beta", "epsilon"):
+            pinecone.create_index(name, dimension=64)
+        pinecone.Index(name="beta").entries.append((SimpleNamespace(page_content="doc"), [0.0] * 64))
+        self.service = LLMServicer(
+            llm=FakeChatModel(),
+            embeddings=FakeEmbeddings(64),
+            pinecone=pinecone,
+            vector_store_factory=fake_vector_store_factory,
+        )
+
+    def list_page(self, **kwargs):
+        return asyncio.run(self.service.list_collections(**kwargs))
+
+    def test_multi_page_listing(self):
+        names, token = [], ""
+        while True:
+            page, info = self.list_page(page_size=2, page_token=token)
+            self.assertLessEqual(len(page), 2)
+            self.assertEqual(len(names), info.offset)
+            names.extend(c["name"] for c in page)
+            token = info.next_token
+            if not token:
+                break
+        self.assertEqual(len(names), info.total)
+        # The default index is created at startup, so it is listed too
+        self.assertEqual(sorted(set(names)), names)
+        self.assertTrue({"alpha", "beta", "gamma", "delta", "epsilon"} <= set(names))
+
+    def test_stats_only_when_requested(self):
+        page, _ = self.list_page(page_size=2)
+        self.assertEqual(["alpha", "beta"], [c["name"] for c in page])
+        self.assertEqual(0, page[1]["document_count"])
+        self.assertEqual({}, page[1]["metadata"])
+
+        page, _ = self.list_page(page_size=2, include_stats=True)
+        self.assertEqual(1, page[1]["document_count"])
+        self.assertIn("namespace_count", page[1]["metadata"])
+
+    def test_invalid_page_token(self):
+        with self.assertRaises(InvalidPageToken):
+            self.list_page(page_token="%%%")
+
+
+@unittest.skipIf(LLMServicer is None, "service dependencies not installed")
+class TestWarmupCollections(unittest.TestCase):
+    def setUp(self):
+        self.pinecone = FakePineconeClient(64)
+        for name in ("alpha", "beta"):
+            self.pinecone.create_index(name, dimension=64)
+        self.service = LLMServicer(
+            llm=FakeChatModel(),
+            embeddings=FakeEmbeddings(64),
+            pinecone=self.pinecone,
+            vector_store_factory=fake_vector_store_factory,
+        )
+        self.calls = []
+        for method in ("Index", "describe_index"):
+            self.count_calls(method)
+
+    def count_calls(self, method):
+        original = getattr(self.pinecone, method)
+
+        def counted(*args, **kwargs):
+            self.calls.append(method)
+            return original(*args, **kwargs)
+        setattr(self.pinecone, method, counted)
+
+    def test_warmup_caches_the_store(self):
+        results = asyncio.run(self.service.warmup_collections(["alpha"]))
+        self.assertEqual(1, len(results))
+        self.assertEqual("alpha", results[0]["collection"])
+        self.assertTrue(results[0]["success"], results[0]["error"])
+        self.assertEqual(["describe_index", "Index"], self.calls)
+
+        # A query after warmup connects to nothing new
+        self.calls.clear()
+        asyncio.run(self.service._retrieve_documents("careers", collections=["alpha"]))
+        self.assertEqual([], self.calls)
+
+    def test_missing_collection_is_reported(self):
+        results = asyncio.run(self.service.warmup_collections(["alpha", "missing"]))
+        by_name = {r["collection"]: r for r in results}
+        self.assertTrue(by_name["alpha"]["success"])
+        self.assertFalse(by_name["missing"]["success"])
+        self.assertIn("missing", by_name["missing"]["error"])
+
+    def test_warms_every_collection_by_default(self):
+        results = asyncio.run(self.service.warmup_collections())
+        names = {r["collection"] for r in results}
+        self.assertTrue({"alpha", "beta"} <= names)
+        self.assertTrue(all(r["success"] for r in results))
+
+    def test_shutdown_cancels_the_startup_warmup(self):
+        started = asyncio.Event()
+
+        async def slow_warmup(names=None):
+            started.set()
+            await asyncio.sleep(60)
+
+        async def run():
+            with mock.patch.object(self.service, "warmup_collections", slow_warmup):
+                task = self.service.start_warmup()
+                await started.wait()
+                await self.service.Shutdown(grace=0)
+                return task
+
+        task = asyncio.run(run())
+        self.assertTrue(task.cancelled())
+
+
+
+@unittest.skipIf(LLMServicer is None, "service dependencies not installed")
+class TestStartupDimensionCheck(unittest.TestCase):
+    def setUp(self):
+        self.pinecone = FakePineconeClient(64)
+        self.pinecone.create_index("faq", dimension=384)
+        vector_store = get_config().vector_store
+        patcher = mock.patch.object(vector_store, "known_indexes", ["faq"])
+        patcher.start()
+        self.addCleanup(patcher.stop)
+
+    def service(self):
+        return LLMServicer(
+            llm=FakeChatModel(),
+            embeddings=FakeEmbeddings(64),
+            pinecone=self.pinecone,
+            vector_store_factory=fake_vector_store_factory,
+        )
+
+    def test_mismatched_known_index_refuses_to_start(self):
+        with self.assertRaises(EmbeddingDimensionMismatch) as cm:
+            self.service()
+        self.assertEqual({"faq": 384}, cm.exception.mismatched)
+
+    def test_matching_known_index_starts(self):
+        self.pinecone.delete_index("faq")
+        self.pinecone.create_index("faq", dimension=64)
+        self.assertIsNotNone(self.service().vector_store)
+
+
+@unittest.skipIf(LLMServicer is None, "service dependencies not installed")
+class TestPurgeOrphanedIndexes(unittest.TestCase):
+    def setUp(self):
+        self.pinecone = FakePineconeClient(64)
+        for name in ("faq", "test-tmp", "migration-v2", "opened", "new"):
+            self.pinecone.create_index(name, dimension=64)
+        self.pinecone.Index(name="new").ready = False
+        self.service = LLMServicer(
+            llm=FakeChatModel(),
+            embeddings=FakeEmbeddings(64),
+            pinecone=self.pinecone,
+            vector_store_factory=fake_vector_store_factory,
+        )
+        self.service._vector_store_for("opened")
+        registry_dir = tempfile.TemporaryDirectory()
+        self.addCleanup(registry_dir.cleanup)
+        self.service.collection_registry = CollectionRegistry(os.path.join(registry_dir.name, "collections.json"))
+        patcher = mock.patch.object(self.service.config.vector_store, "known_indexes", ["faq"])
+        patcher.start()
+        self.addCleanup(patcher.stop)
+        self.default_index = self.service.config.vector_store.default_index
+
+    def purge(self, **kwargs):
+        return asyncio.run(self.service.purge_orphaned_indexes(**kwargs))
+
+    def test_dry_run_reports_orphans(self):
+        result = self.purge()
+        self.assertTrue(result["dry_run"])
+        self.assertEqual(["migration-v2", "test-tmp"], result["orphans"])
+        self.assertEqual(
+            [{"name": "new", "reason": "provisioning"}, {"name": "opened", "reason": "in use"}],
+            result["kept"]
+        )
+        self.assertEqual([], result["deleted"])
+        # Nothing is deleted
+        self.assertEqual(
+            {self.default_index, "faq", "test-tmp", "migration-v2", "opened", "new"},
+            set(self.pinecone.indexes)
+        )
+
+    def test_confirm_deletes_only_named_orphans(self):
+        result = self.purge(confirm=True, names=["migration-v2", "test-tmp", "faq"])
+        self.assertFalse(result["dry_run"])
+        self.assertEqual(["migration-v2", "test-tmp"], result["deleted"])
+        self.assertEqual([], result["failed"])
+        self.assertEqual({self.default_index, "faq", "opened", "new"}, set(self.pinecone.indexes))
+
+        # Nothing is left to purge
+        self.assertEqual([], self.purge()["orphans"])
+
+    def test_orphans_not_named_are_kept(self):
+        result = self.purge(confirm=True, names=["test-tmp"])
+        self.assertEqual(["test-tmp"], result["deleted"])
+        self.assertIn({"name": "migration-v2", "reason": "not named"}, result["kept"])
+        self.assertIn("migration-v2", self.pinecone.indexes)
+
+        with self.assertRaises(ValueError):
+            self.purge(confirm=True)
+
+    def test_created_collections_are_never_orphans(self):
+        created = asyncio.run(self.service.CreateCollection(
+            SimpleNamespace(collection_name="scholarships"), None))
+        self.assertTrue(created.success, created.message)
+        # Created on another replica, or before a restart
+        self.service.collection_registry.add("test-tmp")
+
+        result = self.purge(confirm=True, names=["scholarships", "test-tmp", "migration-v2"])
+        self.assertEqual(["migration-v2"], result["deleted"])
+        self.assertTrue({"scholarships", "test-tmp"} <= set(self.pinecone.indexes))
+
+    def test_deleted_orphans_are_forgotten(self):
+        with mock.patch.object(self.service.collection_dimensions, "forget") as forget_dimensions, \
+                mock.patch.object(self.service.knowledge_base, "forget") as forget_status:
+            self.purge(confirm=True, names=["test-tmp"])
+        forget_dimensions.assert_called_once_with("test-tmp")
+        forget_status.assert_called()
+
+    def test_cleared_collection_is_forgotten(self):
+        self.pinecone.Index(name="opened").entries.append((SimpleNamespace(page_content="doc"), [0.0] * 64))
+        with mock.patch.object(self.service.collection_dimensions, "forget") as forget_dimensions, \
+                mock.patch.object(self.service.knowledge_base, "forget") as forget_status:
+            self.assertTrue(asyncio.run(self.service.clear_collection("opened")))
+        forget_dimensions.assert_called_once_with("opened")
+        forget_status.assert_called()
+        self.assertNotIn("opened", self.service._collection_stores.names())
+
+    def test_failed_deletion_is_reported(self):
+        def delete_index(name):
+            raise RuntimeError("forbidden")
+        self.pinecone.delete_index = delete_index
+        result = self.purge(confirm=True, names=["migration-v2", "test-tmp"])
+        self.assertEqual([], result["deleted"])
+        self.assertEqual(["migration-v2", "test-tmp"], [f["name"] for f in result["failed"]])
+        self.assertIn("forbidden", result["failed"][0]["error"])
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,82 @@
+"""Tests for default deadlines on incoming gRPC calls."""
+
+import asyncio
+import os
+import sys
+import unittest
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.deadlines import DeadlineExceeded, stream_with_deadline, unary_with_deadline
+
+
+class FakeContext:
+    """Servicer context whose call has the given remaining time, or none."""
+
+    def __init__(self, time_remaining=None):
+        self.remaining = time_remaining
+
+    def time_remaining(self):
+        return self.remaining
+
+
+async def slow_unary(request, context):
+    await asyncio.sleep(0.2)
+    return "done"
+
+
+class UnaryWithDeadlineTest(unittest.TestCase):
+    def test_call_without_deadline_gets_the_default(self):
+        with self.assertRaises(DeadlineExceeded):
+            asyncio.run(unary_with_deadline(slow_unary, "req", FakeContext(), 0.05))
+
+    def test_existing_deadline_is_preserved(self):
+        # The client's deadline is left to gRPC, even when shorter
+        result = asyncio.run(unary_with_deadline(slow_unary, "req", FakeContext(time_remaining=0.01), 0.05))
+        self.assertEqual(result, "done")
+
+    def test_zero_disables_the_default(self):
+        self.assertEqual(asyncio.run(unary_with_deadline(slow_unary, "req", FakeContext(), 0)), "done")
+
+    def test_fast_call_finishes(self):
+        async def fast(request, context):
+            return request.upper()
+        self.assertEqual(asyncio.run(unary_with_deadline(fast, "req", FakeContext(), 1)), "REQ")
+
+
+class StreamWithDeadlineTest(unittest.TestCase):
+    def run_stream(self, context, seconds):
+        events = []
+
+        async def tokens(request, context):
+            try:
+                for i in range(5):
+                    await asyncio.sleep(0.03)
+                    yield i
+            finally:
+                events.append("closed")
+
+        async def consume():
+            received = []
+            try:
+                async for token in stream_with_deadline(tokens, "req", context, seconds):
+                    received.append(token)
+            except DeadlineExceeded:
+                events.append("expired")
+            return received
+
+        return asyncio.run(consume()), events
+
+    def test_stream_without_deadline_is_cut_off(self):
+        received, events = self.run_stream(FakeContext(), 0.08)
+        self.assertLess(len(received), 5)
+        self.assertEqual(events, ["closed", "expired"])
+
+    def test_existing_deadline_is_preserved(self):
+        received, events = self.run_stream(FakeContext(time_remaining=30), 0.08)
+        self.assertEqual(received, [0, 1, 2, 3, 4])
+        self.assertEqual(events, ["closed"])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,212 @@
+"""Tests for embedding model selection, index and collection dimension
+checks, and embedding normalization."""
+
+import asyncio
+import math
+import os
+import sys
+import unittest
+from types import SimpleNamespace
+from unittest import mock
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from config.settings import ServiceConfig
+from utils.embeddings import (
+    PROVIDER_HUGGINGFACE,
+    PROVIDER_OPENAI,
+    CollectionDimensions,
+    EmbeddingDimensionMismatch,
+    NormalizedEmbeddings,
+    UnsupportedEmbeddingModel,
+    check_index_dimensions,
+    l2_normalize,
+    resolve_embedding_model,
+)
+from utils.fakes import FakeIndex, FakeVectorStore
+
+
+class TestResolveEmbeddingModel(unittest.TestCase):
+    def test_supported_models(self):
+        spec = resolve_embedding_model("text-embedding-3-large")
+        self.assertEqual((PROVIDER_OPENAI, 3072), (spec.provider, spec.dimensions))
+
+        spec = resolve_embedding_model("llama")
+        self.assertEqual(PROVIDER_HUGGINGFACE, spec.provider)
+        self.assertEqual("sentence-transformers/paraphrase-multilingual-MiniLM-L12-v2", spec.model_name)
+        self.assertEqual(384, spec.dimensions)
+
+    def test_unsupported_model_lists_alternatives(self):
+        with self.assertRaises(UnsupportedEmbeddingModel) as cm:
+            resolve_embedding_model("text-embedding-4-mega")
+        self.assertIn("text-embedding-3-small", str(cm.exception))
+
+
+class TestCheckIndexDimensions(unittest.TestCase):
+    def test_matching_indexes_pass(self):
+        check_index_dimensions("llama", 384, [("a", 384), ("b", 384)])
+
+    def test_mismatch_names_the_index(self):
+        with self.assertRaises(EmbeddingDimensionMismatch) as cm:
+            check_index_dimensions("llama", 384, [("a", 384), ("scores", 1536)])
+        self.assertEqual({"scores": 1536}, cm.exception.mismatched)
+        self.assertIn("'scores' has 1536", str(cm.exception))
+
+
+class TestCollectionDimensions(unittest.TestCase):
+    def setUp(self):
+        self.sizes = {"scores": 384, "scholarships": 1536}
+        self.described = []
+
+    def describe(self, name):
+        self.described.append(name)
+        return self.sizes[name]
+
+    def test_matching_collections_pass_and_are_described_once(self):
+        registry = CollectionDimensions(self.describe)
+        registry.check("llama", 384, ["scores"])
+        registry.check("llama", 384, ["scores"])
+        self.assertEqual(["scores"], self.described)
+
+    def test_mismatch_names_the_collection(self):
+        registry = CollectionDimensions(self.describe)
+        with self.assertRaises(EmbeddingDimensionMismatch) as cm:
+            registry.check("llama", 384, ["scores", "scholarships"])
+        self.assertEqual({"scholarships": 1536}, cm.exception.mismatched)
+
+    def test_configured_sizes_are_not_described(self):
+        registry = CollectionDimensions(self.describe, {"scholarships": 384})
+        registry.check("llama", 384, ["scholarships"])
+        self.assertEqual([], self.described)
+
+    def test_collections_that_cannot_be_described_are_skipped(self):
+        registry = CollectionDimensions(self.describe)
+        registry.check("llama", 384, ["missing"])
+        self.assertIsNone(registry.dimension("missing"))
+
+    def test_forgotten_collection_is_described_again(self):
+        registry = CollectionDimensions(self.describe)
+        registry.check("llama", 384, ["scores"])
+        # Recreated with another model's vectors
+        self.sizes["scores"] = 1536
+        registry.forget("scores")
+        with self.assertRaises(EmbeddingDimensionMismatch):
+            registry.check("llama", 384, ["scores"])
+
+
+class ScriptedEmbeddings:
+    """Embeds each text as the vector scripted for it, which need not be of
+    unit length, and records the texts of both paths."""
+
+    dimensions = 2
+
+    def __init__(self, vectors):
+        self.vectors = vectors
+        self.documents = []
+        self.queries = []
+
+    def embed_documents(self, texts):
+        self.documents.extend(texts)
+        return [self.vectors[text] for text in texts]
+
+    def embed_query(self, text):
+        self.queries.append(text)
+        return self.vectors[text]
+
+    async def aembed_documents(self, texts):
+        return self.embed_documents(texts)
+
+    async def aembed_query(self, text):
+        return self.embed_query(text)
+
+
+def norm(vector):
+    return math.sqrt(sum(v * v for v in vector))
+
+
+class TestNormalizedEmbeddings(unittest.TestCase):
+    def setUp(self):
+        # "aligned" points the query's way but is short; "long" points
+        # elsewhere but is long enough to outscore it unnormalized
+        self.scripted = ScriptedEmbeddings({
+            "query": [2.0, 0.0],
+            "aligned": [0.5, 0.0],
+            "long": [3.0, 3.0],
+        })
+
+    def test_l2_normalize(self):
+        self.assertEqual([0.6, 0.8], l2_normalize([3.0, 4.0]))
+        self.assertEqual([0.0, 0.0], l2_normalize([0.0, 0.0]))
+
+    def test_documents_and_queries_are_normalized(self):
+        embeddings = NormalizedEmbeddings(self.scripted)
+        for vector in embeddings.embed_documents(["aligned", "long"]):
+            self.assertAlmostEqual(1.0, norm(vector))
+        self.assertEqual([1.0, 0.0], embeddings.embed_query("query"))
+        self.assertEqual(["aligned", "long"], self.scripted.documents)
+        self.assertEqual(["query"], self.scripted.queries)
+
+        vectors = asyncio.run(embeddings.aembed_documents(["long"]))
+        self.assertAlmostEqual(1.0, norm(vectors[0]))
+        self.assertEqual([1.0, 0.0], asyncio.run(embeddings.aembed_query("query")))
+
+    def test_other_attributes_are_passed_through(self):
+        self.assertEqual(2, NormalizedEmbeddings(self.scripted).dimensions)
+
+    def search(self, embeddings):
+        store = FakeVectorStore(FakeIndex("scores", 2), embeddings)
+        store.add_documents([SimpleNamespace(page_content=t, metadata={}) for t in ("long", "aligned")])
+        return store, [(doc.page_content, score) for doc, score in store.similarity_search_with_score("query")]
+
+    def test_normalization_ranks_by_direction(self):
+        _, ranked = self.search(self.scripted)
+        self.assertEqual(["long", "aligned"], [text for text, _ in ranked])
+        self.assertEqual([6.0, 1.0], [score for _, score in ranked])
+
+        store, ranked = self.search(NormalizedEmbeddings(self.scripted))
+        self.assertEqual(["aligned", "long"], [text for text, _ in ranked])
+        self.assertAlmostEqual(1.0, ranked[0][1])
+        self.assertAlmostEqual(1 / math.sqrt(2), ranked[1][1])
+        # Stored vectors are normalized too, not only the query
+        for _, vector in store.index.entries:
+            self.assertAlmostEqual(1.0, norm(vector))
+
+
+class TestEmbeddingSettings(unittest.TestCase):
+    def config(self, **env):
+        with mock.patch.dict(os.environ, {"OPENAI_API_KEY": "sk-test", **env}):
+            if "EMBEDDING_DIMENSIONS" not in env:
+                os.environ.pop("EMBEDDING_DIMENSIONS", None)
+            return ServiceConfig()
+
+    def test_dimensions_follow_the_model(self):
+        cfg = self.config(EMBEDDING_MODEL="sentence-transformers/all-mpnet-base-v2")
+        self.assertEqual(768, cfg.vector_store.embedding_dimensions)
+        cfg.validate()
+
+    def test_unsupported_model_fails_validation(self):
+        cfg = self.config(EMBEDDING_MODEL="not-a-model")
+        with self.assertRaisesRegex(ValueError, "unsupported embedding model 'not-a-model'"):
+            cfg.validate()
+
+    def test_conflicting_dimensions_fail_validation(self):
+        cfg = self.config(EMBEDDING_MODEL="llama", EMBEDDING_DIMENSIONS="1536")
+        with self.assertRaisesRegex(ValueError, "embedding_dimensions is 1536 but 'llama' produces 384"):
+            cfg.validate()
+
+    def test_collection_dimensions_from_the_environment(self):
+        cfg = self.config(COLLECTION_DIMENSIONS='{"scholarships": 384}')
+        self.assertEqual({"scholarships": 384}, cfg.vector_store.collection_dimensions)
+        cfg.validate()
+
+        cfg = self.config(COLLECTION_DIMENSIONS='{"scholarships": 0}')
+        with self.assertRaisesRegex(ValueError, "collection_dimensions"):
+            cfg.validate()
+
+    def test_normalization_from_the_environment(self):
+        self.assertFalse(self.config().vector_store.normalize_embeddings)
+        self.assertTrue(self.config(NORMALIZE_EMBEDDINGS="true").vector_store.normalize_embeddings)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,102 @@
+"""Tests for the deterministic test-mode fakes."""
+
+import asyncio
+import os
+import sys
+import unittest
+from types import SimpleNamespace
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.generation import continuation_prompt
+from utils.fakes import (
+    FakeChatModel,
+    FakeEmbeddings,
+    FakePineconeClient,
+    fake_vector_store_factory,
+)
+
+PROMPT = "Context:\n[Source 1 - hust.pdf]\nHUST cutoff is 28.5\n\nQuestion: What is the HUST cutoff?"
+
+
+def collect(model, prompt):
+    async def run():
+        return "".join([chunk.content async for chunk in model.astream(prompt)])
+    return asyncio.run(run())
+
+
+class Schema:
+    """Stands in for a pydantic grader schema."""
+
+    def __init__(self, **fields):
+        self.__dict__.update(fields)
+
+
+class TestFakeChatModel(unittest.TestCase):
+    def test_same_seed_gives_same_reply(self):
+        self.assertEqual(collect(FakeChatModel(seed=7), PROMPT), collect(FakeChatModel(seed=7), PROMPT))
+
+    def test_reply_cites_sources_in_prompt(self):
+        reply = collect(FakeChatModel(), PROMPT)
+        self.assertIn("[Source 1 - hust.pdf]", reply)
+        self.assertIn("no sources", collect(FakeChatModel(), "Question: hello"))
+
+    def test_scripted_responses_cycle(self):
+        model = FakeChatModel(responses=["first", "second"])
+        self.assertEqual(["first", "second", "first"], [collect(model, PROMPT) for _ in range(3)])
+        self.assertEqual([PROMPT] * 3, model.prompts)
+
+    def test_max_words_cuts_reply_at_length(self):
+        async def run(model, prompt):
+            return [chunk async for chunk in model.astream(prompt)]
+        chunks = asyncio.run(run(FakeChatModel(max_words=3), PROMPT))
+        self.assertEqual(3, len(chunks))
+        self.assertEqual("length", chunks[-1].response_metadata["finish_reason"])
+        self.assertEqual({}, chunks[0].response_metadata)
+
+        chunks = asyncio.run(run(FakeChatModel(), PROMPT))
+        self.assertEqual("stop", chunks[-1].response_metadata["finish_reason"])
+
+    def test_continuation_replies_with_the_rest(self):
+        full = collect(FakeChatModel(), PROMPT)
+        partial = collect(FakeChatModel(max_words=3), PROMPT)
+        rest = collect(FakeChatModel(), continuation_prompt(PROMPT, partial))
+        self.assertEqual(full, partial + rest)
+
+    def test_structured_output(self):
+        Schema.model_fields = {"datasource": None}
+        self.assertEqual("web_search", FakeChatModel(route="web_search").with_structured_output(Schema).invoke([]).datasource)
+        Schema.model_fields = {"binary_score": None}
+        self.assertEqual("no", FakeChatModel(grade="no").with_structured_output(Schema).invoke([]).binary_score)
+
+
+class TestFakeVectorStore(unittest.TestCase):
+    def setUp(self):
+        self.pinecone = FakePineconeClient(dimension=32)
+        self.store = fake_vector_store_factory(self.pinecone.Index(name="careerup"), FakeEmbeddings(32, seed=1))
+        self.store.add_documents([
+            SimpleNamespace(page_content="HUST admission cutoff scores", metadata={}),
+            SimpleNamespace(page_content="Hanoi weather in winter", metadata={}),
+        ])
+
+    def test_most_similar_document_first(self):
+        results = self.store.similarity_search_with_score("HUST cutoff", k=2)
+        self.assertEqual("HUST admission cutoff scores", results[0][0].page_content)
+        self.assertGreater(results[0][1], results[1][1])
+
+    def test_embeddings_are_seeded(self):
+        self.assertEqual(FakeEmbeddings(32, seed=1).embed_query("hust"), FakeEmbeddings(32, seed=1).embed_query("hust"))
+        self.assertNotEqual(FakeEmbeddings(32, seed=1).embed_query("hust"), FakeEmbeddings(32, seed=2).embed_query("hust"))
+
+    def test_index_lifecycle(self):
+        self.pinecone.create_index("new", dimension=32, metric="cosine", spec=None)
+        self.assertTrue(self.pinecone.describe_index("new").status.ready)
+        self.assertEqual({"careerup", "new"}, {i.name for i in self.pinecone.list_indexes()})
+        self.assertEqual(2, self.pinecone.Index(name="careerup").describe_index_stats()["total_vector_count"])
+        self.pinecone.delete_index("new")
+        with self.assertRaises(KeyError):
+            self.pinecone.describe_index("new")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,122 @@
+"""Tests for failing generation over to the next model in the chain."""
+
+import asyncio
+import os
+import sys
+import unittest
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.fallback import EVENT_FALLBACK, EVENT_TOKEN, is_retryable, stream_with_fallback
+
+
+class RateLimitError(Exception):
+    """Stands in for openai.RateLimitError, matched by name."""
+
+
+class AuthenticationError(Exception):
+    status_code = 401
+
+
+class FakeModels:
+    """Streams scripted tokens per model, raising where a script has an error."""
+
+    def __init__(self, scripts):
+        self.scripts = scripts
+        self.opened = []
+
+    async def stream(self, model):
+        self.opened.append(model)
+        for item in self.scripts[model]:
+            if isinstance(item, Exception):
+                raise item
+            yield item
+
+
+def collect(models, fake, **kwargs):
+    async def run():
+        return [event async for event in stream_with_fallback(models, fake.stream, **kwargs)]
+    return asyncio.run(run())
+
+
+class StreamWithFallbackTest(unittest.TestCase):
+    def test_primary_success_never_touches_fallback(self):
+        fake = FakeModels({"primary": ["Hel", "lo"], "secondary": ["unused"]})
+        events = collect(["primary", "secondary"], fake)
+
+        self.assertEqual(events, [(EVENT_TOKEN, "Hel"), (EVENT_TOKEN, "lo")])
+        self.assertEqual(fake.opened, ["primary"])
+
+    def test_primary_error_falls_back_to_secondary(self):
+        fake = FakeModels({"primary": [RateLimitError("429")], "secondary": ["Hi", "!"]})
+        fallbacks = []
+        events = collect(["primary", "secondary"], fake,
+                         on_fallback=lambda failed, nxt, err: fallbacks.append((failed, nxt, err)))
+
+        self.assertEqual(events, [(EVENT_FALLBACK, "secondary"), (EVENT_TOKEN, "Hi"), (EVENT_TOKEN, "!")])
+        self.assertEqual(fake.opened, ["primary", "secondary"])
+        self.assertEqual([(f, n) for f, n, _ in fallbacks], [("primary", "secondary")])
+        self.assertIsInstance(fallbacks[0][2], RateLimitError)
+
+    def test_mid_stream_error_restarts_on_next_model(self):
+        fake = FakeModels({
+            "primary": ["The ans", ConnectionError("reset")],
+            "secondary": ["The answer"],
+        })
+        events = collect(["primary", "secondary"], fake)
+
+        # The partial token is followed by a fallback marker, then the full
+        # answer from the start rather than a continuation
+        self.assertEqual(events, [
+            (EVENT_TOKEN, "The ans"),
+            (EVENT_FALLBACK, "secondary"),
+            (EVENT_TOKEN, "The answer"),
+        ])
+
+    def test_walks_the_whole_chain(self):
+        fake = FakeModels({
+            "a": [RateLimitError()],
+            "b": [asyncio.TimeoutError()],
+            "c": ["ok"],
+        })
+        events = collect(["a", "b", "c"], fake)
+
+        self.assertEqual([v for e, v in events if e == EVENT_FALLBACK], ["b", "c"])
+        self.assertEqual(events[-1], (EVENT_TOKEN, "ok"))
+
+    def test_last_model_error_is_raised(self):
+        fake = FakeModels({"primary": [RateLimitError("p")], "secondary": [RateLimitError("s")]})
+        with self.assertRaises(RateLimitError) as ctx:
+            collect(["primary", "secondary"], fake)
+        self.assertEqual(str(ctx.exception), "s")
+
+    def test_non_retryable_error_does_not_fall_back(self):
+        fake = FakeModels({"primary": [AuthenticationError("bad key")], "secondary": ["unused"]})
+        with self.assertRaises(AuthenticationError):
+            collect(["primary", "secondary"], fake)
+        self.assertEqual(fake.opened, ["primary"])
+
+
+class IsRetryableTest(unittest.TestCase):
+    def test_classifies_provider_errors(self):
+        class ServiceUnavailable(Exception):
+            status_code = 503
+
+        class APITimeoutError(Exception):
+            pass
+
+        class BadRequestError(Exception):
+            status_code = 400
+
+        self.assertTrue(is_retryable(RateLimitError()))
+        self.assertTrue(is_retryable(APITimeoutError()))
+        self.assertTrue(is_retryable(ServiceUnavailable()))
+        self.assertTrue(is_retryable(asyncio.TimeoutError()))
+        self.assertTrue(is_retryable(ConnectionResetError()))
+        self.assertFalse(is_retryable(BadRequestError()))
+        self.assertFalse(is_retryable(AuthenticationError()))
+        self.assertFalse(is_retryable(ValueError()))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,206 @@
+"""Tests for resumable ingestion jobs."""
+
+import asyncio
+import os
+import sys
+import tempfile
+import unittest
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from test_ingestion import FakeSplitter, FlakyVectorStore
+from utils.ingest_jobs import (
+    EVENT_BATCH,
+    EVENT_DOCUMENT,
+    EVENT_ERROR,
+    EVENT_SUMMARY,
+    JOB_CANCELLED,
+    JOB_COMPLETED,
+    JOB_FAILED,
+    IngestJobStore,
+    JobDocument,
+    JobEvents,
+    queued_events,
+    run_job,
+    sse_event,
+)
+
+
+class Interrupted(BaseException):
+    """Stands in for the process being stopped mid-job."""
+
+
+class CountingVectorStore:
+    """Records every chunk it embeds; raises Interrupted once limit is reached."""
+
+    def __init__(self, limit=None):
+        self.limit = limit
+        self.embedded = []
+
+    def add_documents(self, documents, ids):
+        if self.limit is not None and len(self.embedded) >= self.limit:
+            raise Interrupted()
+        self.embedded.extend(ids)
+        return ids
+
+
+class IngestJobTest(unittest.TestCase):
+    def setUp(self):
+        self.tmp = tempfile.TemporaryDirectory()
+        self.store = IngestJobStore(self.tmp.name)
+        self.splitter = FakeSplitter(100)
+        # 5 chunks, then 3
+        self.documents = [
+            JobDocument(document_id="guide", content="a" * 500),
+            JobDocument(document_id="fees", content="b" * 300, metadata={"source": "fees.pdf"}),
+        ]
+
+    def tearDown(self):
+        self.tmp.cleanup()
+
+    def run_job(self, job, vector_store, **kwargs):
+        return asyncio.run(run_job(self.store, job, self.splitter, vector_store,
+                                   batch_size=2, max_retries=0, retry_delay=0, **kwargs))
+
+    def test_completes_and_reports_progress(self):
+        job = self.store.create("university-scores", self.documents)
+        vector_store = CountingVectorStore()
+        self.run_job(job, vector_store)
+
+        progress = self.store.get(job.job_id).progress()
+        self.assertEqual(progress["status"], JOB_COMPLETED)
+        self.assertEqual(progress["total_chunks"], 8)
+        self.assertEqual(progress["stored_chunks"], 8)
+        self.assertEqual([d["stored_chunks"] for d in progress["documents"]], [5, 3])
+        self.assertNotIn("content", progress["documents"][0])
+        self.assertEqual(vector_store.embedded, [f"guide#{i}" for i in range(5)] + [f"fees#{i}" for i in range(3)])
+
+    def test_resume_after_interruption_skips_stored_chunks(self):
+        job = self.store.create("university-scores", self.documents)
+        # Stopped after 4 chunks, two batches into the first document
+        with self.assertRaises(Interrupted):
+            self.run_job(job, CountingVectorStore(limit=4))
+
+        # A new process only has the checkpoint to go on
+        saved = self.store.get(job.job_id)
+        self.assertEqual(saved.status, JOB_FAILED)
+        self.assertEqual(saved.progress()["stored_chunks"], 4)
+
+        vector_store = CountingVectorStore()
+        self.run_job(saved, vector_store)
+        self.assertEqual(vector_store.embedded, ["guide#4", "fees#0", "fees#1", "fees#2"])
+        resumed = self.store.get(job.job_id)
+        self.assertEqual(resumed.status, JOB_COMPLETED)
+        self.assertEqual(resumed.progress()["stored_chunks"], 8)
+        self.assertEqual(resumed.errors, [])
+
+    def test_failed_batches_are_retried_on_resume(self):
+        job = self.store.create("university-scores", self.documents)
+        self.run_job(job, FlakyVectorStore(fail_batches={"guide#2"}))
+
+        saved = self.store.get(job.job_id)
+        self.assertEqual(saved.status, JOB_FAILED)
+        self.assertEqual(len(saved.errors), 1)
+        self.assertIn("guide", saved.errors[0])
+        self.assertEqual(saved.progress()["stored_chunks"], 6)
+
+        vector_store = CountingVectorStore()
+        self.run_job(saved, vector_store)
+        self.assertEqual(vector_store.embedded, ["guide#2", "guide#3"])
+        self.assertEqual(self.store.get(job.job_id).status, JOB_COMPLETED)
+
+    def test_cancelled_job_keeps_stored_chunks_and_resumes(self):
+        job = self.store.create("university-scores", self.documents)
+        vector_store = CountingVectorStore()
+        # Cancelled once the first batch is stored
+        self.run_job(job, vector_store, cancelled=lambda: len(vector_store.embedded) >= 2)
+
+        self.assertEqual(vector_store.embedded, ["guide#0", "guide#1"])
+        saved = self.store.get(job.job_id)
+        self.assertEqual(saved.status, JOB_CANCELLED)
+        self.assertEqual(saved.errors, [])
+        self.assertEqual(saved.progress()["stored_chunks"], 2)
+        # The second document was never split
+        self.assertEqual(saved.progress()["documents_split"], 1)
+
+        vector_store = CountingVectorStore()
+        self.run_job(saved, vector_store)
+        self.assertEqual(vector_store.embedded, [f"guide#{i}" for i in range(2, 5)] + [f"fees#{i}" for i in range(3)])
+        self.assertEqual(self.store.get(job.job_id).status, JOB_COMPLETED)
+
+    def test_progress_events_follow_the_job_in_order(self):
+        job = self.store.create("university-scores", self.documents)
+        events = []
+        self.run_job(job, CountingVectorStore(), on_event=lambda event, data: events.append((event, data)))
+
+        self.assertEqual([
+            (EVENT_DOCUMENT, "guide", 0),
+            (EVENT_BATCH, "guide", 2),
+            (EVENT_BATCH, "guide", 4),
+            (EVENT_BATCH, "guide", 5),
+            (EVENT_DOCUMENT, "fees", 0),
+            (EVENT_BATCH, "fees", 2),
+            (EVENT_BATCH, "fees", 3),
+        ], [(event, data["document_id"], data["stored_chunks"]) for event, data in events[:-1]])
+        self.assertEqual({"document_id": "fees", "document": 2, "documents_total": 2,
+                          "total_chunks": 3, "stored_chunks": 0}, events[4][1])
+        self.assertEqual([2, 4, 5, 7, 8], [data["job_stored_chunks"] for event, data in events if event == EVENT_BATCH])
+        self.assertEqual((EVENT_SUMMARY, self.store.get(job.job_id).progress()), events[-1])
+
+    def test_failed_batches_and_interruptions_are_reported(self):
+        job = self.store.create("university-scores", self.documents)
+        events = []
+        self.run_job(job, FlakyVectorStore(fail_batches={"guide#2"}), on_event=lambda event, data: events.append((event, data)))
+
+        errors = [data for event, data in events if event == EVENT_ERROR]
+        self.assertEqual(1, len(errors))
+        self.assertEqual("guide", errors[0]["document_id"])
+        self.assertEqual((EVENT_SUMMARY, JOB_FAILED), (events[-1][0], events[-1][1]["status"]))
+
+        events.clear()
+        job = self.store.create("university-scores", [JobDocument(document_id="guide", content="a" * 500)])
+        with self.assertRaises(Interrupted):
+            self.run_job(job, CountingVectorStore(limit=4), on_event=lambda event, data: events.append((event, data)))
+        self.assertEqual((EVENT_SUMMARY, JOB_FAILED), (events[-1][0], events[-1][1]["status"]))
+
+    def test_unknown_job(self):
+        self.assertIsNone(self.store.get("missing"))
+        self.assertIsNone(self.store.get("../etc/passwd"))
+
+
+
+class JobEventsTest(unittest.TestCase):
+    def test_subscribers_get_events_until_the_summary(self):
+        async def run():
+            events = JobEvents()
+            events.publish("job-1", EVENT_BATCH, {"stored_chunks": 1})
+            queue = events.subscribe("job-1")
+            other = events.subscribe("job-2")
+            events.publish("job-1", EVENT_BATCH, {"stored_chunks": 2})
+            events.publish("job-1", EVENT_SUMMARY, {"status": JOB_COMPLETED})
+            received = [event async for event in queued_events(queue)]
+            events.unsubscribe("job-1", queue)
+            self.assertTrue(other.empty())
+            return received, events
+
+        received, events = asyncio.run(run())
+        self.assertEqual([(EVENT_BATCH, {"stored_chunks": 2}), (EVENT_SUMMARY, {"status": JOB_COMPLETED})], received)
+        self.assertNotIn("job-1", events._subscribers)
+
+    def test_finish_ends_subscriptions_without_a_summary(self):
+        async def run():
+            events = JobEvents()
+            queue = events.subscribe("job-1")
+            events.publish("job-1", EVENT_BATCH, {"stored_chunks": 1})
+            events.finish("job-1")
+            return [event async for event in queued_events(queue)]
+
+        self.assertEqual([(EVENT_BATCH, {"stored_chunks": 1})], asyncio.run(run()))
+
+    def test_sse_event(self):
+        self.assertEqual('event: batch\ndata: {"document_id": "hướng-dẫn"}\n\n',
+                         sse_event(EVENT_BATCH, {"document_id": "hướng-dẫn"}))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,310 @@
+"""Tests for document ingestion, including dry runs."""
+
+import asyncio
+import os
+import sys
+import unittest
+from types import SimpleNamespace
+from unittest import mock
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.fakes import FakeEmbeddings, FakePineconeClient, fake_vector_store_factory
+from utils.ingestion import (
+    SECTION_METADATA_KEY,
+    TokenLimitedSplitter,
+    estimate_embedding_cost,
+    estimate_tokens,
+    ingest_document,
+    sample_ids,
+    section_documents,
+    upsert_in_batches,
+    verify_upsert,
+)
+from utils.memory_store import MemoryVectorStore
+from utils.pinecone_store import PineconeStore
+from utils.retrieval import format_context
+from utils.vector_backend import CollectionHandle
+
+
+class FakeSplitter:
+    """Splits a document into fixed-size chunks."""
+
+    def __init__(self, size):
+        self.size = size
+
+    def split_documents(self, documents):
+        chunks = []
+        for doc in documents:
+            text = doc.page_content
+            for i in range(0, len(text), self.size):
+                chunks.append(SimpleNamespace(page_content=text[i:i + self.size], metadata=doc.metadata))
+        return chunks
+
+
+class IngestDocumentTest(unittest.TestCase):
+    def setUp(self):
+        self.document = SimpleNamespace(page_content="a" * 250 + "b" * 250, metadata={})
+        self.vector_store = mock.Mock()
+
+    def test_dry_run_returns_chunk_metadata(self):
+        result = asyncio.run(ingest_document(
+            FakeSplitter(300), self.vector_store, self.document,
+            "text-embedding-3-small", dry_run=True,
+        ))
+
+        self.assertTrue(result.dry_run)
+        self.assertEqual(len(result.chunks), 2)
+        self.assertEqual([p["index"] for p in result.previews], [0, 1])
+        self.assertEqual([p["char_count"] for p in result.previews], [300, 200])
+        self.assertEqual([p["estimated_tokens"] for p in result.previews], [75, 50])
+        self.assertEqual(len(result.previews[0]["preview"]), 200)
+        self.assertEqual(result.estimated_tokens, 125)
+        self.assertAlmostEqual(result.estimated_cost_usd, 125 / 1000 * 0.00002)
+
+    def test_dry_run_makes_no_vector_store_calls(self):
+        asyncio.run(ingest_document(
+            FakeSplitter(100), self.vector_store, self.document,
+            "text-embedding-3-small", dry_run=True,
+        ))
+        # Embedding happens inside the vector store, so no calls means no embedder calls
+        self.assertEqual(self.vector_store.mock_calls, [])
+
+    def test_ingest_writes_chunks(self):
+        result = asyncio.run(ingest_document(
+            FakeSplitter(100), self.vector_store, self.document, "text-embedding-3-small",
+        ))
+        self.assertFalse(result.dry_run)
+        self.assertEqual(result.previews, [])
+        ids = [f"doc#{i}" for i in range(5)]
+        self.vector_store.add_documents.assert_called_once_with(result.chunks, ids=ids)
+        self.assertEqual(result.succeeded_ids, ids)
+        self.assertEqual(result.failed_ids, [])
+
+    def test_ingest_reports_failed_batches(self):
+        store = FlakyVectorStore(fail_batches={"report#2"})
+        result = asyncio.run(ingest_document(
+            FakeSplitter(100), store, self.document, "text-embedding-3-small",
+            document_id="report", batch_size=2, max_retries=1, retry_delay=0,
+        ))
+
+        self.assertEqual(result.succeeded_ids, ["report#0", "report#1", "report#4"])
+        self.assertEqual(result.failed_ids, ["report#2", "report#3"])
+        self.assertTrue(result.partial)
+        self.assertEqual(len(result.errors), 1)
+        self.assertIn("batch 2", result.errors[0])
+        # The failing batch was tried twice, the others once
+        self.assertEqual(store.attempts, {"report#0": 1, "report#2": 2, "report#4": 1})
+
+
+class SectionsTest(unittest.TestCase):
+    def setUp(self):
+        self.document = SimpleNamespace(page_content="", metadata={"source": "fees.pdf"})
+        self.sections = [("Tuition", "t" * 150), ("Scholarships", "s" * 50)]
+
+    def test_chunks_carry_their_section_title(self):
+        result = asyncio.run(ingest_document(
+            FakeSplitter(100), mock.Mock(), self.document, "text-embedding-3-small",
+            dry_run=True, sections=self.sections,
+        ))
+        self.assertEqual([c.metadata.get(SECTION_METADATA_KEY) for c in result.chunks],
+                         ["Tuition", "Tuition", "Scholarships"])
+        self.assertTrue(all(c.metadata["source"] == "fees.pdf" for c in result.chunks))
+
+    def test_chunks_do_not_cross_section_boundaries(self):
+        result = asyncio.run(ingest_document(
+            FakeSplitter(100), mock.Mock(), self.document, "text-embedding-3-small",
+            dry_run=True, sections=self.sections,
+        ))
+        # Split whole, the second chunk would hold the last 50 t's and all the s's
+        self.assertEqual([c.page_content for c in result.chunks], ["t" * 100, "t" * 50, "s" * 50])
+
+    def test_content_comes_first_without_a_title(self):
+        document = SimpleNamespace(page_content="Overview", metadata={})
+        parts = section_documents(document, [("Tuition", "Fees"), ("Empty", "")])
+        self.assertEqual([p.page_content for p in parts], ["Overview", "Fees"])
+        self.assertNotIn(SECTION_METADATA_KEY, parts[0].metadata)
+        self.assertEqual(parts[1].metadata, {SECTION_METADATA_KEY: "Tuition"})
+        # The document's own metadata is not changed by its sections'
+        self.assertEqual(document.metadata, {})
+
+    def test_section_titles_reach_the_rag_context(self):
+        store = MemoryVectorStore(FakeEmbeddings(16), 16, ["fees"])
+        collection = CollectionHandle(store, "fees")
+        asyncio.run(ingest_document(
+            FakeSplitter(100), collection, self.document, "text-embedding-3-small",
+            document_id="fees", sections=[("Tuition", "Tuition is 30 million VND a year")],
+        ))
+        docs = [d for d, _ in collection.similarity_search_with_score("tuition", k=1)]
+        context = format_context(docs, "Source", "database", "Section")
+        self.assertIn("Section: Tuition\nTuition is 30 million VND a year", context)
+
+
+class FlakyVectorStore:
+    """Vector store whose batches fail while they start with a listed ID.
+
+    A batch in transient_failures fails that many times and then succeeds.
+    """
+
+    def __init__(self, fail_batches=(), transient_failures=None):
+        self.fail_batches = set(fail_batches)
+        self.transient_failures = dict(transient_failures or {})
+        self.attempts = {}
+        self.stored = []
+
+    def add_documents(self, documents, ids):
+        first = ids[0]
+        self.attempts[first] = self.attempts.get(first, 0) + 1
+        if first in self.fail_batches:
+            raise RuntimeError("upsert rejected")
+        if self.attempts[first] <= self.transient_failures.get(first, 0):
+            raise RuntimeError("timeout")
+        self.stored.extend(ids)
+        return ids
+
+
+class UpsertInBatchesTest(unittest.TestCase):
+    def chunks(self, n):
+        return [SimpleNamespace(page_content=str(i), metadata={}) for i in range(n)]
+
+    def test_transient_failures_are_retried(self):
+        store = FlakyVectorStore(transient_failures={"c3": 2})
+        ids = [f"c{i}" for i in range(6)]
+        with mock.patch("asyncio.sleep", new=mock.AsyncMock()) as sleep:
+            result = asyncio.run(upsert_in_batches(
+                store, self.chunks(6), ids, batch_size=3, max_retries=2, retry_delay=0.5,
+            ))
+
+        self.assertEqual(result.succeeded_ids, ids)
+        self.assertEqual(result.failed_ids, [])
+        self.assertFalse(result.partial)
+        self.assertEqual(store.stored, ids)
+        # Backoff doubles between attempts
+        self.assertEqual([c.args[0] for c in sleep.await_args_list], [0.5, 1.0])
+
+    def test_gives_up_after_max_retries(self):
+        store = FlakyVectorStore(fail_batches={"c0"})
+        result = asyncio.run(upsert_in_batches(
+            store, self.chunks(2), ["c0", "c1"], batch_size=10, max_retries=0, retry_delay=0,
+        ))
+        self.assertEqual(result.succeeded_ids, [])
+        self.assertEqual(result.failed_ids, ["c0", "c1"])
+        self.assertFalse(result.partial)
+        self.assertEqual(store.attempts, {"c0": 1})
+
+    def test_cancelling_stops_before_the_next_batch(self):
+        store = mock.Mock()
+        ids = [f"c{i}" for i in range(6)]
+        stored_batches = []
+        result = asyncio.run(upsert_in_batches(
+            store, self.chunks(6), ids, batch_size=2, max_retries=0, retry_delay=0,
+            on_batch_stored=stored_batches.append, cancelled=lambda: len(stored_batches) >= 1,
+        ))
+
+        self.assertEqual(store.add_documents.call_count, 1)
+        self.assertEqual(result.succeeded_ids, ["c0", "c1"])
+        self.assertEqual(result.cancelled_ids, ["c2", "c3", "c4", "c5"])
+        self.assertEqual(result.failed_ids, [])
+        self.assertTrue(result.partial)
+
+
+class VerifyUpsertTest(unittest.TestCase):
+    def setUp(self):
+        client = FakePineconeClient(16)
+        store = PineconeStore(client, FakeEmbeddings(16), fake_vector_store_factory)
+        store.create_collection("faq", 16)
+        self.index = client.Index(name="faq")
+        self.collection = CollectionHandle(store, "faq")
+        self.ids = [f"report#{i}" for i in range(10)]
+        self.collection.add_documents(
+            [SimpleNamespace(page_content=f"chunk {i}", metadata={}) for i in range(10)], ids=self.ids)
+
+    def verify(self, **kwargs):
+        with mock.patch("asyncio.sleep", new=mock.AsyncMock()) as sleep:
+            verification = asyncio.run(verify_upsert(self.collection, self.ids, **kwargs))
+        return verification, [c.args[0] for c in sleep.await_args_list]
+
+    def test_visible_at_once(self):
+        verification, delays = self.verify(sample_size=3)
+        self.assertTrue(verification.visible)
+        self.assertEqual(["report#0", "report#4", "report#9"], verification.sampled_ids)
+        self.assertEqual(1, verification.attempts)
+        self.assertEqual([], delays)
+
+    def test_retries_until_the_index_catches_up(self):
+        for doc_id in self.ids:
+            self.index.lagging[doc_id] = 2
+        verification, delays = self.verify(sample_size=3, max_attempts=5, retry_delay=0.5)
+        self.assertTrue(verification.visible)
+        self.assertEqual([], verification.missing_ids)
+        self.assertEqual(3, verification.attempts)
+        # Backoff doubles between attempts
+        self.assertEqual([0.5, 1.0], delays)
+
+    def test_reports_chunks_still_missing_after_the_last_attempt(self):
+        self.index.lagging["report#9"] = 10
+        verification, _ = self.verify(sample_size=3, max_attempts=3, retry_delay=0)
+        self.assertFalse(verification.visible)
+        self.assertEqual(["report#9"], verification.missing_ids)
+        self.assertEqual(3, verification.attempts)
+
+    def test_failed_fetch_counts_as_an_attempt(self):
+        collection = mock.Mock()
+        collection.visible_ids.side_effect = [RuntimeError("timeout"), ["c0", "c1"]]
+        with mock.patch("asyncio.sleep", new=mock.AsyncMock()):
+            verification = asyncio.run(verify_upsert(collection, ["c0", "c1"], max_attempts=3, retry_delay=0))
+        self.assertTrue(verification.visible)
+        self.assertEqual(2, verification.attempts)
+
+    def test_sample_covers_small_uploads_whole(self):
+        self.assertEqual(["a", "b"], sample_ids(["a", "b"], 5))
+        self.assertEqual(["a"], sample_ids(["a", "b", "c"], 1))
+
+
+class TokenLimitedSplitterTest(unittest.TestCase):
+    def setUp(self):
+        # One chunk of 1000 characters (~250 tokens) and a short one
+        self.document = SimpleNamespace(page_content="x" * 1000 + "y" * 100, metadata={"source": "long.txt"})
+        self.splitter = TokenLimitedSplitter(FakeSplitter(1000), max_tokens=100, make_splitter=FakeSplitter)
+
+    def test_long_chunk_is_split_within_the_limit(self):
+        with self.assertLogs("utils.ingestion", level="INFO") as logs:
+            chunks = self.splitter.split_documents([self.document])
+
+        self.assertEqual([len(c.page_content) for c in chunks], [400, 400, 200, 100])
+        self.assertTrue(all(estimate_tokens(c.page_content) <= 100 for c in chunks))
+        self.assertEqual("".join(c.page_content for c in chunks), self.document.page_content)
+        self.assertTrue(all(c.metadata == {"source": "long.txt"} for c in chunks))
+        self.assertEqual(len(logs.output), 1)
+        self.assertIn("Re-split chunk 0 (~250 tokens) into 3 parts", logs.output[0])
+
+    def test_all_parts_are_indexed(self):
+        store = FlakyVectorStore()
+        result = asyncio.run(ingest_document(
+            self.splitter, store, self.document, "text-embedding-3-small", document_id="long",
+        ))
+
+        ids = [f"long#{i}" for i in range(4)]
+        self.assertEqual(result.succeeded_ids, ids)
+        self.assertEqual(store.stored, ids)
+        self.assertEqual("".join(c.page_content for c in result.chunks), self.document.page_content)
+
+    def test_chunks_within_the_limit_are_kept(self):
+        splitter = TokenLimitedSplitter(FakeSplitter(400), max_tokens=100, make_splitter=FakeSplitter)
+        with self.assertNoLogs("utils.ingestion", level="INFO"):
+            chunks = splitter.split_documents([self.document])
+        self.assertEqual([len(c.page_content) for c in chunks], [400, 400, 300])
+
+
+class EstimateTest(unittest.TestCase):
+    def test_estimate_tokens(self):
+        self.assertEqual(estimate_tokens(""), 0)
+        self.assertEqual(estimate_tokens("abc"), 1)
+        self.assertEqual(estimate_tokens("a" * 400), 100)
+
+    def test_unknown_model_costs_nothing(self):
+        self.assertEqual(estimate_embedding_cost(1000, "llama"), 0.0)
+
+
+if __name__ == "__main__":
+    unittest.main()
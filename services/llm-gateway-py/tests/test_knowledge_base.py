@@ -0,0 +1,95 @@
+"""Tests for detecting an empty knowledge base."""
+
+import os
+import sys
+import unittest
+from types import SimpleNamespace
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.fakes import FakeEmbeddings, FakePineconeClient, fake_vector_store_factory
+from utils.knowledge_base import KnowledgeBaseMonitor
+from utils.memory_store import MemoryVectorStore
+from utils.pinecone_store import PineconeStore
+
+
+def doc(content):
+    return SimpleNamespace(page_content=content, metadata={"source": "hust.pdf"})
+
+
+class Clock:
+    def __init__(self):
+        self.now = 0.0
+
+    def __call__(self):
+        return self.now
+
+
+class KnowledgeBaseMonitorTest(unittest.TestCase):
+    def setUp(self):
+        self.store = MemoryVectorStore(FakeEmbeddings(16), 16)
+        self.clock = Clock()
+        self.monitor = KnowledgeBaseMonitor(self.store, ttl=60, clock=self.clock)
+
+    def test_no_collections_is_empty(self):
+        status = self.monitor.status()
+        self.assertTrue(status.empty)
+        self.assertEqual((0, 0), (status.collections, status.documents))
+
+    def test_collections_without_documents_are_empty(self):
+        self.store.create_collection("universities", 16)
+        self.store.create_collection("scholarships", 16)
+        status = self.monitor.status()
+        self.assertTrue(status.empty)
+        self.assertEqual(2, status.collections)
+
+    def test_documents_in_any_collection_are_not_empty(self):
+        self.store.create_collection("universities", 16)
+        self.store.create_collection("scholarships", 16)
+        self.store.add_documents("scholarships", [doc("HUST merit scholarships")])
+        status = self.monitor.status()
+        self.assertFalse(status.empty)
+        self.assertEqual((2, 1), (status.collections, status.documents))
+
+    def test_status_is_reused_until_the_ttl_or_forget(self):
+        self.store.create_collection("universities", 16)
+        self.assertTrue(self.monitor.status().empty)
+
+        self.store.add_documents("universities", [doc("HUST admission cutoff")])
+        self.assertTrue(self.monitor.status().empty, "reused within the ttl")
+        self.clock.now = 61
+        self.assertFalse(self.monitor.status().empty)
+
+        self.store.clear("universities")
+        self.monitor.forget()
+        self.assertTrue(self.monitor.status().empty)
+
+    def test_uncountable_collection_is_not_empty(self):
+        self.store.create_collection("universities", 16)
+
+        def stats(name):
+            raise ConnectionError("stats unavailable")
+        self.store.stats = stats
+        status = self.monitor.status()
+        self.assertFalse(status.empty)
+        self.assertEqual(1, status.uncounted)
+
+    def test_failed_listing_raises(self):
+        def list_collections():
+            raise ConnectionError("backend down")
+        self.store.list_collections = list_collections
+        with self.assertRaises(ConnectionError):
+            self.monitor.status()
+
+    def test_provisioning_collections_are_skipped(self):
+        client = FakePineconeClient(16)
+        store = PineconeStore(client, FakeEmbeddings(16), fake_vector_store_factory, spec="spec")
+        store.create_collection("universities", 16)
+        client.Index(name="universities").ready = False
+        status = KnowledgeBaseMonitor(store).status()
+        self.assertTrue(status.empty)
+        self.assertEqual(0, status.collections)
+
+
+if __name__ == "__main__":
+    unittest.main()
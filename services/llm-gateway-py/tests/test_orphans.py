@@ -0,0 +1,78 @@
+"""Tests for finding orphaned vector store indexes."""
+
+import json
+import os
+import sys
+import tempfile
+import unittest
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.collection_registry import CollectionRegistry
+from utils.orphans import KEPT_IN_USE, KEPT_PROVISIONING, find_orphans
+from utils.provisioning import STATUS_FAILED, STATUS_PROVISIONING, STATUS_READY
+
+
+class TestFindOrphans(unittest.TestCase):
+    def test_unknown_indexes_are_orphans(self):
+        orphans, kept = find_orphans(
+            {"main": STATUS_READY, "test-tmp": STATUS_READY, "migration-v2": STATUS_FAILED, "faq": STATUS_READY},
+            known=["main", "faq"],
+            in_use=[],
+        )
+        self.assertEqual(["migration-v2", "test-tmp"], orphans)
+        self.assertEqual([], kept)
+
+    def test_active_indexes_are_kept(self):
+        orphans, kept = find_orphans(
+            {"main": STATUS_READY, "opened": STATUS_READY, "new": STATUS_PROVISIONING, "stale": STATUS_READY},
+            known=["main"],
+            in_use=["main", "opened"],
+        )
+        self.assertEqual(["stale"], orphans)
+        self.assertEqual([
+            {"name": "new", "reason": KEPT_PROVISIONING},
+            {"name": "opened", "reason": KEPT_IN_USE},
+        ], kept)
+
+    def test_known_indexes_are_not_reported(self):
+        orphans, kept = find_orphans({"main": STATUS_PROVISIONING}, known=["main"], in_use=["main"])
+        self.assertEqual([], orphans)
+        self.assertEqual([], kept)
+
+
+class TestCollectionRegistry(unittest.TestCase):
+    def setUp(self):
+        directory = tempfile.TemporaryDirectory()
+        self.addCleanup(directory.cleanup)
+        self.path = os.path.join(directory.name, "registry", "collections.json")
+
+    def test_created_collections_outlive_the_registry(self):
+        registry = CollectionRegistry(self.path)
+        self.assertEqual([], registry.names())
+        registry.add("scholarships")
+        registry.add("faq")
+        registry.add("faq")
+
+        # A restart, or another replica sharing the file
+        other = CollectionRegistry(self.path)
+        self.assertEqual(["faq", "scholarships"], other.names())
+        other.remove("faq")
+        other.remove("missing")
+        self.assertEqual(["scholarships"], registry.names())
+
+    def test_unreadable_registry_fails_lookups(self):
+        os.makedirs(os.path.dirname(self.path))
+        with open(self.path, "w") as f:
+            f.write("{not json")
+        with self.assertRaises(ValueError):
+            CollectionRegistry(self.path).names()
+
+    def test_file_is_plain_json(self):
+        CollectionRegistry(self.path).add("faq")
+        with open(self.path) as f:
+            self.assertIn("faq", json.load(f))
+
+
+if __name__ == "__main__":
+    unittest.main()
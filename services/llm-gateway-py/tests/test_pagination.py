@@ -0,0 +1,79 @@
+"""Tests for collection listing pagination."""
+
+import os
+import sys
+import unittest
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.pagination import (
+    DEFAULT_PAGE_SIZE,
+    MAX_PAGE_SIZE,
+    InvalidPageToken,
+    clamp_page_size,
+    paginate,
+    paginate_with_info,
+    pagination_envelope,
+)
+
+
+class TestPaginate(unittest.TestCase):
+    def test_walks_every_page_in_name_order(self):
+        names = [f"user-{i:03d}" for i in range(7)][::-1]
+        pages, token = [], ""
+        while True:
+            page, token = paginate(names, 3, token)
+            pages.append(page)
+            if not token:
+                break
+        self.assertEqual([3, 3, 1], [len(p) for p in pages])
+        self.assertEqual(sorted(names), [name for page in pages for name in page])
+
+    def test_exact_fit_has_no_next_page(self):
+        page, token = paginate(["a", "b"], 2)
+        self.assertEqual(["a", "b"], page)
+        self.assertEqual("", token)
+
+    def test_pages_are_stable_when_collections_change(self):
+        first, token = paginate(["a", "b", "c", "d"], 2)
+        self.assertEqual(["a", "b"], first)
+        # "a" is deleted and "aa" created before the next call
+        second, _ = paginate(["aa", "b", "c", "d"], 2, token)
+        self.assertEqual(["c", "d"], second)
+
+    def test_invalid_token(self):
+        with self.assertRaises(InvalidPageToken):
+            paginate(["a"], 2, "not base64!")
+
+    def test_page_info(self):
+        names = ["c", "a", "e", "b", "d"]
+        page, info = paginate_with_info(names, 2)
+        self.assertEqual(["a", "b"], page)
+        self.assertEqual((5, 0, 2), (info.total, info.offset, info.limit))
+
+        page, info = paginate_with_info(names, 2, info.next_token)
+        self.assertEqual(["c", "d"], page)
+        self.assertEqual(2, info.offset)
+
+        page, info = paginate_with_info(names, 2, info.next_token)
+        self.assertEqual(["e"], page)
+        self.assertEqual(("", 4), (info.next_token, info.offset))
+
+    def test_envelope(self):
+        page, info = paginate_with_info(["a", "b", "c"], 2)
+        body = pagination_envelope([{"name": n} for n in page], info)
+        self.assertEqual([{"name": "a"}, {"name": "b"}], body["data"])
+        self.assertEqual({"total": 3, "limit": 2, "offset": 0, "next": info.next_token}, body["pagination"])
+        self.assertTrue(body["pagination"]["next"])
+
+        page, info = paginate_with_info(["a"], 2)
+        self.assertIsNone(pagination_envelope(page, info)["pagination"]["next"])
+
+    def test_page_size_defaults_and_cap(self):
+        self.assertEqual(DEFAULT_PAGE_SIZE, clamp_page_size(0))
+        self.assertEqual(MAX_PAGE_SIZE, clamp_page_size(10_000))
+        self.assertEqual(5, clamp_page_size(5))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,72 @@
+"""Tests for bounded waits on indexes that are still provisioning."""
+
+import asyncio
+import os
+import sys
+import time
+import unittest
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.provisioning import (
+    STATUS_FAILED,
+    STATUS_PROVISIONING,
+    STATUS_READY,
+    IndexProvisioningTimeout,
+    index_status,
+    wait_until_ready,
+)
+
+
+class FakeIndex:
+    """Reports provisioning for a number of checks (forever by default)."""
+
+    def __init__(self, ready_after=None, final=STATUS_READY):
+        self.ready_after = ready_after
+        self.final = final
+        self.checks = 0
+
+    async def status(self):
+        self.checks += 1
+        if self.ready_after is None or self.checks <= self.ready_after:
+            return STATUS_PROVISIONING
+        return self.final
+
+
+class WaitUntilReadyTest(unittest.TestCase):
+    def wait(self, index, timeout=1.0, poll_interval=0.01):
+        return asyncio.run(wait_until_ready("scholarships", index.status, timeout, poll_interval))
+
+    def test_stuck_index_times_out(self):
+        index = FakeIndex()
+        start = time.monotonic()
+        with self.assertRaises(IndexProvisioningTimeout) as ctx:
+            self.wait(index, timeout=0.05)
+        elapsed = time.monotonic() - start
+
+        self.assertLess(elapsed, 0.5)
+        self.assertGreater(index.checks, 1)
+        self.assertIn("index provisioning timed out", str(ctx.exception))
+        self.assertEqual(ctx.exception.name, "scholarships")
+
+    def test_returns_once_ready(self):
+        index = FakeIndex(ready_after=3)
+        self.assertEqual(self.wait(index), STATUS_READY)
+        self.assertEqual(index.checks, 4)
+
+    def test_returns_failed_without_waiting_out_the_timeout(self):
+        index = FakeIndex(ready_after=1, final=STATUS_FAILED)
+        self.assertEqual(self.wait(index, timeout=10), STATUS_FAILED)
+        self.assertEqual(index.checks, 2)
+
+
+class IndexStatusTest(unittest.TestCase):
+    def test_maps_pinecone_states(self):
+        self.assertEqual(index_status(True, "Ready"), STATUS_READY)
+        self.assertEqual(index_status(False, "Initializing"), STATUS_PROVISIONING)
+        self.assertEqual(index_status(False, "ScalingUp"), STATUS_PROVISIONING)
+        self.assertEqual(index_status(False, "InitializationFailed"), STATUS_FAILED)
+
+
+if __name__ == "__main__":
+    unittest.main()
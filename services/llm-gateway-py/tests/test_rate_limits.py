@@ -0,0 +1,133 @@
+"""Tests for waiting out provider rate limits."""
+
+import asyncio
+import os
+import sys
+import time
+import unittest
+from email.utils import formatdate
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.rate_limits import is_rate_limit, retry_after_header, retry_after_seconds, retry_rate_limited
+
+
+class RateLimitError(Exception):
+    """Stands in for openai.RateLimitError, matched by name."""
+
+    def __init__(self, message="Rate limit reached", headers=None):
+        super().__init__(message)
+        self.response = type("Response", (), {"headers": headers or {}})()
+
+
+class ProviderError(Exception):
+    def __init__(self, status_code):
+        super().__init__(f"HTTP {status_code}")
+        self.status_code = status_code
+
+
+class RateLimitDetectionTest(unittest.TestCase):
+    def test_is_rate_limit(self):
+        self.assertTrue(is_rate_limit(RateLimitError()))
+        self.assertTrue(is_rate_limit(ProviderError(429)))
+        self.assertFalse(is_rate_limit(ProviderError(503)))
+        self.assertFalse(is_rate_limit(ValueError("bad request")))
+
+    def test_retry_after_from_headers(self):
+        self.assertEqual(0.25, retry_after_seconds(RateLimitError(headers={"retry-after-ms": "250"})))
+        self.assertEqual(3.0, retry_after_seconds(RateLimitError(headers={"retry-after": "3"})))
+        # retry-after-ms is the more precise of the two
+        self.assertEqual(0.5, retry_after_seconds(RateLimitError(headers={"retry-after-ms": "500", "retry-after": "1"})))
+
+    def test_retry_after_http_date(self):
+        wait = retry_after_seconds(RateLimitError(headers={"retry-after": formatdate(time.time() + 30, usegmt=True)}))
+        self.assertAlmostEqual(30, wait, delta=2)
+        past = retry_after_seconds(RateLimitError(headers={"retry-after": formatdate(time.time() - 30, usegmt=True)}))
+        self.assertEqual(0.0, past)
+
+    def test_retry_after_from_message(self):
+        self.assertEqual(1.5, retry_after_seconds(RateLimitError(
+            "Rate limit reached for gpt-4o on tokens per min. Please try again in 1.5s.")))
+        self.assertEqual(0.02, retry_after_seconds(RateLimitError("Please try again in 20ms.")))
+
+    def test_retry_after_unknown(self):
+        self.assertIsNone(retry_after_seconds(RateLimitError()))
+        self.assertIsNone(retry_after_seconds(RateLimitError(headers={"retry-after": "soon"})))
+
+    def test_retry_after_header_is_whole_seconds(self):
+        self.assertEqual("2", retry_after_header(RateLimitError(headers={"retry-after-ms": "1200"})))
+        self.assertEqual("1", retry_after_header(RateLimitError(headers={"retry-after-ms": "10"})))
+        self.assertEqual("1", retry_after_header(RateLimitError()))
+
+
+class FakeStream:
+    """Opens streams that raise the scripted errors in turn, then succeed."""
+
+    def __init__(self, *errors, tokens=("Hel", "lo")):
+        self.errors = list(errors)
+        self.tokens = tokens
+        self.opened = 0
+
+    async def open(self):
+        self.opened += 1
+        if self.errors:
+            raise self.errors.pop(0)
+        for token in self.tokens:
+            yield token
+
+
+def collect(fake, max_wait=5.0, max_retries=2):
+    waits = []
+
+    async def sleep(seconds):
+        waits.append(seconds)
+
+    async def run():
+        return [t async for t in retry_rate_limited(fake.open, max_wait, max_retries, sleep=sleep)]
+    return asyncio.run(run()), waits
+
+
+class RetryRateLimitedTest(unittest.TestCase):
+    def test_rate_limited_once_then_succeeds(self):
+        fake = FakeStream(RateLimitError(headers={"retry-after-ms": "300"}))
+        tokens, waits = collect(fake)
+        self.assertEqual(["Hel", "lo"], tokens)
+        self.assertEqual([0.3], waits)
+        self.assertEqual(2, fake.opened)
+
+    def test_default_wait_without_retry_after(self):
+        _, waits = collect(FakeStream(RateLimitError()))
+        self.assertEqual([1.0], waits)
+
+    def test_gives_up_after_max_retries(self):
+        fake = FakeStream(*[RateLimitError("Please try again in 10ms.") for _ in range(3)])
+        with self.assertRaises(RateLimitError):
+            collect(fake, max_retries=2)
+        self.assertEqual(3, fake.opened)
+
+    def test_long_waits_are_not_retried(self):
+        fake = FakeStream(RateLimitError(headers={"retry-after": "60"}))
+        with self.assertRaises(RateLimitError):
+            collect(fake, max_wait=10)
+        self.assertEqual(1, fake.opened)
+
+    def test_other_errors_are_not_retried(self):
+        fake = FakeStream(ProviderError(500))
+        with self.assertRaises(ProviderError):
+            collect(fake)
+        self.assertEqual(1, fake.opened)
+
+    def test_errors_after_the_first_token_are_not_retried(self):
+        class MidStream(FakeStream):
+            async def open(self):
+                self.opened += 1
+                yield "Hel"
+                raise RateLimitError()
+        fake = MidStream()
+        with self.assertRaises(RateLimitError):
+            collect(fake)
+        self.assertEqual(1, fake.opened)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,331 @@
+"""Tests for concurrent retrieval and document merging."""
+
+import asyncio
+import os
+import sys
+import unittest
+from types import SimpleNamespace
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.retrieval import (
+    cites_sources,
+    dedupe_documents,
+    document_sources,
+    format_context,
+    gather_sources,
+    merge_documents,
+    parse_score_boosts,
+    resolve_collections,
+    retrieval_limits,
+    retrieve_from_collections,
+    truncate_text,
+)
+
+
+def doc(content, source=None, score=None):
+    metadata = {"source": source} if source else {}
+    if score is not None:
+        metadata["score"] = score
+    return SimpleNamespace(page_content=content, metadata=metadata)
+
+
+class GatherSourcesTest(unittest.TestCase):
+    def test_sources_are_queried_concurrently(self):
+        started = []
+        both_started = asyncio.Event()
+
+        async def source(name, result):
+            started.append(name)
+            if len(started) == 2:
+                both_started.set()
+            # Each source waits for the other; run sequentially this would time out
+            await asyncio.wait_for(both_started.wait(), timeout=1)
+            return result
+
+        async def run():
+            return await gather_sources(
+                lambda: source("vector", [doc("v1")]),
+                lambda: source("web", [doc("w1", "https://example.com")]),
+            )
+
+        vector_docs, web_docs = asyncio.run(run())
+        self.assertCountEqual(started, ["vector", "web"])
+        self.assertEqual([d.page_content for d in vector_docs], ["v1"])
+        self.assertEqual([d.page_content for d in web_docs], ["w1"])
+
+    def test_failing_source_yields_no_documents(self):
+        async def failing():
+            raise RuntimeError("search backend down")
+
+        async def vector():
+            return [doc("v1")]
+
+        vector_docs, web_docs = asyncio.run(gather_sources(vector, failing))
+        self.assertEqual(len(vector_docs), 1)
+        self.assertEqual(web_docs, [])
+
+
+class MergeDocumentsTest(unittest.TestCase):
+    def test_merges_in_priority_order(self):
+        merged = merge_documents([doc("v1"), doc("v2")], [doc("w1", "https://a")])
+        self.assertEqual([d.page_content for d in merged], ["v1", "v2", "w1"])
+
+    def test_drops_duplicates_by_source_then_content(self):
+        merged = merge_documents(
+            [doc("v1", "https://a"), doc("same")],
+            [doc("w1", "https://a"), doc("same"), doc("w2", "https://b")],
+        )
+        self.assertEqual([d.page_content for d in merged], ["v1", "same", "w2"])
+
+    def test_empty_sources(self):
+        self.assertEqual(merge_documents([], []), [])
+
+
+class DocumentSourcesTest(unittest.TestCase):
+    def test_distinguishes_knowledge_base_and_web(self):
+        documents = [
+            SimpleNamespace(page_content="a", metadata={"source": "hust.pdf", "collection": "universities"}),
+            SimpleNamespace(page_content="b", metadata={"source": "hust.pdf", "collection": "universities"}),
+            SimpleNamespace(page_content="c", metadata={
+                "source": "https://hust.edu.vn", "title": "HUST", "type": "web_search"}),
+        ]
+        self.assertEqual(document_sources(documents), [
+            {"type": "knowledge_base", "title": "", "uri": "hust.pdf", "collection": "universities"},
+            {"type": "web", "title": "HUST", "uri": "https://hust.edu.vn", "collection": ""},
+        ])
+
+    def test_no_documents(self):
+        self.assertEqual(document_sources([]), [])
+
+
+class FormatContextTest(unittest.TestCase):
+    def test_numbers_documents_under_their_citation(self):
+        documents = [
+            SimpleNamespace(page_content="Cutoff is 28.5", metadata={"source": "hust.pdf", "collection": "universities"}),
+            SimpleNamespace(page_content="No source", metadata={}),
+        ]
+        self.assertEqual(format_context(documents, "Source", "database", "Section"),
+                         "\n[Source 1 - hust.pdf (universities)]:\nCutoff is 28.5\n"
+                         "\n[Source 2 - database]:\nNo source\n")
+
+    def test_section_title_heads_the_chunk(self):
+        documents = [SimpleNamespace(page_content="Học phí 30 triệu", metadata={"source": "fees.pdf", "section": "Học phí"})]
+        self.assertEqual(format_context(documents, "Nguồn", "cơ sở dữ liệu", "Mục"),
+                         "\n[Nguồn 1 - fees.pdf]:\nMục: Học phí\nHọc phí 30 triệu\n")
+
+
+class CitesSourcesTest(unittest.TestCase):
+    documents = [doc("Cutoff is 28.5", "hust.pdf"), doc("No source")]
+
+    def test_cited_by_number_or_source(self):
+        self.assertTrue(cites_sources("The cutoff is 28.5 [Source 1 - hust.pdf].", self.documents))
+        self.assertTrue(cites_sources("Điểm chuẩn là 28.5 (nguồn 2).", self.documents))
+        self.assertTrue(cites_sources("According to hust.pdf the cutoff is 28.5.", self.documents))
+
+    def test_uncited(self):
+        self.assertFalse(cites_sources("The cutoff is 28.5.", self.documents))
+        # There is no third document
+        self.assertFalse(cites_sources("The cutoff is 28.5 [Source 3].", self.documents))
+        self.assertFalse(cites_sources("", []))
+
+
+class RetrieveFromCollectionsTest(unittest.TestCase):
+    def setUp(self):
+        self.collections = {
+            "university-scores": [(doc("s1", "https://s1"), 0.91), (doc("s2", "https://s2"), 0.42)],
+            "scholarships": [(doc("g1", "https://g1"), 0.87), (doc("g2", "https://g2"), 0.65)],
+        }
+
+    async def search(self, collection):
+        return self.collections[collection]
+
+    def retrieve(self, collections, top_k=10):
+        return asyncio.run(retrieve_from_collections(self.search, collections, top_k))
+
+    def test_merges_and_orders_by_score(self):
+        docs = self.retrieve(["university-scores", "scholarships"])
+        self.assertEqual([d.page_content for d in docs], ["s1", "g1", "g2", "s2"])
+        self.assertEqual(
+            [d.metadata["collection"] for d in docs],
+            ["university-scores", "scholarships", "scholarships", "university-scores"],
+        )
+
+    def test_caps_to_top_k(self):
+        docs = self.retrieve(["university-scores", "scholarships"], top_k=2)
+        self.assertEqual([d.page_content for d in docs], ["s1", "g1"])
+
+    def test_duplicates_keep_best_score(self):
+        self.collections["scholarships"].append((doc("s1 again", "https://s1"), 0.99))
+        docs = self.retrieve(["university-scores", "scholarships"])
+        self.assertEqual(docs[0].page_content, "s1 again")
+        self.assertEqual(docs[0].metadata["collection"], "scholarships")
+        self.assertEqual(docs[0].metadata["score"], 0.99)
+        self.assertEqual(len(docs), 4)
+
+    def test_failing_collection_is_skipped(self):
+        async def search(collection):
+            if collection == "scholarships":
+                raise RuntimeError("index not found")
+            return self.collections[collection]
+
+        docs = asyncio.run(
+            retrieve_from_collections(search, ["university-scores", "scholarships"], 10)
+        )
+        self.assertEqual([d.page_content for d in docs], ["s1", "s2"])
+
+
+class ScoreBoostTest(unittest.TestCase):
+    BOOSTS = [
+        {"field": "source_type", "equals": "official", "boost": 0.1},
+        {"field": "year", "decay_per_year": 0.01, "max_decay": 0.05},
+    ]
+
+    def setUp(self):
+        official = doc("Official 2021 benchmark scores", "https://moet.gov.vn/2021")
+        official.metadata.update(source_type="official", year=2021)
+        blog = doc("Blog post on 2025 benchmark scores", "https://blog.example/2025")
+        blog.metadata.update(source_type="blog", year=2025)
+        self.candidates = [(official, 0.80), (blog, 0.84)]
+
+    def retrieve(self, boosts):
+        async def search(collection):
+            return self.candidates
+        return asyncio.run(retrieve_from_collections(
+            search, ["university-scores"], 10, parse_score_boosts(boosts), current_year=2026))
+
+    def test_unboosted_ranking_follows_similarity(self):
+        docs = self.retrieve([])
+        self.assertEqual(["https://blog.example/2025", "https://moet.gov.vn/2021"],
+                         [d.metadata["source"] for d in docs])
+        self.assertEqual(docs[0].metadata["score"], docs[0].metadata["base_score"])
+
+    def test_official_older_doc_outranks_unofficial_newer_one(self):
+        docs = self.retrieve(self.BOOSTS)
+        self.assertEqual(["https://moet.gov.vn/2021", "https://blog.example/2025"],
+                         [d.metadata["source"] for d in docs])
+        official, blog = docs
+        # +0.1 for being official, -0.01 for each of its 5 years, capped at 0.05
+        self.assertAlmostEqual(0.85, official.metadata["score"])
+        self.assertEqual(0.80, official.metadata["base_score"])
+        # -0.01 for its one year
+        self.assertAlmostEqual(0.83, blog.metadata["score"])
+        self.assertEqual(0.84, blog.metadata["base_score"])
+
+        # ...unless recency weighs more
+        docs = self.retrieve([self.BOOSTS[0], {"field": "year", "decay_per_year": 0.05}])
+        self.assertEqual("https://blog.example/2025", docs[0].metadata["source"])
+
+    def test_documents_without_the_field_are_left_alone(self):
+        bare = doc("No metadata", "https://bare")
+        self.candidates.append((bare, 0.5))
+        docs = self.retrieve(self.BOOSTS)
+        self.assertEqual(0.5, docs[-1].metadata["score"])
+
+    def test_invalid_boosts_are_rejected(self):
+        for boosts, message in [
+            ({"field": "year"}, "must be a list"),
+            ([{"equals": "official"}], "needs a field"),
+            ([{"field": "year"}], "either equals and boost, or decay_per_year"),
+            ([{"field": "year", "equals": 2025, "decay_per_year": 0.1}], "either equals"),
+            ([{"field": "year", "decay_per_year": -0.1}], "negative"),
+            ([{"field": "year", "decay_per_year": "fast"}], "non-numeric"),
+            ([{"field": "year", "decay": 0.1}], "unknown keys: decay"),
+        ]:
+            with self.subTest(boosts=boosts), self.assertRaisesRegex(ValueError, message):
+                parse_score_boosts(boosts)
+
+
+class DedupeDocumentsTest(unittest.TestCase):
+    PASSAGE = (
+        "Hanoi University of Science and Technology admits students through "
+        "the national exam, with a 2023 benchmark score of 28.5 for computer science"
+    )
+
+    def test_exact_duplicates_keep_best_score(self):
+        docs = dedupe_documents(
+            [doc(self.PASSAGE, "https://a", 0.7), doc("other", "https://b", 0.6),
+             doc(self.PASSAGE, "https://c", 0.9)],
+            0.9,
+        )
+        self.assertEqual([d.metadata["source"] for d in docs], ["https://c", "https://b"])
+
+    def test_formatting_differences_are_duplicates(self):
+        copy = "  " + self.PASSAGE.upper().replace(" ", "\n") + "!"
+        docs = dedupe_documents([doc(self.PASSAGE, "https://a"), doc(copy, "https://b")], 1.0)
+        self.assertEqual([d.metadata["source"] for d in docs], ["https://a"])
+
+    def test_near_duplicates_above_threshold(self):
+        near = self.PASSAGE + " programs"
+        docs = [doc(self.PASSAGE, "https://a", 0.8), doc(near, "https://b", 0.85)]
+        self.assertEqual(
+            [d.metadata["source"] for d in dedupe_documents(docs, 0.9)], ["https://b"]
+        )
+        self.assertEqual(len(dedupe_documents(docs, 1.0)), 2)
+
+    def test_distinct_documents_are_kept_in_order(self):
+        docs = [
+            doc("Tuition at FPT University is about 28 million VND per term", "https://a"),
+            doc(self.PASSAGE, "https://b"),
+            doc("Scholarships cover up to 100% of tuition for top applicants", "https://c"),
+        ]
+        self.assertEqual(dedupe_documents(docs, 0.9), docs)
+
+
+class RetrievalLimitsTest(unittest.TestCase):
+    def test_oversamples_candidates(self):
+        self.assertEqual(retrieval_limits(5, 2.0, 50), (5, 10))
+        self.assertEqual(retrieval_limits(5, 1.5, 50), (5, 8))
+        self.assertEqual(retrieval_limits(5, 1.0, 50), (5, 5))
+
+    def test_caps_top_k_and_candidates(self):
+        self.assertEqual(retrieval_limits(30, 2.0, 50), (30, 50))
+        self.assertEqual(retrieval_limits(80, 2.0, 50), (50, 50))
+
+    def test_top_k_is_at_least_one(self):
+        self.assertEqual(retrieval_limits(0, 2.0, 50), (1, 2))
+
+
+class ResolveCollectionsTest(unittest.TestCase):
+    def test_empty_list_falls_back_to_default(self):
+        self.assertEqual(resolve_collections([], "default"), ["default"])
+        self.assertEqual(resolve_collections(["", "  "], "default"), ["default"])
+
+    def test_drops_blanks_and_repeats(self):
+        self.assertEqual(
+            resolve_collections(["a", " b ", "", "a"], "default"), ["a", "b"]
+        )
+
+
+class TruncateTextTest(unittest.TestCase):
+    TEXT = (
+        "HUST admits students through the national exam. "
+        "Its 2023 benchmark for computer science was 28.5! "
+        "Scholarships cover tuition for the top 5% of each intake."
+    )
+
+    def test_short_text_is_kept(self):
+        self.assertEqual(self.TEXT, truncate_text(self.TEXT, len(self.TEXT)))
+        self.assertEqual(self.TEXT, truncate_text(self.TEXT, 0))
+
+    def test_cut_after_last_whole_sentence(self):
+        text = truncate_text(self.TEXT, 110)
+        self.assertEqual(
+            "HUST admits students through the national exam. "
+            "Its 2023 benchmark for computer science was 28.5!",
+            text,
+        )
+        self.assertLessEqual(len(text), 110)
+
+    def test_decimal_point_is_not_a_sentence_end(self):
+        self.assertEqual("HUST admits students through the national exam.", truncate_text(self.TEXT, 95))
+
+    def test_long_first_sentence_is_cut_on_a_word(self):
+        self.assertEqual("HUST admits students", truncate_text(self.TEXT, 24))
+
+    def test_single_long_word_is_cut_hard(self):
+        self.assertEqual("x" * 10, truncate_text("x" * 50, 10))
+
+
+if __name__ == "__main__":
+    unittest.main()
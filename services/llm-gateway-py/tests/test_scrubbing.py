@@ -0,0 +1,138 @@
+"""Tests for redacting PII and blocklisted terms before ingestion."""
+
+import asyncio
+import os
+import sys
+import tempfile
+import unittest
+from types import SimpleNamespace
+from unittest import mock
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from test_ingestion import FakeSplitter
+from utils.ingest_jobs import IngestJobStore, JobDocument, run_job
+from utils.ingestion import ingest_document
+from utils.scrubbing import Scrubber
+
+DOCUMENT = (
+    "Liên hệ tư vấn tuyển sinh: tuyensinh.hcmus@gmail.com hoặc 0912 345 678. "
+    "Thí sinh Nguyễn Văn A, CCCD 079203001234, hộ chiếu B1234567, gọi +84 28 3835 0098. "
+    "Học phí năm 2024 là 150000000 đồng."
+)
+
+
+class ScrubberTest(unittest.TestCase):
+    def test_pii_is_redacted(self):
+        result = Scrubber().scrub(DOCUMENT)
+
+        for pii in ("tuyensinh.hcmus@gmail.com", "0912 345 678", "079203001234", "B1234567", "+84 28 3835 0098"):
+            self.assertNotIn(pii, result.text)
+        self.assertIn("tư vấn tuyển sinh: [EMAIL] hoặc [PHONE].", result.text)
+        self.assertIn("CCCD [ID], hộ chiếu [ID], gọi [PHONE].", result.text)
+        self.assertEqual(result.redactions, {"email": 1, "id": 2, "phone": 2})
+        self.assertTrue(result.redacted)
+
+    def test_other_numbers_are_kept(self):
+        result = Scrubber().scrub(DOCUMENT)
+        self.assertIn("Học phí năm 2024 là 150000000 đồng.", result.text)
+
+    def test_citizen_ids_need_a_word_naming_them(self):
+        for text, want in [
+            ("Số định danh cá nhân: 001099012345.", "Số định danh cá nhân: [ID]."),
+            ("CCCD số 079203001234", "CCCD số [ID]"),
+            ("Citizen ID no. 096187654321", "Citizen ID no. [ID]"),
+            # Application codes and other 12-digit numbers are kept
+            ("Mã hồ sơ 079203001234", "Mã hồ sơ 079203001234"),
+            ("Tổng 123456789012 lượt xem", "Tổng 123456789012 lượt xem"),
+            # Not a province code
+            ("CCCD 979203001234", "CCCD 979203001234"),
+        ]:
+            with self.subTest(text=text):
+                self.assertEqual(Scrubber().scrub(text).text, want)
+
+    def test_blocklist_matches_whole_words_in_any_case(self):
+        result = Scrubber(["nguyễn văn a", " ", "secret"]).scrub("NGUYỄN VĂN A shares secrets, not the secret.")
+        self.assertEqual(result.text, "[REDACTED] shares secrets, not the [REDACTED].")
+        self.assertEqual(result.redactions, {"blocklist": 2})
+
+    def test_clean_text_is_unchanged(self):
+        result = Scrubber(["secret"]).scrub("Ngành Khoa học máy tính")
+        self.assertEqual(result.text, "Ngành Khoa học máy tính")
+        self.assertFalse(result.redacted)
+
+
+class ScrubbedIngestionTest(unittest.TestCase):
+    def document(self):
+        return SimpleNamespace(page_content=DOCUMENT, metadata={"source": "tuyensinh.txt"})
+
+    def test_stored_chunks_are_redacted(self):
+        vector_store = mock.Mock()
+        result = asyncio.run(ingest_document(
+            FakeSplitter(80), vector_store, self.document(), "text-embedding-3-small",
+            document_id="hcmus", scrubber=Scrubber(["Nguyễn Văn A"]),
+        ))
+
+        self.assertEqual(result.failed_ids, [])
+        self.assertEqual(result.redactions, 6)
+        stored = vector_store.add_documents.call_args.args[0]
+        self.assertEqual(len(stored), len(result.succeeded_ids))
+        content = "".join(chunk.page_content for chunk in stored)
+        for pii in ("gmail.com", "0912", "079203001234", "B1234567", "3835", "Nguyễn Văn A"):
+            self.assertNotIn(pii, content)
+        self.assertIn("Học phí năm 2024", content)
+        for chunk in stored:
+            self.assertEqual(chunk.metadata, {"source": "tuyensinh.txt", "redacted": True})
+
+    def test_unscrubbed_documents_are_stored_as_is(self):
+        vector_store = mock.Mock()
+        result = asyncio.run(ingest_document(
+            FakeSplitter(80), vector_store, self.document(), "text-embedding-3-small",
+        ))
+
+        stored = vector_store.add_documents.call_args.args[0]
+        self.assertEqual("".join(chunk.page_content for chunk in stored), DOCUMENT)
+        self.assertEqual(stored[0].metadata, {"source": "tuyensinh.txt"})
+        self.assertEqual(result.redactions, 0)
+
+    def test_title_and_source_are_redacted(self):
+        document = SimpleNamespace(page_content="Ngành Khoa học máy tính", metadata={
+            "title": "Hồ sơ của Nguyễn Văn A", "source": "mailto:a.nguyen@gmail.com", "year": "2024",
+        })
+        result = Scrubber(["Nguyễn Văn A"]).scrub_document(document)
+
+        self.assertEqual(document.metadata, {
+            "title": "Hồ sơ của [REDACTED]", "source": "mailto:[EMAIL]", "year": "2024", "redacted": True,
+        })
+        self.assertEqual(result.redactions, {"email": 1, "blocklist": 1})
+        self.assertEqual(document.page_content, "Ngành Khoa học máy tính")
+
+    def test_jobs_created_with_scrub_are_redacted(self):
+        with tempfile.TemporaryDirectory() as directory:
+            store = IngestJobStore(directory)
+            job = store.create("kb", [JobDocument("hcmus", DOCUMENT, {"title": "CCCD 079203001234"})],
+                               scrub=True, scrubber=Scrubber())
+            # What was redacted never reaches the job file
+            with open(os.path.join(directory, f"{job.job_id}.json"), encoding="utf-8") as f:
+                saved = f.read()
+            for pii in ("gmail.com", "0912", "079203001234", "B1234567", "3835"):
+                self.assertNotIn(pii, saved)
+
+            vector_store = mock.Mock()
+            asyncio.run(run_job(store, store.get(job.job_id), FakeSplitter(80), vector_store))
+
+        stored = vector_store.add_documents.call_args.args[0]
+        content = "".join(chunk.page_content for chunk in stored)
+        self.assertNotIn("gmail.com", content)
+        self.assertIn("[EMAIL]", content)
+        for chunk in stored:
+            self.assertEqual(chunk.metadata, {"title": "CCCD [ID]", "redacted": True})
+
+    def test_jobs_need_a_scrubber_to_scrub(self):
+        with tempfile.TemporaryDirectory() as directory:
+            with self.assertRaises(ValueError):
+                IngestJobStore(directory).create("kb", [JobDocument("hcmus", DOCUMENT)], scrub=True)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,72 @@
+"""Tests for the gRPC server options.
+
+The message size tests run a real server and need grpc; they are skipped
+without it.
+"""
+
+import asyncio
+import os
+import sys
+import unittest
+from types import SimpleNamespace
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+try:
+    import grpc
+except ImportError:
+    grpc = None
+
+from utils.server_options import grpc_server_options
+
+
+def settings(max_receive=1024, max_send=1024):
+    return SimpleNamespace(
+        grpc_max_receive_message_bytes=max_receive,
+        grpc_max_send_message_bytes=max_send,
+        grpc_keepalive_time_seconds=60.0,
+        grpc_keepalive_timeout_seconds=20.0,
+        grpc_keepalive_min_ping_interval_seconds=10.0,
+        grpc_keepalive_permit_without_calls=True,
+    )
+
+
+class GrpcServerOptionsTest(unittest.TestCase):
+    def test_settings_become_channel_options(self):
+        options = dict(grpc_server_options(settings()))
+        self.assertEqual(1024, options["grpc.max_receive_message_length"])
+        self.assertEqual(1024, options["grpc.max_send_message_length"])
+        self.assertEqual(60000, options["grpc.keepalive_time_ms"])
+        self.assertEqual(20000, options["grpc.keepalive_timeout_ms"])
+        self.assertEqual(10000, options["grpc.http2.min_recv_ping_interval_without_data_ms"])
+        self.assertEqual(1, options["grpc.keepalive_permit_without_calls"])
+
+
+@unittest.skipIf(grpc is None, "grpc not installed")
+class MessageSizeTest(unittest.TestCase):
+    """Raw bytes echoed by a server with 1 KB message limits."""
+
+    async def call(self, size):
+        server = grpc.aio.server(options=grpc_server_options(settings()))
+
+        async def echo(request, context):
+            return request
+
+        server.add_generic_rpc_handlers([grpc.method_handlers_generic_handler(
+            "test.Echo", {"Echo": grpc.unary_unary_rpc_method_handler(echo)})])
+        port = server.add_insecure_port("127.0.0.1:0")
+        await server.start()
+        try:
+            # The client's own limits are well above the server's
+            async with grpc.aio.insecure_channel(f"127.0.0.1:{port}") as channel:
+                return await channel.unary_unary("/test.Echo/Echo")(b"x" * size, timeout=5)
+        finally:
+            await server.stop(None)
+
+    def test_message_under_the_limit_is_accepted(self):
+        self.assertEqual(1000, len(asyncio.run(self.call(1000))))
+
+    def test_message_over_the_limit_is_rejected(self):
+        with self.assertRaises(grpc.aio.AioRpcError) as cm:
+            asyncio.run(self.call(2000))
+        self.assertEqual(grpc.StatusCode.RESOURCE_EXHAUSTED, cm.exception.code())
@@ -0,0 +1,182 @@
+"""Tests for startup validation of the service settings."""
+
+import os
+import sys
+import tempfile
+import unittest
+from unittest import mock
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from config.settings import ConfigError, ServiceConfig
+
+
+def config(**env):
+    """ServiceConfig read from the default file and exactly env."""
+    with mock.patch.dict(os.environ, env, clear=True):
+        return ServiceConfig()
+
+
+class TestValidate(unittest.TestCase):
+    def test_complete_settings_pass(self):
+        cfg = config(OPENAI_API_KEY="sk-test", PINECONE_API_KEY="pc-test", TAVILY_API_KEY="tv-test",
+                     ADMIN_API_KEY="s3cret")
+        cfg.validate()
+        self.assertEqual([], cfg.warnings())
+
+    def test_reports_every_problem_at_once(self):
+        cfg = config(GRPC_PORT="0", RAG_CHUNK_SIZE="big", RAG_TEMPERATURE="warm", RAG_TOP_P="1.5")
+        with self.assertRaises(ConfigError) as cm:
+            cfg.validate()
+        self.assertEqual([
+            "RAG_CHUNK_SIZE must be an integer, got 'big'",
+            "RAG_TEMPERATURE must be a number, got 'warm'",
+            "OPENAI_API_KEY is required unless LLM_TEST_MODE is on",
+            "grpc_port must be between 1 and 65535, got 0",
+            "rag.top_p must be between 0 and 1",
+        ], cm.exception.errors)
+        self.assertIn("RAG_CHUNK_SIZE must be an integer", str(cm.exception))
+
+    def test_grpc_limits_must_be_positive(self):
+        with self.assertRaises(ConfigError) as cm:
+            config(LLM_TEST_MODE="true", GRPC_MAX_RECEIVE_MESSAGE_BYTES="0",
+                   GRPC_KEEPALIVE_TIME_SECONDS="-1").validate()
+        self.assertEqual([
+            "grpc_max_receive_message_bytes must be positive",
+            "grpc_keepalive_time_seconds must be positive",
+        ], cm.exception.errors)
+
+    def test_unparseable_values_keep_their_defaults(self):
+        cfg = config(MAX_WORKERS="many")
+        self.assertEqual(ServiceConfig.max_workers, cfg.max_workers)
+
+    def test_legacy_top_k_name_is_named_in_errors(self):
+        with self.assertRaisesRegex(ConfigError, "RAG_TOP_K must be an integer"):
+            config(OPENAI_API_KEY="sk-test", RAG_TOP_K="five").validate()
+
+    def test_retrieval_boosts_from_the_environment(self):
+        cfg = config(OPENAI_API_KEY="sk-test",
+                     RAG_RETRIEVAL_BOOSTS='[{"field": "source_type", "equals": "official", "boost": 0.1}]')
+        cfg.validate()
+        self.assertEqual([{"field": "source_type", "equals": "official", "boost": 0.1}], cfg.rag.retrieval_boosts)
+
+        with self.assertRaisesRegex(ConfigError, "RAG_RETRIEVAL_BOOSTS must be a JSON list"):
+            config(OPENAI_API_KEY="sk-test", RAG_RETRIEVAL_BOOSTS="official+0.1").validate()
+        with self.assertRaisesRegex(ConfigError, "rag.retrieval_boosts: retrieval boost 0 needs either"):
+            config(OPENAI_API_KEY="sk-test", RAG_RETRIEVAL_BOOSTS='[{"field": "year"}]').validate()
+
+    def test_stop_sequences_from_the_environment(self):
+        cfg = config(OPENAI_API_KEY="sk-test", RAG_STOP_SEQUENCES='["\\n\\nReferences:", "###"]')
+        cfg.validate()
+        self.assertEqual(["\n\nReferences:", "###"], cfg.rag.stop_sequences)
+        with self.assertRaisesRegex(ConfigError, "RAG_STOP_SEQUENCES must be a JSON list"):
+            config(OPENAI_API_KEY="sk-test", RAG_STOP_SEQUENCES="###").validate()
+        with self.assertRaisesRegex(ConfigError, "rag.stop_sequences must be a list of non-empty strings"):
+            config(OPENAI_API_KEY="sk-test", RAG_STOP_SEQUENCES='["###", ""]').validate()
+
+    def test_verbosity_token_limits_from_the_environment(self):
+        cfg = config(OPENAI_API_KEY="sk-test", RAG_SHORT_MAX_TOKENS="150", RAG_DETAILED_MAX_TOKENS="3000")
+        cfg.validate()
+        self.assertEqual((150, 3000), (cfg.rag.short_max_tokens, cfg.rag.detailed_max_tokens))
+        with self.assertRaisesRegex(ConfigError, "rag.short_max_tokens must be at least 1"):
+            config(OPENAI_API_KEY="sk-test", RAG_SHORT_MAX_TOKENS="0").validate()
+
+    def test_onboarding_mode_from_the_environment(self):
+        self.assertFalse(config(OPENAI_API_KEY="sk-test").rag.onboarding_mode)
+        cfg = config(OPENAI_API_KEY="sk-test", RAG_ONBOARDING_MODE="true", KNOWLEDGE_BASE_CHECK_TTL_SECONDS="5")
+        cfg.validate()
+        self.assertEqual((True, 5.0), (cfg.rag.onboarding_mode, cfg.rag.knowledge_base_check_ttl_seconds))
+        with self.assertRaisesRegex(ConfigError, "rag.knowledge_base_check_ttl_seconds must not be negative"):
+            config(OPENAI_API_KEY="sk-test", KNOWLEDGE_BASE_CHECK_TTL_SECONDS="-1").validate()
+
+    def test_known_indexes_from_the_environment(self):
+        cfg = config(OPENAI_API_KEY="sk-test", PINECONE_KNOWN_INDEXES="faq, careers,,")
+        cfg.validate()
+        self.assertEqual(["faq", "careers"], cfg.vector_store.known_indexes)
+        self.assertEqual([], config(OPENAI_API_KEY="sk-test").vector_store.known_indexes)
+
+    def test_vector_store_backend(self):
+        self.assertEqual("pinecone", config(OPENAI_API_KEY="sk-test").vector_store.backend)
+        config(OPENAI_API_KEY="sk-test", VECTOR_STORE_BACKEND="memory").validate()
+        with self.assertRaisesRegex(ConfigError, "vector_store.backend must be one of pinecone, memory"):
+            config(OPENAI_API_KEY="sk-test", VECTOR_STORE_BACKEND="qdrant").validate()
+
+    def test_test_mode_needs_no_api_keys(self):
+        cfg = config(LLM_TEST_MODE="true")
+        cfg.validate()
+        self.assertEqual([], cfg.warnings())
+
+    def test_default_admin_key_is_rejected_in_production(self):
+        with self.assertRaisesRegex(ConfigError, "ADMIN_API_KEY must be changed"):
+            config(OPENAI_API_KEY="sk-test", ENVIRONMENT="production").validate()
+        config(OPENAI_API_KEY="sk-test", ENVIRONMENT="production", ENABLE_ADMIN_API="false").validate()
+
+
+    def test_guardrails_prompt_from_the_environment(self):
+        self.assertIn("medical, legal or financial advice", config(OPENAI_API_KEY="sk-test").rag.guardrails_prompt)
+        cfg = config(OPENAI_API_KEY="sk-test", RAG_GUARDRAILS_PROMPT="Only discuss careers.")
+        self.assertEqual("Only discuss careers.", cfg.rag.guardrails_prompt)
+
+    def test_citation_requirement_from_the_environment(self):
+        self.assertFalse(config(OPENAI_API_KEY="sk-test").rag.require_citations)
+        self.assertTrue(config(OPENAI_API_KEY="sk-test", RAG_REQUIRE_CITATIONS="true").rag.require_citations)
+
+
+class TestPrecedence(unittest.TestCase):
+    def setUp(self):
+        with tempfile.NamedTemporaryFile("w", suffix=".yaml", delete=False) as f:
+            f.write("service:\n"
+                    "  log_level: WARNING\n"
+                    "rag:\n"
+                    "  retrieval_top_k: 7\n"
+                    "vector_store:\n"
+                    "  default_index: from-file\n"
+                    "  embedding_model: text-embedding-3-large\n")
+        self.path = f.name
+        self.addCleanup(os.remove, self.path)
+
+    def test_file_values_override_defaults(self):
+        cfg = config(CONFIG_FILE=self.path)
+        self.assertEqual("WARNING", cfg.log_level)
+        self.assertEqual(7, cfg.rag.retrieval_top_k)
+        self.assertEqual("from-file", cfg.vector_store.default_index)
+        self.assertEqual(3072, cfg.vector_store.embedding_dimensions)
+
+    def test_environment_overrides_the_file(self):
+        cfg = config(CONFIG_FILE=self.path, LOG_LEVEL="DEBUG", RAG_RETRIEVAL_TOP_K="3",
+                     PINECONE_INDEX_NAME="from-env", EMBEDDING_MODEL="text-embedding-3-small")
+        self.assertEqual("DEBUG", cfg.log_level)
+        self.assertEqual(3, cfg.rag.retrieval_top_k)
+        self.assertEqual("from-env", cfg.vector_store.default_index)
+        self.assertEqual(1536, cfg.vector_store.embedding_dimensions)
+
+
+class TestWarnings(unittest.TestCase):
+    def test_missing_optional_settings_are_warned_about(self):
+        cfg = config(OPENAI_API_KEY="sk-test", WEB_SEARCH_ENABLED="true")
+        cfg.validate()
+        self.assertEqual([
+            "PINECONE_API_KEY is not set: vector search is disabled",
+            "TAVILY_API_KEY is not set: web search is disabled",
+            "ADMIN_API_KEY is the default: set it before exposing the admin API",
+        ], cfg.warnings())
+
+    def test_empty_guardrails_are_warned_about(self):
+        cfg = config(OPENAI_API_KEY="sk-test", PINECONE_API_KEY="pc-test", TAVILY_API_KEY="tv-test",
+                     ADMIN_API_KEY="s3cret", RAG_GUARDRAILS_PROMPT="")
+        self.assertEqual(["RAG_GUARDRAILS_PROMPT is empty: generations run without safety rules"], cfg.warnings())
+
+    def test_no_web_search_warning_when_it_is_off(self):
+        cfg = config(OPENAI_API_KEY="sk-test", PINECONE_API_KEY="pc-test", ADMIN_API_KEY="s3cret",
+                     WEB_SEARCH_ENABLED="false")
+        self.assertEqual([], cfg.warnings())
+
+
+    def test_memory_backend_needs_no_pinecone_key(self):
+        cfg = config(OPENAI_API_KEY="sk-test", ADMIN_API_KEY="s3cret", WEB_SEARCH_ENABLED="false",
+                     VECTOR_STORE_BACKEND="memory")
+        self.assertEqual([], cfg.warnings())
+
+
+if __name__ == "__main__":
+    unittest.main()
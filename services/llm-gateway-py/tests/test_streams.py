@@ -0,0 +1,69 @@
+"""Tests for in-flight stream tracking and shutdown."""
+
+import asyncio
+import os
+import sys
+import unittest
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.streams import StreamRegistry
+
+
+class StreamRegistryTest(unittest.TestCase):
+    def test_shutdown_cancels_in_flight_stream(self):
+        async def run():
+            registry = StreamRegistry()
+            started = asyncio.Event()
+            outcome = {}
+
+            async def generate():
+                with registry.track():
+                    started.set()
+                    try:
+                        await asyncio.sleep(120)
+                        outcome["finished"] = True
+                    except asyncio.CancelledError:
+                        outcome["cancelled"] = True
+                        raise
+
+            task = asyncio.create_task(generate())
+            await started.wait()
+            self.assertEqual(registry.active, 1)
+
+            cancelled = await registry.shutdown(grace=0.01)
+            return task, cancelled, outcome, registry
+
+        task, cancelled, outcome, registry = asyncio.run(run())
+        self.assertEqual(cancelled, 1)
+        self.assertTrue(task.cancelled())
+        self.assertEqual(outcome, {"cancelled": True})
+        self.assertEqual(registry.active, 0)
+        self.assertTrue(registry.closing)
+
+    def test_shutdown_lets_streams_finish_within_grace(self):
+        async def run():
+            registry = StreamRegistry()
+
+            async def generate():
+                with registry.track():
+                    await asyncio.sleep(0.01)
+                    return "done"
+
+            task = asyncio.create_task(generate())
+            await asyncio.sleep(0)
+            cancelled = await registry.shutdown(grace=1)
+            return task, cancelled
+
+        task, cancelled = asyncio.run(run())
+        self.assertEqual(cancelled, 0)
+        self.assertEqual(task.result(), "done")
+
+    def test_shutdown_with_no_streams(self):
+        registry = StreamRegistry()
+        self.assertEqual(asyncio.run(registry.shutdown(grace=1)), 0)
+        self.assertTrue(registry.closing)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,191 @@
+"""Tests for structured output: tool calls, the text fallback, and schemas."""
+
+import asyncio
+import json
+import os
+import sys
+import unittest
+from types import SimpleNamespace
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.fakes import FakeChatModel
+from utils.structured import (
+    METHOD_TEXT,
+    METHOD_TOOL_CALL,
+    StructuredOutputError,
+    generate_structured,
+    parse_text_output,
+    tool_call_arguments,
+)
+from utils.usage import UsageMeter
+
+try:
+    from utils.schemas import SCHEMAS, CareerSuggestion, CareerSuggestions
+except ImportError:
+    SCHEMAS = None
+
+
+class Suggestions:
+    """Stands in for a pydantic schema with a single list field."""
+
+    model_fields = {"suggestions": None}
+
+    def __init__(self, suggestions):
+        self.suggestions = suggestions
+
+    @classmethod
+    def model_validate(cls, data):
+        if not isinstance(data, dict) or not isinstance(data.get("suggestions"), list):
+            raise ValueError("suggestions must be a list")
+        if not all(isinstance(s, dict) and s.get("career_field") for s in data["suggestions"]):
+            raise ValueError("every suggestion needs a career_field")
+        return cls(data["suggestions"])
+
+    @staticmethod
+    def model_json_schema():
+        return {"title": "Suggestions", "type": "object"}
+
+
+PAYLOAD = {"suggestions": [
+    {"career_field": "Data Science", "match_percent": 88, "rationale": "Strong logic.", "domain_codes": ["LOGIC"]},
+    {"career_field": "Journalism", "match_percent": 61, "rationale": "Good with words.", "domain_codes": ["LANG"]},
+]}
+
+
+class ToolCallingModel:
+    """Chat model that answers by calling the tool it was bound to."""
+
+    def __init__(self, args, raw=False):
+        self.args = args
+        self.raw = raw
+        self.tools = None
+        self.tool_choice = None
+        self.prompts = []
+
+    def bind_tools(self, tools, tool_choice=None):
+        self.tools = tools
+        self.tool_choice = tool_choice
+        return self
+
+    async def ainvoke(self, prompt, **kwargs):
+        self.prompts.append(prompt)
+        usage = {"input_tokens": 40, "output_tokens": 25}
+        if self.raw:
+            call = {"id": "call-1", "function": {"name": self.tool_choice, "arguments": json.dumps(self.args)}}
+            return SimpleNamespace(content="", additional_kwargs={"tool_calls": [call]}, usage_metadata=usage)
+        call = {"name": self.tool_choice, "args": self.args, "id": "call-1"}
+        return SimpleNamespace(content="", tool_calls=[call], usage_metadata=usage)
+
+
+class NoToolsModel(FakeChatModel):
+    """FakeChatModel whose provider has no tool calling."""
+
+    def bind_tools(self, tools, tool_choice=None):
+        raise NotImplementedError
+
+
+def generate(model, schema=Suggestions, meter=None):
+    return asyncio.run(generate_structured(model, "Suggest careers for a LOGIC-heavy profile", schema, meter))
+
+
+class TestGenerateStructured(unittest.TestCase):
+    def test_tool_call_payload_is_validated(self):
+        model = ToolCallingModel(PAYLOAD)
+        meter = UsageMeter()
+        output, method = generate(model, meter=meter)
+        self.assertEqual(METHOD_TOOL_CALL, method)
+        self.assertEqual(["Data Science", "Journalism"], [s["career_field"] for s in output.suggestions])
+        self.assertEqual([Suggestions], model.tools)
+        self.assertEqual("Suggestions", model.tool_choice)
+        self.assertEqual((40, 25), (meter.prompt_tokens, meter.completion_tokens))
+
+    def test_raw_tool_call_arguments_are_decoded(self):
+        output, method = generate(ToolCallingModel(PAYLOAD, raw=True))
+        self.assertEqual(METHOD_TOOL_CALL, method)
+        self.assertEqual(2, len(output.suggestions))
+
+    def test_invalid_tool_call_payload_is_rejected(self):
+        with self.assertRaises(StructuredOutputError):
+            generate(ToolCallingModel({"suggestions": [{"match_percent": 50}]}))
+
+    def test_model_without_tools_is_asked_for_json(self):
+        model = FakeChatModel(responses=["Sure! Here they are:\n```json\n" + json.dumps(PAYLOAD) + "\n```\nGood luck!"])
+        output, method = generate(model)
+        self.assertEqual(METHOD_TEXT, method)
+        self.assertEqual(2, len(output.suggestions))
+        self.assertIn("JSON schema", model.prompts[-1])
+
+    def test_provider_refusing_tools_falls_back_to_text(self):
+        output, method = generate(NoToolsModel(responses=[json.dumps(PAYLOAD)]))
+        self.assertEqual(METHOD_TEXT, method)
+        self.assertEqual("Journalism", output.suggestions[1]["career_field"])
+
+    def test_reply_without_json_is_rejected(self):
+        with self.assertRaises(StructuredOutputError):
+            generate(FakeChatModel(responses=["Data Science would suit you."]))
+
+
+class TestParseTextOutput(unittest.TestCase):
+    def test_json_among_prose(self):
+        text = "Based on [Source 1] I suggest " + json.dumps(PAYLOAD) + " as a start."
+        self.assertEqual(2, len(parse_text_output(Suggestions, text).suggestions))
+
+    def test_bare_array_fills_the_only_field(self):
+        output = parse_text_output(Suggestions, json.dumps(PAYLOAD["suggestions"]))
+        self.assertEqual("Data Science", output.suggestions[0]["career_field"])
+
+    def test_malformed_raw_arguments(self):
+        message = SimpleNamespace(additional_kwargs={"tool_calls": [{"function": {"name": "Suggestions", "arguments": "{oops"}}]})
+        with self.assertRaises(StructuredOutputError):
+            tool_call_arguments(message, "Suggestions")
+
+    def test_call_to_another_tool_is_ignored(self):
+        message = SimpleNamespace(tool_calls=[{"name": "Other", "args": PAYLOAD}])
+        self.assertIsNone(tool_call_arguments(message, "Suggestions"))
+
+
+@unittest.skipIf(SCHEMAS is None, "pydantic not installed")
+class TestSchemas(unittest.TestCase):
+    def test_tool_call_is_parsed_into_typed_suggestions(self):
+        output, method = generate(ToolCallingModel(PAYLOAD), SCHEMAS["career_suggestions"])
+        self.assertEqual(METHOD_TOOL_CALL, method)
+        self.assertIsInstance(output, CareerSuggestions)
+        self.assertIsInstance(output.suggestions[0], CareerSuggestion)
+        self.assertEqual(88, output.suggestions[0].match_percent)
+        self.assertEqual(["LANG"], output.suggestions[1].domain_codes)
+
+    def test_out_of_range_match_is_rejected(self):
+        payload = {"suggestions": [{"career_field": "Law", "match_percent": 140, "rationale": "?"}]}
+        with self.assertRaises(StructuredOutputError):
+            generate(ToolCallingModel(payload), SCHEMAS["career_suggestions"])
+
+    def test_ilo_analysis_from_text(self):
+        analysis = {
+            "overview": "A logical thinker.",
+            "strengths": ["Solves puzzles quickly"],
+            "development_areas": ["Public speaking"],
+            "careers": PAYLOAD["suggestions"],
+            "next_steps": ["Join the robotics club"],
+        }
+        output, method = generate(FakeChatModel(responses=[json.dumps(analysis)]), SCHEMAS["ilo_analysis"])
+        self.assertEqual(METHOD_TEXT, method)
+        self.assertEqual("Data Science", output.careers[0].career_field)
+
+    def test_follow_up_questions(self):
+        payload = {"questions": ["What are HUST's tuition fees?", "Which majors need math?"]}
+        output, _ = generate(ToolCallingModel(payload), SCHEMAS["follow_up_questions"])
+        self.assertEqual(payload["questions"], output.questions)
+        with self.assertRaises(StructuredOutputError):
+            generate(ToolCallingModel({"questions": []}), SCHEMAS["follow_up_questions"])
+
+    def test_conversation_summary(self):
+        payload = {"summary": "Học sinh thích lập trình, hỏi về điểm chuẩn của HUST."}
+        output, _ = generate(ToolCallingModel(payload), SCHEMAS["conversation_summary"])
+        self.assertEqual(payload["summary"], output.summary)
+        with self.assertRaises(StructuredOutputError):
+            generate(ToolCallingModel({"summary": ""}), SCHEMAS["conversation_summary"])
+
+
+if __name__ == "__main__":
+    unittest.main()
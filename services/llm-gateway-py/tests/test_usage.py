@@ -0,0 +1,88 @@
+"""Tests for per-user token usage and monthly quotas."""
+
+import os
+import sys
+import tempfile
+import unittest
+from datetime import datetime, timedelta, timezone
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.usage import QuotaExceeded, UsageMeter, UsageStore, current_period
+
+
+class UsageStoreTest(unittest.TestCase):
+    def setUp(self):
+        tmp = tempfile.TemporaryDirectory()
+        self.addCleanup(tmp.cleanup)
+        self.directory = tmp.name
+        self.store = UsageStore(self.directory)
+
+    def test_requests_accumulate(self):
+        self.store.record("u1", prompt_tokens=100, completion_tokens=40)
+        usage = self.store.record("u1", prompt_tokens=50, completion_tokens=10)
+
+        self.assertEqual((150, 50, 200, 2), (usage.prompt_tokens, usage.completion_tokens, usage.total_tokens, usage.requests))
+        self.assertEqual(usage, self.store.get("u1"))
+
+    def test_users_are_counted_separately(self):
+        self.store.record("u1", prompt_tokens=100, completion_tokens=40)
+        self.assertEqual(0, self.store.get("u2").total_tokens)
+
+    def test_months_are_counted_separately(self):
+        self.store.record("u1", prompt_tokens=100, completion_tokens=40, period="2026-01")
+        self.store.record("u1", prompt_tokens=5, completion_tokens=5, period="2026-02")
+
+        self.assertEqual(140, self.store.get("u1", "2026-01").total_tokens)
+        self.assertEqual(10, self.store.get("u1", "2026-02").total_tokens)
+        self.assertEqual(0, self.store.get("u1", "2026-03").requests)
+
+    def test_usage_persists_across_stores(self):
+        self.store.record("u1", prompt_tokens=100, completion_tokens=40)
+        self.assertEqual(140, UsageStore(self.directory).get("u1").total_tokens)
+
+    def test_user_ids_are_not_used_as_file_names(self):
+        self.store.record("../escape", prompt_tokens=1, completion_tokens=1)
+        self.assertEqual(1, len(os.listdir(self.directory)))
+        self.assertEqual(2, self.store.get("../escape").total_tokens)
+
+    def test_quota(self):
+        self.store.record("u1", prompt_tokens=60, completion_tokens=39)
+        self.assertEqual(99, self.store.check_quota("u1", 100).total_tokens)
+
+        self.store.record("u1", prompt_tokens=0, completion_tokens=1)
+        with self.assertRaises(QuotaExceeded) as cm:
+            self.store.check_quota("u1", 100)
+        self.assertEqual(100, cm.exception.usage.total_tokens)
+        self.assertIn("100", str(cm.exception))
+
+        # 0 is unlimited
+        self.assertEqual(100, self.store.check_quota("u1", 0).total_tokens)
+
+    def test_quota_resets_next_month(self):
+        last_month = current_period(datetime.now(timezone.utc) - timedelta(days=32))
+        self.store.record("u1", prompt_tokens=500, completion_tokens=500, period=last_month)
+        self.assertEqual(0, self.store.check_quota("u1", 100).total_tokens)
+
+
+class UsageMeterTest(unittest.TestCase):
+    def test_reported_usage_is_preferred(self):
+        meter = UsageMeter()
+        meter.add("a" * 400, "b" * 40, {"input_tokens": 90, "output_tokens": 12, "total_tokens": 102})
+        self.assertEqual((90, 12), (meter.prompt_tokens, meter.completion_tokens))
+
+    def test_usage_is_estimated_without_metadata(self):
+        meter = UsageMeter()
+        meter.add("a" * 400, "b" * 40)
+        meter.add("a" * 400, "")  # a model that failed before answering
+        self.assertEqual((200, 10), (meter.prompt_tokens, meter.completion_tokens))
+
+
+class CurrentPeriodTest(unittest.TestCase):
+    def test_period_is_utc_month(self):
+        hanoi = timezone(timedelta(hours=7))
+        self.assertEqual("2026-02", current_period(datetime(2026, 3, 1, 3, 0, tzinfo=hanoi)))
+
+
+if __name__ == "__main__":
+    unittest.main()
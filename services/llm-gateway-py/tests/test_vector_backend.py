@@ -0,0 +1,277 @@
+"""Tests for the vector store backends, the store cache and concurrent use
+of the fake client."""
+
+import asyncio
+import os
+import sys
+import threading
+import time
+import unittest
+from concurrent.futures import ThreadPoolExecutor
+from types import SimpleNamespace
+
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from utils.fakes import FakeEmbeddings, FakePineconeClient, fake_vector_store_factory
+from utils.memory_store import MemoryVectorStore
+from utils.pinecone_store import PineconeStore
+from utils.provisioning import STATUS_PROVISIONING, STATUS_READY
+from utils.retrieval import retrieve_from_collections
+from utils.vector_backend import CollectionHandle, VectorStoreCache
+
+
+def doc(content, source):
+    return SimpleNamespace(page_content=content, metadata={"source": source})
+
+
+class VectorStoreContract:
+    """Behaviour every VectorStore shares; subclasses set self.store to an
+    empty store with 16-dimensional vectors."""
+
+    def test_collections_are_created_described_and_deleted(self):
+        self.store.create_collection("faq", 16)
+        info = self.store.describe_collection("faq")
+        self.assertEqual(("faq", 16, "cosine", STATUS_READY), (info.name, info.dimension, info.metric, info.status))
+        self.assertEqual(["faq"], [c.name for c in self.store.list_collections()])
+
+        self.store.delete("faq")
+        self.assertEqual([], self.store.list_collections())
+        with self.assertRaises(Exception):
+            self.store.describe_collection("faq")
+
+    def test_query_ranks_by_similarity(self):
+        self.store.create_collection("faq", 16)
+        self.store.add_documents("faq", [
+            doc("tuition fees at HUST", "fees.pdf"),
+            doc("admission cutoff scores", "cutoffs.pdf"),
+            doc("dormitory rooms", "dorms.pdf"),
+        ])
+        results = self.store.query("faq", "admission cutoff", 2)
+        self.assertEqual(2, len(results))
+        self.assertEqual("cutoffs.pdf", results[0][0].metadata["source"])
+        self.assertGreater(results[0][1], results[1][1])
+
+    def test_documents_are_listed_a_page_at_a_time(self):
+        self.store.create_collection("faq", 16)
+        self.store.add_documents("faq", [doc(f"answer {i}", f"{i}.pdf") for i in range(5)],
+                                 ids=[f"d{i}" for i in range(5)])
+        listed, cursor = [], ""
+        while True:
+            page, cursor = self.store.list_documents("faq", 2, cursor)
+            self.assertLessEqual(len(page), 2)
+            listed.extend(page)
+            if not cursor:
+                break
+        self.assertEqual([f"d{i}" for i in range(5)], sorted(doc_id for doc_id, _ in listed))
+        contents = {doc_id: (d.page_content, d.metadata) for doc_id, d in listed}
+        self.assertEqual(("answer 3", {"source": "3.pdf"}), contents["d3"])
+
+    def test_ids_replace_documents_and_clear_keeps_the_collection(self):
+        self.store.create_collection("faq", 16)
+        ids = self.store.add_documents("faq", [doc("first", "a"), doc("second", "b")], ids=["d1", "d2"])
+        self.assertEqual(["d1", "d2"], ids)
+        self.store.add_documents("faq", [doc("first again", "a")], ids=["d1"])
+        self.assertEqual(2, self.store.stats("faq").document_count)
+
+        self.store.clear("faq")
+        self.assertEqual(0, self.store.stats("faq").document_count)
+        self.assertEqual([], self.store.query("faq", "first", 5))
+        self.assertEqual("faq", self.store.describe_collection("faq").name)
+
+    def test_visible_ids_are_the_stored_ones(self):
+        self.store.create_collection("faq", 16)
+        self.store.add_documents("faq", [doc("first", "a"), doc("second", "b")], ids=["d1", "d2"])
+        self.assertEqual(["d2", "d1"], self.store.visible_ids("faq", ["d2", "d3", "d1"]))
+
+
+class MemoryVectorStoreTest(VectorStoreContract, unittest.TestCase):
+    def setUp(self):
+        self.store = MemoryVectorStore(FakeEmbeddings(16), 16)
+
+    def test_initial_collections_exist(self):
+        store = MemoryVectorStore(FakeEmbeddings(16), 16, ["default"])
+        self.assertEqual(["default"], [c.name for c in store.list_collections()])
+        store.connect("default")
+
+    def test_missing_collection_is_an_error(self):
+        with self.assertRaises(KeyError):
+            self.store.add_documents("missing", [doc("text", "a")])
+        with self.assertRaises(KeyError):
+            self.store.connect("missing")
+
+    def test_vectors_of_another_size_are_rejected(self):
+        self.store.create_collection("wide", 32)
+        with self.assertRaisesRegex(ValueError, "16 dimensions"):
+            self.store.add_documents("wide", [doc("text", "a")])
+
+
+class PineconeStoreTest(VectorStoreContract, unittest.TestCase):
+    def setUp(self):
+        self.client = FakePineconeClient(16)
+        self.store = PineconeStore(self.client, FakeEmbeddings(16), fake_vector_store_factory, spec="spec")
+
+    def test_provisioning_index_is_reported(self):
+        self.store.create_collection("new", 16)
+        self.client.Index(name="new").ready = False
+        self.assertEqual(STATUS_PROVISIONING, self.store.describe_collection("new").status)
+
+    def test_documents_are_visible_once_indexed(self):
+        self.store.create_collection("faq", 16)
+        self.client.Index(name="faq").fetch_lag = 1
+        self.store.add_documents("faq", [doc("first", "a")], ids=["d1"])
+        self.assertEqual([], self.store.visible_ids("faq", ["d1"]))
+        self.assertEqual(["d1"], self.store.visible_ids("faq", ["d1"]))
+
+    def test_recreated_index_is_opened_again(self):
+        self.store.create_collection("faq", 16)
+        self.store.add_documents("faq", [doc("old", "a")])
+        self.store.delete("faq")
+        self.store.create_collection("faq", 16)
+        self.assertEqual([], self.store.query("faq", "old", 5))
+
+
+class RetrievalTest(unittest.TestCase):
+    """The RAG retrieval path over collection handles on the in-memory store."""
+
+    def setUp(self):
+        self.store = MemoryVectorStore(FakeEmbeddings(64, seed=3), 64, ["universities", "scholarships"])
+        CollectionHandle(self.store, "universities").add_documents([
+            doc("HUST admission cutoff for IT1 is 28.5", "hust.pdf"),
+            doc("NEU economics cutoff is 27", "neu.pdf"),
+        ])
+        CollectionHandle(self.store, "scholarships").add_documents([
+            doc("HUST offers merit scholarships for cutoff toppers", "hust-scholarships.pdf"),
+            doc("Dormitory fees are paid each semester", "dorms.pdf"),
+        ])
+
+    def retrieve(self, query, collections, top_k):
+        async def search(collection):
+            return CollectionHandle(self.store, collection).similarity_search_with_score(query, k=top_k)
+        return asyncio.run(retrieve_from_collections(search, collections, top_k))
+
+    def test_retrieves_across_collections(self):
+        docs = self.retrieve("HUST admission cutoff", ["universities", "scholarships"], 2)
+        self.assertEqual(["hust.pdf", "hust-scholarships.pdf"], [d.metadata["source"] for d in docs])
+        self.assertEqual(["universities", "scholarships"], [d.metadata["collection"] for d in docs])
+        self.assertGreaterEqual(docs[0].metadata["score"], docs[1].metadata["score"])
+
+    def test_missing_collection_contributes_nothing(self):
+        docs = self.retrieve("HUST admission cutoff", ["universities", "missing"], 5)
+        self.assertEqual({"universities"}, {d.metadata["collection"] for d in docs})
+
+
+class VectorStoreCacheTest(unittest.TestCase):
+    def setUp(self):
+        self.cache = VectorStoreCache()
+        self.pinecone = FakePineconeClient(8)
+        self.opened = []
+        self.opened_lock = threading.Lock()
+
+    def opener(self, collection):
+        def open_store():
+            time.sleep(0.001)  # widen the window for racing opens
+            with self.opened_lock:
+                self.opened.append(collection)
+            return fake_vector_store_factory(self.pinecone.Index(name=collection), FakeEmbeddings(8))
+        return open_store
+
+    def test_concurrent_gets_open_each_collection_once(self):
+        collections = [f"c{i % 4}" for i in range(64)]
+        with ThreadPoolExecutor(max_workers=16) as pool:
+            stores = list(pool.map(lambda c: self.cache.get(c, self.opener(c)), collections))
+
+        self.assertEqual(["c0", "c1", "c2", "c3"], sorted(self.opened))
+        for collection, store in zip(collections, stores):
+            self.assertIs(self.cache.get(collection, self.opener(collection)), store)
+
+    def test_evicted_collection_is_opened_again(self):
+        first = self.cache.get("c0", self.opener("c0"))
+        self.cache.evict("c0")
+        self.cache.evict("never-opened")
+        second = self.cache.get("c0", self.opener("c0"))
+        self.assertIsNot(first, second)
+        self.assertEqual(["c0", "c0"], self.opened)
+
+    def test_slow_open_does_not_hold_up_other_collections(self):
+        opening = threading.Event()
+        release = threading.Event()
+
+        def slow_open():
+            opening.set()
+            release.wait(5)
+            return "slow store"
+
+        with ThreadPoolExecutor(max_workers=3) as pool:
+            slow = pool.submit(self.cache.get, "slow", slow_open)
+            self.assertTrue(opening.wait(5))
+            waiting = pool.submit(self.cache.get, "slow", self.opener("slow"))
+            # Opened while the slow collection still connects
+            self.cache.get("c0", self.opener("c0"))
+            self.assertCountEqual(["slow", "c0"], self.cache.names())
+            release.set()
+            self.assertEqual("slow store", slow.result(5))
+            self.assertEqual("slow store", waiting.result(5), "opened once for both")
+        self.assertEqual(["c0"], self.opened)
+
+    def test_failed_open_is_raised_to_waiters_and_retried(self):
+        opening = threading.Event()
+        release = threading.Event()
+
+        def failing_open():
+            opening.set()
+            release.wait(5)
+            raise ConnectionError("index unreachable")
+
+        with ThreadPoolExecutor(max_workers=2) as pool:
+            first = pool.submit(self.cache.get, "c0", failing_open)
+            self.assertTrue(opening.wait(5))
+            waiting = pool.submit(self.cache.get, "c0", self.opener("c0"))
+            time.sleep(0.01)  # let it wait on the open in progress
+            release.set()
+            with self.assertRaises(ConnectionError):
+                first.result(5)
+            with self.assertRaises(ConnectionError):
+                waiting.result(5)
+
+        self.assertIsNotNone(self.cache.get("c0", self.opener("c0")))
+        self.assertEqual(["c0"], self.opened)
+
+    def test_store_opened_across_an_eviction_is_not_kept(self):
+        opening = threading.Event()
+        release = threading.Event()
+
+        def slow_open():
+            opening.set()
+            release.wait(5)
+            return "outdated store"
+
+        with ThreadPoolExecutor(max_workers=1) as pool:
+            first = pool.submit(self.cache.get, "c0", slow_open)
+            self.assertTrue(opening.wait(5))
+            self.cache.evict("c0")
+            release.set()
+            self.assertEqual("outdated store", first.result(5))
+        self.assertIsNot("outdated store", self.cache.get("c0", self.opener("c0")))
+        self.assertEqual(["c0"], self.opened)
+
+
+class FakePineconeConcurrencyTest(unittest.TestCase):
+    def test_concurrent_create_list_and_delete(self):
+        pinecone = FakePineconeClient(8)
+
+        def churn(worker):
+            for i in range(200):
+                name = f"w{worker}-{i % 5}"
+                pinecone.create_index(name, dimension=8)
+                pinecone.list_indexes()
+                pinecone.Index(name=name)
+                pinecone.delete_index(name)
+
+        with ThreadPoolExecutor(max_workers=8) as pool:
+            for future in [pool.submit(churn, w) for w in range(8)]:
+                future.result()  # re-raises "dictionary changed size" and the like
+        self.assertEqual([], pinecone.list_indexes())
+
+
+if __name__ == "__main__":
+    unittest.main()
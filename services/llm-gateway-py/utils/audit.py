@@ -0,0 +1,98 @@
+"""Append-only audit trail for admin operations."""
+
+import hashlib
+import json
+import logging
+import os
+import threading
+from contextlib import contextmanager
+from datetime import datetime, timezone
+from typing import Any, Dict, Iterator, List, Optional
+
+logger = logging.getLogger(__name__)
+
+# Audited action types
+COLLECTION_CREATE = "collection.create"
+COLLECTION_DELETE = "collection.delete"
+DOCUMENT_INGEST = "document.ingest"
+COLLECTION_IMPORT = "collection.import"
+
+OUTCOME_SUCCESS = "success"
+OUTCOME_FAILURE = "failure"
+
+
+def admin_actor(api_key: str, name: Optional[str] = None) -> str:
+    """Identify the caller of an admin endpoint.
+
+    The key itself is never recorded, only a short fingerprint of it. A
+    self-reported name (the X-Admin-User header) is kept alongside it.
+    """
+    fingerprint = "key:" + hashlib.sha256(api_key.encode("utf-8")).hexdigest()[:8]
+    name = (name or "").strip()
+    return f"{name} ({fingerprint})" if name else fingerprint
+
+
+class AuditLog:
+    """Audit entries stored as JSON lines in an append-only file."""
+
+    def __init__(self, path: str):
+        self.path = path
+        self._lock = threading.Lock()
+
+    def record(self, actor: str, action: str, collection: str, outcome: str,
+               detail: str = "") -> Dict[str, Any]:
+        """Append one entry and return it."""
+        entry = {
+            "timestamp": datetime.now(timezone.utc).isoformat(),
+            "actor": actor,
+            "action": action,
+            "collection": collection,
+            "outcome": outcome,
+            "detail": detail,
+        }
+        line = json.dumps(entry, ensure_ascii=False)
+        with self._lock:
+            directory = os.path.dirname(self.path)
+            if directory:
+                os.makedirs(directory, exist_ok=True)
+            with open(self.path, "a", encoding="utf-8") as f:
+                f.write(line + "\n")
+        logger.info(f"Audit: {actor} {action} {collection or '-'} -> {outcome}")
+        return entry
+
+    @contextmanager
+    def track(self, actor: str, action: str, collection: str) -> Iterator[Dict[str, str]]:
+        """Record the outcome of the enclosed admin operation.
+
+        The operation succeeds unless it raises or sets ``outcome`` in the
+        yielded dict to OUTCOME_FAILURE; it may also set ``detail``.
+        """
+        result = {"outcome": OUTCOME_SUCCESS, "detail": ""}
+        try:
+            yield result
+        except Exception as e:
+            self.record(actor, action, collection, OUTCOME_FAILURE, str(e))
+            raise
+        self.record(actor, action, collection, result["outcome"], result["detail"])
+
+    def recent(self, limit: int = 100, action: Optional[str] = None) -> List[Dict[str, Any]]:
+        """Return up to limit entries, newest first, optionally of one action type."""
+        if not os.path.exists(self.path):
+            return []
+        with self._lock:
+            with open(self.path, "r", encoding="utf-8") as f:
+                lines = f.readlines()
+
+        entries = []
+        for line in reversed(lines):
+            try:
+                entry = json.loads(line)
+            except json.JSONDecodeError:
+                # A torn write from a crash; skip it rather than fail the query
+                continue
+            if action and entry.get("action") != action:
+                continue
+            entries.append(entry)
+            if len(entries) >= limit:
+                break
+        return entries
@@ -0,0 +1,123 @@
+"""Sharing one generation among identical concurrent requests."""
+
+import asyncio
+import logging
+import re
+from typing import Any, AsyncIterator, Callable, Dict, Hashable, List, Optional, Sequence
+
+from .generation import VERBOSITY_NORMAL
+
+logger = logging.getLogger(__name__)
+
+
+def coalescing_key(request: Any, collections: Sequence[str]) -> Optional[Hashable]:
+    """Key under which a GenerateWithRAG request may share a generation.
+
+    Requests with the same prompt (ignoring case and whitespace), collections
+    and pipeline options get the same key. Requests shaped for one caller,
+    with sampling overrides (such as a regenerated answer), continuing an
+    answer or asking for debug output, get None and are never coalesced.
+
+    Args:
+        request: GenerateWithRAGRequest
+        collections: The collections the request resolves to
+
+    Returns:
+        A hashable key, or None when the request must run on its own
+    """
+    if request.debug or request.HasField("params") or request.continue_from:
+        return None
+    prompt = re.sub(r"\s+", " ", request.prompt).strip().lower()
+    strict = request.strict_grounding if request.HasField("strict_grounding") else None
+    return (prompt, tuple(collections), request.adaptive, strict, request.verbosity or VERBOSITY_NORMAL)
+
+
+class _Flight:
+    """One generation in progress and what it has produced so far."""
+
+    def __init__(self):
+        self.items: List[Any] = []
+        self.done = False
+        self.error: Optional[BaseException] = None
+        self.subscribers = 0
+        self.task: Optional[asyncio.Task] = None
+        self.changed = asyncio.Condition()
+        # What the caller that started the stream shared with the others
+        self.shared: Any = None
+
+
+class StreamCoalescer:
+    """Runs one stream per key and fans its items out to every caller.
+
+    The first caller for a key starts the stream in its own task; callers
+    arriving while it runs get everything it produced so far and then each
+    new item. The stream is cancelled once every caller has gone, so one
+    caller disconnecting doesn't cut off the others.
+    """
+
+    def __init__(self):
+        self._flights: Dict[Hashable, _Flight] = {}
+
+    @property
+    def active(self) -> int:
+        """Number of streams currently running."""
+        return len(self._flights)
+
+    async def stream(self, key: Hashable, start: Callable[[], AsyncIterator[Any]], shared: Any = None,
+                     on_leave: Optional[Callable[[Any], None]] = None) -> AsyncIterator[Any]:
+        """Yield the items of the stream for key, starting it if needed.
+
+        Args:
+            key: Identifies streams that may be shared
+            start: Returns the stream; called only when none is running
+                for key
+            shared: Kept with the stream when this call starts it, e.g.
+                what it accounts its cost in
+            on_leave: Called with the shared value of the stream read, the
+                starter's, once this caller stops reading
+
+        Yields:
+            Every item of the stream, from the first
+        """
+        flight = self._flights.get(key)
+        if flight is None:
+            flight = self._flights[key] = _Flight()
+            flight.shared = shared
+            flight.task = asyncio.create_task(self._run(key, flight, start()))
+        else:
+            logger.info(f"Joining a generation already in progress ({flight.subscribers} waiting)")
+
+        flight.subscribers += 1
+        sent = 0
+        try:
+            while True:
+                async with flight.changed:
+                    await flight.changed.wait_for(lambda: flight.done or len(flight.items) > sent)
+                while sent < len(flight.items):
+                    yield flight.items[sent]
+                    sent += 1
+                if flight.done and sent == len(flight.items):
+                    if flight.error is not None:
+                        raise flight.error
+                    return
+        finally:
+            flight.subscribers -= 1
+            if flight.subscribers == 0 and not flight.done:
+                flight.task.cancel()
+            if on_leave is not None:
+                on_leave(flight.shared)
+
+    async def _run(self, key: Hashable, flight: _Flight, items: AsyncIterator[Any]):
+        try:
+            async for item in items:
+                flight.items.append(item)
+                async with flight.changed:
+                    flight.changed.notify_all()
+        except Exception as e:
+            flight.error = e
+        finally:
+            flight.done = True
+            if self._flights.get(key) is flight:
+                del self._flights[key]
+            async with flight.changed:
+                flight.changed.notify_all()
@@ -0,0 +1,131 @@
+"""Export and import of a collection's documents, for backups and for
+promoting a collection from one environment to another.
+
+An export is JSON lines, one object per stored document:
+
+    {"id": "hust#0", "content": "...", "metadata": {"source": "hust.pdf"}}
+
+It is read from the vector store a page at a time, so a large collection
+is never held in memory. Importing re-embeds the documents with the
+target's embedding model and stores them under the same ids, so importing
+an export twice leaves one copy of each document.
+"""
+
+import json
+import logging
+from dataclasses import dataclass, field
+from typing import Any, AsyncIterable, AsyncIterator, Callable, Dict, Iterator, List, Tuple
+
+from .ingestion import (
+    DEFAULT_UPSERT_BATCH_SIZE,
+    DEFAULT_UPSERT_MAX_RETRIES,
+    DEFAULT_UPSERT_RETRY_DELAY,
+    upsert_in_batches,
+)
+
+logger = logging.getLogger(__name__)
+
+# Documents read from the store per page; Pinecone lists at most 100 ids
+EXPORT_PAGE_SIZE = 100
+
+# Builds a document to store from its content and metadata
+DocumentFactory = Callable[[str, Dict[str, Any]], Any]
+
+
+class InvalidExportLine(ValueError):
+    """A line of an import is not an exported document."""
+
+
+@dataclass
+class ImportResult:
+    """How many documents an import stored, and the ids of those it could not."""
+    imported: int = 0
+    failed_ids: List[str] = field(default_factory=list)
+
+
+def export_lines(store, collection: str, page_size: int = EXPORT_PAGE_SIZE) -> Iterator[str]:
+    """Yield the collection's documents as JSON lines, fetching a page of
+    them at a time from store, a utils.vector_backend.VectorStore."""
+    cursor = ""
+    while True:
+        page, cursor = store.list_documents(collection, page_size, cursor)
+        for doc_id, document in page:
+            record = {"id": doc_id, "content": document.page_content, "metadata": dict(document.metadata)}
+            yield json.dumps(record, ensure_ascii=False) + "\n"
+        if not cursor:
+            return
+
+
+def parse_line(line: str, number: int) -> Tuple[str, str, Dict[str, Any]]:
+    """Return the id, content and metadata of an exported document.
+
+    Raises:
+        InvalidExportLine: The line, the number-th, is not one
+    """
+    try:
+        record = json.loads(line)
+    except ValueError as e:
+        raise InvalidExportLine(f"line {number}: not JSON: {e}") from None
+    if not isinstance(record, dict):
+        raise InvalidExportLine(f"line {number}: expected an object")
+    doc_id, content, metadata = record.get("id"), record.get("content"), record.get("metadata", {})
+    if not isinstance(doc_id, str) or not doc_id:
+        raise InvalidExportLine(f"line {number}: id must be a non-empty string")
+    if not isinstance(content, str) or not content:
+        raise InvalidExportLine(f"line {number}: content must be a non-empty string")
+    if not isinstance(metadata, dict):
+        raise InvalidExportLine(f"line {number}: metadata must be an object")
+    return doc_id, content, metadata
+
+
+async def split_lines(chunks: AsyncIterable[bytes]) -> AsyncIterator[str]:
+    """Yield the lines of a UTF-8 body arriving in chunks, such as a
+    streamed request body, without the line endings."""
+    pending = b""
+    async for chunk in chunks:
+        pending += chunk
+        *lines, pending = pending.split(b"\n")
+        for line in lines:
+            yield line.decode("utf-8")
+    if pending:
+        yield pending.decode("utf-8")
+
+
+async def import_lines(vector_store, lines: AsyncIterable[str], make_document: DocumentFactory,
+                       batch_size: int = DEFAULT_UPSERT_BATCH_SIZE,
+                       max_retries: int = DEFAULT_UPSERT_MAX_RETRIES,
+                       retry_delay: float = DEFAULT_UPSERT_RETRY_DELAY) -> ImportResult:
+    """Store the documents of an export in vector_store, a batch at a time.
+
+    Blank lines are skipped. Batches stored before an invalid line stay
+    stored.
+
+    Raises:
+        InvalidExportLine: A line is not an exported document
+    """
+    result = ImportResult()
+    batch: List[Any] = []
+    ids: List[str] = []
+
+    async def flush():
+        stored = await upsert_in_batches(vector_store, batch, ids, batch_size=batch_size,
+                                         max_retries=max_retries, retry_delay=retry_delay)
+        result.imported += len(stored.succeeded_ids)
+        result.failed_ids.extend(stored.failed_ids)
+        batch.clear()
+        ids.clear()
+
+    number = 0
+    async for line in lines:
+        number += 1
+        if not line.strip():
+            continue
+        doc_id, content, metadata = parse_line(line, number)
+        batch.append(make_document(content, metadata))
+        ids.append(doc_id)
+        if len(batch) >= batch_size:
+            await flush()
+    if batch:
+        await flush()
+    logger.info(f"Imported {result.imported} documents, {len(result.failed_ids)} failed")
+    return result
@@ -0,0 +1,74 @@
+"""Record of the collections created through the service.
+
+Orphan purging must never take an index someone created on purpose for
+an orphan, whichever replica created it and however long ago. Every
+collection created with CreateCollection is recorded here, in a JSON file
+that outlives restarts; point it at a volume the replicas share so all of
+them see each other's collections.
+"""
+
+import json
+import logging
+import os
+import threading
+from datetime import datetime, timezone
+from typing import Dict, List
+
+logger = logging.getLogger(__name__)
+
+
+class CollectionRegistry:
+    """Names of the created collections, with when each was created.
+
+    The file is read on every lookup, so collections recorded by other
+    replicas are seen at once, and rewritten whole through a temporary file,
+    so a crash never leaves it half written.
+    """
+
+    def __init__(self, path: str):
+        self.path = path
+        self._lock = threading.Lock()
+
+    def _read(self) -> Dict[str, str]:
+        try:
+            with open(self.path, encoding="utf-8") as f:
+                return json.load(f)
+        except FileNotFoundError:
+            return {}
+
+    def _write(self, entries: Dict[str, str]) -> None:
+        directory = os.path.dirname(self.path)
+        if directory:
+            os.makedirs(directory, exist_ok=True)
+        tmp = f"{self.path}.{os.getpid()}.tmp"
+        with open(tmp, "w", encoding="utf-8") as f:
+            json.dump(entries, f, indent=2, sort_keys=True)
+        os.replace(tmp, self.path)
+
+    def add(self, name: str) -> None:
+        """Record that the collection was created."""
+        with self._lock:
+            entries = self._read()
+            if name in entries:
+                return
+            entries[name] = datetime.now(timezone.utc).isoformat()
+            self._write(entries)
+        logger.info(f"Registered collection '{name}'")
+
+    def remove(self, name: str) -> None:
+        """Forget a collection that was deleted."""
+        with self._lock:
+            entries = self._read()
+            if entries.pop(name, None) is None:
+                return
+            self._write(entries)
+
+    def names(self) -> List[str]:
+        """Return the recorded collections in name order.
+
+        Raises:
+            ValueError: The file is not valid JSON; purging must not go on
+                as if no collection were recorded
+        """
+        with self._lock:
+            return sorted(self._read())
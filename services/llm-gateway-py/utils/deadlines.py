@@ -0,0 +1,115 @@
+"""Default deadlines for gRPC calls that arrive without one.
+
+A client that sets no deadline could otherwise hold a generation or an
+ingestion open indefinitely. Calls that carry a deadline keep it; gRPC
+enforces those itself.
+"""
+
+import asyncio
+from typing import Any, AsyncIterator, Awaitable, Callable
+
+
+class DeadlineExceeded(Exception):
+    """A call ran past the default deadline it was given."""
+
+    def __init__(self, seconds: float):
+        super().__init__(f"call set no deadline and ran past the default of {seconds:g}s")
+
+
+def _needs_default(context, seconds: float) -> bool:
+    return seconds > 0 and context.time_remaining() is None
+
+
+async def unary_with_deadline(behavior: Callable[[Any, Any], Awaitable[Any]], request, context,
+                              seconds: float) -> Any:
+    """Run a unary handler, bounded by seconds when the call has no deadline.
+
+    Raises:
+        DeadlineExceeded: The handler ran past the default deadline; it has
+            been cancelled
+    """
+    if not _needs_default(context, seconds):
+        return await behavior(request, context)
+    try:
+        return await asyncio.wait_for(behavior(request, context), seconds)
+    except asyncio.TimeoutError:
+        raise DeadlineExceeded(seconds) from None
+
+
+async def stream_with_deadline(behavior: Callable[[Any, Any], AsyncIterator[Any]], request, context,
+                               seconds: float) -> AsyncIterator[Any]:
+    """Relay a streaming handler, bounded by seconds when the call has no
+    deadline.
+
+    Raises:
+        DeadlineExceeded: The stream ran past the default deadline; it has
+            been closed
+    """
+    responses = behavior(request, context)
+    if not _needs_default(context, seconds):
+        async for response in responses:
+            yield response
+        return
+
+    loop = asyncio.get_running_loop()
+    deadline = loop.time() + seconds
+    try:
+        while True:
+            try:
+                response = await asyncio.wait_for(responses.__anext__(), max(deadline - loop.time(), 0))
+            except StopAsyncIteration:
+                return
+            except asyncio.TimeoutError:
+                raise DeadlineExceeded(seconds) from None
+            yield response
+    finally:
+        await responses.aclose()
+
+
+def default_deadline_interceptor(unary_seconds: float, stream_seconds: float):
+    """Server interceptor giving unary and server-streaming calls without a
+    deadline one of unary_seconds or stream_seconds; 0 leaves them unbounded.
+    Calls that run past it fail with DEADLINE_EXCEEDED."""
+    import grpc
+
+    async def expire(context, e: DeadlineExceeded):
+        await context.abort(grpc.StatusCode.DEADLINE_EXCEEDED, str(e))
+
+    def bound_unary(behavior):
+        async def handle(request, context):
+            try:
+                return await unary_with_deadline(behavior, request, context, unary_seconds)
+            except DeadlineExceeded as e:
+                await expire(context, e)
+        return handle
+
+    def bound_stream(behavior):
+        async def handle(request, context):
+            try:
+                async for response in stream_with_deadline(behavior, request, context, stream_seconds):
+                    yield response
+            except DeadlineExceeded as e:
+                await expire(context, e)
+        return handle
+
+    class DefaultDeadlineInterceptor(grpc.aio.ServerInterceptor):
+        async def intercept_service(self, continuation, handler_call_details):
+            handler = await continuation(handler_call_details)
+            if handler is None:
+                return None
+            if handler.unary_unary:
+                return grpc.unary_unary_rpc_method_handler(
+                    bound_unary(handler.unary_unary),
+                    request_deserializer=handler.request_deserializer,
+                    response_serializer=handler.response_serializer,
+                )
+            if handler.unary_stream:
+                return grpc.unary_stream_rpc_method_handler(
+                    bound_stream(handler.unary_stream),
+                    request_deserializer=handler.request_deserializer,
+                    response_serializer=handler.response_serializer,
+                )
+            # Client-streaming calls; the service has none
+            return handler
+
+    return DefaultDeadlineInterceptor()
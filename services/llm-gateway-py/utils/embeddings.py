@@ -0,0 +1,167 @@
+"""Supported embedding models, their vector dimensions and input limits,
+and the optional normalization of their vectors."""
+
+import logging
+import math
+import threading
+from dataclasses import dataclass
+from typing import Any, Callable, Dict, Iterable, List, Optional, Tuple
+
+logger = logging.getLogger(__name__)
+
+PROVIDER_OPENAI = "openai"
+PROVIDER_HUGGINGFACE = "huggingface"
+
+
+@dataclass(frozen=True)
+class EmbeddingModelSpec:
+    """How to build an embedding model, the size of its vectors and the
+    most tokens it embeds in one input."""
+    provider: str
+    model_name: str
+    dimensions: int
+    # Longer inputs are rejected (OpenAI) or silently truncated
+    # (sentence-transformers), so chunks are kept within it
+    max_input_tokens: int
+
+
+# Keyed by the EMBEDDING_MODEL value; "llama" is the historical alias for
+# the multilingual MiniLM model
+EMBEDDING_MODELS: Dict[str, EmbeddingModelSpec] = {
+    "text-embedding-3-small": EmbeddingModelSpec(PROVIDER_OPENAI, "text-embedding-3-small", 1536, 8191),
+    "text-embedding-3-large": EmbeddingModelSpec(PROVIDER_OPENAI, "text-embedding-3-large", 3072, 8191),
+    "text-embedding-ada-002": EmbeddingModelSpec(PROVIDER_OPENAI, "text-embedding-ada-002", 1536, 8191),
+    "llama": EmbeddingModelSpec(PROVIDER_HUGGINGFACE, "sentence-transformers/paraphrase-multilingual-MiniLM-L12-v2", 384, 128),
+    "sentence-transformers/paraphrase-multilingual-MiniLM-L12-v2": EmbeddingModelSpec(
+        PROVIDER_HUGGINGFACE, "sentence-transformers/paraphrase-multilingual-MiniLM-L12-v2", 384, 128),
+    "sentence-transformers/all-MiniLM-L6-v2": EmbeddingModelSpec(
+        PROVIDER_HUGGINGFACE, "sentence-transformers/all-MiniLM-L6-v2", 384, 256),
+    "sentence-transformers/all-mpnet-base-v2": EmbeddingModelSpec(
+        PROVIDER_HUGGINGFACE, "sentence-transformers/all-mpnet-base-v2", 768, 384),
+}
+
+
+def l2_normalize(vector: List[float]) -> List[float]:
+    """Scale vector to unit length; a zero vector is returned as is."""
+    norm = math.sqrt(sum(v * v for v in vector))
+    if norm == 0.0:
+        return list(vector)
+    return [v / norm for v in vector]
+
+
+class NormalizedEmbeddings:
+    """Embeddings model whose document and query vectors are scaled to unit
+    length.
+
+    Pinecone's cosine and dot product scores rank best on unit vectors, which
+    some embedders don't produce. Wrapping the one model every vector store
+    embeds with normalizes ingested documents and queries alike, so the two
+    never disagree. Anything else is passed through to the wrapped model.
+    """
+
+    def __init__(self, embeddings: Any):
+        self.embeddings = embeddings
+
+    def embed_documents(self, texts: List[str]) -> List[List[float]]:
+        return [l2_normalize(v) for v in self.embeddings.embed_documents(texts)]
+
+    def embed_query(self, text: str) -> List[float]:
+        return l2_normalize(self.embeddings.embed_query(text))
+
+    async def aembed_documents(self, texts: List[str]) -> List[List[float]]:
+        return [l2_normalize(v) for v in await self.embeddings.aembed_documents(texts)]
+
+    async def aembed_query(self, text: str) -> List[float]:
+        return l2_normalize(await self.embeddings.aembed_query(text))
+
+    def __getattr__(self, name: str) -> Any:
+        # Only called for names not found here; guards copies made before
+        # __init__ ran against recursing
+        if name == "embeddings":
+            raise AttributeError(name)
+        return getattr(self.embeddings, name)
+
+
+class UnsupportedEmbeddingModel(ValueError):
+    """EMBEDDING_MODEL names a model the service can't build."""
+
+    def __init__(self, name: str):
+        super().__init__(
+            f"unsupported embedding model '{name}'; supported models: {', '.join(sorted(EMBEDDING_MODELS))}"
+        )
+        self.name = name
+
+
+class EmbeddingDimensionMismatch(ValueError):
+    """Existing indexes hold vectors of a different size than the model makes."""
+
+    def __init__(self, model: str, dimensions: int, mismatched: Dict[str, int]):
+        found = ", ".join(f"'{name}' has {dim}" for name, dim in sorted(mismatched.items()))
+        super().__init__(
+            f"embedding model '{model}' produces {dimensions}-dimensional vectors but {found}; "
+            f"set EMBEDDING_MODEL to the model the index was built with, or re-ingest into a new index"
+        )
+        self.mismatched = mismatched
+
+
+def resolve_embedding_model(name: str) -> EmbeddingModelSpec:
+    """Look up an EMBEDDING_MODEL value."""
+    try:
+        return EMBEDDING_MODELS[name]
+    except KeyError:
+        raise UnsupportedEmbeddingModel(name) from None
+
+
+def check_index_dimensions(model: str, dimensions: int, indexes: Iterable[Tuple[str, int]]):
+    """Raise EmbeddingDimensionMismatch unless every (name, dimension) index
+    matches the model's dimensions."""
+    mismatched = {name: dim for name, dim in indexes if dim != dimensions}
+    if mismatched:
+        raise EmbeddingDimensionMismatch(model, dimensions, mismatched)
+
+
+class CollectionDimensions:
+    """The vector size of each collection, so retrieval can refuse to search
+    a collection with query embeddings of another size, which would return
+    nonsense rather than fail.
+
+    Configured sizes are used as given. Others are looked up with describe,
+    e.g. Pinecone's DescribeIndex, on first use and remembered until the
+    collection is forgotten. Lookups come from executor threads, so the
+    registry is locked.
+    """
+
+    def __init__(self, describe: Callable[[str], int], configured: Optional[Dict[str, int]] = None):
+        self._describe = describe
+        self._configured = dict(configured or {})
+        self._described: Dict[str, int] = {}
+        self._lock = threading.Lock()
+
+    def dimension(self, collection: str) -> Optional[int]:
+        """Return the collection's vector size, or None when it cannot be
+        described, e.g. because it does not exist."""
+        with self._lock:
+            if collection in self._configured:
+                return self._configured[collection]
+            if collection in self._described:
+                return self._described[collection]
+        try:
+            dimension = self._describe(collection)
+        except Exception as e:
+            logger.warning(f"Could not look up dimensions of collection '{collection}': {e}")
+            return None
+        with self._lock:
+            self._described[collection] = dimension
+        return dimension
+
+    def check(self, model: str, dimensions: int, collections: Iterable[str]):
+        """Raise EmbeddingDimensionMismatch unless every collection of a
+        known size holds vectors of the model's dimensions."""
+        sizes = ((name, self.dimension(name)) for name in collections)
+        check_index_dimensions(model, dimensions, [(name, dim) for name, dim in sizes if dim is not None])
+
+    def forget(self, collection: str) -> None:
+        """Drop the collection's described size, so the next check looks it
+        up again; configured sizes stay."""
+        with self._lock:
+            self._described.pop(collection, None)
@@ -0,0 +1,267 @@
+"""Deterministic stand-ins for OpenAI and Pinecone.
+
+Used by LLM_TEST_MODE and by tests that drive the full RAG pipeline without
+network access. Everything here is seedable and depends only on the
+standard library.
+"""
+
+import hashlib
+import math
+import random
+import re
+import threading
+from types import SimpleNamespace
+from typing import Any, Callable, Dict, List, Optional, Sequence, Tuple
+
+from .generation import FINISH_LENGTH, split_continuation_prompt
+
+# Citation markers the RAG prompts put before each retrieved document
+CITATION_PATTERN = re.compile(r"\[((?:Source|Nguồn) \d+ - [^\]]+)\]")
+
+OPENERS = (
+    "Based on the retrieved information,",
+    "According to the available sources,",
+    "From the documents provided,",
+)
+
+
+class FakeChunk:
+    """A streamed message chunk; the last carries the finish reason."""
+
+    def __init__(self, content: str, finish_reason: Optional[str] = None):
+        self.content = content
+        self.response_metadata = {"finish_reason": finish_reason} if finish_reason else {}
+
+
+class FakeChatModel:
+    """Chat model with scripted or deterministic replies.
+
+    With responses, replies cycle through them. Otherwise each reply is
+    derived from the seed and the prompt and cites every source in the
+    prompt, so tests can see which retrieved context reached generation.
+    Asked to continue a reply (see continuation_prompt), it replies with the
+    rest of it. With max_words, replies stop after that many words as if at
+    the token limit.
+    """
+
+    def __init__(self, responses: Optional[Sequence[str]] = None, seed: int = 0,
+                 route: str = "vectorstore", grade: str = "yes", max_words: Optional[int] = None):
+        self.responses = list(responses or [])
+        self.seed = seed
+        self.route = route
+        self.grade = grade
+        self.max_words = max_words
+        self.prompts: List[str] = []
+        self.bound: Dict[str, Any] = {}
+
+    def bind(self, **kwargs) -> "FakeChatModel":
+        self.bound = kwargs
+        return self
+
+    def reply(self, prompt: str) -> str:
+        if self.responses:
+            return self.responses[(len(self.prompts) - 1) % len(self.responses)]
+        rng = random.Random(f"{self.seed}:{prompt}")
+        sources = CITATION_PATTERN.findall(prompt)
+        if not sources:
+            return f"{rng.choice(OPENERS)} no sources were provided."
+        return f"{rng.choice(OPENERS)} " + " ".join(f"[{s}]" for s in sources)
+
+    async def astream(self, prompt: str, **kwargs):
+        self.prompts.append(prompt)
+        continuation = split_continuation_prompt(prompt)
+        if continuation:
+            original, partial = continuation
+            reply = self.reply(original)
+            reply = reply[len(partial):] if reply.startswith(partial) else reply
+        else:
+            reply = self.reply(prompt)
+        words = re.findall(r"\S+\s*", reply)
+        finish_reason = "stop"
+        if self.max_words is not None and len(words) > self.max_words:
+            words, finish_reason = words[:self.max_words], FINISH_LENGTH
+        for i, word in enumerate(words):
+            yield FakeChunk(word, finish_reason if i == len(words) - 1 else None)
+
+    async def ainvoke(self, prompt: str, **kwargs) -> FakeChunk:
+        self.prompts.append(prompt)
+        return FakeChunk(self.reply(prompt), "stop")
+
+    def with_structured_output(self, schema: Callable[..., Any]) -> "FakeStructuredOutput":
+        return FakeStructuredOutput(schema, self.route, self.grade)
+
+
+class FakeStructuredOutput:
+    """Grader/router that gives the same verdict for every input."""
+
+    def __init__(self, schema: Callable[..., Any], route: str, grade: str):
+        self.schema = schema
+        self.route = route
+        self.grade = grade
+        self.calls = 0
+
+    def invoke(self, messages: Any) -> Any:
+        self.calls += 1
+        fields = getattr(self.schema, "model_fields", {})
+        if "datasource" in fields:
+            return self.schema(datasource=self.route)
+        return self.schema(binary_score=self.grade)
+
+
+class FakeEmbeddings:
+    """Bag-of-words embeddings: each word hashes to a dimension."""
+
+    def __init__(self, dimensions: int = 64, seed: int = 0):
+        self.dimensions = dimensions
+        self.seed = seed
+
+    def _embed(self, text: str) -> List[float]:
+        vector = [0.0] * self.dimensions
+        for word in re.findall(r"\w+", text.lower()):
+            digest = hashlib.sha256(f"{self.seed}:{word}".encode("utf-8")).digest()
+            vector[int.from_bytes(digest[:4], "big") % self.dimensions] += 1.0
+        norm = math.sqrt(sum(v * v for v in vector)) or 1.0
+        return [v / norm for v in vector]
+
+    def embed_documents(self, texts: List[str]) -> List[List[float]]:
+        return [self._embed(text) for text in texts]
+
+    def embed_query(self, text: str) -> List[float]:
+        return self._embed(text)
+
+
+class FakeIndex:
+    """In-memory index holding documents and their vectors."""
+
+    def __init__(self, name: str, dimension: int = 64):
+        self.name = name
+        self.dimension = dimension
+        self.ready = True
+        self.entries: List[Tuple[Any, List[float]]] = []
+        # Position in entries of each document added with an id
+        self.positions: Dict[str, int] = {}
+        # Like Pinecone's indexing lag: a document added with an id is left
+        # out of this many fetches before fetch returns it
+        self.fetch_lag = 0
+        self.lagging: Dict[str, int] = {}
+
+    def fetch(self, ids: List[str], **kwargs) -> Any:
+        vectors = {}
+        for doc_id in ids:
+            if doc_id not in self.positions:
+                continue
+            if self.lagging.get(doc_id, 0) > 0:
+                self.lagging[doc_id] -= 1
+                continue
+            document, vector = self.entries[self.positions[doc_id]]
+            # LangChain's Pinecone store keeps the text with the metadata
+            metadata = {**document.metadata, "text": document.page_content}
+            vectors[doc_id] = SimpleNamespace(id=doc_id, values=vector, metadata=metadata)
+        return SimpleNamespace(vectors=vectors)
+
+    def list_paginated(self, limit: int = 100, pagination_token: Optional[str] = None, **kwargs) -> Any:
+        """List ids in id order; the token is the last id of the page."""
+        ids = sorted(doc_id for doc_id in self.positions if doc_id > (pagination_token or ""))
+        page = ids[:limit]
+        next_token = page[-1] if len(ids) > limit else None
+        return SimpleNamespace(
+            vectors=[SimpleNamespace(id=doc_id) for doc_id in page],
+            pagination=SimpleNamespace(next=next_token) if next_token else None,
+        )
+
+    def describe_index_stats(self) -> Dict[str, Any]:
+        return {"total_vector_count": len(self.entries), "index_fullness": 0.0, "namespaces": {}}
+
+    def delete(self, delete_all: bool = False, **kwargs):
+        if delete_all:
+            self.entries.clear()
+            self.positions.clear()
+            self.lagging.clear()
+
+
+class FakeVectorStore:
+    """Vector store over a FakeIndex, scoring by cosine similarity."""
+
+    def __init__(self, index: FakeIndex, embeddings: FakeEmbeddings):
+        self.index = index
+        self.embeddings = embeddings
+
+    def add_documents(self, documents: List[Any], ids: Optional[List[str]] = None) -> List[str]:
+        if ids is None:
+            start = len(self.index.entries)
+            ids = [f"{self.index.name}-{start + i}" for i in range(len(documents))]
+        vectors = self.embeddings.embed_documents([d.page_content for d in documents])
+        # Upserts, like Pinecone: a document replaces one with the same id
+        for doc_id, entry in zip(ids, zip(documents, vectors)):
+            self.index.lagging[doc_id] = self.index.fetch_lag
+            position = self.index.positions.get(doc_id)
+            if position is None:
+                self.index.positions[doc_id] = len(self.index.entries)
+                self.index.entries.append(entry)
+            else:
+                self.index.entries[position] = entry
+        return list(ids)
+
+    def similarity_search_with_score(self, query: str, k: int = 4) -> List[Tuple[Any, float]]:
+        q = self.embeddings.embed_query(query)
+        scored = [(doc, sum(a * b for a, b in zip(q, vector))) for doc, vector in self.index.entries]
+        scored.sort(key=lambda pair: pair[1], reverse=True)
+        return scored[:k]
+
+    def similarity_search(self, query: str, k: int = 4) -> List[Any]:
+        return [doc for doc, _ in self.similarity_search_with_score(query, k)]
+
+
+class FakePineconeClient:
+    """PineconeClient whose indexes live in memory and are ready at once.
+
+    Like the real client it is called from executor threads, so the index
+    map is locked.
+    """
+
+    def __init__(self, dimension: int = 64):
+        self.dimension = dimension
+        self.indexes: Dict[str, FakeIndex] = {}
+        self._lock = threading.Lock()
+
+    def Index(self, name: str = None, **kwargs) -> FakeIndex:
+        with self._lock:
+            if name not in self.indexes:
+                self.indexes[name] = FakeIndex(name, self.dimension)
+            return self.indexes[name]
+
+    def _describe(self, index: FakeIndex) -> SimpleNamespace:
+        return SimpleNamespace(
+            name=index.name,
+            dimension=index.dimension,
+            metric="cosine",
+            host=f"{index.name}.fake",
+            status=SimpleNamespace(ready=index.ready, state="Ready" if index.ready else "Initializing"),
+        )
+
+    def list_indexes(self) -> List[SimpleNamespace]:
+        with self._lock:
+            indexes = list(self.indexes.values())
+        return [self._describe(index) for index in indexes]
+
+    def describe_index(self, name: str) -> SimpleNamespace:
+        with self._lock:
+            index = self.indexes.get(name)
+        if index is None:
+            raise KeyError(f"index '{name}' not found")
+        return self._describe(index)
+
+    def create_index(self, name: str, dimension: int, metric: str = "cosine", spec: Any = None, timeout: Any = None):
+        with self._lock:
+            if name in self.indexes:
+                raise ValueError(f"index '{name}' already exists")
+            self.indexes[name] = FakeIndex(name, dimension)
+
+    def delete_index(self, name: str):
+        with self._lock:
+            self.indexes.pop(name, None)
+
+
+def fake_vector_store_factory(index: FakeIndex, embeddings: FakeEmbeddings) -> FakeVectorStore:
+    """VectorStoreFactory for fake indexes."""
+    return FakeVectorStore(index, embeddings)
@@ -0,0 +1,75 @@
+"""Fail over generation to the next model in a configured chain."""
+
+import asyncio
+import logging
+from typing import AsyncIterator, Callable, Optional, Sequence, Tuple
+
+logger = logging.getLogger(__name__)
+
+# Events yielded by stream_with_fallback
+EVENT_TOKEN = "token"
+EVENT_FALLBACK = "fallback"
+
+# Provider errors worth retrying on another model, by class name so the
+# openai client does not need to be imported here
+RETRYABLE_ERRORS = {
+    "RateLimitError",
+    "APITimeoutError",
+    "APIConnectionError",
+    "InternalServerError",
+}
+
+RETRYABLE_STATUS_CODES = {408, 429, 500, 502, 503, 504}
+
+
+def is_retryable(error: BaseException) -> bool:
+    """Report whether another model might succeed where this error occurred.
+
+    Rate limits, timeouts, connection failures and provider 5xx responses are
+    retryable; bad requests and authentication errors would fail on any model.
+    """
+    if isinstance(error, (asyncio.TimeoutError, ConnectionError)):
+        return True
+    if any(cls.__name__ in RETRYABLE_ERRORS for cls in type(error).__mro__):
+        return True
+    return getattr(error, "status_code", None) in RETRYABLE_STATUS_CODES
+
+
+async def stream_with_fallback(
+    models: Sequence[str],
+    open_stream: Callable[[str], AsyncIterator[str]],
+    retryable: Callable[[BaseException], bool] = is_retryable,
+    on_fallback: Optional[Callable[[str, str, BaseException], None]] = None,
+) -> AsyncIterator[Tuple[str, str]]:
+    """Stream tokens from the first model in the chain that succeeds.
+
+    Each model's stream is consumed from the start. When a model fails with a
+    retryable error, before or after its first token, an (EVENT_FALLBACK,
+    next_model) event is yielded and the next model starts over; callers that
+    already forwarded tokens must tell their client to discard them.
+
+    Args:
+        models: Model names, primary first
+        open_stream: Returns a fresh token stream for a model
+        retryable: Decides whether an error moves on to the next model
+        on_fallback: Called with (failed_model, next_model, error) before switching
+
+    Yields:
+        (EVENT_TOKEN, token) and (EVENT_FALLBACK, model) tuples
+
+    Raises:
+        The last model's error, or the first non-retryable one
+    """
+    for i, model in enumerate(models):
+        if i > 0:
+            yield EVENT_FALLBACK, model
+        try:
+            async for token in open_stream(model):
+                yield EVENT_TOKEN, token
+            return
+        except Exception as e:
+            if i == len(models) - 1 or not retryable(e):
+                raise
+            logger.warning(f"Model '{model}' failed ({type(e).__name__}: {e}), falling back to '{models[i + 1]}'")
+            if on_fallback:
+                on_fallback(model, models[i + 1], e)
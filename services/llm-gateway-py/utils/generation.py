@@ -0,0 +1,253 @@
+"""Per-request options for LLM generation: sampling parameters, answer length and grounding."""
+
+import logging
+from typing import Any, Dict, Iterable, List, Optional, Tuple
+
+logger = logging.getLogger(__name__)
+
+# Accepted range for each sampling parameter, as (min, max)
+PARAM_RANGES: Dict[str, Tuple[float, float]] = {
+    "temperature": (0.0, 2.0),
+    "top_p": (0.0, 1.0),
+    "presence_penalty": (-2.0, 2.0),
+    "frequency_penalty": (-2.0, 2.0),
+}
+
+
+def clamp(name: str, value: float) -> float:
+    """Clamp a sampling parameter to its accepted range."""
+    low, high = PARAM_RANGES[name]
+    clamped = min(max(value, low), high)
+    if clamped != value:
+        logger.warning(f"Clamped {name} from {value} to {clamped}")
+    return clamped
+
+
+def resolve_generation_options(params: Any, defaults: Any) -> Dict[str, float]:
+    """Build LLM call options from request overrides and configured defaults.
+
+    Args:
+        params: GenerationParams message, or None; only fields that are set
+            override the defaults
+        defaults: Object with temperature, top_p, presence_penalty and
+            frequency_penalty attributes (the RAG config)
+
+    Returns:
+        Clamped options for every sampling parameter
+    """
+    options = {}
+    for name in PARAM_RANGES:
+        value = getattr(defaults, name)
+        if params is not None and params.HasField(name):
+            value = getattr(params, name)
+        options[name] = clamp(name, float(value))
+    return options
+
+
+# Providers take at most this many stop sequences per call; any further ones
+# are only enforced by StopSequenceFilter
+MAX_PROVIDER_STOP_SEQUENCES = 4
+
+
+def resolve_stop_sequences(params: Any, defaults: Any) -> List[str]:
+    """The configured stop sequences followed by the request's own.
+
+    Args:
+        params: GenerationParams message, or None
+        defaults: Object with a stop_sequences attribute (the RAG config)
+
+    Returns:
+        The non-empty sequences, each once, in order
+    """
+    stops = list(defaults.stop_sequences)
+    if params is not None:
+        stops.extend(params.stop)
+    return list(dict.fromkeys(stop for stop in stops if stop))
+
+
+def bind_generation_options(llm: Any, params: Any, defaults: Any, max_tokens: Optional[int] = None) -> Any:
+    """Return llm bound to the resolved sampling options and stop sequences for one request.
+
+    max_tokens, when given, replaces the token limit llm was built with.
+    """
+    options: Dict[str, Any] = resolve_generation_options(params, defaults)
+    stops = resolve_stop_sequences(params, defaults)
+    if stops:
+        options["stop"] = stops[:MAX_PROVIDER_STOP_SEQUENCES]
+    if max_tokens is not None:
+        options["max_tokens"] = max_tokens
+    return llm.bind(**options)
+
+
+# Answer lengths a request may ask for; an empty verbosity means normal
+VERBOSITY_SHORT = "short"
+VERBOSITY_NORMAL = "normal"
+VERBOSITY_DETAILED = "detailed"
+
+# The RAG prompt's length instruction for each verbosity, as (English,
+# Vietnamese)
+LENGTH_INSTRUCTIONS: Dict[str, Tuple[str, str]] = {
+    VERBOSITY_SHORT: (
+        "Answer in one or two sentences with only the key facts.",
+        "Trả lời trong một hoặc hai câu, chỉ nêu những thông tin chính.",
+    ),
+    VERBOSITY_NORMAL: (
+        "Keep your answer concise but comprehensive.",
+        "Trả lời ngắn gọn nhưng đầy đủ.",
+    ),
+    VERBOSITY_DETAILED: (
+        "Give a detailed answer that covers every relevant point, organised into sections.",
+        "Trả lời chi tiết, bao quát mọi thông tin liên quan và chia thành các phần rõ ràng.",
+    ),
+}
+
+
+def resolve_verbosity(request: Any) -> str:
+    """The answer length a RAG request asks for.
+
+    An empty verbosity is VERBOSITY_NORMAL, and so is an unknown one, with a
+    warning.
+    """
+    verbosity = request.verbosity or VERBOSITY_NORMAL
+    if verbosity not in LENGTH_INSTRUCTIONS:
+        logger.warning(f"Unknown verbosity '{verbosity}', using '{VERBOSITY_NORMAL}'")
+        return VERBOSITY_NORMAL
+    return verbosity
+
+
+def verbosity_max_tokens(verbosity: str, defaults: Any) -> int:
+    """The token limit of an answer of the given verbosity.
+
+    Args:
+        verbosity: One of the VERBOSITY_ constants
+        defaults: Object with short_max_tokens, max_tokens and
+            detailed_max_tokens attributes (the RAG config)
+    """
+    if verbosity == VERBOSITY_SHORT:
+        return defaults.short_max_tokens
+    if verbosity == VERBOSITY_DETAILED:
+        return defaults.detailed_max_tokens
+    return defaults.max_tokens
+
+
+def length_instruction(verbosity: str, vietnamese: bool) -> str:
+    """The sentence asking the model for an answer of the given verbosity."""
+    english, vi = LENGTH_INSTRUCTIONS[verbosity]
+    return vi if vietnamese else english
+
+
+# Added to the prompt of an answer regenerated for citing no source
+CITATION_INSTRUCTION = (
+    "Cite the context you use after each fact, e.g. [Source 1], and only state facts the context supports.",
+    "Ghi rõ nguồn sau mỗi thông tin bạn dùng, ví dụ [Nguồn 1], và chỉ nêu những thông tin có trong tài liệu tham khảo.",
+)
+
+
+def citation_instruction(vietnamese: bool) -> str:
+    """The sentence telling the model to cite its sources."""
+    english, vi = CITATION_INSTRUCTION
+    return vi if vietnamese else english
+
+
+class StopSequenceFilter:
+    """Cuts a streamed answer off at the first stop sequence.
+
+    Providers stop generating at a stop sequence, but not all of them, nor
+    fallback models, honour every sequence. Text that may be the start of one
+    is held back until the next token shows whether it is, so the sequence
+    itself is never streamed.
+    """
+
+    def __init__(self, stops: Iterable[str]):
+        self.stops = [stop for stop in stops if stop]
+        self.pending = ""
+        self.stopped = False
+
+    def feed(self, token: str) -> str:
+        """Return the part of the stream up to token that may be sent now.
+
+        Once a stop sequence is seen, stopped is set and nothing more is
+        returned.
+        """
+        if self.stopped:
+            return ""
+        if not self.stops:
+            return token
+        text = self.pending + token
+        found = [i for i in (text.find(stop) for stop in self.stops) if i >= 0]
+        if found:
+            self.stopped = True
+            self.pending = ""
+            return text[:min(found)]
+        held = max(self._partial_match(text, stop) for stop in self.stops)
+        self.pending = text[len(text) - held:] if held else ""
+        return text[:len(text) - held]
+
+    def flush(self) -> str:
+        """Return the text held back, once the stream ended without a stop sequence."""
+        text, self.pending = self.pending, ""
+        return text
+
+    @staticmethod
+    def _partial_match(text: str, stop: str) -> int:
+        """Length of the longest end of text that begins stop."""
+        for n in range(min(len(text), len(stop) - 1), 0, -1):
+            if text.endswith(stop[:n]):
+                return n
+        return 0
+
+
+def strict_grounding_enabled(request: Any, defaults: Any) -> bool:
+    """Whether a RAG request may only be answered from retrieved documents.
+
+    The request's strict_grounding field wins when set; otherwise the
+    configured default applies.
+    """
+    if request.HasField("strict_grounding"):
+        return request.strict_grounding
+    return defaults.strict_grounding
+
+
+def no_results_message(defaults: Any, vietnamese: bool) -> str:
+    """The answer given under strict grounding when nothing relevant was found."""
+    return defaults.no_results_message_vi if vietnamese else defaults.no_results_message
+
+
+# Finish reason the model reports when it stopped at the token limit
+FINISH_LENGTH = "length"
+# Finish reason of an answer that ended normally, or at a stop sequence
+FINISH_STOP = "stop"
+
+
+def guarded_prompt(guardrails: str, prompt: str) -> str:
+    """prompt led by the guardrails, the safety rules every generation is
+    given ahead of its persona, context and question. Nothing a request
+    sends can remove or reorder them; empty guardrails leave prompt as is."""
+    if not guardrails.strip():
+        return prompt
+    return f"{guardrails.strip()}\n\n{prompt}"
+
+
+CONTINUATION_HEADER = "Your answer so far, which was cut off at the length limit:"
+CONTINUATION_INSTRUCTION = (
+    "Continue the answer exactly where it stops, without repeating any of it or starting over."
+)
+
+
+def continuation_prompt(prompt: str, partial_answer: str) -> str:
+    """The prompt asking the model to carry on an answer to prompt that was cut off."""
+    return f"{prompt}\n\n{CONTINUATION_HEADER}\n{partial_answer}\n\n{CONTINUATION_INSTRUCTION}"
+
+
+def split_continuation_prompt(prompt: str) -> Optional[Tuple[str, str]]:
+    """Undo continuation_prompt.
+
+    Returns:
+        (prompt, partial answer), or None for a prompt that asks for no
+        continuation
+    """
+    head, header, rest = prompt.partition(f"\n\n{CONTINUATION_HEADER}\n")
+    suffix = f"\n\n{CONTINUATION_INSTRUCTION}"
+    if not header or not rest.endswith(suffix):
+        return None
+    return head, rest[:-len(suffix)]
@@ -0,0 +1,323 @@
+"""Resumable ingestion jobs.
+
+A job ingests a list of documents into one collection. The IDs of stored
+chunks are checkpointed after every upserted batch, so when a job fails or
+the process dies partway, running it again only embeds the chunks that were
+not stored yet.
+
+A running job reports its progress as events, which JobEvents passes on to
+whoever follows the job, such as the admin API's event stream.
+"""
+
+import asyncio
+import json
+import logging
+import os
+import threading
+import uuid
+from dataclasses import asdict, dataclass, field
+from datetime import datetime, timezone
+from types import SimpleNamespace
+from typing import Any, AsyncIterator, Callable, Dict, List, Optional, Tuple
+
+from .ingestion import (
+    DEFAULT_UPSERT_BATCH_SIZE,
+    DEFAULT_UPSERT_MAX_RETRIES,
+    DEFAULT_UPSERT_RETRY_DELAY,
+    chunk_ids,
+    upsert_in_batches,
+)
+
+logger = logging.getLogger(__name__)
+
+# Job statuses
+JOB_PENDING = "pending"
+JOB_RUNNING = "running"
+JOB_COMPLETED = "completed"
+JOB_FAILED = "failed"
+JOB_CANCELLED = "cancelled"
+
+# Progress event types. A job emits a document event as it starts on each
+# document, a batch event per stored batch, an error event per failed batch
+# and, last, a summary event with its progress. A progress event carries
+# the progress of a job joined partway.
+EVENT_PROGRESS = "progress"
+EVENT_DOCUMENT = "document"
+EVENT_BATCH = "batch"
+EVENT_ERROR = "error"
+EVENT_SUMMARY = "summary"
+
+# Receives a job's progress events, as the event type and its data
+EventHandler = Callable[[str, Dict[str, Any]], None]
+
+
+def _now() -> str:
+    return datetime.now(timezone.utc).isoformat()
+
+
+@dataclass
+class JobDocument:
+    """A document of a job and the chunks of it stored so far."""
+    document_id: str
+    content: str
+    metadata: Dict[str, Any] = field(default_factory=dict)
+    # Known once the document has been split
+    total_chunks: Optional[int] = None
+    stored_ids: List[str] = field(default_factory=list)
+
+
+@dataclass
+class IngestJob:
+    """An ingestion job and its checkpointed progress."""
+    job_id: str
+    collection: str
+    documents: List[JobDocument]
+    status: str = JOB_PENDING
+    # The documents were redacted of PII and blocklisted terms when the job
+    # was created
+    scrub: bool = False
+    errors: List[str] = field(default_factory=list)
+    created_at: str = field(default_factory=_now)
+    updated_at: str = field(default_factory=_now)
+
+    def progress(self) -> Dict[str, Any]:
+        """Summarise the job for the admin API, without document contents."""
+        documents = [
+            {
+                "document_id": doc.document_id,
+                "total_chunks": doc.total_chunks,
+                "stored_chunks": len(doc.stored_ids),
+            }
+            for doc in self.documents
+        ]
+        split = [doc for doc in self.documents if doc.total_chunks is not None]
+        return {
+            "job_id": self.job_id,
+            "collection": self.collection,
+            "status": self.status,
+            "scrub": self.scrub,
+            "documents_total": len(self.documents),
+            "documents_split": len(split),
+            # Counts only documents split so far
+            "total_chunks": sum(doc.total_chunks for doc in split),
+            "stored_chunks": sum(len(doc.stored_ids) for doc in self.documents),
+            "errors": self.errors,
+            "created_at": self.created_at,
+            "updated_at": self.updated_at,
+            "documents": documents,
+        }
+
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "IngestJob":
+        data = dict(data)
+        data["documents"] = [JobDocument(**doc) for doc in data.get("documents", [])]
+        return cls(**data)
+
+
+class IngestJobStore:
+    """Jobs saved as one JSON file each in a directory."""
+
+    def __init__(self, directory: str):
+        self.directory = directory
+        self._lock = threading.Lock()
+
+    def _path(self, job_id: str) -> str:
+        return os.path.join(self.directory, f"{job_id}.json")
+
+    def create(self, collection: str, documents: List[JobDocument], scrub: bool = False,
+               scrubber=None) -> IngestJob:
+        """Save and return a new pending job.
+
+        With scrub set, scrubber redacts the documents, content and
+        metadata, before the job is first saved, so the job file never
+        holds what was redacted.
+        """
+        if scrub:
+            if scrubber is None:
+                raise ValueError("scrubbing a job needs a scrubber")
+            for doc in documents:
+                result = scrubber.scrub(doc.content)
+                doc.content = result.text
+                doc.metadata = scrubber.scrub_metadata(doc.metadata, result)
+                if result.redacted:
+                    logger.info(f"Redacted {result.count} matches from document '{doc.document_id}' before saving its job")
+        job = IngestJob(job_id=uuid.uuid4().hex, collection=collection, documents=documents, scrub=scrub)
+        self.save(job)
+        return job
+
+    def save(self, job: IngestJob):
+        """Write the job, replacing the previous checkpoint atomically."""
+        job.updated_at = _now()
+        data = json.dumps(asdict(job), ensure_ascii=False)
+        with self._lock:
+            os.makedirs(self.directory, exist_ok=True)
+            tmp = self._path(job.job_id) + ".tmp"
+            with open(tmp, "w", encoding="utf-8") as f:
+                f.write(data)
+            os.replace(tmp, self._path(job.job_id))
+
+    def get(self, job_id: str) -> Optional[IngestJob]:
+        """Return the job, or None if there is none with this ID."""
+        if not job_id or os.sep in job_id or job_id.startswith("."):
+            return None
+        try:
+            with open(self._path(job_id), "r", encoding="utf-8") as f:
+                return IngestJob.from_dict(json.load(f))
+        except FileNotFoundError:
+            return None
+
+
+class JobEvents:
+    """Passes the progress events of running jobs on to their subscribers.
+
+    Events are published from the event loop the jobs run on, and only
+    reach subscribers that were subscribed when they were published.
+    """
+
+    def __init__(self):
+        self._subscribers: Dict[str, List[asyncio.Queue]] = {}
+
+    def subscribe(self, job_id: str) -> asyncio.Queue:
+        """Return a queue receiving the job's events from now on; read it
+        with queued_events and unsubscribe once done."""
+        queue: asyncio.Queue = asyncio.Queue()
+        self._subscribers.setdefault(job_id, []).append(queue)
+        return queue
+
+    def unsubscribe(self, job_id: str, queue: asyncio.Queue):
+        queues = self._subscribers.get(job_id, [])
+        if queue in queues:
+            queues.remove(queue)
+        if not queues:
+            self._subscribers.pop(job_id, None)
+
+    def publish(self, job_id: str, event: str, data: Dict[str, Any]):
+        for queue in self._subscribers.get(job_id, []):
+            queue.put_nowait((event, data))
+
+    def finish(self, job_id: str):
+        """End the job's subscriptions, e.g. once its task is done, even if
+        it stopped without a summary."""
+        for queue in self._subscribers.get(job_id, []):
+            queue.put_nowait(None)
+
+
+async def queued_events(queue: asyncio.Queue) -> AsyncIterator[Tuple[str, Dict[str, Any]]]:
+    """Yield the events of a JobEvents subscription until the job's summary,
+    or until the job finishes without one."""
+    while True:
+        item = await queue.get()
+        if item is None:
+            return
+        yield item
+        if item[0] == EVENT_SUMMARY:
+            return
+
+
+def sse_event(event: str, data: Dict[str, Any]) -> str:
+    """Format an event for a text/event-stream response."""
+    return f"event: {event}\ndata: {json.dumps(data, ensure_ascii=False)}\n\n"
+
+
+async def run_job(store: IngestJobStore, job: IngestJob, text_splitter, vector_store,
+                  make_document: Callable[..., Any] = SimpleNamespace,
+                  batch_size: int = DEFAULT_UPSERT_BATCH_SIZE,
+                  max_retries: int = DEFAULT_UPSERT_MAX_RETRIES,
+                  retry_delay: float = DEFAULT_UPSERT_RETRY_DELAY,
+                  cancelled: Optional[Callable[[], bool]] = None,
+                  on_event: Optional[EventHandler] = None) -> IngestJob:
+    """Run or resume a job, skipping chunks its checkpoint already has.
+
+    Chunks are upserted with upsert_in_batches and the job is saved after
+    every stored batch. The job ends completed when every chunk is stored,
+    cancelled when cancelled() turned True before that, and failed
+    otherwise; in the last two cases it can be run again.
+
+    Args:
+        store: Store the job is checkpointed to
+        job: Job to run
+        text_splitter: Splitter used to chunk documents; must split the
+            same way on every run, since chunk IDs are positional
+        vector_store: Vector store of the job's collection
+        make_document: Builds the splitter's input from page_content and
+            metadata keyword arguments
+        batch_size: Chunks per upsert
+        max_retries: Retries per failed batch
+        retry_delay: Wait before the first retry
+        cancelled: Checked before each batch; the batch being upserted
+            when it turns True is still stored and checkpointed
+        on_event: Receives the job's progress events, each once the
+            progress it reports is saved
+
+    Returns:
+        The job, as last saved
+    """
+    def emit(event: str, data: Dict[str, Any]):
+        if on_event is not None:
+            on_event(event, data)
+
+    job.status = JOB_RUNNING
+    job.errors = []
+    store.save(job)
+
+    stopped = False
+    try:
+        for number, doc in enumerate(job.documents, start=1):
+            if cancelled is not None and cancelled():
+                stopped = True
+                break
+            document = make_document(page_content=doc.content, metadata=dict(doc.metadata))
+            chunks = text_splitter.split_documents([document])
+            doc.total_chunks = len(chunks)
+            emit(EVENT_DOCUMENT, {
+                "document_id": doc.document_id,
+                "document": number,
+                "documents_total": len(job.documents),
+                "total_chunks": doc.total_chunks,
+                "stored_chunks": len(doc.stored_ids),
+            })
+            stored = set(doc.stored_ids)
+            pending = [(chunk, chunk_id) for chunk, chunk_id in zip(chunks, chunk_ids(doc.document_id, len(chunks)))
+                       if chunk_id not in stored]
+            if not pending:
+                continue
+            if stored:
+                logger.info(f"Job {job.job_id}: resuming '{doc.document_id}' with {len(pending)}/{len(chunks)} chunks left")
+
+            def checkpoint(batch_ids, doc=doc):
+                doc.stored_ids.extend(batch_ids)
+                store.save(job)
+                emit(EVENT_BATCH, {
+                    "document_id": doc.document_id,
+                    "total_chunks": doc.total_chunks,
+                    "stored_chunks": len(doc.stored_ids),
+                    "job_stored_chunks": sum(len(d.stored_ids) for d in job.documents),
+                })
+
+            result = await upsert_in_batches(
+                vector_store, [chunk for chunk, _ in pending], [chunk_id for _, chunk_id in pending],
+                batch_size=batch_size, max_retries=max_retries, retry_delay=retry_delay,
+                on_batch_stored=checkpoint, cancelled=cancelled,
+            )
+            for error in result.errors:
+                job.errors.append(f"{doc.document_id}: {error}")
+                emit(EVENT_ERROR, {"document_id": doc.document_id, "error": str(error)})
+            if result.cancelled_ids:
+                stopped = True
+                break
+    except BaseException as e:
+        # Includes cancellation; whatever was stored stays checkpointed
+        job.status = JOB_FAILED
+        job.errors.append(f"interrupted: {e!r}")
+        store.save(job)
+        emit(EVENT_SUMMARY, job.progress())
+        raise
+
+    if stopped:
+        job.status = JOB_CANCELLED
+    else:
+        job.status = JOB_FAILED if job.errors else JOB_COMPLETED
+    store.save(job)
+    emit(EVENT_SUMMARY, job.progress())
+    logger.info(f"Job {job.job_id} {job.status}")
+    return job
@@ -0,0 +1,301 @@
+"""Document ingestion helpers shared by the gRPC and admin APIs."""
+
+import asyncio
+import copy
+import logging
+from dataclasses import dataclass, field
+from typing import Any, Callable, Dict, List, Optional, Sequence, Tuple
+
+from .helpers import chunk_list
+
+logger = logging.getLogger(__name__)
+
+# USD per 1K tokens for the OpenAI embedding models we use
+EMBEDDING_COST_PER_1K_TOKENS: Dict[str, float] = {
+    "text-embedding-3-small": 0.00002,
+    "text-embedding-3-large": 0.00013,
+    "text-embedding-ada-002": 0.0001,
+}
+
+CHUNK_PREVIEW_CHARS = 200
+
+# Chunk metadata key holding the title of the section a chunk came from
+SECTION_METADATA_KEY = "section"
+
+# Defaults for upserting chunks; see VectorStoreConfig
+DEFAULT_UPSERT_BATCH_SIZE = 100
+DEFAULT_UPSERT_MAX_RETRIES = 2
+DEFAULT_UPSERT_RETRY_DELAY = 1.0
+
+# Defaults for verifying upserts; see VectorStoreConfig
+DEFAULT_VERIFY_SAMPLE_SIZE = 5
+DEFAULT_VERIFY_MAX_ATTEMPTS = 5
+DEFAULT_VERIFY_RETRY_DELAY = 0.5
+
+
+CHARS_PER_TOKEN = 4
+
+
+def estimate_tokens(text: str) -> int:
+    """Estimate the token count of text (~4 characters per token)."""
+    if not text:
+        return 0
+    return max(1, (len(text) + CHARS_PER_TOKEN - 1) // CHARS_PER_TOKEN)
+
+
+def estimate_embedding_cost(tokens: int, model: str) -> float:
+    """Estimate the USD cost of embedding tokens; 0.0 for local or unknown models."""
+    return tokens / 1000 * EMBEDDING_COST_PER_1K_TOKENS.get(model, 0.0)
+
+
+@dataclass
+class IngestionResult:
+    """Outcome of chunking (and optionally storing) a document."""
+    chunks: List[Any]
+    dry_run: bool
+    previews: List[Dict[str, Any]] = field(default_factory=list)
+    estimated_tokens: int = 0
+    estimated_cost_usd: float = 0.0
+    # Chunk IDs by upsert outcome; all empty for dry runs. Cancelled IDs
+    # were never sent, as the ingestion was cancelled first
+    succeeded_ids: List[str] = field(default_factory=list)
+    failed_ids: List[str] = field(default_factory=list)
+    cancelled_ids: List[str] = field(default_factory=list)
+    errors: List[str] = field(default_factory=list)
+    # Matches redacted from the document; 0 when it was not scrubbed
+    redactions: int = 0
+
+    @property
+    def partial(self) -> bool:
+        """Some chunks were stored and some were not."""
+        return bool(self.succeeded_ids) and bool(self.failed_ids or self.cancelled_ids)
+
+
+@dataclass
+class UpsertVerification:
+    """Whether a sample of upserted chunk IDs could be read back."""
+    sampled_ids: List[str]
+    missing_ids: List[str]
+    attempts: int
+
+    @property
+    def visible(self) -> bool:
+        return not self.missing_ids
+
+
+class TokenLimitedSplitter:
+    """Wraps a splitter so no chunk exceeds the embedder's input limit.
+
+    Chunks whose estimated token count is over max_tokens are split again
+    with make_splitter(chunk_size), where chunk_size is the most characters
+    that fit; the pieces replace the chunk in place, so nothing is dropped
+    or truncated by the embedder.
+    """
+
+    def __init__(self, splitter, max_tokens: int, make_splitter: Callable[[int], Any]):
+        self.splitter = splitter
+        self.max_tokens = max_tokens
+        self.make_splitter = make_splitter
+
+    def split_documents(self, documents: List[Any]) -> List[Any]:
+        chunks = []
+        for i, chunk in enumerate(self.splitter.split_documents(documents)):
+            tokens = estimate_tokens(chunk.page_content)
+            if tokens <= self.max_tokens:
+                chunks.append(chunk)
+                continue
+            parts = self.make_splitter(self.max_tokens * CHARS_PER_TOKEN).split_documents([chunk])
+            logger.info(f"Re-split chunk {i} (~{tokens} tokens) into {len(parts)} parts "
+                        f"to fit the embedder's {self.max_tokens} token limit")
+            chunks.extend(parts)
+        return chunks
+
+
+def section_documents(document: Any, sections: Sequence[Tuple[str, str]]) -> List[Any]:
+    """Split a document into one document per section, for the splitter.
+
+    Splitters chunk each document on its own, so no chunk joins the end of
+    one section to the start of the next. document's own content, if any,
+    comes first, without a title. Each section is a copy of document with
+    the section's content, and its title in metadata under
+    SECTION_METADATA_KEY; sections with no content are left out.
+
+    Args:
+        document: Document whose metadata the sections share
+        sections: (title, content) pairs, in document order
+    """
+    parts = [document] if document.page_content else []
+    for title, content in sections:
+        if not content:
+            continue
+        part = copy.copy(document)
+        part.page_content = content
+        part.metadata = dict(document.metadata or {})
+        if title:
+            part.metadata[SECTION_METADATA_KEY] = title
+        parts.append(part)
+    return parts
+
+
+def chunk_ids(document_id: str, count: int) -> List[str]:
+    """Vector IDs for a document's chunks; stable, so re-ingesting overwrites."""
+    return [f"{document_id}#{i}" for i in range(count)]
+
+
+async def upsert_in_batches(vector_store, chunks: List[Any], ids: List[str],
+                            batch_size: int = DEFAULT_UPSERT_BATCH_SIZE,
+                            max_retries: int = DEFAULT_UPSERT_MAX_RETRIES,
+                            retry_delay: float = DEFAULT_UPSERT_RETRY_DELAY,
+                            result: IngestionResult = None,
+                            on_batch_stored: Optional[Callable[[List[str]], None]] = None,
+                            cancelled: Optional[Callable[[], bool]] = None) -> IngestionResult:
+    """Add chunks to the vector store batch by batch, retrying failed batches.
+
+    A batch that still fails after max_retries retries is recorded and the
+    remaining batches carry on, so the result tells which chunks landed.
+    Retries wait retry_delay, doubling each time. on_batch_stored, if
+    given, is called with the IDs of each batch once it is stored.
+
+    cancelled, if given, is checked before each batch; once it returns
+    True no further batches are embedded. Batches already stored stay in
+    the vector store and the rest are reported in cancelled_ids.
+    """
+    if result is None:
+        result = IngestionResult(chunks=chunks, dry_run=False)
+    loop = asyncio.get_event_loop()
+    batches = list(zip(chunk_list(chunks, batch_size), chunk_list(ids, batch_size)))
+    for n, (batch, batch_ids) in enumerate(batches, 1):
+        if cancelled is not None and cancelled():
+            result.cancelled_ids.extend(chunk_id for _, ids in batches[n - 1:] for chunk_id in ids)
+            logger.info(f"Upsert cancelled before batch {n}/{len(batches)}; "
+                        f"{len(result.succeeded_ids)} chunks stored, {len(result.cancelled_ids)} not sent")
+            break
+        for attempt in range(max_retries + 1):
+            try:
+                await loop.run_in_executor(
+                    None,
+                    lambda: vector_store.add_documents(batch, ids=batch_ids)
+                )
+            except Exception as e:
+                if attempt < max_retries:
+                    logger.warning(f"Upsert of batch {n}/{len(batches)} failed, retrying: {e}")
+                    await asyncio.sleep(retry_delay * 2 ** attempt)
+                    continue
+                logger.error(f"Upsert of batch {n}/{len(batches)} failed after {attempt + 1} attempts: {e}")
+                result.failed_ids.extend(batch_ids)
+                result.errors.append(f"batch {n}: {e}")
+            else:
+                result.succeeded_ids.extend(batch_ids)
+                if on_batch_stored:
+                    on_batch_stored(batch_ids)
+                break
+    return result
+
+
+async def ingest_document(text_splitter, vector_store, document, embedding_model: str,
+                          dry_run: bool = False, document_id: str = "doc",
+                          batch_size: int = DEFAULT_UPSERT_BATCH_SIZE,
+                          max_retries: int = DEFAULT_UPSERT_MAX_RETRIES,
+                          retry_delay: float = DEFAULT_UPSERT_RETRY_DELAY,
+                          scrubber=None,
+                          sections: Sequence[Tuple[str, str]] = ()) -> IngestionResult:
+    """Split a document into chunks and add them to the vector store.
+
+    With dry_run set, the chunks are only previewed and costed: the vector
+    store (and therefore the embedder) is never called. Otherwise chunks are
+    upserted with upsert_in_batches, and the result lists which landed.
+
+    Args:
+        text_splitter: Splitter used to chunk the document
+        vector_store: Vector store to write chunks to; unused for dry runs
+        document: Document to ingest
+        embedding_model: Embedding model name, used for cost estimation
+        dry_run: Skip embedding and upserting
+        document_id: Prefix of the chunk IDs
+        batch_size: Chunks per upsert
+        max_retries: Retries per failed batch
+        retry_delay: Wait before the first retry
+        scrubber: If given, redacts the document in place before it is
+            split, so no chunk carries the redacted content
+        sections: (title, content) pairs ingested after the document's
+            content; see section_documents
+
+    Returns:
+        IngestionResult describing the chunks
+    """
+    documents = section_documents(document, sections) if sections else [document]
+    redactions = 0
+    if scrubber is not None:
+        # Scrubbed before splitting rather than per chunk, so matches
+        # spanning a chunk boundary are still caught
+        redactions = sum(scrubber.scrub_document(doc).count for doc in documents)
+        if redactions:
+            logger.info(f"Redacted {redactions} matches from document '{document_id}' before ingestion")
+    chunks = text_splitter.split_documents(documents)
+    result = IngestionResult(chunks=chunks, dry_run=dry_run, redactions=redactions)
+
+    if dry_run:
+        for i, chunk in enumerate(chunks):
+            tokens = estimate_tokens(chunk.page_content)
+            result.previews.append({
+                "index": i,
+                "preview": chunk.page_content[:CHUNK_PREVIEW_CHARS],
+                "char_count": len(chunk.page_content),
+                "estimated_tokens": tokens,
+            })
+            result.estimated_tokens += tokens
+        result.estimated_cost_usd = estimate_embedding_cost(result.estimated_tokens, embedding_model)
+        logger.info(f"Dry run: document would produce {len(chunks)} chunks (~{result.estimated_tokens} tokens)")
+        return result
+
+    return await upsert_in_batches(
+        vector_store, chunks, chunk_ids(document_id, len(chunks)),
+        batch_size=batch_size, max_retries=max_retries, retry_delay=retry_delay,
+        result=result,
+    )
+
+
+def sample_ids(ids: List[str], size: int) -> List[str]:
+    """Up to size of ids, spread evenly from the first to the last."""
+    if len(ids) <= size:
+        return list(ids)
+    if size == 1:
+        return [ids[0]]
+    return [ids[round(i * (len(ids) - 1) / (size - 1))] for i in range(size)]
+
+
+async def verify_upsert(vector_store, ids: List[str],
+                        sample_size: int = DEFAULT_VERIFY_SAMPLE_SIZE,
+                        max_attempts: int = DEFAULT_VERIFY_MAX_ATTEMPTS,
+                        retry_delay: float = DEFAULT_VERIFY_RETRY_DELAY) -> UpsertVerification:
+    """Check that a sample of just-upserted IDs can be read back.
+
+    Vector stores index upserts asynchronously, so IDs still missing are
+    fetched again after retry_delay, doubling each time, for at most
+    max_attempts fetches. A fetch that fails counts as one that found
+    nothing.
+
+    Args:
+        vector_store: Collection the IDs were upserted to, with visible_ids
+        ids: The upserted IDs
+        sample_size: How many of them to check
+        max_attempts: Fetches to make before giving up
+        retry_delay: Wait before the first retry
+    """
+    sampled = sample_ids(ids, sample_size)
+    missing = list(sampled)
+    loop = asyncio.get_event_loop()
+    attempts = 0
+    while missing and attempts < max_attempts:
+        if attempts:
+            await asyncio.sleep(retry_delay * 2 ** (attempts - 1))
+        attempts += 1
+        try:
+            found = set(await loop.run_in_executor(None, lambda: vector_store.visible_ids(missing)))
+        except Exception as e:
+            logger.warning(f"Fetching upserted chunks failed (attempt {attempts}/{max_attempts}): {e}")
+            continue
+        missing = [doc_id for doc_id in missing if doc_id not in found]
+    if missing:
+        logger.warning(f"{len(missing)} of {len(sampled)} sampled chunks were not readable after {attempts} attempts")
+    return UpsertVerification(sampled_ids=sampled, missing_ids=missing, attempts=attempts)
@@ -0,0 +1,104 @@
+"""Detection of an empty knowledge base.
+
+A fresh deployment has no collections, or only the default one with
+nothing ingested. RAG queries then answer from general knowledge, which
+looks like working retrieval and hides that setup is incomplete.
+KnowledgeBaseMonitor tells when that is the case, for the onboarding mode
+and for readiness reports.
+"""
+
+import logging
+import threading
+import time
+from dataclasses import dataclass
+from typing import Callable, Optional
+
+from .provisioning import STATUS_READY
+
+logger = logging.getLogger(__name__)
+
+# Seconds a knowledge base check is reused for
+DEFAULT_CHECK_TTL = 60.0
+
+# Told to admins in RAG debug output while onboarding
+EMPTY_KNOWLEDGE_BASE_NOTICE = (
+    "No knowledge base is configured: no collection holds documents, so the "
+    "answer was generated from general knowledge. Ingest documents with "
+    "POST /admin/ingest or IngestDocument to complete setup."
+)
+
+
+@dataclass
+class KnowledgeBaseStatus:
+    """What the vector database holds across its collections."""
+    collections: int = 0
+    documents: int = 0
+    # Collections whose documents could not be counted; they may hold some
+    uncounted: int = 0
+
+    @property
+    def empty(self) -> bool:
+        """Whether no collection is known to hold, or may hold, documents."""
+        return self.documents == 0 and self.uncounted == 0
+
+
+class KnowledgeBaseMonitor:
+    """Counts what the vector database holds, remembering the count for ttl
+    seconds since RAG requests ask on every query.
+
+    Checks come from executor threads, so the cached status is locked.
+    """
+
+    def __init__(self, store, ttl: float = DEFAULT_CHECK_TTL,
+                 clock: Callable[[], float] = time.monotonic):
+        """
+        Args:
+            store: The utils.vector_backend.VectorStore to check
+            ttl: Seconds a check is reused for
+            clock: Monotonic time source, replaceable in tests
+        """
+        self.store = store
+        self.ttl = ttl
+        self._clock = clock
+        self._status: Optional[KnowledgeBaseStatus] = None
+        self._checked_at = 0.0
+        self._lock = threading.Lock()
+
+    def status(self) -> KnowledgeBaseStatus:
+        """Return what the knowledge base holds, checking again once the
+        last check is older than the ttl.
+
+        Collections still provisioning are skipped. A collection whose
+        documents cannot be counted is reported as uncounted, so a flaky
+        backend does not make a populated knowledge base look empty.
+
+        Raises:
+            Exception: The collections could not be listed
+        """
+        with self._lock:
+            if self._status is not None and self._clock() - self._checked_at < self.ttl:
+                return self._status
+
+        status = KnowledgeBaseStatus()
+        for info in self.store.list_collections():
+            if info.status != STATUS_READY:
+                continue
+            status.collections += 1
+            try:
+                status.documents += self.store.stats(info.name).document_count
+            except Exception as e:
+                logger.warning(f"Could not count documents of collection '{info.name}': {e}")
+                status.uncounted += 1
+        if status.empty:
+            logger.warning(f"Knowledge base is empty: {status.collections} collections hold no documents")
+
+        with self._lock:
+            self._status = status
+            self._checked_at = self._clock()
+        return status
+
+    def forget(self) -> None:
+        """Drop the remembered status, e.g. after documents were ingested,
+        so the next check counts again."""
+        with self._lock:
+            self._status = None
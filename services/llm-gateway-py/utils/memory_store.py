@@ -0,0 +1,132 @@
+"""VectorStore that keeps collections in process memory.
+
+For local development without a Pinecone account, and for tests. Documents
+are embedded with the service's embeddings model and searched by cosine
+similarity over every stored vector, so it suits small collections only.
+Nothing survives a restart.
+"""
+
+import math
+import threading
+from typing import Any, Dict, Iterable, List, Optional, Tuple
+
+from .provisioning import STATUS_READY
+from .vector_backend import CollectionInfo, CollectionStats
+
+
+class _Collection:
+    def __init__(self, dimension: int, metric: str):
+        self.dimension = dimension
+        self.metric = metric
+        # Documents and their normalised vectors, by id, in insertion order
+        self.entries: Dict[str, Tuple[Any, List[float]]] = {}
+        self.next_id = 0
+
+
+def _normalise(vector: List[float]) -> List[float]:
+    norm = math.sqrt(sum(v * v for v in vector)) or 1.0
+    return [v / norm for v in vector]
+
+
+class MemoryVectorStore:
+    """VectorStore whose collections live in memory and are ready at once."""
+
+    def __init__(self, embeddings: Any, dimension: int, collections: Iterable[str] = ()):
+        """
+        Args:
+            embeddings: Embeddings model documents and queries are embedded with
+            dimension: Size of the vectors embeddings produces
+            collections: Collections to create up front, e.g. the default one
+        """
+        self.embeddings = embeddings
+        self.dimension = dimension
+        self._collections: Dict[str, _Collection] = {}
+        self._lock = threading.Lock()
+        for name in collections:
+            self.create_collection(name, dimension)
+
+    def _get(self, name: str) -> _Collection:
+        collection = self._collections.get(name)
+        if collection is None:
+            raise KeyError(f"collection '{name}' not found")
+        return collection
+
+    def _info(self, name: str, collection: _Collection) -> CollectionInfo:
+        return CollectionInfo(name=name, dimension=collection.dimension,
+                              metric=collection.metric, status=STATUS_READY, host="memory")
+
+    def create_collection(self, name: str, dimension: int, metric: str = "cosine") -> None:
+        if metric != "cosine":
+            raise ValueError(f"metric '{metric}' is not supported; the in-memory store only uses cosine")
+        with self._lock:
+            if name in self._collections:
+                raise ValueError(f"collection '{name}' already exists")
+            self._collections[name] = _Collection(dimension, metric)
+
+    def describe_collection(self, name: str) -> CollectionInfo:
+        with self._lock:
+            return self._info(name, self._get(name))
+
+    def list_collections(self) -> List[CollectionInfo]:
+        with self._lock:
+            return [self._info(name, c) for name, c in self._collections.items()]
+
+    def connect(self, collection: str) -> None:
+        with self._lock:
+            self._get(collection)
+
+    def add_documents(self, collection: str, documents: List[Any],
+                      ids: Optional[List[str]] = None) -> List[str]:
+        if ids is not None and len(ids) != len(documents):
+            raise ValueError(f"{len(ids)} ids given for {len(documents)} documents")
+        # Embed outside the lock; it may call out to a provider
+        vectors = [_normalise(v) for v in self.embeddings.embed_documents([d.page_content for d in documents])]
+        with self._lock:
+            target = self._get(collection)
+            for vector in vectors:
+                if len(vector) != target.dimension:
+                    raise ValueError(
+                        f"vector has {len(vector)} dimensions; collection '{collection}' holds {target.dimension}"
+                    )
+            if ids is None:
+                ids = [f"{collection}-{target.next_id + i}" for i in range(len(documents))]
+                target.next_id += len(documents)
+            for doc_id, document, vector in zip(ids, documents, vectors):
+                target.entries[doc_id] = (document, vector)
+        return list(ids)
+
+    def visible_ids(self, collection: str, ids: List[str]) -> List[str]:
+        # Documents are searchable as soon as they are added
+        with self._lock:
+            entries = self._get(collection).entries
+            return [doc_id for doc_id in ids if doc_id in entries]
+
+    def query(self, collection: str, query: str, k: int) -> List[Tuple[Any, float]]:
+        q = _normalise(self.embeddings.embed_query(query))
+        with self._lock:
+            entries = list(self._get(collection).entries.values())
+        scored = [(document, sum(a * b for a, b in zip(q, vector))) for document, vector in entries]
+        scored.sort(key=lambda pair: pair[1], reverse=True)
+        return scored[:k]
+
+    def list_documents(self, collection: str, limit: int,
+                       cursor: str = "") -> Tuple[List[Tuple[str, Any]], str]:
+        # In id order; the cursor is the last id returned
+        with self._lock:
+            entries = self._get(collection).entries
+            ids = sorted(doc_id for doc_id in entries if doc_id > cursor)
+            page = [(doc_id, entries[doc_id][0]) for doc_id in ids[:limit]]
+        return page, page[-1][0] if len(ids) > limit else ""
+
+    def stats(self, collection: str) -> CollectionStats:
+        with self._lock:
+            return CollectionStats(document_count=len(self._get(collection).entries))
+
+    def clear(self, collection: str) -> None:
+        with self._lock:
+            self._get(collection).entries.clear()
+
+    def delete(self, collection: str) -> None:
+        with self._lock:
+            self._get(collection)
+            del self._collections[collection]
@@ -60,6 +60,8 @@ class MetricsCollector:
         self.total_errors = 0
         self.total_tokens = 0
         self.total_duration = 0.0
+        # Generations that failed over to another model, by "from->to"
+        self.model_fallbacks: Dict[str, int] = defaultdict(int)
         
         # Rate tracking
         self.request_times = deque(maxlen=100)  # Last 100 requests for rate calculation
@@ -183,6 +185,16 @@ class MetricsCollector:
         for key in old_buckets:
             del self.aggregated_metrics[key]
     
+    def record_fallback(self, from_model: str, to_model: str):
+        """Record a generation failing over from one model to the next.
+        
+        Args:
+            from_model: Model that failed
+            to_model: Model tried next
+        """
+        with self.lock:
+            self.model_fallbacks[f"{from_model}->{to_model}"] += 1
+    
     def get_current_stats(self) -> Dict[str, Any]:
         """Get current statistics.
         
@@ -215,6 +227,7 @@ class MetricsCollector:
                 'average_duration': round(average_duration, 3),
                 'total_tokens': self.total_tokens,
                 'requests_per_minute': requests_per_minute,
+                'model_fallbacks': dict(self.model_fallbacks),
                 'timestamp': datetime.utcnow().isoformat()
             }
     
@@ -355,7 +368,13 @@ class MetricsCollector:
             f"# HELP llm_gateway_requests_per_minute Current requests per minute",
             f"# TYPE llm_gateway_requests_per_minute gauge",
             f"llm_gateway_requests_per_minute {stats['requests_per_minute']}",
+            f"",
+            f"# HELP llm_gateway_model_fallbacks_total Generations that failed over to the next model",
+            f"# TYPE llm_gateway_model_fallbacks_total counter",
         ]
+        for pair, count in stats['model_fallbacks'].items():
+            from_model, to_model = pair.split("->", 1)
+            lines.append(f'llm_gateway_model_fallbacks_total{{from="{from_model}",to="{to_model}"}} {count}')
         
         return "\n".join(lines)
     
@@ -368,6 +387,7 @@ class MetricsCollector:
             self.total_errors = 0
             self.total_tokens = 0
             self.total_duration = 0.0
+            self.model_fallbacks.clear()
             self.request_times.clear()
 
 
@@ -0,0 +1,46 @@
+"""Finding vector store indexes that nothing tracks any more.
+
+Collections created for tests or by failed migrations are left behind as
+indexes that still cost money. An index is orphaned when it is neither
+known (configured, the default index, or created through the service) nor
+active: in use since startup, or still provisioning, which means someone is
+creating it right now.
+"""
+
+from typing import Dict, Iterable, List, Tuple
+
+from .provisioning import STATUS_PROVISIONING
+
+# Why an index that is not known is kept anyway
+KEPT_IN_USE = "in use"
+KEPT_PROVISIONING = "provisioning"
+# Why an orphan is kept by a confirmed purge
+KEPT_NOT_NAMED = "not named"
+
+
+def find_orphans(indexes: Dict[str, str], known: Iterable[str],
+                 in_use: Iterable[str]) -> Tuple[List[str], List[Dict[str, str]]]:
+    """Split indexes that are not known into orphans and active ones.
+
+    Args:
+        indexes: Collection status by index name
+        known: Names of the indexes that are meant to exist
+        in_use: Names of the indexes the service has opened
+
+    Returns:
+        The orphaned index names, and a dict with name and reason keys for
+        each unknown index that is kept because it is active, both in name
+        order
+    """
+    known, in_use = set(known), set(in_use)
+    orphans, kept = [], []
+    for name in sorted(indexes):
+        if name in known:
+            continue
+        if name in in_use:
+            kept.append({"name": name, "reason": KEPT_IN_USE})
+        elif indexes[name] == STATUS_PROVISIONING:
+            kept.append({"name": name, "reason": KEPT_PROVISIONING})
+        else:
+            orphans.append(name)
+    return orphans, kept
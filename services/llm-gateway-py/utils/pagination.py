@@ -0,0 +1,84 @@
+"""Cursor pagination over name-ordered listings."""
+
+import base64
+import binascii
+from dataclasses import dataclass
+from typing import Any, Dict, List, Sequence, Tuple
+
+DEFAULT_PAGE_SIZE = 50
+MAX_PAGE_SIZE = 200
+
+
+class InvalidPageToken(ValueError):
+    """The page token was not issued by this service."""
+
+
+@dataclass
+class PageInfo:
+    """Where a page sits in the full listing."""
+    next_token: str  # Empty on the last page
+    total: int
+    offset: int
+    limit: int
+
+
+def encode_page_token(last_name: str) -> str:
+    """Build an opaque token resuming after last_name."""
+    return base64.urlsafe_b64encode(last_name.encode("utf-8")).decode("ascii")
+
+
+def decode_page_token(token: str) -> str:
+    """Return the name a page token resumes after."""
+    try:
+        return base64.b64decode(token, altchars=b"-_", validate=True).decode("utf-8")
+    except (binascii.Error, UnicodeError, ValueError) as e:
+        raise InvalidPageToken(f"invalid page token: {token!r}") from e
+
+
+def clamp_page_size(page_size: int) -> int:
+    """Apply the default to an unset page size and cap large ones."""
+    if page_size <= 0:
+        return DEFAULT_PAGE_SIZE
+    return min(page_size, MAX_PAGE_SIZE)
+
+
+def paginate(names: Sequence[str], page_size: int, page_token: str = "") -> Tuple[List[str], str]:
+    """Return one page of names, in sorted order, and the next page's token.
+
+    The token records the last name returned rather than an offset, so names
+    added or removed between calls don't shift later pages. The next token is
+    empty on the last page.
+    """
+    page, info = paginate_with_info(names, page_size, page_token)
+    return page, info.next_token
+
+
+def paginate_with_info(names: Sequence[str], page_size: int,
+                       page_token: str = "") -> Tuple[List[str], PageInfo]:
+    """Like paginate, but describe the page's position too."""
+    ordered = sorted(set(names))
+    total = len(ordered)
+    if page_token:
+        after = decode_page_token(page_token)
+        ordered = [name for name in ordered if name > after]
+
+    size = clamp_page_size(page_size)
+    page = ordered[:size]
+    next_token = encode_page_token(page[-1]) if len(ordered) > size else ""
+    return page, PageInfo(next_token=next_token, total=total, offset=total - len(ordered), limit=size)
+
+
+def pagination_envelope(data: List[Any], info: PageInfo) -> Dict[str, Any]:
+    """The {data, pagination} body list endpoints respond with.
+
+    next is the page_token for the following page, or None on the last.
+    """
+    return {
+        "data": data,
+        "pagination": {
+            "total": info.total,
+            "limit": info.limit,
+            "offset": info.offset,
+            "next": info.next_token or None,
+        },
+    }
@@ -0,0 +1,125 @@
+"""VectorStore backed by Pinecone, with one index per collection.
+
+Documents are stored and searched through LangChain vector stores over the
+indexes, built by a VectorStoreFactory and kept open once used. The client
+and factory are injected so the store runs against utils.fakes in tests.
+"""
+
+from typing import Any, List, Optional, Tuple
+
+from .provisioning import index_status
+from .vector_backend import (
+    CollectionInfo,
+    CollectionStats,
+    PineconeClient,
+    StoredDocument,
+    VectorStoreCache,
+    VectorStoreFactory,
+)
+
+# Metadata field LangChain's Pinecone store keeps a document's text in
+PINECONE_TEXT_KEY = "text"
+
+
+class PineconeStore:
+    """VectorStore over a Pinecone client."""
+
+    def __init__(self, client: PineconeClient, embeddings: Any,
+                 store_factory: VectorStoreFactory, spec: Any = None):
+        """
+        Args:
+            client: Pinecone client, or a fake with the same methods
+            embeddings: Embeddings model documents and queries are embedded with
+            store_factory: Builds a LangChain vector store over an index
+            spec: Deployment spec new indexes are created with, e.g. a
+                pinecone.ServerlessSpec
+        """
+        self.client = client
+        self.embeddings = embeddings
+        self.store_factory = store_factory
+        self.spec = spec
+        self._stores = VectorStoreCache()
+
+    def _store(self, collection: str) -> Any:
+        return self._stores.get(
+            collection, lambda: self.store_factory(self.client.Index(name=collection), self.embeddings)
+        )
+
+    @staticmethod
+    def _info(description: Any) -> CollectionInfo:
+        status = description.status
+        return CollectionInfo(
+            name=str(description.name),
+            dimension=int(description.dimension),
+            metric=str(description.metric),
+            status=index_status(bool(status.ready), str(status.state)) if status else "unknown",
+            host=str(description.host),
+        )
+
+    def create_collection(self, name: str, dimension: int, metric: str = "cosine") -> None:
+        self.client.create_index(
+            name=name,
+            dimension=dimension,
+            metric=metric,
+            spec=self.spec,
+            timeout=-1  # Don't let the client block; callers poll for readiness
+        )
+        # A store opened on an index of the same name deleted since points
+        # at the old index
+        self._stores.evict(name)
+
+    def describe_collection(self, name: str) -> CollectionInfo:
+        return self._info(self.client.describe_index(name))
+
+    def list_collections(self) -> List[CollectionInfo]:
+        return [self._info(description) for description in self.client.list_indexes()]
+
+    def connect(self, collection: str) -> None:
+        self._store(collection)
+
+    def add_documents(self, collection: str, documents: List[Any],
+                      ids: Optional[List[str]] = None) -> List[str]:
+        return self._store(collection).add_documents(documents, ids=ids)
+
+    def visible_ids(self, collection: str, ids: List[str]) -> List[str]:
+        # Pinecone leaves vectors out of fetches until they are indexed
+        vectors = self.client.Index(name=collection).fetch(ids=list(ids)).vectors
+        return [doc_id for doc_id in ids if doc_id in vectors]
+
+    def query(self, collection: str, query: str, k: int) -> List[Tuple[Any, float]]:
+        return self._store(collection).similarity_search_with_score(query, k=k)
+
+    def list_documents(self, collection: str, limit: int,
+                       cursor: str = "") -> Tuple[List[Tuple[str, Any]], str]:
+        # Pinecone lists ids a page at a time, in its own order, and the
+        # cursor is its pagination token
+        index = self.client.Index(name=collection)
+        listed = index.list_paginated(limit=limit, pagination_token=cursor or None)
+        ids = [vector.id for vector in listed.vectors]
+        vectors = index.fetch(ids=ids).vectors if ids else {}
+        page = []
+        for doc_id in ids:
+            if doc_id not in vectors:
+                continue  # deleted since it was listed
+            metadata = dict(vectors[doc_id].metadata or {})
+            text = metadata.pop(PINECONE_TEXT_KEY, "")
+            page.append((doc_id, StoredDocument(text, metadata)))
+        pagination = listed.pagination
+        return page, (pagination.next or "") if pagination else ""
+
+    def stats(self, collection: str) -> CollectionStats:
+        stats = self.client.Index(name=collection).describe_index_stats()
+        return CollectionStats(
+            document_count=int(stats.get("total_vector_count", 0)),
+            metadata={
+                "index_fullness": float(stats.get("index_fullness", 0.0)),
+                "namespace_count": len(stats.get("namespaces", {})),
+            },
+        )
+
+    def clear(self, collection: str) -> None:
+        self.client.Index(name=collection).delete(delete_all=True)
+
+    def delete(self, collection: str) -> None:
+        self.client.delete_index(collection)
+        self._stores.evict(collection)
@@ -0,0 +1,59 @@
+"""Readiness tracking for vector store indexes that are still provisioning."""
+
+import asyncio
+import logging
+import time
+from typing import Awaitable, Callable
+
+logger = logging.getLogger(__name__)
+
+# Collection states reported to callers
+STATUS_READY = "ready"
+STATUS_PROVISIONING = "provisioning"
+STATUS_FAILED = "failed"
+
+
+class IndexProvisioningTimeout(Exception):
+    """The index did not become ready within the allowed time."""
+
+    def __init__(self, name: str, timeout: float):
+        super().__init__(f"index provisioning timed out after {timeout:g}s for '{name}'")
+        self.name = name
+        self.timeout = timeout
+
+
+def index_status(ready: bool, state: str) -> str:
+    """Map a Pinecone index's ready flag and state to a collection status."""
+    if ready:
+        return STATUS_READY
+    if "failed" in (state or "").lower():
+        return STATUS_FAILED
+    return STATUS_PROVISIONING
+
+
+async def wait_until_ready(name: str, check: Callable[[], Awaitable[str]],
+                           timeout: float, poll_interval: float) -> str:
+    """Poll an index's status until it is ready or provisioning has failed.
+
+    Args:
+        name: Index name, for logging and errors
+        check: Coroutine factory returning the current collection status
+        timeout: Maximum seconds to wait
+        poll_interval: Seconds between checks
+
+    Returns:
+        STATUS_READY or STATUS_FAILED
+
+    Raises:
+        IndexProvisioningTimeout: The index was still provisioning at the deadline
+    """
+    deadline = time.monotonic() + timeout
+    while True:
+        status = await check()
+        if status != STATUS_PROVISIONING:
+            return status
+        remaining = deadline - time.monotonic()
+        if remaining <= 0:
+            raise IndexProvisioningTimeout(name, timeout)
+        logger.info(f"Index '{name}' is still provisioning, checking again in {poll_interval:g}s")
+        await asyncio.sleep(min(poll_interval, remaining))
@@ -0,0 +1,114 @@
+"""Wait out provider rate limits before giving up on a model."""
+
+import asyncio
+import logging
+import math
+import re
+import time
+from email.utils import parsedate_to_datetime
+from typing import AsyncIterator, Awaitable, Callable, Optional
+
+logger = logging.getLogger(__name__)
+
+# Shown to users when every model stays rate limited
+HIGH_DEMAND_MESSAGE = "The assistant is in high demand right now. Please try again shortly."
+
+# Assumed when a rate limit error says nothing about when to retry
+DEFAULT_RETRY_AFTER_SECONDS = 1.0
+
+# OpenAI rate limit messages end with e.g. "Please try again in 1.2s."
+_RETRY_IN = re.compile(r"try again in (\d+(?:\.\d+)?)\s*(ms|s)\b", re.IGNORECASE)
+
+
+def is_rate_limit(error: BaseException) -> bool:
+    """Report whether error is a provider rate limit (HTTP 429).
+
+    openai.RateLimitError is matched by class name so the openai client does
+    not need to be imported here.
+    """
+    if any(cls.__name__ == "RateLimitError" for cls in type(error).__mro__):
+        return True
+    return getattr(error, "status_code", None) == 429
+
+
+def retry_after_seconds(error: BaseException) -> Optional[float]:
+    """How long the provider asked to wait before retrying, if it said.
+
+    Read from the response's retry-after-ms or retry-after header (seconds
+    or an HTTP date), else from the "try again in" hint in the message.
+    """
+    headers = getattr(getattr(error, "response", None), "headers", None) or {}
+    value = headers.get("retry-after-ms")
+    if value:
+        try:
+            return max(float(value) / 1000, 0.0)
+        except ValueError:
+            pass
+    value = headers.get("retry-after")
+    if value:
+        try:
+            return max(float(value), 0.0)
+        except ValueError:
+            pass
+        try:
+            return max(parsedate_to_datetime(value).timestamp() - time.time(), 0.0)
+        except (TypeError, ValueError):
+            pass
+    match = _RETRY_IN.search(str(error))
+    if match:
+        seconds = float(match.group(1))
+        return seconds / 1000 if match.group(2).lower() == "ms" else seconds
+    return None
+
+
+def retry_after_header(error: BaseException) -> str:
+    """Whole seconds a client should wait before retrying, for a
+    retry-after trailer."""
+    retry_after = retry_after_seconds(error)
+    if retry_after is None:
+        retry_after = DEFAULT_RETRY_AFTER_SECONDS
+    return str(max(math.ceil(retry_after), 1))
+
+
+async def retry_rate_limited(
+    open_stream: Callable[[], AsyncIterator[str]],
+    max_wait: float,
+    max_retries: int,
+    sleep: Callable[[float], Awaitable[None]] = asyncio.sleep,
+) -> AsyncIterator[str]:
+    """Stream tokens, retrying the stream when it is rate limited.
+
+    A rate limit before the first token is retried up to max_retries times,
+    each after the wait the provider asked for. A wait longer than max_wait
+    is not attempted, so the error goes on to the next model, or the client,
+    at once. Errors after the first token are never retried here.
+
+    Args:
+        open_stream: Returns a fresh token stream
+        max_wait: Longest wait, in seconds, worth retrying after
+        max_retries: Retries after the first attempt
+        sleep: Waits the given seconds
+
+    Raises:
+        The last error, once it is not retried
+    """
+    retries = 0
+    while True:
+        started = False
+        try:
+            async for token in open_stream():
+                started = True
+                yield token
+            return
+        except Exception as e:
+            if started or not is_rate_limit(e) or retries >= max_retries:
+                raise
+            wait = retry_after_seconds(e)
+            if wait is None:
+                wait = DEFAULT_RETRY_AFTER_SECONDS
+            if wait > max_wait:
+                logger.warning(f"Rate limited for {wait:.1f}s, longer than the {max_wait:.1f}s worth waiting")
+                raise
+            retries += 1
+            logger.info(f"Rate limited, retrying in {wait:.2f}s (retry {retries} of {max_retries})")
+            await sleep(wait)
@@ -0,0 +1,422 @@
+"""Helpers for combining documents from several retrieval sources."""
+
+import asyncio
+import datetime
+import logging
+import math
+import re
+from dataclasses import dataclass
+from typing import Any, Awaitable, Callable, Dict, List, Mapping, Optional, Sequence, Tuple
+
+from .ingestion import SECTION_METADATA_KEY
+
+logger = logging.getLogger(__name__)
+
+Retriever = Callable[[], Awaitable[List[Any]]]
+ScoredSearch = Callable[[str], Awaitable[List[Tuple[Any, float]]]]
+
+
+async def gather_sources(vector_search: Retriever, web_search: Retriever) -> Tuple[List[Any], List[Any]]:
+    """Run vector retrieval and web search concurrently.
+
+    A source that raises is logged and treated as having returned no
+    documents, so one slow or failing backend never blocks the other.
+
+    Args:
+        vector_search: Coroutine factory returning vector store documents
+        web_search: Coroutine factory returning web search documents
+
+    Returns:
+        Tuple of (vector documents, web documents)
+    """
+    vector_docs, web_docs = await asyncio.gather(
+        vector_search(), web_search(), return_exceptions=True
+    )
+    if isinstance(vector_docs, BaseException):
+        logger.error(f"Vector retrieval failed: {vector_docs}")
+        vector_docs = []
+    if isinstance(web_docs, BaseException):
+        logger.error(f"Web search failed: {web_docs}")
+        web_docs = []
+    return list(vector_docs), list(web_docs)
+
+
+def merge_documents(*sources: Sequence[Any]) -> List[Any]:
+    """Merge document lists in priority order, dropping duplicates.
+
+    Documents are considered duplicates when they share a source URL or,
+    without one, identical content. Earlier sources win.
+
+    Args:
+        sources: Document lists, highest priority first
+
+    Returns:
+        Merged list of documents
+    """
+    merged = []
+    seen = set()
+    for docs in sources:
+        for doc in docs:
+            key = _document_key(doc)
+            if key in seen:
+                continue
+            seen.add(key)
+            merged.append(doc)
+    return merged
+
+
+def _document_key(doc: Any) -> str:
+    metadata = getattr(doc, "metadata", None) or {}
+    return metadata.get("source") or getattr(doc, "page_content", "")
+
+
+_WORD = re.compile(r"\w+")
+
+
+def _shingles(text: str, size: int = 3) -> frozenset:
+    words = _WORD.findall(text.lower())
+    if len(words) < size:
+        return frozenset(words)
+    return frozenset(tuple(words[i:i + size]) for i in range(len(words) - size + 1))
+
+
+def _jaccard(a: frozenset, b: frozenset) -> float:
+    if not a and not b:
+        return 1.0
+    return len(a & b) / len(a | b)
+
+
+def dedupe_documents(documents: Sequence[Any], threshold: float) -> List[Any]:
+    """Drop documents whose text duplicates a better-scored one.
+
+    Text is compared case-insensitively on its words, ignoring punctuation
+    and whitespace, so the same passage scraped twice or indexed in two
+    collections counts as one. Documents whose word 3-gram overlap (Jaccard
+    similarity) is at least threshold are near-duplicates; a threshold of 1
+    drops exact duplicates only. Of each group the document with the highest
+    ``metadata["score"]`` is kept, or the earliest without scores.
+
+    Args:
+        documents: Documents in ranked order
+        threshold: Similarity in (0, 1] at which documents are duplicates
+
+    Returns:
+        The kept documents, in their original order
+    """
+    kept: List[Tuple[int, Any, frozenset]] = []
+    for index, doc in enumerate(documents):
+        shingles = _shingles(getattr(doc, "page_content", "") or "")
+        for i, (_, other, other_shingles) in enumerate(kept):
+            if _jaccard(shingles, other_shingles) >= threshold:
+                if _score(doc) > _score(other):
+                    kept[i] = (kept[i][0], doc, shingles)
+                break
+        else:
+            kept.append((index, doc, shingles))
+    return [doc for _, doc, _ in kept]
+
+
+def _score(doc: Any) -> float:
+    metadata = getattr(doc, "metadata", None) or {}
+    score = metadata.get("score")
+    return float("-inf") if score is None else float(score)
+
+
+# Source types reported with RAG answers
+SOURCE_KNOWLEDGE_BASE = "knowledge_base"
+SOURCE_WEB = "web"
+
+
+_SENTENCE_END = re.compile(r"[.!?…](?=[\s\"')\]]|$)|\n")
+
+
+def truncate_text(text: str, max_chars: int) -> str:
+    """Shorten text to at most max_chars, ending on a whole sentence.
+
+    The cut falls after the last sentence that fits; when the first one
+    already doesn't, after the last whole word, and failing that at
+    max_chars exactly.
+
+    Args:
+        text: Text to shorten
+        max_chars: Maximum length; 0 or less leaves text as it is
+
+    Returns:
+        The text, shortened when it was longer than max_chars
+    """
+    if max_chars <= 0 or len(text) <= max_chars:
+        return text
+    head = text[:max_chars]
+    # Matched on the whole text, so a "." cut off from what follows it
+    # (such as the point of a decimal) isn't taken for a sentence end
+    ends = [m.end() for m in _SENTENCE_END.finditer(text, 0, max_chars + 1) if m.end() <= max_chars]
+    if ends:
+        return head[:ends[-1]].rstrip()
+    space = head.rfind(" ")
+    if space > 0:
+        return head[:space].rstrip()
+    return head
+
+
+def citation_source(doc: Any, default: str) -> str:
+    """Label a document for citation with its source and collection."""
+    metadata = getattr(doc, "metadata", None) or {}
+    source = metadata.get("source", default)
+    collection = metadata.get("collection")
+    return f"{source} ({collection})" if collection else source
+
+
+def format_context(documents: Sequence[Any], source_label: str, default_source: str,
+                   section_label: str) -> str:
+    """Number documents for a RAG prompt, each under a citation header.
+
+    Chunks of a document ingested in sections are headed by their section
+    title too, so the model knows what part of the document it is reading.
+
+    Args:
+        documents: Documents in prompt order
+        source_label: Word for "source" in the prompt's language
+        default_source: Citation for documents without a source
+        section_label: Word for "section" in the prompt's language
+
+    Returns:
+        The documents, each as "[<source_label> <n> - <citation>]:" then
+        "<section_label>: <title>" when it has one, then its content
+    """
+    context = ""
+    for i, doc in enumerate(documents, 1):
+        context += f"\n[{source_label} {i} - {citation_source(doc, default_source)}]:\n"
+        section = (getattr(doc, "metadata", None) or {}).get(SECTION_METADATA_KEY)
+        if section:
+            context += f"{section_label}: {section}\n"
+        context += f"{doc.page_content}\n"
+    return context
+
+
+# A document cited by the number format_context gave it, e.g. "Source 2"
+_NUMBERED_CITATION = re.compile(r"\b(?:source|nguồn)\s*(\d+)", re.IGNORECASE)
+
+
+def cites_sources(answer: str, documents: Sequence[Any]) -> bool:
+    """Whether answer cites any of the documents a RAG prompt numbered with
+    format_context, by its number or by its source."""
+    for match in _NUMBERED_CITATION.finditer(answer):
+        if 1 <= int(match.group(1)) <= len(documents):
+            return True
+    for doc in documents:
+        source = (getattr(doc, "metadata", None) or {}).get("source")
+        if source and source in answer:
+            return True
+    return False
+
+
+def document_sources(documents: Sequence[Any]) -> List[Dict[str, str]]:
+    """Describe the documents an answer is generated from.
+
+    Web search results (metadata type "web_search") are reported as web
+    sources and everything else as knowledge base documents. Chunks of the
+    same document are reported once.
+
+    Args:
+        documents: Documents given to the model, in prompt order
+
+    Returns:
+        Dicts with type, title, uri and collection keys
+    """
+    sources = []
+    seen = set()
+    for doc in documents:
+        metadata = getattr(doc, "metadata", None) or {}
+        is_web = metadata.get("type") == "web_search"
+        source = {
+            "type": SOURCE_WEB if is_web else SOURCE_KNOWLEDGE_BASE,
+            "title": str(metadata.get("title") or ""),
+            "uri": str(metadata.get("source") or ""),
+            "collection": "" if is_web else str(metadata.get("collection") or ""),
+        }
+        key = tuple(source.values())
+        if key in seen:
+            continue
+        seen.add(key)
+        sources.append(source)
+    return sources
+
+
+def retrieval_limits(top_k: int, oversample: float, max_top_k: int) -> Tuple[int, int]:
+    """Work out how many documents to return and to fetch per collection.
+
+    Args:
+        top_k: Documents wanted
+        oversample: Multiple of top_k to fetch as candidates for ranking
+        max_top_k: Cap on both, to bound the load on the vector store
+
+    Returns:
+        Tuple of (top_k, fetch_k), with 1 <= top_k <= fetch_k <= max_top_k
+    """
+    top_k = max(1, min(top_k, max_top_k))
+    fetch_k = min(max(top_k, math.ceil(top_k * oversample)), max_top_k)
+    return top_k, fetch_k
+
+
+def resolve_collections(requested: Sequence[str], default: str) -> List[str]:
+    """Normalize the collections a request asked for.
+
+    Blank and repeated names are dropped; an empty list falls back to the
+    default collection.
+
+    Args:
+        requested: Collection names from the request, in order
+        default: Collection to use when none is requested
+
+    Returns:
+        Non-empty list of distinct collection names
+    """
+    collections = []
+    for name in requested:
+        name = (name or "").strip()
+        if name and name not in collections:
+            collections.append(name)
+    return collections or [default]
+
+
+@dataclass(frozen=True)
+class ScoreBoost:
+    """Adjusts the similarity of retrieved documents by their metadata.
+
+    With equals set, documents whose field equals it have boost added (a
+    negative boost demotes them). With decay_per_year set, field holds a
+    year, and documents lose decay_per_year for every year it lies before
+    the current one, at most max_decay. Documents without the field are
+    left alone.
+    """
+    field: str
+    equals: Any = None
+    boost: float = 0.0
+    decay_per_year: float = 0.0
+    max_decay: Optional[float] = None
+
+    def adjustment(self, metadata: Mapping[str, Any], current_year: int) -> float:
+        value = metadata.get(self.field)
+        if value is None:
+            return 0.0
+        if self.equals is not None:
+            return self.boost if str(value) == str(self.equals) else 0.0
+        try:
+            age = current_year - int(float(value))
+        except (TypeError, ValueError):
+            return 0.0
+        decay = self.decay_per_year * max(age, 0)
+        if self.max_decay is not None:
+            decay = min(decay, self.max_decay)
+        return -decay
+
+
+def parse_score_boosts(raw: Sequence[Mapping[str, Any]]) -> List[ScoreBoost]:
+    """Build score boosts from their config entries.
+
+    Each entry names a metadata field and either equals and boost, or
+    decay_per_year and optionally max_decay.
+
+    Raises:
+        ValueError: Naming the first entry that is not a valid boost
+    """
+    if not isinstance(raw, (list, tuple)):
+        raise ValueError("retrieval boosts must be a list")
+    boosts = []
+    for i, entry in enumerate(raw):
+        if not isinstance(entry, Mapping) or not entry.get("field"):
+            raise ValueError(f"retrieval boost {i} needs a field")
+        unknown = set(entry) - {"field", "equals", "boost", "decay_per_year", "max_decay"}
+        if unknown:
+            raise ValueError(f"retrieval boost {i} has unknown keys: {', '.join(sorted(unknown))}")
+        has_match = "equals" in entry
+        has_decay = "decay_per_year" in entry
+        if has_match == has_decay:
+            raise ValueError(f"retrieval boost {i} needs either equals and boost, or decay_per_year")
+        try:
+            boost = ScoreBoost(
+                field=str(entry["field"]),
+                equals=entry.get("equals"),
+                boost=float(entry.get("boost", 0.0)),
+                decay_per_year=float(entry.get("decay_per_year", 0.0)),
+                max_decay=None if entry.get("max_decay") is None else float(entry["max_decay"]),
+            )
+        except (TypeError, ValueError):
+            raise ValueError(f"retrieval boost {i} has a non-numeric boost, decay_per_year or max_decay")
+        if boost.decay_per_year < 0 or (boost.max_decay is not None and boost.max_decay < 0):
+            raise ValueError(f"retrieval boost {i} must not decay by a negative amount")
+        boosts.append(boost)
+    return boosts
+
+
+def boosted_score(metadata: Mapping[str, Any], score: float, boosts: Sequence[ScoreBoost],
+                  current_year: Optional[int] = None) -> float:
+    """Apply every boost to a document's similarity score.
+
+    Args:
+        metadata: The document's metadata
+        score: Its similarity
+        boosts: Boosts to apply
+        current_year: Year documents' ages are counted to; defaults to this
+            year
+
+    Returns:
+        The adjusted score
+    """
+    if not boosts:
+        return score
+    if current_year is None:
+        current_year = datetime.date.today().year
+    return score + sum(boost.adjustment(metadata, current_year) for boost in boosts)
+
+
+async def retrieve_from_collections(
+    search: ScoredSearch, collections: Sequence[str], top_k: int,
+    boosts: Sequence[ScoreBoost] = (), current_year: Optional[int] = None,
+) -> List[Any]:
+    """Query several collections concurrently and re-rank the results.
+
+    Every document is tagged with the collection it came from in
+    ``metadata["collection"]`` so answers can cite it, with its similarity
+    in ``metadata["base_score"]``, and with that similarity adjusted by
+    boosts in ``metadata["score"]``. Results are merged, de-duplicated
+    (keeping the best score), ordered by descending score and capped to
+    top_k. A collection that fails is logged and contributes no documents.
+
+    Args:
+        search: Coroutine factory returning (document, score) pairs for a
+            collection; higher scores are more similar
+        collections: Collection names to query
+        top_k: Maximum number of documents to return
+        boosts: Metadata boosts applied before ranking
+        current_year: Year the boosts count documents' ages to; defaults to
+            this year
+
+    Returns:
+        The top_k documents across all collections
+    """
+    results = await asyncio.gather(
+        *(search(collection) for collection in collections), return_exceptions=True
+    )
+    if current_year is None:
+        current_year = datetime.date.today().year
+
+    best: Dict[str, Tuple[Any, float]] = {}
+    for collection, scored in zip(collections, results):
+        if isinstance(scored, BaseException):
+            logger.error(f"Retrieval from collection '{collection}' failed: {scored}")
+            continue
+        for doc, score in scored:
+            metadata: Optional[dict] = getattr(doc, "metadata", None)
+            if metadata is None:
+                metadata = doc.metadata = {}
+            metadata["collection"] = collection
+            metadata["base_score"] = score
+            score = boosted_score(metadata, score, boosts, current_year)
+            metadata["score"] = score
+            key = _document_key(doc)
+            if key not in best or score > best[key][1]:
+                best[key] = (doc, score)
+
+    ranked = sorted(best.values(), key=lambda pair: pair[1], reverse=True)
+    return [doc for doc, _ in ranked[:top_k]]
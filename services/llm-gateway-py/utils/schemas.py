@@ -0,0 +1,61 @@
+"""Schemas of the structured outputs the gateway generates.
+
+Each schema is a pydantic model: it is sent to providers as a tool's
+parameters, described in the prompt for models without tool calling, and
+validates whatever comes back (see utils.structured). Clients pick one by
+its name in SCHEMAS.
+"""
+
+from typing import Dict, List, Type
+
+from pydantic import BaseModel, Field
+
+
+class CareerSuggestion(BaseModel):
+    """A career field suggested for the candidate."""
+    career_field: str = Field(min_length=1, description="Name of the career field, e.g. 'Data Science'")
+    match_percent: int = Field(ge=0, le=100, description="How well the field fits the candidate, from 0 to 100")
+    rationale: str = Field(description="One sentence on why the field fits the candidate")
+    domain_codes: List[str] = Field(
+        default_factory=list,
+        description="Codes of the ILO domains the field draws on, e.g. 'LOGIC'",
+    )
+
+
+class CareerSuggestions(BaseModel):
+    """Career fields suggested for the candidate, best match first."""
+    suggestions: List[CareerSuggestion] = Field(min_length=1, description="The suggested career fields")
+
+
+class IloAnalysis(BaseModel):
+    """Analysis of a candidate's ILO test result."""
+    overview: str = Field(description="Brief narrative overview of the candidate's dominant interest profile")
+    strengths: List[str] = Field(description="Key strengths, each with a concrete example")
+    development_areas: List[str] = Field(description="Areas the candidate could develop")
+    careers: List[CareerSuggestion] = Field(description="Three to five career fields that fit the profile")
+    next_steps: List[str] = Field(description="Actionable next steps for the next 3 to 6 months")
+
+
+class FollowUpQuestions(BaseModel):
+    """Questions the user may want to ask next, after an answer."""
+    questions: List[str] = Field(
+        min_length=1, max_length=5,
+        description="Short follow-up questions, in the language of the answer",
+    )
+
+
+class ConversationSummary(BaseModel):
+    """Summary of the earlier turns of a conversation."""
+    summary: str = Field(
+        min_length=1,
+        description="What the student shared about themselves, asked and was told, in the language of the conversation",
+    )
+
+
+# Schemas by the name clients request them with
+SCHEMAS: Dict[str, Type[BaseModel]] = {
+    "career_suggestions": CareerSuggestions,
+    "ilo_analysis": IloAnalysis,
+    "follow_up_questions": FollowUpQuestions,
+    "conversation_summary": ConversationSummary,
+}
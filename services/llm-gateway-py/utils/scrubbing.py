@@ -0,0 +1,101 @@
+"""Redaction of PII and blocklisted terms from documents before ingestion."""
+
+import re
+from dataclasses import dataclass, field
+from typing import Any, Dict, Iterable, List, Tuple
+
+# Placeholders the matches are replaced with
+REDACTED_EMAIL = "[EMAIL]"
+REDACTED_PHONE = "[PHONE]"
+REDACTED_ID = "[ID]"
+REDACTED_TERM = "[REDACTED]"
+
+# Set on scrubbed documents, and so on each of their chunks
+REDACTED_METADATA_KEY = "redacted"
+
+# Metadata fields scrubbed along with the content, since they are stored
+# with every chunk: the title, the source URL and a section's title
+SCRUBBED_METADATA_FIELDS = ("title", "source", "section")
+
+# Applied in order; IDs go before phone numbers, which would otherwise match
+# the start of a citizen ID
+PII_PATTERNS: List[Tuple[str, "re.Pattern[str]", str]] = [
+    ("email", re.compile(r"[\w.%+-]+@[\w-]+(?:\.[\w-]+)*\.[A-Za-z]{2,}"), REDACTED_EMAIL),
+    # 12-digit citizen ID (CCCD), whose first three digits are a province
+    # code from 001 to 096. Other 12-digit numbers, such as application
+    # codes, are common, so it only matches after a word naming it
+    ("id", re.compile(
+        r"(?P<context>(?<!\w)(?:CCCD|CMND|CMT|căn cước(?: công dân)?|định danh(?: cá nhân)?|citizen ID|national ID|ID)"
+        r"(?!\w)[^\w\n]{0,5}(?:(?:số|no\.?|number)[^\w\n]{0,5})?)"
+        r"0(?:0[1-9]|[1-8]\d|9[0-6])\d{9}(?!\w)",
+        re.IGNORECASE,
+    ), r"\g<context>" + REDACTED_ID),
+    # Passport number
+    ("id", re.compile(r"(?<![\w])[A-Z]\d{7}(?![\w])"), REDACTED_ID),
+    # Vietnamese phone numbers, local or +84, optionally grouped with
+    # spaces, dots or dashes
+    ("phone", re.compile(r"(?<![\w+])(?:\+84|0)[ .-]?[1-9](?:[ .-]?\d){7,9}(?![\w])"), REDACTED_PHONE),
+]
+
+
+@dataclass
+class ScrubResult:
+    """Scrubbed text and how many matches of each kind were redacted."""
+    text: str
+    redactions: Dict[str, int] = field(default_factory=dict)
+
+    @property
+    def redacted(self) -> bool:
+        return bool(self.redactions)
+
+    @property
+    def count(self) -> int:
+        return sum(self.redactions.values())
+
+
+class Scrubber:
+    """Redacts emails, phone numbers, IDs and blocklisted terms.
+
+    Blocklist terms match case-insensitively as whole words.
+    """
+
+    def __init__(self, blocklist: Iterable[str] = ()):
+        terms = sorted({t.strip() for t in blocklist if t and t.strip()}, key=len, reverse=True)
+        self.patterns = list(PII_PATTERNS)
+        if terms:
+            blocklist_pattern = re.compile(
+                r"(?<!\w)(?:" + "|".join(re.escape(t) for t in terms) + r")(?!\w)", re.IGNORECASE
+            )
+            self.patterns.append(("blocklist", blocklist_pattern, REDACTED_TERM))
+
+    def scrub(self, text: str) -> ScrubResult:
+        """Return text with every match replaced by its placeholder."""
+        result = ScrubResult(text=text or "")
+        for kind, pattern, placeholder in self.patterns:
+            result.text, n = pattern.subn(placeholder, result.text)
+            if n:
+                result.redactions[kind] = result.redactions.get(kind, 0) + n
+        return result
+
+    def scrub_metadata(self, metadata: Dict[str, Any], result: ScrubResult) -> Dict[str, Any]:
+        """Return a copy of metadata with its SCRUBBED_METADATA_FIELDS
+        scrubbed, and flagged with whether anything was redacted from them
+        or, as counted in result, from the content. Their matches are
+        added to result."""
+        scrubbed = dict(metadata or {})
+        for key in SCRUBBED_METADATA_FIELDS:
+            if isinstance(scrubbed.get(key), str):
+                field_result = self.scrub(scrubbed[key])
+                scrubbed[key] = field_result.text
+                for kind, n in field_result.redactions.items():
+                    result.redactions[kind] = result.redactions.get(kind, 0) + n
+        scrubbed[REDACTED_METADATA_KEY] = result.redacted
+        return scrubbed
+
+    def scrub_document(self, document: Any) -> ScrubResult:
+        """Scrub a document's page_content and metadata in place and flag
+        it in its metadata, which the splitter copies to every chunk."""
+        result = self.scrub(document.page_content)
+        document.page_content = result.text
+        document.metadata = self.scrub_metadata(document.metadata, result)
+        return result
@@ -0,0 +1,38 @@
+"""Channel options for the gRPC server.
+
+gRPC's defaults cap received messages at 4 MB, which large ingests and RAG
+contexts can exceed, and leave idle connections to whatever the network
+does to them. The options here apply the configured limits instead.
+"""
+
+from typing import Any, List, Tuple
+
+
+def _ms(seconds: float) -> int:
+    return int(seconds * 1000)
+
+
+def grpc_server_options(settings: Any) -> List[Tuple[str, int]]:
+    """Options for grpc.aio.server from the service settings.
+
+    Idle connections are pinged every grpc_keepalive_time_seconds and
+    closed when a ping goes unanswered for grpc_keepalive_timeout_seconds.
+    Clients may ping at most every grpc_keepalive_min_ping_interval_seconds,
+    with no limit on pings between calls when
+    grpc_keepalive_permit_without_calls is set; a client pinging more often
+    is disconnected.
+
+    Args:
+        settings: ServiceConfig with the grpc_max_* and grpc_keepalive_*
+            settings
+    """
+    return [
+        ("grpc.max_receive_message_length", settings.grpc_max_receive_message_bytes),
+        ("grpc.max_send_message_length", settings.grpc_max_send_message_bytes),
+        ("grpc.keepalive_time_ms", _ms(settings.grpc_keepalive_time_seconds)),
+        ("grpc.keepalive_timeout_ms", _ms(settings.grpc_keepalive_timeout_seconds)),
+        # Keep pinging idle connections; gRPC stops after two by default
+        ("grpc.http2.max_pings_without_data", 0),
+        ("grpc.http2.min_recv_ping_interval_without_data_ms", _ms(settings.grpc_keepalive_min_ping_interval_seconds)),
+        ("grpc.keepalive_permit_without_calls", int(settings.grpc_keepalive_permit_without_calls)),
+    ]
@@ -0,0 +1,59 @@
+"""Tracking of in-flight streaming RPCs so shutdown doesn't wait on them."""
+
+import asyncio
+import logging
+from contextlib import contextmanager
+from typing import Set
+
+logger = logging.getLogger(__name__)
+
+
+class StreamRegistry:
+    """Registry of the tasks serving active streaming requests."""
+
+    def __init__(self):
+        self._tasks: Set[asyncio.Task] = set()
+        self._closing = False
+
+    @property
+    def closing(self) -> bool:
+        """Whether shutdown has started; new streams should be refused."""
+        return self._closing
+
+    @property
+    def active(self) -> int:
+        """Number of streams currently being served."""
+        return len(self._tasks)
+
+    @contextmanager
+    def track(self):
+        """Register the current task for the duration of the block."""
+        task = asyncio.current_task()
+        self._tasks.add(task)
+        try:
+            yield
+        finally:
+            self._tasks.discard(task)
+
+    async def shutdown(self, grace: float) -> int:
+        """Stop accepting streams and cancel those still running after grace.
+
+        Args:
+            grace: Seconds to let in-flight streams finish on their own
+
+        Returns:
+            Number of streams that had to be cancelled
+        """
+        self._closing = True
+        tasks = {t for t in self._tasks if not t.done()}
+        if not tasks:
+            return 0
+
+        logger.info(f"Waiting up to {grace}s for {len(tasks)} in-flight streams")
+        _, pending = await asyncio.wait(tasks, timeout=grace)
+        for task in pending:
+            task.cancel()
+        if pending:
+            logger.info(f"Cancelled {len(pending)} in-flight streams")
+            await asyncio.wait(pending)
+        return len(pending)
@@ -0,0 +1,147 @@
+"""Structured model output that is checked against a schema.
+
+Models that support tool calling are made to call a tool whose arguments
+are the schema, so the provider enforces its shape. Other models are asked
+for JSON in the prompt and their reply is parsed, tolerating the code
+fences and prose models tend to wrap it in. Either way the output is
+validated before it is returned.
+
+Schemas are pydantic models (see utils.schemas); nothing here imports
+pydantic, so the parsing can be used and tested without it.
+"""
+
+import json
+import re
+from typing import Any, Optional, Tuple
+
+from .usage import UsageMeter
+
+# How a structured output was obtained
+METHOD_TOOL_CALL = "tool_call"
+METHOD_TEXT = "text"
+
+# A fenced code block, optionally tagged json
+CODE_FENCE_PATTERN = re.compile(r"```(?:json)?\s*(.*?)```", re.DOTALL | re.IGNORECASE)
+
+
+class StructuredOutputError(ValueError):
+    """The model's output was missing or did not match the schema."""
+
+
+def json_instructions(schema: Any) -> str:
+    """Prompt suffix asking a model without tool calling for JSON."""
+    return (
+        "Reply with a single JSON object and nothing else. It must match this JSON schema:\n"
+        + json.dumps(schema.model_json_schema(), ensure_ascii=False)
+    )
+
+
+def validate_output(schema: Any, data: Any) -> Any:
+    """Validate data against schema, returning the schema instance."""
+    try:
+        return schema.model_validate(data)
+    except ValueError as e:
+        # pydantic's ValidationError is a ValueError
+        raise StructuredOutputError(f"output does not match {schema.__name__}: {e}") from e
+
+
+def tool_call_arguments(message: Any, name: str) -> Optional[Any]:
+    """Return the arguments of message's call to the tool name.
+
+    LangChain puts parsed calls in message.tool_calls; providers' raw calls,
+    with JSON-encoded arguments, may only be in additional_kwargs. Returns
+    None when the model did not call the tool.
+    """
+    for call in getattr(message, "tool_calls", None) or []:
+        if call.get("name") == name:
+            return call.get("args")
+    raw_calls = (getattr(message, "additional_kwargs", None) or {}).get("tool_calls") or []
+    for call in raw_calls:
+        function = call.get("function") or {}
+        if function.get("name") == name:
+            try:
+                return json.loads(function.get("arguments") or "{}")
+            except json.JSONDecodeError as e:
+                raise StructuredOutputError(f"tool call arguments are not JSON: {e}") from e
+    return None
+
+
+def _first_json_value(text: str) -> Optional[Any]:
+    """Decode the first JSON object or array in text."""
+    decoder = json.JSONDecoder()
+    for i, char in enumerate(text):
+        if char not in "{[":
+            continue
+        try:
+            value, _ = decoder.raw_decode(text, i)
+        except json.JSONDecodeError:
+            continue
+        return value
+    return None
+
+
+def parse_text_output(schema: Any, text: str) -> Any:
+    """Parse output for schema out of a model's free-text reply.
+
+    The JSON may be in a code fence, bare, or among prose; the first object
+    or array found is used. A bare array is taken as the value of the
+    schema's only field, for replies that skip the wrapping object.
+    """
+    data = None
+    for candidate in [m.group(1) for m in CODE_FENCE_PATTERN.finditer(text)] + [text]:
+        data = _first_json_value(candidate)
+        if data is not None:
+            break
+    if data is None:
+        raise StructuredOutputError("no JSON found in the model's reply")
+    fields = list(getattr(schema, "model_fields", {}))
+    if isinstance(data, list) and len(fields) == 1:
+        data = {fields[0]: data}
+    return validate_output(schema, data)
+
+
+def _bind_schema_tool(llm: Any, schema: Any) -> Optional[Any]:
+    """Bind schema to llm as a tool it must call, or None if it cannot."""
+    bind_tools = getattr(llm, "bind_tools", None)
+    if bind_tools is None:
+        return None
+    try:
+        return bind_tools([schema], tool_choice=schema.__name__)
+    except NotImplementedError:
+        return None
+
+
+def _message_text(message: Any) -> str:
+    content = getattr(message, "content", "") or ""
+    if isinstance(content, list):
+        # Content blocks, as some providers return
+        return "".join(block.get("text", "") if isinstance(block, dict) else str(block) for block in content)
+    return str(content)
+
+
+async def generate_structured(llm: Any, prompt: str, schema: Any,
+                              meter: Optional[UsageMeter] = None) -> Tuple[Any, str]:
+    """Ask llm for output matching schema.
+
+    A model that answers the tool call with text instead is parsed like one
+    without tool calling. The generation is counted on meter.
+
+    Returns:
+        The validated schema instance, and METHOD_TOOL_CALL or METHOD_TEXT
+
+    Raises:
+        StructuredOutputError: The output was missing or invalid
+    """
+    bound = _bind_schema_tool(llm, schema)
+    if bound is None:
+        prompt = f"{prompt}\n\n{json_instructions(schema)}"
+        bound = llm
+    message = await bound.ainvoke(prompt)
+
+    args = tool_call_arguments(message, schema.__name__)
+    if meter is not None:
+        completion = json.dumps(args, ensure_ascii=False) if args is not None else _message_text(message)
+        meter.add(prompt, completion, getattr(message, "usage_metadata", None))
+    if args is not None:
+        return validate_output(schema, args), METHOD_TOOL_CALL
+    return parse_text_output(schema, _message_text(message)), METHOD_TEXT
@@ -0,0 +1,128 @@
+"""Per-user LLM token usage and monthly quotas.
+
+Usage is counted per calendar month (UTC). Prompt and completion tokens come
+from the model's usage metadata when it reports any, and are estimated from
+the text otherwise.
+"""
+
+import hashlib
+import json
+import os
+import threading
+from dataclasses import asdict, dataclass
+from datetime import datetime, timezone
+from typing import Optional
+
+from .ingestion import estimate_tokens
+
+
+def current_period(now: Optional[datetime] = None) -> str:
+    """Return the usage period of now, as YYYY-MM in UTC."""
+    now = now or datetime.now(timezone.utc)
+    return now.astimezone(timezone.utc).strftime("%Y-%m")
+
+
+@dataclass
+class Usage:
+    """A user's token usage in one period."""
+    period: str
+    prompt_tokens: int = 0
+    completion_tokens: int = 0
+    requests: int = 0
+
+    @property
+    def total_tokens(self) -> int:
+        return self.prompt_tokens + self.completion_tokens
+
+
+class QuotaExceeded(Exception):
+    """Raised when a user has used up their monthly token quota."""
+
+    def __init__(self, usage: Usage, quota: int):
+        super().__init__(
+            f"monthly token quota of {quota} used up for {usage.period} "
+            f"({usage.total_tokens} tokens used)"
+        )
+        self.usage = usage
+        self.quota = quota
+
+
+class UsageStore:
+    """Usage saved as one JSON file per user in a directory, keyed by period."""
+
+    def __init__(self, directory: str):
+        self.directory = directory
+        self._lock = threading.Lock()
+
+    def _path(self, user_id: str) -> str:
+        # User IDs are not trusted as file names
+        name = hashlib.sha256(user_id.encode("utf-8")).hexdigest()
+        return os.path.join(self.directory, f"{name}.json")
+
+    def _load(self, user_id: str) -> dict:
+        try:
+            with open(self._path(user_id), "r", encoding="utf-8") as f:
+                return json.load(f)
+        except FileNotFoundError:
+            return {"user_id": user_id, "periods": {}}
+
+    def get(self, user_id: str, period: Optional[str] = None) -> Usage:
+        """Return the user's usage in period, the current one by default."""
+        period = period or current_period()
+        with self._lock:
+            data = self._load(user_id)
+        return Usage(**data["periods"].get(period, {"period": period}))
+
+    def record(self, user_id: str, prompt_tokens: int, completion_tokens: int,
+               period: Optional[str] = None) -> Usage:
+        """Add one request's tokens to the user's usage and return the total."""
+        period = period or current_period()
+        with self._lock:
+            data = self._load(user_id)
+            usage = Usage(**data["periods"].get(period, {"period": period}))
+            usage.prompt_tokens += prompt_tokens
+            usage.completion_tokens += completion_tokens
+            usage.requests += 1
+            data["periods"][period] = asdict(usage)
+
+            os.makedirs(self.directory, exist_ok=True)
+            tmp = self._path(user_id) + ".tmp"
+            with open(tmp, "w", encoding="utf-8") as f:
+                f.write(json.dumps(data, ensure_ascii=False))
+            os.replace(tmp, self._path(user_id))
+        return usage
+
+    def check_quota(self, user_id: str, quota: int) -> Usage:
+        """Return the user's current usage, or raise QuotaExceeded.
+
+        A quota of 0 means unlimited. A request that starts under the quota
+        is allowed to finish, so usage can end up slightly over it.
+        """
+        usage = self.get(user_id)
+        if quota > 0 and usage.total_tokens >= quota:
+            raise QuotaExceeded(usage, quota)
+        return usage
+
+
+class UsageMeter:
+    """Counts the tokens of the generations made for one request."""
+
+    def __init__(self):
+        self.prompt_tokens = 0
+        self.completion_tokens = 0
+
+    def add(self, prompt: str, completion: str, usage_metadata: Optional[dict] = None):
+        """Count one generation.
+
+        Args:
+            prompt: Prompt sent to the model
+            completion: Text the model returned, possibly partial
+            usage_metadata: The model's own counts (input_tokens and
+                output_tokens), preferred over estimates when present
+        """
+        if usage_metadata:
+            self.prompt_tokens += usage_metadata.get("input_tokens", 0)
+            self.completion_tokens += usage_metadata.get("output_tokens", 0)
+            return
+        self.prompt_tokens += estimate_tokens(prompt)
+        self.completion_tokens += estimate_tokens(completion)
@@ -0,0 +1,206 @@
+"""Interfaces the LLM service needs from its vector database.
+
+The service stores and searches documents through a VectorStore, so the
+database behind it can be swapped in configuration: Pinecone
+(utils.pinecone_store) in production, or the in-memory store
+(utils.memory_store) for local development without an account.
+"""
+
+import threading
+from concurrent.futures import Future
+from dataclasses import dataclass, field
+from typing import Any, Callable, Dict, List, Optional, Protocol, Tuple
+
+# Values of vector_store.backend
+BACKEND_PINECONE = "pinecone"
+BACKEND_MEMORY = "memory"
+BACKENDS = (BACKEND_PINECONE, BACKEND_MEMORY)
+
+
+class PineconeClient(Protocol):
+    """The subset of pinecone.Pinecone the service uses.
+
+    Tests and test mode substitute utils.fakes.FakePineconeClient.
+    """
+
+    def Index(self, name: str) -> Any:
+        """Return a handle on an index."""
+
+    def list_indexes(self) -> Any:
+        """Describe every index."""
+
+    def describe_index(self, name: str) -> Any:
+        """Describe one index, including its status."""
+
+    def create_index(self, name: str, dimension: int, metric: str, spec: Any, timeout: Any = None) -> Any:
+        """Start creating an index."""
+
+    def delete_index(self, name: str) -> Any:
+        """Delete an index."""
+
+
+@dataclass
+class CollectionInfo:
+    """A collection's description; status is a utils.provisioning status."""
+    name: str
+    dimension: int
+    metric: str
+    status: str
+    host: str = ""
+
+
+@dataclass
+class StoredDocument:
+    """A document read back from a backend that keeps its text as
+    metadata, with the text and the rest of the metadata split again."""
+    page_content: str
+    metadata: Dict[str, Any] = field(default_factory=dict)
+
+
+@dataclass
+class CollectionStats:
+    """How much a collection holds, with backend-specific details."""
+    document_count: int
+    metadata: Dict[str, Any] = field(default_factory=dict)
+
+
+class VectorStore(Protocol):
+    """A vector database holding documents in named collections.
+
+    Every method may be called from executor threads, and may block on the
+    network. Methods on a collection that does not exist raise.
+    """
+
+    def create_collection(self, name: str, dimension: int, metric: str = "cosine") -> None:
+        """Start creating a collection; it may be provisioning for a while."""
+
+    def describe_collection(self, name: str) -> CollectionInfo:
+        """Describe one collection, including its status."""
+
+    def list_collections(self) -> List[CollectionInfo]:
+        """Describe every collection."""
+
+    def connect(self, collection: str) -> None:
+        """Open the collection ahead of its first query, if that costs anything."""
+
+    def add_documents(self, collection: str, documents: List[Any],
+                      ids: Optional[List[str]] = None) -> List[str]:
+        """Embed and store documents, replacing any with the same ids.
+
+        Returns:
+            The ids the documents were stored under
+        """
+
+    def visible_ids(self, collection: str, ids: List[str]) -> List[str]:
+        """Return those of ids whose documents can be read back.
+
+        A backend may take a moment to index what add_documents stored, and
+        leave it out until then.
+        """
+
+    def query(self, collection: str, query: str, k: int) -> List[Tuple[Any, float]]:
+        """Return the k documents most similar to query, with their
+        similarity, most similar first."""
+
+    def list_documents(self, collection: str, limit: int,
+                       cursor: str = "") -> Tuple[List[Tuple[str, Any]], str]:
+        """Return up to limit stored documents with their ids, in a stable
+        order, starting after cursor, and the cursor of the next page.
+
+        The cursor is empty on the last page; the backend decides what it
+        holds.
+        """
+
+    def stats(self, collection: str) -> CollectionStats:
+        """Count what the collection holds."""
+
+    def clear(self, collection: str) -> None:
+        """Delete every document in the collection, keeping the collection."""
+
+    def delete(self, collection: str) -> None:
+        """Delete the collection and everything in it."""
+
+
+class CollectionHandle:
+    """One collection of a VectorStore, with the LangChain vector store
+    methods ingestion and retrieval call."""
+
+    def __init__(self, store: VectorStore, collection: str):
+        self.store = store
+        self.collection = collection
+
+    def add_documents(self, documents: List[Any], ids: Optional[List[str]] = None) -> List[str]:
+        return self.store.add_documents(self.collection, documents, ids=ids)
+
+    def similarity_search_with_score(self, query: str, k: int = 4) -> List[Tuple[Any, float]]:
+        return self.store.query(self.collection, query, k)
+
+    def visible_ids(self, ids: List[str]) -> List[str]:
+        return self.store.visible_ids(self.collection, ids)
+
+
+# Builds a LangChain-style vector store (similarity_search_with_score,
+# add_documents) over an index handle and an embeddings model
+VectorStoreFactory = Callable[[Any, Any], Any]
+
+
+class VectorStoreCache:
+    """Vector stores by collection name, opened on first use.
+
+    Lookups come from executor threads as well as the event loop, so the
+    cache is locked. Opening a store connects to the database, which is
+    done outside the lock so other collections are not held up: the first
+    request for a collection opens it, and those arriving meanwhile wait on
+    its future.
+    """
+
+    def __init__(self):
+        self._stores: Dict[str, Any] = {}
+        # Opens in progress, by collection
+        self._opening: Dict[str, Future] = {}
+        self._lock = threading.Lock()
+
+    def get(self, collection: str, open_store: Callable[[], Any]) -> Any:
+        """Return the collection's store, calling open_store if there is none.
+
+        A failed open is raised to every request waiting on it, and the next
+        request tries again.
+        """
+        with self._lock:
+            store = self._stores.get(collection)
+            if store is not None:
+                return store
+            future = self._opening.get(collection)
+            opener = future is None
+            if opener:
+                future = self._opening[collection] = Future()
+        if not opener:
+            return future.result()
+
+        try:
+            store = open_store()
+        except BaseException as e:
+            with self._lock:
+                if self._opening.get(collection) is future:
+                    del self._opening[collection]
+            future.set_exception(e)
+            raise
+        with self._lock:
+            # Unless evicted meanwhile, when it may be outdated already
+            if self._opening.get(collection) is future:
+                del self._opening[collection]
+                self._stores[collection] = store
+        future.set_result(store)
+        return store
+
+    def names(self) -> List[str]:
+        """Return the collections whose stores are open or being opened."""
+        with self._lock:
+            return list(self._stores) + [name for name in self._opening if name not in self._stores]
+
+    def evict(self, collection: str) -> None:
+        """Forget the collection's store, so the next get opens it again. A
+        store still being opened is not kept."""
+        with self._lock:
+            self._stores.pop(collection, None)
+            self._opening.pop(collection, None)